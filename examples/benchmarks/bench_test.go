@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// Пример 1: Ловушка - компилятор может выбросить вычисление,
+// результат которого не используется, и бенчмарк измерит пустой цикл.
+// Сохранение результата в глобальную переменную (sink) это предотвращает.
+var sinkInt int
+var sinkBytes []byte
+
+func sum(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+func BenchmarkSum_NoSink(b *testing.B) {
+	xs := make([]int, 1000)
+	for i := 0; i < b.N; i++ {
+		sum(xs) // результат отброшен - после инлайнинга цикл может исчезнуть
+	}
+}
+
+func BenchmarkSum_Sink(b *testing.B) {
+	xs := make([]int, 1000)
+	var total int
+	for i := 0; i < b.N; i++ {
+		total += sum(xs)
+	}
+	sinkInt = total
+}
+
+// Go 1.24+: b.Loop сам не дает компилятору выбросить тело цикла
+// и исключает подготовку до цикла из замера
+func BenchmarkSum_Loop(b *testing.B) {
+	xs := make([]int, 1000)
+	for b.Loop() {
+		sum(xs)
+	}
+}
+
+// Пример 2: ResetTimer - дорогая подготовка не попадает в результат
+func BenchmarkSum_ResetTimer(b *testing.B) {
+	xs := make([]int, 1_000_000)
+	for i := range xs {
+		xs[i] = i
+	}
+	b.ResetTimer()
+	
+	for i := 0; i < b.N; i++ {
+		sinkInt = sum(xs)
+	}
+}
+
+// Пример 3: Под-бенчмарки по размерам входа и ReportAllocs
+func BenchmarkItoaConcat(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s := ""
+				for j := 0; j < n; j++ {
+					s += strconv.Itoa(j)
+				}
+				sinkInt = len(s)
+			}
+		})
+	}
+}
+
+func BenchmarkItoaAppend(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf := make([]byte, 0, n*4)
+				for j := 0; j < n; j++ {
+					buf = strconv.AppendInt(buf, int64(j), 10)
+				}
+				sinkBytes = buf
+			}
+		})
+	}
+}
+
+// Пример 4: Сравнение кодеков - матрица "кодек x размер". Кодеки -
+// encoders из main.go; protobuf попадает в матрицу с -tags protobuf
+
+func makeUser(tags int) *User {
+	u := &User{ID: 42, Name: "Иван Иванов", Email: "ivan@example.com"}
+	for i := 0; i < tags; i++ {
+		u.Tags = append(u.Tags, fmt.Sprintf("tag-%d", i))
+	}
+	return u
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, enc := range encoders {
+		for _, tags := range []int{1, 10, 1000} {
+			user := makeUser(tags)
+			b.Run(fmt.Sprintf("%s/%d", enc.Name(), tags), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					data, err := enc.Encode(user)
+					if err != nil {
+						b.Fatal(err)
+					}
+					sinkBytes = data
+				}
+				// Пользовательская метрика рядом с ns/op и B/op
+				b.ReportMetric(float64(len(sinkBytes)), "bytes/msg")
+			})
+		}
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, enc := range encoders {
+		for _, tags := range []int{1, 10, 1000} {
+			data, err := enc.Encode(makeUser(tags))
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(fmt.Sprintf("%s/%d", enc.Name(), tags), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data))) // Покажет пропускную способность MB/s
+				for i := 0; i < b.N; i++ {
+					var u User
+					if err := enc.Decode(data, &u); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Бенчмарк ничего не стоит, если код неверен: кодеки проверяются тестом
+func TestEncodersRoundTrip(t *testing.T) {
+	for _, enc := range encoders {
+		t.Run(enc.Name(), func(t *testing.T) {
+			want := makeUser(3)
+			data, err := enc.Encode(want)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			
+			var got User
+			if err := enc.Decode(data, &got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(&got, want) {
+				t.Errorf("Round trip mismatch: %+v != %+v", got, *want)
+			}
+		})
+	}
+}
\ No newline at end of file
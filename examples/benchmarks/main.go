@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// User модель пользователя, которую кодируем разными способами
+type User struct {
+	ID    int64    `json:"id"`
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Tags  []string `json:"tags"`
+}
+
+// Encoder общий интерфейс для сравнения кодеков
+type Encoder interface {
+	Name() string
+	Encode(u *User) ([]byte, error)
+	Decode(data []byte, u *User) error
+}
+
+// JSONEncoder encoding/json
+type JSONEncoder struct{}
+
+func (JSONEncoder) Name() string                      { return "json" }
+func (JSONEncoder) Encode(u *User) ([]byte, error)    { return json.Marshal(u) }
+func (JSONEncoder) Decode(data []byte, u *User) error { return json.Unmarshal(data, u) }
+
+// GobEncoder encoding/gob. Каждое сообщение несет описание типа,
+// поэтому на одиночных значениях gob медленнее и объемнее,
+// чем в потоке, где описание передается один раз.
+type GobEncoder struct{}
+
+func (GobEncoder) Name() string { return "gob" }
+
+func (GobEncoder) Encode(u *User) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(u)
+	return buf.Bytes(), err
+}
+
+func (GobEncoder) Decode(data []byte, u *User) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(u)
+}
+
+// BinaryEncoder ручной бинарный формат: varint и строки с префиксом длины.
+// Это не protobuf: у полей нет тегов, поэтому формат не переживает
+// добавления полей. Настоящий wire-формат protobuf - ProtoEncoder в
+// protobuf.go, он собирается с тегом protobuf (go test -tags protobuf),
+// чтобы пример без тега не требовал внешней зависимости.
+type BinaryEncoder struct{}
+
+func (BinaryEncoder) Name() string { return "binary" }
+
+func (BinaryEncoder) Encode(u *User) ([]byte, error) {
+	size := binary.MaxVarintLen64 * (3 + len(u.Tags))
+	size += len(u.Name) + len(u.Email)
+	for _, t := range u.Tags {
+		size += len(t)
+	}
+	
+	buf := make([]byte, 0, size)
+	buf = binary.AppendVarint(buf, u.ID)
+	buf = appendString(buf, u.Name)
+	buf = appendString(buf, u.Email)
+	buf = binary.AppendUvarint(buf, uint64(len(u.Tags)))
+	for _, t := range u.Tags {
+		buf = appendString(buf, t)
+	}
+	return buf, nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+var errShortBuffer = errors.New("неожиданный конец данных")
+
+func (BinaryEncoder) Decode(data []byte, u *User) error {
+	id, n := binary.Varint(data)
+	if n <= 0 {
+		return errShortBuffer
+	}
+	u.ID = id
+	data = data[n:]
+	
+	var err error
+	if u.Name, data, err = readString(data); err != nil {
+		return err
+	}
+	if u.Email, data, err = readString(data); err != nil {
+		return err
+	}
+	
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return errShortBuffer
+	}
+	data = data[n:]
+	
+	u.Tags = make([]string, count)
+	for i := range u.Tags {
+		if u.Tags[i], data, err = readString(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readString(data []byte) (string, []byte, error) {
+	l, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < l {
+		return "", nil, errShortBuffer
+	}
+	end := n + int(l)
+	return string(data[n:end]), data[end:], nil
+}
+
+// encoders кодеки для сравнения; с тегом protobuf добавляется ProtoEncoder
+var encoders = []Encoder{JSONEncoder{}, GobEncoder{}, BinaryEncoder{}}
+
+// Пример 1: Размер закодированных данных
+func compareSizes() {
+	fmt.Println("=== Размер сообщений ===")
+	
+	user := &User{ID: 42, Name: "Иван Иванов", Email: "ivan@example.com", Tags: []string{"admin", "beta"}}
+	for _, enc := range encoders {
+		data, err := enc.Encode(user)
+		if err != nil {
+			fmt.Println("Ошибка:", err)
+			continue
+		}
+		fmt.Printf("%-7s %4d байт\n", enc.Name(), len(data))
+	}
+}
+
+// Пример 2: Как запускать и сравнивать бенчмарки
+func printBenchmarkGuide() {
+	fmt.Println("\n=== Бенчмарки ===")
+	fmt.Println(strings.TrimSpace(`
+Все бенчмарки с аллокациями:
+    go test -run=^$ -bench=. -benchmem
+
+Только кодеки, только размер 1000:
+    go test -run=^$ -bench='Encode/.*/1000'
+
+Сравнение "до/после" с benchstat (go install golang.org/x/perf/cmd/benchstat@latest):
+    go test -run=^$ -bench=Sum -count=10 > old.txt
+    ... изменить код ...
+    go test -run=^$ -bench=Sum -count=10 > new.txt
+    benchstat old.txt new.txt
+
+-count=10 нужен, чтобы benchstat оценил разброс: без него разница
+в 3% неотличима от шума. Строка "~" в выводе означает "разницы нет".`))
+}
+
+func main() {
+	compareSizes()
+	printBenchmarkGuide()
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+//go:build protobuf
+
+package main
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Сборка с protobuf:
+//   go get google.golang.org/protobuf
+//   go test -tags protobuf -run=^$ -bench=Encode -benchmem
+// Без тега файл не компилируется, и примеру не нужна зависимость.
+
+// ProtoEncoder настоящий wire-формат protobuf для схемы
+//
+//	message User {
+//	  int64 id = 1;
+//	  string name = 2;
+//	  string email = 3;
+//	  repeated string tags = 4;
+//	}
+//
+// Кодирование через protowire - тот же формат, что дает protoc-gen-go,
+// но без генерации кода и рефлексии proto.Marshal. Сгенерированный код
+// медленнее на рефлексии, зато байты совпадают: их прочитает любой
+// клиент protobuf.
+type ProtoEncoder struct{}
+
+func init() {
+	encoders = append(encoders, ProtoEncoder{})
+}
+
+func (ProtoEncoder) Name() string { return "protobuf" }
+
+func (ProtoEncoder) Encode(u *User) ([]byte, error) {
+	var buf []byte
+	// Поля со значением по умолчанию protobuf не пишет
+	if u.ID != 0 {
+		buf = protowire.AppendTag(buf, 1, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(u.ID))
+	}
+	if u.Name != "" {
+		buf = protowire.AppendTag(buf, 2, protowire.BytesType)
+		buf = protowire.AppendString(buf, u.Name)
+	}
+	if u.Email != "" {
+		buf = protowire.AppendTag(buf, 3, protowire.BytesType)
+		buf = protowire.AppendString(buf, u.Email)
+	}
+	for _, t := range u.Tags {
+		buf = protowire.AppendTag(buf, 4, protowire.BytesType)
+		buf = protowire.AppendString(buf, t)
+	}
+	return buf, nil
+}
+
+func (ProtoEncoder) Decode(data []byte, u *User) error {
+	*u = User{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			u.ID = int64(v)
+			data = data[n:]
+		case num >= 2 && num <= 4 && typ == protowire.BytesType:
+			s, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			switch num {
+			case 2:
+				u.Name = s
+			case 3:
+				u.Email = s
+			default:
+				u.Tags = append(u.Tags, s)
+			}
+			data = data[n:]
+		default:
+			// Незнакомое поле пропускаем: так protobuf читает данные
+			// от более новой версии схемы
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+		}
+	}
+	return nil
+}
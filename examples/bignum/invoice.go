@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Line строка счета
+type Line struct {
+	Description string
+	Quantity    int64
+	UnitPrice   Money
+}
+
+// Invoice счет: строки, скидка и НДС в процентах
+type Invoice struct {
+	Lines    []Line
+	Discount string // Процент скидки, например "5"
+	VAT      string // Процент НДС, например "20"
+}
+
+// Totals итоги счета, каждая сумма округлена до копеек
+type Totals struct {
+	Subtotal Money
+	Discount Money
+	Net      Money
+	VAT      Money
+	Total    Money
+}
+
+// Calculate считает итоги. Округление выполняется один раз на каждую
+// итоговую величину, а итог складывается из уже округленных частей -
+// тогда Net + VAT == Total до копейки и счет сходится при проверке.
+func (inv Invoice) Calculate() Totals {
+	subtotal := Zero()
+	for _, l := range inv.Lines {
+		subtotal = subtotal.Add(l.UnitPrice.MulInt(l.Quantity))
+	}
+	subtotal = subtotal.Round()
+	
+	discount := Zero()
+	if inv.Discount != "" {
+		discount = subtotal.Percent(inv.Discount).Round()
+	}
+	net := subtotal.Sub(discount)
+	
+	vat := Zero()
+	if inv.VAT != "" {
+		vat = net.Percent(inv.VAT).Round()
+	}
+	return Totals{
+		Subtotal: subtotal,
+		Discount: discount,
+		Net:      net,
+		VAT:      vat,
+		Total:    net.Add(vat),
+	}
+}
+
+// Format печатает счет
+func (inv Invoice) Format() string {
+	var sb strings.Builder
+	for _, l := range inv.Lines {
+		fmt.Fprintf(&sb, "%-24s %4d x %10s = %12s\n", l.Description, l.Quantity, l.UnitPrice, l.UnitPrice.MulInt(l.Quantity).Round())
+	}
+	t := inv.Calculate()
+	fmt.Fprintf(&sb, "%-43s %12s\n", "Подытог", t.Subtotal)
+	if inv.Discount != "" {
+		fmt.Fprintf(&sb, "%-43s %12s\n", "Скидка "+inv.Discount+"%", "-"+t.Discount.String())
+	}
+	if inv.VAT != "" {
+		fmt.Fprintf(&sb, "%-43s %12s\n", "НДС "+inv.VAT+"%", t.VAT)
+	}
+	fmt.Fprintf(&sb, "%-43s %12s\n", "Итого", t.Total)
+	return sb.String()
+}
+
+// calculateFloat - тот же расчет на float64, для сравнения
+func calculateFloat(lines []struct {
+	qty   int
+	price float64
+}, discount, vat float64) float64 {
+	subtotal := 0.0
+	for _, l := range lines {
+		subtotal += float64(l.qty) * l.price
+	}
+	net := subtotal - subtotal*discount/100
+	return net + net*vat/100
+}
\ No newline at end of file
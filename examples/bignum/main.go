@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Пример 1: Почему float64 не годится для денег
+func floatProblems() {
+	fmt.Println("=== float64 и деньги ===")
+	
+	// Переменные, а не константы: константные выражения Go вычисляет
+	// точно при компиляции, и 0.1+0.2 == 0.3 там истинно
+	a, b := 0.1, 0.2
+	fmt.Println("0.1 + 0.2 == 0.3:", a+b == 0.3)
+	fmt.Printf("0.1 + 0.2 = %.17f\n", a+b)
+	
+	sum := 0.0
+	for range 1000000 {
+		sum += 0.01 // Миллион платежей по копейке
+	}
+	fmt.Printf("Миллион копеек float64: %.10f (ожидалось 10000)\n", sum)
+	
+	exact := Zero()
+	kop := MustMoney("0.01")
+	for range 1000000 {
+		exact = exact.Add(kop)
+	}
+	fmt.Println("Миллион копеек big.Rat:", exact)
+	
+	// 1.015 в двоичном виде чуть меньше, поэтому ровная половина
+	// "округляется" вниз; Money видит точную половину и округляет к четному
+	price := 1.015
+	fmt.Printf("math.Round(1.015*100)/100 = %.2f, Money: %s\n", math.Round(price*100)/100, MustMoney("1.015").Round())
+	
+	// Целые тоже переполняются молча
+	var balance int64 = math.MaxInt64
+	balance++
+	fmt.Println("MaxInt64 + 1 =", balance)
+}
+
+// Пример 2: Счет
+func invoice() {
+	fmt.Println("\n=== Счет ===")
+	
+	inv := Invoice{
+		Lines: []Line{
+			{"Лицензия, месяц", 3, MustMoney("1990.00")},
+			{"Поддержка, час", 7, MustMoney("1333.33")},
+			{"Настройка", 1, MustMoney("0.10")},
+			{"SMS-уведомления", 1234, MustMoney("0.35")},
+		},
+		Discount: "7.5",
+		VAT:      "20",
+	}
+	fmt.Print(inv.Format())
+	
+	floatTotal := calculateFloat([]struct {
+		qty   int
+		price float64
+	}{{3, 1990.00}, {7, 1333.33}, {1, 0.10}, {1234, 0.35}}, 7.5, 20)
+	fmt.Printf("То же на float64: %.10f - не кратно копейке, и итог зависит\n", floatTotal)
+	fmt.Println("от того, где и как округлять; в Money правило записано в коде")
+}
+
+// factorial вычисляет n! точно
+func factorial(n int64) *big.Int {
+	return new(big.Int).MulRange(1, n)
+}
+
+// Пример 3: Большие целые
+func bigInts() {
+	fmt.Println("\n=== big.Int ===")
+	
+	fmt.Println("20! =", factorial(20), "(еще влезает в int64)")
+	f := factorial(100)
+	fmt.Printf("100! = %s... (%d цифр)\n", f.String()[:30], len(f.String()))
+	
+	// Модульное возведение в степень: основа RSA и Диффи-Хеллмана.
+	// Exp не вычисляет base^exp целиком - это число из миллиардов цифр.
+	p, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1, простое
+	base := big.NewInt(3)
+	exp := new(big.Int).Sub(p, big.NewInt(1))
+	fmt.Println("3^(p-1) mod p =", new(big.Int).Exp(base, exp, p), "(малая теорема Ферма)")
+	fmt.Println("p простое (Миллер-Рабин):", p.ProbablyPrime(20))
+	
+	// Игрушечный RSA на маленьких числах
+	pp, q := big.NewInt(61), big.NewInt(53)
+	n := new(big.Int).Mul(pp, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(pp, big.NewInt(1)), new(big.Int).Sub(q, big.NewInt(1)))
+	e := big.NewInt(17)
+	d := new(big.Int).ModInverse(e, phi)
+	msg := big.NewInt(65)
+	c := new(big.Int).Exp(msg, e, n)
+	fmt.Printf("RSA: n=%v e=%v d=%v, шифр(65)=%v, расшифровка=%v\n", n, e, d, c, new(big.Int).Exp(c, d, n))
+}
+
+// Пример 4: Дроби и числа произвольной точности
+func ratsAndFloats() {
+	fmt.Println("\n=== big.Rat и big.Float ===")
+	
+	third := big.NewRat(1, 3)
+	sum := new(big.Rat).Add(third, third)
+	sum.Add(sum, third)
+	fmt.Println("1/3 + 1/3 + 1/3 =", sum, "(точно)")
+	
+	// √2 с точностью 200 бит (~60 десятичных знаков)
+	two := new(big.Float).SetPrec(200).SetInt64(2)
+	fmt.Println("√2 =", new(big.Float).SetPrec(200).Sqrt(two).Text('f', 55))
+	fmt.Println("float64 √2 =", fmt.Sprintf("%.55f", math.Sqrt2))
+	
+	// big.Float тоже двоичный: 0.1 в нем неточен, просто точнее
+	tenth, _ := new(big.Float).SetPrec(100).SetString("0.1")
+	fmt.Println("0.1 в big.Float(100 бит) =", tenth.Text('f', 40))
+}
+
+func main() {
+	floatProblems()
+	invoice()
+	bigInts()
+	ratsAndFloats()
+}
\ No newline at end of file
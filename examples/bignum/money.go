@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Почему не float64: двоичная дробь не представляет точно 0.1, 0.2 и
+// большинство цен. Ошибки малы, но накапливаются при суммировании и
+// всплывают при сравнении и округлении: 0.1+0.2 != 0.3, а сумма счета
+// может разойтись с суммой строк на копейку. Для денег два честных
+// варианта: целые минимальные единицы (int64 копеек) или точные дроби
+// (big.Rat), если в расчетах есть проценты и деления.
+
+// Money точная денежная сумма. Промежуточные значения хранятся как
+// дроби без потерь, округление до копеек - только явное.
+type Money struct {
+	r *big.Rat
+}
+
+// ParseMoney разбирает "1234.56" или "1234,56"
+func ParseMoney(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.ReplaceAll(strings.TrimSpace(s), ",", "."))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return Money{r}, nil
+}
+
+// MustMoney - ParseMoney для констант в коде
+func MustMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Zero возвращает нулевую сумму
+func Zero() Money {
+	return Money{new(big.Rat)}
+}
+
+func (m Money) rat() *big.Rat {
+	if m.r == nil {
+		return new(big.Rat)
+	}
+	return m.r
+}
+
+// Add возвращает m + o
+func (m Money) Add(o Money) Money {
+	return Money{new(big.Rat).Add(m.rat(), o.rat())}
+}
+
+// Sub возвращает m - o
+func (m Money) Sub(o Money) Money {
+	return Money{new(big.Rat).Sub(m.rat(), o.rat())}
+}
+
+// MulInt умножает на целое количество
+func (m Money) MulInt(n int64) Money {
+	return Money{new(big.Rat).Mul(m.rat(), new(big.Rat).SetInt64(n))}
+}
+
+// Percent возвращает p процентов от суммы; p задается строкой ("20", "12.5"),
+// чтобы и процент не проходил через float64
+func (m Money) Percent(p string) Money {
+	pr, ok := new(big.Rat).SetString(p)
+	if !ok {
+		panic("invalid percent " + p)
+	}
+	pr.Quo(pr, big.NewRat(100, 1))
+	return Money{new(big.Rat).Mul(m.rat(), pr)}
+}
+
+// Round округляет до копеек по правилу банковского округления
+// (половина - к четному): при массовых расчетах оно не дает
+// систематического сдвига вверх, как школьное "половина - вверх"
+func (m Money) Round() Money {
+	// Количество копеек как дробь: q = m·100
+	q := new(big.Rat).Mul(m.rat(), big.NewRat(100, 1))
+	num, den := q.Num(), q.Denom()
+	
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	// Сравниваем 2·|rem| с den: меньше - вниз, больше - вверх, равно - к четному
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	switch c := twice.Cmp(den); {
+	case c > 0, c == 0 && quo.Bit(0) == 1:
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return Money{new(big.Rat).SetFrac(quo, big.NewInt(100))}
+}
+
+// Cmp сравнивает суммы: -1, 0 или 1
+func (m Money) Cmp(o Money) int {
+	return m.rat().Cmp(o.rat())
+}
+
+// Kopecks возвращает сумму в копейках (после Round)
+func (m Money) Kopecks() int64 {
+	q := new(big.Rat).Mul(m.Round().rat(), big.NewRat(100, 1))
+	return q.Num().Int64()
+}
+
+// String форматирует сумму с двумя знаками после запятой
+func (m Money) String() string {
+	return m.rat().FloatString(2)
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"2.675", "2.68"},  // Половина, 7 нечетное - вверх к 8
+		{"2.665", "2.66"},  // Половина, 6 четное - остается
+		{"2.6651", "2.67"}, // Больше половины
+		{"-2.675", "-2.68"},
+		{"-2.665", "-2.66"},
+		{"0.004", "0.00"},
+		{"1/3", "0.33"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := MustMoney(tt.in).Round().String(); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	m, err := ParseMoney(" 1234,56 ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.Kopecks() != 123456 {
+		t.Errorf("Expected 123456 kopecks, got %d", m.Kopecks())
+	}
+	if _, err := ParseMoney("12.3.4"); err == nil {
+		t.Error("Expected error for invalid amount")
+	}
+}
+
+func TestInvoiceTotals(t *testing.T) {
+	inv := Invoice{
+		Lines: []Line{
+			{"a", 3, MustMoney("0.10")},
+			{"b", 1, MustMoney("0.20")},
+		},
+		Discount: "10",
+		VAT:      "20",
+	}
+	got := inv.Calculate()
+	
+	checks := []struct {
+		name string
+		got  Money
+		want string
+	}{
+		{"subtotal", got.Subtotal, "0.50"},
+		{"discount", got.Discount, "0.05"},
+		{"net", got.Net, "0.45"},
+		{"vat", got.VAT, "0.09"},
+		{"total", got.Total, "0.54"},
+	}
+	for _, c := range checks {
+		if c.got.String() != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, c.got)
+		}
+	}
+	
+	// Итог всегда сходится с частями до копейки
+	if got.Net.Add(got.VAT).Cmp(got.Total) != 0 {
+		t.Error("Expected Net + VAT == Total")
+	}
+}
+
+func TestFactorial(t *testing.T) {
+	if got := factorial(25).String(); got != "15511210043330985984000000" {
+		t.Errorf("Unexpected 25!: %s", got)
+	}
+}
\ No newline at end of file
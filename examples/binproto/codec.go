@@ -0,0 +1,344 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MsgType тип сообщения - байт t в заголовке кадра
+type MsgType byte
+
+const (
+	MsgPing MsgType = iota + 1
+	MsgPong
+	MsgGet
+	MsgSet
+	MsgValue
+	MsgOK
+	MsgError
+)
+
+func (t MsgType) String() string {
+	switch t {
+	case MsgPing:
+		return "Ping"
+	case MsgPong:
+		return "Pong"
+	case MsgGet:
+		return "Get"
+	case MsgSet:
+		return "Set"
+	case MsgValue:
+		return "Value"
+	case MsgOK:
+		return "OK"
+	case MsgError:
+		return "Error"
+	}
+	return fmt.Sprintf("MsgType(%d)", byte(t))
+}
+
+// Message сообщение протокола
+type Message interface {
+	Type() MsgType
+}
+
+// Ping проверка связи: сервер отвечает Pong с тем же Nonce
+type Ping struct {
+	Nonce uint64 `json:"nonce"`
+}
+
+type Pong struct {
+	Nonce uint64 `json:"nonce"`
+}
+
+// Get чтение ключа: ответ Value
+type Get struct {
+	Key string `json:"key"`
+}
+
+// Set запись ключа: ответ OK
+type Set struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type Value struct {
+	Key   string `json:"key"`
+	Found bool   `json:"found"`
+	Value []byte `json:"value,omitempty"`
+}
+
+type OK struct{}
+
+// Error ответ об ошибке: Code - для программы, Text - для человека
+type Error struct {
+	Code uint16 `json:"code"`
+	Text string `json:"text"`
+}
+
+// Коды ошибок
+const (
+	CodeBadRequest  uint16 = 400
+	CodeBadFrame    uint16 = 422
+	CodeUnsupported uint16 = 505
+)
+
+func (Ping) Type() MsgType  { return MsgPing }
+func (Pong) Type() MsgType  { return MsgPong }
+func (Get) Type() MsgType   { return MsgGet }
+func (Set) Type() MsgType   { return MsgSet }
+func (Value) Type() MsgType { return MsgValue }
+func (OK) Type() MsgType    { return MsgOK }
+func (Error) Type() MsgType { return MsgError }
+
+func (e Error) Error() string {
+	return fmt.Sprintf("код %d: %s", e.Code, e.Text)
+}
+
+// newMessage пустое сообщение по типу из заголовка
+func newMessage(t MsgType) (Message, error) {
+	switch t {
+	case MsgPing:
+		return &Ping{}, nil
+	case MsgPong:
+		return &Pong{}, nil
+	case MsgGet:
+		return &Get{}, nil
+	case MsgSet:
+		return &Set{}, nil
+	case MsgValue:
+		return &Value{}, nil
+	case MsgOK:
+		return &OK{}, nil
+	case MsgError:
+		return &Error{}, nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnknownType, t)
+}
+
+// CodecID кодек данных - байт enc в заголовке кадра. Кодек выбирает
+// клиент, сервер отвечает тем же.
+type CodecID byte
+
+const (
+	CodecBinary CodecID = iota + 1
+	CodecJSON
+)
+
+// Codec кодирует данные сообщения. Тип сообщения в данные не входит:
+// он уже есть в заголовке кадра.
+type Codec interface {
+	ID() CodecID
+	Marshal(m Message) ([]byte, error)
+	Unmarshal(t MsgType, data []byte) (Message, error)
+}
+
+var (
+	ErrUnknownType  = errors.New("неизвестный тип сообщения")
+	ErrUnknownCodec = errors.New("неизвестный кодек")
+	ErrMalformed    = errors.New("повреждены данные сообщения")
+)
+
+var codecs = map[CodecID]Codec{
+	CodecBinary: binaryCodec{},
+	CodecJSON:   jsonCodec{},
+}
+
+// codecFor кодек по байту из заголовка
+func codecFor(id CodecID) (Codec, error) {
+	c, ok := codecs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, id)
+	}
+	return c, nil
+}
+
+// jsonCodec читаемый и расширяемый: новые поля старые клиенты
+// пропускают. Платит размером и временем разбора.
+type jsonCodec struct{}
+
+func (jsonCodec) ID() CodecID { return CodecJSON }
+
+func (jsonCodec) Marshal(m Message) ([]byte, error) {
+	return json.Marshal(m)
+}
+
+func (jsonCodec) Unmarshal(t MsgType, data []byte) (Message, error) {
+	m, err := newMessage(t)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return deref(m), nil
+}
+
+// binaryCodec компактный: числа - varint, строки и байты - длина
+// varint и содержимое. Поля идут в фиксированном порядке, поэтому
+// добавить поле можно только в конец и только вместе с новой версией.
+type binaryCodec struct{}
+
+func (binaryCodec) ID() CodecID { return CodecBinary }
+
+func (binaryCodec) Marshal(m Message) ([]byte, error) {
+	var b []byte
+	switch m := m.(type) {
+	case Ping:
+		b = binary.AppendUvarint(b, m.Nonce)
+	case Pong:
+		b = binary.AppendUvarint(b, m.Nonce)
+	case Get:
+		b = appendString(b, m.Key)
+	case Set:
+		b = appendString(b, m.Key)
+		b = appendBytes(b, m.Value)
+	case Value:
+		b = appendString(b, m.Key)
+		b = appendBool(b, m.Found)
+		b = appendBytes(b, m.Value)
+	case OK:
+	case Error:
+		b = binary.AppendUvarint(b, uint64(m.Code))
+		b = appendString(b, m.Text)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownType, m)
+	}
+	return b, nil
+}
+
+func (binaryCodec) Unmarshal(t MsgType, data []byte) (Message, error) {
+	d := decoder{buf: data}
+	var m Message
+	switch t {
+	case MsgPing:
+		m = Ping{Nonce: d.uvarint()}
+	case MsgPong:
+		m = Pong{Nonce: d.uvarint()}
+	case MsgGet:
+		m = Get{Key: d.string()}
+	case MsgSet:
+		m = Set{Key: d.string(), Value: d.bytes()}
+	case MsgValue:
+		m = Value{Key: d.string(), Found: d.bool(), Value: d.bytes()}
+	case MsgOK:
+		m = OK{}
+	case MsgError:
+		code := d.uvarint()
+		if code > 0xFFFF {
+			d.fail()
+		}
+		m = Error{Code: uint16(code), Text: d.string()}
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownType, t)
+	}
+	// Лишние байты в конце - тоже ошибка: иначе у одного сообщения
+	// было бы много кодировок, и CRC по ним ничего бы не доказывал
+	if d.err != nil || len(d.buf) != 0 {
+		return nil, ErrMalformed
+	}
+	return m, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendBytes(b, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+// decoder читает поля по порядку. Первая ошибка запоминается, дальше
+// все чтения возвращают нули - проверка одна, в конце.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = ErrMalformed
+	}
+	d.buf = nil
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	// Ноль в старшем байте - лишний: 0x80 0x00 тоже читается как 0.
+	// Такие кодировки отвергаются, у каждого числа она одна.
+	if n <= 0 || (n > 1 && d.buf[n-1] == 0) {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// take следующие n байт. Длина из данных сравнивается с остатком до
+// выделения памяти: varint на 10 байт не должен стоить гигабайта.
+func (d *decoder) take() []byte {
+	n := d.uvarint()
+	if d.err != nil || n > uint64(len(d.buf)) {
+		d.fail()
+		return nil
+	}
+	v := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	return string(d.take())
+}
+
+func (d *decoder) bytes() []byte {
+	v := d.take()
+	if len(v) == 0 {
+		return nil
+	}
+	return append([]byte(nil), v...)
+}
+
+func (d *decoder) bool() bool {
+	if len(d.buf) == 0 || d.buf[0] > 1 {
+		d.fail()
+		return false
+	}
+	v := d.buf[0] == 1
+	d.buf = d.buf[1:]
+	return v
+}
+
+// deref *Ping -> Ping: JSON разбирает в указатель, а сообщения
+// передаются значениями
+func deref(m Message) Message {
+	switch m := m.(type) {
+	case *Ping:
+		return *m
+	case *Pong:
+		return *m
+	case *Get:
+		return *m
+	case *Set:
+		return *m
+	case *Value:
+		return *m
+	case *OK:
+		return *m
+	case *Error:
+		return *m
+	}
+	return m
+}
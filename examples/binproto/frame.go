@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Кадр протокола. TCP - поток байт без границ сообщений: один Write
+// может прийти двумя Read, два Write - одним. Границы задает кадр:
+//
+//	 0      2    3    4     5          9            9+N    13+N
+//	+------+----+----+-----+----------+------------+--------+
+//	| "GL" | v  | t  | enc | длина N  | данные (N) | CRC32  |
+//	+------+----+----+-----+----------+------------+--------+
+//
+//   - "GL" (magic) - быстро отличить наш протокол от случайного клиента
+//     (HTTP, сканер портов) и заметить сбой синхронизации;
+//   - v - версия формата кадра: сервер отвечает ошибкой на незнакомую,
+//     а не пытается разобрать ее как свою;
+//   - t - тип сообщения, enc - кодек данных (binary или JSON);
+//   - длина N - uint32 big-endian, не больше MaxPayload: до проверки
+//     память под данные не выделяется, иначе четыре байта от клиента
+//     заставили бы сервер выделить 4 ГБ;
+//   - CRC32 (Castagnoli) заголовка и данных. TCP уже считает свою
+//     контрольную сумму, но она 16-битная и не ловит ошибки в памяти
+//     прокси и балансировщиков по пути.
+
+const (
+	// Version текущая версия формата кадра
+	Version = 1
+	
+	// MaxPayload предел данных кадра
+	MaxPayload = 1 << 20
+	
+	headerSize  = 9
+	trailerSize = 4
+)
+
+var magic = [2]byte{'G', 'L'}
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Ошибки разбора кадра. После любой из них поток рассинхронизирован:
+// где начинается следующий кадр, неизвестно, и соединение закрывают.
+var (
+	ErrBadMagic = errors.New("неверная сигнатура кадра")
+	ErrVersion  = errors.New("неподдерживаемая версия протокола")
+	ErrTooLarge = errors.New("кадр больше предела")
+	ErrChecksum = errors.New("неверная контрольная сумма")
+)
+
+// Frame кадр: тип сообщения, кодек и закодированные данные
+type Frame struct {
+	Version byte
+	Type    MsgType
+	Codec   CodecID
+	Payload []byte
+}
+
+// appendFrame дописывает кадр в buf
+func appendFrame(buf []byte, f Frame) ([]byte, error) {
+	if len(f.Payload) > MaxPayload {
+		return buf, ErrTooLarge
+	}
+	start := len(buf)
+	buf = append(buf, magic[0], magic[1], f.Version, byte(f.Type), byte(f.Codec))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(f.Payload)))
+	buf = append(buf, f.Payload...)
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf[start:], crcTable)), nil
+}
+
+// WriteFrame пишет кадр одним Write: куски кадра от разных горутин не
+// перемешаются, если Write на соединении сериализован
+func WriteFrame(w io.Writer, f Frame) error {
+	buf, err := appendFrame(make([]byte, 0, headerSize+len(f.Payload)+trailerSize), f)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// ReadFrame читает один кадр. io.EOF - только если поток кончился ровно
+// между кадрами; оборванный кадр - io.ErrUnexpectedEOF.
+func ReadFrame(r *bufio.Reader) (Frame, error) {
+	var hdr [headerSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return Frame{}, err
+	}
+	if hdr[0] != magic[0] || hdr[1] != magic[1] {
+		return Frame{}, ErrBadMagic
+	}
+	f := Frame{Version: hdr[2], Type: MsgType(hdr[3]), Codec: CodecID(hdr[4])}
+	if f.Version != Version {
+		return f, fmt.Errorf("%w: %d", ErrVersion, f.Version)
+	}
+	n := binary.BigEndian.Uint32(hdr[5:])
+	if n > MaxPayload {
+		return f, fmt.Errorf("%w: %d байт", ErrTooLarge, n)
+	}
+	
+	body := make([]byte, int(n)+trailerSize)
+	if _, err := io.ReadFull(r, body); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return f, err
+	}
+	crc := crc32.Update(crc32.Checksum(hdr[:], crcTable), crcTable, body[:n])
+	if crc != binary.BigEndian.Uint32(body[n:]) {
+		return f, ErrChecksum
+	}
+	f.Payload = body[:n:n]
+	return f, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"testing"
+	"time"
+)
+
+// Собственный бинарный протокол поверх TCP: кадры с сигнатурой,
+// версией, типом, длиной и CRC, два кодека данных и fuzz-тесты на
+// испорченный ввод. Объединяет уроки про сеть, кодирование и fuzzing.
+//
+//   - frame.go - формат кадра, чтение и запись;
+//   - codec.go - сообщения и интерфейс Codec: binary и JSON;
+//   - server.go - сервер ключ-значение и клиент;
+//   - proto_test.go - тесты, fuzz-тесты и бенчмарки кодеков.
+//
+// Запуск: go run .
+// Fuzzing (по одному fuzz-тесту за запуск):
+//
+//	go test -fuzz=FuzzReadFrame -fuzztime=30s
+//	go test -fuzz=FuzzBinaryUnmarshal -fuzztime=30s
+//	go test -fuzz=FuzzServeConn -fuzztime=30s
+//
+// Найденные входы сохраняются в testdata/fuzz и дальше проверяются
+// обычным go test.
+
+// Пример 1: Запросы обоими кодеками
+func requests(addr string) {
+	fmt.Println("=== Запросы ===")
+	
+	for _, codec := range []Codec{binaryCodec{}, jsonCodec{}} {
+		c, err := Dial(addr, codec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		key := fmt.Sprintf("greeting-%d", codec.ID())
+		for _, req := range []Message{
+			Ping{Nonce: 42},
+			Set{Key: key, Value: []byte("привет")},
+			Get{Key: key},
+			Get{Key: "missing"},
+			Set{Key: ""},
+		} {
+			resp, err := c.Do(req)
+			if err != nil {
+				fmt.Printf("  %-6s %-8s -> ошибка: %v\n", codecName(codec), req.Type(), err)
+				continue
+			}
+			fmt.Printf("  %-6s %-8s -> %s %+v\n", codecName(codec), req.Type(), resp.Type(), resp)
+		}
+		c.Close()
+	}
+}
+
+func codecName(c Codec) string {
+	if c.ID() == CodecJSON {
+		return "json"
+	}
+	return "binary"
+}
+
+// Пример 2: Кадр байт за байтом и размер кодеков
+func wireFormat() {
+	fmt.Println("\n=== Кадр ===")
+	
+	msg := Set{Key: "user:1", Value: []byte("Alice")}
+	for _, codec := range []Codec{binaryCodec{}, jsonCodec{}} {
+		payload, _ := codec.Marshal(msg)
+		frame, _ := appendFrame(nil, Frame{Version: Version, Type: msg.Type(), Codec: codec.ID(), Payload: payload})
+		fmt.Printf("%s: данные %d байт, кадр %d байт\n", codecName(codec), len(payload), len(frame))
+		fmt.Printf("  заголовок % x | данные % x | crc % x\n",
+			frame[:headerSize], frame[headerSize:len(frame)-trailerSize], frame[len(frame)-trailerSize:])
+	}
+}
+
+// Пример 3: Испорченный ввод - сервер отвечает ошибкой и закрывает
+// соединение, а не падает и не ждет вечно
+func malformed(addr string) {
+	fmt.Println("\n=== Испорченный ввод ===")
+	
+	payload, _ := binaryCodec{}.Marshal(Get{Key: "k"})
+	valid, _ := appendFrame(nil, Frame{Version: Version, Type: MsgGet, Codec: CodecBinary, Payload: payload})
+	
+	corrupt := func(fn func(b []byte) []byte) []byte {
+		return fn(append([]byte(nil), valid...))
+	}
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"HTTP-запрос", []byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")},
+		{"версия 2", corrupt(func(b []byte) []byte { b[2] = 2; return b })},
+		{"длина 4 ГБ", corrupt(func(b []byte) []byte { copy(b[5:9], []byte{0xFF, 0xFF, 0xFF, 0xFF}); return b })},
+		{"бит в данных", corrupt(func(b []byte) []byte { b[headerSize] ^= 0x01; return b })},
+		{"неизвестный кодек", corrupt(func(b []byte) []byte { b[4] = 9; return resum(b) })},
+		{"обрыв данных", corrupt(func(b []byte) []byte { return append(b[:headerSize], b[headerSize:][0]) })},
+	}
+	for _, tc := range cases {
+		fmt.Printf("  %-18s -> %s\n", tc.name, sendRaw(addr, tc.data))
+	}
+}
+
+// resum пересчитывает CRC после правки заголовка: кадр цел, но
+// содержимое заголовка серверу незнакомо
+func resum(b []byte) []byte {
+	f := b[:len(b)-trailerSize]
+	out, _ := appendFrame(nil, Frame{Version: f[2], Type: MsgType(f[3]), Codec: CodecID(f[4]), Payload: f[headerSize:]})
+	return out
+}
+
+// sendRaw отправляет байты как есть и описывает реакцию сервера
+func sendRaw(addr string, data []byte) string {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err.Error()
+	}
+	defer conn.Close()
+	conn.Write(data)
+	// Обрыв кадра сервер видит, только когда клиент закрыл запись
+	conn.(*net.TCPConn).CloseWrite()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	
+	f, err := ReadFrame(bufio.NewReader(conn))
+	if err != nil {
+		return "соединение закрыто без ответа (" + err.Error() + ")"
+	}
+	codec, err := codecFor(f.Codec)
+	if err != nil {
+		return err.Error()
+	}
+	resp, err := codec.Unmarshal(f.Type, f.Payload)
+	if err != nil {
+		return err.Error()
+	}
+	if e, ok := resp.(Error); ok {
+		return fmt.Sprintf("Error %d: %s", e.Code, e.Text)
+	}
+	return fmt.Sprintf("%s %+v", resp.Type(), resp)
+}
+
+// Пример 4: Цена кодеков
+func codecCost() {
+	fmt.Println("\n=== Кодеки: кодирование и разбор ===")
+	
+	msg := Value{Key: "session:8f14e45f", Found: true, Value: []byte(hex.EncodeToString(make([]byte, 32)))}
+	fmt.Printf("  %-8s %8s %12s %12s\n", "кодек", "байт", "Marshal", "Unmarshal")
+	for _, codec := range []Codec{binaryCodec{}, jsonCodec{}} {
+		payload, _ := codec.Marshal(msg)
+		enc := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				codec.Marshal(msg)
+			}
+		})
+		dec := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				codec.Unmarshal(MsgValue, payload)
+			}
+		})
+		fmt.Printf("  %-8s %8d %9d нс %9d нс\n", codecName(codec), len(payload), enc.NsPerOp(), dec.NsPerOp())
+	}
+}
+
+func main() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := NewServer()
+	go srv.Serve(l)
+	defer func() {
+		l.Close()
+		srv.Close()
+	}()
+	addr := l.Addr().String()
+	fmt.Println("Сервер:", addr)
+	
+	requests(addr)
+	wireFormat()
+	malformed(addr)
+	codecCost()
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var sampleMessages = []Message{
+	Ping{Nonce: 1 << 40},
+	Pong{Nonce: 7},
+	Get{Key: "user:1"},
+	Set{Key: "user:1", Value: []byte("Alice")},
+	Value{Key: "user:1", Found: true, Value: []byte{0, 1, 2}},
+	Value{Key: "missing"},
+	OK{},
+	Error{Code: CodeBadRequest, Text: "пустой ключ"},
+}
+
+func encodeFrame(t testing.TB, codec Codec, m Message) []byte {
+	payload, err := codec.Marshal(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, err := appendFrame(nil, Frame{Version: Version, Type: m.Type(), Codec: codec.ID(), Payload: payload})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return b
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	for _, codec := range []Codec{binaryCodec{}, jsonCodec{}} {
+		for _, m := range sampleMessages {
+			var buf bytes.Buffer
+			buf.Write(encodeFrame(t, codec, m))
+			f, err := ReadFrame(bufio.NewReader(&buf))
+			if err != nil {
+				t.Fatalf("%v %v: unexpected error: %v", codec.ID(), m.Type(), err)
+			}
+			got, err := codec.Unmarshal(f.Type, f.Payload)
+			if err != nil {
+				t.Fatalf("%v %v: unexpected error: %v", codec.ID(), m.Type(), err)
+			}
+			if !reflect.DeepEqual(got, m) {
+				t.Errorf("%v: expected %+v, got %+v", codec.ID(), m, got)
+			}
+		}
+	}
+}
+
+func TestReadFrame_Errors(t *testing.T) {
+	valid := encodeFrame(t, binaryCodec{}, Get{Key: "k"})
+	edit := func(fn func(b []byte)) []byte {
+		b := append([]byte(nil), valid...)
+		fn(b)
+		return b
+	}
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"empty", nil, io.EOF},
+		{"short header", valid[:4], io.ErrUnexpectedEOF},
+		{"short body", valid[:len(valid)-1], io.ErrUnexpectedEOF},
+		{"magic", edit(func(b []byte) { b[0] = 'X' }), ErrBadMagic},
+		{"version", edit(func(b []byte) { b[2] = Version + 1 }), ErrVersion},
+		{"length", edit(func(b []byte) { b[5] = 0xFF }), ErrTooLarge},
+		{"payload bit", edit(func(b []byte) { b[headerSize] ^= 0x80 }), ErrChecksum},
+		{"type bit", edit(func(b []byte) { b[3] ^= 0x01 }), ErrChecksum},
+		{"crc", edit(func(b []byte) { b[len(b)-1]++ }), ErrChecksum},
+	}
+	for _, tt := range tests {
+		_, err := ReadFrame(bufio.NewReader(bytes.NewReader(tt.data)))
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestReadFrame_Stream(t *testing.T) {
+	// Несколько кадров подряд, прочитанные по байту: границы из TCP
+	// не важны
+	var stream []byte
+	for _, m := range sampleMessages {
+		stream = append(stream, encodeFrame(t, binaryCodec{}, m)...)
+	}
+	r := bufio.NewReader(io.LimitReader(&oneByteReader{stream}, int64(len(stream))))
+	for _, m := range sampleMessages {
+		f, err := ReadFrame(r)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if f.Type != m.Type() {
+			t.Errorf("Expected %v, got %v", m.Type(), f.Type)
+		}
+	}
+	if _, err := ReadFrame(r); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+type oneByteReader struct{ b []byte }
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.b) == 0 {
+		return 0, io.EOF
+	}
+	p[0] = r.b[0]
+	r.b = r.b[1:]
+	return 1, nil
+}
+
+func TestBinaryUnmarshal_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  MsgType
+		data []byte
+	}{
+		{"truncated varint", MsgPing, []byte{0x80}},
+		{"length past end", MsgGet, []byte{10, 'a'}},
+		{"huge length", MsgGet, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}},
+		{"trailing bytes", MsgOK, []byte{0}},
+		{"bad bool", MsgValue, []byte{1, 'k', 2, 0}},
+		{"code overflow", MsgError, []byte{0x80, 0x80, 0x04, 0}},
+		{"non-minimal varint", MsgPing, []byte{0xB1, 0x00}},
+	}
+	for _, tt := range tests {
+		if _, err := (binaryCodec{}).Unmarshal(tt.typ, tt.data); !errors.Is(err, ErrMalformed) {
+			t.Errorf("%s: expected ErrMalformed, got %v", tt.name, err)
+		}
+	}
+	if _, err := (binaryCodec{}).Unmarshal(MsgType(200), nil); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, got %v", err)
+	}
+}
+
+func startServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	srv := NewServer()
+	go srv.Serve(l)
+	t.Cleanup(func() {
+		l.Close()
+		srv.Close()
+	})
+	return l.Addr().String()
+}
+
+func TestServer_Requests(t *testing.T) {
+	addr := startServer(t)
+	for _, codec := range []Codec{binaryCodec{}, jsonCodec{}} {
+		c, err := Dial(addr, codec)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer c.Close()
+		
+		if resp, err := c.Do(Ping{Nonce: 9}); err != nil || resp != (Pong{Nonce: 9}) {
+			t.Errorf("Expected Pong 9, got %v, %v", resp, err)
+		}
+		if _, err := c.Do(Set{Key: "k", Value: []byte("v")}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		resp, err := c.Do(Get{Key: "k"})
+		if v, ok := resp.(Value); err != nil || !ok || !v.Found || string(v.Value) != "v" {
+			t.Errorf("Expected value v, got %+v, %v", resp, err)
+		}
+		var e Error
+		if _, err := c.Do(Pong{}); !errors.As(err, &e) || e.Code != CodeBadRequest {
+			t.Errorf("Expected code %d, got %v", CodeBadRequest, err)
+		}
+	}
+}
+
+func TestServer_BadFrameClosesConnection(t *testing.T) {
+	addr := startServer(t)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	
+	frame := encodeFrame(t, binaryCodec{}, Get{Key: "k"})
+	frame[headerSize] ^= 0xFF
+	conn.Write(frame)
+	r := bufio.NewReader(conn)
+	f, err := ReadFrame(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp, _ := (binaryCodec{}).Unmarshal(f.Type, f.Payload)
+	if e, ok := resp.(Error); !ok || e.Code != CodeBadFrame {
+		t.Errorf("Expected code %d, got %+v", CodeBadFrame, resp)
+	}
+	if _, err := ReadFrame(r); err != io.EOF {
+		t.Errorf("Expected connection closed, got %v", err)
+	}
+}
+
+func TestServer_BadPayloadKeepsConnection(t *testing.T) {
+	addr := startServer(t)
+	c, err := Dial(addr, binaryCodec{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+	
+	// Целый кадр с мусором внутри: ошибка в ответ, соединение живо
+	bad, _ := appendFrame(nil, Frame{Version: Version, Type: MsgGet, Codec: CodecBinary, Payload: []byte{0xFF}})
+	c.conn.Write(bad)
+	f, err := ReadFrame(c.r)
+	if err != nil || f.Type != MsgError {
+		t.Fatalf("Expected Error frame, got %v, %v", f.Type, err)
+	}
+	if _, err := c.Do(Ping{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// FuzzReadFrame произвольные байты не роняют разбор, а успешно
+// прочитанный кадр кодируется обратно в те же байты
+func FuzzReadFrame(f *testing.F) {
+	for _, m := range sampleMessages {
+		f.Add(encodeFrame(f, binaryCodec{}, m))
+		f.Add(encodeFrame(f, jsonCodec{}, m))
+	}
+	f.Add([]byte("GL"))
+	f.Add([]byte("GET / HTTP/1.1\r\n\r\n"))
+	
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fr, err := ReadFrame(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return
+		}
+		if len(fr.Payload) > MaxPayload {
+			t.Fatalf("Payload larger than MaxPayload: %d", len(fr.Payload))
+		}
+		out, err := appendFrame(nil, fr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !bytes.Equal(out, data[:len(out)]) {
+			t.Errorf("Re-encoded frame differs:\n%x\n%x", out, data[:len(out)])
+		}
+	})
+}
+
+// FuzzBinaryUnmarshal разбор данных не паникует, и у каждого сообщения
+// одна кодировка: разобранное кодируется обратно в те же байты
+func FuzzBinaryUnmarshal(f *testing.F) {
+	for _, m := range sampleMessages {
+		b, _ := binaryCodec{}.Marshal(m)
+		f.Add(byte(m.Type()), b)
+	}
+	f.Fuzz(func(t *testing.T, typ byte, data []byte) {
+		m, err := binaryCodec{}.Unmarshal(MsgType(typ), data)
+		if err != nil {
+			return
+		}
+		out, err := binaryCodec{}.Marshal(m)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !bytes.Equal(out, data) {
+			t.Errorf("%v: re-encoded %x, input %x", m.Type(), out, data)
+		}
+	})
+}
+
+// FuzzJSONUnmarshal мусор в JSON - ошибка, а не паника
+func FuzzJSONUnmarshal(f *testing.F) {
+	for _, m := range sampleMessages {
+		b, _ := jsonCodec{}.Marshal(m)
+		f.Add(byte(m.Type()), b)
+	}
+	f.Fuzz(func(t *testing.T, typ byte, data []byte) {
+		m, err := jsonCodec{}.Unmarshal(MsgType(typ), data)
+		if err == nil && m.Type() != MsgType(typ) {
+			t.Errorf("Expected type %v, got %v", MsgType(typ), m.Type())
+		}
+	})
+}
+
+// fuzzConn соединение из готового ввода: ответы сервера копятся в
+// буфере, конец ввода - EOF, как после закрытия записи клиентом
+type fuzzConn struct {
+	io.Reader
+	bytes.Buffer
+}
+
+func (c *fuzzConn) Read(p []byte) (int, error)       { return c.Reader.Read(p) }
+func (c *fuzzConn) Write(p []byte) (int, error)      { return c.Buffer.Write(p) }
+func (c *fuzzConn) Close() error                     { return nil }
+func (c *fuzzConn) LocalAddr() net.Addr              { return &net.TCPAddr{} }
+func (c *fuzzConn) RemoteAddr() net.Addr             { return &net.TCPAddr{} }
+func (c *fuzzConn) SetDeadline(time.Time) error      { return nil }
+func (c *fuzzConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *fuzzConn) SetWriteDeadline(time.Time) error { return nil }
+
+// FuzzServeConn сервер на любом вводе отвечает только целыми кадрами,
+// которые разбирает клиент, и завершает соединение, когда ввод кончился
+func FuzzServeConn(f *testing.F) {
+	ping := encodeFrame(f, binaryCodec{}, Ping{Nonce: 1})
+	f.Add(ping)
+	f.Add(append(append([]byte(nil), ping...), ping...))
+	f.Add(encodeFrame(f, jsonCodec{}, Set{Key: "k", Value: []byte("v")}))
+	f.Add([]byte("GL\x01\x03\x01\x00\x00\x00\x01"))
+	
+	f.Fuzz(func(t *testing.T, data []byte) {
+		conn := &fuzzConn{Reader: bytes.NewReader(data)}
+		NewServer().ServeConn(conn)
+		
+		r := bufio.NewReader(&conn.Buffer)
+		for {
+			fr, err := ReadFrame(r)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				t.Fatalf("Server sent malformed frame: %v", err)
+			}
+			codec, err := codecFor(fr.Codec)
+			if err != nil {
+				t.Fatalf("Server replied with unknown codec: %v", err)
+			}
+			if _, err := codec.Unmarshal(fr.Type, fr.Payload); err != nil {
+				t.Fatalf("Server sent undecodable %v: %v", fr.Type, err)
+			}
+		}
+	})
+}
+
+func BenchmarkCodecs(b *testing.B) {
+	msg := Value{Key: "session:8f14e45f", Found: true, Value: bytes.Repeat([]byte("x"), 64)}
+	for _, codec := range []Codec{binaryCodec{}, jsonCodec{}} {
+		payload, _ := codec.Marshal(msg)
+		b.Run(codecName(codec)+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				codec.Marshal(msg)
+			}
+		})
+		b.Run(codecName(codec)+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				codec.Unmarshal(MsgValue, payload)
+			}
+		})
+	}
+}
+
+func BenchmarkReadFrame(b *testing.B) {
+	frame := encodeFrame(b, binaryCodec{}, Set{Key: "k", Value: bytes.Repeat([]byte("x"), 1024)})
+	rd := bytes.NewReader(frame)
+	r := bufio.NewReader(rd)
+	b.SetBytes(int64(len(frame)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rd.Reset(frame)
+		r.Reset(rd)
+		if _, err := ReadFrame(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
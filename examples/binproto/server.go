@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// Server хранилище ключ-значение поверх кадров. Каждое соединение -
+// своя горутина: прочитать кадр, разобрать, ответить тем же кодеком.
+type Server struct {
+	IdleTimeout time.Duration // Соединение без запросов дольше - закрывается
+	
+	mu    sync.Mutex
+	store map[string][]byte
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+func NewServer() *Server {
+	return &Server{
+		IdleTimeout: 30 * time.Second,
+		store:       make(map[string][]byte),
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve принимает соединения, пока l не закроют
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.ServeConn(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Close закрывает открытые соединения и ждет их горутины. Listener
+// закрывает вызывающий.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// ServeConn обслуживает одно соединение до ошибки или EOF
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	log := slog.With("remote", conn.RemoteAddr())
+	
+	for {
+		if s.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.IdleTimeout))
+		}
+		f, err := ReadFrame(r)
+		if err != nil {
+			if err == io.EOF || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// Кадр не разобран - граница следующего неизвестна. Пробуем
+			// объяснить клиенту причину и закрываем соединение.
+			log.Warn("ошибка кадра", "error", err)
+			if code, ok := frameErrorCode(err); ok {
+				s.reply(w, CodecBinary, Error{Code: code, Text: err.Error()})
+			}
+			return
+		}
+		
+		codec, err := codecFor(f.Codec)
+		if err != nil {
+			// Кадр цел, ответить можно, но кодеком, который клиент
+			// точно понимает
+			if !s.reply(w, CodecBinary, Error{Code: CodeUnsupported, Text: err.Error()}) {
+				return
+			}
+			continue
+		}
+		var resp Message
+		req, err := codec.Unmarshal(f.Type, f.Payload)
+		if err != nil {
+			// Плохие данные внутри целого кадра: поток не сбит,
+			// соединение продолжает работать
+			resp = Error{Code: CodeBadRequest, Text: err.Error()}
+		} else {
+			resp = s.handle(req)
+		}
+		if !s.reply(w, f.Codec, resp) {
+			return
+		}
+	}
+}
+
+// frameErrorCode код ответа на ошибку кадра. На оборванное соединение
+// и таймаут отвечать некому.
+func frameErrorCode(err error) (uint16, bool) {
+	switch {
+	case errors.Is(err, ErrVersion):
+		return CodeUnsupported, true
+	case errors.Is(err, ErrBadMagic), errors.Is(err, ErrTooLarge), errors.Is(err, ErrChecksum):
+		return CodeBadFrame, true
+	}
+	return 0, false
+}
+
+func (s *Server) reply(w *bufio.Writer, id CodecID, m Message) bool {
+	codec, _ := codecFor(id)
+	payload, err := codec.Marshal(m)
+	if err != nil {
+		slog.Error("ошибка кодирования ответа", "type", m.Type(), "error", err)
+		return false
+	}
+	if err := WriteFrame(w, Frame{Version: Version, Type: m.Type(), Codec: id, Payload: payload}); err != nil {
+		return false
+	}
+	return w.Flush() == nil
+}
+
+func (s *Server) handle(req Message) Message {
+	switch req := req.(type) {
+	case Ping:
+		return Pong{Nonce: req.Nonce}
+	case Get:
+		s.mu.Lock()
+		v, ok := s.store[req.Key]
+		s.mu.Unlock()
+		return Value{Key: req.Key, Found: ok, Value: v}
+	case Set:
+		if req.Key == "" {
+			return Error{Code: CodeBadRequest, Text: "пустой ключ"}
+		}
+		s.mu.Lock()
+		s.store[req.Key] = req.Value
+		s.mu.Unlock()
+		return OK{}
+	}
+	return Error{Code: CodeBadRequest, Text: "сообщение " + req.Type().String() + " не является запросом"}
+}
+
+// Client клиент одного соединения. Запросы по очереди: ответ приходит
+// на соединение в порядке запросов, идентификаторы не нужны.
+type Client struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	codec Codec
+	mu    sync.Mutex
+}
+
+func Dial(addr string, codec Codec) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, r: bufio.NewReader(conn), codec: codec}, nil
+}
+
+// Do отправляет запрос и ждет ответ. Ответ Error возвращается как
+// ошибка типа Error.
+func (c *Client) Do(req Message) (Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	payload, err := c.codec.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := WriteFrame(c.conn, Frame{Version: Version, Type: req.Type(), Codec: c.codec.ID(), Payload: payload}); err != nil {
+		return nil, err
+	}
+	f, err := ReadFrame(c.r)
+	if err != nil {
+		return nil, err
+	}
+	codec, err := codecFor(f.Codec)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := codec.Unmarshal(f.Type, f.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := resp.(Error); ok {
+		return nil, e
+	}
+	return resp, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
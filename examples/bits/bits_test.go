@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBitSetMatchesMap(t *testing.T) {
+	s := NewBitSet(10)
+	ref := map[int]bool{}
+	for range 2000 {
+		i := rand.IntN(300)
+		if rand.IntN(3) == 0 {
+			s.Remove(i)
+			delete(ref, i)
+		} else {
+			s.Add(i)
+			ref[i] = true
+		}
+	}
+	
+	if s.Len() != len(ref) {
+		t.Errorf("Expected Len %d, got %d", len(ref), s.Len())
+	}
+	for i := range 300 {
+		if s.Has(i) != ref[i] {
+			t.Errorf("Has(%d): expected %v", i, ref[i])
+		}
+	}
+	
+	var got []int
+	s.Each(func(i int) { got = append(got, i) })
+	if !slices.IsSorted(got) || len(got) != len(ref) {
+		t.Errorf("Each must visit %d elements in order, got %v", len(ref), got)
+	}
+	if len(got) > 0 && s.Max() != got[len(got)-1] {
+		t.Errorf("Expected Max %d, got %d", got[len(got)-1], s.Max())
+	}
+}
+
+func TestBitSetUnionIntersect(t *testing.T) {
+	a, b := NewBitSet(0), NewBitSet(0)
+	for _, i := range []int{1, 5, 64, 200} {
+		a.Add(i)
+	}
+	for _, i := range []int{5, 64, 65} {
+		b.Add(i)
+	}
+	
+	a.Intersect(b)
+	if got := a.String(); got != "{5 64}" {
+		t.Errorf("Expected {5 64}, got %s", got)
+	}
+	a.Union(b)
+	if got := a.String(); got != "{5 64 65}" {
+		t.Errorf("Expected {5 64 65}, got %s", got)
+	}
+	if NewBitSet(100).Max() != -1 {
+		t.Error("Expected Max -1 for empty set")
+	}
+}
+
+func TestPermissionJSONRoundTrip(t *testing.T) {
+	for p := range Permission(RoleOwner + 1) {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", p, err)
+		}
+		var back Permission
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", data, err)
+		}
+		if back != p {
+			t.Errorf("Round trip %s -> %s -> %s", p, data, back)
+		}
+	}
+}
+
+func TestPermissionJSONErrors(t *testing.T) {
+	tests := []string{`["read","root"]`, `3`, `"read"`}
+	for _, input := range tests {
+		var p Permission
+		if err := json.Unmarshal([]byte(input), &p); err == nil {
+			t.Errorf("Expected error for %s, got %s", input, p)
+		}
+	}
+	if _, err := json.Marshal(Permission(1 << 40)); err == nil {
+		t.Error("Expected error for unknown bits")
+	}
+}
+
+func TestPermissionOps(t *testing.T) {
+	p := RoleEditor.Grant(PermAdmin).Revoke(PermWrite)
+	if p != PermRead|PermAdmin {
+		t.Errorf("Expected read|admin, got %s", p)
+	}
+	if p.Has(RoleEditor) || !p.HasAny(RoleEditor) {
+		t.Errorf("Unexpected Has/HasAny for %s", p)
+	}
+	if p.Count() != 2 {
+		t.Errorf("Expected 2 permissions, got %d", p.Count())
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	server := httptest.NewServer(NewUserStore().Handler())
+	defer server.Close()
+	
+	tests := []struct {
+		name, method, path, user, body string
+		want                           int
+	}{
+		{"no user", "GET", "/api/users", "", "", http.StatusUnauthorized},
+		{"unknown user", "GET", "/api/users", "99", "", http.StatusUnauthorized},
+		{"viewer reads", "GET", "/api/users", "3", "", http.StatusOK},
+		{"editor is not admin", "PUT", "/api/users/3/permissions", "2", `["read"]`, http.StatusForbidden},
+		{"admin grants", "PUT", "/api/users/3/permissions", "1", `[]`, http.StatusOK},
+		{"revoked viewer", "GET", "/api/users", "3", "", http.StatusForbidden},
+		{"bad permission", "PUT", "/api/users/2/permissions", "1", `["sudo"]`, http.StatusBadRequest},
+		{"missing user", "PUT", "/api/users/42/permissions", "1", `["read"]`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if tt.user != "" {
+				req.Header.Set("X-User-ID", tt.user)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
\ No newline at end of file
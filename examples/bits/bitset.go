@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// BitSet множество неотрицательных целых: бит i слова i/64 отвечает за
+// элемент i. Для плотных множеств небольших чисел это в 64 раза компактнее
+// map[int]bool, а объединение и пересечение - по слову за операцию.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet создает множество, рассчитанное на элементы меньше n
+// (больше тоже можно - слайс дорастет)
+func NewBitSet(n int) *BitSet {
+	return &BitSet{words: make([]uint64, (n+63)/64)}
+}
+
+// Add добавляет i
+func (s *BitSet) Add(i int) {
+	w := i / 64
+	for w >= len(s.words) {
+		s.words = append(s.words, 0)
+	}
+	s.words[w] |= 1 << (i % 64)
+}
+
+// Remove удаляет i; &^ (AND NOT) сбрасывает бит
+func (s *BitSet) Remove(i int) {
+	if w := i / 64; w < len(s.words) {
+		s.words[w] &^= 1 << (i % 64)
+	}
+}
+
+// Has проверяет наличие i
+func (s *BitSet) Has(i int) bool {
+	w := i / 64
+	return w < len(s.words) && s.words[w]&(1<<(i%64)) != 0
+}
+
+// Len возвращает число элементов: popcount каждого слова. bits.OnesCount64
+// компилируется в одну инструкцию POPCNT на amd64.
+func (s *BitSet) Len() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Union добавляет все элементы o
+func (s *BitSet) Union(o *BitSet) {
+	for len(s.words) < len(o.words) {
+		s.words = append(s.words, 0)
+	}
+	for i, w := range o.words {
+		s.words[i] |= w
+	}
+}
+
+// Intersect оставляет только элементы, которые есть и в o
+func (s *BitSet) Intersect(o *BitSet) {
+	for i := range s.words {
+		if i < len(o.words) {
+			s.words[i] &= o.words[i]
+		} else {
+			s.words[i] = 0
+		}
+	}
+}
+
+// Each вызывает fn для элементов по возрастанию. TrailingZeros64 сразу
+// находит младший установленный бит, а w &= w-1 сбрасывает его -
+// перебираются только элементы, а не все 64 бита слова.
+func (s *BitSet) Each(fn func(i int)) {
+	for wi, w := range s.words {
+		for w != 0 {
+			fn(wi*64 + bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+}
+
+// Max возвращает наибольший элемент или -1 для пустого множества
+func (s *BitSet) Max() int {
+	for wi := len(s.words) - 1; wi >= 0; wi-- {
+		if w := s.words[wi]; w != 0 {
+			return wi*64 + 63 - bits.LeadingZeros64(w)
+		}
+	}
+	return -1
+}
+
+// String форматирует множество как {1 5 64}
+func (s *BitSet) String() string {
+	var parts []string
+	s.Each(func(i int) { parts = append(parts, strconv.Itoa(i)) })
+	return "{" + strings.Join(parts, " ") + "}"
+}
\ No newline at end of file
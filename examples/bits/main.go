@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/bits"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Запуск демонстрации: go run .
+// Сервер с проверкой прав: go run . -serve :8080
+//   curl -H 'X-User-ID: 1' localhost:8080/api/users
+//   curl -X PUT -H 'X-User-ID: 1' -d '["read","write"]' localhost:8080/api/users/3/permissions
+
+// Пример 1: Флаги в одном числе
+func flags() {
+	fmt.Println("=== Флаги в uint64 ===")
+	
+	p := RoleViewer
+	fmt.Printf("viewer:          %-22s %05b\n", p, uint64(p))
+	p = p.Grant(PermWrite | PermInvite)
+	fmt.Printf("+write +invite:  %-22s %05b\n", p, uint64(p))
+	p = p.Revoke(PermInvite)
+	fmt.Printf("-invite:         %-22s %05b\n", p, uint64(p))
+	
+	fmt.Printf("Has(read|write) = %v, Has(read|admin) = %v, HasAny(read|admin) = %v\n",
+		p.Has(PermRead|PermWrite), p.Has(PermRead|PermAdmin), p.HasAny(PermRead|PermAdmin))
+	fmt.Printf("owner: %s, прав: %d\n", RoleOwner, RoleOwner.Count())
+	
+	// Переключение бита - XOR
+	p ^= PermDelete
+	fmt.Printf("^delete:         %s\n", p)
+	p ^= PermDelete
+	fmt.Printf("^delete еще раз: %s\n", p)
+}
+
+// Пример 2: math/bits
+func mathBits() {
+	fmt.Println("\n=== math/bits ===")
+	
+	for _, x := range []uint64{0, 1, 12, 255, 1 << 40, ^uint64(0)} {
+		fmt.Printf("%20d: единиц=%2d ведущих нулей=%2d хвостовых нулей=%2d длина=%2d\n",
+			x, bits.OnesCount64(x), bits.LeadingZeros64(x), bits.TrailingZeros64(x), bits.Len64(x))
+	}
+	
+	// Len64(x-1) - показатель ближайшей степени двойки сверху
+	for _, n := range []uint64{5, 64, 1000} {
+		fmt.Printf("степень двойки >= %d: %d\n", n, uint64(1)<<bits.Len64(n-1))
+	}
+	
+	// x & (x-1) == 0 - проверка на степень двойки без циклов
+	for _, n := range []uint64{64, 96} {
+		fmt.Printf("%d степень двойки: %v\n", n, n != 0 && n&(n-1) == 0)
+	}
+	
+	fmt.Printf("RotateLeft8(10010110, 3) = %08b\n", bits.RotateLeft8(0b10010110, 3))
+	fmt.Printf("Reverse8(10010110)       = %08b\n", bits.Reverse8(0b10010110))
+	
+	// Add64/Mul64 возвращают перенос и старшую половину - основа длинной арифметики
+	sum, carry := bits.Add64(^uint64(0), 1, 0)
+	hi, lo := bits.Mul64(1<<40, 1<<40)
+	fmt.Printf("Add64(max, 1) = %d перенос %d; Mul64(2^40, 2^40) = hi %d lo %d\n", sum, carry, hi, lo)
+}
+
+// Пример 3: Битовое множество
+func bitsets() {
+	fmt.Println("\n=== BitSet ===")
+	
+	// Решето Эратосфена: 1 бит на число вместо байта у []bool
+	const n = 100
+	composite := NewBitSet(n)
+	for i := 2; i*i < n; i++ {
+		if !composite.Has(i) {
+			for j := i * i; j < n; j += i {
+				composite.Add(j)
+			}
+		}
+	}
+	primes := NewBitSet(n)
+	for i := 2; i < n; i++ {
+		if !composite.Has(i) {
+			primes.Add(i)
+		}
+	}
+	fmt.Printf("Простые < %d (%d шт.): %s\n", n, primes.Len(), primes)
+	fmt.Printf("Наибольшее: %d\n", primes.Max())
+	
+	odd := NewBitSet(n)
+	for i := 1; i < 20; i += 2 {
+		odd.Add(i)
+	}
+	odd.Intersect(primes)
+	fmt.Printf("Нечетные простые < 20: %s\n", odd)
+	
+	big := NewBitSet(0)
+	big.Add(1000)
+	big.Union(odd)
+	fmt.Printf("Объединение с {1000}: %s, слов: %d\n", big, len(big.words))
+}
+
+// Пример 4: JSON
+func permissionsJSON() {
+	fmt.Println("\n=== Права в JSON ===")
+	
+	type member struct {
+		Name        string     `json:"name"`
+		Permissions Permission `json:"permissions"`
+	}
+	
+	data, _ := json.Marshal(member{"Мария", RoleEditor | PermInvite})
+	fmt.Printf("Marshal:   %s\n", data)
+	
+	var m member
+	if err := json.Unmarshal(data, &m); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Unmarshal: %s, в БД хранится число %d\n", m.Permissions, uint64(m.Permissions))
+	
+	for _, bad := range []string{`{"permissions":["read","admn"]}`, `{"permissions":7}`} {
+		err := json.Unmarshal([]byte(bad), &m)
+		fmt.Printf("%s -> %v\n", bad, err)
+	}
+}
+
+// User пользователь с правами
+type User struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Permissions Permission `json:"permissions"`
+}
+
+// UserStore хранилище пользователей
+type UserStore struct {
+	mu    sync.RWMutex
+	users map[int]*User
+}
+
+// NewUserStore создает хранилище с тестовыми пользователями
+func NewUserStore() *UserStore {
+	return &UserStore{users: map[int]*User{
+		1: {1, "Иван Иванов", RoleOwner},
+		2: {2, "Мария Петрова", RoleEditor},
+		3: {3, "Петр Сидоров", RoleViewer},
+	}}
+}
+
+func (s *UserStore) get(id int) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	u, ok := s.users[id]
+	if !ok {
+		return User{}, false
+	}
+	return *u, true
+}
+
+// requirePermission пропускает запрос, только если у пользователя из
+// X-User-ID есть все права из want. В реальном сервисе пользователь
+// берется из проверенного токена, а не из заголовка.
+func (s *UserStore) requirePermission(want Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.Header.Get("X-User-ID"))
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		u, ok := s.get(id)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !u.Permissions.Has(want) {
+			missing := want &^ u.Permissions
+			http.Error(w, "Forbidden: missing "+missing.String(), http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *UserStore) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	result := make([]User, 0, len(s.users))
+	for _, u := range s.users {
+		result = append(result, *u)
+	}
+	s.mu.RUnlock()
+	slices.SortFunc(result, func(a, b User) int { return a.ID - b.ID })
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (s *UserStore) setPermissions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	
+	var perms Permission
+	if err := json.NewDecoder(r.Body).Decode(&perms); err != nil {
+		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	s.mu.Lock()
+	var updated User
+	u, ok := s.users[id]
+	if ok {
+		u.Permissions = perms
+		updated = *u
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Handler возвращает роутер users API
+func (s *UserStore) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/users", s.requirePermission(PermRead, s.list))
+	mux.HandleFunc("PUT /api/users/{id}/permissions", s.requirePermission(PermAdmin, s.setPermissions))
+	return mux
+}
+
+// Пример 5: Проверка прав в users API
+func usersAPI() {
+	fmt.Println("\n=== Права в users API ===")
+	
+	server := httptest.NewServer(NewUserStore().Handler())
+	defer server.Close()
+	
+	requests := []struct {
+		method, path, user, body string
+	}{
+		{"GET", "/api/users", "3", ""},
+		{"PUT", "/api/users/3/permissions", "2", `["read","write"]`},
+		{"PUT", "/api/users/3/permissions", "1", `["read","write"]`},
+		{"PUT", "/api/users/3/permissions", "1", `["read","root"]`},
+		{"GET", "/api/users", "", ""},
+	}
+	for _, rq := range requests {
+		req, _ := http.NewRequest(rq.method, server.URL+rq.path, strings.NewReader(rq.body))
+		if rq.user != "" {
+			req.Header.Set("X-User-ID", rq.user)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("%s %s (user %q) -> %d %s", rq.method, rq.path, rq.user, resp.StatusCode, body)
+	}
+}
+
+func main() {
+	addr := flag.String("serve", "", "Адрес для запуска users API")
+	flag.Parse()
+	
+	if *addr != "" {
+		log.Printf("Сервер запущен на %s", *addr)
+		log.Fatal(http.ListenAndServe(*addr, NewUserStore().Handler()))
+	}
+	
+	flags()
+	mathBits()
+	bitsets()
+	permissionsJSON()
+	usersAPI()
+}
\ No newline at end of file
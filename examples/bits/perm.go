@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Permission набор прав пользователя в одном uint64: проверка права -
+// одна операция AND, хранение в БД - одна целочисленная колонка.
+// В JSON права выводятся списком имен, а не числом: число нечитаемо
+// и ломается при перестановке констант.
+type Permission uint64
+
+// Права - степени двойки через 1 << iota. Значения хранятся в БД,
+// поэтому новые права добавляются только в конец списка.
+const (
+	PermRead Permission = 1 << iota
+	PermWrite
+	PermDelete
+	PermInvite
+	PermAdmin
+
+	PermNone Permission = 0
+	// Роли - готовые комбинации
+	RoleViewer = PermRead
+	RoleEditor = PermRead | PermWrite
+	RoleOwner  = PermRead | PermWrite | PermDelete | PermInvite | PermAdmin
+)
+
+var permNames = []struct {
+	perm Permission
+	name string
+}{
+	{PermRead, "read"},
+	{PermWrite, "write"},
+	{PermDelete, "delete"},
+	{PermInvite, "invite"},
+	{PermAdmin, "admin"},
+}
+
+// Has проверяет, что есть все права из want
+func (p Permission) Has(want Permission) bool {
+	return p&want == want
+}
+
+// HasAny проверяет, что есть хотя бы одно право из want
+func (p Permission) HasAny(want Permission) bool {
+	return p&want != 0
+}
+
+// Grant возвращает набор с добавленными правами
+func (p Permission) Grant(add Permission) Permission {
+	return p | add
+}
+
+// Revoke возвращает набор без указанных прав
+func (p Permission) Revoke(remove Permission) Permission {
+	return p &^ remove
+}
+
+// Count возвращает число прав в наборе
+func (p Permission) Count() int {
+	return bits.OnesCount64(uint64(p))
+}
+
+// Names возвращает имена прав в порядке объявления
+func (p Permission) Names() []string {
+	names := []string{}
+	for _, pn := range permNames {
+		if p.Has(pn.perm) {
+			names = append(names, pn.name)
+		}
+	}
+	return names
+}
+
+// String форматирует набор как read|write
+func (p Permission) String() string {
+	if p == PermNone {
+		return "none"
+	}
+	s := strings.Join(p.Names(), "|")
+	// Неизвестные биты не теряются молча
+	if unknown := p &^ RoleOwner; unknown != 0 {
+		s += fmt.Sprintf("|0x%x", uint64(unknown))
+	}
+	return strings.TrimPrefix(s, "|")
+}
+
+// ParsePermission разбирает имя права
+func ParsePermission(name string) (Permission, error) {
+	for _, pn := range permNames {
+		if pn.name == name {
+			return pn.perm, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown permission %q", name)
+}
+
+// MarshalJSON выводит ["read","write"]
+func (p Permission) MarshalJSON() ([]byte, error) {
+	if unknown := p &^ RoleOwner; unknown != 0 {
+		return nil, fmt.Errorf("permission has unknown bits 0x%x", uint64(unknown))
+	}
+	return json.Marshal(p.Names())
+}
+
+// UnmarshalJSON принимает ["read","write"]; неизвестное имя - ошибка,
+// а не молчаливый пропуск, иначе опечатка в "admin" лишит прав без следа
+func (p *Permission) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("permissions must be a list of names: %w", err)
+	}
+	var result Permission
+	for _, name := range names {
+		perm, err := ParsePermission(name)
+		if err != nil {
+			return err
+		}
+		result |= perm
+	}
+	*p = result
+	return nil
+}
\ No newline at end of file
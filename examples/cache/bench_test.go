@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// go test -bench=. -benchmem
+// Ключи с распределением Ципфа: немногие горячие, длинный хвост холодных -
+// так выглядит реальная нагрузка на кэш
+
+func zipfKeys(n int) []int {
+	z := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, 100000)
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = int(z.Uint64())
+	}
+	return keys
+}
+
+func benchCache(b *testing.B, c Cache[int, int]) {
+	keys := zipfKeys(1 << 16)
+	hits := 0
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		k := keys[i&(len(keys)-1)]
+		if _, ok := c.Get(k); ok {
+			hits++
+		} else {
+			c.Set(k, k)
+		}
+		i++
+	}
+	b.ReportMetric(float64(hits)/float64(i)*100, "hit%")
+}
+
+func BenchmarkLRU(b *testing.B) {
+	benchCache(b, NewLRU[int, int](1000))
+}
+
+func BenchmarkLFU(b *testing.B) {
+	benchCache(b, NewLFU[int, int](1000))
+}
+
+// Базовая линия: map без вытеснения (неограниченный рост памяти)
+type mapCache map[int]int
+
+func (m mapCache) Get(k int) (int, bool) { v, ok := m[k]; return v, ok }
+func (m mapCache) Set(k, v int)          { m[k] = v }
+func (m mapCache) Delete(k int) bool     { _, ok := m[k]; delete(m, k); return ok }
+func (m mapCache) Len() int              { return len(m) }
+
+func BenchmarkMap(b *testing.B) {
+	benchCache(b, mapCache{})
+}
+
+func BenchmarkSynchronizedLRUParallel(b *testing.B) {
+	c := NewSynchronized[int, int](NewLRU[int, int](1000))
+	keys := zipfKeys(1 << 16)
+	var seed sync.Mutex
+	next := 0
+	b.RunParallel(func(pb *testing.PB) {
+		seed.Lock()
+		i := next
+		next += 7919
+		seed.Unlock()
+		for pb.Next() {
+			k := keys[i&(len(keys)-1)]
+			if _, ok := c.Get(k); !ok {
+				c.Set(k, k)
+			}
+			i++
+		}
+	})
+}
\ No newline at end of file
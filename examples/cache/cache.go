@@ -0,0 +1,92 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache общий интерфейс LRU и LFU. Реализации не потокобезопасны:
+// даже Get меняет внутренний порядок, поэтому для конкурентного доступа
+// нужна обертка Synchronized.
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V)
+	Delete(key K) bool
+	Len() int
+}
+
+// Option настраивает кэш
+type Option func(*options)
+
+type options struct {
+	ttl   time.Duration
+	now   func() time.Time
+	evict func(key any)
+}
+
+// WithTTL задает срок жизни записей. Просроченные записи удаляются лениво:
+// при обращении к ним или при вытеснении, без фоновой горутины.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) { o.ttl = ttl }
+}
+
+// WithClock подменяет источник времени (для тестов)
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// WithEvictCallback вызывается для ключей, вытесненных из-за переполнения
+func WithEvictCallback(fn func(key any)) Option {
+	return func(o *options) { o.evict = fn }
+}
+
+func buildOptions(opts []Option) options {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options) expiry() time.Time {
+	if o.ttl <= 0 {
+		return time.Time{}
+	}
+	return o.now().Add(o.ttl)
+}
+
+// Synchronized делает любой Cache безопасным для конкурентного доступа.
+// Обычный Mutex, а не RWMutex: Get тоже пишет (переставляет элементы).
+type Synchronized[K comparable, V any] struct {
+	mu    sync.Mutex
+	cache Cache[K, V]
+}
+
+// NewSynchronized оборачивает кэш мьютексом
+func NewSynchronized[K comparable, V any](c Cache[K, V]) *Synchronized[K, V] {
+	return &Synchronized[K, V]{cache: c}
+}
+
+func (s *Synchronized[K, V]) Get(key K) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Get(key)
+}
+
+func (s *Synchronized[K, V]) Set(key K, value V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache.Set(key, value)
+}
+
+func (s *Synchronized[K, V]) Delete(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Delete(key)
+}
+
+func (s *Synchronized[K, V]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Len()
+}
\ No newline at end of file
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictionOrder(t *testing.T) {
+	var evicted []any
+	c := NewLRU[int, string](2, WithEvictCallback(func(k any) { evicted = append(evicted, k) }))
+	c.Set(1, "a")
+	c.Set(2, "b")
+	c.Get(1)
+	c.Set(3, "c") // Вытесняет 2
+	
+	if _, ok := c.Get(2); ok {
+		t.Error("Expected key 2 to be evicted")
+	}
+	if !slices.Equal(c.Keys(), []int{3, 1}) {
+		t.Errorf("Expected order [3 1], got %v", c.Keys())
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Errorf("Expected eviction callback for 2, got %v", evicted)
+	}
+	
+	c.Set(1, "A") // Обновление не вытесняет
+	if v, _ := c.Get(1); v != "A" || c.Len() != 2 {
+		t.Errorf("Expected updated value A and len 2, got %q and %d", v, c.Len())
+	}
+}
+
+func TestLFUEvictsLeastFrequent(t *testing.T) {
+	c := NewLFU[string, int](2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Get("a")
+	c.Set("c", 3) // Вытесняет b (freq 1)
+	
+	if _, ok := c.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if f := c.Freq("a"); f != 3 { // Set + 2 Get
+		t.Errorf("Expected freq(a) = 3, got %d", f)
+	}
+	
+	// При равной частоте вытесняется самая давняя запись
+	c2 := NewLFU[string, int](2)
+	c2.Set("x", 1)
+	c2.Set("y", 2)
+	c2.Set("z", 3)
+	if _, ok := c2.Get("x"); ok {
+		t.Error("Expected x to be evicted on frequency tie")
+	}
+}
+
+// Эталонная модель: LRU на слайсе, O(n), но очевидно корректная
+func TestLRUMatchesModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	c := NewLRU[int, int](8)
+	var model []int // Ключи от старого к свежему
+	values := make(map[int]int)
+	
+	touch := func(k int) {
+		i := slices.Index(model, k)
+		model = append(slices.Delete(model, i, i+1), k)
+	}
+	for step := 0; step < 10000; step++ {
+		k := rng.Intn(20)
+		switch rng.Intn(3) {
+		case 0:
+			v, ok := c.Get(k)
+			_, inModel := values[k]
+			if ok != inModel || (ok && v != values[k]) {
+				t.Fatalf("Step %d: Get(%d) = %d,%v; model %d,%v", step, k, v, ok, values[k], inModel)
+			}
+			if ok {
+				touch(k)
+			}
+		case 1:
+			if _, ok := values[k]; ok {
+				touch(k)
+			} else {
+				if len(model) == 8 {
+					delete(values, model[0])
+					model = model[1:]
+				}
+				model = append(model, k)
+			}
+			values[k] = step
+			c.Set(k, step)
+		case 2:
+			_, inModel := values[k]
+			if c.Delete(k) != inModel {
+				t.Fatalf("Step %d: Delete(%d) mismatch", step, k)
+			}
+			if inModel {
+				delete(values, k)
+				i := slices.Index(model, k)
+				model = slices.Delete(model, i, i+1)
+			}
+		}
+		if c.Len() != len(model) {
+			t.Fatalf("Step %d: expected len %d, got %d", step, len(model), c.Len())
+		}
+	}
+}
+
+func TestLFUDeleteKeepsMinFreq(t *testing.T) {
+	c := NewLFU[int, int](3)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(2)
+	c.Set(3, 3)
+	c.Get(3)
+	c.Delete(1) // minFreq должен стать 2
+	c.Set(4, 4)
+	c.Set(5, 5) // Вытесняет 4 (freq 1), а не 2 или 3
+	
+	for _, k := range []int{2, 3, 5} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("Expected key %d to be present", k)
+		}
+	}
+	if _, ok := c.Get(4); ok {
+		t.Error("Expected key 4 to be evicted")
+	}
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+	
+	for name, c := range map[string]Cache[string, int]{
+		"LRU": NewLRU[string, int](10, WithTTL(time.Second), WithClock(clock)),
+		"LFU": NewLFU[string, int](10, WithTTL(time.Second), WithClock(clock)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			now = time.Unix(0, 0)
+			c.Set("k", 1)
+			now = now.Add(500 * time.Millisecond)
+			if _, ok := c.Get("k"); !ok {
+				t.Error("Expected key before TTL")
+			}
+			now = now.Add(time.Second)
+			if _, ok := c.Get("k"); ok {
+				t.Error("Expected key to expire")
+			}
+			if c.Len() != 0 {
+				t.Errorf("Expected expired entry to be removed, len %d", c.Len())
+			}
+		})
+	}
+}
+
+func TestLoadingDeduplicatesAndSkipsErrors(t *testing.T) {
+	var loads atomic.Int32
+	fail := atomic.Bool{}
+	fail.Store(true)
+	
+	l := NewLoading(NewLRU[int, int](10), func(ctx context.Context, k int) (int, error) {
+		loads.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		if fail.Load() {
+			return 0, errors.New("db down")
+		}
+		return k * 10, nil
+	})
+	
+	if _, err := l.Get(context.Background(), 1); err == nil {
+		t.Fatal("Expected error from loader")
+	}
+	fail.Store(false)
+	
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Get(context.Background(), 1)
+			if err != nil || v != 10 {
+				t.Errorf("Expected 10, got %d (%v)", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	
+	// 1 неудачная загрузка + 1 общая для 50 горутин (ошибка не закэширована)
+	if n := loads.Load(); n != 2 {
+		t.Errorf("Expected 2 loads, got %d", n)
+	}
+	
+	l.Invalidate(1)
+	l.Get(context.Background(), 1)
+	if n := loads.Load(); n != 3 {
+		t.Errorf("Expected reload after Invalidate, got %d loads", n)
+	}
+}
+
+func TestSynchronizedConcurrent(t *testing.T) {
+	c := NewSynchronized[int, int](NewLFU[int, int](50))
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				k := (g*31 + i) % 100
+				c.Set(k, i)
+				c.Get(k)
+			}
+		}()
+	}
+	wg.Wait()
+	if c.Len() > 50 {
+		t.Errorf("Expected at most 50 entries, got %d", c.Len())
+	}
+}
\ No newline at end of file
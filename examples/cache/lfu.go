@@ -0,0 +1,142 @@
+package main
+
+// LFU вытесняет запись с наименьшим числом обращений, а среди равных -
+// самую давнюю. В отличие от LRU, однократный проход по множеству
+// холодных ключей (scan) не выбивает из кэша часто используемые.
+//
+// Get, Set и вытеснение - O(1): записи сгруппированы в списки по частоте, и
+// minFreq указывает на список, из которого вытеснять.
+type LFU[K comparable, V any] struct {
+	capacity int
+	items    map[K]*entry[K, V]
+	freqs    map[int]*list[K, V]
+	minFreq  int
+	opts     options
+}
+
+// NewLFU создает LFU-кэш на capacity записей
+func NewLFU[K comparable, V any](capacity int, opts ...Option) *LFU[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be positive")
+	}
+	return &LFU[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*entry[K, V], capacity),
+		freqs:    make(map[int]*list[K, V]),
+		opts:     buildOptions(opts),
+	}
+}
+
+// Get возвращает значение и увеличивает счетчик обращений
+func (c *LFU[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if e.expired(c.opts.now()) {
+		c.removeEntry(e)
+		var zero V
+		return zero, false
+	}
+	c.touch(e)
+	return e.value, true
+}
+
+// Set добавляет или обновляет запись
+func (c *LFU[K, V]) Set(key K, value V) {
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		e.expires = c.opts.expiry()
+		c.touch(e)
+		return
+	}
+	
+	if len(c.items) >= c.capacity {
+		c.evict()
+	}
+	e := &entry[K, V]{key: key, value: value, expires: c.opts.expiry(), freq: 1}
+	c.items[key] = e
+	c.bucket(1).pushFront(e)
+	c.minFreq = 1
+}
+
+// Delete удаляет запись
+func (c *LFU[K, V]) Delete(key K) bool {
+	e, ok := c.items[key]
+	if ok {
+		c.removeEntry(e)
+	}
+	return ok
+}
+
+// Len возвращает число записей
+func (c *LFU[K, V]) Len() int {
+	return len(c.items)
+}
+
+// Freq возвращает счетчик обращений к ключу (для демонстрации и тестов)
+func (c *LFU[K, V]) Freq(key K) int {
+	if e, ok := c.items[key]; ok {
+		return e.freq
+	}
+	return 0
+}
+
+func (c *LFU[K, V]) bucket(freq int) *list[K, V] {
+	l, ok := c.freqs[freq]
+	if !ok {
+		l = newList[K, V]()
+		c.freqs[freq] = l
+	}
+	return l
+}
+
+// touch переносит запись в список со следующей частотой
+func (c *LFU[K, V]) touch(e *entry[K, V]) {
+	old := c.freqs[e.freq]
+	old.remove(e)
+	if old.len == 0 {
+		delete(c.freqs, e.freq)
+		if c.minFreq == e.freq {
+			c.minFreq++
+		}
+	}
+	e.freq++
+	c.bucket(e.freq).pushFront(e)
+}
+
+func (c *LFU[K, V]) evict() {
+	l, ok := c.freqs[c.minFreq]
+	if !ok {
+		return
+	}
+	e := l.back()
+	c.removeEntry(e)
+	if c.opts.evict != nil && !e.expired(c.opts.now()) {
+		c.opts.evict(e.key)
+	}
+}
+
+func (c *LFU[K, V]) removeEntry(e *entry[K, V]) {
+	l := c.freqs[e.freq]
+	l.remove(e)
+	if l.len == 0 {
+		delete(c.freqs, e.freq)
+		if c.minFreq == e.freq {
+			c.recomputeMinFreq()
+		}
+	}
+	delete(c.items, e.key)
+}
+
+// recomputeMinFreq нужен только после Delete или удаления просроченной
+// записи; при вытеснении сразу за ним следует вставка с freq=1
+func (c *LFU[K, V]) recomputeMinFreq() {
+	c.minFreq = 0
+	for f := range c.freqs {
+		if c.minFreq == 0 || f < c.minFreq {
+			c.minFreq = f
+		}
+	}
+}
\ No newline at end of file
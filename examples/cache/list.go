@@ -0,0 +1,63 @@
+package main
+
+import "time"
+
+// entry элемент кэша; он же узел двусвязного списка, чтобы не
+// выделять под узел отдельный объект
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // Нулевое значение - без срока жизни
+	freq    int       // Только для LFU
+
+	prev, next *entry[K, V]
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// list двусвязный список с фиктивным корнем: root.next - голова,
+// root.prev - хвост, пустой список замкнут сам на себя
+type list[K comparable, V any] struct {
+	root entry[K, V]
+	len  int
+}
+
+func newList[K comparable, V any]() *list[K, V] {
+	l := &list[K, V]{}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+func (l *list[K, V]) pushFront(e *entry[K, V]) {
+	e.prev = &l.root
+	e.next = l.root.next
+	l.root.next.prev = e
+	l.root.next = e
+	l.len++
+}
+
+func (l *list[K, V]) remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+	l.len--
+}
+
+func (l *list[K, V]) moveToFront(e *entry[K, V]) {
+	if l.root.next == e {
+		return
+	}
+	l.remove(e)
+	l.pushFront(e)
+}
+
+// back возвращает хвост или nil для пустого списка
+func (l *list[K, V]) back() *entry[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
\ No newline at end of file
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// Group схлопывает одновременные вызовы с одним ключом в один: пока
+// первый вызов выполняется, остальные ждут и получают его результат.
+// Упрощенный аналог golang.org/x/sync/singleflight, только на дженериках.
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+	dups  int
+}
+
+// Do выполняет fn для ключа; shared == true, если результат получили
+// несколько вызывающих
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err, true
+	}
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+	
+	c.value, c.err = fn()
+	
+	g.mu.Lock()
+	delete(g.calls, key)
+	shared = c.dups > 0
+	g.mu.Unlock()
+	c.wg.Done()
+	return c.value, c.err, shared
+}
+
+// LoadFunc загружает значение из источника (БД, внешний API)
+type LoadFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Loading кэш со сквозной загрузкой: промах вызывает load, причем
+// одновременные промахи по одному ключу дают одну загрузку, а не
+// лавину запросов в БД (cache stampede). Ошибки не кэшируются.
+type Loading[K comparable, V any] struct {
+	cache Cache[K, V]
+	load  LoadFunc[K, V]
+	group Group[K, V]
+}
+
+// NewLoading создает кэш со сквозной загрузкой поверх c
+func NewLoading[K comparable, V any](c Cache[K, V], load LoadFunc[K, V]) *Loading[K, V] {
+	return &Loading[K, V]{cache: NewSynchronized(c), load: load}
+}
+
+// Get возвращает значение из кэша или загружает его
+func (l *Loading[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if v, ok := l.cache.Get(key); ok {
+		return v, nil
+	}
+	
+	v, err, _ := l.group.Do(key, func() (V, error) {
+		// Контекст первого вызывающего: если его отменят, ошибку получат
+		// все ожидающие. Для учебного примера это приемлемо.
+		v, err := l.load(ctx, key)
+		if err == nil {
+			l.cache.Set(key, v)
+		}
+		return v, err
+	})
+	return v, err
+}
+
+// Invalidate удаляет ключ, например после обновления записи в БД
+func (l *Loading[K, V]) Invalidate(key K) {
+	l.cache.Delete(key)
+}
\ No newline at end of file
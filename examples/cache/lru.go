@@ -0,0 +1,96 @@
+package main
+
+// LRU вытесняет запись, к которой дольше всего не обращались.
+// map дает поиск за O(1), список хранит порядок использования:
+// голова - самая свежая запись, хвост - кандидат на вытеснение.
+type LRU[K comparable, V any] struct {
+	capacity int
+	items    map[K]*entry[K, V]
+	order    *list[K, V]
+	opts     options
+}
+
+// NewLRU создает LRU-кэш на capacity записей
+func NewLRU[K comparable, V any](capacity int, opts ...Option) *LRU[K, V] {
+	if capacity <= 0 {
+		panic("cache: capacity must be positive")
+	}
+	return &LRU[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*entry[K, V], capacity),
+		order:    newList[K, V](),
+		opts:     buildOptions(opts),
+	}
+}
+
+// Get возвращает значение и помечает запись как недавно использованную
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if e.expired(c.opts.now()) {
+		c.removeEntry(e)
+		var zero V
+		return zero, false
+	}
+	c.order.moveToFront(e)
+	return e.value, true
+}
+
+// Set добавляет или обновляет запись, при переполнении вытесняя хвост
+func (c *LRU[K, V]) Set(key K, value V) {
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		e.expires = c.opts.expiry()
+		c.order.moveToFront(e)
+		return
+	}
+	
+	if len(c.items) >= c.capacity {
+		c.evict()
+	}
+	e := &entry[K, V]{key: key, value: value, expires: c.opts.expiry()}
+	c.items[key] = e
+	c.order.pushFront(e)
+}
+
+// Delete удаляет запись
+func (c *LRU[K, V]) Delete(key K) bool {
+	e, ok := c.items[key]
+	if ok {
+		c.removeEntry(e)
+	}
+	return ok
+}
+
+// Len возвращает число записей, включая еще не удаленные просроченные
+func (c *LRU[K, V]) Len() int {
+	return len(c.items)
+}
+
+// Keys возвращает ключи от самого свежего к самому старому
+func (c *LRU[K, V]) Keys() []K {
+	keys := make([]K, 0, len(c.items))
+	for e := c.order.root.next; e != &c.order.root; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+func (c *LRU[K, V]) evict() {
+	e := c.order.back()
+	if e == nil {
+		return
+	}
+	c.removeEntry(e)
+	if c.opts.evict != nil && !e.expired(c.opts.now()) {
+		c.opts.evict(e.key)
+	}
+}
+
+func (c *LRU[K, V]) removeEntry(e *entry[K, V]) {
+	c.order.remove(e)
+	delete(c.items, e.key)
+}
\ No newline at end of file
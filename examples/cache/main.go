@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Пример 1: Вытеснение в LRU
+func lruEviction() {
+	fmt.Println("=== LRU ===")
+	
+	c := NewLRU[string, int](3, WithEvictCallback(func(key any) {
+		fmt.Println("  вытеснен:", key)
+	}))
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a") // a становится самым свежим
+	fmt.Println("Порядок:", c.Keys())
+	c.Set("d", 4) // Вытесняет b
+	fmt.Println("Порядок:", c.Keys())
+}
+
+// Пример 2: LRU против LFU при сканировании
+func lruVsLFU() {
+	fmt.Println("\n=== LRU vs LFU ===")
+	
+	// Горячие ключи читаются постоянно, затем разовый проход по 100
+	// холодным ключам (отчет, выгрузка) - классический scan
+	run := func(c Cache[int, int]) int {
+		hot := []int{1, 2, 3}
+		for range 10 {
+			for _, k := range hot {
+				if _, ok := c.Get(k); !ok {
+					c.Set(k, k)
+				}
+			}
+		}
+		for k := 100; k < 200; k++ {
+			c.Set(k, k)
+		}
+		survived := 0
+		for _, k := range hot {
+			if _, ok := c.Get(k); ok {
+				survived++
+			}
+		}
+		return survived
+	}
+	
+	fmt.Printf("LRU: горячих ключей после скана %d из 3\n", run(NewLRU[int, int](10)))
+	fmt.Printf("LFU: горячих ключей после скана %d из 3\n", run(NewLFU[int, int](10)))
+}
+
+// Пример 3: Срок жизни записей
+func ttl() {
+	fmt.Println("\n=== TTL ===")
+	
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU[string, string](10, WithTTL(time.Minute), WithClock(func() time.Time { return now }))
+	c.Set("session", "user-42")
+	
+	for _, d := range []time.Duration{30 * time.Second, 61 * time.Second} {
+		now = now.Add(d)
+		v, ok := c.Get("session")
+		fmt.Printf("+%v: %q найдено=%v\n", d, v, ok)
+	}
+}
+
+// Пример 4: Защита от лавины запросов
+func stampede() {
+	fmt.Println("\n=== Singleflight ===")
+	
+	var loads atomic.Int32
+	cache := NewLoading(NewLRU[int, string](100), func(ctx context.Context, id int) (string, error) {
+		loads.Add(1)
+		time.Sleep(50 * time.Millisecond) // Медленный запрос к БД
+		return "user-" + strconv.Itoa(id), nil
+	})
+	
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Get(context.Background(), 1)
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("100 одновременных промахов -> %d загрузка(и) из БД\n", loads.Load())
+}
+
+// User пользователь из "медленной" БД
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var errUserNotFound = errors.New("user not found")
+
+// userStore имитирует БД с задержкой
+type userStore struct {
+	queries atomic.Int32
+	users   map[int]User
+}
+
+func (s *userStore) FindByID(ctx context.Context, id int) (User, error) {
+	s.queries.Add(1)
+	select {
+	case <-time.After(20 * time.Millisecond):
+	case <-ctx.Done():
+		return User{}, ctx.Err()
+	}
+	u, ok := s.users[id]
+	if !ok {
+		return User{}, errUserNotFound
+	}
+	return u, nil
+}
+
+// userHandler GET /api/users/{id} с кэшированием
+func userHandler(users *Loading[int, User]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+		
+		user, err := users.Get(r.Context(), id)
+		if errors.Is(err, errUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(user)
+	}
+}
+
+// Пример 5: Кэш поиска пользователей в HTTP-обработчике
+func httpUserCache() {
+	fmt.Println("\n=== Кэш пользователей в HTTP ===")
+	
+	store := &userStore{users: map[int]User{
+		1: {1, "Иван Иванов"},
+		2: {2, "Мария Петрова"},
+	}}
+	users := NewLoading(NewLRU[int, User](1000, WithTTL(time.Minute)), store.FindByID)
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/users/{id}", userHandler(users))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	
+	for _, path := range []string{"/api/users/1", "/api/users/1", "/api/users/2", "/api/users/1", "/api/users/9"} {
+		start := time.Now()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("%-14s %d %-8v %s", path, resp.StatusCode, time.Since(start).Round(time.Millisecond), body)
+	}
+	fmt.Println("Запросов к БД:", store.queries.Load())
+}
+
+func main() {
+	lruEviction()
+	lruVsLFU()
+	ttl()
+	stampede()
+	httpUserCache()
+}
\ No newline at end of file
@@ -102,6 +102,10 @@ func selectExample() {
 }
 
 // Пример 5: Паттерн Worker Pool
+// Задача может завершиться ошибкой, поэтому в results идет Result[int]:
+// значение и ошибка в одном сообщении. С отдельным каналом ошибок
+// пришлось бы слушать оба и следить, чтобы ни один не остался
+// невычитанным, иначе воркер навсегда заблокируется на отправке.
 func workerPool() {
 	fmt.Println("\n=== Паттерн Worker Pool ===")
 	
@@ -110,7 +114,7 @@ func workerPool() {
 	
 	// Каналы для jobs и results
 	jobs := make(chan int, numJobs)
-	results := make(chan int, numJobs)
+	results := make(chan Result[int], numJobs)
 	
 	// Запускаем workers
 	var wg sync.WaitGroup
@@ -122,7 +126,7 @@ func workerPool() {
 				fmt.Printf("Worker %d обрабатывает job %d\n", workerID, job)
 				// Имитируем работу
 				time.Sleep(time.Second)
-				results <- job * 2 // Возвращаем результат
+				results <- process(job)
 			}
 		}(i)
 	}
@@ -141,8 +145,21 @@ func workerPool() {
 	
 	// Получаем results
 	for result := range results {
-		fmt.Println("Результат:", result)
+		v, err := result.Get()
+		if err != nil {
+			fmt.Println("Ошибка:", err)
+			continue
+		}
+		fmt.Println("Результат:", v)
+	}
+}
+
+// process обрабатывает задачу; каждая седьмая завершается ошибкой
+func process(job int) Result[int] {
+	if job%7 == 0 {
+		return Err[int](fmt.Errorf("job %d: не удалось обработать", job))
 	}
+	return Ok(job * 2)
 }
 
 func main() {
@@ -0,0 +1,24 @@
+package main
+
+// Result значение или ошибка одной задачи. Урезанная копия
+// examples/result/result.go: там же разобрано, когда такая обертка
+// уместна, а когда лучше обычная пара (T, error).
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok успешный результат
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err результат с ошибкой
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Get возвращает обычную пару (T, error)
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
\ No newline at end of file
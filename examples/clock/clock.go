@@ -0,0 +1,218 @@
+// Package clock источник времени для кода, который тестируют без настоящих
+// time.Sleep: в программе - NewRealClock, в тестах - FakeClock. Его
+// принимают ограничитель частоты, сессии, JWT и подписанные куки
+// examples/http-server; демонстрация - cmd/clock.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock источник времени. Код, который зависит от Clock, а не от пакета
+// time напрямую, можно тестировать без настоящих time.Sleep.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer аналог *time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker аналог *time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// ---------- Настоящие часы ----------
+
+type realClock struct{}
+
+// NewRealClock возвращает часы поверх пакета time
+func NewRealClock() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time        { return r.t.C }
+func (r realTimer) Stop() bool                 { return r.t.Stop() }
+func (r realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+// ---------- Управляемые часы для тестов ----------
+
+// FakeClock часы, время на которых двигается только вызовом Advance
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter ожидающий таймер, тикер или After
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration // > 0 для тикеров
+	ch     chan time.Time
+}
+
+// NewFakeClock создает часы, остановленные на момент start
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now возвращает текущее фейковое время
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since возвращает время, прошедшее с t по фейковым часам
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After срабатывает, когда Advance перешагнет now+d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.add(d, 0).ch
+}
+
+// Sleep блокирует горутину до соответствующего Advance
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// NewTimer создает фейковый таймер
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return &fakeTimer{clock: c, w: c.add(d, 0)}
+}
+
+// NewTicker создает фейковый тикер
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return &fakeTicker{clock: c, w: c.add(d, d)}
+}
+
+// Advance сдвигает время вперед и по порядку срабатывает все таймеры,
+// чей момент наступил. Тикер может сработать несколько раз, но как и
+// настоящий, не копит тики, если их никто не читает.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	end := c.now.Add(d)
+	for {
+		sort.Slice(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+		if len(c.waiters) == 0 || c.waiters[0].at.After(end) {
+			break
+		}
+		
+		w := c.waiters[0]
+		c.now = w.at
+		select {
+		case w.ch <- c.now:
+		default:
+		}
+		
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			c.waiters = c.waiters[1:]
+		}
+	}
+	c.now = end
+}
+
+// BlockUntil ждет, пока на часах не будет n ожидающих таймеров.
+// Нужен, чтобы тест не вызвал Advance раньше, чем горутина
+// под тестом успела подписаться на время.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) add(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	w := &fakeWaiter{at: c.now.Add(d), period: period, ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- c.now
+		if period == 0 {
+			return w
+		}
+	}
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+	return w
+}
+
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, x := range c.waiters {
+		if x == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	w     *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTimer) Stop() bool { return t.clock.remove(t.w) }
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	active := t.clock.remove(t.w)
+	t.clock.mu.Lock()
+	t.w.at = t.clock.now.Add(d)
+	t.clock.waiters = append(t.clock.waiters, t.w)
+	t.clock.cond.Broadcast()
+	t.clock.mu.Unlock()
+	return active
+}
+
+type fakeTicker struct {
+	clock *FakeClock
+	w     *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTicker) Stop() { t.clock.remove(t.w) }
\ No newline at end of file
@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClock_Timer(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	timer := clk.NewTimer(time.Second)
+	
+	clk.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("Timer fired too early")
+	default:
+	}
+	
+	clk.Advance(time.Millisecond)
+	select {
+	case now := <-timer.C():
+		if !now.Equal(epoch.Add(time.Second)) {
+			t.Errorf("Expected fire time %v, got %v", epoch.Add(time.Second), now)
+		}
+	default:
+		t.Fatal("Timer did not fire")
+	}
+	
+	if timer.Stop() {
+		t.Error("Stop on fired timer should return false")
+	}
+}
+
+func TestFakeClock_TickerDoesNotQueueTicks(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	ticker := clk.NewTicker(time.Second)
+	defer ticker.Stop()
+	
+	// Пять интервалов без чтения: в канале, как и у time.Ticker, один тик
+	clk.Advance(5 * time.Second)
+	
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("Ticker should drop ticks nobody reads")
+	default:
+	}
+	
+	if got := clk.Now(); !got.Equal(epoch.Add(5 * time.Second)) {
+		t.Errorf("Expected now %v, got %v", epoch.Add(5*time.Second), got)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"clock"
+)
+
+// Часы как зависимость: код получает clock.Clock вместо прямых вызовов
+// time.Now и time.After. В программе это NewRealClock, в тестах -
+// FakeClock, на котором час "проходит" мгновенно. Настоящие потребители -
+// ограничитель частоты (ratelimit.go), сессии (session.go), JWT (auth.go)
+// и подписанные куки (cookies.go) в examples/http-server.
+//
+// Запуск: go run ./cmd/clock
+
+// Пример 1: Настоящие часы
+func realClockExample() {
+	fmt.Println("=== Настоящие часы ===")
+	
+	clk := clock.NewRealClock()
+	start := clk.Now()
+	timer := clk.NewTimer(50 * time.Millisecond)
+	<-timer.C()
+	fmt.Println("Таймер сработал через", clk.Since(start).Round(10*time.Millisecond))
+}
+
+// Пример 2: Тикер на фейковых часах - сутки без ожидания
+func fakeTickerExample() {
+	fmt.Println("\n=== Тикер на фейковых часах ===")
+	
+	clk := clock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ticks := make(chan time.Time)
+	done := make(chan struct{})
+	defer close(done)
+	
+	go func() {
+		ticker := clk.NewTicker(8 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C():
+				ticks <- now
+			case <-done:
+				return
+			}
+		}
+	}()
+	
+	// Ждем, пока горутина создаст тикер: иначе Advance случится раньше
+	clk.BlockUntil(1)
+	for range 3 {
+		clk.Advance(8 * time.Hour)
+		fmt.Println("Тик в", (<-ticks).Format("02.01 15:04"))
+	}
+}
+
+// Пример 3: Истечение срока по фейковым часам
+func expiryExample() {
+	fmt.Println("\n=== Истечение срока ===")
+	
+	clk := clock.NewFakeClock(time.Now())
+	expires := clk.Now().Add(30 * time.Minute)
+	
+	clk.Advance(29 * time.Minute)
+	fmt.Println("Через 29 минут действует:", clk.Now().Before(expires))
+	
+	clk.Advance(time.Minute)
+	fmt.Println("Через 30 минут действует:", clk.Now().Before(expires))
+}
+
+func main() {
+	realClockExample()
+	fakeTickerExample()
+	expiryExample()
+	fmt.Println("\nТесты не спят: go test -v")
+}
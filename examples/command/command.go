@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Document текст документа. Позиции считаются в символах (rune), а не
+// в байтах: иначе вставка в середину кириллической буквы испортила бы
+// текст.
+type Document struct {
+	text []rune
+}
+
+// NewDocument создает документ с текстом s
+func NewDocument(s string) *Document {
+	return &Document{text: []rune(s)}
+}
+
+func (d *Document) String() string { return string(d.text) }
+
+// Len длина текста в символах
+func (d *Document) Len() int { return len(d.text) }
+
+// ErrOutOfRange позиция за пределами текста
+var ErrOutOfRange = errors.New("позиция за пределами текста")
+
+func (d *Document) insert(pos int, s string) error {
+	if pos < 0 || pos > len(d.text) {
+		return fmt.Errorf("%w: %d (длина %d)", ErrOutOfRange, pos, len(d.text))
+	}
+	d.text = append(d.text[:pos], append([]rune(s), d.text[pos:]...)...)
+	return nil
+}
+
+// remove удаляет n символов с позиции pos и возвращает удаленное
+func (d *Document) remove(pos, n int) (string, error) {
+	if pos < 0 || n < 0 || pos+n > len(d.text) {
+		return "", fmt.Errorf("%w: %d..%d (длина %d)", ErrOutOfRange, pos, pos+n, len(d.text))
+	}
+	removed := string(d.text[pos : pos+n])
+	d.text = append(d.text[:pos], d.text[pos+n:]...)
+	return removed, nil
+}
+
+// Command действие над документом, которое умеет себя отменить.
+//
+// Execute проверяет аргументы и ничего не меняет при ошибке: в историю
+// попадают только выполненные команды, и Undo никогда не отменяет то,
+// чего не было. Undo вызывается только после успешного Execute и ровно
+// над тем состоянием, которое Execute оставил, поэтому ошибок не
+// возвращает.
+type Command interface {
+	Execute(d *Document) error
+	Undo(d *Document)
+	String() string // Для истории: "вставка «мир» в 7"
+}
+
+// Insert вставляет текст в позицию
+type Insert struct {
+	Pos  int
+	Text string
+}
+
+func (c *Insert) Execute(d *Document) error { return d.insert(c.Pos, c.Text) }
+
+func (c *Insert) Undo(d *Document) { d.remove(c.Pos, len([]rune(c.Text))) }
+
+func (c *Insert) String() string { return fmt.Sprintf("вставка «%s» в %d", c.Text, c.Pos) }
+
+// Delete удаляет N символов с позиции Pos. Удаленный текст команда
+// запоминает при выполнении: без него отмена невозможна, а узнать его
+// заранее нельзя - к моменту Execute документ мог измениться.
+type Delete struct {
+	Pos, N  int
+	removed string
+}
+
+func (c *Delete) Execute(d *Document) error {
+	removed, err := d.remove(c.Pos, c.N)
+	if err != nil {
+		return err
+	}
+	c.removed = removed
+	return nil
+}
+
+func (c *Delete) Undo(d *Document) { d.insert(c.Pos, c.removed) }
+
+func (c *Delete) String() string {
+	if c.removed != "" {
+		return fmt.Sprintf("удаление «%s» с %d", c.removed, c.Pos)
+	}
+	return fmt.Sprintf("удаление %d симв. с %d", c.N, c.Pos)
+}
+
+// Macro составная команда: выполняется и отменяется как одна. Отмена
+// идет в обратном порядке - каждая команда отменяется над тем
+// состоянием, которое оставила.
+type Macro struct {
+	Name     string
+	Commands []Command
+}
+
+// Execute выполняет команды по порядку. Если одна упала, уже
+// выполненные отменяются: макрос либо выполнен целиком, либо не
+// выполнен вовсе.
+func (m *Macro) Execute(d *Document) error {
+	for i, c := range m.Commands {
+		if err := c.Execute(d); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				m.Commands[j].Undo(d)
+			}
+			return fmt.Errorf("%s: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Macro) Undo(d *Document) {
+	for i := len(m.Commands) - 1; i >= 0; i-- {
+		m.Commands[i].Undo(d)
+	}
+}
+
+func (m *Macro) String() string { return m.Name }
+
+// ReplaceAll заменяет все вхождения old на new. Вхождения ищутся при
+// выполнении, а не при создании команды: замена строится из Delete и
+// Insert по текущему тексту и отменяется одним шагом.
+type ReplaceAll struct {
+	Old, New string
+	macro    Macro
+}
+
+func (c *ReplaceAll) Execute(d *Document) error {
+	if c.Old == "" {
+		return errors.New("пустая строка для замены")
+	}
+	text, old := d.String(), []rune(c.Old)
+	var positions []int
+	for i, offset := 0, 0; ; {
+		j := strings.Index(text[i:], c.Old)
+		if j < 0 {
+			break
+		}
+		offset += len([]rune(text[i : i+j]))
+		positions = append(positions, offset)
+		offset += len(old)
+		i += j + len(c.Old)
+	}
+	if len(positions) == 0 {
+		return fmt.Errorf("«%s» не найдено", c.Old)
+	}
+	
+	// С конца, чтобы замена не сдвигала позиции еще не замененных
+	c.macro = Macro{Name: c.String()}
+	for _, pos := range positions {
+		c.macro.Commands = append([]Command{
+			&Delete{Pos: pos, N: len(old)},
+			&Insert{Pos: pos, Text: c.New},
+		}, c.macro.Commands...)
+	}
+	return c.macro.Execute(d)
+}
+
+func (c *ReplaceAll) Undo(d *Document) { c.macro.Undo(d) }
+
+func (c *ReplaceAll) String() string {
+	return fmt.Sprintf("замена «%s» на «%s»", c.Old, c.New)
+}
+
+// History выполняет команды и хранит два стека: выполненные (для Undo)
+// и отмененные (для Redo). Новая команда очищает стек Redo: отмененные
+// команды рассчитаны на документ, которого больше нет.
+type History struct {
+	done   []Command
+	undone []Command
+	limit  int
+}
+
+// NewHistory создает историю глубиной до limit шагов; 0 - без ограничения.
+// Ограничение нужно, потому что команды держат удаленный текст: история
+// бесконечного сеанса правки иначе растет без конца.
+func NewHistory(limit int) *History {
+	return &History{limit: limit}
+}
+
+// Do выполняет команду и записывает ее в историю
+func (h *History) Do(d *Document, c Command) error {
+	if err := c.Execute(d); err != nil {
+		return err
+	}
+	h.done = append(h.done, c)
+	if h.limit > 0 && len(h.done) > h.limit {
+		// Самый старый шаг больше не отменить
+		h.done[0] = nil
+		h.done = h.done[1:]
+	}
+	clear(h.undone)
+	h.undone = h.undone[:0]
+	return nil
+}
+
+// Undo отменяет последнюю выполненную команду
+func (h *History) Undo(d *Document) (Command, bool) {
+	if len(h.done) == 0 {
+		return nil, false
+	}
+	c := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	c.Undo(d)
+	h.undone = append(h.undone, c)
+	return c, true
+}
+
+// Redo повторяет последнюю отмененную команду. Execute вызывается
+// заново: для Delete и ReplaceAll это пересчитывает удаленный текст,
+// а документ в точности тот, над которым команда выполнялась впервые.
+func (h *History) Redo(d *Document) (Command, bool, error) {
+	if len(h.undone) == 0 {
+		return nil, false, nil
+	}
+	c := h.undone[len(h.undone)-1]
+	if err := c.Execute(d); err != nil {
+		return c, true, err
+	}
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, c)
+	return c, true, nil
+}
+
+// Done выполненные команды, от старых к новым
+func (h *History) Done() []Command { return h.done }
+
+// Undone отмененные команды, от последней отмененной к первой
+func (h *History) Undone() []Command {
+	out := make([]Command, 0, len(h.undone))
+	for i := len(h.undone) - 1; i >= 0; i-- {
+		out = append(out, h.undone[i])
+	}
+	return out
+}
\ No newline at end of file
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		initial  string
+		cmd      Command
+		expected string
+		wantErr  bool
+	}{
+		{"вставка в начало", "мир", &Insert{Pos: 0, Text: "Привет, "}, "Привет, мир", false},
+		{"вставка в конец", "Привет", &Insert{Pos: 6, Text: "!"}, "Привет!", false},
+		{"вставка за концом", "Привет", &Insert{Pos: 7, Text: "!"}, "Привет", true},
+		{"удаление", "Привет, мир", &Delete{Pos: 6, N: 5}, "Привет", false},
+		{"удаление за концом", "Привет", &Delete{Pos: 4, N: 5}, "Привет", true},
+		{"замена всех", "кот и кот", &ReplaceAll{Old: "кот", New: "пес"}, "пес и пес", false},
+		{"замена длиннее", "а-а", &ReplaceAll{Old: "а", New: "ооо"}, "ооо-ооо", false},
+		{"замена на пустое", "а-б-в", &ReplaceAll{Old: "-", New: ""}, "абв", false},
+		{"замена не найдена", "кот", &ReplaceAll{Old: "пес", New: "кот"}, "кот", true},
+		{"макрос откатывается", "текст", &Macro{Name: "m", Commands: []Command{
+			&Insert{Pos: 0, Text: "новый "}, &Delete{Pos: 0, N: 100},
+		}}, "текст", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := NewDocument(tt.initial)
+			err := tt.cmd.Execute(doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if doc.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, doc)
+			}
+			if err != nil {
+				return
+			}
+			tt.cmd.Undo(doc)
+			if doc.String() != tt.initial {
+				t.Errorf("Expected %q after undo, got %q", tt.initial, doc)
+			}
+		})
+	}
+}
+
+func TestInsert_OutOfRange(t *testing.T) {
+	err := (&Insert{Pos: -1, Text: "x"}).Execute(NewDocument("abc"))
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestHistory_UndoRedo(t *testing.T) {
+	doc := NewDocument("")
+	h := NewHistory(0)
+	for _, s := range []string{"a", "b", "c"} {
+		if err := h.Do(doc, &Insert{Pos: doc.Len(), Text: s}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	
+	steps := []struct {
+		op       string
+		ok       bool
+		expected string
+	}{
+		{"undo", true, "ab"},
+		{"undo", true, "a"},
+		{"redo", true, "ab"},
+		{"undo", true, "a"},
+		{"undo", true, ""},
+		{"undo", false, ""},
+		{"redo", true, "a"},
+		{"redo", true, "ab"},
+		{"redo", true, "abc"},
+		{"redo", false, "abc"},
+	}
+	for i, s := range steps {
+		var ok bool
+		if s.op == "undo" {
+			_, ok = h.Undo(doc)
+		} else {
+			var err error
+			if _, ok, err = h.Redo(doc); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if ok != s.ok || doc.String() != s.expected {
+			t.Errorf("Step %d %s: expected %v %q, got %v %q", i, s.op, s.ok, s.expected, ok, doc)
+		}
+	}
+}
+
+func TestHistory_NewCommandClearsRedo(t *testing.T) {
+	doc := NewDocument("abc")
+	h := NewHistory(0)
+	h.Do(doc, &Delete{Pos: 0, N: 1})
+	h.Undo(doc)
+	h.Do(doc, &Insert{Pos: 3, Text: "d"})
+	if _, ok, _ := h.Redo(doc); ok {
+		t.Error("Expected redo to be cleared by a new command")
+	}
+	if doc.String() != "abcd" {
+		t.Errorf("Expected abcd, got %q", doc)
+	}
+}
+
+func TestHistory_FailedCommandNotRecorded(t *testing.T) {
+	doc := NewDocument("abc")
+	h := NewHistory(0)
+	h.Do(doc, &Insert{Pos: 0, Text: "x"})
+	h.Undo(doc)
+	if err := h.Do(doc, &Delete{Pos: 10, N: 1}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if len(h.Done()) != 0 {
+		t.Errorf("Expected empty history, got %v", h.Done())
+	}
+	// Неудачная команда не сбрасывает redo: документ не изменился
+	if _, ok, _ := h.Redo(doc); !ok || doc.String() != "xabc" {
+		t.Errorf("Expected redo to xabc, got %v %q", ok, doc)
+	}
+}
+
+func TestHistory_Limit(t *testing.T) {
+	doc := NewDocument("")
+	h := NewHistory(2)
+	for _, s := range []string{"a", "b", "c"} {
+		h.Do(doc, &Insert{Pos: doc.Len(), Text: s})
+	}
+	for {
+		if _, ok := h.Undo(doc); !ok {
+			break
+		}
+	}
+	// Первый шаг вытеснен и не отменяется
+	if doc.String() != "a" {
+		t.Errorf("Expected a, got %q", doc)
+	}
+}
+
+// Случайная последовательность правок, отмененная целиком, возвращает
+// исходный текст, а повторенная целиком - итоговый
+func TestHistory_UndoAllRedoAll(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	words := []string{"кот", "пес", " ", "и", "ёж"}
+	for run := range 50 {
+		initial := "кот и пес"
+		doc := NewDocument(initial)
+		h := NewHistory(0)
+		for range 30 {
+			var c Command
+			switch r.IntN(3) {
+			case 0:
+				c = &Insert{Pos: r.IntN(doc.Len() + 1), Text: words[r.IntN(len(words))]}
+			case 1:
+				pos := r.IntN(doc.Len() + 1)
+				c = &Delete{Pos: pos, N: r.IntN(doc.Len() - pos + 1)}
+			default:
+				c = &ReplaceAll{Old: words[r.IntN(len(words))], New: words[r.IntN(len(words))]}
+			}
+			h.Do(doc, c) // Ненайденная замена не выполняется и не записывается
+		}
+		final := doc.String()
+		
+		for {
+			if _, ok := h.Undo(doc); !ok {
+				break
+			}
+		}
+		if doc.String() != initial {
+			t.Fatalf("Run %d: expected %q after undo all, got %q", run, initial, doc)
+		}
+		for {
+			_, ok, err := h.Redo(doc)
+			if err != nil {
+				t.Fatalf("Run %d: unexpected error: %v", run, err)
+			}
+			if !ok {
+				break
+			}
+		}
+		if doc.String() != final {
+			t.Fatalf("Run %d: expected %q after redo all, got %q", run, final, doc)
+		}
+	}
+}
+
+func TestEditor_Run(t *testing.T) {
+	input := `append , мир
+insert 0 >>
+replace мир Go
+
+undo
+bogus
+delete 0 100
+history
+quit
+append не выполнится`
+	var out strings.Builder
+	doc := NewDocument("Привет")
+	e := NewEditor(doc, NewHistory(0), &out)
+	if err := e.Run(strings.NewReader(input), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc.String() != ">>Привет, мир" {
+		t.Errorf("Expected >>Привет, мир, got %q", doc)
+	}
+	for _, s := range []string{
+		"Отменено: замена «мир» на «Go»",
+		`неизвестная команда "bogus"`,
+		"позиция за пределами текста",
+		"2. вставка «>>» в 0",
+		"(отменено) замена «мир» на «Go»",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected output to contain %q, got:\n%s", s, out.String())
+		}
+	}
+}
+
+func TestEditor_ParseErrors(t *testing.T) {
+	tests := []string{"insert x текст", "insert 1", "append", "delete 1", "delete a b", "replace кот", "undo", "redo"}
+	for _, line := range tests {
+		t.Run(line, func(t *testing.T) {
+			e := NewEditor(NewDocument("кот"), NewHistory(0), &strings.Builder{})
+			if err := e.Exec(line); err == nil {
+				t.Errorf("Expected error for %q", line)
+			}
+		})
+	}
+}
\ No newline at end of file
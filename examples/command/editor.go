@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const editorHelp = `Команды:
+  show                 показать текст
+  insert <поз> <текст> вставить текст в позицию (с 0)
+  append <текст>       дописать текст в конец
+  delete <поз> <n>     удалить n символов с позиции
+  replace <что> <чем>  заменить все вхождения слова
+  undo, redo           отменить или повторить
+  history              история команд
+  help, quit`
+
+// Editor построчный редактор поверх History. Разбор ввода создает
+// команду и только: что она делает и как отменяется, редактор не знает.
+type Editor struct {
+	doc     *Document
+	history *History
+	out     io.Writer
+}
+
+// NewEditor создает редактор документа doc с выводом в out
+func NewEditor(doc *Document, history *History, out io.Writer) *Editor {
+	return &Editor{doc: doc, history: history, out: out}
+}
+
+// Run читает команды из in до quit или конца ввода. prompt выводит
+// приглашение перед каждой командой - для работы в терминале.
+func (e *Editor) Run(in io.Reader, prompt bool) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		if prompt {
+			fmt.Fprint(e.out, "> ")
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		if err := e.Exec(line); err != nil {
+			fmt.Fprintln(e.out, "Ошибка:", err)
+		}
+	}
+}
+
+// Exec выполняет одну строку ввода
+func (e *Editor) Exec(line string) error {
+	// Аргументы не обрезаются: "append  мир" дописывает " мир"
+	name, args, _ := strings.Cut(line, " ")
+	
+	switch name {
+	case "show":
+		e.show()
+		return nil
+	case "help":
+		fmt.Fprintln(e.out, editorHelp)
+		return nil
+	case "history":
+		e.printHistory()
+		return nil
+	case "undo":
+		c, ok := e.history.Undo(e.doc)
+		if !ok {
+			return errors.New("нечего отменять")
+		}
+		fmt.Fprintln(e.out, "Отменено:", c)
+		e.show()
+		return nil
+	case "redo":
+		c, ok, err := e.history.Redo(e.doc)
+		if !ok {
+			return errors.New("нечего повторять")
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.out, "Повторено:", c)
+		e.show()
+		return nil
+	}
+	
+	c, err := e.parse(name, args)
+	if err != nil {
+		return err
+	}
+	if err := e.history.Do(e.doc, c); err != nil {
+		return err
+	}
+	e.show()
+	return nil
+}
+
+// parse создает команду по имени и аргументам
+func (e *Editor) parse(name, args string) (Command, error) {
+	switch name {
+	case "insert":
+		pos, text, ok := strings.Cut(args, " ")
+		if !ok {
+			return nil, errors.New("insert <поз> <текст>")
+		}
+		n, err := strconv.Atoi(pos)
+		if err != nil {
+			return nil, fmt.Errorf("позиция должна быть числом: %q", pos)
+		}
+		return &Insert{Pos: n, Text: text}, nil
+	case "append":
+		if args == "" {
+			return nil, errors.New("append <текст>")
+		}
+		// Позиция фиксируется сейчас: Redo вставит туда же, даже если
+		// конец текста к тому времени сдвинется
+		return &Insert{Pos: e.doc.Len(), Text: args}, nil
+	case "delete":
+		fields := strings.Fields(args)
+		if len(fields) != 2 {
+			return nil, errors.New("delete <поз> <n>")
+		}
+		pos, err1 := strconv.Atoi(fields[0])
+		n, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("позиция и длина должны быть числами")
+		}
+		return &Delete{Pos: pos, N: n}, nil
+	case "replace":
+		old, repl, ok := strings.Cut(args, " ")
+		if !ok {
+			return nil, errors.New("replace <что> <чем>")
+		}
+		return &ReplaceAll{Old: old, New: repl}, nil
+	default:
+		return nil, fmt.Errorf("неизвестная команда %q, список - help", name)
+	}
+}
+
+func (e *Editor) show() {
+	fmt.Fprintf(e.out, "  «%s»\n", e.doc)
+}
+
+func (e *Editor) printHistory() {
+	done, undone := e.history.Done(), e.history.Undone()
+	if len(done) == 0 && len(undone) == 0 {
+		fmt.Fprintln(e.out, "  история пуста")
+		return
+	}
+	for i, c := range done {
+		fmt.Fprintf(e.out, "  %d. %s\n", i+1, c)
+	}
+	for _, c := range undone {
+		fmt.Fprintf(e.out, "     (отменено) %s\n", c)
+	}
+}
\ No newline at end of file
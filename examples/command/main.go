@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Паттерн "Команда": действие - это объект с Execute и Undo, а не вызов
+// метода. Раз действие - значение, его можно положить в стек и отменить,
+// повторить, собрать несколько в одно (Macro) или показать в истории.
+//
+//   command.go - документ, команды Insert, Delete, ReplaceAll, Macro и
+//                история с двумя стеками
+//   editor.go  - построчный редактор: разбирает ввод в команды
+//
+// Два способа отмены:
+//   - обратная операция (здесь): команда помнит ровно то, что нужно для
+//     отката - Delete хранит удаленный текст, Insert - только позицию;
+//   - снимок (memento): перед каждой командой сохраняется весь документ.
+//     Проще и не ошибается, но на большом документе история из снимков
+//     занимает в сотни раз больше памяти.
+//
+// Запуск демонстрации: go run .
+// Редактор: go run . -edit "Привет, мир"
+
+// Пример 1: Отмена и повтор
+func undoRedo() {
+	fmt.Println("=== Отмена и повтор ===")
+	
+	doc := NewDocument("Привет")
+	h := NewHistory(0)
+	for _, c := range []Command{
+		&Insert{Pos: 6, Text: ", мир"},
+		&Insert{Pos: 11, Text: "!"},
+		&Delete{Pos: 0, N: 8},
+	} {
+		if err := h.Do(doc, c); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%-24s → «%s»\n", c, doc)
+	}
+	
+	for range 2 {
+		c, _ := h.Undo(doc)
+		fmt.Printf("undo %-19s → «%s»\n", c, doc)
+	}
+	c, _, _ := h.Redo(doc)
+	fmt.Printf("redo %-19s → «%s»\n", c, doc)
+	
+	// Новая команда после отмены: отмененный Delete больше не повторить
+	h.Do(doc, &Insert{Pos: 0, Text: "Ну, "})
+	_, ok, _ := h.Redo(doc)
+	fmt.Printf("После новой команды redo доступен: %v, текст «%s»\n", ok, doc)
+}
+
+// Пример 2: Составные команды
+func macros() {
+	fmt.Println("\n=== Составные команды ===")
+	
+	doc := NewDocument("кот и кот, и еще кот")
+	h := NewHistory(0)
+	if err := h.Do(doc, &ReplaceAll{Old: "кот", New: "пес"}); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Замена: «%s» (%d шаг в истории)\n", doc, len(h.Done()))
+	h.Undo(doc)
+	fmt.Printf("Одна отмена: «%s»\n", doc)
+	
+	// Второй шаг макроса падает: первый откатывается, документ не
+	// остается наполовину измененным
+	m := &Macro{Name: "вставка и удаление", Commands: []Command{
+		&Insert{Pos: 0, Text: ">> "},
+		&Delete{Pos: 100, N: 1},
+	}}
+	err := h.Do(doc, m)
+	fmt.Printf("Ошибка макроса: %v\nТекст без изменений: «%s», в истории %d\n", err, doc, len(h.Done()))
+}
+
+// Пример 3: Редактор по сценарию - то же, что ввод в -edit
+func scripted() {
+	fmt.Println("\n=== Сеанс редактора ===")
+	
+	script := `append , мир
+insert 0 Эй!
+replace мир Go
+delete 0 3
+undo
+undo
+redo
+history`
+	e := NewEditor(NewDocument("Привет"), NewHistory(0), os.Stdout)
+	for _, line := range strings.Split(script, "\n") {
+		fmt.Println(">", line)
+		if err := e.Exec(line); err != nil {
+			fmt.Println("Ошибка:", err)
+		}
+	}
+}
+
+func main() {
+	edit := flag.String("edit", "", "Запустить редактор с этим текстом")
+	depth := flag.Int("depth", 100, "Глубина истории отмены")
+	flag.Parse()
+	
+	if isFlagSet("edit") {
+		fmt.Println(editorHelp)
+		e := NewEditor(NewDocument(*edit), NewHistory(*depth), os.Stdout)
+		e.show()
+		if err := e.Run(os.Stdin, true); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	
+	undoRedo()
+	macros()
+	scripted()
+}
+
+// isFlagSet передан ли флаг: -edit "" - редактор с пустым документом
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
\ No newline at end of file
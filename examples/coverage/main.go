@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mergeProfiles объединяет несколько файлов -coverprofile в один.
+// Одинаковые блоки складываются (mode: count/atomic) или объединяются
+// через OR (mode: set), поэтому покрытие нескольких прогонов не теряется.
+func mergeProfiles(out string, inputs []string) error {
+	mode := ""
+	blocks := make(map[string]int)
+	
+	for _, path := range inputs {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if m, ok := strings.CutPrefix(line, "mode: "); ok {
+				if mode != "" && mode != m {
+					f.Close()
+					return fmt.Errorf("%s: режим %q не совпадает с %q", path, m, mode)
+				}
+				mode = m
+				continue
+			}
+			
+			if line == "" {
+				continue
+			}
+			
+			// Формат: file.go:10.2,12.16 3 1 (блок, число операторов, счетчик)
+			i := strings.LastIndexByte(line, ' ')
+			if i < 0 {
+				f.Close()
+				return fmt.Errorf("%s: неверная строка профиля %q", path, line)
+			}
+			count, err := strconv.Atoi(line[i+1:])
+			if err != nil || count < 0 {
+				f.Close()
+				return fmt.Errorf("%s: неверный счетчик в строке %q", path, line)
+			}
+			
+			key := line[:i]
+			if mode == "set" {
+				if count > 0 {
+					blocks[key] = 1
+				} else if _, ok := blocks[key]; !ok {
+					blocks[key] = 0
+				}
+			} else {
+				blocks[key] += count
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	
+	keys := make([]string, 0, len(blocks))
+	for k := range blocks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	
+	var b strings.Builder
+	fmt.Fprintf(&b, "mode: %s\n", mode)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s %d\n", k, blocks[k])
+	}
+	return os.WriteFile(out, []byte(b.String()), 0o644)
+}
+
+// Пример 1: Разбор настроек, покрытый тестами
+func parserExample() {
+	fmt.Println("=== Парсер настроек ===")
+	
+	cfg, err := ParseConfig("port = 8080\ndebug = true\nname = \"users api\"")
+	fmt.Printf("Результат: %v, ошибка: %v\n", cfg, err)
+	
+	_, err = ParseConfig("port = 8080\nport = 9090")
+	fmt.Println("Ошибка:", err)
+}
+
+// Пример 2: Инструменты покрытия
+func printCoverageGuide() {
+	fmt.Println("\n=== Инструменты покрытия ===")
+	fmt.Println(`Процент покрытия:
+    go test -cover
+
+Профиль и покрытие по функциям:
+    go test -coverprofile=cover.out
+    go tool cover -func=cover.out
+
+HTML-отчет: красные строки подсказывают, каких тестов не хватает
+(так появились тесты в TestParseConfig_Errors):
+    go tool cover -html=cover.out
+
+Сколько раз выполнялась каждая строка (а не только "да/нет"):
+    go test -covermode=count -coverprofile=cover.out
+
+Покрытие нескольких пакетов или отдельных каталогов examples/*:
+    (cd examples/coverage && go test -coverprofile=/tmp/a.out)
+    (cd examples/fuzzing && go test -coverprofile=/tmp/b.out)
+    go run . merge /tmp/all.out /tmp/a.out /tmp/b.out
+    go tool cover -func=/tmp/all.out
+
+Покрытие интеграционных прогонов бинарника (Go 1.20+):
+    go build -cover -o app . && GOCOVERDIR=./covdata ./app
+    go tool covdata percent -i=./covdata
+    go tool covdata merge -i=./covdata,./other -o=./merged
+
+100% покрытия не гарантирует правильность: строка может выполняться,
+а ее результат - не проверяться (см. TestParseConfig_Values).`)
+}
+
+func main() {
+	if len(os.Args) > 3 && os.Args[1] == "merge" {
+		if err := mergeProfiles(os.Args[2], os.Args[3:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка объединения:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Профили объединены в", os.Args[2])
+		return
+	}
+	
+	parserExample()
+	printCoverageGuide()
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   []string
+		expected string
+		wantErr  string
+	}{
+		{
+			name: "set: OR по блокам",
+			inputs: []string{
+				"mode: set\na.go:1.1,2.2 1 1\na.go:3.1,4.2 2 0\n",
+				"mode: set\na.go:1.1,2.2 1 0\na.go:3.1,4.2 2 1\nb.go:1.1,2.2 1 0\n",
+			},
+			expected: "mode: set\na.go:1.1,2.2 1 1\na.go:3.1,4.2 2 1\nb.go:1.1,2.2 1 0\n",
+		},
+		{
+			name: "count: сумма счетчиков",
+			inputs: []string{
+				"mode: count\na.go:1.1,2.2 1 3\na.go:3.1,4.2 2 0\n",
+				"mode: count\na.go:1.1,2.2 1 4\na.go:3.1,4.2 2 5\n",
+			},
+			expected: "mode: count\na.go:1.1,2.2 1 7\na.go:3.1,4.2 2 5\n",
+		},
+		{
+			name: "разные режимы",
+			inputs: []string{
+				"mode: set\na.go:1.1,2.2 1 1\n",
+				"mode: atomic\na.go:1.1,2.2 1 2\n",
+			},
+			wantErr: "не совпадает",
+		},
+		{
+			name:    "счетчик не число",
+			inputs:  []string{"mode: count\na.go:1.1,2.2 1 x\n"},
+			wantErr: "неверный счетчик",
+		},
+		{
+			name:    "отрицательный счетчик",
+			inputs:  []string{"mode: count\na.go:1.1,2.2 1 -1\n"},
+			wantErr: "неверный счетчик",
+		},
+		{
+			name:    "строка без счетчика",
+			inputs:  []string{"mode: count\na.go:1.1,2.2\n"},
+			wantErr: "неверная строка",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var paths []string
+			for i, content := range tt.inputs {
+				path := filepath.Join(dir, "in"+string(rune('a'+i))+".out")
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				paths = append(paths, path)
+			}
+			out := filepath.Join(dir, "merged.out")
+			
+			err := mergeProfiles(out, paths)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got, _ := os.ReadFile(out)
+			if string(got) != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeProfiles_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := mergeProfiles(filepath.Join(dir, "out"), []string{filepath.Join(dir, "missing.out")}); err == nil {
+		t.Error("Expected error for missing input")
+	}
+}
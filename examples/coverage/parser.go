@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Config результат разбора файла настроек вида key = value
+type Config map[string]any
+
+// ParseError ошибка разбора с номером строки
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("строка %d: %s", e.Line, e.Msg)
+}
+
+// ParseConfig разбирает настройки:
+//
+//	# комментарий
+//	port = 8080
+//	debug = true
+//	name = "users api"
+func ParseConfig(input string) (Config, error) {
+	cfg := Config{}
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	line := 0
+	
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		
+		key, raw, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, &ParseError{Line: line, Msg: "ожидается key = value"}
+		}
+		
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, &ParseError{Line: line, Msg: "пустой ключ"}
+		}
+		if _, exists := cfg[key]; exists {
+			return nil, &ParseError{Line: line, Msg: fmt.Sprintf("повторный ключ %q", key)}
+		}
+		
+		value, err := parseValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, &ParseError{Line: line, Msg: err.Error()}
+		}
+		cfg[key] = value
+	}
+	
+	return cfg, scanner.Err()
+}
+
+// parseValue определяет тип значения: строка в кавычках, bool или int
+func parseValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, fmt.Errorf("пустое значение")
+	case strings.HasPrefix(raw, `"`):
+		s, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("неверная строка %s", raw)
+		}
+		return s, nil
+	case raw == "true" || raw == "false":
+		return raw == "true", nil
+	}
+	
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("неизвестное значение %q (строки берутся в кавычки)", raw)
+	}
+	return n, nil
+}
\ No newline at end of file
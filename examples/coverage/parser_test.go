@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Пример 1: Исходный тест - только "счастливый путь".
+// go tool cover -func покажет, что ветки ошибок в parser.go не выполнялись.
+func TestParseConfig_HappyPath(t *testing.T) {
+	input := `
+# настройки сервера
+port = 8080
+debug = true
+name = "users api"
+`
+	cfg, err := ParseConfig(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if cfg["port"] != 8080 {
+		t.Errorf("Expected port 8080, got %v", cfg["port"])
+	}
+	if cfg["debug"] != true {
+		t.Errorf("Expected debug true, got %v", cfg["debug"])
+	}
+	if cfg["name"] != "users api" {
+		t.Errorf("Expected name 'users api', got %v", cfg["name"])
+	}
+}
+
+// Пример 2: Тесты, добавленные по отчету go tool cover -html.
+// Каждый случай закрывает красную (невыполненную) ветку parser.go.
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{"missing equals", "port 8080", 1},
+		{"empty key", " = 1", 1},
+		{"duplicate key", "a = 1\na = 2", 2},
+		{"empty value", "a =", 1},
+		{"bad quoted string", `a = "unterminated`, 1},
+		{"unquoted string", "a = hello", 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig(tt.input)
+			
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Expected ParseError, got %v", err)
+			}
+			if perr.Line != tt.line {
+				t.Errorf("Expected error on line %d, got %d", tt.line, perr.Line)
+			}
+		})
+	}
+}
+
+// Пример 3: Граничные случаи, которые покрытие не подсказывает:
+// строка уже выполнена, но значение false не проверялось
+func TestParseConfig_Values(t *testing.T) {
+	cfg, err := ParseConfig("off = false\nneg = -1\nempty = \"\"")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if cfg["off"] != false {
+		t.Errorf("Expected false, got %v", cfg["off"])
+	}
+	if cfg["neg"] != -1 {
+		t.Errorf("Expected -1, got %v", cfg["neg"])
+	}
+	if cfg["empty"] != "" {
+		t.Errorf("Expected empty string, got %v", cfg["empty"])
+	}
+}
+
+func TestParseError_Message(t *testing.T) {
+	err := &ParseError{Line: 3, Msg: "пустой ключ"}
+	if err.Error() != "строка 3: пустой ключ" {
+		t.Errorf("Unexpected message: %s", err.Error())
+	}
+}
\ No newline at end of file
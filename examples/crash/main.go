@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"syscall"
+	"time"
+)
+
+// Режимы запуска:
+// go run . -mode=main      - паника в main, отчет пишет отложенный обработчик
+// go run . -mode=goroutine - паника в горутине, отчет пишет обертка safeGo
+// go run . -mode=unhandled - паника без recover, вывод пишет SetCrashOutput
+// go run . -mode=serve     - сервер с /debug/goroutines; kill -QUIT <pid> пишет дамп
+
+var crashDir = filepath.Join(os.TempDir(), "crash-reports")
+
+// goroutineDump возвращает стеки всех горутин.
+// Буфер увеличивается, пока дамп не поместится целиком.
+func goroutineDump() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// writeBuildInfo выводит версию Go, модуль и VCS-метаданные сборки
+func writeBuildInfo(w io.Writer) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		fmt.Fprintln(w, "build info недоступна")
+		return
+	}
+	fmt.Fprintf(w, "go: %s\nmodule: %s %s\n", info.GoVersion, info.Main.Path, info.Main.Version)
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified", "GOOS", "GOARCH", "-race":
+			fmt.Fprintf(w, "%s: %s\n", s.Key, s.Value)
+		}
+	}
+}
+
+// writeCrashReport сохраняет отчет о падении в файл и возвращает путь к нему
+func writeCrashReport(reason any, stack []byte) (string, error) {
+	if err := os.MkdirAll(crashDir, 0o755); err != nil {
+		return "", err
+	}
+	
+	name := filepath.Join(crashDir, fmt.Sprintf("crash-%s-%d.txt", time.Now().Format("20060102-150405"), os.Getpid()))
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	
+	fmt.Fprintf(f, "=== Причина ===\n%v\n\n", reason)
+	fmt.Fprintf(f, "=== Время ===\n%s\n\n", time.Now().Format(time.RFC3339Nano))
+	fmt.Fprintf(f, "=== Сборка ===\n")
+	writeBuildInfo(f)
+	if stack != nil {
+		fmt.Fprintf(f, "\n=== Стек паникующей горутины ===\n%s", stack)
+	}
+	fmt.Fprintln(f)
+	fmt.Fprintf(f, "=== Все горутины (%d) ===\n%s", runtime.NumGoroutine(), goroutineDump())
+	
+	// Sync - данные должны оказаться на диске до выхода процесса
+	return name, f.Sync()
+}
+
+// handlePanic - обработчик верхнего уровня, вызывается через defer в main.
+// recover работает только в той же горутине, где произошла паника.
+func handlePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	
+	name, err := writeCrashReport(r, debug.Stack())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "паника: %v (отчет не записан: %v)\n", r, err)
+	} else {
+		fmt.Fprintf(os.Stderr, "паника: %v\nотчет: %s\n", r, name)
+	}
+	
+	// Код 2 - как у необработанной паники в Go.
+	// Продолжать работу после неизвестной паники опасно: состояние могло испортиться.
+	os.Exit(2)
+}
+
+// safeGo запускает горутину с тем же обработчиком:
+// паника в горутине без recover роняет весь процесс, минуя defer в main
+func safeGo(fn func()) {
+	go func() {
+		defer handlePanic()
+		fn()
+	}()
+}
+
+// Пример 1: /debug/goroutines - дамп горутин по HTTP
+func goroutinesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	
+	// debug=1 - сгруппированные одинаковые стеки, debug=2 - полный дамп как при панике
+	if r.URL.Query().Get("full") == "1" {
+		w.Write(goroutineDump())
+		return
+	}
+	pprof.Lookup("goroutine").WriteTo(w, 1)
+}
+
+// Пример 2: Дамп по SIGQUIT без завершения процесса.
+// По умолчанию Go на SIGQUIT (Ctrl+\) печатает стеки всех горутин и выходит
+// с кодом 2; перехват позволяет снять дамп с живого процесса.
+func dumpOnSIGQUIT() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGQUIT)
+	go func() {
+		for range sigs {
+			name, err := writeCrashReport("SIGQUIT: дамп по запросу", nil)
+			if err != nil {
+				log.Println("Ошибка записи дампа:", err)
+				continue
+			}
+			log.Println("Дамп горутин записан:", name)
+		}
+	}()
+}
+
+func serve() {
+	dumpOnSIGQUIT()
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/goroutines", goroutinesHandler)
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		// net/http сам восстанавливается после паники в обработчике
+		// и закрывает соединение, процесс продолжает работать
+		panic("паника в обработчике")
+	})
+	
+	ln, err := net.Listen("tcp", "localhost:8080")
+	if err != nil {
+		log.Fatal("Ошибка запуска сервера:", err)
+	}
+	fmt.Println("http://localhost:8080/debug/goroutines?full=1")
+	fmt.Printf("Дамп без остановки: kill -QUIT %d\n", os.Getpid())
+	log.Fatal(http.Serve(ln, mux))
+}
+
+// Пример 3: debug.SetCrashOutput (Go 1.23+) дублирует вывод фатальной
+// паники в файл - в том числе паники в горутинах без recover, которые
+// никакой defer в main не перехватит
+func setCrashOutput() {
+	if err := os.MkdirAll(crashDir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(crashDir, fmt.Sprintf("fatal-%d.txt", os.Getpid())))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		log.Fatal(err)
+	}
+	f.Close() // SetCrashOutput дублирует дескриптор
+	fmt.Println("Фатальный вывод дублируется в", f.Name())
+}
+
+func main() {
+	mode := flag.String("mode", "main", "main, goroutine, unhandled или serve")
+	flag.Parse()
+	
+	// Полные стеки всех горутин при фатальной панике (аналог GOTRACEBACK=all)
+	debug.SetTraceback("all")
+	
+	defer handlePanic()
+	
+	switch *mode {
+	case "main":
+		var m map[string]int
+		m["key"] = 1 // assignment to entry in nil map
+	case "goroutine":
+		safeGo(func() {
+			var items []int
+			_ = items[5] // index out of range
+		})
+		// Не ждем через defer close(done) в горутине: отложенные вызовы
+		// выполняются до handlePanic, и main успел бы завершиться раньше отчета
+		time.Sleep(time.Second)
+	case "unhandled":
+		setCrashOutput()
+		go func() {
+			panic("паника в горутине без recover")
+		}()
+		time.Sleep(time.Second)
+	case "serve":
+		serve()
+	default:
+		log.Fatalf("Неизвестный режим %q", *mode)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// go get golang.org/x/crypto
+
+// Пример 1: Генерация ключа.
+// Ключ AES - 16, 24 или 32 случайных байта (AES-128/192/256) из crypto/rand.
+// math/rand для ключей не годится: его выход предсказуем.
+func generateKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatal(err)
+	}
+	return key
+}
+
+// Encrypt шифрует сообщение AES-256-GCM. Результат: nonce || шифртекст || тег.
+// additionalData аутентифицируется, но не шифруется (например, ID записи:
+// шифртекст нельзя будет перенести в чужую запись).
+func Encrypt(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Случайный 96-битный nonce безопасен примерно до 2^32 сообщений на ключ
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	
+	// Seal дописывает результат к первому аргументу - кладем nonce в начало
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt проверяет тег и расшифровывает результат Encrypt
+func Decrypt(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	
+	if len(ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("шифртекст слишком короткий")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
+
+// DeriveKey выводит 256-битный ключ из пароля.
+// Пароль нельзя использовать как ключ напрямую: в нем мало энтропии,
+// а scrypt делает каждую попытку перебора дорогой по памяти и времени.
+// N=2^15, r=8, p=1 - рекомендуемые параметры для интерактивного ввода.
+// Альтернатива - argon2.IDKey (см. examples/passwords).
+func DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+func basicEncryption() {
+	fmt.Println("=== AES-256-GCM ===")
+	
+	key := generateKey()
+	fmt.Println("Ключ:", hex.EncodeToString(key))
+	
+	ct, err := Encrypt(key, []byte("номер карты 4111 1111 1111 1111"), []byte("user:42"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Шифртекст:", hex.EncodeToString(ct))
+	
+	pt, err := Decrypt(key, ct, []byte("user:42"))
+	fmt.Printf("Расшифровано: %q, err=%v\n", pt, err)
+	
+	// Один и тот же текст шифруется по-разному благодаря случайному nonce
+	ct2, _ := Encrypt(key, []byte("номер карты 4111 1111 1111 1111"), []byte("user:42"))
+	fmt.Println("Повторное шифрование совпадает:", bytes.Equal(ct, ct2))
+	
+	// GCM - аутентифицированное шифрование: любая подмена обнаруживается
+	tampered := append([]byte(nil), ct...)
+	tampered[len(tampered)-20] ^= 1
+	_, err = Decrypt(key, tampered, []byte("user:42"))
+	fmt.Println("Измененный шифртекст:", err)
+	
+	_, err = Decrypt(key, ct, []byte("user:43"))
+	fmt.Println("Чужие additional data:", err)
+	
+	_, err = Decrypt(generateKey(), ct, []byte("user:42"))
+	fmt.Println("Другой ключ:", err)
+}
+
+func passphraseKey() {
+	fmt.Println("\n=== Ключ из пароля ===")
+	
+	// Соль случайная и хранится рядом с шифртекстом, она не секретна
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	
+	key, err := DeriveKey("correct horse battery staple", salt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Соль: %x\nКлюч: %x\n", salt, key)
+	
+	again, _ := DeriveKey("correct horse battery staple", salt)
+	fmt.Println("Тот же пароль и соль дают тот же ключ:", bytes.Equal(key, again))
+}
+
+func fileEncryption() {
+	fmt.Println("\n=== Потоковое шифрование файла ===")
+	
+	dir, err := os.MkdirTemp("", "crypto-aes")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	
+	// Файл на несколько фрагментов
+	data := make([]byte, 3*chunkSize+1234)
+	rand.Read(data)
+	plainPath := filepath.Join(dir, "data.bin")
+	os.WriteFile(plainPath, data, 0o600)
+	
+	encPath := plainPath + ".enc"
+	in, _ := os.Open(plainPath)
+	out, _ := os.Create(encPath)
+	if err := EncryptStream(out, in, "s3cret"); err != nil {
+		log.Fatal("Ошибка шифрования:", err)
+	}
+	in.Close()
+	out.Close()
+	
+	enc, _ := os.ReadFile(encPath)
+	fmt.Printf("Открытый текст: %d байт, зашифровано: %d байт\n", len(data), len(enc))
+	
+	var restored bytes.Buffer
+	err = DecryptStream(&restored, bytes.NewReader(enc), "s3cret")
+	fmt.Printf("Расшифровка: err=%v, совпадает=%v\n", err, bytes.Equal(restored.Bytes(), data))
+	
+	err = DecryptStream(io.Discard, bytes.NewReader(enc), "wrong")
+	fmt.Println("Неверный пароль:", err)
+	
+	// Обрезка по границе фрагмента: без флага последнего фрагмента прошла бы незамеченной
+	cut := enc[:headerSize+2*(chunkSize+16)]
+	err = DecryptStream(io.Discard, bytes.NewReader(cut), "s3cret")
+	fmt.Println("Обрезанный файл:", err)
+}
+
+// Пример 4: Повторное использование nonce - классическая ошибка.
+// GCM - потоковый режим: шифртекст = открытый текст XOR keystream(key, nonce).
+// Один nonce дважды => XOR двух шифртекстов равен XOR открытых текстов,
+// и знание одного сообщения раскрывает другое. Кроме того, раскрывается
+// ключ аутентификации, и злоумышленник может подделывать сообщения.
+func nonceReuse() {
+	fmt.Println("\n=== Ошибка: повторный nonce ===")
+	
+	key := generateKey()
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	
+	nonce := make([]byte, aead.NonceSize()) // Нулевой nonce для обоих сообщений - ОШИБКА
+	known := []byte("Перевод 100 руб. на счет 1111")
+	secret := []byte("Пароль от сейфа: 7392-alpha!!")
+	
+	c1 := aead.Seal(nil, nonce, known, nil)
+	c2 := aead.Seal(nil, nonce, secret, nil)
+	
+	// Атакующий знает (или угадал) первое сообщение и видит оба шифртекста
+	recovered := make([]byte, len(secret))
+	for i := range recovered {
+		recovered[i] = c1[i] ^ c2[i] ^ known[i]
+	}
+	fmt.Printf("Восстановлено без ключа: %q\n", recovered)
+	
+	fmt.Println("Как избежать:")
+	fmt.Println("  - случайный nonce из crypto/rand на каждое сообщение (до ~2^32 сообщений на ключ)")
+	fmt.Println("  - счетчик, если его состояние надежно сохраняется и не сбрасывается")
+	fmt.Println("  - cipher.NewGCMWithRandomNonce (Go 1.24+) - nonce генерируется внутри Seal")
+}
+
+func main() {
+	basicEncryption()
+	passphraseKey()
+	fileEncryption()
+	nonceReuse()
+}
\ No newline at end of file
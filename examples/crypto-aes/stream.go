@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Потоковое шифрование файла фрагментами.
+//
+// Один вызов Seal на весь файл требует держать его в памяти целиком,
+// а GCM ограничен ~64 ГиБ на одно сообщение. Поэтому файл режется на
+// фрагменты, каждый шифруется отдельно со своим nonce (схема STREAM):
+//
+//   nonce = префикс (7 байт) || номер фрагмента (4 байта) || флаг последнего (1 байт)
+//
+// Номер фрагмента не дает переставить фрагменты местами, флаг последнего -
+// обрезать файл по границе фрагмента: обе атаки ломают аутентификацию.
+//
+// Формат файла: magic (4) || соль (16) || префикс nonce (7) || фрагменты,
+// каждый фрагмент - до chunkSize байт открытого текста + 16 байт тега.
+
+const (
+	chunkSize   = 64 * 1024
+	magic       = "GLE1"
+	saltSize    = 16
+	prefixSize  = 7
+	headerSize  = len(magic) + saltSize + prefixSize
+	maxChunkNum = 1<<32 - 1
+)
+
+// ErrTruncated файл обрезан или поврежден
+var ErrTruncated = errors.New("файл обрезан или поврежден")
+
+func chunkNonce(prefix []byte, n uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixSize:], n)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// EncryptStream шифрует src в dst ключом, выведенным из пароля
+func EncryptStream(dst io.Writer, src io.Reader, passphrase string) error {
+	salt := make([]byte, saltSize)
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	if _, err := rand.Read(prefix); err != nil {
+		return err
+	}
+	
+	key, err := DeriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	
+	// Заголовок не шифруется и не передается как additional data:
+	// подмена соли меняет ключ, подмена префикса - nonce, и Open не пройдет
+	if _, err := fmt.Fprint(dst, magic); err != nil {
+		return err
+	}
+	dst.Write(salt)
+	dst.Write(prefix)
+	
+	// Читаем на один фрагмент вперед, чтобы знать, какой из них последний
+	buf := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+	n, err := io.ReadFull(src, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	
+	for i := uint32(0); ; i++ {
+		m, nextErr := io.ReadFull(src, next)
+		if nextErr != nil && nextErr != io.ErrUnexpectedEOF && nextErr != io.EOF {
+			return nextErr
+		}
+		last := m == 0
+		
+		sealed := aead.Seal(nil, chunkNonce(prefix, i, last), buf[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		if i == maxChunkNum {
+			return errors.New("файл слишком большой")
+		}
+		buf, next = next, buf
+		n = m
+	}
+}
+
+// DecryptStream расшифровывает поток, созданный EncryptStream.
+// Фрагмент записывается в dst только после успешной проверки тега,
+// но при ошибке в середине dst уже содержит начало файла - такой
+// результат нужно удалить (например, писать во временный файл и
+// переименовывать только после успеха).
+func DecryptStream(dst io.Writer, src io.Reader, passphrase string) error {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return ErrTruncated
+	}
+	if string(header[:len(magic)]) != magic {
+		return errors.New("неизвестный формат файла")
+	}
+	salt := header[len(magic) : len(magic)+saltSize]
+	prefix := header[len(magic)+saltSize:]
+	
+	key, err := DeriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	
+	sealedSize := chunkSize + aead.Overhead()
+	buf := make([]byte, sealedSize)
+	next := make([]byte, sealedSize)
+	n, err := io.ReadFull(src, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ErrTruncated
+	}
+	
+	for i := uint32(0); ; i++ {
+		m, nextErr := io.ReadFull(src, next)
+		if nextErr != nil && nextErr != io.ErrUnexpectedEOF && nextErr != io.EOF {
+			return nextErr
+		}
+		last := m == 0
+		
+		plain, err := aead.Open(buf[:0], chunkNonce(prefix, i, last), buf[:n], nil)
+		if err != nil {
+			// Неверный пароль, подмена, перестановка или обрезка
+			return fmt.Errorf("фрагмент %d: %w", i, ErrTruncated)
+		}
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		buf, next = next, buf
+		n = m
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
\ No newline at end of file
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectorSnapshot(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newCollector(10*time.Second, 3, func() time.Time { return now })
+	
+	// 10 секунд по 20 запросов, каждый десятый - 500
+	for range 10 {
+		now = now.Add(time.Second)
+		for i := range 20 {
+			status := http.StatusOK
+			if i%10 == 0 {
+				status = http.StatusInternalServerError
+			}
+			c.Observe(time.Duration(i+1)*time.Millisecond, status)
+		}
+	}
+	s := c.Snapshot()
+	
+	if math.Abs(s.RPS-20) > 0.5 {
+		t.Errorf("Expected ~20 rps, got %.2f", s.RPS)
+	}
+	if math.Abs(s.ErrorRate-0.1) > 0.01 {
+		t.Errorf("Expected error rate 0.1, got %.3f", s.ErrorRate)
+	}
+	if s.P50Ms < 5 || s.P50Ms > 15 {
+		t.Errorf("Expected p50 about 10ms, got %.2f", s.P50Ms)
+	}
+	if s.P99Ms < s.P50Ms {
+		t.Errorf("Expected p99 >= p50, got %.2f < %.2f", s.P99Ms, s.P50Ms)
+	}
+	if s.At != now.UnixMilli() {
+		t.Errorf("Expected timestamp %d, got %d", now.UnixMilli(), s.At)
+	}
+	
+	// Перцентили считаются за интервал: без новых запросов - пусто
+	if next := c.Snapshot(); next.P99Ms != 0 {
+		t.Errorf("Expected empty p99 in new interval, got %.2f", next.P99Ms)
+	}
+}
+
+func TestCollectorHistoryAndSubscribe(t *testing.T) {
+	c := NewCollector(time.Second, 3)
+	for range 5 {
+		c.Snapshot()
+	}
+	history, ch := c.Subscribe()
+	if len(history) != 3 {
+		t.Fatalf("Expected history of 3, got %d", len(history))
+	}
+	
+	s := c.Snapshot()
+	select {
+	case got := <-ch:
+		if got != s {
+			t.Errorf("Expected %+v, got %+v", s, got)
+		}
+	default:
+		t.Fatal("Expected snapshot to be delivered")
+	}
+	if c.Latest() != s {
+		t.Errorf("Expected Latest to return last snapshot")
+	}
+	
+	// Медленный подписчик не блокирует сборщик
+	for range cap(ch) + 5 {
+		c.Snapshot()
+	}
+	c.Unsubscribe(ch)
+	for range ch {
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	c := NewCollector(time.Second, 10)
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "fail", http.StatusInternalServerError)
+		}
+	}))
+	for _, path := range []string{"/ok", "/ok", "/ok", "/fail"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	s := c.Snapshot()
+	if s.ErrorRate != 0.25 {
+		t.Errorf("Expected error rate 0.25, got %.3f", s.ErrorRate)
+	}
+	if s.InFlight != 0 {
+		t.Errorf("Expected 0 in flight, got %d", s.InFlight)
+	}
+}
+
+// readEvent читает одно событие SSE, пропуская комментарии
+func readEvent(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event != "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestEventsStream(t *testing.T) {
+	c := NewCollector(time.Second, 10)
+	c.Snapshot()
+	ts := httptest.NewServer(Routes(c))
+	defer ts.Close()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		c.Run(ctx, 10*time.Millisecond)
+		close(runDone)
+	}()
+	
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", ct)
+	}
+	
+	r := bufio.NewReader(resp.Body)
+	event, data := readEvent(t, r)
+	var history []Snapshot
+	if err := json.Unmarshal([]byte(data), &history); event != "history" || err != nil || len(history) == 0 {
+		t.Fatalf("Expected non-empty history event, got %q %q (%v)", event, data, err)
+	}
+	event, data = readEvent(t, r)
+	var s Snapshot
+	if err := json.Unmarshal([]byte(data), &s); event != "snapshot" || err != nil {
+		t.Fatalf("Expected snapshot event, got %q %q (%v)", event, data, err)
+	}
+	
+	// Остановка сборщика завершает поток - иначе Shutdown ждал бы его
+	cancel()
+	<-runDone
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Errorf("Expected stream to end cleanly, got %v", err)
+	}
+}
+
+func TestIndexPage(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Routes(NewCollector(time.Second, 1)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "EventSource") {
+		t.Errorf("Expected dashboard page, got %d", rec.Code)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Живой дашборд метрик: сервис сам считает свои метрики в процессе и
+// показывает их на странице с графиками, обновляемой в реальном времени
+// (точка раз в секунду - "мягкое" реальное время: опоздание точки
+// ничего не ломает).
+//   metrics.go - Collector: middleware измеряет запросы, раз в интервал
+//                получается Snapshot для подписчиков; окно, EWMA и P² -
+//                из examples/streamstats (go.mod подключает его через replace)
+//   server.go  - страница и поток Server-Sent Events
+//   static/    - HTML с графиками на canvas, без библиотек
+//   main.go    - демо-сервис и встроенный генератор нагрузки
+//
+// Это та же картина, что Prometheus + Grafana, только в одном процессе и
+// без хранения: история живет в памяти, пока работает сервис. Для
+// production метрики отдают наружу (examples/expvar), а графики строит
+// отдельная система.
+//
+// Запуск: go run . и открыть http://localhost:8080
+// Генератор нагрузки по кругу меняет фазы: обычная работа, медленная
+// зависимость (растет p99), всплеск ошибок, пик запросов.
+//   go run . -load 0                     без встроенной нагрузки
+//   go run ../loadgen -url http://localhost:8080/api/work -d 30s   внешняя нагрузка
+//   curl -N localhost:8080/events        поток как есть
+//
+// Тесты: go test -race ./...
+
+// Config параметры дашборда
+type Config struct {
+	Addr     string
+	Interval time.Duration // Период снимков
+	Window   time.Duration // Окно rps и доли ошибок
+	History  int           // Сколько снимков помнить
+	Load     int           // Запросов в секунду от генератора, 0 - выключен
+}
+
+// phase режим работы демо-сервиса
+type phase struct {
+	name      string
+	latency   time.Duration // Средняя задержка ответа
+	errorRate float64
+	loadX     int // Множитель нагрузки генератора
+}
+
+var phases = []phase{
+	{"обычная работа", 20 * time.Millisecond, 0.01, 1},
+	{"медленная зависимость", 120 * time.Millisecond, 0.01, 1},
+	{"всплеск ошибок", 20 * time.Millisecond, 0.3, 1},
+	{"пик нагрузки", 30 * time.Millisecond, 0.02, 3},
+}
+
+const phaseDuration = 20 * time.Second
+
+// currentPhase фаза по времени с момента запуска
+func currentPhase(start time.Time) phase {
+	return phases[int(time.Since(start)/phaseDuration)%len(phases)]
+}
+
+// workHandler демо-обработчик: задержка и ошибки зависят от фазы
+func workHandler(start time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p := currentPhase(start)
+		// Экспоненциальное распределение: большинство ответов быстрые,
+		// но есть длинный хвост - как у настоящих сервисов
+		delay := time.Duration(rand.ExpFloat64() * float64(p.latency))
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		if rand.Float64() < p.errorRate {
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, "ok (%s)\n", p.name)
+	}
+}
+
+// generateLoad шлет rps запросов в секунду на url до отмены ctx.
+// Запросы идут по таймеру, не дожидаясь ответов (открытая модель, как
+// у реальных пользователей), поэтому медленная фаза видна и как рост
+// числа запросов в обработке.
+func generateLoad(ctx context.Context, url string, rps int, start time.Time) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	ticker := time.NewTicker(time.Second / time.Duration(rps))
+	defer ticker.Stop()
+	lastPhase := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		p := currentPhase(start)
+		if p.name != lastPhase {
+			log.Printf("Фаза: %s", p.name)
+			lastPhase = p.name
+		}
+		for range p.loadX {
+			go func() {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+				if err != nil {
+					return
+				}
+				if resp, err := client.Do(req); err == nil {
+					resp.Body.Close()
+				}
+			}()
+		}
+	}
+}
+
+// run запускает сервис и блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	start := time.Now()
+	collector := NewCollector(cfg.Window, cfg.History)
+	go collector.Run(ctx, cfg.Interval)
+	
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/work", collector.Middleware(workHandler(start)))
+	mux.Handle("/", Routes(collector))
+	server := &http.Server{Addr: cfg.Addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Дашборд: http://localhost%s", cfg.Addr)
+		errCh <- server.ListenAndServe()
+	}()
+	if cfg.Load > 0 {
+		go generateLoad(ctx, "http://localhost"+cfg.Addr+"/api/work", cfg.Load, start)
+	}
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	// Потоки SSE завершатся сами: Collector.Run закрывает каналы
+	// подписчиков при отмене ctx
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.Addr, "addr", ":8080", "адрес HTTP-сервера")
+	flag.DurationVar(&cfg.Interval, "interval", time.Second, "период точек на графиках")
+	flag.DurationVar(&cfg.Window, "window", 10*time.Second, "окно rps и доли ошибок")
+	flag.IntVar(&cfg.History, "history", 300, "сколько точек хранить")
+	flag.IntVar(&cfg.Load, "load", 50, "встроенная нагрузка, запросов в секунду; 0 - выключена")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
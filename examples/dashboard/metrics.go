@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"streamstats"
+)
+
+// Snapshot точка на графиках: состояние сервиса в момент At
+type Snapshot struct {
+	At         int64   `json:"at"`         // Unix, мс
+	RPS        float64 `json:"rps"`        // Запросов в секунду за окно
+	ErrorRate  float64 `json:"error_rate"` // Доля ответов 5xx за окно, 0..1
+	AvgMs      float64 `json:"avg_ms"`     // EWMA задержки
+	P50Ms      float64 `json:"p50_ms"`     // Медиана за последний интервал
+	P99Ms      float64 `json:"p99_ms"`     // p99 за последний интервал
+	InFlight   int64   `json:"in_flight"`  // Запросов в обработке
+	Goroutines int     `json:"goroutines"`
+	HeapMB     float64 `json:"heap_mb"` // Занятая куча
+}
+
+// Collector собирает метрики запросов в процессе и раз в интервал
+// превращает их в Snapshot для подписчиков.
+//
+// Каждая метрика считается своим способом из пакета streamstats:
+//   - rps и доля ошибок - скользящее окно (RateCounter): плавно, но
+//     реагирует на всплеск с задержкой в окно;
+//   - средняя задержка - EWMA: одно число, прошлое забывается за период
+//     полураспада;
+//   - перцентили - P² на один интервал: оценщики заменяются новыми при
+//     каждом снимке, иначе p99 за все время работы перестал бы
+//     замечать свежие выбросы.
+type Collector struct {
+	mu       sync.Mutex
+	requests *streamstats.RateCounter
+	errors   *streamstats.RateCounter
+	latency  *streamstats.EWMA
+	p50, p99 *streamstats.P2
+	inFlight int64
+	now      func() time.Time
+
+	history     []Snapshot // Последние снимки для новых подписчиков
+	historySize int
+	subscribers map[chan Snapshot]struct{}
+	closed      bool
+}
+
+// NewCollector создает сборщик, который помнит historySize снимков
+func NewCollector(window time.Duration, historySize int) *Collector {
+	return newCollector(window, historySize, time.Now)
+}
+
+func newCollector(window time.Duration, historySize int, now func() time.Time) *Collector {
+	return &Collector{
+		requests:    streamstats.NewRateCounterWithNow(window, 10, now),
+		errors:      streamstats.NewRateCounterWithNow(window, 10, now),
+		latency:     streamstats.NewEWMA(5 * time.Second),
+		p50:         streamstats.NewP2(0.5),
+		p99:         streamstats.NewP2(0.99),
+		now:         now,
+		historySize: historySize,
+		subscribers: make(map[chan Snapshot]struct{}),
+	}
+}
+
+// Observe учитывает завершенный запрос
+func (c *Collector) Observe(latency time.Duration, status int) {
+	ms := float64(latency.Microseconds()) / 1000
+	c.requests.Add(1)
+	if status >= 500 {
+		c.errors.Add(1)
+	}
+	c.latency.Observe(ms, c.now())
+	
+	c.mu.Lock()
+	p50, p99 := c.p50, c.p99
+	c.mu.Unlock()
+	p50.Add(ms)
+	p99.Add(ms)
+}
+
+// Middleware измеряет каждый запрос через next
+func (c *Collector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := c.now()
+		c.mu.Lock()
+		c.inFlight++
+		c.mu.Unlock()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		
+		defer func() {
+			c.mu.Lock()
+			c.inFlight--
+			c.mu.Unlock()
+			c.Observe(c.now().Sub(start), rec.status)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Snapshot снимает текущее состояние, начинает новый интервал
+// перцентилей и рассылает снимок подписчикам
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.Lock()
+	p50, p99 := c.p50, c.p99
+	c.p50, c.p99 = streamstats.NewP2(0.5), streamstats.NewP2(0.99)
+	inFlight := c.inFlight
+	c.mu.Unlock()
+	
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	s := Snapshot{
+		At:         c.now().UnixMilli(),
+		RPS:        c.requests.Rate(),
+		AvgMs:      c.latency.Value(),
+		P50Ms:      p50.Value(),
+		P99Ms:      p99.Value(),
+		InFlight:   inFlight,
+		Goroutines: runtime.NumGoroutine(),
+		HeapMB:     float64(mem.HeapAlloc) / (1 << 20),
+	}
+	if total := c.requests.Count(); total > 0 {
+		s.ErrorRate = float64(c.errors.Count()) / float64(total)
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.history = append(c.history, s)
+	if len(c.history) > c.historySize {
+		c.history = c.history[len(c.history)-c.historySize:]
+	}
+	for ch := range c.subscribers {
+		// Медленный клиент пропускает точку, а не тормозит остальных
+		select {
+		case ch <- s:
+		default:
+		}
+	}
+	return s
+}
+
+// Subscribe возвращает историю и канал новых снимков. Канал закрывается
+// при Unsubscribe или остановке сборщика.
+func (c *Collector) Subscribe() ([]Snapshot, chan Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan Snapshot, 8)
+	if c.closed {
+		close(ch)
+		return nil, ch
+	}
+	c.subscribers[ch] = struct{}{}
+	return append([]Snapshot{}, c.history...), ch
+}
+
+// Latest возвращает последний снимок; нулевой, если снимков еще не было
+func (c *Collector) Latest() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.history) == 0 {
+		return Snapshot{}
+	}
+	return c.history[len(c.history)-1]
+}
+
+// Unsubscribe отписывает канал
+func (c *Collector) Unsubscribe(ch chan Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.subscribers[ch]; ok {
+		delete(c.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Run делает снимок каждые interval до отмены ctx, затем закрывает
+// каналы подписчиков: их SSE-обработчики завершатся, и Shutdown
+// сервера не будет ждать вечных соединений
+func (c *Collector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.closed = true
+			for ch := range c.subscribers {
+				delete(c.subscribers, ch)
+				close(ch)
+			}
+			c.mu.Unlock()
+			return
+		case <-ticker.C:
+			c.Snapshot()
+		}
+	}
+}
\ No newline at end of file
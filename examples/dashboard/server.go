@@ -0,0 +1,95 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// heartbeatPeriod как часто слать комментарий в поток SSE: прокси
+// закрывают соединения, по которым долго ничего не идет
+const heartbeatPeriod = 15 * time.Second
+
+// Routes возвращает маршруты дашборда:
+//
+//	GET /            HTML-страница с графиками
+//	GET /events      поток снимков (Server-Sent Events)
+//	GET /api/metrics последний снимок JSON
+func Routes(c *Collector) http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.Handle("GET /events", handleEvents(c))
+	mux.HandleFunc("GET /api/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.Latest())
+	})
+	return mux
+}
+
+// handleEvents отдает снимки как Server-Sent Events. SSE выбран вместо
+// WebSocket (см. projects/chat): данные идут только от сервера к
+// браузеру, а это обычный HTTP-ответ, который не кончается, - без
+// своего протокола, и EventSource в браузере сам переподключается.
+//
+// Формат: первым событием "history" - накопленные снимки, чтобы графики
+// не начинались с пустого места, затем по событию "snapshot" на точку.
+func handleEvents(c *Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Потоковая передача не поддерживается", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		// nginx по умолчанию буферизует ответ и задержал бы события
+		w.Header().Set("X-Accel-Buffering", "no")
+		
+		history, ch := c.Subscribe()
+		defer c.Unsubscribe(ch)
+		
+		if err := writeEvent(w, "history", history); err != nil {
+			return
+		}
+		flusher.Flush()
+		
+		heartbeat := time.NewTicker(heartbeatPeriod)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case s, ok := <-ch:
+				if !ok {
+					return // Сборщик остановлен
+				}
+				if err := writeEvent(w, "snapshot", s); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	})
+}
+
+// writeEvent пишет событие SSE. JSON не содержит переводов строк, поэтому
+// помещается в одно поле data.
+func writeEvent(w http.ResponseWriter, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
\ No newline at end of file
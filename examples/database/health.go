@@ -0,0 +1,283 @@
+package main
+
+// Проверки /healthz и /readyz - копия пакета examples/http-server/health
+// (health.go и checkers.go; New здесь - NewHealth): у этого примера нет
+// своего модуля, и импортировать пакет он не может. Подробно о разнице
+// liveness и readiness - в комментарии к пакету.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout таймаут проверки, если не задан WithTimeout; меньше
+// секунды, которую Kubernetes по умолчанию ждет ответа пробы
+const DefaultTimeout = 800 * time.Millisecond
+
+// Статусы проверки и отчета целиком
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker одна проверка; nil - все в порядке. Проверка должна уважать
+// отмену ctx: по таймауту Registry перестает ее ждать, но остановить
+// саму проверку может только она.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc функция как Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check вызывает f(ctx)
+func (f CheckerFunc) Check(ctx context.Context) error { return f(ctx) }
+
+// Option настройка проверки при регистрации
+type Option func(*check)
+
+// WithTimeout свой таймаут проверки вместо DefaultTimeout
+func WithTimeout(d time.Duration) Option {
+	return func(c *check) { c.timeout = d }
+}
+
+// Optional проверка попадает в отчет, но ее сбой не делает сервис
+// неготовым: например, сервис рекомендаций, без которого страница
+// показывается, просто беднее
+func Optional() Option {
+	return func(c *check) { c.optional = true }
+}
+
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	optional bool
+}
+
+// Result итог одной проверки
+type Result struct {
+	Status   string  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"duration_ms"`
+	Optional bool    `json:"optional,omitempty"`
+}
+
+// Report итог всех проверок одного вида
+type Report struct {
+	Status string            `json:"status"`
+	Reason string            `json:"reason,omitempty"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Registry зарегистрированные проверки liveness и readiness
+type Registry struct {
+	mu    sync.RWMutex
+	live  []check
+	ready []check
+
+	draining atomic.Bool
+}
+
+// NewHealth пустой Registry: без проверок оба эндпоинта отвечают 200
+func NewHealth() *Registry {
+	return &Registry{}
+}
+
+// Live регистрирует проверку liveness - только то, что лечится
+// перезапуском процесса (зависший цикл обработки, исчерпанные ресурсы)
+func (r *Registry) Live(name string, c Checker, opts ...Option) {
+	r.add(&r.live, name, c, opts)
+}
+
+// Ready регистрирует проверку readiness - зависимости, без которых
+// сервис не может обслуживать запросы
+func (r *Registry) Ready(name string, c Checker, opts ...Option) {
+	r.add(&r.ready, name, c, opts)
+}
+
+func (r *Registry) add(list *[]check, name string, c Checker, opts []Option) {
+	ch := check{name: name, checker: c, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(&ch)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*list = append(*list, ch)
+}
+
+// Drain переводит readiness в fail без запуска проверок. Вызывается в
+// начале остановки (http.Server.RegisterOnShutdown): балансировщик
+// убирает копию из ротации, пока она дорабатывает текущие запросы.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// CheckLive выполняет проверки liveness
+func (r *Registry) CheckLive(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := r.live
+	r.mu.RUnlock()
+	return run(ctx, checks)
+}
+
+// CheckReady выполняет проверки readiness; после Drain - сразу fail
+func (r *Registry) CheckReady(ctx context.Context) Report {
+	if r.draining.Load() {
+		return Report{Status: StatusFail, Reason: "остановка"}
+	}
+	r.mu.RLock()
+	checks := r.ready
+	r.mu.RUnlock()
+	return run(ctx, checks)
+}
+
+// run выполняет проверки параллельно; отчет fail, если не прошла хотя
+// бы одна обязательная
+func run(ctx context.Context, checks []check) Report {
+	rep := Report{Status: StatusOK}
+	if len(checks) == 0 {
+		return rep
+	}
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runOne(ctx, c)
+		}()
+	}
+	wg.Wait()
+	
+	rep.Checks = make(map[string]Result, len(checks))
+	for i, c := range checks {
+		rep.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK && !c.optional {
+			rep.Status = StatusFail
+		}
+	}
+	return rep
+}
+
+// runOne выполняет проверку с таймаутом. Проверка идет в своей
+// горутине: если она не смотрит на ctx, отчет все равно не ждет
+// дольше таймаута, а горутина доработает сама.
+func runOne(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("паника: %v", p)
+			}
+		}()
+		done <- c.checker.Check(ctx)
+	}()
+	
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := Result{
+		Status:   StatusOK,
+		Duration: float64(time.Since(start).Microseconds()) / 1000,
+		Optional: c.optional,
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			res.Error = fmt.Sprintf("таймаут %v", c.timeout)
+		}
+	}
+	return res
+}
+
+// LiveHandler обработчик /healthz
+func (r *Registry) LiveHandler() http.Handler {
+	return reportHandler(r.CheckLive)
+}
+
+// ReadyHandler обработчик /readyz
+func (r *Registry) ReadyHandler() http.Handler {
+	return reportHandler(r.CheckReady)
+}
+
+// reportHandler отвечает отчетом в JSON: 200 при ok, 503 при fail.
+// Оркестратор смотрит только на код, JSON - для человека и мониторинга.
+func reportHandler(check func(context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rep := check(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		// Кэш между пробой и сервисом показал бы старое состояние
+		w.Header().Set("Cache-Control", "no-store")
+		if rep.Status != StatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(rep)
+	})
+}
+
+// Pinger то, что умеет проверить соединение: *sql.DB, клиенты Redis
+// и брокеров очередей
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping проверка соединения с базой. *sql.DB.PingContext берет
+// соединение из пула и при необходимости открывает новое, так что
+// проверка заметит и упавшую базу, и исчерпанный пул.
+func Ping(p Pinger) Checker {
+	return CheckerFunc(p.PingContext)
+}
+
+// HTTPGet проверка соседнего сервиса: GET url должен вернуть 2xx.
+// Обычно url - его собственный /readyz или /healthz. client - свой, с
+// транспортом сервиса; таймаут задает Registry через контекст.
+func HTTPGet(client *http.Client, url string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		// Дочитываем тело, чтобы соединение вернулось в пул
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s: %s", url, resp.Status)
+		}
+		return nil
+	})
+}
+
+// DiskSpace проверка свободного места на разделе с path: база и
+// загрузки, которым некуда писать, отвечают ошибками на каждый запрос
+func DiskSpace(path string, minFree uint64) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		free, err := freeSpace(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%s: свободно %d МБ, нужно не меньше %d МБ", path, free>>20, minFree>>20)
+		}
+		return nil
+	})
+}
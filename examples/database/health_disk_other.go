@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd)
+
+package main
+
+import (
+	"errors"
+	"runtime"
+)
+
+// freeSpace на остальных платформах нужен свой системный вызов
+// (GetDiskFreeSpaceEx на Windows) - в примере его нет
+func freeSpace(string) (uint64, error) {
+	return 0, errors.New("проверка места на диске не поддерживается на " + runtime.GOOS)
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"time"
-
+	
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -81,7 +84,11 @@ func (d *Database) GetUserByID(id int) (*User, error) {
 	return &user, nil
 }
 
-// GetAllUsers получает всех пользователей
+// GetAllUsers получает всех пользователей. Срез растет через append:
+// число строк заранее неизвестно, а отдельный COUNT(*) ради емкости -
+// лишний запрос с полным проходом по таблице, дороже всех копий при
+// росте, и к чтению строк он уже может устареть. Предвыделение - для
+// размеров, известных в памяти (см. examples/prealloc).
 func (d *Database) GetAllUsers() ([]User, error) {
 	query := `SELECT id, name, email, created_at FROM users`
 	rows, err := d.db.Query(query)
@@ -430,6 +437,48 @@ func nullValues() {
 	}
 }
 
+// Пример 7: Проверки здоровья (см. health.go)
+// База - зависимость для readiness: пока она не отвечает, запросы на
+// эту копию сервиса слать бесполезно, но перезапуск ее не починит
+func healthChecks() {
+	fmt.Println("\n=== Проверки здоровья ===")
+	
+	db, err := NewDatabase(":memory:")
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	
+	checks := NewHealth()
+	checks.Ready("db", Ping(db.db), WithTimeout(500*time.Millisecond))
+	checks.Ready("disk", DiskSpace(".", 100<<20))
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", checks.LiveHandler())
+	mux.Handle("/readyz", checks.ReadyHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	
+	get := func(path string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var rep Report
+		json.NewDecoder(resp.Body).Decode(&rep)
+		fmt.Printf("%s: %d %s", path, resp.StatusCode, rep.Status)
+		for name, res := range rep.Checks {
+			fmt.Printf(", %s=%s %s", name, res.Status, res.Error)
+		}
+		fmt.Println()
+	}
+	get("/readyz")
+	
+	// Закрытый пул - как упавшая база: readiness падает, liveness нет
+	db.Close()
+	get("/readyz")
+	get("/healthz")
+}
+
 func main() {
 	basicDatabaseOperations()
 	transactionsExample()
@@ -437,6 +486,7 @@ func main() {
 	connectionPooling()
 	databaseErrorHandling()
 	nullValues()
+	healthChecks()
 	
 	fmt.Println("\n=== Все примеры работы с БД ===")
 	fmt.Println("Для запуска примеров убедитесь, что установлен драйвер: go get github.com/mattn/go-sqlite3")
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"ivan@example.com", "ivan@example.com", true},
+		{"  Ivan@Example.COM ", "ivan@example.com", true},
+		{"", "", false},
+		{"ivan", "", false},
+		{"Иван <ivan@example.com>", "", false},
+		{"ivan@example.com, maria@example.com", "", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			e, err := ParseEmail(tt.in)
+			if tt.ok != (err == nil) {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, err)
+			}
+			if e.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, e)
+			}
+			var verr *ValidationError
+			if err != nil && !errors.As(err, &verr) {
+				t.Errorf("Expected ValidationError, got %T", err)
+			}
+		})
+	}
+	
+	a, _ := ParseEmail("Ivan@example.com")
+	b, _ := ParseEmail("ivan@EXAMPLE.com")
+	if a != b || a.Domain() != "example.com" {
+		t.Errorf("Expected equal values with domain example.com, got %v %v", a, b)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency Currency
+		minor    int64
+		str      string
+		ok       bool
+	}{
+		{"10", "RUB", 1000, "10.00 RUB", true},
+		{"10.5", "RUB", 1050, "10.50 RUB", true},
+		{"10,05", "RUB", 1005, "10.05 RUB", true},
+		{"-0.75", "USD", -75, "-0.75 USD", true},
+		{"1500", "JPY", 1500, "1500 JPY", true},
+		{"0.001", "RUB", 0, "", false},
+		{"1.5", "JPY", 0, "", false},
+		{"", "RUB", 0, "", false},
+		{"-", "RUB", 0, "", false},
+		{".5", "RUB", 0, "", false},
+		{"+1", "RUB", 0, "", false},
+		{"1e3", "RUB", 0, "", false},
+		{"1.-5", "RUB", 0, "", false},
+		{"99999999999999999999", "RUB", 0, "", false},
+		{"10", "XYZ", 0, "", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.in+" "+string(tt.currency), func(t *testing.T) {
+			m, err := ParseMoney(tt.in, tt.currency)
+			if tt.ok != (err == nil) {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, err)
+			}
+			if !tt.ok {
+				return
+			}
+			if m.Minor() != tt.minor || m.String() != tt.str {
+				t.Errorf("Expected %d (%s), got %d (%s)", tt.minor, tt.str, m.Minor(), m)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	rub := func(minor int64) Money { m, _ := NewMoney(minor, "RUB"); return m }
+	usd, _ := NewMoney(100, "USD")
+	
+	tests := []struct {
+		name string
+		op   func() (Money, error)
+		want Money
+		err  error
+	}{
+		{"add", func() (Money, error) { return rub(150).Add(rub(75)) }, rub(225), nil},
+		{"sub to negative", func() (Money, error) { return rub(50).Sub(rub(75)) }, rub(-25), nil},
+		{"currency mismatch", func() (Money, error) { return rub(1).Add(usd) }, Money{}, ErrCurrencyMismatch},
+		{"overflow", func() (Money, error) { return rub(math.MaxInt64).Add(rub(1)) }, Money{}, ErrOverflow},
+		{"underflow", func() (Money, error) { return rub(math.MinInt64).Sub(rub(1)) }, Money{}, ErrOverflow},
+		{"sub min", func() (Money, error) { return rub(0).Sub(rub(math.MinInt64)) }, Money{}, ErrOverflow},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+	
+	if c, err := rub(1).Cmp(rub(2)); c != -1 || err != nil {
+		t.Errorf("Expected -1, got %d, %v", c, err)
+	}
+	if _, err := rub(1).Cmp(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if s := rub(math.MinInt64).String(); s != "-92233720368547758.08 RUB" {
+		t.Errorf("Expected MinInt64 to format without overflow, got %s", s)
+	}
+}
+
+func newUser(t *testing.T) *User {
+	t.Helper()
+	email, _ := ParseEmail("ivan@example.com")
+	u, err := RegisterUser(1, "  Иван   Иванов ", email, "RUB", time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return u
+}
+
+func TestRegisterUser(t *testing.T) {
+	email, _ := ParseEmail("ivan@example.com")
+	long := make([]rune, maxNameLength+1)
+	for i := range long {
+		long[i] = 'я'
+	}
+	
+	tests := []struct {
+		name     string
+		userName string
+		email    Email
+		currency Currency
+		field    string
+	}{
+		{"empty name", "  ", email, "RUB", "name"},
+		{"long name", string(long), email, "RUB", "name"},
+		{"zero email", "Иван", Email{}, "RUB", "email"},
+		{"unknown currency", "Иван", email, "XYZ", "currency"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RegisterUser(1, tt.userName, tt.email, tt.currency, time.Now())
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Errorf("Expected ValidationError on %s, got %v", tt.field, err)
+			}
+		})
+	}
+	
+	u := newUser(t)
+	if u.Name() != "Иван Иванов" || !u.Active() || !u.Balance().IsZero() || u.Balance().Currency() != "RUB" {
+		t.Errorf("Unexpected user state: %+v", u.Snapshot())
+	}
+	events := u.PullEvents()
+	if len(events) != 1 || events[0].EventName() != "user.registered" {
+		t.Errorf("Expected user.registered event, got %v", events)
+	}
+	if len(u.PullEvents()) != 0 {
+		t.Error("Expected PullEvents to clear events")
+	}
+}
+
+func TestUserInvariants(t *testing.T) {
+	rub := func(s string) Money { m, _ := ParseMoney(s, "RUB"); return m }
+	usd, _ := ParseMoney("1", "USD")
+	now := time.Now()
+	
+	tests := []struct {
+		name    string
+		prepare func(u *User)
+		cmd     func(u *User) error
+		err     error
+		balance string
+	}{
+		{"deposit", nil, func(u *User) error { return u.Deposit(rub("10"), now) }, nil, "10.00 RUB"},
+		{"deposit zero", nil, func(u *User) error { return u.Deposit(rub("0"), now) }, ErrNotPositive, "0.00 RUB"},
+		{"deposit negative", nil, func(u *User) error { return u.Deposit(rub("-1"), now) }, ErrNotPositive, "0.00 RUB"},
+		{"deposit other currency", nil, func(u *User) error { return u.Deposit(usd, now) }, ErrCurrencyMismatch, "0.00 RUB"},
+		{"charge within balance",
+			func(u *User) { u.Deposit(rub("10"), now) },
+			func(u *User) error { return u.Charge(rub("10"), "x", now) }, nil, "0.00 RUB"},
+		{"charge over balance",
+			func(u *User) { u.Deposit(rub("10"), now) },
+			func(u *User) error { return u.Charge(rub("10.01"), "x", now) }, ErrInsufficientFunds, "10.00 RUB"},
+		{"deactivate with balance",
+			func(u *User) { u.Deposit(rub("1"), now) },
+			func(u *User) error { return u.Deactivate(now) }, ErrNonZeroBalance, "1.00 RUB"},
+		{"deposit after deactivate",
+			func(u *User) { u.Deactivate(now) },
+			func(u *User) error { return u.Deposit(rub("1"), now) }, ErrDeactivated, "0.00 RUB"},
+		{"deactivate twice",
+			func(u *User) { u.Deactivate(now) },
+			func(u *User) error { return u.Deactivate(now) }, ErrDeactivated, "0.00 RUB"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUser(t)
+			if tt.prepare != nil {
+				tt.prepare(u)
+			}
+			u.PullEvents()
+			
+			err := tt.cmd(u)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if u.Balance().String() != tt.balance {
+				t.Errorf("Expected balance %s, got %s", tt.balance, u.Balance())
+			}
+			// Отказ не оставляет событий, успех оставляет ровно одно
+			if n := len(u.PullEvents()); (tt.err == nil) != (n == 1) {
+				t.Errorf("Expected 1 event on success and none on error, got %d", n)
+			}
+		})
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	u := newUser(t)
+	u.PullEvents()
+	same, _ := ParseEmail("IVAN@example.com")
+	other, _ := ParseEmail("ivan@work.example.com")
+	
+	if err := u.ChangeEmail(same, time.Now()); err != nil || len(u.PullEvents()) != 0 {
+		t.Errorf("Expected no event for the same email, got %v", err)
+	}
+	if err := u.ChangeEmail(other, time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	events := u.PullEvents()
+	ev, ok := events[0].(EmailChanged)
+	if len(events) != 1 || !ok || ev.Old.String() != "ivan@example.com" || ev.New != other {
+		t.Errorf("Expected EmailChanged from old to new address, got %+v", events)
+	}
+	if err := u.ChangeEmail(Email{}, time.Now()); err == nil {
+		t.Error("Expected error for zero email")
+	}
+}
+
+func TestService(t *testing.T) {
+	var published []string
+	events := NewDispatcher()
+	for _, name := range []string{"user.registered", "user.funds_deposited", "user.funds_charged"} {
+		events.Subscribe(name, func(e Event) { published = append(published, e.EventName()) })
+	}
+	svc := &Service{users: NewUsers(), events: events, now: time.Now}
+	
+	id, err := svc.Register("Иван", "ivan@example.com", "RUB")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := svc.Register("Двойник", "IVAN@example.com", "RUB"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("Expected ErrEmailTaken, got %v", err)
+	}
+	if err := svc.Deposit(id, "100"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := svc.Charge(id, "150", "покупка"); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Expected ErrInsufficientFunds, got %v", err)
+	}
+	if err := svc.Deposit(99, "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	
+	// Отклоненные команды и неудачное сохранение не публикуют событий
+	want := []string{"user.registered", "user.funds_deposited"}
+	if len(published) != len(want) || published[0] != want[0] || published[1] != want[1] {
+		t.Errorf("Expected events %v, got %v", want, published)
+	}
+	u, _ := svc.users.Get(id)
+	if u.Balance().String() != "100.00 RUB" {
+		t.Errorf("Expected balance 100.00 RUB, got %s", u.Balance())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Domain-driven design в малом: объекты-значения, агрегат и доменные
+// события вместо структур с открытыми полями.
+//
+//   values.go - Email и Money: проверка в конструкторе, неизменяемость
+//   user.go   - агрегат User: правила в методах, события об изменениях
+//   main.go   - прикладной сервис: разбор ввода, хранилище, публикация
+//               событий после сохранения
+//
+// Запуск: go run .
+
+// ErrEmailTaken адрес занят другим пользователем. Уникальность - не
+// правило одного агрегата (User не знает о других пользователях),
+// поэтому ее проверяет хранилище.
+var ErrEmailTaken = errors.New("email already taken")
+
+// ErrNotFound пользователя нет
+var ErrNotFound = errors.New("user not found")
+
+// Users хранилище агрегатов. Хранит снимки, а не указатели, чтобы
+// изменения без Save не попадали в хранилище.
+type Users struct {
+	mu     sync.Mutex
+	nextID UserID
+	byID   map[UserID]UserSnapshot
+}
+
+// NewUsers создает хранилище
+func NewUsers() *Users {
+	return &Users{byID: make(map[UserID]UserSnapshot)}
+}
+
+// NextID выдает идентичность для нового агрегата
+func (r *Users) NextID() UserID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	return r.nextID
+}
+
+// Get загружает агрегат
+func (r *Users) Get(id UserID) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.byID[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return Restore(s), nil
+}
+
+// Save сохраняет агрегат целиком
+func (r *Users) Save(u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := u.Snapshot()
+	for id, other := range r.byID {
+		if id != s.ID && other.Email == s.Email {
+			return ErrEmailTaken
+		}
+	}
+	r.byID[s.ID] = s
+	return nil
+}
+
+// Dispatcher рассылает события подписчикам. Подписчики - другие части
+// системы (письма, аудит, аналитика), о которых агрегат не знает.
+type Dispatcher struct {
+	handlers map[string][]func(Event)
+}
+
+// NewDispatcher создает диспетчер
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]func(Event))}
+}
+
+// Subscribe подписывает обработчик на событие с именем name
+func (d *Dispatcher) Subscribe(name string, h func(Event)) {
+	d.handlers[name] = append(d.handlers[name], h)
+}
+
+// Publish вызывает обработчики событий по порядку
+func (d *Dispatcher) Publish(events []Event) {
+	for _, e := range events {
+		for _, h := range d.handlers[e.EventName()] {
+			h(e)
+		}
+	}
+}
+
+// Service прикладной сервис: превращает ввод в объекты-значения,
+// загружает агрегат, вызывает команду, сохраняет и публикует события.
+// Сам он правил не содержит.
+type Service struct {
+	users  *Users
+	events *Dispatcher
+	now    func() time.Time
+}
+
+// Register регистрирует пользователя
+func (s *Service) Register(name, email string, currency Currency) (UserID, error) {
+	addr, err := ParseEmail(email)
+	if err != nil {
+		return 0, err
+	}
+	u, err := RegisterUser(s.users.NextID(), name, addr, currency, s.now())
+	if err != nil {
+		return 0, err
+	}
+	return u.ID(), s.save(u)
+}
+
+// ChangeEmail меняет адрес пользователя
+func (s *Service) ChangeEmail(id UserID, email string) error {
+	addr, err := ParseEmail(email)
+	if err != nil {
+		return err
+	}
+	return s.update(id, func(u *User) error { return u.ChangeEmail(addr, s.now()) })
+}
+
+// Deposit пополняет баланс на сумму вида "100.50" в валюте пользователя
+func (s *Service) Deposit(id UserID, amount string) error {
+	return s.update(id, func(u *User) error {
+		m, err := ParseMoney(amount, u.Balance().Currency())
+		if err != nil {
+			return err
+		}
+		return u.Deposit(m, s.now())
+	})
+}
+
+// Charge списывает с баланса
+func (s *Service) Charge(id UserID, amount, reason string) error {
+	return s.update(id, func(u *User) error {
+		m, err := ParseMoney(amount, u.Balance().Currency())
+		if err != nil {
+			return err
+		}
+		return u.Charge(m, reason, s.now())
+	})
+}
+
+// Deactivate отключает пользователя
+func (s *Service) Deactivate(id UserID) error {
+	return s.update(id, func(u *User) error { return u.Deactivate(s.now()) })
+}
+
+func (s *Service) update(id UserID, cmd func(*User) error) error {
+	u, err := s.users.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := cmd(u); err != nil {
+		return err
+	}
+	return s.save(u)
+}
+
+// save сохраняет агрегат и только потом публикует его события. В
+// системе с БД и брокером события записывают в таблицу outbox в той же
+// транзакции, что и агрегат, а отправляет их отдельный процесс.
+func (s *Service) save(u *User) error {
+	if err := s.users.Save(u); err != nil {
+		return err
+	}
+	s.events.Publish(u.PullEvents())
+	return nil
+}
+
+func main() {
+	events := NewDispatcher()
+	events.Subscribe("user.registered", func(e Event) {
+		fmt.Printf("  [письма] приветствие на %s\n", e.(UserRegistered).Email)
+	})
+	events.Subscribe("user.email_changed", func(e Event) {
+		ev := e.(EmailChanged)
+		fmt.Printf("  [письма] уведомление о смене адреса на старый адрес %s\n", ev.Old)
+	})
+	events.Subscribe("user.funds_deposited", func(e Event) {
+		ev := e.(FundsDeposited)
+		fmt.Printf("  [аудит] +%s, баланс %s\n", ev.Amount, ev.Balance)
+	})
+	events.Subscribe("user.funds_charged", func(e Event) {
+		ev := e.(FundsCharged)
+		fmt.Printf("  [аудит] -%s (%s), баланс %s\n", ev.Amount, ev.Reason, ev.Balance)
+	})
+	events.Subscribe("user.deactivated", func(e Event) {
+		fmt.Printf("  [аудит] пользователь %d отключен\n", e.(UserDeactivated).UserID)
+	})
+	
+	svc := &Service{users: NewUsers(), events: events, now: time.Now}
+	step := func(title string, err error) {
+		if err != nil {
+			fmt.Printf("%s: отказ: %v\n", title, err)
+			return
+		}
+		fmt.Printf("%s: ok\n", title)
+	}
+	
+	fmt.Println("=== Объекты-значения ===")
+	for _, in := range []string{"Ivan@Example.COM", "not-an-email"} {
+		e, err := ParseEmail(in)
+		fmt.Printf("ParseEmail(%q) = %q, %v\n", in, e, err)
+	}
+	a, _ := ParseMoney("10.50", "RUB")
+	b, _ := ParseMoney("0.75", "RUB")
+	sum, _ := a.Add(b)
+	fmt.Printf("%s + %s = %s\n", a, b, sum)
+	usd, _ := ParseMoney("1", "USD")
+	_, err := a.Add(usd)
+	fmt.Printf("%s + %s: %v\n", a, usd, err)
+	_, err = ParseMoney("0.001", "RUB")
+	fmt.Printf("ParseMoney(0.001 RUB): %v\n", err)
+	fmt.Println()
+	
+	fmt.Println("=== Агрегат и события ===")
+	id, err := svc.Register("Иван Иванов", "ivan@example.com", "RUB")
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = svc.Register("Двойник", "IVAN@example.com", "RUB")
+	step("Регистрация с занятым адресом", err)
+	step("Пополнение на 100.00", svc.Deposit(id, "100"))
+	step("Списание 30.50", svc.Charge(id, "30.50", "подписка"))
+	step("Списание 100.00", svc.Charge(id, "100", "покупка"))
+	step("Пополнение на -5", svc.Deposit(id, "-5"))
+	step("Смена адреса на тот же", svc.ChangeEmail(id, "Ivan@Example.com"))
+	step("Смена адреса", svc.ChangeEmail(id, "ivan@work.example.com"))
+	step("Отключение с деньгами на балансе", svc.Deactivate(id))
+	step("Списание остатка 69.50", svc.Charge(id, "69.50", "возврат"))
+	step("Отключение", svc.Deactivate(id))
+	step("Пополнение отключенного", svc.Deposit(id, "1"))
+	
+	u, _ := svc.users.Get(id)
+	fmt.Printf("Итог: %s <%s>, баланс %s, активен: %v\n", u.Name(), u.Email(), u.Balance(), u.Active())
+}
\ No newline at end of file
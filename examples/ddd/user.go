@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Агрегат - группа объектов, которая меняется только через корень и
+// после любой операции остается в согласованном состоянии. Правила
+// ("у деактивированного нельзя менять email", "нельзя списать больше,
+// чем на балансе") живут в методах User, а не в обработчиках HTTP: в
+// какой бы обработчик, воркер или тест ни попал User, обойти их нельзя.
+//
+// Для сравнения, "анемичная" модель из остальных примеров репозитория:
+//
+//	type User struct {
+//		ID      int64
+//		Email   string
+//		Balance int64
+//		Active  bool
+//	}
+//
+// Любой код может написать u.Balance -= 100 или u.Email = "", и
+// проверки приходится повторять в каждом месте, где меняется поле.
+
+// Ошибки нарушения правил
+var (
+	ErrDeactivated       = errors.New("user is deactivated")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrNonZeroBalance    = errors.New("balance must be zero to deactivate")
+	ErrNotPositive       = errors.New("amount must be positive")
+)
+
+// UserID идентичность агрегата. Отдельный тип, чтобы ID пользователя
+// нельзя было случайно передать туда, где ждут ID заказа.
+type UserID int64
+
+// Event доменное событие - факт, который уже произошел. Имена в
+// прошедшем времени.
+type Event interface {
+	EventName() string
+}
+
+// UserRegistered пользователь зарегистрирован
+type UserRegistered struct {
+	UserID   UserID
+	Email    Email
+	Currency Currency
+	At       time.Time
+}
+
+// EmailChanged пользователь сменил адрес
+type EmailChanged struct {
+	UserID   UserID
+	Old, New Email
+	At       time.Time
+}
+
+// FundsDeposited баланс пополнен
+type FundsDeposited struct {
+	UserID  UserID
+	Amount  Money
+	Balance Money // Баланс после операции
+	At      time.Time
+}
+
+// FundsCharged с баланса списано
+type FundsCharged struct {
+	UserID  UserID
+	Amount  Money
+	Balance Money
+	Reason  string
+	At      time.Time
+}
+
+// UserDeactivated пользователь отключен
+type UserDeactivated struct {
+	UserID UserID
+	At     time.Time
+}
+
+func (UserRegistered) EventName() string  { return "user.registered" }
+func (EmailChanged) EventName() string    { return "user.email_changed" }
+func (FundsDeposited) EventName() string  { return "user.funds_deposited" }
+func (FundsCharged) EventName() string    { return "user.funds_charged" }
+func (UserDeactivated) EventName() string { return "user.deactivated" }
+
+// User корень агрегата. Поля неэкспортируемые: читать можно через
+// методы, менять - только командами, которые проверяют правила.
+type User struct {
+	id      UserID
+	name    string
+	email   Email
+	balance Money
+	active  bool
+
+	events []Event // Еще не опубликованные события
+}
+
+// maxNameLength ограничение длины имени в символах
+const maxNameLength = 100
+
+// RegisterUser создает пользователя с нулевым балансом в валюте
+// currency. Email уже проверен - это гарантирует тип.
+func RegisterUser(id UserID, name string, email Email, currency Currency, now time.Time) (*User, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return nil, &ValidationError{"name", "is required"}
+	}
+	if len([]rune(name)) > maxNameLength {
+		return nil, &ValidationError{"name", fmt.Sprintf("must be at most %d characters", maxNameLength)}
+	}
+	if email.IsZero() {
+		return nil, &ValidationError{"email", "is required"}
+	}
+	balance, err := NewMoney(0, currency)
+	if err != nil {
+		return nil, err
+	}
+	
+	u := &User{id: id, name: name, email: email, balance: balance, active: true}
+	u.record(UserRegistered{UserID: id, Email: email, Currency: currency, At: now})
+	return u, nil
+}
+
+// UserSnapshot состояние для хранилища. Restore не проверяет правила и
+// не порождает событий: данные уже были проверены при сохранении.
+type UserSnapshot struct {
+	ID      UserID
+	Name    string
+	Email   Email
+	Balance Money
+	Active  bool
+}
+
+// Restore восстанавливает агрегат из хранилища
+func Restore(s UserSnapshot) *User {
+	return &User{id: s.ID, name: s.Name, email: s.Email, balance: s.Balance, active: s.Active}
+}
+
+// Snapshot возвращает состояние для хранилища
+func (u *User) Snapshot() UserSnapshot {
+	return UserSnapshot{ID: u.id, Name: u.name, Email: u.email, Balance: u.balance, Active: u.active}
+}
+
+func (u *User) ID() UserID     { return u.id }
+func (u *User) Name() string   { return u.name }
+func (u *User) Email() Email   { return u.email }
+func (u *User) Balance() Money { return u.balance }
+func (u *User) Active() bool   { return u.active }
+
+// ChangeEmail меняет адрес. Тот же адрес - не изменение, события нет.
+func (u *User) ChangeEmail(email Email, now time.Time) error {
+	if !u.active {
+		return ErrDeactivated
+	}
+	if email.IsZero() {
+		return &ValidationError{"email", "is required"}
+	}
+	if email == u.email {
+		return nil
+	}
+	old := u.email
+	u.email = email
+	u.record(EmailChanged{UserID: u.id, Old: old, New: email, At: now})
+	return nil
+}
+
+// Deposit пополняет баланс
+func (u *User) Deposit(amount Money, now time.Time) error {
+	if !u.active {
+		return ErrDeactivated
+	}
+	if !amount.IsPositive() {
+		return ErrNotPositive
+	}
+	balance, err := u.balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	u.balance = balance
+	u.record(FundsDeposited{UserID: u.id, Amount: amount, Balance: balance, At: now})
+	return nil
+}
+
+// Charge списывает с баланса; уйти в минус нельзя
+func (u *User) Charge(amount Money, reason string, now time.Time) error {
+	if !u.active {
+		return ErrDeactivated
+	}
+	if !amount.IsPositive() {
+		return ErrNotPositive
+	}
+	balance, err := u.balance.Sub(amount)
+	if err != nil {
+		return err
+	}
+	if balance.Minor() < 0 {
+		return fmt.Errorf("%w: balance %s, charge %s", ErrInsufficientFunds, u.balance, amount)
+	}
+	u.balance = balance
+	u.record(FundsCharged{UserID: u.id, Amount: amount, Balance: balance, Reason: reason, At: now})
+	return nil
+}
+
+// Deactivate отключает пользователя. С деньгами на балансе нельзя:
+// сначала их нужно вернуть, иначе они "зависнут" на отключенном аккаунте.
+func (u *User) Deactivate(now time.Time) error {
+	if !u.active {
+		return ErrDeactivated
+	}
+	if !u.balance.IsZero() {
+		return ErrNonZeroBalance
+	}
+	u.active = false
+	u.record(UserDeactivated{UserID: u.id, At: now})
+	return nil
+}
+
+func (u *User) record(e Event) {
+	u.events = append(u.events, e)
+}
+
+// PullEvents возвращает накопленные события и очищает список.
+// Вызывается после успешного сохранения агрегата: событие о том, что
+// не записалось в хранилище, публиковать нельзя.
+func (u *User) PullEvents() []Event {
+	events := u.events
+	u.events = nil
+	return events
+}
\ No newline at end of file
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// Объект-значение (value object) определяется своим значением, а не
+// идентичностью: два Email с одинаковым адресом - одно и то же. Он
+// неизменяем и проверяется один раз, в конструкторе. Поля
+// неэкспортируемые, поэтому создать неправильный Email в обход
+// ParseEmail нельзя, и функциям, принимающим Email, не нужно повторять
+// проверку. Сравниваются такие объекты обычным ==.
+
+// ValidationError неверное значение
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// Email проверенный адрес в нижнем регистре
+type Email struct {
+	addr string
+}
+
+// ParseEmail проверяет и нормализует адрес
+func ParseEmail(s string) (Email, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return Email{}, &ValidationError{"email", "is required"}
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return Email{}, &ValidationError{"email", "is malformed"}
+	}
+	return Email{addr: s}, nil
+}
+
+// String возвращает адрес
+func (e Email) String() string { return e.addr }
+
+// Domain возвращает часть после @
+func (e Email) Domain() string {
+	_, domain, _ := strings.Cut(e.addr, "@")
+	return domain
+}
+
+// IsZero сообщает, что это нулевое значение, а не проверенный адрес
+func (e Email) IsZero() bool { return e.addr == "" }
+
+// Currency код валюты ISO 4217
+type Currency string
+
+// Поддерживаемые валюты и число знаков после запятой в каждой
+var minorUnits = map[Currency]int{"RUB": 2, "USD": 2, "EUR": 2, "JPY": 0}
+
+// Ошибки денежных операций
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrOverflow         = errors.New("amount overflow")
+)
+
+// Money сумма в минимальных единицах валюты (копейках, центах). В
+// отличие от examples/bignum, где суммы - точные дроби для расчетов с
+// процентами, здесь хватает int64: баланс только складывается и
+// вычитается. Сложение разных валют - ошибка, а не молчаливый пересчет.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// NewMoney создает сумму из минимальных единиц
+func NewMoney(minor int64, currency Currency) (Money, error) {
+	if _, ok := minorUnits[currency]; !ok {
+		return Money{}, &ValidationError{"currency", fmt.Sprintf("%q is not supported", currency)}
+	}
+	return Money{amount: minor, currency: currency}, nil
+}
+
+// ParseMoney разбирает "1234.56" в валюте currency. Знаков после
+// запятой не больше, чем у валюты: 0.001 рубля не бывает.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	digits, ok := minorUnits[currency]
+	if !ok {
+		return Money{}, &ValidationError{"currency", fmt.Sprintf("%q is not supported", currency)}
+	}
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	whole, frac, _ := strings.Cut(s, ".")
+	if !isDigits(strings.TrimPrefix(whole, "-")) || (frac != "" && !isDigits(frac)) || len(frac) > digits {
+		return Money{}, &ValidationError{"amount", fmt.Sprintf("%q is malformed", s)}
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, &ValidationError{"amount", fmt.Sprintf("%q is out of range", s)}
+	}
+	return Money{amount: minor, currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor возвращает сумму в минимальных единицах
+func (m Money) Minor() int64 { return m.amount }
+
+// Currency возвращает валюту
+func (m Money) Currency() Currency { return m.currency }
+
+// IsZero сообщает, что сумма равна нулю
+func (m Money) IsZero() bool { return m.amount == 0 }
+
+// IsPositive сообщает, что сумма больше нуля
+func (m Money) IsPositive() bool { return m.amount > 0 }
+
+// Add возвращает m + o
+func (m Money) Add(o Money) (Money, error) {
+	if m.currency != o.currency {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	if (o.amount > 0 && m.amount > math.MaxInt64-o.amount) || (o.amount < 0 && m.amount < math.MinInt64-o.amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: m.amount + o.amount, currency: m.currency}, nil
+}
+
+// Sub возвращает m - o
+func (m Money) Sub(o Money) (Money, error) {
+	if o.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{amount: -o.amount, currency: o.currency})
+}
+
+// Cmp сравнивает суммы одной валюты: -1, 0 или 1
+func (m Money) Cmp(o Money) (int, error) {
+	if m.currency != o.currency {
+		return 0, fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	switch {
+	case m.amount < o.amount:
+		return -1, nil
+	case m.amount > o.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String форматирует сумму: "1234.50 RUB"
+func (m Money) String() string {
+	digits := minorUnits[m.currency]
+	if digits == 0 {
+		return fmt.Sprintf("%d %s", m.amount, m.currency)
+	}
+	sign, abs := "", m.amount
+	if abs < 0 {
+		sign = "-"
+	}
+	// Через uint64, чтобы -MinInt64 не переполнился
+	u := uint64(abs)
+	if abs < 0 {
+		u = uint64(-(abs + 1)) + 1
+	}
+	pow := uint64(math.Pow10(digits))
+	return fmt.Sprintf("%s%d.%0*d %s", sign, u/pow, digits, u%pow, m.currency)
+}
\ No newline at end of file
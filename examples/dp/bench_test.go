@@ -0,0 +1,82 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func BenchmarkKnapsack(b *testing.B) {
+	items := randomItems(rand.New(rand.NewSource(1)), 20)
+	const capacity = 50
+	b.Run("naive", func(b *testing.B) {
+		for b.Loop() {
+			KnapsackNaive(items, capacity)
+		}
+	})
+	b.Run("memo", func(b *testing.B) {
+		for b.Loop() {
+			KnapsackMemo(items, capacity)
+		}
+	})
+	b.Run("table", func(b *testing.B) {
+		for b.Loop() {
+			KnapsackTable(items, capacity)
+		}
+	})
+	b.Run("compact", func(b *testing.B) {
+		for b.Loop() {
+			KnapsackCompact(items, capacity)
+		}
+	})
+}
+
+func BenchmarkLCS(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		s := make([]byte, n)
+		for i := range s {
+			s[i] = "ACGT"[rng.Intn(4)]
+		}
+		return s
+	}
+	for _, n := range []int{10, 14} {
+		x, y := random(n), random(n)
+		b.Run("naive/"+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				LCSNaive(x, y)
+			}
+		})
+		b.Run("memo/"+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				LCSMemo(x, y)
+			}
+		})
+		b.Run("table/"+strconv.Itoa(n), func(b *testing.B) {
+			for b.Loop() {
+				LCSTable(x, y)
+			}
+		})
+	}
+}
+
+func BenchmarkCoins(b *testing.B) {
+	coins := []int{1, 5, 10, 25}
+	for _, amount := range []int{30, 60} {
+		b.Run("naive/"+strconv.Itoa(amount), func(b *testing.B) {
+			for b.Loop() {
+				CoinsNaive(coins, amount)
+			}
+		})
+		b.Run("memo/"+strconv.Itoa(amount), func(b *testing.B) {
+			for b.Loop() {
+				CoinsMemo(coins, amount)
+			}
+		})
+		b.Run("table/"+strconv.Itoa(amount), func(b *testing.B) {
+			for b.Loop() {
+				CoinsTable(coins, amount)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"slices"
+)
+
+// Размен монет: минимальное число монет для суммы amount.
+//
+//	coins(a) = 1 + min(coins(a - c)) по всем монетам c <= a
+//
+// Жадный алгоритм (брать самую крупную) оптимален не для всех наборов:
+// для {1, 3, 4} и суммы 6 он дает 4+1+1, а оптимум 3+3.
+
+// noSolution - сумму нельзя набрать данными монетами
+const noSolution = math.MaxInt
+
+// CoinsNaive - рекурсия без запоминания, экспоненциальная
+func CoinsNaive(coins []int, amount int) int {
+	if amount == 0 {
+		return 0
+	}
+	best := noSolution
+	for _, c := range coins {
+		if c <= amount {
+			if n := CoinsNaive(coins, amount-c); n != noSolution {
+				best = min(best, n+1)
+			}
+		}
+	}
+	return best
+}
+
+// CoinsMemo - рекурсия с мемоизацией, O(amount·len(coins))
+func CoinsMemo(coins []int, amount int) int {
+	memo := make([]int, amount+1)
+	for i := range memo {
+		memo[i] = -1
+	}
+	var solve func(a int) int
+	solve = func(a int) int {
+		if a == 0 {
+			return 0
+		}
+		if memo[a] >= 0 {
+			return memo[a]
+		}
+		best := noSolution
+		for _, c := range coins {
+			if c <= a {
+				if n := solve(a - c); n != noSolution {
+					best = min(best, n+1)
+				}
+			}
+		}
+		memo[a] = best
+		return best
+	}
+	return solve(amount)
+}
+
+// CoinsTable считает снизу вверх и возвращает сами монеты. Рекурсия на
+// больших суммах уходит на глубину amount, а таблица - нет.
+func CoinsTable(coins []int, amount int) (int, []int) {
+	dp := make([]int, amount+1)
+	last := make([]int, amount+1) // Последняя монета в оптимальном размене
+	for a := 1; a <= amount; a++ {
+		dp[a] = noSolution
+		for _, c := range coins {
+			if c <= a && dp[a-c] != noSolution && dp[a-c]+1 < dp[a] {
+				dp[a] = dp[a-c] + 1
+				last[a] = c
+			}
+		}
+	}
+	if dp[amount] == noSolution {
+		return noSolution, nil
+	}
+	
+	var used []int
+	for a := amount; a > 0; a -= last[a] {
+		used = append(used, last[a])
+	}
+	slices.Sort(used)
+	return dp[amount], used
+}
+
+// CoinsGreedy - жадный размен для сравнения; не всегда оптимален
+func CoinsGreedy(coins []int, amount int) (int, []int) {
+	sorted := slices.Clone(coins)
+	slices.Sort(sorted)
+	var used []int
+	for i := len(sorted) - 1; i >= 0 && amount > 0; i-- {
+		for amount >= sorted[i] {
+			amount -= sorted[i]
+			used = append(used, sorted[i])
+		}
+	}
+	if amount > 0 {
+		return noSolution, nil
+	}
+	slices.Sort(used)
+	return len(used), used
+}
\ No newline at end of file
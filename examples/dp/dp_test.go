@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func randomItems(rng *rand.Rand, n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		items[i] = Item{Weight: 1 + rng.Intn(10), Value: rng.Intn(100)}
+	}
+	return items
+}
+
+func TestKnapsackVariantsAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := range 50 {
+		items := randomItems(rng, rng.Intn(12))
+		capacity := rng.Intn(30)
+		
+		want := KnapsackNaive(items, capacity)
+		got, chosen := KnapsackTable(items, capacity)
+		if got != want || KnapsackMemo(items, capacity) != want || KnapsackCompact(items, capacity) != want {
+			t.Fatalf("Trial %d: variants disagree, naive %d", trial, want)
+		}
+		
+		weight, value := 0, 0
+		for _, it := range chosen {
+			weight += it.Weight
+			value += it.Value
+		}
+		if weight > capacity || value != want {
+			t.Errorf("Trial %d: chosen items weigh %d (cap %d) and are worth %d, want %d", trial, weight, capacity, value, want)
+		}
+	}
+}
+
+func TestLCS(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"ABCBDAB", "BDCABA", 4},
+		{"", "abc", 0},
+		{"abc", "abc", 3},
+		{"abc", "def", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			a, b := []rune(tt.a), []rune(tt.b)
+			if got := LCSNaive(a, b); got != tt.want {
+				t.Errorf("Naive: expected %d, got %d", tt.want, got)
+			}
+			if got := LCSMemo(a, b); got != tt.want {
+				t.Errorf("Memo: expected %d, got %d", tt.want, got)
+			}
+			seq := LCSTable(a, b)
+			if len(seq) != tt.want || !isSubsequence(seq, a) || !isSubsequence(seq, b) {
+				t.Errorf("Table: %q is not a common subsequence of length %d", string(seq), tt.want)
+			}
+		})
+	}
+}
+
+func isSubsequence(sub, s []rune) bool {
+	i := 0
+	for _, r := range s {
+		if i < len(sub) && sub[i] == r {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+func TestDiff(t *testing.T) {
+	got := Diff([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	want := []string{"  a", "- b", "+ x", "  c", "+ d"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestCoins(t *testing.T) {
+	tests := []struct {
+		coins  []int
+		amount int
+		want   int
+	}{
+		{[]int{1, 2, 5}, 11, 3},
+		{[]int{1, 3, 4}, 6, 2},
+		{[]int{2}, 3, noSolution},
+		{[]int{1}, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := CoinsNaive(tt.coins, tt.amount); got != tt.want {
+			t.Errorf("Naive(%v, %d): expected %d, got %d", tt.coins, tt.amount, tt.want, got)
+		}
+		if got := CoinsMemo(tt.coins, tt.amount); got != tt.want {
+			t.Errorf("Memo(%v, %d): expected %d, got %d", tt.coins, tt.amount, tt.want, got)
+		}
+		got, used := CoinsTable(tt.coins, tt.amount)
+		sum := 0
+		for _, c := range used {
+			sum += c
+		}
+		if got != tt.want || (got != noSolution && (len(used) != got || sum != tt.amount)) {
+			t.Errorf("Table(%v, %d): expected %d, got %d %v", tt.coins, tt.amount, tt.want, got, used)
+		}
+	}
+	
+	// Жадный размен проигрывает на {1, 3, 4}
+	if n, _ := CoinsGreedy([]int{1, 3, 4}, 6); n != 3 {
+		t.Errorf("Expected greedy to use 3 coins, got %d", n)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package main
+
+// Item предмет для рюкзака
+type Item struct {
+	Name   string
+	Weight int
+	Value  int
+}
+
+// Рюкзак 0/1: выбрать предметы суммарным весом не больше capacity с
+// максимальной ценностью. Подзадача: best(i, c) - лучшая ценность из
+// первых i предметов при вместимости c. Предмет i либо не берем,
+// либо берем (если влезает):
+//
+//	best(i, c) = max(best(i-1, c), best(i-1, c-w[i]) + v[i])
+
+// KnapsackNaive перебирает все 2^n подмножеств
+func KnapsackNaive(items []Item, capacity int) int {
+	var best func(i, c int) int
+	best = func(i, c int) int {
+		if i == 0 {
+			return 0
+		}
+		it := items[i-1]
+		skip := best(i-1, c)
+		if it.Weight > c {
+			return skip
+		}
+		return max(skip, best(i-1, c-it.Weight)+it.Value)
+	}
+	return best(len(items), capacity)
+}
+
+// KnapsackMemo - та же рекурсия, но каждая пара (i, c) считается один раз:
+// O(n·capacity) вместо O(2^n). Сверху вниз: решаются только подзадачи,
+// реально достижимые из исходной.
+func KnapsackMemo(items []Item, capacity int) int {
+	memo := make(map[[2]int]int)
+	var best func(i, c int) int
+	best = func(i, c int) int {
+		if i == 0 {
+			return 0
+		}
+		key := [2]int{i, c}
+		if v, ok := memo[key]; ok {
+			return v
+		}
+		it := items[i-1]
+		v := best(i-1, c)
+		if it.Weight <= c {
+			v = max(v, best(i-1, c-it.Weight)+it.Value)
+		}
+		memo[key] = v
+		return v
+	}
+	return best(len(items), capacity)
+}
+
+// KnapsackTable заполняет таблицу снизу вверх и восстанавливает набор
+// предметов. Без рекурсии и map - быстрее мемоизации на плотных задачах.
+func KnapsackTable(items []Item, capacity int) (int, []Item) {
+	n := len(items)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, capacity+1)
+	}
+	for i := 1; i <= n; i++ {
+		it := items[i-1]
+		for c := 0; c <= capacity; c++ {
+			dp[i][c] = dp[i-1][c]
+			if it.Weight <= c {
+				dp[i][c] = max(dp[i][c], dp[i-1][c-it.Weight]+it.Value)
+			}
+		}
+	}
+	
+	// Если значение изменилось при добавлении предмета i - он взят
+	var chosen []Item
+	for i, c := n, capacity; i > 0; i-- {
+		if dp[i][c] != dp[i-1][c] {
+			chosen = append(chosen, items[i-1])
+			c -= items[i-1].Weight
+		}
+	}
+	return dp[n][capacity], chosen
+}
+
+// KnapsackCompact хранит одну строку таблицы: O(capacity) памяти.
+// Вместимость перебирается по убыванию, чтобы dp[c-w] еще относился
+// к предыдущей строке и предмет не брался дважды.
+func KnapsackCompact(items []Item, capacity int) int {
+	dp := make([]int, capacity+1)
+	for _, it := range items {
+		for c := capacity; c >= it.Weight; c-- {
+			dp[c] = max(dp[c], dp[c-it.Weight]+it.Value)
+		}
+	}
+	return dp[capacity]
+}
\ No newline at end of file
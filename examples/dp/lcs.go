@@ -0,0 +1,112 @@
+package main
+
+import "slices"
+
+// Наибольшая общая подпоследовательность (LCS) - основа diff: строки,
+// не вошедшие в LCS, и есть удаленные и добавленные.
+//
+//	lcs(i, j) = lcs(i-1, j-1) + 1,              если a[i-1] == b[j-1]
+//	lcs(i, j) = max(lcs(i-1, j), lcs(i, j-1)),  иначе
+
+// LCSNaive - рекурсия без запоминания, O(2^(n+m)) в худшем случае
+func LCSNaive[T comparable](a, b []T) int {
+	var lcs func(i, j int) int
+	lcs = func(i, j int) int {
+		if i == 0 || j == 0 {
+			return 0
+		}
+		if a[i-1] == b[j-1] {
+			return lcs(i-1, j-1) + 1
+		}
+		return max(lcs(i-1, j), lcs(i, j-1))
+	}
+	return lcs(len(a), len(b))
+}
+
+// LCSMemo - рекурсия с мемоизацией в плоском слайсе, O(n·m).
+// -1 отмечает еще не вычисленные подзадачи.
+func LCSMemo[T comparable](a, b []T) int {
+	m := len(b) + 1
+	memo := make([]int, (len(a)+1)*m)
+	for i := range memo {
+		memo[i] = -1
+	}
+	var lcs func(i, j int) int
+	lcs = func(i, j int) int {
+		if i == 0 || j == 0 {
+			return 0
+		}
+		if v := memo[i*m+j]; v >= 0 {
+			return v
+		}
+		var v int
+		if a[i-1] == b[j-1] {
+			v = lcs(i-1, j-1) + 1
+		} else {
+			v = max(lcs(i-1, j), lcs(i, j-1))
+		}
+		memo[i*m+j] = v
+		return v
+	}
+	return lcs(len(a), len(b))
+}
+
+// LCSTable заполняет таблицу снизу вверх и восстанавливает саму
+// подпоследовательность обратным проходом
+func LCSTable[T comparable](a, b []T) []T {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+	
+	result := make([]T, 0, dp[len(a)][len(b)])
+	for i, j := len(a), len(b); i > 0 && j > 0; {
+		switch {
+		case a[i-1] == b[j-1]:
+			result = append(result, a[i-1])
+			i, j = i-1, j-1
+		case dp[i-1][j] >= dp[i][j-1]:
+			i--
+		default:
+			j--
+		}
+	}
+	slices.Reverse(result)
+	return result
+}
+
+// Diff строит построчный diff на основе LCS: " " - общая строка,
+// "-" - только в a, "+" - только в b
+func Diff(a, b []string) []string {
+	common := LCSTable(a, b)
+	var out []string
+	i, j := 0, 0
+	for _, line := range common {
+		for a[i] != line {
+			out = append(out, "- "+a[i])
+			i++
+		}
+		for b[j] != line {
+			out = append(out, "+ "+b[j])
+			j++
+		}
+		out = append(out, "  "+line)
+		i, j = i+1, j+1
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+ "+b[j])
+	}
+	return out
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Бенчмарки наивной рекурсии против мемоизации и таблицы:
+//   go test -bench=. -benchmem
+
+// Пример 1: Рюкзак
+func knapsack() {
+	fmt.Println("=== Рюкзак 0/1 ===")
+	
+	items := []Item{
+		{"ноутбук", 3, 2000}, {"камера", 2, 1200}, {"книга", 1, 150},
+		{"куртка", 4, 800}, {"наушники", 1, 400}, {"планшет", 2, 1100},
+	}
+	value, chosen := KnapsackTable(items, 7)
+	fmt.Println("Вместимость 7 кг, ценность:", value)
+	for _, it := range chosen {
+		fmt.Printf("  %-9s %d кг  %d\n", it.Name, it.Weight, it.Value)
+	}
+	fmt.Println("Наивно:", KnapsackNaive(items, 7), "Мемо:", KnapsackMemo(items, 7), "Одна строка:", KnapsackCompact(items, 7))
+}
+
+// Пример 2: LCS и diff
+func lcs() {
+	fmt.Println("\n=== Наибольшая общая подпоследовательность ===")
+	
+	a, b := "ABCBDAB", "BDCABA"
+	fmt.Printf("LCS(%s, %s) = %s\n", a, b, string(LCSTable([]rune(a), []rune(b))))
+	
+	before := strings.Split("func main() {\n\tx := 1\n\tfmt.Println(x)\n}", "\n")
+	after := strings.Split("func main() {\n\tx := 2\n\ty := 3\n\tfmt.Println(x)\n}", "\n")
+	fmt.Println("Diff:")
+	for _, line := range Diff(before, after) {
+		fmt.Println(" ", line)
+	}
+}
+
+// Пример 3: Размен монет
+func coins() {
+	fmt.Println("\n=== Размен монет ===")
+	
+	for _, tc := range []struct {
+		coins  []int
+		amount int
+	}{
+		{[]int{1, 2, 5, 10}, 27},
+		{[]int{1, 3, 4}, 6},
+		{[]int{5, 10}, 3},
+	} {
+		n, used := CoinsTable(tc.coins, tc.amount)
+		g, greedy := CoinsGreedy(tc.coins, tc.amount)
+		if n == noSolution {
+			fmt.Printf("%v, сумма %d: не набрать\n", tc.coins, tc.amount)
+			continue
+		}
+		fmt.Printf("%v, сумма %d: оптимум %d %v, жадно %d %v\n", tc.coins, tc.amount, n, used, g, greedy)
+	}
+}
+
+// Пример 4: Цена экспоненты
+func payoff() {
+	fmt.Println("\n=== Наивная рекурсия против DP ===")
+	
+	coinSet := []int{1, 5, 10, 25}
+	for _, amount := range []int{20, 40, 60} {
+		start := time.Now()
+		CoinsNaive(coinSet, amount)
+		naive := time.Since(start)
+		
+		start = time.Now()
+		CoinsMemo(coinSet, amount)
+		memo := time.Since(start)
+		fmt.Printf("сумма %d: наивно %-12v мемо %v\n", amount, naive.Round(time.Microsecond), memo)
+	}
+}
+
+func main() {
+	knapsack()
+	lcs()
+	coins()
+	payoff()
+}
\ No newline at end of file
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInjected ошибка, внедренная по запросу теста
+var ErrInjected = errors.New("driverfault: внедренная ошибка")
+
+// Faults сценарий сбоев. Методы безопасны для вызова из тестов
+// параллельно с запросами к БД.
+type Faults struct {
+	mu          sync.Mutex
+	badConn     int           // сколько следующих запросов вернут ErrBadConn
+	latency     time.Duration // задержка перед каждым запросом
+	failNthInTx int           // N-й запрос внутри транзакции вернет ErrInjected
+	failCommit  bool          // следующий Commit вернет ErrInjected
+
+	calls int
+}
+
+// InjectBadConn следующие n запросов вернут driver.ErrBadConn
+func (f *Faults) InjectBadConn(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.badConn = n
+}
+
+// SetLatency добавляет задержку к каждому запросу
+func (f *Faults) SetLatency(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency = d
+}
+
+// FailNthStatementInTx N-й запрос (с 1) следующей транзакции упадет
+func (f *Faults) FailNthStatementInTx(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failNthInTx = n
+}
+
+// FailNextCommit следующий Commit упадет, транзакция будет откачена
+func (f *Faults) FailNextCommit() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failCommit = true
+}
+
+// Reset отключает все сбои
+func (f *Faults) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.badConn, f.latency, f.failNthInTx, f.failCommit = 0, 0, 0, false
+}
+
+// Calls сколько запросов дошло до обертки (включая неудачные)
+func (f *Faults) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// before вызывается перед каждым запросом; txStmt - номер запроса
+// внутри транзакции или 0 вне ее
+func (f *Faults) before(ctx context.Context, txStmt int) error {
+	f.mu.Lock()
+	f.calls++
+	latency := f.latency
+	var err error
+	switch {
+	case f.badConn > 0:
+		f.badConn--
+		err = driver.ErrBadConn
+	case txStmt > 0 && txStmt == f.failNthInTx:
+		f.failNthInTx = 0
+		err = ErrInjected
+	}
+	f.mu.Unlock()
+	
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+func (f *Faults) takeCommitFailure() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	failed := f.failCommit
+	f.failCommit = false
+	return failed
+}
+
+// Driver обертка над настоящим драйвером
+type Driver struct {
+	inner  driver.Driver
+	faults *Faults
+}
+
+// Wrap оборачивает драйвер. Регистрация:
+//
+//	sql.Register("sqlite3-faulty", driverfault.Wrap(&sqlite3.SQLiteDriver{}, faults))
+func Wrap(inner driver.Driver, faults *Faults) *Driver {
+	return &Driver{inner: inner, faults: faults}
+}
+
+// Open открывает соединение настоящего драйвера и оборачивает его
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	c, err := d.inner.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{inner: c, faults: d.faults}, nil
+}
+
+// conn соединение с внедрением сбоев. Запросы через Prepare
+// (если драйвер не поддерживает ExecerContext) не перехватываются.
+type conn struct {
+	inner  driver.Conn
+	faults *Faults
+	txStmt int // 0 вне транзакции
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.inner.Prepare(query)
+}
+
+func (c *conn) Close() error {
+	return c.inner.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	var err error
+	if b, ok := c.inner.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.inner.Begin() // Запасной путь для старых драйверов
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.txStmt = 1
+	return &faultyTx{inner: tx, conn: c}, nil
+}
+
+func (c *conn) next() int {
+	if c.txStmt == 0 {
+		return 0
+	}
+	n := c.txStmt
+	c.txStmt++
+	return n
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.inner.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.faults.before(ctx, c.next()); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.inner.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.faults.before(ctx, c.next()); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+type faultyTx struct {
+	inner driver.Tx
+	conn  *conn
+}
+
+func (t *faultyTx) Commit() error {
+	t.conn.txStmt = 0
+	if t.conn.faults.takeCommitFailure() {
+		t.inner.Rollback()
+		return ErrInjected
+	}
+	return t.inner.Commit()
+}
+
+func (t *faultyTx) Rollback() error {
+	t.conn.txStmt = 0
+	return t.inner.Rollback()
+}
\ No newline at end of file
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newFaultyDB(t *testing.T) (*sql.DB, *Faults) {
+	t.Helper()
+	
+	faults := &Faults{}
+	db, err := openFaulty(filepath.Join(t.TempDir(), "test.db"), faults)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	return db, faults
+}
+
+func countRows(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	return n
+}
+
+// Пример 1: Ветка, которую без обертки не воспроизвести
+func TestWithRetry_RecoversFromBadConn(t *testing.T) {
+	db, faults := newFaultyDB(t)
+	ctx := context.Background()
+	
+	faults.InjectBadConn(6) // Больше, чем database/sql повторяет сам
+	attempts := 0
+	err := WithRetry(ctx, 5, time.Millisecond, func() error {
+		attempts++
+		_, err := db.ExecContext(ctx, `INSERT INTO users (name, email) VALUES ('A', 'a@example.com')`)
+		return err
+	})
+	
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if attempts < 2 {
+		t.Errorf("Expected WithRetry to retry, got %d attempts", attempts)
+	}
+	if n := countRows(t, db, "users"); n != 1 {
+		t.Errorf("Expected exactly 1 user, got %d", n)
+	}
+}
+
+func TestWithRetry_GivesUp(t *testing.T) {
+	db, faults := newFaultyDB(t)
+	ctx := context.Background()
+	
+	faults.InjectBadConn(1000)
+	err := WithRetry(ctx, 3, time.Millisecond, func() error {
+		_, err := db.ExecContext(ctx, `SELECT 1`)
+		return err
+	})
+	if !errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("Expected ErrBadConn, got %v", err)
+	}
+}
+
+func TestWithRetry_DoesNotRetryOtherErrors(t *testing.T) {
+	attempts := 0
+	err := WithRetry(context.Background(), 5, time.Millisecond, func() error {
+		attempts++
+		return ErrInjected
+	})
+	if !errors.Is(err, ErrInjected) || attempts != 1 {
+		t.Errorf("Expected single attempt with ErrInjected, got %d attempts, %v", attempts, err)
+	}
+}
+
+// Пример 2: Атомарность - сбой на любом шаге не оставляет половину данных
+func TestCreateUserWithAudit_Atomic(t *testing.T) {
+	tests := []struct {
+		name   string
+		inject func(f *Faults)
+	}{
+		{"first statement fails", func(f *Faults) { f.FailNthStatementInTx(1) }},
+		{"second statement fails", func(f *Faults) { f.FailNthStatementInTx(2) }},
+		{"commit fails", func(f *Faults) { f.FailNextCommit() }},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, faults := newFaultyDB(t)
+			tt.inject(faults)
+			
+			_, err := CreateUserWithAudit(context.Background(), db, "Иван", "ivan@example.com")
+			if !errors.Is(err, ErrInjected) {
+				t.Fatalf("Expected ErrInjected, got %v", err)
+			}
+			
+			if n := countRows(t, db, "users"); n != 0 {
+				t.Errorf("Expected no users after rollback, got %d", n)
+			}
+			if n := countRows(t, db, "audit_log"); n != 0 {
+				t.Errorf("Expected no audit rows after rollback, got %d", n)
+			}
+		})
+	}
+}
+
+// Пример 3: Задержка + контекст
+func TestLatencyRespectsContext(t *testing.T) {
+	db, faults := newFaultyDB(t)
+	faults.SetLatency(time.Second)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	
+	start := time.Now()
+	_, err := db.ExecContext(ctx, `SELECT 1`)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Query did not honor context deadline")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithRetry повторяет fn при ошибках разорванного соединения.
+// database/sql сам повторяет запрос после driver.ErrBadConn
+// (два раза на пуле, затем на новом соединении), поэтому сюда ошибка
+// доходит только при долгой недоступности БД.
+func WithRetry(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil || !isRetryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff * time.Duration(i+1)):
+		}
+	}
+	return fmt.Errorf("после %d попыток: %w", attempts, err)
+}
+
+func isRetryable(err error) bool {
+	return errors.Is(err, driver.ErrBadConn)
+}
+
+// WithTx выполняет fn в транзакции: Commit при успехе, Rollback при ошибке или панике
+func WithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+	
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+// CreateUserWithAudit создает пользователя и запись аудита атомарно
+func CreateUserWithAudit(ctx context.Context, db *sql.DB, name, email string) (int64, error) {
+	var id int64
+	err := WithTx(ctx, db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `INSERT INTO users (name, email) VALUES (?, ?)`, name, email)
+		if err != nil {
+			return err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO audit_log (user_id, action) VALUES (?, 'created')`, id)
+		return err
+	})
+	return id, err
+}
+
+// schema таблицы примера
+const schema = `
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	email TEXT UNIQUE NOT NULL
+);
+CREATE TABLE IF NOT EXISTS audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	action TEXT NOT NULL
+);`
\ No newline at end of file
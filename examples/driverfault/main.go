@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openFaulty открывает SQLite через обертку со сбоями.
+// Настоящий драйвер берем у обычного *sql.DB - так не нужен
+// прямой импорт типов go-sqlite3.
+func openFaulty(dsn string, faults *Faults) (*sql.DB, error) {
+	plain, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	inner := plain.Driver()
+	plain.Close()
+	
+	name := fmt.Sprintf("sqlite3-faulty-%p", faults)
+	sql.Register(name, Wrap(inner, faults))
+	return sql.Open(name, dsn)
+}
+
+func count(db *sql.DB, table string) int {
+	var n int
+	db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n)
+	return n
+}
+
+// Пример 1: ErrBadConn и автоматические повторы database/sql
+func badConnExample(db *sql.DB, faults *Faults) {
+	fmt.Println("=== ErrBadConn ===")
+	ctx := context.Background()
+	
+	faults.InjectBadConn(2)
+	_, err := db.ExecContext(ctx, `INSERT INTO users (name, email) VALUES ('A', 'a@example.com')`)
+	fmt.Printf("2 сбоя: err=%v (database/sql повторил сам)\n", err)
+	
+	faults.InjectBadConn(10)
+	_, err = db.ExecContext(ctx, `INSERT INTO users (name, email) VALUES ('B', 'b@example.com')`)
+	fmt.Printf("10 сбоев: err=%v\n", err)
+	
+	faults.InjectBadConn(5)
+	err = WithRetry(ctx, 5, 10*time.Millisecond, func() error {
+		_, err := db.ExecContext(ctx, `INSERT INTO users (name, email) VALUES ('B', 'b@example.com')`)
+		return err
+	})
+	fmt.Printf("5 сбоев + WithRetry: err=%v\n", err)
+	faults.Reset()
+}
+
+// Пример 2: Сбой посреди транзакции
+func midTransactionExample(db *sql.DB, faults *Faults) {
+	fmt.Println("\n=== Сбой посреди транзакции ===")
+	ctx := context.Background()
+	
+	before := count(db, "users")
+	faults.FailNthStatementInTx(2) // упадет INSERT в audit_log
+	_, err := CreateUserWithAudit(ctx, db, "Иван", "ivan@example.com")
+	fmt.Printf("Ошибка: %v; пользователей было %d, стало %d\n", err, before, count(db, "users"))
+	
+	faults.FailNextCommit()
+	_, err = CreateUserWithAudit(ctx, db, "Иван", "ivan@example.com")
+	fmt.Printf("Сбой commit: %v; пользователей %d\n", err, count(db, "users"))
+	
+	_, err = CreateUserWithAudit(ctx, db, "Иван", "ivan@example.com")
+	fmt.Printf("Без сбоев: %v; пользователей %d, записей аудита %d\n", err, count(db, "users"), count(db, "audit_log"))
+}
+
+// Пример 3: Задержка и таймаут контекста
+func latencyExample(db *sql.DB, faults *Faults) {
+	fmt.Println("\n=== Задержка ===")
+	
+	faults.SetLatency(200 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	
+	start := time.Now()
+	_, err := db.ExecContext(ctx, `SELECT 1`)
+	fmt.Printf("Ошибка: %v через %v\n", err, time.Since(start).Round(time.Millisecond))
+	faults.Reset()
+}
+
+func main() {
+	dir, err := os.MkdirTemp("", "driverfault")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	
+	faults := &Faults{}
+	db, err := openFaulty(filepath.Join(dir, "test.db"), faults)
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	if _, err := db.Exec(schema); err != nil {
+		log.Fatal("Ошибка инициализации БД:", err)
+	}
+	
+	badConnExample(db, faults)
+	midTransactionExample(db, faults)
+	latencyExample(db, faults)
+}
\ No newline at end of file
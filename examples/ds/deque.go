@@ -0,0 +1,96 @@
+package main
+
+// Deque двусторонняя очередь на кольцевом буфере:
+// добавление и удаление с обоих концов за O(1) амортизированно
+type Deque[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+// PushFront добавляет элемент в начало
+func (d *Deque[T]) PushFront(v T) {
+	if d.size == len(d.buf) {
+		d.grow()
+	}
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = v
+	d.size++
+}
+
+// PushBack добавляет элемент в конец
+func (d *Deque[T]) PushBack(v T) {
+	if d.size == len(d.buf) {
+		d.grow()
+	}
+	d.buf[(d.head+d.size)%len(d.buf)] = v
+	d.size++
+}
+
+// PopFront извлекает первый элемент
+func (d *Deque[T]) PopFront() (v T, ok bool) {
+	if d.size == 0 {
+		return v, false
+	}
+	v = d.buf[d.head]
+	var zero T
+	d.buf[d.head] = zero
+	d.head = (d.head + 1) % len(d.buf)
+	d.size--
+	return v, true
+}
+
+// PopBack извлекает последний элемент
+func (d *Deque[T]) PopBack() (v T, ok bool) {
+	if d.size == 0 {
+		return v, false
+	}
+	i := (d.head + d.size - 1) % len(d.buf)
+	v = d.buf[i]
+	var zero T
+	d.buf[i] = zero
+	d.size--
+	return v, true
+}
+
+// Front возвращает первый элемент без извлечения
+func (d *Deque[T]) Front() (v T, ok bool) {
+	if d.size == 0 {
+		return v, false
+	}
+	return d.buf[d.head], true
+}
+
+// Back возвращает последний элемент без извлечения
+func (d *Deque[T]) Back() (v T, ok bool) {
+	if d.size == 0 {
+		return v, false
+	}
+	return d.buf[(d.head+d.size-1)%len(d.buf)], true
+}
+
+// At возвращает i-й элемент от начала
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.size {
+		panic("deque: индекс вне диапазона")
+	}
+	return d.buf[(d.head+i)%len(d.buf)]
+}
+
+// Len возвращает число элементов
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) grow() {
+	newCap := 2 * len(d.buf)
+	if newCap == 0 {
+		newCap = 8
+	}
+	buf := make([]T, newCap)
+	for i := 0; i < d.size; i++ {
+		buf[i] = d.buf[(d.head+i)%len(d.buf)]
+	}
+	d.buf = buf
+	d.head = 0
+}
\ No newline at end of file
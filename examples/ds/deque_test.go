@@ -0,0 +1,110 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDequeBothEnds(t *testing.T) {
+	var d Deque[int]
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+	
+	for i := 0; i < 4; i++ {
+		if got := d.At(i); got != i {
+			t.Errorf("At(%d) = %d", i, got)
+		}
+	}
+	
+	if v, _ := d.PopFront(); v != 0 {
+		t.Errorf("Expected PopFront 0, got %d", v)
+	}
+	if v, _ := d.PopBack(); v != 3 {
+		t.Errorf("Expected PopBack 3, got %d", v)
+	}
+	if d.Len() != 2 {
+		t.Errorf("Expected len 2, got %d", d.Len())
+	}
+}
+
+func TestDequeEmpty(t *testing.T) {
+	var d Deque[int]
+	
+	if _, ok := d.PopFront(); ok {
+		t.Error("Expected PopFront on empty deque to fail")
+	}
+	if _, ok := d.PopBack(); ok {
+		t.Error("Expected PopBack on empty deque to fail")
+	}
+	if _, ok := d.Front(); ok {
+		t.Error("Expected Front on empty deque to fail")
+	}
+	if _, ok := d.Back(); ok {
+		t.Error("Expected Back on empty deque to fail")
+	}
+}
+
+// Случайная последовательность операций сверяется с моделью на слайсе
+func TestDequeMatchesSliceModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var d Deque[int]
+	var model []int
+	
+	for i := 0; i < 10000; i++ {
+		switch rng.Intn(4) {
+		case 0:
+			d.PushFront(i)
+			model = slices.Insert(model, 0, i)
+		case 1:
+			d.PushBack(i)
+			model = append(model, i)
+		case 2:
+			got, ok := d.PopFront()
+			if ok != (len(model) > 0) {
+				t.Fatalf("Step %d: PopFront ok=%v with model len %d", i, ok, len(model))
+			}
+			if ok {
+				if got != model[0] {
+					t.Fatalf("Step %d: PopFront %d, want %d", i, got, model[0])
+				}
+				model = model[1:]
+			}
+		case 3:
+			got, ok := d.PopBack()
+			if ok != (len(model) > 0) {
+				t.Fatalf("Step %d: PopBack ok=%v with model len %d", i, ok, len(model))
+			}
+			if ok {
+				if got != model[len(model)-1] {
+					t.Fatalf("Step %d: PopBack %d, want %d", i, got, model[len(model)-1])
+				}
+				model = model[:len(model)-1]
+			}
+		}
+		
+		if d.Len() != len(model) {
+			t.Fatalf("Step %d: len %d, want %d", i, d.Len(), len(model))
+		}
+	}
+}
+
+func TestDequeAtPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for out of range index")
+		}
+	}()
+	var d Deque[int]
+	d.At(0)
+}
+
+func TestSlidingMax(t *testing.T) {
+	got := slidingMax([]int{1, 3, -1, -3, 5, 3, 6, 7}, 3)
+	want := []int{3, 3, 5, 5, 6, 7}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
\ No newline at end of file
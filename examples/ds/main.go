@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Пример 1: Стек - проверка скобок
+func balancedBrackets(expr string) bool {
+	pairs := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	var stack Stack[rune]
+	
+	for _, r := range expr {
+		switch r {
+		case '(', '[', '{':
+			stack.Push(r)
+		case ')', ']', '}':
+			top, ok := stack.Pop()
+			if !ok || top != pairs[r] {
+				return false
+			}
+		}
+	}
+	return stack.Len() == 0
+}
+
+func stackExample() {
+	fmt.Println("=== Стек: скобки ===")
+	for _, expr := range []string{"f(a[i], {b})", "(]", "((x)", ""} {
+		fmt.Printf("%-14q сбалансировано: %v\n", expr, balancedBrackets(expr))
+	}
+}
+
+// Пример 2: Очередь - поиск в ширину по лабиринту
+func shortestPath(grid []string) int {
+	type point struct{ r, c int }
+	
+	var start, end point
+	for r, row := range grid {
+		if c := strings.IndexByte(row, 'S'); c >= 0 {
+			start = point{r, c}
+		}
+		if c := strings.IndexByte(row, 'E'); c >= 0 {
+			end = point{r, c}
+		}
+	}
+	
+	dist := map[point]int{start: 0}
+	var queue Queue[point]
+	queue.Enqueue(start)
+	
+	for queue.Len() > 0 {
+		p, _ := queue.Dequeue()
+		if p == end {
+			return dist[p]
+		}
+		for _, d := range []point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			n := point{p.r + d.r, p.c + d.c}
+			if n.r < 0 || n.r >= len(grid) || n.c < 0 || n.c >= len(grid[n.r]) || grid[n.r][n.c] == '#' {
+				continue
+			}
+			if _, seen := dist[n]; !seen {
+				dist[n] = dist[p] + 1
+				queue.Enqueue(n)
+			}
+		}
+	}
+	return -1
+}
+
+func queueExample() {
+	fmt.Println("\n=== Очередь: BFS ===")
+	grid := []string{
+		"S.#.....",
+		".##.###.",
+		"....#...",
+		"##.##.#.",
+		"......#E",
+	}
+	for _, row := range grid {
+		fmt.Println(row)
+	}
+	fmt.Println("Кратчайший путь:", shortestPath(grid))
+}
+
+// Пример 3: Дек - максимум в скользящем окне за O(n).
+// В деке хранятся индексы по убыванию значений: голова - текущий максимум.
+func slidingMax(nums []int, k int) []int {
+	var window Deque[int]
+	var result []int
+	
+	for i, v := range nums {
+		// Голова вышла за пределы окна
+		if front, ok := window.Front(); ok && front <= i-k {
+			window.PopFront()
+		}
+		// Меньшие элементы уже никогда не станут максимумом
+		for {
+			back, ok := window.Back()
+			if !ok || nums[back] > v {
+				break
+			}
+			window.PopBack()
+		}
+		window.PushBack(i)
+		
+		if i >= k-1 {
+			front, _ := window.Front()
+			result = append(result, nums[front])
+		}
+	}
+	return result
+}
+
+func dequeExample() {
+	fmt.Println("\n=== Дек: максимум в окне ===")
+	nums := []int{1, 3, -1, -3, 5, 3, 6, 7}
+	fmt.Printf("%v, окно 3 -> %v\n", nums, slidingMax(nums, 3))
+}
+
+// Пример 4: Множество - права пользователей
+func setExample() {
+	fmt.Println("\n=== Множество ===")
+	
+	editor := NewSet("read", "write", "comment")
+	moderator := NewSet("read", "comment", "delete", "ban")
+	
+	fmt.Println("Объединение:", Sorted(editor.Union(moderator)))
+	fmt.Println("Пересечение:", Sorted(editor.Intersection(moderator)))
+	fmt.Println("Только у редактора:", Sorted(editor.Difference(moderator)))
+	
+	// Дедупликация
+	seen := NewSet[string]()
+	for _, email := range []string{"a@x.io", "b@x.io", "a@x.io", "c@x.io", "b@x.io"} {
+		if !seen.Add(email) {
+			fmt.Println("Дубликат:", email)
+		}
+	}
+}
+
+// Пример 5: Очередь задач для пула воркеров.
+// Канал - естественная очередь между горутинами, но у него фиксированная
+// емкость. Queue под мьютексом с sync.Cond - неограниченный буфер:
+// продюсер никогда не блокируется.
+type TaskQueue[T any] struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  Queue[T]
+	closed bool
+}
+
+func NewTaskQueue[T any]() *TaskQueue[T] {
+	q := &TaskQueue[T]{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *TaskQueue[T]) Put(v T) {
+	q.mu.Lock()
+	q.items.Enqueue(v)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// Get блокируется до появления задачи; ok == false после Close и опустошения
+func (q *TaskQueue[T]) Get() (v T, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.items.Len() == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	return q.items.Dequeue()
+}
+
+func (q *TaskQueue[T]) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+func workerPoolExample() {
+	fmt.Println("\n=== Очередь для пула воркеров ===")
+	
+	tasks := NewTaskQueue[int]()
+	for i := 1; i <= 10; i++ {
+		tasks.Put(i)
+	}
+	tasks.Close()
+	
+	var mu sync.Mutex
+	results := NewSet[int]()
+	var wg sync.WaitGroup
+	for w := 0; w < 3; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n, ok := tasks.Get()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				results.Add(n * n)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println("Квадраты:", Sorted(results))
+}
+
+func main() {
+	stackExample()
+	queueExample()
+	dequeExample()
+	setExample()
+	workerPoolExample()
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package main
+
+// Queue очередь (FIFO) на кольцевом буфере.
+//
+// Наивная очередь q = q[1:] не освобождает начало массива: память
+// возвращается только при следующем росте через append. Кольцевой
+// буфер переиспользует освободившиеся слоты.
+type Queue[T any] struct {
+	buf  []T
+	head int // Индекс первого элемента
+	size int
+}
+
+// Enqueue добавляет элемент в конец
+func (q *Queue[T]) Enqueue(v T) {
+	if q.size == len(q.buf) {
+		q.grow()
+	}
+	q.buf[(q.head+q.size)%len(q.buf)] = v
+	q.size++
+}
+
+// Dequeue извлекает элемент из начала; ok == false для пустой очереди
+func (q *Queue[T]) Dequeue() (v T, ok bool) {
+	if q.size == 0 {
+		return v, false
+	}
+	v = q.buf[q.head]
+	var zero T
+	q.buf[q.head] = zero
+	q.head = (q.head + 1) % len(q.buf)
+	q.size--
+	return v, true
+}
+
+// Peek возвращает первый элемент, не извлекая его
+func (q *Queue[T]) Peek() (v T, ok bool) {
+	if q.size == 0 {
+		return v, false
+	}
+	return q.buf[q.head], true
+}
+
+// Len возвращает число элементов
+func (q *Queue[T]) Len() int {
+	return q.size
+}
+
+// grow удваивает буфер, раскладывая элементы по порядку с нуля
+func (q *Queue[T]) grow() {
+	newCap := 2 * len(q.buf)
+	if newCap == 0 {
+		newCap = 8
+	}
+	buf := make([]T, newCap)
+	for i := 0; i < q.size; i++ {
+		buf[i] = q.buf[(q.head+i)%len(q.buf)]
+	}
+	q.buf = buf
+	q.head = 0
+}
\ No newline at end of file
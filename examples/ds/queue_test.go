@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func TestQueueFIFO(t *testing.T) {
+	var q Queue[int]
+	for i := 0; i < 100; i++ {
+		q.Enqueue(i)
+	}
+	
+	if q.Len() != 100 {
+		t.Fatalf("Expected len 100, got %d", q.Len())
+	}
+	for want := 0; want < 100; want++ {
+		got, ok := q.Dequeue()
+		if !ok || got != want {
+			t.Fatalf("Expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+	if _, ok := q.Dequeue(); ok {
+		t.Error("Expected Dequeue on empty queue to fail")
+	}
+}
+
+func TestQueueWrapAround(t *testing.T) {
+	var q Queue[int]
+	next, want := 0, 0
+	
+	// Чередуем добавление и извлечение, чтобы голова обошла буфер по кругу
+	// несколько раз, в том числе во время роста
+	for round := 0; round < 50; round++ {
+		for i := 0; i < 5; i++ {
+			q.Enqueue(next)
+			next++
+		}
+		for i := 0; i < 3; i++ {
+			got, _ := q.Dequeue()
+			if got != want {
+				t.Fatalf("Round %d: expected %d, got %d", round, want, got)
+			}
+			want++
+		}
+	}
+	
+	if q.Len() != next-want {
+		t.Errorf("Expected len %d, got %d", next-want, q.Len())
+	}
+	if front, _ := q.Peek(); front != want {
+		t.Errorf("Expected Peek %d, got %d", want, front)
+	}
+}
+
+func TestQueueReusesBuffer(t *testing.T) {
+	var q Queue[int]
+	for i := 0; i < 8; i++ {
+		q.Enqueue(i)
+	}
+	capacity := len(q.buf)
+	
+	for i := 0; i < 1000; i++ {
+		q.Dequeue()
+		q.Enqueue(i)
+	}
+	
+	if len(q.buf) != capacity {
+		t.Errorf("Expected buffer to stay at %d, got %d", capacity, len(q.buf))
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	tests := []struct {
+		name string
+		grid []string
+		want int
+	}{
+		{"straight", []string{"S..E"}, 3},
+		{"around wall", []string{"S#E", "..."}, 4},
+		{"blocked", []string{"S#E"}, -1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortestPath(tt.grid); got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Set множество на map с пустыми значениями: struct{} не занимает памяти
+type Set[T comparable] struct {
+	m map[T]struct{}
+}
+
+// NewSet создает множество из перечисленных элементов
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{m: make(map[T]struct{}, len(items))}
+	for _, v := range items {
+		s.m[v] = struct{}{}
+	}
+	return s
+}
+
+// Add добавляет элемент; возвращает false, если он уже был
+func (s *Set[T]) Add(v T) bool {
+	if s.m == nil {
+		s.m = make(map[T]struct{})
+	}
+	if _, ok := s.m[v]; ok {
+		return false
+	}
+	s.m[v] = struct{}{}
+	return true
+}
+
+// Remove удаляет элемент
+func (s *Set[T]) Remove(v T) {
+	delete(s.m, v)
+}
+
+// Contains проверяет наличие элемента
+func (s *Set[T]) Contains(v T) bool {
+	_, ok := s.m[v]
+	return ok
+}
+
+// Len возвращает число элементов
+func (s *Set[T]) Len() int {
+	return len(s.m)
+}
+
+// All перебирает элементы в произвольном порядке
+func (s *Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s.m)
+}
+
+// Union возвращает объединение множеств
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for v := range s.m {
+		result.m[v] = struct{}{}
+	}
+	for v := range other.m {
+		result.m[v] = struct{}{}
+	}
+	return result
+}
+
+// Intersection возвращает пересечение; перебирается меньшее из множеств
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	result := NewSet[T]()
+	for v := range small.m {
+		if large.Contains(v) {
+			result.m[v] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference возвращает элементы s, которых нет в other
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for v := range s.m {
+		if !other.Contains(v) {
+			result.m[v] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Sorted возвращает элементы по возрастанию - для стабильного вывода
+func Sorted[T cmp.Ordered](s *Set[T]) []T {
+	return slices.Sorted(s.All())
+}
\ No newline at end of file
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSetBasics(t *testing.T) {
+	s := NewSet(1, 2, 2, 3)
+	
+	if s.Len() != 3 {
+		t.Errorf("Expected len 3, got %d", s.Len())
+	}
+	if !s.Add(4) {
+		t.Error("Expected Add of new element to return true")
+	}
+	if s.Add(4) {
+		t.Error("Expected Add of existing element to return false")
+	}
+	
+	s.Remove(1)
+	if s.Contains(1) {
+		t.Error("Expected 1 to be removed")
+	}
+	if got := Sorted(s); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("Expected [2 3 4], got %v", got)
+	}
+}
+
+func TestSetZeroValue(t *testing.T) {
+	var s Set[string]
+	
+	if s.Contains("x") {
+		t.Error("Expected empty set")
+	}
+	s.Add("x")
+	if !s.Contains("x") {
+		t.Error("Expected zero value set to accept Add")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+	
+	tests := []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"intersection", a.Intersection(b), []int{3, 4}},
+		{"intersection reversed", b.Intersection(a), []int{3, 4}},
+		{"difference", a.Difference(b), []int{1, 2}},
+		{"difference reversed", b.Difference(a), []int{5}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sorted(tt.got); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+	
+	// Операции не меняют исходные множества
+	if a.Len() != 4 || b.Len() != 3 {
+		t.Error("Expected operands to be unchanged")
+	}
+}
+
+func TestTaskQueue(t *testing.T) {
+	q := NewTaskQueue[int]()
+	
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	got := NewSet[int]()
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, ok := q.Get()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				got.Add(v)
+				mu.Unlock()
+			}
+		}()
+	}
+	
+	// Задачи добавляются, пока воркеры уже ждут
+	for i := 0; i < 1000; i++ {
+		q.Put(i)
+	}
+	q.Close()
+	wg.Wait()
+	
+	if got.Len() != 1000 {
+		t.Errorf("Expected 1000 tasks processed, got %d", got.Len())
+	}
+}
\ No newline at end of file
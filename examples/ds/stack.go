@@ -0,0 +1,40 @@
+package main
+
+// Stack стек (LIFO) поверх слайса.
+// Нулевое значение готово к использованию.
+type Stack[T any] struct {
+	items []T
+}
+
+// Push кладет элемент на вершину
+func (s *Stack[T]) Push(v T) {
+	s.items = append(s.items, v)
+}
+
+// Pop снимает элемент с вершины; ok == false для пустого стека
+func (s *Stack[T]) Pop() (v T, ok bool) {
+	if len(s.items) == 0 {
+		return v, false
+	}
+	last := len(s.items) - 1
+	v = s.items[last]
+	
+	// Обнуляем слот, чтобы слайс не удерживал объект от сборки мусора
+	var zero T
+	s.items[last] = zero
+	s.items = s.items[:last]
+	return v, true
+}
+
+// Peek возвращает вершину, не снимая ее
+func (s *Stack[T]) Peek() (v T, ok bool) {
+	if len(s.items) == 0 {
+		return v, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Len возвращает число элементов
+func (s *Stack[T]) Len() int {
+	return len(s.items)
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func TestStackLIFO(t *testing.T) {
+	var s Stack[int]
+	for i := 1; i <= 3; i++ {
+		s.Push(i)
+	}
+	
+	if top, _ := s.Peek(); top != 3 {
+		t.Errorf("Expected Peek 3, got %d", top)
+	}
+	
+	for want := 3; want >= 1; want-- {
+		got, ok := s.Pop()
+		if !ok || got != want {
+			t.Errorf("Expected Pop %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+	
+	if s.Len() != 0 {
+		t.Errorf("Expected empty stack, got len %d", s.Len())
+	}
+}
+
+func TestStackEmpty(t *testing.T) {
+	var s Stack[string]
+	
+	if _, ok := s.Pop(); ok {
+		t.Error("Expected Pop on empty stack to fail")
+	}
+	if _, ok := s.Peek(); ok {
+		t.Error("Expected Peek on empty stack to fail")
+	}
+}
+
+func TestStackReleasesReferences(t *testing.T) {
+	var s Stack[*int]
+	v := 42
+	s.Push(&v)
+	s.Pop()
+	
+	// Слот под вершиной должен быть обнулен
+	if s.items[:1][0] != nil {
+		t.Error("Expected popped slot to be cleared")
+	}
+}
+
+func TestBalancedBrackets(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{"()[]{}", true},
+		{"f(a[i], {b})", true},
+		{"(]", false},
+		{"((x)", false},
+		{")(", false},
+	}
+	
+	for _, tt := range tests {
+		if got := balancedBrackets(tt.expr); got != tt.want {
+			t.Errorf("balancedBrackets(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// go test -bench=. -benchtime=3x (наивная версия на 10000 элементов медленная)
+// Каждая итерация: n объединений и 4n случайных Find. Порядок "chain"
+// вырождает наивное дерево в список; на "random" ранг сам по себе дает
+// деревья высотой до log n, и выигрыш добавляет сжатие путей.
+
+type unionFinder interface {
+	Find(x int) int
+	Union(x, y int) bool
+}
+
+func benchDSU(b *testing.B, n int, random bool, newDSU func(int) unionFinder) {
+	rng := rand.New(rand.NewSource(1))
+	pairs := make([][2]int, n)
+	for i := range pairs {
+		if random {
+			pairs[i] = [2]int{rng.Intn(n), rng.Intn(n)}
+		} else {
+			pairs[i] = [2]int{i, (i + 1) % n}
+		}
+	}
+	queries := rng.Perm(n)
+	
+	for b.Loop() {
+		d := newDSU(n)
+		for _, p := range pairs {
+			d.Union(p[0], p[1])
+		}
+		for range 4 {
+			for _, q := range queries {
+				d.Find(q)
+			}
+		}
+	}
+}
+
+// Ранг без сжатия путей: высота не больше log n
+type rankOnlyDSU struct {
+	*DSU
+}
+
+func (d rankOnlyDSU) Find(x int) int {
+	for d.parent[x] != x {
+		x = d.parent[x]
+	}
+	return x
+}
+
+func (d rankOnlyDSU) Union(x, y int) bool {
+	rx, ry := d.Find(x), d.Find(y)
+	if rx == ry {
+		return false
+	}
+	if d.rank[rx] < d.rank[ry] {
+		rx, ry = ry, rx
+	}
+	d.parent[ry] = rx
+	if d.rank[rx] == d.rank[ry] {
+		d.rank[rx]++
+	}
+	return true
+}
+
+func BenchmarkDSU(b *testing.B) {
+	variants := []struct {
+		name string
+		new  func(int) unionFinder
+	}{
+		{"naive", func(n int) unionFinder { return newNaiveDSU(n) }},
+		{"rank", func(n int) unionFinder { return rankOnlyDSU{NewDSU(n)} }},
+		{"rank+compression", func(n int) unionFinder { return NewDSU(n) }},
+	}
+	for _, order := range []string{"chain", "random"} {
+		for _, n := range []int{1000, 10000} {
+			for _, v := range variants {
+				b.Run(order+"/"+strconv.Itoa(n)+"/"+v.name, func(b *testing.B) {
+					benchDSU(b, n, order == "random", v.new)
+				})
+			}
+		}
+	}
+}
\ No newline at end of file
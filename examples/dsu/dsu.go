@@ -0,0 +1,113 @@
+package main
+
+// DSU система непересекающихся множеств (union-find) над элементами 0..n-1.
+// Каждое множество - дерево, корень - его представитель. Две оптимизации
+// вместе дают почти O(1) на операцию (обратная функция Аккермана α(n) ≤ 4
+// для любых реальных n):
+//   - объединение по рангу: меньшее дерево подвешивается к большему,
+//     высота растет только при равных рангах;
+//   - сжатие путей: Find перевешивает пройденные узлы прямо на корень.
+type DSU struct {
+	parent []int
+	rank   []uint8 // Ранг - верхняя оценка высоты, больше 64 не бывает
+	count  int     // Число множеств
+}
+
+// NewDSU создает n одноэлементных множеств
+func NewDSU(n int) *DSU {
+	d := &DSU{parent: make([]int, n), rank: make([]uint8, n), count: n}
+	for i := range d.parent {
+		d.parent[i] = i
+	}
+	return d
+}
+
+// Find возвращает представителя множества x
+func (d *DSU) Find(x int) int {
+	root := x
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+	// Второй проход: сжатие путей без рекурсии
+	for d.parent[x] != root {
+		d.parent[x], x = root, d.parent[x]
+	}
+	return root
+}
+
+// Union объединяет множества x и y; false, если они уже совпадали
+func (d *DSU) Union(x, y int) bool {
+	rx, ry := d.Find(x), d.Find(y)
+	if rx == ry {
+		return false
+	}
+	switch {
+	case d.rank[rx] < d.rank[ry]:
+		d.parent[rx] = ry
+	case d.rank[rx] > d.rank[ry]:
+		d.parent[ry] = rx
+	default:
+		d.parent[ry] = rx
+		d.rank[rx]++
+	}
+	d.count--
+	return true
+}
+
+// Connected проверяет, лежат ли x и y в одном множестве
+func (d *DSU) Connected(x, y int) bool {
+	return d.Find(x) == d.Find(y)
+}
+
+// Count возвращает число множеств
+func (d *DSU) Count() int {
+	return d.count
+}
+
+// Groups возвращает множества как списки элементов
+func (d *DSU) Groups() [][]int {
+	index := make(map[int]int)
+	var groups [][]int
+	for x := range d.parent {
+		root := d.Find(x)
+		i, ok := index[root]
+		if !ok {
+			i = len(groups)
+			index[root] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], x)
+	}
+	return groups
+}
+
+// naiveDSU без оптимизаций: Union подвешивает первый корень ко второму,
+// Find идет по цепочке. На неудачном порядке объединений деревья
+// вырождаются в списки и Find становится O(n).
+type naiveDSU struct {
+	parent []int
+}
+
+func newNaiveDSU(n int) *naiveDSU {
+	d := &naiveDSU{parent: make([]int, n)}
+	for i := range d.parent {
+		d.parent[i] = i
+	}
+	return d
+}
+
+func (d *naiveDSU) Find(x int) int {
+	for d.parent[x] != x {
+		x = d.parent[x]
+	}
+	return x
+}
+
+func (d *naiveDSU) Union(x, y int) bool {
+	rx, ry := d.Find(x), d.Find(y)
+	if rx == ry {
+		return false
+	}
+	d.parent[rx] = ry
+	return true
+}
\ No newline at end of file
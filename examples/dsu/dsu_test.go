@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Сверка с наивной реализацией на случайных операциях
+func TestDSUMatchesNaive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 200
+	d := NewDSU(n)
+	naive := newNaiveDSU(n)
+	sets := n
+	
+	for step := 0; step < 5000; step++ {
+		x, y := rng.Intn(n), rng.Intn(n)
+		if rng.Intn(2) == 0 {
+			merged := d.Union(x, y)
+			if merged != naive.Union(x, y) {
+				t.Fatalf("Step %d: Union(%d, %d) mismatch", step, x, y)
+			}
+			if merged {
+				sets--
+			}
+		} else if d.Connected(x, y) != (naive.Find(x) == naive.Find(y)) {
+			t.Fatalf("Step %d: Connected(%d, %d) mismatch", step, x, y)
+		}
+	}
+	if d.Count() != sets {
+		t.Errorf("Expected %d sets, got %d", sets, d.Count())
+	}
+}
+
+func TestDSURankBoundsHeight(t *testing.T) {
+	const n = 1 << 12
+	d := NewDSU(n)
+	// Объединение цепочкой - худший случай для наивной версии
+	for i := 1; i < n; i++ {
+		d.Union(i-1, i)
+	}
+	// Ранг корня не превышает log2(n)
+	if r := d.rank[d.Find(0)]; r > 12 {
+		t.Errorf("Expected rank <= 12, got %d", r)
+	}
+	if d.Count() != 1 {
+		t.Errorf("Expected single set, got %d", d.Count())
+	}
+}
+
+func TestMergeAccounts(t *testing.T) {
+	accounts := []Account{
+		{ID: 1, Emails: []string{"a@x"}},
+		{ID: 2, Emails: []string{"b@x"}, Phone: "555"},
+		{ID: 3, Emails: []string{"A@X"}, Phone: "555"}, // Связывает 1 и 2
+		{ID: 4, Emails: []string{"c@x"}},
+	}
+	got := mergeAccounts(accounts)
+	want := [][]int{{1, 2, 3}, {4}}
+	if !slices.EqualFunc(got, want, slices.Equal[[]int]) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Пример 1: Компоненты связности
+func components() {
+	fmt.Println("=== Компоненты связности ===")
+	
+	// Серверы и сетевые линки между ними: какие группы видят друг друга?
+	servers := []string{"api-1", "api-2", "db-1", "db-2", "cache-1", "queue-1", "worker-1", "worker-2"}
+	links := [][2]int{{0, 1}, {0, 2}, {2, 3}, {1, 4}, {5, 6}, {6, 7}}
+	
+	d := NewDSU(len(servers))
+	for _, l := range links {
+		d.Union(l[0], l[1])
+	}
+	
+	fmt.Println("Число сегментов сети:", d.Count())
+	for i, g := range d.Groups() {
+		names := make([]string, len(g))
+		for j, x := range g {
+			names[j] = servers[x]
+		}
+		fmt.Printf("  сегмент %d: %s\n", i+1, strings.Join(names, ", "))
+	}
+	fmt.Println("api-1 видит worker-1:", d.Connected(0, 6))
+	
+	// Линк, замыкающий цикл, ничего не объединяет - так Краскал
+	// отбрасывает ребра при построении остовного дерева
+	fmt.Println("Линк api-2 <-> db-2 нужен:", d.Union(1, 3))
+}
+
+// Account учетная запись пользователя
+type Account struct {
+	ID     int
+	Emails []string
+	Phone  string
+}
+
+// mergeAccounts склеивает дубликаты: записи с общим email или телефоном
+// принадлежат одному человеку, причем связь транзитивна (A~B по email,
+// B~C по телефону => A~C)
+func mergeAccounts(accounts []Account) [][]int {
+	d := NewDSU(len(accounts))
+	owner := make(map[string]int) // Контакт -> первая запись с ним
+	
+	link := func(contact string, i int) {
+		if contact == "" {
+			return
+		}
+		contact = strings.ToLower(contact)
+		if j, ok := owner[contact]; ok {
+			d.Union(i, j)
+		} else {
+			owner[contact] = i
+		}
+	}
+	for i, a := range accounts {
+		for _, e := range a.Emails {
+			link(e, i)
+		}
+		link(a.Phone, i)
+	}
+	
+	var result [][]int
+	for _, g := range d.Groups() {
+		ids := make([]int, len(g))
+		for j, x := range g {
+			ids[j] = accounts[x].ID
+		}
+		result = append(result, ids)
+	}
+	return result
+}
+
+// Пример 2: Дедупликация учетных записей
+func deduplication() {
+	fmt.Println("\n=== Дедупликация учетных записей ===")
+	
+	accounts := []Account{
+		{ID: 101, Emails: []string{"ivan@example.com"}, Phone: "+7-900-000-00-01"},
+		{ID: 102, Emails: []string{"maria@example.com"}},
+		{ID: 103, Emails: []string{"i.ivanov@work.example"}, Phone: "+7-900-000-00-01"},
+		{ID: 104, Emails: []string{"I.Ivanov@work.example", "vanya@mail.example"}},
+		{ID: 105, Emails: []string{"petr@example.com"}},
+		{ID: 106, Emails: []string{"maria.p@example.com", "maria@example.com"}},
+	}
+	
+	for _, g := range mergeAccounts(accounts) {
+		fmt.Println("  человек:", g)
+	}
+}
+
+func main() {
+	components()
+	deduplication()
+}
\ No newline at end of file
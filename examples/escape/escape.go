@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Пары функций: первая отправляет значение в кучу, вторая оставляет его
+// на стеке. Сообщения компилятора (go build -gcflags=-m) приведены у
+// каждой функции - так их и нужно читать: "escapes to heap" и
+// "moved to heap" - выделение в куче, "does not escape" - нет.
+//
+// //go:noinline нужен только для наглядности: после встраивания в
+// вызывающую функцию анализ идет заново, и часть "убегающих" значений
+// снова оказывается на стеке. В настоящем коде его не ставят.
+
+// point небольшая структура, которую дешево копировать
+type point struct {
+	X, Y, Z float64
+}
+
+// Пара 1: вернуть указатель или значение.
+//
+// newPointPtr: "moved to heap: p" - указатель переживает функцию, и p
+// не может жить в ее кадре стека.
+//
+//go:noinline
+func newPointPtr(x, y, z float64) *point {
+	p := point{x, y, z}
+	return &p
+}
+
+// newPoint: значение копируется в кадр вызывающего, выделений нет.
+// Копия 24 байт дешевле выделения и работы сборщика мусора; указатель
+// оправдан для больших структур или когда нужна общая изменяемая копия.
+//
+//go:noinline
+func newPoint(x, y, z float64) point {
+	return point{x, y, z}
+}
+
+// Пара 2: значение уходит в interface.
+//
+// appendAny: "v escapes to heap" - fmt принимает ...any, и компилятор не
+// может доказать, что fmt не сохранит аргумент: v упаковывается в
+// interface в куче. Так же ведут себя fmt.Sprint, log.Printf, errors
+// с %v и все функции с ...any.
+//
+//go:noinline
+func appendAny(dst []byte, v int) []byte {
+	return fmt.Appendf(dst, "%d", v)
+}
+
+// appendInt: конкретный тип, упаковки нет - ни одного выделения
+//
+//go:noinline
+func appendInt(dst []byte, v int) []byte {
+	return strconv.AppendInt(dst, int64(v), 10)
+}
+
+// Пара 3: кто владеет буфером.
+//
+// hexNew: "make([]byte, 0, 2 * len(data)) escapes to heap" - буфер
+// возвращается вызывающему и переживает функцию.
+//
+//go:noinline
+func hexNew(data []byte) []byte {
+	out := make([]byte, 0, 2*len(data))
+	return appendHex(out, data)
+}
+
+// appendHex: "leaking param: dst to result" - dst только возвращается,
+// поэтому буфер выбирает вызывающий: var buf [64]byte на стеке, и
+// выделений нет, пока результат помещается в него. Это соглашение
+// strconv.AppendInt, fmt.Appendf и time.AppendFormat.
+//
+//go:noinline
+func appendHex(dst, data []byte) []byte {
+	const digits = "0123456789abcdef"
+	for _, b := range data {
+		dst = append(dst, digits[b>>4], digits[b&15])
+	}
+	return dst
+}
+
+// Пара 4: функция-параметр, которую сохраняют или только вызывают.
+//
+// register: "leaking param: fn" - fn сохраняется в глобальном срезе, и
+// замыкание у вызывающего ("func literal escapes to heap") выделяется в
+// куче вместе с захваченными переменными.
+var hooks []func(int) int
+
+//go:noinline
+func register(fn func(int) int) {
+	hooks = append(hooks, fn)
+}
+
+// apply: "fn does not escape" - fn только вызывается, замыкание и то,
+// что оно захватило, остаются на стеке вызывающего
+//
+//go:noinline
+func apply(fn func(int) int, v int) int {
+	return fn(v)
+}
\ No newline at end of file
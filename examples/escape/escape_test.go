@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+// Тесты на выделения фиксируют решение компилятора: если правка
+// отправит значение в кучу, упадет тест, а не тихо просядет сервис
+
+func TestPairs_StackNoAllocs(t *testing.T) {
+	for _, p := range pairs() {
+		t.Run(p.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, p.stack); allocs != 0 {
+				t.Errorf("Expected 0 allocations, got %.0f", allocs)
+			}
+			if allocs := testing.AllocsPerRun(100, p.heap); allocs == 0 {
+				t.Error("Expected heap variant to allocate")
+			}
+		})
+	}
+}
+
+func TestPairs_SameResult(t *testing.T) {
+	var buf [64]byte
+	if got := *newPointPtr(1, 2, 3); got != newPoint(1, 2, 3) {
+		t.Errorf("Expected %v, got %v", newPoint(1, 2, 3), got)
+	}
+	for _, v := range []int{0, -7, 1234567} {
+		if a, b := appendAny(buf[:0], v), appendInt(nil, v); !bytes.Equal(a, b) {
+			t.Errorf("Expected %s, got %s", b, a)
+		}
+	}
+	data := []byte{0x00, 0x7f, 0xab, 0xff}
+	if a, b := hexNew(data), appendHex(buf[:0], data); string(a) != "007fabff" || !bytes.Equal(a, b) {
+		t.Errorf("Expected 007fabff, got %s and %s", a, b)
+	}
+}
+
+func TestPool_SameTotal(t *testing.T) {
+	const n = 1000
+	// sum(i) + sum(i) по Payload[0]
+	expected := int64(n * (n - 1))
+	for _, workers := range []int{1, 4} {
+		if got := runPoolPtr(workers, n); got != expected {
+			t.Errorf("runPoolPtr(%d): expected %d, got %d", workers, expected, got)
+		}
+		if got := runPoolValue(workers, n); got != expected {
+			t.Errorf("runPoolValue(%d): expected %d, got %d", workers, expected, got)
+		}
+	}
+}
+
+func TestPool_ValueNoAllocsPerTask(t *testing.T) {
+	// Горутины, канал и WaitGroup - несколько выделений на запуск, но не на задачу
+	allocs := testing.AllocsPerRun(10, func() { runPoolValue(4, 10000) })
+	if allocs > 50 {
+		t.Errorf("Expected allocations independent of task count, got %.0f", allocs)
+	}
+}
+
+type recorder struct {
+	discardResponse
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(p []byte) (int, error) { return r.body.Write(p) }
+
+func serve(h http.HandlerFunc, body string) *recorder {
+	req, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+	req.Body = &bodyReader{data: []byte(body)}
+	w := &recorder{discardResponse: discardResponse{header: http.Header{}}}
+	h(w, req)
+	return w
+}
+
+func TestOrderHandlers_SameResponse(t *testing.T) {
+	body := `{"id": 4242, "items": [100, 250, 75]}`
+	slow, fast := serve(orderHandlerSlow, body), serve(orderHandlerFast, body)
+	if fast.body.String() != "{\"id\":4242,\"total\":425}\n" {
+		t.Errorf("Unexpected body %q", fast.body.String())
+	}
+	if slow.body.String() != fast.body.String() {
+		t.Errorf("Expected %q, got %q", fast.body.String(), slow.body.String())
+	}
+	if got := fast.header.Get("X-Order-Total"); got != "425" || slow.header.Get("X-Order-Total") != got {
+		t.Errorf("Expected X-Order-Total 425, got %q and %q", got, slow.header.Get("X-Order-Total"))
+	}
+}
+
+func TestOrderHandlers_FewerAllocs(t *testing.T) {
+	body := []byte(`{"id": 4242, "items": [100, 250, 75]}`)
+	measure := func(h http.HandlerFunc) float64 {
+		req, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+		w := &discardResponse{header: http.Header{}}
+		r := &bodyReader{}
+		return testing.AllocsPerRun(100, func() {
+			*r = bodyReader{data: body}
+			req.Body = r
+			h(w, req)
+		})
+	}
+	slow, fast := measure(orderHandlerSlow), measure(orderHandlerFast)
+	if fast >= slow {
+		t.Errorf("Expected fast handler to allocate less, got %.0f vs %.0f", fast, slow)
+	}
+}
+
+func BenchmarkPairs(b *testing.B) {
+	for _, p := range pairs() {
+		b.Run(p.name+"/куча", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				p.heap()
+			}
+		})
+		b.Run(p.name+"/стек", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				p.stack()
+			}
+		})
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	b.Run("ptr", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			runPoolPtr(4, 10000)
+		}
+	})
+	b.Run("value", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			runPoolValue(4, 10000)
+		}
+	})
+}
+
+func BenchmarkOrderHandler(b *testing.B) {
+	body := []byte(`{"id": 4242, "items": [100, 250, 75]}`)
+	for _, v := range []struct {
+		name string
+		h    http.HandlerFunc
+	}{{"slow", orderHandlerSlow}, {"fast", orderHandlerFast}} {
+		b.Run(v.name, func(b *testing.B) {
+			req, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+			w := &discardResponse{header: http.Header{}}
+			r := &bodyReader{}
+			b.ReportAllocs()
+			for b.Loop() {
+				*r = bodyReader{data: body}
+				req.Body = r
+				v.h(w, req)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Горячий путь JSON-обработчика. Сам encoding/json работает через
+// reflect и interface, поэтому то, что передано в Unmarshal/Encode,
+// убегает всегда - это плата за удобство (без нее - examples/jsonfast).
+// Но вокруг разбора легко добавить выделения, которые ничего не дают.
+
+// order тело запроса
+type order struct {
+	ID    int   `json:"id"`
+	Items []int `json:"items"`
+}
+
+// orderTotal тело ответа
+type orderTotal struct {
+	ID    int `json:"id"`
+	Total int `json:"total"`
+}
+
+// debugLog включает отладочный журнал; в бою выключен
+var debugLog = false
+
+// debugf проверяет флаг внутри: "leaking param content: args" -
+// аргументы передаются в log.Printf, поэтому у вызывающего они
+// упаковываются в interface в куче при каждом вызове, даже когда журнал
+// выключен и ничего не печатается. Исключение - числа 0..255: для них
+// у runtime есть готовые значения, и выделения нет.
+func debugf(format string, args ...any) {
+	if debugLog {
+		log.Printf(format, args...)
+	}
+}
+
+// orderHandlerSlow: выключенный журнал все равно стоит выделений, а
+// заголовок собирается через fmt.Sprint с упаковкой total в interface
+func orderHandlerSlow(w http.ResponseWriter, r *http.Request) {
+	var req order
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	debugf("заказ %d: %d позиций", req.ID, len(req.Items))
+	
+	resp := orderTotal{ID: req.ID}
+	for _, v := range req.Items {
+		resp.Total += v
+	}
+	debugf("заказ %d: сумма %d", resp.ID, resp.Total)
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Order-Total", fmt.Sprint(resp.Total))
+	json.NewEncoder(w).Encode(resp)
+}
+
+// orderHandlerFast: проверка флага у вызова - упаковка аргументов
+// происходит только внутри if, и с выключенным журналом ее нет;
+// заголовок - через strconv без interface
+func orderHandlerFast(w http.ResponseWriter, r *http.Request) {
+	var req order
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if debugLog {
+		log.Printf("заказ %d: %d позиций", req.ID, len(req.Items))
+	}
+	
+	resp := orderTotal{ID: req.ID}
+	for _, v := range req.Items {
+		resp.Total += v
+	}
+	if debugLog {
+		log.Printf("заказ %d: сумма %d", resp.ID, resp.Total)
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Order-Total", strconv.Itoa(resp.Total))
+	json.NewEncoder(w).Encode(resp)
+}
+
+// discardResponse ResponseWriter без накладных расходов httptest: в
+// бенчмарке видны только выделения обработчика
+type discardResponse struct {
+	header http.Header
+}
+
+func (d *discardResponse) Header() http.Header         { return d.header }
+func (d *discardResponse) Write(p []byte) (int, error) { return len(p), nil }
+func (d *discardResponse) WriteHeader(int)             {}
+
+// bodyReader тело запроса, которое можно перечитывать без выделений
+type bodyReader struct {
+	data []byte
+	off  int
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	if b.off >= len(b.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, b.data[b.off:])
+	b.off += n
+	return n, nil
+}
+
+func (b *bodyReader) Close() error { return nil }
\ No newline at end of file
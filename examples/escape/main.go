@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Escape analysis: компилятор решает, где живет значение - на стеке
+// (бесплатно, исчезает при выходе из функции) или в куче (выделение и
+// работа сборщика мусора). Значение "убегает" в кучу, если может
+// пережить функцию: возвращается по указателю, сохраняется в
+// глобальной переменной или поле, уходит в канал, в interface или в
+// замыкание, которое живет дольше.
+//
+//   - escape.go - пары функций: убегает / остается на стеке;
+//   - pool.go - задачи пула воркеров по указателю и по значению;
+//   - handler.go - JSON-обработчик с лишними и без лишних выделений;
+//   - escape_test.go - тесты на число выделений и бенчмарки.
+//
+// Запуск: go run .
+//
+// Что решил компилятор (строки "escapes to heap", "moved to heap",
+// "does not escape", "leaking param"):
+//
+//	go build -gcflags=-m 2>&1 | grep -v inlin
+//	go build -gcflags='-m -m' 2>&1 | grep -A5 'newPointPtr'  # с причинами
+//
+// Для пакета в модуле - go build -gcflags='./internal/...=-m' ./...
+// (без шаблона пакета -gcflags применяется только к указанным пакетам).
+//
+// Бенчмарки:
+//
+//	go test -run=^$ -bench=. -benchmem
+//
+// Правило: сначала профиль (alloc_space в pprof), потом -gcflags=-m по
+// горячим функциям. Переписывать ради стека все подряд не нужно -
+// только то, что вызывается миллионы раз.
+
+// pair две реализации одной операции
+type pair struct {
+	name  string
+	heap  func()
+	stack func()
+}
+
+// Приемники результатов, чтобы компилятор не выбросил вызовы
+var (
+	sinkPoint point
+	sinkInt   int
+)
+
+func pairs() []pair {
+	data := []byte("escape analysis")
+	v := 1234567
+	return []pair{
+		{"указатель / значение",
+			func() { sinkPoint = *newPointPtr(1, 2, 3) },
+			func() { sinkPoint = newPoint(1, 2, 3) }},
+		{"fmt / strconv",
+			func() {
+				var buf [32]byte
+				sinkInt = len(appendAny(buf[:0], v))
+			},
+			func() {
+				var buf [32]byte
+				sinkInt = len(appendInt(buf[:0], v))
+			}},
+		{"свой буфер / буфер вызывающего",
+			func() { sinkInt = len(hexNew(data)) },
+			func() {
+				var buf [64]byte
+				sinkInt = len(appendHex(buf[:0], data))
+			}},
+		{"сохранить / вызвать замыкание",
+			func() {
+				k := v
+				register(func(x int) int { return x * k })
+				hooks = hooks[:0]
+			},
+			func() {
+				k := v
+				sinkInt = apply(func(x int) int { return x * k }, 2)
+			}},
+	}
+}
+
+// Пример 1: Пары функций - выделения и время на вызов
+func comparePairs() {
+	fmt.Println("=== Куча / стек ===")
+	
+	for _, p := range pairs() {
+		fmt.Printf("%s:\n", p.name)
+		for _, v := range []struct {
+			name string
+			fn   func()
+		}{{"куча", p.heap}, {"стек", p.stack}} {
+			r := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					v.fn()
+				}
+			})
+			fmt.Printf("  %-5s %6.1f нс/op %4d Б/op %2d allocs/op\n",
+				v.name, float64(r.T.Nanoseconds())/float64(r.N), r.AllocedBytesPerOp(), r.AllocsPerOp())
+		}
+	}
+}
+
+// Пример 2: Пул воркеров - задача в канале по указателю и по значению.
+// Время почти одинаковое - его съедает синхронизация канала; разница в
+// 800 КБ мусора на каждые 10000 задач, которые потом собирает GC.
+func comparePool() {
+	fmt.Println("\n=== Пул воркеров, 10000 задач ===")
+	
+	const workers, n = 4, 10000
+	fmt.Printf("Суммы совпадают: %v\n", runPoolPtr(workers, n) == runPoolValue(workers, n))
+	for _, v := range []struct {
+		name string
+		run  func(int, int) int64
+	}{{"*task", runPoolPtr}, {"task", runPoolValue}} {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v.run(workers, n)
+			}
+		})
+		fmt.Printf("  chan %-5s %8d нс/op %7d Б/op %5d allocs/op\n",
+			v.name, r.NsPerOp(), r.AllocedBytesPerOp(), r.AllocsPerOp())
+	}
+}
+
+// Пример 3: JSON-обработчик - одинаковый ответ, разное число выделений.
+// Большую часть выделений делает encoding/json, их так не убрать; но
+// четыре лишних выделения на запрос - на ровном месте.
+func compareHandlers() {
+	fmt.Println("\n=== JSON-обработчик ===")
+	
+	body := []byte(`{"id": 4242, "items": [100, 250, 75]}`)
+	for _, v := range []struct {
+		name string
+		h    http.HandlerFunc
+	}{{"медленный", orderHandlerSlow}, {"быстрый", orderHandlerFast}} {
+		req, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+		w := &discardResponse{header: http.Header{}}
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				req.Body = &bodyReader{data: body}
+				v.h(w, req)
+			}
+		})
+		fmt.Printf("  %-10s %6d нс/op %5d Б/op %3d allocs/op, X-Order-Total: %s\n",
+			v.name, r.NsPerOp(), r.AllocedBytesPerOp(), r.AllocsPerOp(), w.header.Get("X-Order-Total"))
+	}
+}
+
+func main() {
+	comparePairs()
+	comparePool()
+	compareHandlers()
+}
\ No newline at end of file
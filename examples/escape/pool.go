@@ -0,0 +1,81 @@
+package main
+
+import "sync"
+
+// Горячий путь пула воркеров: на каждую задачу - отправка в канал и
+// разбор задачи воркером. Выделение на задачу при сотнях тысяч задач в
+// секунду - это и время, и работа сборщика мусора.
+
+// task задача пула: небольшая, копировать дешевле, чем выделять
+type task struct {
+	ID      int
+	Payload [8]int64
+}
+
+// sum работа воркера над задачей
+func (t *task) sum() int64 {
+	s := int64(t.ID)
+	for _, v := range t.Payload {
+		s += v
+	}
+	return s
+}
+
+// runPoolPtr передает задачи по указателю: "&task{...} escapes to heap" -
+// значение уходит в канал, компилятор не знает, когда его прочитают, и
+// каждая задача выделяется в куче.
+func runPoolPtr(workers, n int) int64 {
+	jobs := make(chan *task, workers)
+	var total int64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local int64
+			for t := range jobs {
+				local += t.sum()
+			}
+			mu.Lock()
+			total += local
+			mu.Unlock()
+		}()
+	}
+	for i := range n {
+		jobs <- &task{ID: i, Payload: [8]int64{int64(i)}}
+	}
+	close(jobs)
+	wg.Wait()
+	return total
+}
+
+// runPoolValue передает задачи по значению: 72 байта копируются в буфер
+// канала и обратно, выделений на задачу нет. Метод с получателем
+// *task вызывается у локальной копии - t не убегает ("t does not
+// escape" у sum), поэтому копия остается на стеке воркера.
+func runPoolValue(workers, n int) int64 {
+	jobs := make(chan task, workers)
+	var total int64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local int64
+			for t := range jobs {
+				local += t.sum()
+			}
+			mu.Lock()
+			total += local
+			mu.Unlock()
+		}()
+	}
+	for i := range n {
+		jobs <- task{ID: i, Payload: [8]int64{int64(i)}}
+	}
+	close(jobs)
+	wg.Wait()
+	return total
+}
\ No newline at end of file
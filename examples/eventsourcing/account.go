@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// Типы событий. Событие - факт, который уже произошел, поэтому
+// имена в прошедшем времени, а данные никогда не меняются.
+const (
+	EventAccountCreated     = "AccountCreated"
+	EventEmailChanged       = "EmailChanged"
+	EventAccountDeactivated = "AccountDeactivated"
+)
+
+// AccountCreated аккаунт создан
+type AccountCreated struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// EmailChanged email изменен
+type EmailChanged struct {
+	Email string `json:"email"`
+}
+
+// AccountDeactivated аккаунт выключен
+type AccountDeactivated struct {
+	Reason string `json:"reason"`
+}
+
+// NewEvent событие, которое команда хочет записать
+type NewEvent struct {
+	Type string
+	Data any
+}
+
+// Ошибки команд
+var (
+	ErrAccountExists  = errors.New("account already exists")
+	ErrAccountMissing = errors.New("account not found")
+	ErrDeactivated    = errors.New("account is deactivated")
+	ErrInvalid        = errors.New("invalid command")
+)
+
+// Account агрегат: состояние, восстановленное из событий. Методы-команды
+// проверяют правила и возвращают новые события, но состояние не меняют -
+// это делает только Apply, и при записи, и при чтении истории.
+type Account struct {
+	ID      string
+	Name    string
+	Email   string
+	Active  bool
+	Version int // Номер последнего примененного события
+}
+
+// Replay восстанавливает аккаунт из истории его событий
+func Replay(id string, history []StoredEvent) (*Account, error) {
+	a := &Account{ID: id}
+	for _, e := range history {
+		if err := a.Apply(e); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Apply применяет событие к состоянию
+func (a *Account) Apply(e StoredEvent) error {
+	switch e.Type {
+	case EventAccountCreated:
+		var data AccountCreated
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		a.Name, a.Email, a.Active = data.Name, data.Email, true
+	case EventEmailChanged:
+		var data EmailChanged
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		a.Email = data.Email
+	case EventAccountDeactivated:
+		a.Active = false
+	default:
+		return fmt.Errorf("неизвестный тип события %q", e.Type)
+	}
+	a.Version = e.Version
+	return nil
+}
+
+// Create команда создания
+func (a *Account) Create(name, email string) ([]NewEvent, error) {
+	if a.Version > 0 {
+		return nil, ErrAccountExists
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return []NewEvent{{EventAccountCreated, AccountCreated{name, email}}}, nil
+}
+
+// ChangeEmail команда смены email. Тот же адрес - не ошибка,
+// но и не событие: в истории остаются только настоящие изменения.
+func (a *Account) ChangeEmail(email string) ([]NewEvent, error) {
+	if err := a.mustBeActive(); err != nil {
+		return nil, err
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if email == a.Email {
+		return nil, nil
+	}
+	return []NewEvent{{EventEmailChanged, EmailChanged{email}}}, nil
+}
+
+// Deactivate команда выключения
+func (a *Account) Deactivate(reason string) ([]NewEvent, error) {
+	if err := a.mustBeActive(); err != nil {
+		return nil, err
+	}
+	return []NewEvent{{EventAccountDeactivated, AccountDeactivated{strings.TrimSpace(reason)}}}, nil
+}
+
+func (a *Account) mustBeActive() error {
+	if a.Version == 0 {
+		return ErrAccountMissing
+	}
+	if !a.Active {
+		return ErrDeactivated
+	}
+	return nil
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: email is malformed", ErrInvalid)
+	}
+	return email, nil
+}
\ No newline at end of file
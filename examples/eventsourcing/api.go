@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Commands сторона записи: загружает историю, проигрывает ее,
+// выполняет команду и дописывает новые события
+type Commands struct {
+	events *EventStore
+}
+
+// CommandResult что вернуть клиенту после команды. Position позволяет
+// дождаться, пока модель чтения догонит запись (см. ?after= в запросах).
+type CommandResult struct {
+	ID       string `json:"id"`
+	Version  int    `json:"version"`
+	Position int64  `json:"position,omitempty"`
+}
+
+// maxRetries сколько раз повторить команду при ErrConcurrency
+const maxRetries = 3
+
+// Execute выполняет команду над аккаунтом. При конфликте версий история
+// перечитывается и команда проверяется заново: правила могли сработать
+// иначе на новом состоянии (например, аккаунт уже выключен).
+func (c *Commands) Execute(ctx context.Context, id string, cmd func(*Account) ([]NewEvent, error)) (CommandResult, error) {
+	for attempt := 1; ; attempt++ {
+		history, err := c.events.Load(ctx, id)
+		if err != nil {
+			return CommandResult{}, err
+		}
+		account, err := Replay(id, history)
+		if err != nil {
+			return CommandResult{}, err
+		}
+		newEvents, err := cmd(account)
+		if err != nil {
+			return CommandResult{}, err
+		}
+		
+		stored, err := c.events.Append(ctx, id, account.Version, newEvents)
+		if errors.Is(err, ErrConcurrency) && attempt < maxRetries {
+			continue
+		}
+		if err != nil {
+			return CommandResult{}, err
+		}
+		
+		result := CommandResult{ID: id, Version: account.Version}
+		if len(stored) > 0 {
+			last := stored[len(stored)-1]
+			result.Version, result.Position = last.Version, last.Position
+		}
+		return result, nil
+	}
+}
+
+// newID случайный идентификатор аккаунта
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Routes разделяет команды и запросы (CQRS):
+//
+//	Команды (пишут события, отвечают 202 и позицией в журнале):
+//	POST /api/accounts                     {"name", "email"}
+//	POST /api/accounts/{id}/email          {"email"}
+//	POST /api/accounts/{id}/deactivate     {"reason"}
+//
+//	Запросы (читают модель чтения; ?after=POSITION ждет, пока проекция
+//	догонит указанную позицию):
+//	GET  /api/accounts[?active=1]
+//	GET  /api/accounts/{id}
+//	GET  /api/accounts/{id}/events         история из журнала
+func Routes(commands *Commands, projector *Projector, events *EventStore) http.Handler {
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("POST /api/accounts", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		result, err := commands.Execute(r.Context(), newID(), func(a *Account) ([]NewEvent, error) {
+			return a.Create(req.Name, req.Email)
+		})
+		writeCommandResult(w, result, err)
+	})
+	
+	mux.HandleFunc("POST /api/accounts/{id}/email", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Email string `json:"email"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		result, err := commands.Execute(r.Context(), r.PathValue("id"), func(a *Account) ([]NewEvent, error) {
+			return a.ChangeEmail(req.Email)
+		})
+		writeCommandResult(w, result, err)
+	})
+	
+	mux.HandleFunc("POST /api/accounts/{id}/deactivate", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+		result, err := commands.Execute(r.Context(), r.PathValue("id"), func(a *Account) ([]NewEvent, error) {
+			return a.Deactivate(req.Reason)
+		})
+		writeCommandResult(w, result, err)
+	})
+	
+	mux.HandleFunc("GET /api/accounts", func(w http.ResponseWriter, r *http.Request) {
+		if !waitForPosition(w, r, projector) {
+			return
+		}
+		views, err := projector.List(r.Context(), r.URL.Query().Get("active") == "1")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, views)
+	})
+	
+	mux.HandleFunc("GET /api/accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !waitForPosition(w, r, projector) {
+			return
+		}
+		view, err := projector.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, view)
+	})
+	
+	mux.HandleFunc("GET /api/accounts/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+		history, err := events.Load(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if len(history) == 0 {
+			writeError(w, ErrAccountMissing)
+			return
+		}
+		writeJSON(w, http.StatusOK, history)
+	})
+	
+	return mux
+}
+
+// waitForPosition реализует read-your-writes поверх асинхронной проекции:
+// клиент передает позицию из ответа команды, и запрос ждет до секунды,
+// пока проекция ее не обработает
+func waitForPosition(w http.ResponseWriter, r *http.Request, projector *Projector) bool {
+	raw := r.URL.Query().Get("after")
+	if raw == "" {
+		return true
+	}
+	after, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "after must be a number"})
+		return false
+	}
+	
+	ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+	defer cancel()
+	for {
+		pos, err := projector.Position(ctx)
+		if err == nil && pos >= after {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "read model is behind, retry later"})
+			return false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return false
+	}
+	return true
+}
+
+func writeCommandResult(w http.ResponseWriter, result CommandResult, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// 202: событие записано, но модель чтения обновится чуть позже
+	writeJSON(w, http.StatusAccepted, result)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrInvalid):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, ErrAccountMissing):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrAccountExists), errors.Is(err, ErrDeactivated), errors.Is(err, ErrConcurrency):
+		status = http.StatusConflict
+	default:
+		log.Printf("Внутренняя ошибка: %v", err)
+		err = errors.New("internal error")
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
\ No newline at end of file
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (*EventStore, *Projector) {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	events, projector := setup(db)
+	return events, projector
+}
+
+func created(name, email string) NewEvent {
+	return NewEvent{EventAccountCreated, AccountCreated{name, email}}
+}
+
+func TestAccountCommands(t *testing.T) {
+	active := &Account{ID: "a", Email: "a@example.com", Active: true, Version: 1}
+	inactive := &Account{ID: "a", Email: "a@example.com", Version: 2}
+	
+	tests := []struct {
+		name    string
+		run     func() ([]NewEvent, error)
+		events  int
+		wantErr error
+	}{
+		{"create", func() ([]NewEvent, error) { return (&Account{}).Create("Анна", " Anna@Example.com ") }, 1, nil},
+		{"create twice", func() ([]NewEvent, error) { return active.Create("Анна", "a@example.com") }, 0, ErrAccountExists},
+		{"create without name", func() ([]NewEvent, error) { return (&Account{}).Create(" ", "a@example.com") }, 0, ErrInvalid},
+		{"create bad email", func() ([]NewEvent, error) { return (&Account{}).Create("Анна", "not-an-email") }, 0, ErrInvalid},
+		{"change email", func() ([]NewEvent, error) { return active.ChangeEmail("b@example.com") }, 1, nil},
+		{"same email", func() ([]NewEvent, error) { return active.ChangeEmail("A@example.com") }, 0, nil},
+		{"change missing", func() ([]NewEvent, error) { return (&Account{}).ChangeEmail("b@example.com") }, 0, ErrAccountMissing},
+		{"change deactivated", func() ([]NewEvent, error) { return inactive.ChangeEmail("b@example.com") }, 0, ErrDeactivated},
+		{"deactivate", func() ([]NewEvent, error) { return active.Deactivate("спам") }, 1, nil},
+		{"deactivate twice", func() ([]NewEvent, error) { return inactive.Deactivate("") }, 0, ErrDeactivated},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := tt.run()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(events) != tt.events {
+				t.Errorf("Expected %d events, got %d", tt.events, len(events))
+			}
+		})
+	}
+}
+
+func TestAppendOptimisticConcurrency(t *testing.T) {
+	events, _ := newTestStore(t)
+	ctx := context.Background()
+	
+	if _, err := events.Append(ctx, "a", 0, []NewEvent{created("Анна", "a@example.com")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := events.Append(ctx, "a", 0, []NewEvent{created("Анна", "a@example.com")}); !errors.Is(err, ErrConcurrency) {
+		t.Errorf("Expected ErrConcurrency for stale version, got %v", err)
+	}
+	stored, err := events.Append(ctx, "a", 1, []NewEvent{
+		{EventEmailChanged, EmailChanged{"b@example.com"}},
+		{EventAccountDeactivated, AccountDeactivated{}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored[0].Version != 2 || stored[1].Version != 3 {
+		t.Errorf("Expected versions 2 and 3, got %d and %d", stored[0].Version, stored[1].Version)
+	}
+	if stored[1].Position <= stored[0].Position {
+		t.Errorf("Expected increasing positions, got %d then %d", stored[0].Position, stored[1].Position)
+	}
+}
+
+func TestEventsAreAppendOnly(t *testing.T) {
+	events, _ := newTestStore(t)
+	ctx := context.Background()
+	if _, err := events.Append(ctx, "a", 0, []NewEvent{created("Анна", "a@example.com")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	for _, query := range []string{
+		`UPDATE events SET data = '{}'`,
+		`DELETE FROM events`,
+	} {
+		if _, err := events.db.Exec(query); err == nil || !strings.Contains(err.Error(), "append-only") {
+			t.Errorf("%s: expected append-only error, got %v", query, err)
+		}
+	}
+}
+
+func TestCommandRetriesOnConflict(t *testing.T) {
+	events, _ := newTestStore(t)
+	ctx := context.Background()
+	commands := &Commands{events: events}
+	if _, err := events.Append(ctx, "a", 0, []NewEvent{created("Анна", "a@example.com")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	// Первая попытка проигрывает гонку: пока команда думала,
+	// аккаунт выключили. Повтор видит новое состояние и отказывает.
+	raced := false
+	_, err := commands.Execute(ctx, "a", func(a *Account) ([]NewEvent, error) {
+		if !raced {
+			raced = true
+			if _, err := events.Append(ctx, "a", a.Version, []NewEvent{{EventAccountDeactivated, AccountDeactivated{}}}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		return a.ChangeEmail("b@example.com")
+	})
+	if !errors.Is(err, ErrDeactivated) {
+		t.Errorf("Expected ErrDeactivated after retry, got %v", err)
+	}
+}
+
+func TestProjectionMatchesReplay(t *testing.T) {
+	events, projector := newTestStore(t)
+	ctx := context.Background()
+	commands := &Commands{events: events}
+	projector.batch = 2 // Несколько пакетов на одну проекцию
+	
+	var ids []string
+	for i := range 3 {
+		result, err := commands.Execute(ctx, newID(), func(a *Account) ([]NewEvent, error) {
+			return a.Create(fmt.Sprintf("user%d", i), fmt.Sprintf("user%d@example.com", i))
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, result.ID)
+	}
+	commands.Execute(ctx, ids[0], func(a *Account) ([]NewEvent, error) { return a.ChangeEmail("new@example.com") })
+	commands.Execute(ctx, ids[1], func(a *Account) ([]NewEvent, error) { return a.Deactivate("") })
+	
+	count, err := projector.CatchUp(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 events applied, got %d", count)
+	}
+	
+	for _, id := range ids {
+		history, _ := events.Load(ctx, id)
+		account, err := Replay(id, history)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		view, err := projector.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if view.Email != account.Email || view.Active != account.Active || view.Version != account.Version {
+			t.Errorf("Expected view to match replay %+v, got %+v", account, view)
+		}
+	}
+	
+	active, _ := projector.List(ctx, true)
+	if len(active) != 2 {
+		t.Errorf("Expected 2 active accounts, got %d", len(active))
+	}
+}
+
+func TestProjectionResumesFromCheckpoint(t *testing.T) {
+	events, projector := newTestStore(t)
+	ctx := context.Background()
+	
+	events.Append(ctx, "a", 0, []NewEvent{created("Анна", "a@example.com")})
+	if count, _ := projector.CatchUp(ctx); count != 1 {
+		t.Fatalf("Expected 1 event, got %d", count)
+	}
+	
+	// Новая проекция над той же БД - как после перезапуска процесса
+	restarted := NewProjector(projector.db, events)
+	events.Append(ctx, "a", 1, []NewEvent{{EventEmailChanged, EmailChanged{"b@example.com"}}})
+	count, err := restarted.CatchUp(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected only the new event to be applied, got %d", count)
+	}
+	
+	if count, _ := restarted.Rebuild(ctx); count != 2 {
+		t.Errorf("Expected rebuild to replay 2 events, got %d", count)
+	}
+	view, _ := restarted.Get(ctx, "a")
+	if view.Email != "b@example.com" || view.Version != 2 {
+		t.Errorf("Expected rebuilt view with new email at version 2, got %+v", view)
+	}
+}
+
+func TestConcurrentCatchUp(t *testing.T) {
+	events, projector := newTestStore(t)
+	ctx := context.Background()
+	for i := range 20 {
+		events.Append(ctx, fmt.Sprint(i), 0, []NewEvent{created("user", fmt.Sprintf("u%d@example.com", i))})
+	}
+	projector.batch = 3
+	
+	totals := make(chan int, 4)
+	for range 4 {
+		go func() {
+			count, err := projector.CatchUp(ctx)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			totals <- count
+		}()
+	}
+	sum := 0
+	for range 4 {
+		sum += <-totals
+	}
+	// Каждое событие применено ровно одним из обработчиков
+	if sum != 20 {
+		t.Errorf("Expected 20 events applied in total, got %d", sum)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	events, projector := newTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go projector.Run(ctx, 10*time.Millisecond)
+	
+	server := httptest.NewServer(Routes(&Commands{events: events}, projector, events))
+	defer server.Close()
+	
+	post := func(path, body string) (int, CommandResult) {
+		t.Helper()
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		var result CommandResult
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+	
+	status, anna := post("/api/accounts", `{"name":"Анна","email":"anna@example.com"}`)
+	if status != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", status)
+	}
+	_, changed := post("/api/accounts/"+anna.ID+"/email", `{"email":"anna@new.example.com"}`)
+	
+	resp, err := http.Get(fmt.Sprintf("%s/api/accounts/%s?after=%d", server.URL, anna.ID, changed.Position))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var view AccountView
+	json.NewDecoder(resp.Body).Decode(&view)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || view.Email != "anna@new.example.com" {
+		t.Errorf("Expected read-your-writes view with new email, got %d %+v", resp.StatusCode, view)
+	}
+	
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid email", "/api/accounts", `{"name":"Петр","email":"petr"}`, http.StatusUnprocessableEntity},
+		{"invalid json", "/api/accounts", `{`, http.StatusBadRequest},
+		{"missing account", "/api/accounts/nope/email", `{"email":"x@example.com"}`, http.StatusNotFound},
+		{"deactivate", "/api/accounts/" + anna.ID + "/deactivate", `{"reason":"тест"}`, http.StatusAccepted},
+		{"deactivated", "/api/accounts/" + anna.ID + "/email", `{"email":"x@example.com"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := post(tt.path, tt.body); status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+	
+	resp, err = http.Get(server.URL + "/api/accounts/" + anna.ID + "/events")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var history []StoredEvent
+	json.NewDecoder(resp.Body).Decode(&history)
+	resp.Body.Close()
+	if len(history) != 3 {
+		t.Errorf("Expected 3 events in history, got %d", len(history))
+	}
+	
+	resp, err = http.Get(server.URL + "/api/accounts?after=1000000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 for unreachable position, got %d", resp.StatusCode)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Event sourcing и CQRS: состояние аккаунта не хранится, а вычисляется
+// из журнала событий (AccountCreated, EmailChanged, AccountDeactivated).
+// Запросы читают отдельную модель чтения, которую асинхронно строит
+// проекция.
+//
+//   account.go    - агрегат: команды проверяют правила и порождают события
+//   store.go      - журнал событий в SQLite, только добавление
+//   projection.go - модель чтения account_view и ее перестройка
+//   api.go        - команды и запросы на разных маршрутах
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск демонстрации: go run .
+// Сервер: go run . -serve :8080 -db accounts.db
+//   curl -d '{"name":"Иван","email":"ivan@example.com"}' localhost:8080/api/accounts
+//   curl 'localhost:8080/api/accounts/<id>?after=<position>'
+//   curl localhost:8080/api/accounts/<id>/events
+
+// openDB открывает БД; _txlock=immediate, чтобы проверка версии
+// и вставка в Append шли под одной блокировкой на запись
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_txlock=immediate&_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func setup(db *sql.DB) (*EventStore, *Projector) {
+	ctx := context.Background()
+	events := NewEventStore(db)
+	if err := events.Migrate(ctx); err != nil {
+		log.Fatal("Ошибка миграции журнала:", err)
+	}
+	projector := NewProjector(db, events)
+	if err := projector.Migrate(ctx); err != nil {
+		log.Fatal("Ошибка миграции модели чтения:", err)
+	}
+	return events, projector
+}
+
+// Пример 1: Состояние как свертка событий
+func replay(events *EventStore) {
+	fmt.Println("=== Восстановление состояния из событий ===")
+	ctx := context.Background()
+	commands := &Commands{events: events}
+	
+	id := newID()
+	steps := []func(*Account) ([]NewEvent, error){
+		func(a *Account) ([]NewEvent, error) { return a.Create("Иван Иванов", "ivan@example.com") },
+		func(a *Account) ([]NewEvent, error) { return a.ChangeEmail("ivan@work.example.com") },
+		func(a *Account) ([]NewEvent, error) { return a.ChangeEmail("IVAN@work.example.com") }, // Тот же адрес - без события
+		func(a *Account) ([]NewEvent, error) {
+			return a.Deactivate("по просьбе пользователя")
+		},
+	}
+	for _, step := range steps {
+		if _, err := commands.Execute(ctx, id, step); err != nil {
+			log.Fatal(err)
+		}
+	}
+	
+	history, _ := events.Load(ctx, id)
+	for _, e := range history {
+		fmt.Printf("  v%d %-20s %s\n", e.Version, e.Type, e.Data)
+	}
+	for _, n := range []int{1, 2, len(history)} {
+		a, _ := Replay(id, history[:n])
+		fmt.Printf("Состояние после %d событий: email=%s active=%v\n", n, a.Email, a.Active)
+	}
+	
+	_, err := commands.Execute(ctx, id, func(a *Account) ([]NewEvent, error) { return a.ChangeEmail("new@example.com") })
+	fmt.Printf("Смена email выключенного аккаунта: %v\n", err)
+	
+	_, err = events.db.Exec(`UPDATE events SET data = '{}' WHERE aggregate_id = ?`, id)
+	fmt.Printf("Попытка переписать историю: %v\n", err)
+}
+
+// Пример 2: Оптимистичная блокировка
+func concurrency(events *EventStore) {
+	fmt.Println("\n=== Конкурентные команды ===")
+	ctx := context.Background()
+	id := newID()
+	created, _ := events.Append(ctx, id, 0, []NewEvent{{EventAccountCreated, AccountCreated{"Петр", "petr@example.com"}}})
+	
+	// Два обработчика прочитали версию 1 и оба пытаются записать версию 2
+	version := created[0].Version
+	_, err1 := events.Append(ctx, id, version, []NewEvent{{EventEmailChanged, EmailChanged{"a@example.com"}}})
+	_, err2 := events.Append(ctx, id, version, []NewEvent{{EventEmailChanged, EmailChanged{"b@example.com"}}})
+	fmt.Printf("Первая запись: %v, вторая: %v\n", err1, err2)
+}
+
+// Пример 3: CQRS по HTTP с асинхронной проекцией
+func cqrs(events *EventStore, projector *Projector) {
+	fmt.Println("\n=== Команды и запросы ===")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go projector.Run(ctx, time.Second)
+	
+	server := httptest.NewServer(Routes(&Commands{events: events}, projector, events))
+	defer server.Close()
+	
+	post := func(path, body string) CommandResult {
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var result CommandResult
+		json.NewDecoder(resp.Body).Decode(&result)
+		fmt.Printf("POST %-36s -> %d version=%d position=%d\n", path, resp.StatusCode, result.Version, result.Position)
+		return result
+	}
+	get := func(path string) {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		fmt.Printf("GET  %s -> %d %s", path, resp.StatusCode, body)
+	}
+	
+	anna := post("/api/accounts", `{"name":"Анна","email":"anna@example.com"}`)
+	result := post("/api/accounts/"+anna.ID+"/email", `{"email":"anna@new.example.com"}`)
+	// Без ?after запрос мог бы увидеть старый email: проекция асинхронна
+	get(fmt.Sprintf("/api/accounts/%s?after=%d", anna.ID, result.Position))
+	
+	count, err := projector.Rebuild(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Модель чтения перестроена из %d событий\n", count)
+	get("/api/accounts?active=1")
+}
+
+func main() {
+	addr := flag.String("serve", "", "Адрес для запуска сервера")
+	dbPath := flag.String("db", "", "Файл БД (по умолчанию временный)")
+	flag.Parse()
+	
+	if *dbPath == "" {
+		dir, err := os.MkdirTemp("", "eventsourcing")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		*dbPath = filepath.Join(dir, "events.db")
+	}
+	db, err := openDB(*dbPath)
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	events, projector := setup(db)
+	
+	if *addr != "" {
+		go projector.Run(context.Background(), time.Second)
+		log.Printf("Сервер запущен на %s", *addr)
+		log.Fatal(http.ListenAndServe(*addr, Routes(&Commands{events: events}, projector, events)))
+	}
+	
+	replay(events)
+	concurrency(events)
+	cqrs(events, projector)
+}
\ No newline at end of file
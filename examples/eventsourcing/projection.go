@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+)
+
+// AccountView строка модели чтения: то, что нужно запросам, в готовом
+// виде - без повторного проигрывания событий
+type AccountView struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Active    bool      `json:"active"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Projector строит модель чтения accounts из журнала событий.
+// Работает асинхронно: запросы видят изменения с небольшой задержкой
+// (eventual consistency). Позиция последнего обработанного события
+// хранится в той же транзакции, что и изменения модели, поэтому после
+// перезапуска проекция продолжает с того же места и ничего не применяет
+// дважды.
+type Projector struct {
+	db     *sql.DB
+	events *EventStore
+	batch  int
+}
+
+const projectionName = "accounts"
+
+// NewProjector создает проекцию
+func NewProjector(db *sql.DB, events *EventStore) *Projector {
+	return &Projector{db: db, events: events, batch: 100}
+}
+
+// Migrate создает таблицы модели чтения
+func (p *Projector) Migrate(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS account_view (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		active INTEGER NOT NULL,
+		version INTEGER NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_account_view_email ON account_view(email);
+	CREATE TABLE IF NOT EXISTS projections (
+		name TEXT PRIMARY KEY,
+		position INTEGER NOT NULL
+	);`)
+	return err
+}
+
+// Position номер последнего примененного события
+func (p *Projector) Position(ctx context.Context) (int64, error) {
+	var pos int64
+	err := p.db.QueryRowContext(ctx, `SELECT position FROM projections WHERE name = ?`, projectionName).Scan(&pos)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return pos, err
+}
+
+// CatchUp применяет все новые события и возвращает их количество
+func (p *Projector) CatchUp(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		pos, err := p.Position(ctx)
+		if err != nil {
+			return total, err
+		}
+		events, err := p.events.ReadFrom(ctx, pos, p.batch)
+		if err != nil {
+			return total, err
+		}
+		if len(events) == 0 {
+			return total, nil
+		}
+		applied, err := p.applyBatch(ctx, pos, events)
+		if err != nil {
+			return total, err
+		}
+		if applied {
+			total += len(events)
+		}
+	}
+}
+
+// applyBatch применяет события, прочитанные после позиции from. Если
+// позиция успела сдвинуться (параллельный CatchUp или Rebuild), пакет
+// устарел и пропускается: следующий проход прочитает события заново.
+func (p *Projector) applyBatch(ctx context.Context, from int64, events []StoredEvent) (bool, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	
+	var current int64
+	err = tx.QueryRowContext(ctx, `SELECT position FROM projections WHERE name = ?`, projectionName).Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	if current != from {
+		return false, nil
+	}
+	
+	for _, e := range events {
+		if err := applyToView(ctx, tx, e); err != nil {
+			return false, err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO projections (name, position) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET position = excluded.position`,
+		projectionName, events[len(events)-1].Position)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func applyToView(ctx context.Context, tx *sql.Tx, e StoredEvent) error {
+	at := e.RecordedAt.UnixMilli()
+	switch e.Type {
+	case EventAccountCreated:
+		var data AccountCreated
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO account_view (id, name, email, active, version, created_at, updated_at)
+			VALUES (?, ?, ?, 1, ?, ?, ?)`,
+			e.AggregateID, data.Name, data.Email, e.Version, at, at)
+		return err
+	case EventEmailChanged:
+		var data EmailChanged
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE account_view SET email = ?, version = ?, updated_at = ? WHERE id = ?`,
+			data.Email, e.Version, at, e.AggregateID)
+		return err
+	case EventAccountDeactivated:
+		_, err := tx.ExecContext(ctx, `UPDATE account_view SET active = 0, version = ?, updated_at = ? WHERE id = ?`,
+			e.Version, at, e.AggregateID)
+		return err
+	}
+	// Незнакомые события проекция пропускает: их могли добавить
+	// для другой модели чтения
+	return nil
+}
+
+// Run догоняет журнал после каждой записи и раз в interval на случай,
+// если события записал другой процесс. Блокируется до отмены ctx.
+func (p *Projector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := p.CatchUp(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Ошибка проекции: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.events.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebuild стирает модель чтения и строит ее заново из всей истории:
+// так исправляют ошибку в проекции или добавляют новое поле
+func (p *Projector) Rebuild(ctx context.Context) (int, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM account_view`); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM projections WHERE name = ?`, projectionName); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return p.CatchUp(ctx)
+}
+
+// Get возвращает аккаунт из модели чтения
+func (p *Projector) Get(ctx context.Context, id string) (AccountView, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT id, name, email, active, version, created_at, updated_at
+		FROM account_view WHERE id = ?`, id)
+	v, err := scanView(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AccountView{}, ErrAccountMissing
+	}
+	return v, err
+}
+
+// List возвращает аккаунты; activeOnly - только активные
+func (p *Projector) List(ctx context.Context, activeOnly bool) ([]AccountView, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, name, email, active, version, created_at, updated_at
+		FROM account_view WHERE active = 1 OR ? = 0 ORDER BY created_at, id`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	views := []AccountView{}
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanView(s scanner) (AccountView, error) {
+	var v AccountView
+	var createdAt, updatedAt int64
+	err := s.Scan(&v.ID, &v.Name, &v.Email, &v.Active, &v.Version, &createdAt, &updatedAt)
+	v.CreatedAt = time.UnixMilli(createdAt)
+	v.UpdatedAt = time.UnixMilli(updatedAt)
+	return v, err
+}
\ No newline at end of file
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrConcurrency история агрегата изменилась с момента чтения:
+// команду нужно выполнить заново на свежем состоянии
+var ErrConcurrency = errors.New("concurrent modification")
+
+// StoredEvent событие в хранилище
+type StoredEvent struct {
+	Position    int64           `json:"position"` // Глобальный порядок по всем агрегатам
+	AggregateID string          `json:"aggregate_id"`
+	Version     int             `json:"version"` // Порядок внутри агрегата, с 1
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	RecordedAt  time.Time       `json:"recorded_at"`
+}
+
+// EventStore журнал событий в SQLite. Только добавление: UPDATE и DELETE
+// запрещены триггерами, так что история - единственный источник правды,
+// а любое состояние можно пересчитать заново.
+type EventStore struct {
+	db     *sql.DB
+	now    func() time.Time
+	notify chan struct{} // Будит проекции после записи
+}
+
+// NewEventStore создает хранилище
+func NewEventStore(db *sql.DB) *EventStore {
+	return &EventStore{db: db, now: time.Now, notify: make(chan struct{}, 1)}
+}
+
+// Migrate создает таблицу событий и запрещающие триггеры
+func (s *EventStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS events (
+		position INTEGER PRIMARY KEY AUTOINCREMENT,
+		aggregate_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		data TEXT NOT NULL,
+		recorded_at INTEGER NOT NULL,
+		UNIQUE (aggregate_id, version)
+	);
+	CREATE TRIGGER IF NOT EXISTS events_no_update BEFORE UPDATE ON events
+	BEGIN SELECT RAISE(ABORT, 'events are append-only'); END;
+	CREATE TRIGGER IF NOT EXISTS events_no_delete BEFORE DELETE ON events
+	BEGIN SELECT RAISE(ABORT, 'events are append-only'); END;`)
+	return err
+}
+
+// Append добавляет события агрегата, если его текущая версия равна
+// expectedVersion (оптимистичная блокировка). Если другой запрос успел
+// записать событие раньше, UNIQUE (aggregate_id, version) не даст
+// вставить ту же версию второй раз, и вернется ErrConcurrency.
+func (s *EventStore) Append(ctx context.Context, aggregateID string, expectedVersion int, events []NewEvent) ([]StoredEvent, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	
+	var current int
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM events WHERE aggregate_id = ?`,
+		aggregateID).Scan(&current)
+	if err != nil {
+		return nil, err
+	}
+	if current != expectedVersion {
+		return nil, ErrConcurrency
+	}
+	
+	now := s.now()
+	stored := make([]StoredEvent, 0, len(events))
+	for i, e := range events {
+		data, err := json.Marshal(e.Data)
+		if err != nil {
+			return nil, err
+		}
+		se := StoredEvent{
+			AggregateID: aggregateID,
+			Version:     expectedVersion + i + 1,
+			Type:        e.Type,
+			Data:        data,
+			RecordedAt:  now,
+		}
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO events (aggregate_id, version, type, data, recorded_at)
+			VALUES (?, ?, ?, ?, ?) RETURNING position`,
+			se.AggregateID, se.Version, se.Type, string(se.Data), now.UnixMilli()).Scan(&se.Position)
+		if err != nil {
+			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				return nil, ErrConcurrency
+			}
+			return nil, fmt.Errorf("append event: %w", err)
+		}
+		stored = append(stored, se)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	
+	select {
+	case s.notify <- struct{}{}:
+	default: // Проекция уже разбужена
+	}
+	return stored, nil
+}
+
+// Load возвращает историю агрегата
+func (s *EventStore) Load(ctx context.Context, aggregateID string) ([]StoredEvent, error) {
+	return s.query(ctx, `
+		SELECT position, aggregate_id, version, type, data, recorded_at
+		FROM events WHERE aggregate_id = ? ORDER BY version`, aggregateID)
+}
+
+// ReadFrom возвращает до limit событий всех агрегатов после позиции
+func (s *EventStore) ReadFrom(ctx context.Context, after int64, limit int) ([]StoredEvent, error) {
+	return s.query(ctx, `
+		SELECT position, aggregate_id, version, type, data, recorded_at
+		FROM events WHERE position > ? ORDER BY position LIMIT ?`, after, limit)
+}
+
+func (s *EventStore) query(ctx context.Context, query string, args ...any) ([]StoredEvent, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var events []StoredEvent
+	for rows.Next() {
+		var e StoredEvent
+		var data string
+		var recordedAt int64
+		if err := rows.Scan(&e.Position, &e.AggregateID, &e.Version, &e.Type, &data, &recordedAt); err != nil {
+			return nil, err
+		}
+		e.Data = json.RawMessage(data)
+		e.RecordedAt = time.UnixMilli(recordedAt)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+//go:build unix
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup запускает команду в собственной группе процессов
+// и при отмене контекста убивает всю группу, включая внуков
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		// Отрицательный PID - сигнал всей группе
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
\ No newline at end of file
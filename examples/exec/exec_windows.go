@@ -0,0 +1,16 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// killProcessGroup в Windows групп процессов в смысле Unix нет;
+// дерево процессов убиваем через taskkill /T (или Job Objects из golang.org/x/sys/windows)
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+	}
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Примеры используют sh и стандартные утилиты Unix.
+// В Windows команды нужно заменить (cmd /C ..., PowerShell).
+
+// Пример 1: Раздельный захват stdout и stderr
+func captureOutput() {
+	fmt.Println("=== Захват stdout и stderr ===")
+	
+	cmd := exec.Command("sh", "-c", "echo результат; echo предупреждение >&2")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	
+	if err := cmd.Run(); err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	fmt.Printf("stdout: %q\nstderr: %q\n", stdout.String(), stderr.String())
+	
+	// Output - короткий путь, если нужен только stdout;
+	// stderr при ошибке окажется в ExitError.Stderr
+	out, err := exec.Command("sh", "-c", "echo ok").Output()
+	fmt.Printf("Output(): %q, err=%v\n", out, err)
+}
+
+// Пример 2: Построчная обработка вывода по мере появления
+func streamOutput() {
+	fmt.Println("\n=== Потоковый вывод ===")
+	
+	cmd := exec.Command("sh", "-c", "for i in 1 2 3; do echo строка $i; sleep 0.2; done")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	
+	// Читаем до EOF и только потом вызываем Wait: Wait закрывает pipe
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		fmt.Printf("[%v] %s\n", time.Since(start).Round(100*time.Millisecond), scanner.Text())
+	}
+	
+	if err := cmd.Wait(); err != nil {
+		fmt.Println("Ошибка:", err)
+	}
+}
+
+// Пример 3: Передача данных на stdin
+func stdinPipe() {
+	fmt.Println("\n=== stdin ===")
+	
+	// Простой случай - готовые данные
+	cmd := exec.Command("sort")
+	cmd.Stdin = strings.NewReader("banana\napple\ncherry\n")
+	out, _ := cmd.Output()
+	fmt.Printf("sort: %q\n", out)
+	
+	// Запись по частям через StdinPipe
+	cmd = exec.Command("wc", "-l")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	var result bytes.Buffer
+	cmd.Stdout = &result
+	
+	if err := cmd.Start(); err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(stdin, "line %d\n", i)
+	}
+	stdin.Close() // Без Close wc не получит EOF и будет ждать вечно
+	
+	cmd.Wait()
+	fmt.Printf("wc -l: %s", result.String())
+}
+
+// Пример 4: Окружение и рабочий каталог
+func envAndDir() {
+	fmt.Println("\n=== Окружение и каталог ===")
+	
+	cmd := exec.Command("sh", "-c", "echo $APP_MODE; pwd")
+	// Env == nil - наследовать окружение; иначе - ровно этот список
+	cmd.Env = append(os.Environ(), "APP_MODE=production")
+	cmd.Dir = os.TempDir()
+	
+	out, err := cmd.Output()
+	fmt.Printf("%s(err=%v)\n", out, err)
+	
+	// LookPath - где будет найдена команда (и есть ли она вообще)
+	if path, err := exec.LookPath("git"); err == nil {
+		fmt.Println("git:", path)
+	} else {
+		fmt.Println("git не найден:", err)
+	}
+}
+
+// Пример 5: Код возврата
+func exitCodes() {
+	fmt.Println("\n=== Код возврата ===")
+	
+	for _, script := range []string{"exit 0", "echo fail >&2; exit 3", "kill -9 $$"} {
+		err := exec.Command("sh", "-c", script).Run()
+		
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			fmt.Printf("%-22q успех\n", script)
+		case errors.As(err, &exitErr):
+			// ExitCode() == -1, если процесс убит сигналом
+			fmt.Printf("%-22q код %d (%v)\n", script, exitErr.ExitCode(), exitErr)
+		default:
+			// Команда не запустилась: нет файла, нет прав
+			fmt.Printf("%-22q не запущена: %v\n", script, err)
+		}
+	}
+	
+	err := exec.Command("no-such-command").Run()
+	fmt.Println("Нет команды:", err, errors.Is(err, exec.ErrNotFound))
+}
+
+// Пример 6: Отмена по контексту и уборка дочерних процессов
+func cancelWithContext() {
+	fmt.Println("\n=== Отмена и группа процессов ===")
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	
+	// sh запускает внука; по умолчанию CommandContext убивает только sh,
+	// а sleep остается сиротой и держит stdout открытым
+	cmd := exec.CommandContext(ctx, "sh", "-c", "sleep 30 & echo внук $!; wait")
+	cmd.Stdout = os.Stdout
+	killProcessGroup(cmd)
+	// Если кто-то держит pipe после убийства, Wait вернется не позже чем через WaitDelay
+	cmd.WaitDelay = time.Second
+	
+	start := time.Now()
+	err := cmd.Run()
+	fmt.Printf("Завершено через %v: %v\n", time.Since(start).Round(100*time.Millisecond), err)
+}
+
+// Пример 7: Конвейер cmd1 | cmd2
+func pipeline() {
+	fmt.Println("\n=== Конвейер ===")
+	
+	producer := exec.Command("printf", "b\\na\\nb\\nc\\n")
+	consumer := exec.Command("sort", "-u")
+	
+	pr, pw := io.Pipe()
+	producer.Stdout = pw
+	consumer.Stdin = pr
+	var out bytes.Buffer
+	consumer.Stdout = &out
+	
+	producer.Start()
+	consumer.Start()
+	producer.Wait()
+	pw.Close()
+	consumer.Wait()
+	
+	fmt.Printf("printf | sort -u: %q\n", out.String())
+}
+
+func main() {
+	captureOutput()
+	streamOutput()
+	stdinPipe()
+	envAndDir()
+	exitCodes()
+	cancelWithContext()
+	pipeline()
+}
\ No newline at end of file
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"runtime"
+	"time"
+
+	"streamstats"
+)
+
+// Импорт expvar регистрирует обработчик /debug/vars в http.DefaultServeMux
+// и публикует две переменные: cmdline и memstats.
+
+// Пример 1: Встроенные типы переменных
+var (
+	requestsTotal = expvar.NewInt("requests_total")
+	errorsTotal   = expvar.NewInt("errors_total")
+	lastLatency   = expvar.NewFloat("last_latency_ms")
+	buildVersion  = expvar.NewString("version")
+
+	// Map - набор счетчиков с ключами, аналог метки в Prometheus
+	requestsByPath = expvar.NewMap("requests_by_path")
+)
+
+// Пример 2: Собственный тип, реализующий expvar.Var.
+// String() должен возвращать корректный JSON.
+// Перцентили считаются потоково (P², см. examples/streamstats): память
+// постоянна и чтение не сортирует накопленные значения.
+type LatencyStats struct {
+	p50, p95, p99 *streamstats.P2
+	avg           *streamstats.EWMA
+}
+
+func NewLatencyStats() *LatencyStats {
+	return &LatencyStats{
+		p50: streamstats.NewP2(0.5),
+		p95: streamstats.NewP2(0.95),
+		p99: streamstats.NewP2(0.99),
+		avg: streamstats.NewEWMA(10 * time.Second),
+	}
+}
+
+func (l *LatencyStats) Observe(ms float64) {
+	l.p50.Add(ms)
+	l.p95.Add(ms)
+	l.p99.Add(ms)
+	l.avg.Observe(ms, time.Now())
+}
+
+func (l *LatencyStats) String() string {
+	b, _ := json.Marshal(map[string]any{
+		"count": l.p50.Count(),
+		"p50":   l.p50.Value(),
+		"p95":   l.p95.Value(),
+		"p99":   l.p99.Value(),
+		"ewma":  l.avg.Value(),
+	})
+	return string(b)
+}
+
+var (
+	latency = NewLatencyStats()
+	
+	// Текущая нагрузка за последние 10 секунд; requests_total растет
+	// монотонно, и rps из него пришлось бы считать на стороне читателя
+	requestRate = streamstats.NewRateCounter(10*time.Second, 10)
+)
+
+func init() {
+	buildVersion.Set("1.2.0")
+	expvar.Publish("latency_ms", latency)
+	
+	// Func - значение вычисляется при каждом чтении /debug/vars
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+	
+	expvar.Publish("requests_per_second", expvar.Func(func() any {
+		return requestRate.Rate()
+	}))
+	
+	start := time.Now()
+	expvar.Publish("uptime_seconds", expvar.Func(func() any {
+		return int64(time.Since(start).Seconds())
+	}))
+}
+
+// instrument считает запросы, ошибки и задержку
+func instrument(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		
+		next(rec, r)
+		
+		ms := float64(time.Since(start).Microseconds()) / 1000
+		requestsTotal.Add(1)
+		requestRate.Add(1)
+		requestsByPath.Add(r.URL.Path, 1)
+		if rec.status >= 500 {
+			errorsTotal.Add(1)
+		}
+		lastLatency.Set(ms)
+		latency.Observe(ms)
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func usersHandler(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(time.Duration(rand.Intn(20)) * time.Millisecond)
+	fmt.Fprintln(w, `[{"id":1,"name":"Иван"}]`)
+}
+
+func flakyHandler(w http.ResponseWriter, r *http.Request) {
+	if rand.Intn(3) == 0 {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// Пример 3: Клиент, опрашивающий /debug/vars
+func pollVars(baseURL string, interval time.Duration, times int) {
+	fmt.Println("\n=== Опрос /debug/vars ===")
+	
+	var prev int64
+	for i := 0; i < times; i++ {
+		time.Sleep(interval)
+		
+		resp, err := http.Get(baseURL + "/debug/vars")
+		if err != nil {
+			log.Println("Ошибка опроса:", err)
+			continue
+		}
+		
+		var vars struct {
+			RequestsTotal  int64            `json:"requests_total"`
+			ErrorsTotal    int64            `json:"errors_total"`
+			RequestsByPath map[string]int64 `json:"requests_by_path"`
+			Latency        struct {
+				Count int     `json:"count"`
+				P95   float64 `json:"p95"`
+			} `json:"latency_ms"`
+			Goroutines int `json:"goroutines"`
+			MemStats   struct {
+				HeapAlloc uint64 `json:"HeapAlloc"`
+			} `json:"memstats"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&vars)
+		resp.Body.Close()
+		if err != nil {
+			log.Println("Ошибка разбора:", err)
+			continue
+		}
+		
+		// Счетчики монотонны; скорость клиент считает сам по разнице
+		rps := float64(vars.RequestsTotal-prev) / interval.Seconds()
+		prev = vars.RequestsTotal
+		fmt.Printf("запросов: %4d (%5.1f/с), ошибок: %3d, p95: %5.1fмс, горутин: %d, куча: %d KiB, по путям: %v\n",
+			vars.RequestsTotal, rps, vars.ErrorsTotal, vars.Latency.P95,
+			vars.Goroutines, vars.MemStats.HeapAlloc>>10, vars.RequestsByPath)
+	}
+}
+
+// generateTraffic отправляет запросы, пока не закроется stop
+func generateTraffic(baseURL string, stop <-chan struct{}) {
+	paths := []string{"/api/users", "/api/flaky"}
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		resp, err := http.Get(baseURL + paths[rand.Intn(len(paths))])
+		if err == nil {
+			resp.Body.Close()
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Сравнение с Prometheus (github.com/prometheus/client_golang):
+//
+//   expvar                              Prometheus
+//   ---------------------------------   ---------------------------------------
+//   стандартная библиотека              внешняя зависимость
+//   произвольный JSON                   текстовый формат с типами метрик
+//   expvar.Map вместо меток             метки: requests_total{path="/api"}
+//   нет гистограмм, квантили считаем     Histogram/Summary с бакетами,
+//   сами (LatencyStats выше)            агрегируемые между инстансами
+//   клиент сам считает rate             rate() и алерты в PromQL
+//   глобальный реестр, Publish          Registry, можно несколько
+//   паникует при повторном имени        MustRegister тоже паникует,
+//                                       Register возвращает ошибку
+//
+// Тот же счетчик в Prometheus:
+//
+//   var requests = promauto.NewCounterVec(prometheus.CounterOpts{
+//       Name: "http_requests_total",
+//       Help: "Число HTTP-запросов",
+//   }, []string{"path", "code"})
+//
+//   requests.WithLabelValues(r.URL.Path, strconv.Itoa(status)).Inc()
+//   http.Handle("/metrics", promhttp.Handler())
+//
+// expvar хорош для быстрой отладки и внутренних инструментов;
+// для мониторинга в продакшене обычно выбирают Prometheus или OpenTelemetry.
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users", instrument(usersHandler))
+	mux.HandleFunc("/api/flaky", instrument(flakyHandler))
+	// Свой mux не содержит /debug/vars - регистрируем обработчик явно
+	mux.Handle("/debug/vars", expvar.Handler())
+	
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		log.Fatal("Ошибка запуска сервера:", err)
+	}
+	baseURL := "http://" + ln.Addr().String()
+	go http.Serve(ln, mux)
+	
+	fmt.Println("=== expvar ===")
+	fmt.Printf("Переменные: %s/debug/vars\n", baseURL)
+	
+	stop := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		go generateTraffic(baseURL, stop)
+	}
+	
+	pollVars(baseURL, 500*time.Millisecond, 5)
+	close(stop)
+	
+	// Переменные можно читать и из кода
+	fmt.Println("\n=== Чтение из кода ===")
+	expvar.Do(func(kv expvar.KeyValue) {
+		if kv.Key == "memstats" || kv.Key == "cmdline" {
+			return
+		}
+		fmt.Printf("%s = %s\n", kv.Key, kv.Value)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// Инварианты вместо конкретных ожидаемых значений: фазер генерирует
+// произвольные входы, поэтому проверяем свойства, верные для любого входа.
+
+func FuzzParseEmail(f *testing.F) {
+	// Seed corpus: дополняется файлами из testdata/fuzz/FuzzParseEmail
+	f.Add("ivan@example.com")
+	f.Add("a@b.c")
+	f.Add("@example.com")
+	f.Add("no-at-sign")
+	
+	f.Fuzz(func(t *testing.T, s string) {
+		e, err := ParseEmail(s)
+		if err != nil {
+			return
+		}
+		
+		if e.Local == "" || e.Domain == "" {
+			t.Fatalf("ParseEmail(%q) вернул пустые части: %+v", s, e)
+		}
+		
+		// Разбор и сборка должны давать исходную строку
+		if e.String() != s {
+			t.Fatalf("round-trip: ParseEmail(%q).String() = %q", s, e.String())
+		}
+		
+		// Повторный разбор дает тот же результат
+		again, err := ParseEmail(e.String())
+		if err != nil || again != e {
+			t.Fatalf("повторный разбор %q: %+v, %v", e.String(), again, err)
+		}
+	})
+}
+
+func FuzzParseURL(f *testing.F) {
+	f.Add("http://example.com")
+	f.Add("https://example.com:8443/api/users")
+	f.Add("ftp://example.com")
+	f.Add("http://:80/")
+	
+	f.Fuzz(func(t *testing.T, s string) {
+		u, err := ParseURL(s)
+		if err != nil {
+			return
+		}
+		
+		if u.Port < 0 || u.Port > 65535 {
+			t.Fatalf("ParseURL(%q): порт вне диапазона: %d", s, u.Port)
+		}
+		if !strings.HasPrefix(u.Path, "/") {
+			t.Fatalf("ParseURL(%q): путь без ведущего /: %q", s, u.Path)
+		}
+		
+		again, err := ParseURL(u.String())
+		if err != nil {
+			t.Fatalf("ParseURL(%q) не разбирает собственный вывод %q: %v", s, u.String(), err)
+		}
+		if again != u {
+			t.Fatalf("повторный разбор %q: %+v != %+v", u.String(), again, u)
+		}
+	})
+}
+
+func FuzzContains(f *testing.F) {
+	f.Add("golang", "lang")
+	f.Add("", "")
+	f.Add("abc", "abcd")
+	
+	f.Fuzz(func(t *testing.T, s, substr string) {
+		// strings.Contains служит эталоном (differential fuzzing)
+		if got, want := contains(s, substr), strings.Contains(s, substr); got != want {
+			t.Fatalf("contains(%q, %q) = %v, strings.Contains = %v", s, substr, got, want)
+		}
+	})
+}
+
+func FuzzReverse(f *testing.F) {
+	// Только ASCII: с таким корпусом обычный go test проходит
+	f.Add("hello")
+	f.Add("")
+	f.Add("!12345")
+	
+	f.Fuzz(func(t *testing.T, s string) {
+		rev := Reverse(s)
+		if utf8.ValidString(s) && !utf8.ValidString(rev) {
+			t.Fatalf("Reverse(%q) = %q: невалидный UTF-8", s, rev)
+		}
+		if Reverse(rev) != s {
+			t.Fatalf("Reverse(Reverse(%q)) != исходной строке", s)
+		}
+	})
+}
+
+func FuzzReverseRunes(f *testing.F) {
+	f.Add("hello")
+	f.Add("привет")
+	
+	f.Fuzz(func(t *testing.T, s string) {
+		// Для невалидного UTF-8 []rune заменяет байты на U+FFFD,
+		// поэтому инвариант проверяем только для валидных строк
+		if !utf8.ValidString(s) {
+			return
+		}
+		rev := ReverseRunes(s)
+		if !utf8.ValidString(rev) {
+			t.Fatalf("ReverseRunes(%q) = %q: невалидный UTF-8", s, rev)
+		}
+		if ReverseRunes(rev) != s {
+			t.Fatalf("ReverseRunes(ReverseRunes(%q)) != исходной строке", s)
+		}
+	})
+}
\ No newline at end of file
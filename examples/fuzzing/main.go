@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Email разобранный email адрес
+type Email struct {
+	Local  string
+	Domain string
+}
+
+// String собирает адрес обратно
+func (e Email) String() string {
+	return e.Local + "@" + e.Domain
+}
+
+// ParseEmail разбирает email адрес (упрощенно, без RFC 5322)
+func ParseEmail(s string) (Email, error) {
+	at := strings.LastIndex(s, "@")
+	if at <= 0 || at == len(s)-1 {
+		return Email{}, errors.New("адрес должен иметь вид local@domain")
+	}
+	
+	local, domain := s[:at], s[at+1:]
+	if strings.ContainsAny(local, "@ \t\r\n") {
+		return Email{}, errors.New("недопустимые символы в локальной части")
+	}
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return Email{}, errors.New("домен должен содержать точку")
+	}
+	for _, r := range domain {
+		if !(r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return Email{}, fmt.Errorf("недопустимый символ %q в домене", r)
+		}
+	}
+	
+	return Email{Local: local, Domain: domain}, nil
+}
+
+// URL разобранный адрес вида scheme://host[:port]/path
+type URL struct {
+	Scheme string
+	Host   string
+	Port   int
+	Path   string
+}
+
+// ParseURL разбирает упрощенный URL
+func ParseURL(s string) (URL, error) {
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok || scheme == "" {
+		return URL{}, errors.New("нет схемы")
+	}
+	if scheme != "http" && scheme != "https" {
+		return URL{}, fmt.Errorf("неподдерживаемая схема %q", scheme)
+	}
+	
+	hostport, path := rest, "/"
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		hostport, path = rest[:i], rest[i:]
+	}
+	
+	host, portStr, hasPort := strings.Cut(hostport, ":")
+	if host == "" {
+		return URL{}, errors.New("пустой хост")
+	}
+	
+	u := URL{Scheme: scheme, Host: host, Path: path}
+	if hasPort {
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			return URL{}, fmt.Errorf("неверный порт %q", portStr)
+		}
+		u.Port = port
+	}
+	
+	return u, nil
+}
+
+// String собирает URL обратно
+func (u URL) String() string {
+	if u.Port != 0 {
+		return fmt.Sprintf("%s://%s:%d%s", u.Scheme, u.Host, u.Port, u.Path)
+	}
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// contains - тот же ручной поиск подстроки, что и в examples/http-server
+func contains(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
+
+// Reverse переворачивает строку. Содержит намеренную ошибку:
+// разворачивает байты, а не руны, и ломает многобайтовые символы UTF-8.
+// Тесты с ASCII-строками проходят, фазер находит ошибку за секунды.
+func Reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// ReverseRunes исправленная версия Reverse
+func ReverseRunes(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// Пример 1: Функции, которые будем фаззить
+func showTargets() {
+	fmt.Println("=== Функции под фаззингом ===")
+	
+	if e, err := ParseEmail("ivan@example.com"); err == nil {
+		fmt.Printf("ParseEmail: local=%s domain=%s\n", e.Local, e.Domain)
+	}
+	
+	if u, err := ParseURL("https://example.com:8443/api/users"); err == nil {
+		fmt.Printf("ParseURL: %+v\n", u)
+	}
+	
+	fmt.Println("contains(\"golang\", \"lang\") =", contains("golang", "lang"))
+	fmt.Println("Reverse(\"hello\") =", Reverse("hello"))
+	fmt.Println("Reverse(\"привет\") =", Reverse("привет"), "<- ошибка!")
+	fmt.Println("ReverseRunes(\"привет\") =", ReverseRunes("привет"))
+}
+
+// Пример 2: Как запускать фаззинг
+func printInstructions() {
+	fmt.Println("\n=== Как запускать фаззинг ===")
+	fmt.Println(`
+Команды запускаются из examples/fuzzing: фаззингу нужен модуль (go.mod).
+
+Обычный go test прогоняет только seed corpus (f.Add и testdata/fuzz):
+    go test -run=Fuzz -v
+
+Фаззинг одной цели (флаг -fuzz принимает регулярное выражение,
+и оно должно совпадать ровно с одной функцией):
+    go test -run=^$ -fuzz=^FuzzParseEmail$ -fuzztime=30s
+    go test -run=^$ -fuzz=^FuzzParseURL$ -fuzztime=30s
+    go test -run=^$ -fuzz=^FuzzContains$ -fuzztime=30s
+
+Найти намеренную ошибку в Reverse:
+    go test -run=^$ -fuzz=^FuzzReverse$
+
+Фазер сохранит падающий вход в testdata/fuzz/FuzzReverse/<hash>.
+С этого момента обычный go test тоже падает на нем - это регрессионный тест.
+Воспроизвести конкретный случай:
+    go test -run=FuzzReverse/<hash>
+
+Исправленная версия проходит фаззинг:
+    go test -run=^$ -fuzz=^FuzzReverseRunes$ -fuzztime=30s`)
+}
+
+func main() {
+	showTargets()
+	printInstructions()
+}
\ No newline at end of file
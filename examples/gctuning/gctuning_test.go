@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+	"runtime/metrics"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig("  GOGC=off   GOMEMLIMIT=100MiB ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.name != "GOGC=off GOMEMLIMIT=100MiB" {
+		t.Errorf("Unexpected name %q", c.name)
+	}
+	if !slices.Equal(c.env, []string{"GOGC=off", "GOMEMLIMIT=100MiB"}) {
+		t.Errorf("Unexpected env %q", c.env)
+	}
+	for _, s := range []string{"", "GOGC", "=50", "GOGC=50 off"} {
+		if _, err := parseConfig(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestChildEnv(t *testing.T) {
+	t.Setenv("GOGC", "10")
+	t.Setenv("GOMEMLIMIT", "1GiB")
+	c, _ := parseConfig("GOGC=200")
+	env := childEnv(c)
+	if slices.Contains(env, "GOGC=10") || slices.Contains(env, "GOMEMLIMIT=1GiB") {
+		t.Errorf("Expected parent GOGC and GOMEMLIMIT to be dropped, got %q", env)
+	}
+	if !slices.Contains(env, "GOGC=200") || !slices.Contains(env, envChild+"=1") {
+		t.Errorf("Expected config and child marker in env, got %q", env)
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected byteSize
+	}{
+		{"64MiB", 64 << 20},
+		{"2GiB", 2 << 30},
+		{"512KiB", 512 << 10},
+		{"100B", 100},
+		{"4096", 4096},
+	}
+	for _, tt := range tests {
+		var b byteSize
+		if err := b.Set(tt.in); err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if b != tt.expected {
+			t.Errorf("%q: expected %d, got %d", tt.in, tt.expected, b)
+		}
+	}
+	for _, in := range []string{"", "MiB", "-1MiB", "10MB"} {
+		var b byteSize
+		if err := b.Set(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Buckets: []float64{math.Inf(-1), 1, 2, 4, math.Inf(1)},
+		Counts:  []uint64{0, 98, 1, 1},
+	}
+	tests := []struct {
+		q        float64
+		expected float64
+	}{
+		{0.5, 2},
+		{0.98, 2},
+		{0.99, 4},
+		{1, 4}, // Последняя корзина до +Inf - нижняя граница
+	}
+	for _, tt := range tests {
+		if got := quantile(h, tt.q); got != tt.expected {
+			t.Errorf("q=%v: expected %v, got %v", tt.q, tt.expected, got)
+		}
+	}
+	if got := histogramSum(h); got != 98*1.5+3+4 {
+		t.Errorf("Unexpected sum %v", got)
+	}
+	if got := quantile(&metrics.Float64Histogram{Buckets: []float64{0, 1}, Counts: []uint64{0}}, 0.5); got != 0 {
+		t.Errorf("Expected 0 for empty histogram, got %v", got)
+	}
+}
+
+func TestWorkload(t *testing.T) {
+	r := workload(1<<20, 32<<20)
+	if r.NumGC == 0 {
+		t.Error("Expected at least one GC cycle")
+	}
+	if r.PeakHeap < 1<<20 {
+		t.Errorf("Expected peak heap above live data, got %d", r.PeakHeap)
+	}
+	if r.Elapsed <= 0 || r.PauseMax < r.PauseP50 || r.PauseMax > time.Second {
+		t.Errorf("Unexpected report %+v", r)
+	}
+}
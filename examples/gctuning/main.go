@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Стенд для настройки GC: одна и та же нагрузка с выделениями памяти
+// прогоняется при разных GOGC и GOMEMLIMIT, и результаты сводятся в
+// таблицу - время, число циклов GC, паузы, доля процессора на GC, пик
+// кучи и пик RSS.
+//
+// Каждая настройка - отдельный процесс: программа перезапускает сама
+// себя с переменными окружения. Менять их через debug.SetGCPercent в
+// одном процессе (как в examples/runtime) проще, но куча, полученная
+// от ОС в прошлом прогоне, и накопленная статистика пауз достаются
+// следующему, а пик RSS у процесса один на всех.
+//
+//   - workload.go - нагрузка и сбор статистики из runtime/metrics;
+//   - rss_unix.go - пик RSS дочернего процесса из rusage.
+//
+// Запуск: go run .
+// Больше живых данных и выделений: go run . -live 256MiB -total 4GiB
+// Свои настройки - аргументами, по одной на аргумент:
+//
+//	go run . "GOGC=100" "GOGC=off GOMEMLIMIT=300MiB" "GOGC=50 GOMEMLIMIT=1GiB"
+//
+// Что смотреть в таблице:
+//   - GOGC меняет компромисс память/процессор: куча растет до
+//     живые*(1+GOGC/100), меньше GOGC - больше циклов и доля GC, но
+//     меньше пик;
+//   - GOMEMLIMIT при GOGC=off - GC только у лимита: циклов мало, пока
+//     живых данных заметно меньше лимита;
+//   - лимит ниже живых данных - GC работает почти непрерывно (death
+//     spiral); рантайм ограничивает его половиной процессора, лимит
+//     при этом превышается - он мягкий;
+//   - паузы STW почти не зависят от настроек: они короткие, основная
+//     работа GC идет параллельно программе. Цена GC - доля процессора.
+//
+// Подробный лог каждого цикла: GODEBUG=gctrace=1 в настройке, вывод
+// дочернего процесса идет в stderr.
+
+// envChild признак дочернего процесса с одной нагрузкой
+const envChild = "GCTUNING_CHILD"
+
+// config одна настройка GC: строка вида "GOGC=off GOMEMLIMIT=100MiB"
+type config struct {
+	name string
+	env  []string
+}
+
+// parseConfig разбирает настройку из аргумента командной строки
+func parseConfig(s string) (config, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return config{}, errors.New("пустая настройка")
+	}
+	for _, f := range fields {
+		if k, _, ok := strings.Cut(f, "="); !ok || k == "" {
+			return config{}, fmt.Errorf("ожидалось ИМЯ=значение, получено %q", f)
+		}
+	}
+	return config{name: strings.Join(fields, " "), env: fields}, nil
+}
+
+// defaultConfigs набор настроек относительно объема живых данных
+func defaultConfigs(live int64) []config {
+	mib := func(n int64) string { return fmt.Sprintf("%dMiB", n>>20) }
+	var configs []config
+	for _, s := range []string{
+		"GOGC=100",
+		"GOGC=50",
+		"GOGC=200",
+		"GOGC=400",
+		"GOGC=100 GOMEMLIMIT=" + mib(live*3/2),
+		"GOGC=off GOMEMLIMIT=" + mib(live*2),
+		"GOGC=off GOMEMLIMIT=" + mib(live*4),
+		"GOGC=off GOMEMLIMIT=" + mib(live*3/4), // Лимит ниже живых данных
+	} {
+		c, _ := parseConfig(s)
+		configs = append(configs, c)
+	}
+	return configs
+}
+
+// childEnv окружение дочернего процесса: GOGC и GOMEMLIMIT родителя
+// убираются, чтобы настройка без одной из них получала значение по
+// умолчанию, а не унаследованное
+func childEnv(c config) []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if k != "GOGC" && k != "GOMEMLIMIT" && k != envChild {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, envChild+"=1")
+	return append(env, c.env...)
+}
+
+// run перезапускает программу с настройкой c и читает отчет из stdout
+func run(c config, live, total int64) (report, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return report{}, err
+	}
+	cmd := exec.Command(exe, "-live", fmt.Sprint(live), "-total", fmt.Sprint(total))
+	cmd.Env = childEnv(c)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return report{}, fmt.Errorf("%s: %w", c.name, err)
+	}
+	var r report
+	if err := json.Unmarshal(out, &r); err != nil {
+		return report{}, fmt.Errorf("%s: отчет: %w", c.name, err)
+	}
+	r.RSS = peakRSS(cmd.ProcessState)
+	return r, nil
+}
+
+// child нагрузка в дочернем процессе, отчет - одной строкой JSON
+func child(live, total int64) {
+	r := workload(int(live), int(total))
+	if err := json.NewEncoder(os.Stdout).Encode(r); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// printTable сводная таблица по всем настройкам
+func printTable(configs []config, reports []report) {
+	fmt.Printf("%-32s %9s %6s %9s %9s %9s %9s %6s %10s %10s\n",
+		"настройка", "время", "GC", "Σ пауз", "пауза50", "пауза99", "паузаMax", "GC CPU", "пик кучи", "пик RSS")
+	for i, r := range reports {
+		rss := "н/д"
+		if r.RSS > 0 {
+			rss = formatBytes(r.RSS)
+		}
+		fmt.Printf("%-32s %9v %6d %9v %9v %9v %9v %5.1f%% %10s %10s\n",
+			configs[i].name, r.Elapsed.Round(time.Millisecond), r.NumGC, r.PauseTotal.Round(time.Microsecond),
+			r.PauseP50, r.PauseP99, r.PauseMax, r.GCCPU*100, formatBytes(r.PeakHeap), rss)
+	}
+	fmt.Println("Паузы - верхние границы корзин гистограммы /sched/pauses/total/gc:seconds")
+}
+
+// byteSize значение флага в байтах: 64MiB, 1GiB или просто число
+type byteSize int64
+
+func (b *byteSize) String() string { return formatBytes(uint64(*b)) }
+
+func (b *byteSize) Set(s string) error {
+	units := []struct {
+		suffix string
+		mult   int64
+	}{{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1}}
+	mult := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSuffix(s, u.suffix), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("ожидался размер вида 64MiB, получено %q", s)
+	}
+	*b = byteSize(n * mult)
+	return nil
+}
+
+func formatBytes(b uint64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fGiB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.1fMiB", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.1fKiB", float64(b)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", b)
+	}
+}
+
+func main() {
+	live, total := byteSize(64<<20), byteSize(2<<30)
+	flag.Var(&live, "live", "объем долгоживущих данных")
+	flag.Var(&total, "total", "сколько всего выделить за прогон")
+	flag.Parse()
+	
+	if os.Getenv(envChild) != "" {
+		child(int64(live), int64(total))
+		return
+	}
+	
+	configs := defaultConfigs(int64(live))
+	if flag.NArg() > 0 {
+		configs = configs[:0]
+		for _, arg := range flag.Args() {
+			c, err := parseConfig(arg)
+			if err != nil {
+				log.Fatal(err)
+			}
+			configs = append(configs, c)
+		}
+	}
+	
+	fmt.Printf("Живых данных %s, выделений за прогон %s\n", live.String(), total.String())
+	reports := make([]report, 0, len(configs))
+	for _, c := range configs {
+		fmt.Printf("  %s...\n", c.name)
+		r, err := run(c, int64(live), int64(total))
+		if err != nil {
+			log.Fatal(err)
+		}
+		reports = append(reports, r)
+	}
+	fmt.Println()
+	printTable(configs, reports)
+}
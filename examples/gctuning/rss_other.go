@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// peakRSS на Windows rusage нет; пиковый рабочий набор отдает
+// GetProcessMemoryInfo, но только для живого процесса
+func peakRSS(*os.ProcessState) uint64 {
+	return 0
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// peakRSS пик резидентной памяти завершившегося процесса - сколько
+// физической памяти он занимал на самом деле, вместе со стеками,
+// структурами рантайма и страницами кучи, которые GC еще не вернул ОС
+func peakRSS(state *os.ProcessState) uint64 {
+	ru, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// На macOS ru_maxrss в байтах, на Linux и BSD - в килобайтах
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return uint64(ru.Maxrss)
+	}
+	return uint64(ru.Maxrss) << 10
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+// report итог прогона нагрузки в дочернем процессе. Пик RSS дочерний
+// процесс про себя не пишет - его берет родитель из rusage после выхода.
+type report struct {
+	Elapsed    time.Duration `json:"elapsed"`
+	NumGC      uint64        `json:"num_gc"`
+	PauseTotal time.Duration `json:"pause_total"`
+	PauseP50   time.Duration `json:"pause_p50"`
+	PauseP99   time.Duration `json:"pause_p99"`
+	PauseMax   time.Duration `json:"pause_max"`
+	GCCPU      float64       `json:"gc_cpu"`    // Доля процессорного времени на GC
+	PeakHeap   uint64        `json:"peak_heap"` // Максимум памяти под объекты кучи
+	RSS        uint64        `json:"-"`
+}
+
+// sink приемник временных объектов: без него компилятор мог бы
+// разместить их на стеке, и нагрузки на GC бы не было
+var sink []byte
+
+// workload нагрузка как у типичного сервиса: live байт долгоживущих
+// данных (кеш, сессии) и поток короткоживущих объектов разного размера
+// (разбор запросов, буферы ответов) - всего total байт. Каждый восьмой
+// временный объект вытесняет старый из долгоживущих, так что живой
+// набор не стоит на месте и сборщику есть что помечать.
+func workload(live, total int) report {
+	const avgSize = 1 << 10
+	rng := rand.New(rand.NewSource(1)) // Одинаковая нагрузка во всех прогонах
+	ring := make([][]byte, max(1, live/avgSize))
+	for i := range ring {
+		ring[i] = make([]byte, avgSize)
+	}
+	runtime.GC()
+	
+	heap := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	var peak uint64
+	start := time.Now()
+	for i, allocated := 0, 0; allocated < total; i++ {
+		size := 64 + rng.Intn(2*avgSize-64)
+		buf := make([]byte, size)
+		buf[0] = byte(i)
+		if i%8 == 0 {
+			ring[rng.Intn(len(ring))] = buf
+		} else {
+			sink = buf
+		}
+		allocated += size
+	
+		// runtime/metrics не останавливает мир, но и не бесплатен:
+		// пик кучи достаточно смотреть раз в несколько тысяч выделений
+		if i%4096 == 0 {
+			metrics.Read(heap)
+			peak = max(peak, heap[0].Value.Uint64())
+		}
+	}
+	elapsed := time.Since(start)
+	
+	r := readGC()
+	r.Elapsed = elapsed
+	r.PeakHeap = peak
+	runtime.KeepAlive(ring)
+	return r
+}
+
+// readGC собирает статистику GC за все время процесса из runtime/metrics
+func readGC() report {
+	samples := []metrics.Sample{
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/sched/pauses/total/gc:seconds"},
+		{Name: "/cpu/classes/gc/total:cpu-seconds"},
+		{Name: "/cpu/classes/total:cpu-seconds"},
+	}
+	metrics.Read(samples)
+	
+	var r report
+	r.NumGC = samples[0].Value.Uint64()
+	if samples[1].Value.Kind() == metrics.KindFloat64Histogram {
+		h := samples[1].Value.Float64Histogram()
+		r.PauseTotal = seconds(histogramSum(h))
+		r.PauseP50 = seconds(quantile(h, 0.5))
+		r.PauseP99 = seconds(quantile(h, 0.99))
+		r.PauseMax = seconds(quantile(h, 1))
+	}
+	if total := samples[3].Value.Float64(); total > 0 {
+		r.GCCPU = samples[2].Value.Float64() / total
+	}
+	return r
+}
+
+// quantile верхняя граница корзины гистограммы, в которую попадает
+// квантиль q. Точных значений runtime/metrics не хранит - только
+// корзины, поэтому p99 здесь - "не больше чем". У последней корзины
+// верхняя граница +Inf, для нее берется нижняя.
+func quantile(h *metrics.Float64Histogram, q float64) float64 {
+	var total uint64
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	need := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for i, c := range h.Counts {
+		seen += c
+		if c > 0 && seen >= need {
+			if math.IsInf(h.Buckets[i+1], 1) {
+				return h.Buckets[i]
+			}
+			return h.Buckets[i+1]
+		}
+	}
+	return 0
+}
+
+// histogramSum оценка суммы значений: середина корзины на число
+// попаданий в нее
+func histogramSum(h *metrics.Float64Histogram) float64 {
+	var sum float64
+	for i, c := range h.Counts {
+		if c == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		switch {
+		case math.IsInf(lo, -1):
+			lo = hi
+		case math.IsInf(hi, 1):
+			hi = lo
+		}
+		sum += (lo + hi) / 2 * float64(c)
+	}
+	return sum
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
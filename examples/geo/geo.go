@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadiusKm средний радиус Земли. Земля - не шар, поэтому у
+// haversine погрешность до 0.5%; для "пользователей рядом" этого хватает.
+const EarthRadiusKm = 6371.0
+
+// Point географическая точка в градусах
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%.4f, %.4f)", p.Lat, p.Lng)
+}
+
+// Valid проверяет диапазоны широты и долготы
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+func rad(deg float64) float64 { return deg * math.Pi / 180 }
+func deg(rad float64) float64 { return rad * 180 / math.Pi }
+
+// Distance возвращает расстояние по большому кругу в километрах.
+// Формула haversine устойчива на малых расстояниях, где закон
+// косинусов теряет точность из-за acos около 1.
+func Distance(a, b Point) float64 {
+	dLat := rad(b.Lat - a.Lat)
+	dLng := rad(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(a.Lat))*math.Cos(rad(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Box прямоугольник в координатах. Если MinLng > MaxLng, прямоугольник
+// пересекает антимеридиан (180°) и по долготе состоит из двух частей.
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// Contains проверяет, что точка внутри прямоугольника
+func (b Box) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLng <= b.MaxLng {
+		return p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+	}
+	return p.Lng >= b.MinLng || p.Lng <= b.MaxLng
+}
+
+// Center возвращает центр прямоугольника (без учета антимеридиана)
+func (b Box) Center() Point {
+	return Point{(b.MinLat + b.MaxLat) / 2, (b.MinLng + b.MaxLng) / 2}
+}
+
+// BoundingBox возвращает прямоугольник, содержащий круг радиуса radiusKm.
+// Прямоугольник - грубый фильтр для индекса в БД: в его углах есть точки
+// дальше radiusKm, поэтому результат нужно дофильтровать через Distance.
+func BoundingBox(center Point, radiusKm float64) Box {
+	dLat := deg(radiusKm / EarthRadiusKm)
+	box := Box{MinLat: center.Lat - dLat, MaxLat: center.Lat + dLat}
+	
+	// Круг захватывает полюс - подходит любая долгота
+	if box.MaxLat >= 90 || box.MinLat <= -90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		box.MinLng, box.MaxLng = -180, 180
+		return box
+	}
+	
+	// Градус долготы короче к полюсам в cos(lat) раз. Точное полуокно
+	// по долготе - asin(sin(r)/cos(lat)), а не r/cos(lat).
+	dLng := deg(math.Asin(math.Sin(radiusKm/EarthRadiusKm) / math.Cos(rad(center.Lat))))
+	box.MinLng = normalizeLng(center.Lng - dLng)
+	box.MaxLng = normalizeLng(center.Lng + dLng)
+	return box
+}
+
+// normalizeLng приводит долготу к [-180, 180]
+func normalizeLng(lng float64) float64 {
+	for lng < -180 {
+		lng += 360
+	}
+	for lng > 180 {
+		lng -= 360
+	}
+	return lng
+}
\ No newline at end of file
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", Point{55.75, 37.62}, Point{55.75, 37.62}, 0},
+		{"quarter meridian", Point{0, 0}, Point{90, 0}, EarthRadiusKm * math.Pi / 2},
+		{"antipodes", Point{0, 0}, Point{0, 180}, EarthRadiusKm * math.Pi},
+		{"Moscow - Saint Petersburg", Point{55.7539, 37.6208}, Point{59.9343, 30.3351}, 633},
+		{"across antimeridian", Point{0, 179.5}, Point{0, -179.5}, EarthRadiusKm * math.Pi / 180},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+				t.Errorf("Expected %.1f km, got %.1f km", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGeohashKnownValue(t *testing.T) {
+	// Пример из описания geohash на geohash.org
+	if got := EncodeGeohash(Point{57.64911, 10.40744}, 11); got != "u4pruydqqvj" {
+		t.Errorf("Expected u4pruydqqvj, got %s", got)
+	}
+	if _, err := DecodeGeohash("u4pa"); err == nil {
+		t.Error("Expected error for invalid character")
+	}
+}
+
+func TestGeohashRoundTrip(t *testing.T) {
+	for range 1000 {
+		p := Point{rand.Float64()*180 - 90, rand.Float64()*360 - 180}
+		for _, precision := range []int{1, 5, 9} {
+			hash := EncodeGeohash(p, precision)
+			box, err := DecodeGeohash(hash)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !box.Contains(p) {
+				t.Fatalf("Cell %s %+v does not contain %v", hash, box, p)
+			}
+		}
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	n := GeohashNeighbors("ucfv0j")
+	if len(n) != 9 || n[0] != "ucfv0j" {
+		t.Errorf("Expected cell and 8 neighbors, got %v", n)
+	}
+	// У полюса соседей сверху нет
+	if n := GeohashNeighbors(EncodeGeohash(Point{89.99, 0}, 3)); len(n) != 6 {
+		t.Errorf("Expected 6 cells near the pole, got %v", n)
+	}
+}
+
+func TestBoundingBoxContainsCircle(t *testing.T) {
+	for range 2000 {
+		center := Point{rand.Float64()*170 - 85, rand.Float64()*360 - 180}
+		radius := rand.Float64()*2000 + 1
+		box := BoundingBox(center, radius)
+		
+		// Случайная точка в круге: азимут и расстояние по сфере
+		bearing := rand.Float64() * 2 * math.Pi
+		d := rand.Float64() * radius / EarthRadiusKm
+		lat1, lng1 := rad(center.Lat), rad(center.Lng)
+		lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(bearing))
+		lng2 := lng1 + math.Atan2(math.Sin(bearing)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+		p := Point{deg(lat2), normalizeLng(deg(lng2))}
+		
+		if !box.Contains(p) {
+			t.Fatalf("Box %+v for %v r=%.0f does not contain %v (%.1f km away)",
+				box, center, radius, p, Distance(center, p))
+		}
+	}
+}
+
+func TestNearbyMatchesBruteForce(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	
+	store := NewUserStore(db)
+	if err := store.Migrate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	// Точки сгущены у Москвы и у антимеридиана
+	var points []Point
+	for i := range 2000 {
+		base := Point{55.75, 37.62}
+		if i%2 == 1 {
+			base = Point{65, 179.9}
+		}
+		p := Point{base.Lat + rand.Float64()*4 - 2, normalizeLng(base.Lng + rand.Float64()*4 - 2)}
+		points = append(points, p)
+		if _, err := store.Create("u", p); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	
+	for _, c := range []struct {
+		center Point
+		radius float64
+	}{
+		{Point{55.75, 37.62}, 5},
+		{Point{55.75, 37.62}, 50},
+		{Point{65, 179.9}, 30},
+		{Point{65, -179.95}, 80},
+	} {
+		want := 0
+		for _, p := range points {
+			if Distance(c.center, p) <= c.radius {
+				want++
+			}
+		}
+		
+		byBox, err := store.Nearby(c.center, c.radius, len(points))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		byHash, err := store.NearbyGeohash(c.center, c.radius, len(points))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(byBox) != want || len(byHash) != want {
+			t.Errorf("%v r=%.0f: expected %d users, box found %d, geohash found %d",
+				c.center, c.radius, want, len(byBox), len(byHash))
+		}
+		for i := 1; i < len(byBox); i++ {
+			if byBox[i].Distance < byBox[i-1].Distance {
+				t.Fatalf("Results are not sorted by distance")
+			}
+		}
+	}
+}
\ No newline at end of file
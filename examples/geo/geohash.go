@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Geohash кодирует точку строкой base32: каждый символ - 5 бит,
+// биты по очереди делят пополам диапазон долготы и широты. Соседние
+// точки обычно имеют общий префикс, поэтому поиск по префиксу в
+// обычном B-tree индексе находит точки в одной ячейке.
+//
+// Длина -> размер ячейки на экваторе:
+//
+//	4 - 39 x 19.5 км, 5 - 4.9 x 4.9 км, 6 - 1.2 x 0.6 км, 7 - 153 x 153 м
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash возвращает geohash точки длиной precision символов
+func EncodeGeohash(p Point, precision int) string {
+	latLo, latHi := -90.0, 90.0
+	lngLo, lngHi := -180.0, 180.0
+	
+	var sb strings.Builder
+	var ch, bit int
+	even := true // Четные биты - долгота, нечетные - широта
+	for sb.Len() < precision {
+		if even {
+			mid := (lngLo + lngHi) / 2
+			if p.Lng >= mid {
+				ch |= 1 << (4 - bit)
+				lngLo = mid
+			} else {
+				lngHi = mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			if p.Lat >= mid {
+				ch |= 1 << (4 - bit)
+				latLo = mid
+			} else {
+				latHi = mid
+			}
+		}
+		even = !even
+		
+		if bit++; bit == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			ch, bit = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// DecodeGeohash возвращает ячейку, которую обозначает hash. Точка
+// восстанавливается только с точностью до ячейки - обычно берут ее центр.
+func DecodeGeohash(hash string) (Box, error) {
+	box := Box{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+	even := true
+	for i, c := range strings.ToLower(hash) {
+		v := strings.IndexRune(geohashAlphabet, c)
+		if v < 0 {
+			return Box{}, fmt.Errorf("invalid geohash character %q at %d", c, i)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := v&(1<<bit) != 0
+			if even {
+				mid := (box.MinLng + box.MaxLng) / 2
+				if set {
+					box.MinLng = mid
+				} else {
+					box.MaxLng = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if set {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box, nil
+}
+
+// GeohashNeighbors возвращает ячейку hash и восемь соседних. Точка у
+// границы ячейки может быть ближе к точкам соседней ячейки, чем к
+// дальнему краю своей, поэтому поиск "рядом" проверяет все девять.
+func GeohashNeighbors(hash string) []string {
+	box, err := DecodeGeohash(hash)
+	if err != nil {
+		return nil
+	}
+	c := box.Center()
+	h := box.MaxLat - box.MinLat
+	w := box.MaxLng - box.MinLng
+	
+	seen := make(map[string]bool)
+	var result []string
+	for _, dLat := range []float64{0, h, -h} {
+		for _, dLng := range []float64{0, w, -w} {
+			p := Point{c.Lat + dLat, normalizeLng(c.Lng + dLng)}
+			if p.Lat > 90 || p.Lat < -90 {
+				continue
+			}
+			n := EncodeGeohash(p, len(hash))
+			if !seen[n] {
+				seen[n] = true
+				result = append(result, n)
+			}
+		}
+	}
+	return result
+}
\ No newline at end of file
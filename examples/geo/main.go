@@ -0,0 +1,194 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск демонстрации: go run .
+// Сервер: go run . -serve :8080
+//   curl 'localhost:8080/api/users/nearby?lat=55.7558&lng=37.6173&radius_km=5'
+
+var places = []struct {
+	name string
+	p    Point
+}{
+	{"Москва, Красная площадь", Point{55.7539, 37.6208}},
+	{"Москва, Тверская", Point{55.7649, 37.6050}},
+	{"Москва, Арбат", Point{55.7494, 37.5912}},
+	{"Москва, ВДНХ", Point{55.8297, 37.6336}},
+	{"Химки", Point{55.8970, 37.4297}},
+	{"Санкт-Петербург", Point{59.9343, 30.3351}},
+	{"Новосибирск", Point{55.0084, 82.9357}},
+	{"Анадырь", Point{64.7337, 177.5089}},
+	{"Ном, Аляска", Point{64.5011, -165.4064}},
+}
+
+// Пример 1: Расстояния и geohash
+func basics() {
+	fmt.Println("=== Расстояния ===")
+	
+	moscow := places[0].p
+	for _, pl := range places[1:] {
+		fmt.Printf("%-24s %8.1f км\n", pl.name, Distance(moscow, pl.p))
+	}
+	fmt.Printf("Анадырь - Ном: %.0f км (через антимеридиан)\n", Distance(places[7].p, places[8].p))
+	
+	fmt.Println("\n=== Geohash ===")
+	for _, pl := range places[:5] {
+		fmt.Printf("%-24s %s\n", pl.name, EncodeGeohash(pl.p, 8))
+	}
+	
+	hash := EncodeGeohash(moscow, 6)
+	box, _ := DecodeGeohash(hash)
+	fmt.Printf("Ячейка %s: широта [%.4f, %.4f], долгота [%.4f, %.4f], центр %v в %.0f м от точки\n",
+		hash, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng, box.Center(), Distance(moscow, box.Center())*1000)
+	fmt.Printf("Соседи %s: %v\n", hash, GeohashNeighbors(hash))
+}
+
+// Пример 2: Прямоугольник для индекса
+func boundingBoxes() {
+	fmt.Println("\n=== Ограничивающий прямоугольник ===")
+	
+	for _, c := range []struct {
+		name   string
+		center Point
+		km     float64
+	}{
+		{"Москва, 10 км", places[0].p, 10},
+		{"экватор, 10 км", Point{0, 0}, 10},
+		{"Анадырь, 1000 км", places[7].p, 1000},
+		{"у полюса, 500 км", Point{88, 0}, 500},
+	} {
+		b := BoundingBox(c.center, c.km)
+		fmt.Printf("%-18s lat [%7.3f, %7.3f] lng [%8.3f, %8.3f]\n", c.name, b.MinLat, b.MaxLat, b.MinLng, b.MaxLng)
+	}
+	
+	// Доля прямоугольника, занятая кругом: остальное отсекает Distance
+	fmt.Printf("Круг занимает %.0f%% прямоугольника - угловые кандидаты отбрасываются после запроса\n", math.Pi/4*100)
+}
+
+// nearbyHandler GET /api/users/nearby?lat=&lng=&radius_km=&limit=
+func nearbyHandler(store *UserStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		lat, err1 := strconv.ParseFloat(q.Get("lat"), 64)
+		lng, err2 := strconv.ParseFloat(q.Get("lng"), 64)
+		center := Point{lat, lng}
+		if err1 != nil || err2 != nil || !center.Valid() {
+			http.Error(w, "Invalid lat/lng", http.StatusBadRequest)
+			return
+		}
+		
+		radius := 5.0
+		if s := q.Get("radius_km"); s != "" {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 || v > 1000 {
+				http.Error(w, "Invalid radius_km: expected (0, 1000]", http.StatusBadRequest)
+				return
+			}
+			radius = v
+		}
+		
+		limit := 20
+		if s := q.Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > 100 {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		
+		users, err := store.Nearby(center, radius, limit)
+		if err != nil {
+			log.Println("Ошибка поиска:", err)
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(users)
+	}
+}
+
+// newStore создает БД в памяти с тестовыми пользователями
+func newStore() (*UserStore, func()) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	// У каждого соединения :memory: своя БД - держим одно
+	db.SetMaxOpenConns(1)
+	
+	store := NewUserStore(db)
+	if err := store.Migrate(); err != nil {
+		log.Fatal("Ошибка миграции:", err)
+	}
+	for _, pl := range places {
+		if _, err := store.Create(pl.name, pl.p); err != nil {
+			log.Fatal("Ошибка создания пользователя:", err)
+		}
+	}
+	return store, func() { db.Close() }
+}
+
+// Пример 3: Эндпоинт "пользователи рядом"
+func nearbyAPI(store *UserStore) {
+	fmt.Println("\n=== /api/users/nearby ===")
+	
+	server := httptest.NewServer(nearbyHandler(store))
+	defer server.Close()
+	
+	for _, query := range []string{
+		"lat=55.7558&lng=37.6173&radius_km=3",
+		"lat=55.7558&lng=37.6173&radius_km=50&limit=3",
+		"lat=65&lng=179.9&radius_km=900",
+		"lat=95&lng=0",
+	} {
+		resp, err := http.Get(server.URL + "?" + query)
+		if err != nil {
+			log.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("%s -> %d %s", query, resp.StatusCode, body)
+	}
+	
+	// Тот же поиск через geohash дает тот же ответ
+	center := Point{55.7558, 37.6173}
+	byBox, _ := store.Nearby(center, 3, 10)
+	byHash, _ := store.NearbyGeohash(center, 3, 10)
+	fmt.Printf("Прямоугольник: %d, geohash (%s + соседи): %d\n",
+		len(byBox), EncodeGeohash(center, precisionFor(center, 3)), len(byHash))
+}
+
+func main() {
+	addr := flag.String("serve", "", "Адрес для запуска сервера")
+	flag.Parse()
+	
+	store, closeDB := newStore()
+	defer closeDB()
+	
+	if *addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("GET /api/users/nearby", nearbyHandler(store))
+		log.Printf("Сервер запущен на %s", *addr)
+		log.Fatal(http.ListenAndServe(*addr, mux))
+	}
+	
+	basics()
+	boundingBoxes()
+	nearbyAPI(store)
+}
\ No newline at end of file
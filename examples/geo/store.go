@@ -0,0 +1,169 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// geohashPrecision длина geohash в БД: ячейка ~1.2 x 0.6 км. Для поиска
+// в большем радиусе используется префикс - он описывает ячейку крупнее.
+const geohashPrecision = 6
+
+// User пользователь с координатами
+type User struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Location Point   `json:"location"`
+	Distance float64 `json:"distance_km,omitempty"`
+}
+
+// UserStore хранилище пользователей в SQLite
+type UserStore struct {
+	db *sql.DB
+}
+
+// NewUserStore создает хранилище
+func NewUserStore(db *sql.DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+// Migrate создает таблицу и индексы. Индекс (lat, lng) обслуживает
+// поиск по прямоугольнику: диапазон по lat идет по индексу, lng
+// проверяется по записям индекса без чтения таблицы. Индекс по
+// geohash обслуживает поиск по префиксу.
+func (s *UserStore) Migrate() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		lat REAL NOT NULL,
+		lng REAL NOT NULL,
+		geohash TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_users_lat_lng ON users(lat, lng);
+	CREATE INDEX IF NOT EXISTS idx_users_geohash ON users(geohash);`
+	
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// Create добавляет пользователя
+func (s *UserStore) Create(name string, p Point) (int64, error) {
+	if !p.Valid() {
+		return 0, fmt.Errorf("invalid coordinates %v", p)
+	}
+	result, err := s.db.Exec(`INSERT INTO users (name, lat, lng, geohash) VALUES (?, ?, ?, ?)`,
+		name, p.Lat, p.Lng, EncodeGeohash(p, geohashPrecision))
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// Nearby возвращает до limit пользователей в радиусе radiusKm от center,
+// ближайшие первыми. БД отбирает кандидатов по прямоугольнику через
+// индекс, точное расстояние и сортировка считаются в Go: в SQLite нет
+// тригонометрии без расширений.
+func (s *UserStore) Nearby(center Point, radiusKm float64, limit int) ([]User, error) {
+	box := BoundingBox(center, radiusKm)
+	
+	query := `SELECT id, name, lat, lng FROM users WHERE lat BETWEEN ? AND ? AND `
+	if box.MinLng <= box.MaxLng {
+		query += `lng BETWEEN ? AND ?`
+	} else {
+		// Прямоугольник пересекает антимеридиан
+		query += `(lng >= ? OR lng <= ?)`
+	}
+	rows, err := s.db.Query(query, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := scanUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+	return closest(candidates, center, radiusKm, limit), nil
+}
+
+// NearbyGeohash делает то же через geohash: берет ячейку, которая
+// покрывает радиус, и восемь соседних, и ищет по префиксам (LIKE 'u33d%'
+// использует индекс, если префикс постоянный).
+func (s *UserStore) NearbyGeohash(center Point, radiusKm float64, limit int) ([]User, error) {
+	prefix := EncodeGeohash(center, precisionFor(center, radiusKm))
+	cells := GeohashNeighbors(prefix)
+	
+	conds := make([]string, len(cells))
+	args := make([]any, len(cells))
+	for i, c := range cells {
+		conds[i] = `geohash LIKE ?`
+		args[i] = c + "%"
+	}
+	rows, err := s.db.Query(`SELECT id, name, lat, lng FROM users WHERE `+strings.Join(conds, " OR "), args...)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := scanUsers(rows)
+	if err != nil {
+		return nil, err
+	}
+	return closest(candidates, center, radiusKm, limit), nil
+}
+
+// precisionFor подбирает самую длинную точность geohash, у которой
+// ячейка по обеим сторонам не меньше радиуса: тогда круг целиком лежит
+// в 3x3 ячейках. Ширина ячейки в км сжимается к полюсам в cos(lat) раз.
+func precisionFor(center Point, radiusKm float64) int {
+	kmPerDeg := EarthRadiusKm * math.Pi / 180
+	for p := 12; p > 1; p-- {
+		// Из 5p бит долготе достается ceil(5p/2), широте - floor(5p/2)
+		lngBits, latBits := (5*p+1)/2, 5*p/2
+		heightKm := 180 / math.Exp2(float64(latBits)) * kmPerDeg
+		widthKm := 360 / math.Exp2(float64(lngBits)) * kmPerDeg * math.Cos(rad(center.Lat))
+		if heightKm >= radiusKm && widthKm >= radiusKm {
+			return p
+		}
+	}
+	return 1
+}
+
+func scanUsers(rows *sql.Rows) ([]User, error) {
+	defer rows.Close()
+	var users []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Location.Lat, &u.Location.Lng); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// closest считает расстояния, отбрасывает кандидатов вне круга и
+// возвращает limit ближайших
+func closest(candidates []User, center Point, radiusKm float64, limit int) []User {
+	result := []User{}
+	for _, u := range candidates {
+		d := Distance(center, u.Location)
+		if d <= radiusKm {
+			u.Distance = math.Round(d*1000) / 1000 // Точнее метра haversine все равно не дает
+			result = append(result, u)
+		}
+	}
+	slices.SortFunc(result, func(a, b User) int {
+		switch {
+		case a.Distance < b.Distance:
+			return -1
+		case a.Distance > b.Distance:
+			return 1
+		}
+		return int(a.ID - b.ID)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
\ No newline at end of file
@@ -0,0 +1,282 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Перезапуск без потери соединений через наследование сокета.
+//
+// Graceful shutdown (examples/http-server, examples/signals) дожидается
+// активных запросов, но между остановкой старого и запуском нового
+// процесса порт закрыт - новые клиенты получают connection refused.
+// Здесь новый процесс запускается, пока старый еще слушает порт:
+//
+//   1. Старый процесс получает SIGHUP.
+//   2. Запускает свой бинарник заново и передает ему слушающий сокет
+//      через ExtraFiles (дескриптор 3) и pipe готовности (дескриптор 4).
+//   3. Дочерний процесс создает listener из унаследованного дескриптора,
+//      начинает принимать соединения и пишет в pipe "ready".
+//   4. Старый процесс вызывает Shutdown: перестает принимать новые
+//      соединения и дожидается активных запросов. Все это время ядро
+//      отдает новые соединения из общей очереди accept дочернему процессу.
+//
+// Альтернатива - SO_REUSEPORT: оба процесса открывают порт независимо
+// (net.ListenConfig с Control, выставляющим опцию), ядро распределяет
+// соединения между ними. Минус: соединения, уже попавшие в очередь accept
+// закрывающегося сокета, теряются, поэтому наследование надежнее.
+//
+// Ручной запуск:
+//   go build -o server . && ./server -serve
+//   while true; do curl -s localhost:8080/slow; done   # в другом терминале
+//   kill -HUP <pid>                                     # PID меняется, ошибок нет
+//
+// Автоматическая проверка: go run . (режим по умолчанию)
+
+const (
+	envInherited = "GRACEFUL_RESTART"
+	addr         = "localhost:8080"
+)
+
+// listen возвращает унаследованный listener или открывает новый
+func listen() (net.Listener, error) {
+	if os.Getenv(envInherited) == "" {
+		return net.Listen("tcp", addr)
+	}
+	
+	// Дескрипторы ExtraFiles нумеруются с 3 (после stdin, stdout, stderr)
+	f := os.NewFile(3, "listener")
+	defer f.Close() // FileListener дублирует дескриптор
+	return net.FileListener(f)
+}
+
+// notifyReady сообщает родителю, что дочерний процесс принимает соединения
+func notifyReady() {
+	if os.Getenv(envInherited) == "" {
+		return
+	}
+	pipe := os.NewFile(4, "ready")
+	pipe.Write([]byte("ready"))
+	pipe.Close()
+}
+
+// spawnChild запускает новую копию процесса с унаследованным сокетом
+// и ждет, пока она будет готова принимать соединения
+func spawnChild(ln net.Listener) error {
+	lnFile, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		return fmt.Errorf("получение дескриптора: %w", err)
+	}
+	defer lnFile.Close()
+	
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyR.Close()
+	
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), envInherited+"=1")
+	cmd.ExtraFiles = []*os.File{lnFile, readyW}
+	
+	if err := cmd.Start(); err != nil {
+		readyW.Close()
+		return fmt.Errorf("запуск дочернего процесса: %w", err)
+	}
+	// Закрываем свою копию конца записи: иначе Read не получит EOF,
+	// если дочерний процесс упадет до готовности
+	readyW.Close()
+	
+	childPID := cmd.Process.Pid
+	// Дочерний процесс переживет родителя, ждать его через Wait не нужно
+	defer cmd.Process.Release()
+	
+	readyR.SetReadDeadline(time.Now().Add(10 * time.Second))
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(readyR, buf); err != nil {
+		return fmt.Errorf("дочерний процесс %d не сообщил о готовности: %w", childPID, err)
+	}
+	log.Printf("[%d] дочерний процесс %d готов", os.Getpid(), childPID)
+	return nil
+}
+
+// serve запускает сервер и перезапускает его по SIGHUP
+func serve() {
+	ln, err := listen()
+	if err != nil {
+		log.Fatal("Ошибка открытия порта:", err)
+	}
+	
+	pid := strconv.Itoa(os.Getpid())
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, pid)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		// Запрос, который застанет перезапуск в процессе обработки
+		time.Sleep(300 * time.Millisecond)
+		fmt.Fprintln(w, pid)
+	})
+	server := &http.Server{Handler: mux}
+	
+	go func() {
+		if err := server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Ошибка сервера:", err)
+		}
+	}()
+	notifyReady()
+	log.Printf("[%s] принимает соединения на %s", pid, ln.Addr())
+	
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	for sig := range sigs {
+		if sig == syscall.SIGHUP {
+			if err := spawnChild(ln); err != nil {
+				// Новая версия не запустилась - продолжаем работать на старой
+				log.Printf("[%s] перезапуск отменен: %v", pid, err)
+				continue
+			}
+		}
+		break
+	}
+	
+	// Новые соединения уже принимает дочерний процесс; дожидаемся своих
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("[%s] ошибка остановки: %v", pid, err)
+	}
+	log.Printf("[%s] завершен", pid)
+}
+
+// Проверка: клиенты шлют запросы непрерывно, сервер дважды перезапускается
+func selfTest() {
+	fmt.Println("=== Перезапуск под нагрузкой ===")
+	
+	exe, err := os.Executable()
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := exec.Command(exe, "-serve")
+	server.Stdout = os.Stdout
+	server.Stderr = os.Stderr
+	if err := server.Start(); err != nil {
+		log.Fatal("Ошибка запуска сервера:", err)
+	}
+	go server.Wait()
+	
+	client := &http.Client{Timeout: 5 * time.Second}
+	get := func(path string) (string, error) {
+		resp, err := client.Get("http://" + addr + path)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return strings.TrimSpace(string(body)), err
+	}
+	
+	// Ждем запуска первого сервера
+	var current string
+	for i := 0; i < 50; i++ {
+		if current, err = get("/"); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		log.Fatal("Сервер не запустился:", err)
+	}
+	
+	var (
+		ok, failed atomic.Int64
+		mu         sync.Mutex
+		pids       = map[string]int{}
+	)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				pid, err := get("/slow")
+				if err != nil {
+					failed.Add(1)
+					log.Println("Ошибка запроса:", err)
+					continue
+				}
+				ok.Add(1)
+				mu.Lock()
+				pids[pid]++
+				mu.Unlock()
+			}
+		}()
+	}
+	
+	for i := 0; i < 2; i++ {
+		time.Sleep(time.Second)
+		p, _ := strconv.Atoi(current)
+		fmt.Printf("Отправляем SIGHUP процессу %d\n", p)
+		syscall.Kill(p, syscall.SIGHUP)
+		
+		// Ждем, пока ответы начнет давать новый процесс
+		for {
+			time.Sleep(100 * time.Millisecond)
+			if pid, err := get("/"); err == nil && pid != current {
+				current = pid
+				break
+			}
+		}
+	}
+	time.Sleep(time.Second)
+	close(stop)
+	wg.Wait()
+	
+	p, _ := strconv.Atoi(current)
+	syscall.Kill(p, syscall.SIGTERM)
+	time.Sleep(500 * time.Millisecond)
+	
+	fmt.Printf("\nУспешных запросов: %d, ошибок: %d\n", ok.Load(), failed.Load())
+	fmt.Printf("Ответы по процессам: %v\n", pids)
+}
+
+func main() {
+	serveMode := flag.Bool("serve", false, "запустить сервер")
+	flag.Parse()
+	
+	if *serveMode {
+		serve()
+		return
+	}
+	selfTest()
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+//go:build windows
+
+package main
+
+import "fmt"
+
+// Наследование слушающего сокета через ExtraFiles и сигнал SIGHUP
+// в Windows недоступны. Там похожего результата добиваются через
+// WSADuplicateSocket или перезапуск за балансировщиком нагрузки.
+func main() {
+	fmt.Println("Пример поддерживается только в Unix-системах")
+}
\ No newline at end of file
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrCycle граф содержит цикл, топологический порядок не существует
+var ErrCycle = errors.New("graph has a cycle")
+
+// BFS обходит вершины в порядке удаления от start (по числу ребер).
+// visit может вернуть false, чтобы остановить обход.
+func BFS[T comparable](g *Graph[T], start T, visit func(v T, depth int) bool) {
+	if !g.HasVertex(start) {
+		return
+	}
+	depth := map[T]int{start: 0}
+	queue := []T{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if !visit(v, depth[v]) {
+			return
+		}
+		for _, e := range g.Neighbors(v) {
+			if _, seen := depth[e.To]; !seen {
+				depth[e.To] = depth[v] + 1
+				queue = append(queue, e.To)
+			}
+		}
+	}
+}
+
+// DFS обходит вершины в глубину. Явный стек вместо рекурсии:
+// на длинных цепочках рекурсия раздувает стек горутины.
+func DFS[T comparable](g *Graph[T], start T, visit func(v T) bool) {
+	if !g.HasVertex(start) {
+		return
+	}
+	visited := make(map[T]bool)
+	stack := []T{start}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+		if !visit(v) {
+			return
+		}
+		// В обратном порядке, чтобы первый сосед обрабатывался первым
+		edges := g.Neighbors(v)
+		for i := len(edges) - 1; i >= 0; i-- {
+			if !visited[edges[i].To] {
+				stack = append(stack, edges[i].To)
+			}
+		}
+	}
+}
+
+// ShortestPath находит путь с наименьшим числом ребер (BFS)
+func ShortestPath[T comparable](g *Graph[T], from, to T) ([]T, bool) {
+	parent := make(map[T]T)
+	found := false
+	BFS(g, from, func(v T, _ int) bool {
+		if v == to {
+			found = true
+			return false
+		}
+		for _, e := range g.Neighbors(v) {
+			if _, ok := parent[e.To]; !ok && e.To != from {
+				parent[e.To] = v
+			}
+		}
+		return true
+	})
+	if !found {
+		return nil, false
+	}
+	return buildPath(parent, from, to), true
+}
+
+func buildPath[T comparable](parent map[T]T, from, to T) []T {
+	path := []T{to}
+	for v := to; v != from; {
+		v = parent[v]
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Цвета вершин для поиска цикла в ориентированном графе
+const (
+	white = iota // Не посещена
+	gray         // В текущем пути обхода
+	black        // Обработана полностью
+)
+
+// FindCycle возвращает цикл ориентированного графа, если он есть.
+// Ребро в "серую" вершину - обратное ребро, оно и замыкает цикл.
+func FindCycle[T comparable](g *Graph[T]) ([]T, bool) {
+	color := make(map[T]int)
+	parent := make(map[T]T)
+	var cycle []T
+	
+	var visit func(v T) bool
+	visit = func(v T) bool {
+		color[v] = gray
+		for _, e := range g.Neighbors(v) {
+			switch color[e.To] {
+			case gray:
+				cycle = []T{e.To}
+				for u := v; u != e.To; u = parent[u] {
+					cycle = append(cycle, u)
+				}
+				cycle = append(cycle, e.To)
+				slices.Reverse(cycle)
+				return true
+			case white:
+				parent[e.To] = v
+				if visit(e.To) {
+					return true
+				}
+			}
+		}
+		color[v] = black
+		return false
+	}
+	
+	for _, v := range g.Vertices() {
+		if color[v] == white && visit(v) {
+			return cycle, true
+		}
+	}
+	return nil, false
+}
+
+// TopoSort упорядочивает вершины так, что каждое ребро ведет вперед
+// (алгоритм Кана: снимаем вершины без входящих ребер). Среди готовых
+// вершин сохраняется порядок добавления.
+func TopoSort[T comparable](g *Graph[T]) ([]T, error) {
+	inDegree := make(map[T]int)
+	for _, v := range g.Vertices() {
+		for _, e := range g.Neighbors(v) {
+			inDegree[e.To]++
+		}
+	}
+	
+	var ready []T
+	for _, v := range g.Vertices() {
+		if inDegree[v] == 0 {
+			ready = append(ready, v)
+		}
+	}
+	
+	result := make([]T, 0, len(g.Vertices()))
+	for len(ready) > 0 {
+		v := ready[0]
+		ready = ready[1:]
+		result = append(result, v)
+		for _, e := range g.Neighbors(v) {
+			inDegree[e.To]--
+			if inDegree[e.To] == 0 {
+				ready = append(ready, e.To)
+			}
+		}
+	}
+	
+	if len(result) < len(g.Vertices()) {
+		cycle, _ := FindCycle(g)
+		return nil, fmt.Errorf("%w: %v", ErrCycle, cycle)
+	}
+	return result, nil
+}
+
+// Dijkstra находит кратчайшие расстояния от source до всех достижимых
+// вершин и карту предков для восстановления путей. Веса должны быть
+// неотрицательными. Вместо decrease-key в очередь кладутся дубликаты,
+// устаревшие записи пропускаются при извлечении: O((V+E) log E).
+func Dijkstra[T comparable](g *Graph[T], source T) (dist map[T]float64, parent map[T]T) {
+	type item struct {
+		v    T
+		dist float64
+	}
+	
+	dist = map[T]float64{source: 0}
+	parent = make(map[T]T)
+	pq := NewPriorityQueue(func(a, b item) bool { return a.dist < b.dist })
+	pq.Push(item{source, 0})
+	
+	for pq.Len() > 0 {
+		cur, _ := pq.Pop()
+		if cur.dist > dist[cur.v] {
+			continue // Устаревшая запись
+		}
+		for _, e := range g.Neighbors(cur.v) {
+			if e.Weight < 0 {
+				panic("dijkstra: negative edge weight")
+			}
+			nd := cur.dist + e.Weight
+			if old, ok := dist[e.To]; !ok || nd < old {
+				dist[e.To] = nd
+				parent[e.To] = cur.v
+				pq.Push(item{e.To, nd})
+			}
+		}
+	}
+	return dist, parent
+}
+
+// WeightedPath возвращает кратчайший взвешенный путь и его длину
+func WeightedPath[T comparable](g *Graph[T], from, to T) ([]T, float64) {
+	dist, parent := Dijkstra(g, from)
+	d, ok := dist[to]
+	if !ok {
+		return nil, math.Inf(1)
+	}
+	return buildPath(parent, from, to), d
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package main
+
+// Edge ребро списка смежности
+type Edge[T comparable] struct {
+	To     T
+	Weight float64
+}
+
+// Graph граф на списках смежности. Вершины хранятся в порядке добавления,
+// чтобы обходы и топологическая сортировка были детерминированными -
+// по map порядок менялся бы от запуска к запуску.
+type Graph[T comparable] struct {
+	directed bool
+	adj      map[T][]Edge[T]
+	order    []T
+}
+
+// NewGraph создает ориентированный или неориентированный граф
+func NewGraph[T comparable](directed bool) *Graph[T] {
+	return &Graph[T]{directed: directed, adj: make(map[T][]Edge[T])}
+}
+
+// AddVertex добавляет вершину, если ее еще нет
+func (g *Graph[T]) AddVertex(v T) {
+	if _, ok := g.adj[v]; !ok {
+		g.adj[v] = nil
+		g.order = append(g.order, v)
+	}
+}
+
+// AddEdge добавляет ребро веса 1
+func (g *Graph[T]) AddEdge(from, to T) {
+	g.AddWeightedEdge(from, to, 1)
+}
+
+// AddWeightedEdge добавляет ребро; в неориентированном графе - в обе стороны
+func (g *Graph[T]) AddWeightedEdge(from, to T, w float64) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+	g.adj[from] = append(g.adj[from], Edge[T]{To: to, Weight: w})
+	if !g.directed && from != to {
+		g.adj[to] = append(g.adj[to], Edge[T]{To: from, Weight: w})
+	}
+}
+
+// Vertices возвращает вершины в порядке добавления
+func (g *Graph[T]) Vertices() []T {
+	return g.order
+}
+
+// Neighbors возвращает исходящие ребра вершины
+func (g *Graph[T]) Neighbors(v T) []Edge[T] {
+	return g.adj[v]
+}
+
+// HasVertex проверяет наличие вершины
+func (g *Graph[T]) HasVertex(v T) bool {
+	_, ok := g.adj[v]
+	return ok
+}
\ No newline at end of file
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBFSDepth(t *testing.T) {
+	g := NewGraph[int](false)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(4, 5)
+	
+	depths := make(map[int]int)
+	BFS(g, 1, func(v, depth int) bool {
+		depths[v] = depth
+		return true
+	})
+	want := map[int]int{1: 0, 2: 1, 3: 1, 4: 2, 5: 3}
+	for v, d := range want {
+		if depths[v] != d {
+			t.Errorf("Vertex %d: expected depth %d, got %d", v, d, depths[v])
+		}
+	}
+}
+
+func TestDFSOrder(t *testing.T) {
+	g := NewGraph[string](true)
+	g.AddEdge("a", "b")
+	g.AddEdge("a", "c")
+	g.AddEdge("b", "d")
+	g.AddEdge("c", "d")
+	
+	var got []string
+	DFS(g, "a", func(v string) bool {
+		got = append(got, v)
+		return true
+	})
+	if want := []string{"a", "b", "d", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := NewGraph[int](false)
+	for i := 0; i < 5; i++ {
+		g.AddEdge(i, i+1) // Длинная цепочка 0..5
+	}
+	g.AddEdge(0, 4) // Короткий путь
+	g.AddVertex(9)
+	
+	path, ok := ShortestPath(g, 0, 5)
+	if !ok || !slices.Equal(path, []int{0, 4, 5}) {
+		t.Errorf("Expected [0 4 5], got %v (ok=%v)", path, ok)
+	}
+	if _, ok := ShortestPath(g, 0, 9); ok {
+		t.Error("Expected no path to isolated vertex")
+	}
+}
+
+func TestTopoSortCurriculum(t *testing.T) {
+	order, err := TopoSort(curriculumGraph())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(order) != len(curriculum) {
+		t.Fatalf("Expected %d topics, got %d", len(curriculum), len(order))
+	}
+	
+	pos := make(map[string]int)
+	for i, topic := range order {
+		pos[topic] = i
+	}
+	for topic, pres := range curriculum {
+		for _, pre := range pres {
+			if pos[pre] >= pos[topic] {
+				t.Errorf("%q must come before %q", pre, topic)
+			}
+		}
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	g := NewGraph[string](true)
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "a")
+	g.AddEdge("x", "a")
+	
+	_, err := TopoSort(g)
+	if !errors.Is(err, ErrCycle) {
+		t.Fatalf("Expected ErrCycle, got %v", err)
+	}
+	
+	cycle, ok := FindCycle(g)
+	if !ok || len(cycle) != 4 || cycle[0] != cycle[len(cycle)-1] {
+		t.Errorf("Expected closed cycle of 3 vertices, got %v", cycle)
+	}
+}
+
+// bellmanFord - медленный, но простой эталон для проверки Дейкстры
+func bellmanFord(g *Graph[int], source int) map[int]float64 {
+	dist := map[int]float64{source: 0}
+	for range g.Vertices() {
+		for _, v := range g.Vertices() {
+			d, ok := dist[v]
+			if !ok {
+				continue
+			}
+			for _, e := range g.Neighbors(v) {
+				if old, ok := dist[e.To]; !ok || d+e.Weight < old {
+					dist[e.To] = d + e.Weight
+				}
+			}
+		}
+	}
+	return dist
+}
+
+func TestDijkstraMatchesBellmanFord(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		g := NewGraph[int](true)
+		for i := 0; i < 30; i++ {
+			g.AddVertex(i)
+		}
+		for i := 0; i < 100; i++ {
+			g.AddWeightedEdge(rng.Intn(30), rng.Intn(30), float64(rng.Intn(50)))
+		}
+		
+		got, _ := Dijkstra(g, 0)
+		want := bellmanFord(g, 0)
+		if len(got) != len(want) {
+			t.Fatalf("Trial %d: expected %d reachable, got %d", trial, len(want), len(got))
+		}
+		for v, d := range want {
+			if math.Abs(got[v]-d) > 1e-9 {
+				t.Errorf("Trial %d, vertex %d: expected %v, got %v", trial, v, d, got[v])
+			}
+		}
+	}
+}
+
+func TestWeightedPathUnreachable(t *testing.T) {
+	g := NewGraph[string](true)
+	g.AddWeightedEdge("a", "b", 1)
+	g.AddVertex("c")
+	
+	if path, d := WeightedPath(g, "a", "c"); path != nil || !math.IsInf(d, 1) {
+		t.Errorf("Expected no path, got %v (%v)", path, d)
+	}
+}
\ No newline at end of file
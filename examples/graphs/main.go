@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Темы плана подготовки (day1-2.md ... day7.md) и их пререквизиты:
+// тема -> что нужно знать до нее
+var curriculum = map[string][]string{
+	"Горутины и каналы":  nil,
+	"Мьютексы":           {"Горутины и каналы"},
+	"Context":            {"Горутины и каналы"},
+	"Интерфейсы":         nil,
+	"Работа с памятью":   nil,
+	"net/http":           {"Интерфейсы", "Context"},
+	"encoding/json":      {"Интерфейсы"},
+	"database/sql":       {"Context"},
+	"testing":            {"Интерфейсы"},
+	"Чистая архитектура": {"net/http", "database/sql"},
+	"Моки в тестах":      {"testing", "Чистая архитектура"},
+	"pprof":              {"Работа с памятью", "net/http"},
+	"Детектор гонок":     {"Мьютексы", "testing"},
+	"Системный дизайн":   {"Чистая архитектура", "pprof"},
+}
+
+// Порядок, в котором темы идут в плане
+var topicOrder = []string{
+	"Горутины и каналы", "Мьютексы", "Context", "Интерфейсы", "Работа с памятью",
+	"net/http", "encoding/json", "database/sql", "testing",
+	"Чистая архитектура", "Моки в тестах", "pprof", "Детектор гонок", "Системный дизайн",
+}
+
+// curriculumGraph строит граф "пререквизит -> тема"
+func curriculumGraph() *Graph[string] {
+	g := NewGraph[string](true)
+	for _, topic := range topicOrder {
+		g.AddVertex(topic)
+	}
+	for _, topic := range topicOrder {
+		for _, pre := range curriculum[topic] {
+			g.AddEdge(pre, topic)
+		}
+	}
+	return g
+}
+
+// Пример 1: Обходы
+func traversals() {
+	fmt.Println("=== BFS и DFS ===")
+	
+	// Неориентированный граф знакомств
+	g := NewGraph[string](false)
+	for _, pair := range [][2]string{
+		{"Аня", "Борис"}, {"Аня", "Вера"}, {"Борис", "Глеб"},
+		{"Вера", "Глеб"}, {"Глеб", "Дина"}, {"Дина", "Егор"}, {"Жанна", "Зоя"},
+	} {
+		g.AddEdge(pair[0], pair[1])
+	}
+	
+	fmt.Print("BFS от Ани:")
+	BFS(g, "Аня", func(v string, depth int) bool {
+		fmt.Printf(" %s(%d)", v, depth)
+		return true
+	})
+	fmt.Print("\nDFS от Ани:")
+	DFS(g, "Аня", func(v string) bool {
+		fmt.Print(" ", v)
+		return true
+	})
+	fmt.Println()
+	
+	path, _ := ShortestPath(g, "Аня", "Егор")
+	fmt.Println("Цепочка знакомств:", strings.Join(path, " -> "))
+	_, ok := ShortestPath(g, "Аня", "Зоя")
+	fmt.Println("Аня знакома с Зоей через кого-то:", ok)
+}
+
+// Пример 2: Порядок изучения тем
+func studyOrder() {
+	fmt.Println("\n=== Топологическая сортировка плана ===")
+	
+	g := curriculumGraph()
+	order, err := TopoSort(g)
+	if err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	for i, topic := range order {
+		fmt.Printf("%2d. %s\n", i+1, topic)
+	}
+	
+	// Ошибка в плане: цикл в пререквизитах
+	g.AddEdge("Системный дизайн", "net/http")
+	_, err = TopoSort(g)
+	fmt.Println("\nС циклом:", err)
+	fmt.Println("errors.Is(err, ErrCycle):", errors.Is(err, ErrCycle))
+}
+
+// Пример 3: Кратчайшие пути
+func shortestPaths() {
+	fmt.Println("\n=== Дейкстра ===")
+	
+	// Дороги между городами, км
+	g := NewGraph[string](false)
+	roads := []struct {
+		from, to string
+		km       float64
+	}{
+		{"Москва", "Тверь", 180}, {"Тверь", "Санкт-Петербург", 530},
+		{"Москва", "Ярославль", 270}, {"Ярославль", "Вологда", 200},
+		{"Вологда", "Санкт-Петербург", 660}, {"Москва", "Великий Новгород", 530},
+		{"Великий Новгород", "Санкт-Петербург", 190}, {"Тверь", "Великий Новгород", 360},
+	}
+	for _, r := range roads {
+		g.AddWeightedEdge(r.from, r.to, r.km)
+	}
+	
+	dist, _ := Dijkstra(g, "Москва")
+	for _, city := range g.Vertices() {
+		fmt.Printf("%-18s %5.0f км\n", city, dist[city])
+	}
+	
+	path, km := WeightedPath(g, "Москва", "Санкт-Петербург")
+	fmt.Printf("Маршрут: %s (%.0f км)\n", strings.Join(path, " -> "), km)
+}
+
+func main() {
+	traversals()
+	studyOrder()
+	shortestPaths()
+}
\ No newline at end of file
@@ -0,0 +1,84 @@
+package main
+
+// Копия examples/heap/pq.go: каждый пример - самостоятельный package main
+
+// PriorityQueue обобщенная двоичная куча без heap.Interface:
+// порядок задается функцией less, нет приведений из any и
+// промежуточных интерфейсных вызовов. less(a, b) == true означает,
+// что a извлекается раньше b.
+type PriorityQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// NewPriorityQueue создает очередь с заданным порядком
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// Push добавляет элемент за O(log n)
+func (pq *PriorityQueue[T]) Push(v T) {
+	pq.items = append(pq.items, v)
+	pq.up(len(pq.items) - 1)
+}
+
+// Pop извлекает первый по порядку элемент за O(log n)
+func (pq *PriorityQueue[T]) Pop() (v T, ok bool) {
+	n := len(pq.items)
+	if n == 0 {
+		return v, false
+	}
+	v = pq.items[0]
+	pq.items[0] = pq.items[n-1]
+	var zero T
+	pq.items[n-1] = zero
+	pq.items = pq.items[:n-1]
+	if n > 1 {
+		pq.down(0)
+	}
+	return v, true
+}
+
+// Peek возвращает первый элемент без извлечения
+func (pq *PriorityQueue[T]) Peek() (v T, ok bool) {
+	if len(pq.items) == 0 {
+		return v, false
+	}
+	return pq.items[0], true
+}
+
+// Len возвращает число элементов
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Дети узла i - 2i+1 и 2i+2, родитель - (i-1)/2
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.items[i], pq.items[parent]) {
+			return
+		}
+		pq.items[i], pq.items[parent] = pq.items[parent], pq.items[i]
+		i = parent
+	}
+}
+
+func (pq *PriorityQueue[T]) down(i int) {
+	n := len(pq.items)
+	for {
+		smallest := i
+		if l := 2*i + 1; l < n && pq.less(pq.items[l], pq.items[smallest]) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < n && pq.less(pq.items[r], pq.items[smallest]) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		pq.items[i], pq.items[smallest] = pq.items[smallest], pq.items[i]
+		i = smallest
+	}
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"container/heap"
+	"math/rand"
+	"testing"
+)
+
+// go test -bench=. -benchmem
+// container/heap платит за вызовы через интерфейс и упаковку int в any
+// при каждом Push/Pop (отсюда аллокации); обобщенная версия - нет.
+
+func benchValues() []int {
+	return rand.New(rand.NewSource(1)).Perm(10000)
+}
+
+func BenchmarkContainerHeap(b *testing.B) {
+	values := benchValues()
+	b.ReportAllocs()
+	for b.Loop() {
+		h := &IntHeap{}
+		for _, v := range values {
+			heap.Push(h, v)
+		}
+		for h.Len() > 0 {
+			heap.Pop(h)
+		}
+	}
+}
+
+func BenchmarkGenericPriorityQueue(b *testing.B) {
+	values := benchValues()
+	b.ReportAllocs()
+	for b.Loop() {
+		pq := NewPriorityQueue(func(a, b int) bool { return a < b })
+		for _, v := range values {
+			pq.Push(v)
+		}
+		for pq.Len() > 0 {
+			pq.Pop()
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"time"
+)
+
+// IntHeap - минимальная реализация heap.Interface из документации
+type IntHeap []int
+
+func (h IntHeap) Len() int           { return len(h) }
+func (h IntHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h IntHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *IntHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *IntHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Пример 1: container/heap
+func containerHeap() {
+	fmt.Println("=== container/heap ===")
+	
+	h := &IntHeap{5, 2, 8}
+	heap.Init(h) // O(n) - быстрее, чем n вызовов Push
+	heap.Push(h, 3)
+	
+	// Частая ошибка: h.Pop() вместо heap.Pop(h) снимет последний
+	// элемент слайса, а не минимум
+	fmt.Print("По возрастанию:")
+	for h.Len() > 0 {
+		fmt.Print(" ", heap.Pop(h))
+	}
+	fmt.Println()
+}
+
+// Пример 2: Планировщик задач с изменением приоритета
+func scheduler() {
+	fmt.Println("\n=== Планировщик задач ===")
+	
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var s Scheduler
+	
+	report := &Task{Name: "отчет", RunAt: start.Add(30 * time.Minute)}
+	backup := &Task{Name: "бэкап", RunAt: start.Add(10 * time.Minute)}
+	email := &Task{Name: "рассылка", RunAt: start.Add(10 * time.Minute), Priority: 5}
+	cleanup := &Task{Name: "очистка", RunAt: start.Add(20 * time.Minute)}
+	for _, t := range []*Task{report, backup, email, cleanup} {
+		s.Schedule(t)
+	}
+	
+	s.Reschedule(report, start.Add(5*time.Minute)) // Срочно нужен
+	s.Cancel(cleanup)
+	
+	for _, offset := range []time.Duration{5, 10, 60} {
+		now := start.Add(offset * time.Minute)
+		for _, t := range s.Due(now) {
+			fmt.Printf("%s: %s (приоритет %d)\n", now.Format("15:04"), t.Name, t.Priority)
+		}
+	}
+}
+
+// Пример 3: Обобщенная очередь - top-K
+func topK[T any](items []T, k int, less func(a, b T) bool) []T {
+	// Min-куча размера k: в корне худший из лучших, его и вытесняем.
+	// O(n log k) вместо O(n log n) для полной сортировки.
+	pq := NewPriorityQueue(less)
+	for _, v := range items {
+		pq.Push(v)
+		if pq.Len() > k {
+			pq.Pop()
+		}
+	}
+	
+	result := make([]T, pq.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i], _ = pq.Pop()
+	}
+	return result
+}
+
+// Пример 4: Слияние k отсортированных слайсов за O(n log k)
+func mergeSorted[T cmp.Ordered](lists ...[]T) []T {
+	type cursor struct {
+		list []T
+		pos  int
+	}
+	pq := NewPriorityQueue(func(a, b cursor) bool {
+		return a.list[a.pos] < b.list[b.pos]
+	})
+	for _, l := range lists {
+		if len(l) > 0 {
+			pq.Push(cursor{list: l})
+		}
+	}
+	
+	var result []T
+	for pq.Len() > 0 {
+		c, _ := pq.Pop()
+		result = append(result, c.list[c.pos])
+		if c.pos+1 < len(c.list) {
+			pq.Push(cursor{list: c.list, pos: c.pos + 1})
+		}
+	}
+	return result
+}
+
+func genericQueue() {
+	fmt.Println("\n=== PriorityQueue[T] ===")
+	
+	type endpoint struct {
+		path    string
+		latency time.Duration
+	}
+	endpoints := []endpoint{
+		{"/api/users", 12 * time.Millisecond},
+		{"/api/orders", 340 * time.Millisecond},
+		{"/api/search", 870 * time.Millisecond},
+		{"/api/health", time.Millisecond},
+		{"/api/reports", 1200 * time.Millisecond},
+		{"/api/login", 95 * time.Millisecond},
+	}
+	slowest := topK(endpoints, 3, func(a, b endpoint) bool { return a.latency < b.latency })
+	fmt.Println("Три самых медленных:")
+	for _, e := range slowest {
+		fmt.Printf("  %-14s %v\n", e.path, e.latency)
+	}
+	
+	fmt.Println("Слияние:", mergeSorted([]int{1, 4, 9}, []int{2, 3, 10}, []int{0, 5}))
+}
+
+func main() {
+	containerHeap()
+	scheduler()
+	genericQueue()
+}
\ No newline at end of file
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"container/heap"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueueOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	pq := NewPriorityQueue(func(a, b int) bool { return a < b })
+	values := rng.Perm(1000)
+	for _, v := range values {
+		pq.Push(v)
+	}
+	
+	for want := 0; want < 1000; want++ {
+		got, ok := pq.Pop()
+		if !ok || got != want {
+			t.Fatalf("Expected %d, got %d (ok=%v)", want, got, ok)
+		}
+	}
+	if _, ok := pq.Pop(); ok {
+		t.Error("Expected Pop on empty queue to fail")
+	}
+}
+
+func TestPriorityQueueMatchesContainerHeap(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	pq := NewPriorityQueue(func(a, b int) bool { return a > b }) // max-куча
+	h := &IntHeap{}
+	
+	for i := 0; i < 5000; i++ {
+		if rng.Intn(3) > 0 {
+			v := rng.Intn(100)
+			pq.Push(v)
+			heap.Push(h, -v)
+			continue
+		}
+		if h.Len() == 0 {
+			continue
+		}
+		got, _ := pq.Pop()
+		want := -heap.Pop(h).(int)
+		if got != want {
+			t.Fatalf("Step %d: expected %d, got %d", i, want, got)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	got := topK([]int{5, 1, 9, 3, 7, 2}, 3, func(a, b int) bool { return a < b })
+	if !slices.Equal(got, []int{9, 7, 5}) {
+		t.Errorf("Expected [9 7 5], got %v", got)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	got := mergeSorted([]int{1, 4, 9}, nil, []int{2, 3, 10}, []int{0, 5})
+	want := []int{0, 1, 2, 3, 4, 5, 9, 10}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	base := time.Unix(0, 0)
+	var s Scheduler
+	a := &Task{Name: "a", RunAt: base.Add(3 * time.Second)}
+	b := &Task{Name: "b", RunAt: base.Add(1 * time.Second)}
+	c := &Task{Name: "c", RunAt: base.Add(2 * time.Second)}
+	for _, task := range []*Task{a, b, c} {
+		s.Schedule(task)
+	}
+	
+	s.Reschedule(a, base)
+	s.Cancel(c)
+	
+	var names []string
+	for _, task := range s.Due(base.Add(10 * time.Second)) {
+		names = append(names, task.Name)
+	}
+	if !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", names)
+	}
+	
+	// Отмена уже выполненной задачи не должна ничего ломать
+	s.Cancel(a)
+	if s.Len() != 0 {
+		t.Errorf("Expected empty scheduler, got %d", s.Len())
+	}
+}
\ No newline at end of file
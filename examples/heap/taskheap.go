@@ -0,0 +1,96 @@
+package main
+
+import (
+	"container/heap"
+	"time"
+)
+
+// container/heap не хранит данные сам: он работает с любым типом,
+// реализующим heap.Interface (sort.Interface + Push + Pop). Это и есть
+// "танец с интерфейсом": пять методов, из которых Push и Pop принимают
+// и возвращают any и вызываются только пакетом heap, а не напрямую.
+
+// Task задача планировщика
+type Task struct {
+	Name     string
+	Priority int
+	RunAt    time.Time
+
+	index int // Позиция в куче; нужна для heap.Fix и heap.Remove
+}
+
+// TaskHeap min-куча задач по времени запуска, при равенстве - по приоритету
+type TaskHeap []*Task
+
+func (h TaskHeap) Len() int { return len(h) }
+
+func (h TaskHeap) Less(i, j int) bool {
+	if !h[i].RunAt.Equal(h[j].RunAt) {
+		return h[i].RunAt.Before(h[j].RunAt)
+	}
+	return h[i].Priority > h[j].Priority
+}
+
+func (h TaskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push вызывается из heap.Push: добавить в конец, heap сам поднимет элемент
+func (h *TaskHeap) Push(x any) {
+	t := x.(*Task)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+// Pop вызывается из heap.Pop, который уже переставил минимум в конец
+func (h *TaskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil // Не удерживаем задачу от сборки мусора
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
+
+// Scheduler выдает задачи в порядке времени запуска
+type Scheduler struct {
+	tasks TaskHeap
+}
+
+// Schedule добавляет задачу
+func (s *Scheduler) Schedule(t *Task) {
+	heap.Push(&s.tasks, t)
+}
+
+// Reschedule меняет время запуска задачи, уже находящейся в куче, за O(log n)
+func (s *Scheduler) Reschedule(t *Task, runAt time.Time) {
+	if t.index < 0 {
+		return
+	}
+	t.RunAt = runAt
+	heap.Fix(&s.tasks, t.index)
+}
+
+// Cancel удаляет задачу из кучи
+func (s *Scheduler) Cancel(t *Task) {
+	if t.index >= 0 {
+		heap.Remove(&s.tasks, t.index)
+	}
+}
+
+// Due извлекает все задачи, время которых наступило к now
+func (s *Scheduler) Due(now time.Time) []*Task {
+	var due []*Task
+	for s.tasks.Len() > 0 && !s.tasks[0].RunAt.After(now) {
+		due = append(due, heap.Pop(&s.tasks).(*Task))
+	}
+	return due
+}
+
+// Len возвращает число запланированных задач
+func (s *Scheduler) Len() int {
+	return s.tasks.Len()
+}
\ No newline at end of file
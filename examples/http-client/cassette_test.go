@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Проигрывание кассет, записанных examples/httprecord: ответы берутся из
+// testdata/*.json, без сервера и сети. Урезанная копия Replayer оттуда
+// же - только то, что нужно тестам. Перезаписать кассету:
+//   cd ../httprecord && go run . -mode record -target <адрес users API> \
+//     -cassette ../http-client/testdata/users.json
+
+type cassette struct {
+	Interactions []struct {
+		Request struct {
+			Method string `json:"method"`
+			URL    string `json:"url"`
+		} `json:"request"`
+		Response struct {
+			Status int         `json:"status"`
+			Header http.Header `json:"header"`
+			Body   string      `json:"body"`
+		} `json:"response"`
+	} `json:"interactions"`
+
+	mu   sync.Mutex
+	next int
+}
+
+func loadCassette(t *testing.T, path string) *cassette {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return &c
+}
+
+// RoundTrip отвечает записями строго по порядку: клиент должен сделать
+// те же запросы, что и при записи, иначе тест падает
+func (c *cassette) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.next >= len(c.Interactions) {
+		return nil, fmt.Errorf("кассета кончилась: %s %s", r.Method, r.URL.RequestURI())
+	}
+	in := c.Interactions[c.next]
+	if in.Request.Method != r.Method || in.Request.URL != r.URL.RequestURI() {
+		return nil, fmt.Errorf("ожидался %s %s, получен %s %s",
+			in.Request.Method, in.Request.URL, r.Method, r.URL.RequestURI())
+	}
+	c.next++
+	return &http.Response{
+		StatusCode: in.Response.Status,
+		Header:     in.Response.Header,
+		Body:       io.NopCloser(strings.NewReader(in.Response.Body)),
+		Request:    r,
+	}, nil
+}
+
+// played все ли записи проиграны
+func (c *cassette) played() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.next == len(c.Interactions)
+}
+
+func TestClient_Cassette(t *testing.T) {
+	tape := loadCassette(t, "testdata/users.json")
+	// Адрес не важен: запросы до сети не доходят
+	client := newTestClient("http://users.invalid", &http.Client{Transport: tape})
+	ctx := context.Background()
+	
+	// В кассете первый ответ - 503: клиент должен повторить запрос
+	user, err := client.GetUser(ctx, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.Name != "Иван Иванов" || user.Email != "ivan@example.com" {
+		t.Errorf("Expected Иван Иванов, got %+v", user)
+	}
+	
+	_, err = client.GetUser(ctx, 42)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 StatusError, got %v", err)
+	}
+	
+	if !tape.played() {
+		t.Error("Expected all recorded interactions to be played")
+	}
+}
\ No newline at end of file
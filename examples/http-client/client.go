@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// User модель пользователя из users API
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// StatusError ответ сервера с неуспешным статусом
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("статус %d: %s", e.Code, e.Body)
+}
+
+// Client HTTP клиент для users API с повторами
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewClient создает клиент. httpClient можно подменить, например,
+// на srv.Client() из httptest, который доверяет тестовому сертификату.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		maxRetries: 3,
+		backoff:    100 * time.Millisecond,
+	}
+}
+
+// GetUser получает пользователя по ID
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	var user User
+	if err := c.getJSON(ctx, fmt.Sprintf("/api/users/%d", id), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// getJSON выполняет GET и повторяет запрос при 5xx и сетевых ошибках
+func (c *Client) getJSON(ctx context.Context, path string, dst any) error {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.backoff * time.Duration(attempt)):
+			}
+		}
+		
+		retry, err := c.do(ctx, path, dst)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("после %d повторов: %w", c.maxRetries, lastErr)
+}
+
+// do выполняет один запрос и сообщает, имеет ли смысл повторять
+func (c *Client) do(ctx context.Context, path string, dst any) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Отмена контекста повторять бессмысленно, остальные сетевые ошибки - можно
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode >= 500 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return true, &StatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, &StatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+	
+	return false, json.NewDecoder(resp.Body).Decode(dst)
+}
\ No newline at end of file
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer отвечает по заранее заданному сценарию статусов,
+// после окончания сценария - 200 с пользователем
+type flakyServer struct {
+	mu       sync.Mutex
+	script   []int
+	delay    time.Duration
+	requests int
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	status := http.StatusOK
+	if len(f.script) > 0 {
+		status, f.script = f.script[0], f.script[1:]
+	}
+	delay := f.delay
+	f.mu.Unlock()
+	
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(User{ID: 1, Name: "John"})
+}
+
+func (f *flakyServer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func newTestClient(url string, hc *http.Client) *Client {
+	c := NewClient(url, hc)
+	c.backoff = time.Millisecond // Не ждем в тестах
+	return c
+}
+
+// Пример 1: Повторы при 5xx
+func TestClient_RetriesOn500(t *testing.T) {
+	flaky := &flakyServer{script: []int{500, 503}}
+	srv := httptest.NewServer(flaky)
+	defer srv.Close()
+	
+	user, err := newTestClient(srv.URL, nil).GetUser(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.Name != "John" {
+		t.Errorf("Expected 'John', got '%s'", user.Name)
+	}
+	if flaky.count() != 3 {
+		t.Errorf("Expected 3 requests, got %d", flaky.count())
+	}
+}
+
+// Пример 2: Табличный тест сценариев сбоев
+func TestClient_FailureScenarios(t *testing.T) {
+	tests := []struct {
+		name         string
+		script       []int
+		wantStatus   int
+		wantRequests int
+	}{
+		{"success", nil, 0, 1},
+		{"404 is not retried", []int{404}, 404, 1},
+		{"retries exhausted", []int{500, 500, 500, 500}, 500, 4},
+		{"recovers on last retry", []int{502, 502, 502}, 0, 4},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyServer{script: tt.script}
+			srv := httptest.NewServer(flaky)
+			defer srv.Close()
+			
+			_, err := newTestClient(srv.URL, nil).GetUser(context.Background(), 1)
+			
+			var statusErr *StatusError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case tt.wantStatus != 0 && !errors.As(err, &statusErr):
+				t.Fatalf("Expected StatusError, got %v", err)
+			case tt.wantStatus != 0 && statusErr.Code != tt.wantStatus:
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, statusErr.Code)
+			}
+			
+			if flaky.count() != tt.wantRequests {
+				t.Errorf("Expected %d requests, got %d", tt.wantRequests, flaky.count())
+			}
+		})
+	}
+}
+
+// Пример 3: Таймаут клиента против медленного сервера
+func TestClient_Timeout(t *testing.T) {
+	srv := httptest.NewServer(&flakyServer{delay: time.Second})
+	defer srv.Close()
+	
+	client := newTestClient(srv.URL, &http.Client{Timeout: 50 * time.Millisecond})
+	client.maxRetries = 0
+	
+	start := time.Now()
+	_, err := client.GetUser(context.Background(), 1)
+	if err == nil {
+		t.Fatal("Expected timeout error, but got none")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Timeout took too long: %v", elapsed)
+	}
+}
+
+// Пример 4: Отмена через контекст прерывает и повторы
+func TestClient_ContextCancel(t *testing.T) {
+	flaky := &flakyServer{delay: time.Second}
+	srv := httptest.NewServer(flaky)
+	defer srv.Close()
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	
+	_, err := newTestClient(srv.URL, nil).GetUser(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if flaky.count() != 1 {
+		t.Errorf("Canceled request should not be retried, got %d requests", flaky.count())
+	}
+}
+
+// Пример 5: TLS - клиенту нужно доверять сертификату сервера
+func TestClient_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(&flakyServer{})
+	defer srv.Close()
+	
+	t.Run("default client rejects self-signed certificate", func(t *testing.T) {
+		client := newTestClient(srv.URL, &http.Client{Timeout: time.Second})
+		client.maxRetries = 0
+		
+		_, err := client.GetUser(context.Background(), 1)
+		if err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Fatalf("Expected certificate error, got %v", err)
+		}
+	})
+	
+	t.Run("srv.Client trusts test certificate", func(t *testing.T) {
+		user, err := newTestClient(srv.URL, srv.Client()).GetUser(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user.ID != 1 {
+			t.Errorf("Expected ID 1, got %d", user.ID)
+		}
+	})
+}
\ No newline at end of file
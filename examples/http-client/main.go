@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+)
+
+// Пример 1: Клиент против локального сервера из httptest
+func clientExample() {
+	fmt.Println("=== Клиент и httptest.Server ===")
+	
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Первый запрос падает - клиент повторит
+		if requests.Add(1) == 1 {
+			http.Error(w, "временная ошибка", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(User{ID: 1, Name: "Иван Иванов", Email: "ivan@example.com"})
+	}))
+	defer srv.Close()
+	
+	client := NewClient(srv.URL, nil)
+	user, err := client.GetUser(context.Background(), 1)
+	if err != nil {
+		fmt.Println("Ошибка:", err)
+		return
+	}
+	fmt.Printf("Получен пользователь %+v за %d запроса\n", *user, requests.Load())
+}
+
+func main() {
+	clientExample()
+	fmt.Println("\nТесты с NewServer/NewTLSServer: go test -v")
+}
\ No newline at end of file
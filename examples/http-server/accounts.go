@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// accounts учетные записи для входа: общие для JWT (auth.go) и сессий
+// (session.go). Заполняются при запуске, дальше только читаются.
+type accounts struct {
+	byEmail map[string]account
+}
+
+// account учетная запись. Хеш здесь - SHA-256 только ради краткости
+// примера: для настоящих паролей нужен медленный хеш (bcrypt, argon2),
+// см. examples/passwords.
+type account struct {
+	userID       int
+	passwordHash [32]byte
+}
+
+func newAccounts() *accounts {
+	return &accounts{byEmail: make(map[string]account)}
+}
+
+// add регистрирует вход для пользователя
+func (a *accounts) add(email, password string, userID int) {
+	a.byEmail[email] = account{userID: userID, passwordHash: sha256.Sum256([]byte(password))}
+}
+
+// authenticate проверяет email и пароль
+func (a *accounts) authenticate(email, password string) (int, bool) {
+	acc, ok := a.byEmail[email]
+	// Сравнение идет и для неизвестного email: иначе по времени ответа
+	// видно, какие адреса зарегистрированы (см. examples/timing-attack)
+	hash := sha256.Sum256([]byte(password))
+	match := subtle.ConstantTimeCompare(hash[:], acc.passwordHash[:]) == 1
+	return acc.userID, ok && match
+}
+
+// demoAccounts входы для пользователей из users
+func demoAccounts() *accounts {
+	a := newAccounts()
+	a.add("ivan@example.com", "ivan-password", 1)
+	a.add("maria@example.com", "maria-password", 2)
+	return a
+}
\ No newline at end of file
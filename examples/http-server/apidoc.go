@@ -0,0 +1,131 @@
+package main
+
+import (
+	"net/http"
+
+	"httpserver/openapi"
+	"httpserver/router"
+)
+
+// Маршруты /api/users вместе с описанием для OpenAPI. Маршрутизатор и
+// документ строятся из одной таблицы: маршрут без описания не
+// зарегистрировать, а описание без маршрута не появится в документе.
+//
+//   GET /api/openapi.json - документ OpenAPI 3
+//   GET /api/docs         - Swagger UI
+
+// apiRoute маршрут и его описание
+type apiRoute struct {
+	op      openapi.Operation
+	handler http.HandlerFunc
+}
+
+var (
+	idParam     = openapi.Param{Name: "id", In: openapi.InPath, Type: 0, Description: "ID пользователя"}
+	badRequest  = openapi.Response{Description: "Неверный запрос", Body: openapi.PlainText}
+	notFoundErr = openapi.Response{Description: "Пользователь не найден", Body: openapi.PlainText}
+)
+
+// userRoutes маршруты примера 2
+func userRoutes() []apiRoute {
+	tags := []string{"users"}
+	return []apiRoute{
+		{openapi.Operation{
+			Method: http.MethodGet, Path: "/api/users", Tags: tags,
+			Summary: "Список пользователей",
+			Description: "Фильтр, сортировка и страницы - по номеру или по курсору. " +
+				"С параметром search - нечеткий поиск по имени, ответ - массив пользователей без конверта.",
+			Params: []openapi.Param{
+				{Name: "q", In: openapi.InQuery, Type: "", Description: "Подстрока имени или email без учета регистра"},
+				{Name: "sort", In: openapi.InQuery, Type: "", Enum: userSorts, Description: "Поле сортировки, по умолчанию id"},
+				{Name: "limit", In: openapi.InQuery, Type: 0, Description: "Размер страницы, от 1 до 100, по умолчанию 20"},
+				{Name: "page", In: openapi.InQuery, Type: 0, Description: "Номер страницы, с 1; нельзя вместе с cursor"},
+				{Name: "cursor", In: openapi.InQuery, Type: "", Description: "next_cursor из предыдущего ответа"},
+				{Name: "search", In: openapi.InQuery, Type: "", Description: "Нечеткий поиск по имени"},
+			},
+			Responses: map[int]openapi.Response{
+				http.StatusOK:         {Description: "Страница пользователей", Body: pagedUsers{}},
+				http.StatusBadRequest: badRequest,
+			},
+		}, listUsers},
+		{openapi.Operation{
+			Method: http.MethodPost, Path: "/api/users", Tags: tags,
+			Summary: "Создать пользователя",
+			Request: User{},
+			Responses: map[int]openapi.Response{
+				http.StatusCreated:    {Description: "Пользователь создан", Body: User{}},
+				http.StatusBadRequest: badRequest,
+			},
+		}, createUser},
+		{openapi.Operation{
+			Method: http.MethodGet, Path: "/api/users/autocomplete", Tags: tags,
+			Summary:     "Автодополнение имени",
+			Description: "Пользователи, чье имя начинается с q; с fuzzy=1 - с опечатками. Индекс строится по текущему списку пользователей.",
+			Params: []openapi.Param{
+				{Name: "q", In: openapi.InQuery, Type: "", Required: true, Description: "Начало имени без учета регистра"},
+				{Name: "limit", In: openapi.InQuery, Type: 0, Description: "Сколько вернуть, от 1 до 50, по умолчанию 10"},
+				{Name: "fuzzy", In: openapi.InQuery, Type: "", Description: "1 - допускать опечатки"},
+			},
+			Responses: map[int]openapi.Response{
+				http.StatusOK:         {Description: "Подходящие пользователи", Body: []User{}},
+				http.StatusBadRequest: badRequest,
+			},
+		}, autocomplete},
+		{openapi.Operation{
+			Method: http.MethodGet, Path: "/api/users/suggest", Tags: tags,
+			Summary: "Подсказки по мере ввода",
+			Description: "Каждое слово запроса - начало какого-то слова имени; без точных совпадений " +
+				"последнее слово ищется с опечатками. Запрос короче двух символов - пустой список.",
+			Params: []openapi.Param{
+				{Name: "q", In: openapi.InQuery, Type: "", Description: "Текст из поля ввода, до 100 символов"},
+				{Name: "limit", In: openapi.InQuery, Type: 0, Description: "Сколько вернуть, от 1 до 50, по умолчанию 10"},
+			},
+			Responses: map[int]openapi.Response{
+				http.StatusOK:         {Description: "Подсказки и исходный запрос", Body: suggestResponse{}},
+				http.StatusBadRequest: badRequest,
+			},
+		}, suggest},
+		{openapi.Operation{
+			Method: http.MethodGet, Path: "/api/users/{id}", Tags: tags,
+			Summary: "Пользователь по ID",
+			Params:  []openapi.Param{idParam},
+			Responses: map[int]openapi.Response{
+				http.StatusOK:         {Description: "Пользователь", Body: User{}},
+				http.StatusBadRequest: badRequest,
+				http.StatusNotFound:   notFoundErr,
+			},
+		}, getUser},
+		{openapi.Operation{
+			Method: http.MethodPut, Path: "/api/users/{id}", Tags: tags,
+			Summary: "Заменить пользователя",
+			Params:  []openapi.Param{idParam},
+			Request: User{},
+			Responses: map[int]openapi.Response{
+				http.StatusOK:         {Description: "Пользователь после изменения", Body: User{}},
+				http.StatusBadRequest: badRequest,
+				http.StatusNotFound:   notFoundErr,
+			},
+		}, updateUser},
+		{openapi.Operation{
+			Method: http.MethodDelete, Path: "/api/users/{id}", Tags: tags,
+			Summary: "Удалить пользователя",
+			Params:  []openapi.Param{idParam},
+			Responses: map[int]openapi.Response{
+				http.StatusNoContent:  {Description: "Пользователь удален"},
+				http.StatusBadRequest: badRequest,
+				http.StatusNotFound:   notFoundErr,
+			},
+		}, deleteUser},
+	}
+}
+
+// usersAPI маршрутизатор и документ для маршрутов routes
+func usersAPI(routes []apiRoute) (*router.Router, *openapi.Spec) {
+	r := router.New()
+	spec := openapi.New("Users API", "1.0.0")
+	for _, rt := range routes {
+		r.HandleFunc(rt.op.Method, rt.op.Path, rt.handler)
+		spec.Add(rt.op)
+	}
+	return r, spec
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"httpserver/openapi"
+)
+
+// Каждый описанный маршрут отвечает одним из описанных статусов, а
+// JSON-ответы отдаются с типом из документа
+func TestUserRoutes_MatchDocument(t *testing.T) {
+	withUsers(t, User{ID: 1, Name: "Иван Иванов", Email: "ivan@example.com"})
+	r, _ := usersAPI(userRoutes())
+	
+	for _, rt := range userRoutes() {
+		op := rt.op
+		t.Run(op.Method+" "+op.Path, func(t *testing.T) {
+			body := strings.NewReader("")
+			if op.Request != nil {
+				body = strings.NewReader(`{"name":"Петр","email":"petr@example.com"}`)
+			}
+			path := strings.ReplaceAll(op.Path, "{id}", "1")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(op.Method, path, body))
+			
+			resp, ok := op.Responses[w.Code]
+			if !ok {
+				t.Fatalf("Status %d is not documented: %s", w.Code, w.Body)
+			}
+			if resp.Body == nil && w.Body.Len() > 0 {
+				t.Errorf("Expected empty body, got %s", w.Body)
+			}
+			if resp.Body == nil {
+				return
+			}
+			expected := "application/json"
+			if resp.Body == openapi.PlainText {
+				expected = "text/plain"
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, expected) {
+				t.Errorf("Expected %s, got %q", expected, ct)
+			}
+		})
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	_, spec := usersAPI(userRoutes())
+	w := httptest.NewRecorder()
+	spec.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	
+	var doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, rt := range userRoutes() {
+		if _, ok := doc.Paths[rt.op.Path][strings.ToLower(rt.op.Method)]; !ok {
+			t.Errorf("Expected %s %s in document", rt.op.Method, rt.op.Path)
+		}
+	}
+	
+	// Все ссылки ведут на описанные схемы
+	body := w.Body.String()
+	const prefix = `"$ref": "#/components/schemas/`
+	for rest := body; ; {
+		i := strings.Index(rest, prefix)
+		if i < 0 {
+			break
+		}
+		rest = rest[i+len(prefix):]
+		name := rest[:strings.IndexByte(rest, '"')]
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("Expected schema %s in components", name)
+		}
+	}
+	
+	// Описание поля из тега doc
+	var user struct {
+		Properties map[string]struct {
+			Description string `json:"description"`
+		} `json:"properties"`
+	}
+	json.Unmarshal(doc.Components.Schemas["User"], &user)
+	if user.Properties["id"].Description == "" {
+		t.Error("Expected description for User.id")
+	}
+	if !strings.Contains(body, `"name": "limit"`) {
+		t.Error("Expected list parameters in document")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"clock"
+	"httpserver/router"
+)
+
+// jwtAuth выпуск и проверка токенов.
+//
+// Access-токен живет минуты и проверяется только по подписи, без
+// обращения к хранилищу - в этом смысл JWT. Обратная сторона: отозвать
+// его до истечения нельзя, поэтому срок короткий. Refresh-токен живет
+// дни, и сервер хранит его jti: при обновлении старый refresh удаляется
+// (ротация), а повторное предъявление уже использованного означает, что
+// токен украден, - тогда отзываются все refresh-токены пользователя.
+type jwtAuth struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	clock      clock.Clock
+
+	accounts *accounts
+
+	mu       sync.Mutex
+	refresh  map[string]refreshToken // jti -> действующий refresh
+	consumed map[string]refreshToken // jti -> уже обмененный refresh
+}
+
+type refreshToken struct {
+	userID    int
+	expiresAt time.Time
+}
+
+func newJWTAuth(clk clock.Clock, secret []byte, accounts *accounts) *jwtAuth {
+	return &jwtAuth{
+		secret:     secret,
+		accessTTL:  15 * time.Minute,
+		refreshTTL: 7 * 24 * time.Hour,
+		clock:      clk,
+		accounts:   accounts,
+		refresh:    make(map[string]refreshToken),
+		consumed:   make(map[string]refreshToken),
+	}
+}
+
+// tokenPair ответ на вход и обновление
+type tokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Секунды жизни access-токена
+}
+
+// issue выпускает пару токенов
+func (a *jwtAuth) issue(userID int) (tokenPair, error) {
+	now := a.clock.Now()
+	access, err := signJWT(a.secret, claims{
+		Subject:   userID,
+		Type:      tokenAccess,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(a.accessTTL).Unix(),
+	})
+	if err != nil {
+		return tokenPair{}, err
+	}
+	
+	jti, err := newTokenID()
+	if err != nil {
+		return tokenPair{}, err
+	}
+	refresh, err := signJWT(a.secret, claims{
+		Subject:   userID,
+		Type:      tokenRefresh,
+		ID:        jti,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(a.refreshTTL).Unix(),
+	})
+	if err != nil {
+		return tokenPair{}, err
+	}
+	
+	a.mu.Lock()
+	a.refresh[jti] = refreshToken{userID: userID, expiresAt: now.Add(a.refreshTTL)}
+	a.mu.Unlock()
+	return tokenPair{AccessToken: access, RefreshToken: refresh, TokenType: "Bearer", ExpiresIn: int(a.accessTTL.Seconds())}, nil
+}
+
+// rotate обменивает refresh-токен на новую пару
+func (a *jwtAuth) rotate(token string) (tokenPair, error) {
+	c, err := parseJWT(a.secret, token, a.clock.Now())
+	if err != nil {
+		return tokenPair{}, err
+	}
+	if c.Type != tokenRefresh {
+		return tokenPair{}, errInvalidToken
+	}
+	
+	a.mu.Lock()
+	t, ok := a.refresh[c.ID]
+	if ok {
+		delete(a.refresh, c.ID)
+		a.consumed[c.ID] = t
+	} else if t, reused := a.consumed[c.ID]; reused {
+		a.revokeAll(t.userID)
+	}
+	a.mu.Unlock()
+	if !ok {
+		return tokenPair{}, errInvalidToken
+	}
+	return a.issue(c.Subject)
+}
+
+// revokeAll отзывает refresh-токены пользователя; вызывается под mu
+func (a *jwtAuth) revokeAll(userID int) {
+	for jti, t := range a.refresh {
+		if t.userID == userID {
+			delete(a.refresh, jti)
+		}
+	}
+}
+
+// revoke отзывает refresh-токен при выходе; ошибки не возвращает:
+// выход с уже недействительным токеном - тоже выход
+func (a *jwtAuth) revoke(token string) {
+	c, err := parseJWT(a.secret, token, a.clock.Now())
+	if err != nil || c.Type != tokenRefresh {
+		return
+	}
+	a.mu.Lock()
+	delete(a.refresh, c.ID)
+	a.mu.Unlock()
+}
+
+// deleteExpired удаляет истекшие refresh-токены. Использованные jti
+// хранятся, пока не истек бы сам токен: позже повтор и так не пройдет
+// проверку срока.
+func (a *jwtAuth) deleteExpired() {
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, m := range []map[string]refreshToken{a.refresh, a.consumed} {
+		for jti, t := range m {
+			if !now.Before(t.expiresAt) {
+				delete(m, jti)
+			}
+		}
+	}
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type contextKey string
+
+const userIDKey contextKey = "userID"
+
+// currentUserID ID пользователя из access-токена; есть только за
+// authMiddleware
+func currentUserID(r *http.Request) (int, bool) {
+	id, ok := r.Context().Value(userIDKey).(int)
+	return id, ok
+}
+
+// authMiddleware пропускает только запросы с действительным access-токеном
+// в заголовке Authorization: Bearer <токен>. Ответ 401 с WWW-Authenticate
+// по RFC 6750: клиент по error="invalid_token" понимает, что пора
+// обновить токен через /api/refresh.
+func authMiddleware(a *jwtAuth, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+			return
+		}
+		
+		c, err := parseJWT(a.secret, token, a.clock.Now())
+		if err == nil && c.Type != tokenAccess {
+			err = errInvalidToken
+		}
+		if err != nil {
+			desc := "Недействительный токен"
+			if errors.Is(err, errTokenExpired) {
+				desc = "Срок действия токена истек"
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="`+err.Error()+`"`)
+			http.Error(w, desc, http.StatusUnauthorized)
+			return
+		}
+		
+		ctx := context.WithValue(r.Context(), userIDKey, c.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Вход: POST /api/login {"email": "...", "password": "..."}
+func (a *jwtAuth) login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		http.Error(w, "Неверный JSON", http.StatusBadRequest)
+		return
+	}
+	userID, ok := a.accounts.authenticate(req.Email, req.Password)
+	if !ok {
+		// Одна ошибка для неверного email и неверного пароля
+		http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		return
+	}
+	pair, err := a.issue(userID)
+	a.writeTokens(w, pair, err)
+}
+
+// Обновление: POST /api/refresh {"refresh_token": "..."}
+func (a *jwtAuth) refreshTokens(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<12)).Decode(&req); err != nil {
+		http.Error(w, "Неверный JSON", http.StatusBadRequest)
+		return
+	}
+	pair, err := a.rotate(req.RefreshToken)
+	if errors.Is(err, errInvalidToken) || errors.Is(err, errTokenExpired) {
+		http.Error(w, "Недействительный refresh-токен, войдите заново", http.StatusUnauthorized)
+		return
+	}
+	a.writeTokens(w, pair, err)
+}
+
+// Выход: POST /api/logout {"refresh_token": "..."}
+func (a *jwtAuth) logout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<12)).Decode(&req); err != nil {
+		http.Error(w, "Неверный JSON", http.StatusBadRequest)
+		return
+	}
+	a.revoke(req.RefreshToken)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *jwtAuth) writeTokens(w http.ResponseWriter, pair tokenPair, err error) {
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	// Токены - учетные данные: прокси и браузер не должны их кэшировать
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pair)
+}
+
+// Текущий пользователь: GET /api/secure/me
+func getMe(w http.ResponseWriter, r *http.Request) {
+	id, _ := currentUserID(r)
+	user, ok := cachedUsers.Get(id, findUser)
+	if !ok {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// onlySelf пропускает изменение только собственной записи
+func onlySelf(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := userID(w, r)
+		if !ok {
+			return
+		}
+		if current, _ := currentUserID(r); current != id {
+			http.Error(w, "Можно изменять только свою запись", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// secureRoutes вход, обновление токенов и защищенные варианты
+// пользовательских маршрутов под /api/secure/
+func secureRoutes(a *jwtAuth) http.Handler {
+	public := router.New()
+	public.HandleFunc(http.MethodPost, "/api/login", a.login)
+	public.HandleFunc(http.MethodPost, "/api/refresh", a.refreshTokens)
+	public.HandleFunc(http.MethodPost, "/api/logout", a.logout)
+	
+	protected := router.New()
+	protected.HandleFunc(http.MethodGet, "/api/secure/me", getMe)
+	protected.HandleFunc(http.MethodGet, "/api/secure/users", listUsers)
+	protected.HandleFunc(http.MethodPost, "/api/secure/users", createUser)
+	protected.HandleFunc(http.MethodGet, "/api/secure/users/{id}", getUser)
+	protected.HandleFunc(http.MethodPut, "/api/secure/users/{id}", onlySelf(updateUser))
+	protected.HandleFunc(http.MethodDelete, "/api/secure/users/{id}", onlySelf(deleteUser))
+	
+	mux := http.NewServeMux()
+	mux.Handle("/api/secure/", authMiddleware(a, protected))
+	mux.Handle("/", public)
+	return mux
+}
\ No newline at end of file
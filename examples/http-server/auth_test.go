@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"clock"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestParseJWT(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	valid, err := signJWT(testSecret, claims{Subject: 1, Type: tokenAccess, ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expired, _ := signJWT(testSecret, claims{Subject: 1, Type: tokenAccess, ExpiresAt: now.Unix()})
+	other, _ := signJWT([]byte("другой секрет"), claims{Subject: 1, Type: tokenAccess, ExpiresAt: now.Add(time.Minute).Unix()})
+	
+	parts := strings.Split(valid, ".")
+	enc := base64.RawURLEncoding
+	forgedPayload := enc.EncodeToString([]byte(`{"sub":2,"typ":"access","exp":9999999999}`))
+	noneHeader := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"valid", valid, nil},
+		{"expired", expired, errTokenExpired},
+		{"other secret", other, errInvalidToken},
+		{"forged payload", parts[0] + "." + forgedPayload + "." + parts[2], errInvalidToken},
+		{"alg none", noneHeader + "." + parts[1] + ".", errInvalidToken},
+		{"two parts", parts[0] + "." + parts[1], errInvalidToken},
+		{"garbage", "не.токен.вовсе", errInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseJWT(testSecret, tt.token, now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Expected error %v, got %v", tt.want, err)
+			}
+			if err == nil && c.Subject != 1 {
+				t.Errorf("Expected subject 1, got %d", c.Subject)
+			}
+		})
+	}
+}
+
+// authEnv сервер с авторизацией и управляемыми часами
+type authEnv struct {
+	t       *testing.T
+	auth    *jwtAuth
+	handler http.Handler
+	clock   *clock.FakeClock
+}
+
+func newAuthEnv(t *testing.T) *authEnv {
+	clk := clock.NewFakeClock(time.Unix(1_700_000_000, 0))
+	env := &authEnv{t: t, auth: newJWTAuth(clk, testSecret, demoAccounts()), clock: clk}
+	env.handler = secureRoutes(env.auth)
+	return env
+}
+
+func (e *authEnv) do(method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (e *authEnv) tokens(rec *httptest.ResponseRecorder) tokenPair {
+	e.t.Helper()
+	if rec.Code != http.StatusOK {
+		e.t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var pair tokenPair
+	if err := json.NewDecoder(rec.Body).Decode(&pair); err != nil {
+		e.t.Fatalf("Unexpected error: %v", err)
+	}
+	return pair
+}
+
+func (e *authEnv) login() tokenPair {
+	e.t.Helper()
+	return e.tokens(e.do("POST", "/api/login", "", `{"email":"ivan@example.com","password":"ivan-password"}`))
+}
+
+func (e *authEnv) refresh(token string) *httptest.ResponseRecorder {
+	return e.do("POST", "/api/refresh", "", `{"refresh_token":"`+token+`"}`)
+}
+
+func TestLogin(t *testing.T) {
+	env := newAuthEnv(t)
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"ok", `{"email":"ivan@example.com","password":"ivan-password"}`, http.StatusOK},
+		{"wrong password", `{"email":"ivan@example.com","password":"nope"}`, http.StatusUnauthorized},
+		{"unknown email", `{"email":"nobody@example.com","password":"ivan-password"}`, http.StatusUnauthorized},
+		{"bad json", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := env.do("POST", "/api/login", "", tt.body); rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestProtectedRoutes(t *testing.T) {
+	env := newAuthEnv(t)
+	pair := env.login()
+	
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"no token", "GET", "/api/secure/me", "", http.StatusUnauthorized},
+		{"garbage token", "GET", "/api/secure/me", "abc", http.StatusUnauthorized},
+		{"refresh as access", "GET", "/api/secure/me", pair.RefreshToken, http.StatusUnauthorized},
+		{"me", "GET", "/api/secure/me", pair.AccessToken, http.StatusOK},
+		{"get user", "GET", "/api/secure/users/2", pair.AccessToken, http.StatusOK},
+		{"delete other", "DELETE", "/api/secure/users/2", pair.AccessToken, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := env.do(tt.method, tt.path, tt.token, ""); rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+	
+	rec := env.do("GET", "/api/secure/me", "", "")
+	if got := rec.Header().Get("WWW-Authenticate"); !strings.HasPrefix(got, "Bearer") {
+		t.Errorf("Expected Bearer challenge, got %q", got)
+	}
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	env := newAuthEnv(t)
+	pair := env.login()
+	
+	env.clock.Advance(env.auth.accessTTL)
+	rec := env.do("GET", "/api/secure/me", pair.AccessToken, "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("WWW-Authenticate"); !strings.Contains(got, `error="invalid_token"`) {
+		t.Errorf("Expected invalid_token challenge, got %q", got)
+	}
+	
+	// Refresh-токен еще жив и дает новый access
+	fresh := env.tokens(env.refresh(pair.RefreshToken))
+	if rec := env.do("GET", "/api/secure/me", fresh.AccessToken, ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with refreshed token, got %d", rec.Code)
+	}
+}
+
+func TestRefreshRotation(t *testing.T) {
+	env := newAuthEnv(t)
+	first := env.login()
+	second := env.tokens(env.refresh(first.RefreshToken))
+	
+	// Повтор уже обмененного токена - признак кражи: отзываются все
+	// refresh-токены пользователя, включая выданный законно
+	if rec := env.refresh(first.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected reused token to be rejected, got %d", rec.Code)
+	}
+	if rec := env.refresh(second.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected all tokens revoked after reuse, got %d", rec.Code)
+	}
+}
+
+func TestRefreshExpiryAndLogout(t *testing.T) {
+	env := newAuthEnv(t)
+	pair := env.login()
+	env.clock.Advance(env.auth.refreshTTL)
+	if rec := env.refresh(pair.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected expired refresh token to be rejected, got %d", rec.Code)
+	}
+	
+	pair = env.login()
+	if rec := env.do("POST", "/api/logout", "", `{"refresh_token":"`+pair.RefreshToken+`"}`); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if rec := env.refresh(pair.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected refresh after logout to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	env := newAuthEnv(t)
+	pair := env.login()
+	env.tokens(env.refresh(pair.RefreshToken))
+	
+	env.clock.Advance(env.auth.refreshTTL)
+	env.auth.deleteExpired()
+	if len(env.auth.refresh) != 0 || len(env.auth.consumed) != 0 {
+		t.Errorf("Expected no stored tokens, got %d active and %d consumed", len(env.auth.refresh), len(env.auth.consumed))
+	}
+}
\ No newline at end of file
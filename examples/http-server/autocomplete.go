@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"trie"
+)
+
+// Автодополнение имен: GET /api/users/autocomplete?q=мар&limit=5&fuzzy=1
+// Префиксное дерево - examples/trie (подключен через replace в go.mod).
+//
+// Индекс строится по users и перестраивается при первом запросе после
+// любого изменения пользователей: Create, Update и Delete меняют
+// users.Version. Так созданный пользователь сразу виден в подсказках,
+// а удаленный пропадает. Перестройка - проход по всем именам; для
+// учебного сервера это дешевле и проще, чем править дерево на каждое
+// изменение, а запросы без изменений между ними берут готовый индекс.
+
+// UserIndex индекс имен для автодополнения. Ключ - имя в нижнем регистре,
+// значение - все пользователи с таким именем (тезки не теряются).
+type UserIndex struct {
+	trie *trie.Trie[[]User]
+}
+
+// NewUserIndex строит индекс по списку пользователей
+func NewUserIndex(users []User) *UserIndex {
+	idx := &UserIndex{trie: trie.NewTrie[[]User]()}
+	for _, u := range users {
+		key := normalize(u.Name)
+		existing, _ := idx.trie.Get(key)
+		idx.trie.Insert(key, append(existing, u))
+	}
+	return idx
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// Complete возвращает до limit пользователей, чье имя начинается с q
+func (idx *UserIndex) Complete(q string, limit int) []User {
+	result := []User{}
+	for _, group := range idx.trie.WithPrefix(normalize(q)) {
+		for _, u := range group {
+			if len(result) == limit {
+				return result
+			}
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// CompleteFuzzy то же, но допускает опечатки: одна правка на каждые 3 символа запроса
+func (idx *UserIndex) CompleteFuzzy(q string, limit int) []User {
+	q = normalize(q)
+	result := []User{}
+	for _, m := range idx.trie.Fuzzy(q, len([]rune(q))/3) {
+		for _, u := range m.Value {
+			if len(result) == limit {
+				return result
+			}
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// usersIndex индекс, построенный по users, вместе с версией хранилища,
+// по которой он построен
+type usersIndex[T any] struct {
+	build func([]User) T
+	
+	mu      sync.Mutex
+	store   *userStore // Тесты подменяют users целиком (withUsers)
+	version uint64
+	idx     T
+}
+
+func newUsersIndex[T any](build func([]User) T) *usersIndex[T] {
+	return &usersIndex[T]{build: build}
+}
+
+// get индекс для текущего состояния users
+func (x *usersIndex[T]) get() T {
+	store := users
+	version := store.Version()
+	
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.store != store || x.version != version {
+		list, v := store.Snapshot()
+		x.idx, x.store, x.version = x.build(list), store, v
+	}
+	return x.idx
+}
+
+var autocompleteIndex = newUsersIndex(NewUserIndex)
+
+// autocomplete GET /api/users/autocomplete?q=...&limit=...&fuzzy=1.
+// Метод проверяет router: на другой ответит 405 сам.
+func autocomplete(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		http.Error(w, "Параметр q обязателен", http.StatusBadRequest)
+		return
+	}
+	
+	limit := 10
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 50 {
+			http.Error(w, "limit должен быть от 1 до 50", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	
+	idx := autocompleteIndex.get()
+	var result []User
+	if r.URL.Query().Get("fuzzy") == "1" {
+		result = idx.CompleteFuzzy(q, limit)
+	} else {
+		result = idx.Complete(q, limit)
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
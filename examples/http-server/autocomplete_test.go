@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// autocompleteIDs запрос к /api/users/autocomplete через маршрутизатор
+// users API: заодно проверяется, что литерал autocomplete важнее {id}
+func autocompleteIDs(t *testing.T, query url.Values) (int, []int) {
+	t.Helper()
+	r, _ := usersAPI(userRoutes())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users/autocomplete?"+query.Encode(), nil))
+	if w.Code != http.StatusOK {
+		return w.Code, nil
+	}
+	var got []User
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ids := []int{}
+	for _, u := range got {
+		ids = append(ids, u.ID)
+	}
+	return w.Code, ids
+}
+
+func TestAutocomplete(t *testing.T) {
+	withUsers(t,
+		User{ID: 1, Name: "Иван Иванов"},
+		User{ID: 2, Name: "Мария Петрова"},
+		User{ID: 3, Name: "Марина Соколова"},
+		User{ID: 4, Name: "Марк Лебедев"},
+		User{ID: 5, Name: "Иван Смирнов"},
+	)
+	tests := []struct {
+		name    string
+		query   url.Values
+		status  int
+		wantIDs []int
+	}{
+		{"префикс", url.Values{"q": {"Мар"}}, http.StatusOK, []int{3, 2, 4}},
+		{"регистр и limit", url.Values{"q": {"ИВАН"}, "limit": {"1"}}, http.StatusOK, []int{1}},
+		{"опечатка", url.Values{"q": {"мра"}, "fuzzy": {"1"}}, http.StatusOK, []int{3, 2, 4}},
+		{"без опечаток не найдет", url.Values{"q": {"мра"}}, http.StatusOK, []int{}},
+		{"без q", url.Values{}, http.StatusBadRequest, nil},
+		{"пробелы", url.Values{"q": {"  "}}, http.StatusBadRequest, nil},
+		{"неверный limit", url.Values{"q": {"мар"}, "limit": {"0"}}, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ids := autocompleteIDs(t, tt.query)
+			if status != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, status)
+			}
+			if tt.wantIDs != nil && !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected ids %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+// Индекс следует за users: созданный, переименованный и удаленный
+// пользователь сразу видны в подсказках
+func TestAutocomplete_FollowsUsers(t *testing.T) {
+	withUsers(t, User{ID: 1, Name: "Иван Иванов"})
+	q := url.Values{"q": {"пет"}}
+	if _, ids := autocompleteIDs(t, q); len(ids) != 0 {
+		t.Fatalf("Expected no suggestions, got %v", ids)
+	}
+	
+	w := httptest.NewRecorder()
+	createUser(w, httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{"name":"Петр Петров"}`)))
+	if _, ids := autocompleteIDs(t, q); !slices.Equal(ids, []int{2}) {
+		t.Errorf("Expected created user 2, got %v", ids)
+	}
+	
+	users.Update(User{ID: 2, Name: "Олег Петров"})
+	if _, ids := autocompleteIDs(t, q); len(ids) != 0 {
+		t.Errorf("Expected renamed user to leave suggestions, got %v", ids)
+	}
+	if _, ids := autocompleteIDs(t, url.Values{"q": {"олег"}}); !slices.Equal(ids, []int{2}) {
+		t.Errorf("Expected renamed user under new name, got %v", ids)
+	}
+	
+	users.Delete(2)
+	if _, ids := autocompleteIDs(t, url.Values{"q": {"олег"}}); len(ids) != 0 {
+		t.Errorf("Expected deleted user to leave suggestions, got %v", ids)
+	}
+}
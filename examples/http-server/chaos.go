@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Внедрение сбоев (chaos): middleware по правилам для префиксов путей
+// задерживает ответы, отвечает 5xx и обрывает соединения. Так повторы
+// и таймауты клиента (examples/http-client, examples/context) можно
+// проверить на сервере, который ведет себя как настоящий под нагрузкой,
+// а не только в моках.
+//
+// Только для разработки: включается переменной CHAOS, без нее
+// middleware не ставится вовсе. Формат - правила через ";", в правиле
+// префикс пути и сбои с вероятностью в процентах после "@":
+//
+//   CHAOS="/api/users latency=300ms@50 error=503@10 drop@5; /api/ping error=500@20"
+//
+// Для запроса берется правило с самым длинным подходящим префиксом,
+// "/" подходит ко всем.
+
+// chaosRule сбои для префикса пути; проценты от 0 до 100
+type chaosRule struct {
+	prefix     string
+	latency    time.Duration
+	latencyPct float64
+	status     int
+	errorPct   float64
+	dropPct    float64
+}
+
+// chaos набор правил
+type chaos struct {
+	rules []chaosRule
+	// roll случайное число в [0, 100); подменяется в тестах
+	roll  func() float64
+	sleep func(r *http.Request, d time.Duration)
+}
+
+// parseChaos разбирает правила из формата переменной CHAOS
+func parseChaos(spec string) (*chaos, error) {
+	c := &chaos{
+		roll:  func() float64 { return rand.Float64() * 100 },
+		sleep: sleepCtx,
+	}
+	for _, part := range strings.Split(spec, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		rule := chaosRule{prefix: fields[0]}
+		if !strings.HasPrefix(rule.prefix, "/") {
+			return nil, fmt.Errorf("chaos: префикс должен начинаться с /, получено %q", rule.prefix)
+		}
+		for _, f := range fields[1:] {
+			if err := rule.parseFault(f); err != nil {
+				return nil, fmt.Errorf("chaos: %s: %w", rule.prefix, err)
+			}
+		}
+		c.rules = append(c.rules, rule)
+	}
+	if len(c.rules) == 0 {
+		return nil, fmt.Errorf("chaos: нет правил в %q", spec)
+	}
+	return c, nil
+}
+
+// parseFault разбирает один сбой: latency=300ms@50, error=503@10, drop@5
+func (r *chaosRule) parseFault(f string) error {
+	kindValue, pctStr, ok := strings.Cut(f, "@")
+	if !ok {
+		return fmt.Errorf("нет вероятности в %q", f)
+	}
+	pct, err := strconv.ParseFloat(pctStr, 64)
+	if err != nil || pct < 0 || pct > 100 {
+		return fmt.Errorf("вероятность в %q должна быть от 0 до 100", f)
+	}
+	kind, value, _ := strings.Cut(kindValue, "=")
+	switch kind {
+	case "latency":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("неверная задержка в %q", f)
+		}
+		r.latency, r.latencyPct = d, pct
+	case "error":
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 500 || status > 599 {
+			return fmt.Errorf("статус в %q должен быть 5xx", f)
+		}
+		r.status, r.errorPct = status, pct
+	case "drop":
+		r.dropPct = pct
+	default:
+		return fmt.Errorf("неизвестный сбой %q", f)
+	}
+	return nil
+}
+
+// match правило с самым длинным префиксом, подходящим к path
+func (c *chaos) match(path string) (chaosRule, bool) {
+	var best chaosRule
+	found := false
+	for _, r := range c.rules {
+		if strings.HasPrefix(path, r.prefix) && (!found || len(r.prefix) > len(best.prefix)) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+func sleepCtx(r *http.Request, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-r.Context().Done():
+	}
+}
+
+// chaosMiddleware применяет сбои правила к запросу. Каждый сбой
+// выпадает независимо: сначала задержка (медленный ответ и потом
+// ошибка - частый случай), затем обрыв, затем ошибка.
+func chaosMiddleware(c *chaos, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := c.match(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if rule.latencyPct > 0 && c.roll() < rule.latencyPct {
+			c.sleep(r, rule.latency)
+		}
+		if rule.dropPct > 0 && c.roll() < rule.dropPct {
+			// net/http закрывает соединение без ответа и не пишет в лог
+			// стек: клиент увидит EOF, как при падении сервера
+			panic(http.ErrAbortHandler)
+		}
+		if rule.errorPct > 0 && c.roll() < rule.errorPct {
+			w.Header().Set("X-Chaos", "error")
+			http.Error(w, "Внедренная ошибка", rule.status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withChaos ставит chaosMiddleware, если задана переменная CHAOS, и
+// громко пишет об этом в лог: случайные 503 в логах без такой строки
+// стоят часов поиска причины
+func withChaos(spec string, next http.Handler) (http.Handler, error) {
+	if spec == "" {
+		return next, nil
+	}
+	c, err := parseChaos(spec)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("ВНИМАНИЕ: включено внедрение сбоев (CHAOS=%q), не для production", spec)
+	return chaosMiddleware(c, next), nil
+}
\ No newline at end of file
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseChaos(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{"все сбои", "/api/users latency=300ms@50 error=503@10 drop@5", false},
+		{"несколько правил", "/api error=500@20; /api/ping drop@1;", false},
+		{"дробный процент", "/ latency=1s@0.5", false},
+		{"пусто", " ; ", true},
+		{"префикс без /", "api error=500@20", true},
+		{"нет вероятности", "/api error=500", true},
+		{"процент больше 100", "/api drop@150", true},
+		{"не 5xx", "/api error=404@10", true},
+		{"неверная задержка", "/api latency=быстро@10", true},
+		{"неизвестный сбой", "/api explode@10", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseChaos(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestChaos_Match(t *testing.T) {
+	c, err := parseChaos("/ error=500@1; /api error=502@1; /api/users error=503@1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/", 500},
+		{"/static/app.js", 500},
+		{"/api/ping", 502},
+		{"/api/users/1", 503},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rule, ok := c.match(tt.path)
+			if !ok || rule.status != tt.expected {
+				t.Errorf("Expected %d, got %d (found=%v)", tt.expected, rule.status, ok)
+			}
+		})
+	}
+}
+
+// fixedRolls отдает числа по очереди: тест сам решает, какие сбои выпадут
+func fixedRolls(rolls ...float64) func() float64 {
+	return func() float64 {
+		v := rolls[0]
+		rolls = rolls[1:]
+		return v
+	}
+}
+
+func TestChaosMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "pong")
+	})
+	tests := []struct {
+		name     string
+		rolls    []float64 // задержка, обрыв, ошибка
+		path     string
+		expected int
+		slept    bool
+	}{
+		{"без сбоев", []float64{99, 99, 99}, "/api/ping", http.StatusOK, false},
+		{"задержка", []float64{10, 99, 99}, "/api/ping", http.StatusOK, true},
+		{"ошибка", []float64{99, 99, 10}, "/api/ping", http.StatusServiceUnavailable, false},
+		{"задержка и ошибка", []float64{10, 99, 10}, "/api/ping", http.StatusServiceUnavailable, true},
+		{"другой путь", nil, "/health", http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseChaos("/api latency=1s@50 error=503@50 drop@50")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			c.roll = fixedRolls(tt.rolls...)
+			var slept time.Duration
+			c.sleep = func(_ *http.Request, d time.Duration) { slept = d }
+			
+			w := httptest.NewRecorder()
+			chaosMiddleware(c, ok).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			
+			if w.Code != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, w.Code)
+			}
+			if (slept == time.Second) != tt.slept {
+				t.Errorf("Expected slept=%v, got %v", tt.slept, slept)
+			}
+		})
+	}
+}
+
+func TestChaosMiddleware_Drop(t *testing.T) {
+	c, err := parseChaos("/ drop@100")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	srv := httptest.NewServer(chaosMiddleware(c, http.NotFoundHandler()))
+	defer srv.Close()
+	
+	// Соединение закрыто без ответа: у клиента ошибка, а не статус
+	resp, err := srv.Client().Get(srv.URL + "/api/ping")
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("Expected connection error, got %d", resp.StatusCode)
+	}
+}
+
+func TestChaosMiddleware_LatencyRespectsContext(t *testing.T) {
+	c, err := parseChaos("/ latency=10s@100")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	srv := httptest.NewServer(chaosMiddleware(c, http.NotFoundHandler()))
+	defer srv.Close()
+	
+	// Клиент бросает запрос по таймауту, сервер не держит горутину 10 секунд
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("Expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected quick timeout, got %v", elapsed)
+	}
+	// srv.Close ждет активные обработчики: sleep без ctx повесил бы
+	// тест на 10 секунд
+}
+
+func TestWithChaos_Disabled(t *testing.T) {
+	next := http.NotFoundHandler()
+	h, err := withChaos("", next)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+	if _, err := withChaos("/api drop", next); err == nil {
+		t.Error("Expected error for invalid spec")
+	}
+}
\ No newline at end of file
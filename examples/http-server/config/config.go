@@ -0,0 +1,381 @@
+// Package config собирает настройки сервера из четырех источников;
+// каждый следующий перекрывает предыдущий:
+//
+//  1. значения по умолчанию (Default);
+//  2. файл JSON или YAML (-config или HTTP_CONFIG), только заданные ключи;
+//  3. переменные окружения HTTP_*;
+//  4. флаги командной строки.
+//
+// Так файл описывает окружение целиком, переменные подстраивают его в
+// контейнере, а флаг - разовый запуск. Незнакомый ключ в файле - ошибка,
+// а не молчание: опечатка "read_timout" иначе оставила бы значение по
+// умолчанию, и никто бы не заметил.
+//
+// Имя настройки одно на все источники: ключ read_timeout в файле -
+// переменная HTTP_READ_TIMEOUT - флаг -read-timeout. Пример файла со
+// всеми ключами - config.example.yaml в корне http-server.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config настройки HTTP-сервера
+type Config struct {
+	Addr string
+
+	// ReadHeaderTimeout обязателен: без него клиент, который шлет
+	// заголовки по байту (Slowloris), держит соединение сколько угодно
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	// WriteTimeout 0 по умолчанию: поток /api/events живет часами, и
+	// таймаут записи оборвал бы его
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+
+	// CORSOrigins origin'ы, которым разрешены запросы из браузера;
+	// "*" - всем, пусто - только своему
+	CORSOrigins []string
+
+	// DBPath файл, где пользователи переживают перезапуск; пусто -
+	// только в памяти
+	DBPath string
+
+	// LogFormat "text" - key=value для человека в терминале, "json" -
+	// для сборщика логов (Loki, ELK); LogLevel - ниже него не пишется
+	LogFormat string
+	LogLevel  slog.Level
+}
+
+// Default настройки по умолчанию: удобны локально, для production их
+// перекрывает файл или окружение
+func Default() Config {
+	return Config{
+		Addr:              ":8080",
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       time.Minute,
+		IdleTimeout:       2 * time.Minute,
+		ShutdownTimeout:   10 * time.Second,
+		CORSOrigins:       []string{"*"},
+		LogFormat:         "text",
+		LogLevel:          slog.LevelInfo,
+	}
+}
+
+// setting одна настройка: ее ключ и как ее прочитать из строки. Флаги,
+// переменные и файл разбираются одним и тем же set, поэтому "5s" везде
+// значит одно и то же.
+type setting struct {
+	key   string
+	usage string
+	set   func(c *Config, v string) error
+	get   func(c Config) string
+}
+
+func (s setting) env() string  { return "HTTP_" + strings.ToUpper(s.key) }
+func (s setting) flag() string { return strings.ReplaceAll(s.key, "_", "-") }
+
+func durationSetting(key, usage string, field func(c *Config) *time.Duration) setting {
+	return setting{
+		key:   key,
+		usage: usage,
+		set: func(c *Config, v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return err
+			}
+			*field(c) = d
+			return nil
+		},
+		get: func(c Config) string { return field(&c).String() },
+	}
+}
+
+var settings = []setting{
+	{
+		key:   "addr",
+		usage: "адрес сервера, host:port",
+		set:   func(c *Config, v string) error { c.Addr = v; return nil },
+		get:   func(c Config) string { return c.Addr },
+	},
+	durationSetting("read_header_timeout", "время на чтение заголовков запроса",
+		func(c *Config) *time.Duration { return &c.ReadHeaderTimeout }),
+	durationSetting("read_timeout", "время на чтение всего запроса с телом; 0 - без ограничения",
+		func(c *Config) *time.Duration { return &c.ReadTimeout }),
+	durationSetting("write_timeout", "время на запись ответа; 0 - без ограничения",
+		func(c *Config) *time.Duration { return &c.WriteTimeout }),
+	durationSetting("idle_timeout", "сколько держать простаивающее keep-alive соединение",
+		func(c *Config) *time.Duration { return &c.IdleTimeout }),
+	durationSetting("shutdown_timeout", "запас на остановку: дождаться запросов и закрыть ресурсы",
+		func(c *Config) *time.Duration { return &c.ShutdownTimeout }),
+	{
+		key:   "cors_origins",
+		usage: "разрешенные origin'ы через запятую; * - все",
+		set: func(c *Config, v string) error {
+			c.CORSOrigins = nil
+			for _, o := range strings.Split(v, ",") {
+				if o = strings.TrimSpace(o); o != "" {
+					c.CORSOrigins = append(c.CORSOrigins, o)
+				}
+			}
+			return nil
+		},
+		get: func(c Config) string { return strings.Join(c.CORSOrigins, ",") },
+	},
+	{
+		key:   "db_path",
+		usage: "файл с пользователями; пусто - только в памяти",
+		set:   func(c *Config, v string) error { c.DBPath = v; return nil },
+		get:   func(c Config) string { return c.DBPath },
+	},
+	{
+		key:   "log_format",
+		usage: "формат журнала: text или json",
+		set:   func(c *Config, v string) error { c.LogFormat = v; return nil },
+		get:   func(c Config) string { return c.LogFormat },
+	},
+	{
+		key:   "log_level",
+		usage: "уровень журнала: debug, info, warn или error",
+		set:   func(c *Config, v string) error { return c.LogLevel.UnmarshalText([]byte(v)) },
+		get:   func(c Config) string { return strings.ToLower(c.LogLevel.String()) },
+	},
+}
+
+// Load разбирает args флагами из fs (туда же добавляются флаги
+// настроек и -config), затем собирает Config по порядку источников и
+// проверяет его. getenv - обычно os.Getenv; пустая переменная считается
+// незаданной.
+func Load(fs *flag.FlagSet, args []string, getenv func(string) string) (Config, error) {
+	type flagValue struct {
+		s setting
+		v string
+	}
+	var flags []flagValue
+	path := fs.String("config", "", "файл настроек .json, .yaml или .yml (или HTTP_CONFIG)")
+	def := Default()
+	for _, s := range settings {
+		fs.Func(s.flag(), fmt.Sprintf("%s (%s, по умолчанию %q)", s.usage, s.env(), s.get(def)), func(v string) error {
+			// Проверка формата сразу, применение - после файла и
+			// окружения, чтобы флаг победил их независимо от порядка
+			var scratch Config
+			if err := s.set(&scratch, v); err != nil {
+				return err
+			}
+			flags = append(flags, flagValue{s, v})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	
+	c := def
+	if *path == "" {
+		*path = getenv("HTTP_CONFIG")
+	}
+	if *path != "" {
+		if err := c.loadFile(*path); err != nil {
+			return Config{}, fmt.Errorf("config: %s: %w", *path, err)
+		}
+	}
+	for _, s := range settings {
+		if v := getenv(s.env()); v != "" {
+			if err := s.set(&c, v); err != nil {
+				return Config{}, fmt.Errorf("config: %s: %w", s.env(), err)
+			}
+		}
+	}
+	for _, f := range flags {
+		f.s.set(&c, f.v)
+	}
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// loadFile применяет ключи из файла. Формат - по расширению; YAML
+// разбирается в те же значения, что JSON, и дальше путь общий.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&values); err != nil {
+			return err
+		}
+	case ".yaml", ".yml":
+		if values, err = parseYAML(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("неизвестный формат %q: нужен .json, .yaml или .yml", ext)
+	}
+	
+	byKey := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byKey[s.key] = s
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys) // Ошибки в одном и том же порядке
+	var errs []error
+	for _, key := range keys {
+		raw := values[key]
+		s, ok := byKey[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("неизвестный ключ %q", key))
+			continue
+		}
+		v, err := fileValue(raw)
+		if err == nil {
+			err = s.set(c, v)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fileValue значение из файла в том же виде, что у переменной
+// окружения: список - через запятую
+func fileValue(raw any) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("элемент списка %v не строка", item)
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("значение %v: нужна строка или список строк", raw)
+	}
+}
+
+// Validate проверяет настройки все сразу: исправлять по одной ошибке
+// за запуск утомительно
+func (c Config) Validate() error {
+	var errs []error
+	add := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("config: %s: "+format, append([]any{key}, args...)...))
+	}
+	
+	if _, port, err := net.SplitHostPort(c.Addr); err != nil {
+		add("addr", "%v", err)
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		add("addr", "порт %q не число от 0 до 65535", port)
+	}
+	
+	if c.ReadHeaderTimeout <= 0 {
+		add("read_header_timeout", "должен быть больше нуля")
+	}
+	for _, t := range []struct {
+		key string
+		d   time.Duration
+	}{
+		{"read_timeout", c.ReadTimeout},
+		{"write_timeout", c.WriteTimeout},
+		{"idle_timeout", c.IdleTimeout},
+	} {
+		if t.d < 0 {
+			add(t.key, "не может быть отрицательным")
+		}
+	}
+	if c.ShutdownTimeout <= 0 {
+		add("shutdown_timeout", "должен быть больше нуля")
+	}
+	
+	for _, o := range c.CORSOrigins {
+		if o == "*" {
+			if len(c.CORSOrigins) > 1 {
+				add("cors_origins", "* не сочетается с другими origin'ами")
+			}
+			continue
+		}
+		// Origin - это схема, хост и порт; путь или слэш в конце
+		// никогда не совпадут с заголовком Origin браузера
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.User != nil {
+			add("cors_origins", "%q не origin вида https://example.com", o)
+		}
+	}
+	
+	if c.DBPath != "" {
+		if info, err := os.Stat(filepath.Dir(c.DBPath)); err != nil || !info.IsDir() {
+			add("db_path", "каталог %s не существует", filepath.Dir(c.DBPath))
+		}
+	}
+	
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		add("log_format", "%q: нужен text или json", c.LogFormat)
+	}
+	return errors.Join(errs...)
+}
+
+// Server http.Server с адресом и таймаутами из настроек
+func (c Config) Server(h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              c.Addr,
+		Handler:           h,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		ReadTimeout:       c.ReadTimeout,
+		WriteTimeout:      c.WriteTimeout,
+		IdleTimeout:       c.IdleTimeout,
+	}
+}
+
+// Logger журнал в w в формате и с уровнем из настроек
+func (c Config) Logger(w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: c.LogLevel}
+	if c.LogFormat == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// BaseURL адрес для подсказок вида curl localhost:8080/...: без хоста
+// или на всех интерфейсах - localhost
+func (c Config) BaseURL() string {
+	host, port, err := net.SplitHostPort(c.Addr)
+	if err != nil {
+		return "http://" + c.Addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
\ No newline at end of file
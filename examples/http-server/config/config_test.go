@@ -0,0 +1,265 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return path
+}
+
+func load(args []string, env map[string]string) (Config, error) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return Load(fs, args, func(k string) string { return env[k] })
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := load(nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c, Default()) {
+		t.Errorf("Expected defaults %+v, got %+v", Default(), c)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	files := map[string]string{
+		"server.json": `{
+			"addr": ":9000",
+			"read_timeout": "10s",
+			"write_timeout": "15s",
+			"cors_origins": ["https://a.example", "https://b.example"]
+		}`,
+		"server.yaml": `# то же в YAML
+addr: ":9000"
+read_timeout: 10s
+write_timeout: '15s'   # кавычки не обязательны
+cors_origins:
+  - https://a.example
+  - "https://b.example"
+`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := writeFile(t, name, content)
+			env := map[string]string{
+				"HTTP_CONFIG":       path,
+				"HTTP_READ_TIMEOUT": "20s", // Перекрывает файл
+				"HTTP_ADDR":         ":9100",
+			}
+			c, err := load([]string{"-addr", ":9200"}, env) // Флаг перекрывает все
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := Default()
+			expected.Addr = ":9200"
+			expected.ReadTimeout = 20 * time.Second
+			expected.WriteTimeout = 15 * time.Second
+			expected.CORSOrigins = []string{"https://a.example", "https://b.example"}
+			if !reflect.DeepEqual(c, expected) {
+				t.Errorf("Expected %+v, got %+v", expected, c)
+			}
+		})
+	}
+}
+
+// Пример в корне http-server должен оставаться рабочим
+func TestLoad_ExampleFile(t *testing.T) {
+	c, err := load([]string{"-config", "../config.example.yaml"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.DBPath != "users.json" || len(c.CORSOrigins) != 1 {
+		t.Errorf("Unexpected config %+v", c)
+	}
+}
+
+func TestLoad_ConfigFlagOverridesEnv(t *testing.T) {
+	fromEnv := writeFile(t, "env.json", `{"addr": ":1111"}`)
+	fromFlag := writeFile(t, "flag.yml", `addr: ":2222"`)
+	c, err := load([]string{"-config", fromFlag}, map[string]string{"HTTP_CONFIG": fromEnv})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Addr != ":2222" {
+		t.Errorf("Expected :2222, got %s", c.Addr)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string // имя:содержимое
+		args     []string
+		env      map[string]string
+		expected []string // Подстроки ошибки
+	}{
+		{"опечатка в ключе", "c.json:" + `{"read_timout": "5s"}`, nil, nil, []string{`неизвестный ключ "read_timout"`}},
+		{"число вместо длительности", "c.json:" + `{"read_timeout": 5}`, nil, nil, []string{"read_timeout", "missing unit"}},
+		{"не строка", "c.json:" + `{"addr": true}`, nil, nil, []string{"addr", "нужна строка"}},
+		{"вложенный YAML", "c.yaml:server:\n  addr: x\n", nil, nil, []string{"строка 2", "вложенные"}},
+		{"неизвестный формат", "c.toml:addr = 1", nil, nil, []string{`неизвестный формат ".toml"`}},
+		{"нет файла", "", []string{"-config", "/нет/такого.json"}, nil, []string{"/нет/такого.json"}},
+		{"неверная переменная", "", nil, map[string]string{"HTTP_IDLE_TIMEOUT": "минута"}, []string{"HTTP_IDLE_TIMEOUT"}},
+		{"неверный флаг", "", []string{"-shutdown-timeout", "x"}, nil, []string{"shutdown-timeout"}},
+		{"неверный уровень журнала", "", nil, map[string]string{"HTTP_LOG_LEVEL": "громко"}, []string{"HTTP_LOG_LEVEL"}},
+		{"все ошибки проверки сразу", "", []string{"-addr", "8080", "-shutdown-timeout", "0s"}, nil,
+			[]string{"addr", "shutdown_timeout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			if tt.file != "" {
+				name, content, _ := strings.Cut(tt.file, ":")
+				env["HTTP_CONFIG"] = writeFile(t, name, content)
+			}
+			_, err := load(tt.args, env)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			for _, s := range tt.expected {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("Expected error to contain %q, got %v", s, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		valid  bool
+	}{
+		{"по умолчанию", func(c *Config) {}, true},
+		{"хост и порт", func(c *Config) { c.Addr = "127.0.0.1:0" }, true},
+		{"без порта", func(c *Config) { c.Addr = "localhost" }, false},
+		{"порт вне диапазона", func(c *Config) { c.Addr = ":70000" }, false},
+		{"без ReadHeaderTimeout", func(c *Config) { c.ReadHeaderTimeout = 0 }, false},
+		{"отрицательный таймаут", func(c *Config) { c.IdleTimeout = -time.Second }, false},
+		{"origin'ы", func(c *Config) { c.CORSOrigins = []string{"https://a.example", "http://localhost:3000"} }, true},
+		{"без CORS", func(c *Config) { c.CORSOrigins = nil }, true},
+		{"origin со слэшем", func(c *Config) { c.CORSOrigins = []string{"https://a.example/"} }, false},
+		{"origin без схемы", func(c *Config) { c.CORSOrigins = []string{"a.example"} }, false},
+		{"* вместе с другими", func(c *Config) { c.CORSOrigins = []string{"*", "https://a.example"} }, false},
+		{"база в существующем каталоге", func(c *Config) { c.DBPath = filepath.Join(os.TempDir(), "users.json") }, true},
+		{"база в несуществующем каталоге", func(c *Config) { c.DBPath = "/нет/такого/users.json" }, false},
+		{"журнал в JSON", func(c *Config) { c.LogFormat = "json" }, true},
+		{"неизвестный формат журнала", func(c *Config) { c.LogFormat = "xml" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.modify(&c)
+			err := c.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]any
+		err      bool
+	}{
+		{"скаляры и комментарии", "# шапка\na: 1\nb: \"x # не комментарий\"  # комментарий\nc: 'it''s'\nd: url#frag\n",
+			map[string]any{"a": "1", "b": "x # не комментарий", "c": "it's", "d": "url#frag"}, false},
+		{"список в строку", `l: [a, "b,c", 'd']`, map[string]any{"l": []any{"a", "b,c", "d"}}, false},
+		{"пустой список", "l: []\nm:\n", map[string]any{"l": []any{}, "m": []any{}}, false},
+		{"список блоком", "l:\n  - a\n  - \"b\"\nk: v\n", map[string]any{"l": []any{"a", "b"}, "k": "v"}, false},
+		{"CRLF", "a: 1\r\nb: 2\r\n", map[string]any{"a": "1", "b": "2"}, false},
+		{"повтор ключа", "a: 1\na: 2\n", nil, true},
+		{"вложенный объект", "a:\n  b: 1\n", nil, true},
+		{"отступ без списка", "  - a\n", nil, true},
+		{"нет двоеточия", "просто текст\n", nil, true},
+		{"незакрытый список", "l: [a, b\n", nil, true},
+		{"якорь", "a: &x 1\n", nil, true},
+		{"многострочная строка", "a: |\n  текст\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML([]byte(tt.input))
+			if tt.err {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := map[string]string{
+		":8080":          "http://localhost:8080",
+		"0.0.0.0:80":     "http://localhost:80",
+		"127.0.0.1:9000": "http://127.0.0.1:9000",
+		"[::1]:8080":     "http://[::1]:8080",
+	}
+	for addr, expected := range tests {
+		c := Config{Addr: addr}
+		if got := c.BaseURL(); got != expected {
+			t.Errorf("%s: expected %s, got %s", addr, expected, got)
+		}
+	}
+}
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		format   string
+		level    string
+		expected string
+	}{
+		{"text", "info", "level=WARN msg=видно"},
+		{"json", "warn", `"level":"WARN","msg":"видно"`},
+		{"json", "debug", `"level":"DEBUG","msg":"отладка"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.level, func(t *testing.T) {
+			c, err := load([]string{"-log-format", tt.format, "-log-level", tt.level}, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var buf strings.Builder
+			logger := c.Logger(&buf)
+			logger.Debug("отладка")
+			logger.Warn("видно")
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Expected %q in %q", tt.expected, buf.String())
+			}
+			if tt.level != "debug" && strings.Contains(buf.String(), "отладка") {
+				t.Errorf("Expected debug to be filtered at %s, got %q", tt.level, buf.String())
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML разбирает подмножество YAML, которого хватает плоскому
+// файлу настроек:
+//
+//	# комментарий
+//	addr: ":8080"
+//	read_timeout: 30s          # комментарий в конце строки
+//	cors_origins: [https://a.example, "https://b.example"]
+//	cors_origins:
+//	  - https://a.example
+//
+// Значения - строки или списки строк, как после JSON. Вложенные
+// объекты, многострочные строки, якоря и прочее - ошибка с номером
+// строки, а не тихий неверный разбор. Полный YAML - это
+// gopkg.in/yaml.v3; пример обходится без внешних зависимостей.
+func parseYAML(data []byte) (map[string]any, error) {
+	values := make(map[string]any)
+	var listKey string // Ключ, под которым собираются "- item"
+	for i, line := range strings.Split(string(data), "\n") {
+		n := i + 1
+		line = strings.TrimRight(stripComment(line), " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		
+		if indented := line[0] == ' ' || line[0] == '\t'; indented {
+			item, ok := strings.CutPrefix(strings.TrimLeft(line, " \t"), "- ")
+			if !ok || listKey == "" {
+				return nil, fmt.Errorf("строка %d: вложенные значения не поддерживаются", n)
+			}
+			v, err := scalar(item)
+			if err != nil {
+				return nil, fmt.Errorf("строка %d: %w", n, err)
+			}
+			values[listKey] = append(values[listKey].([]any), v)
+			continue
+		}
+		listKey = ""
+		
+		key, rest, ok := strings.Cut(line, ":")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"'") {
+			return nil, fmt.Errorf("строка %d: нужна строка вида ключ: значение", n)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("строка %d: ключ %q повторяется", n, key)
+		}
+		rest = strings.TrimSpace(rest)
+		switch {
+		case rest == "":
+			// Значение - список на следующих строках
+			values[key] = []any{}
+			listKey = key
+		case rest[0] == '[':
+			list, err := flowList(rest)
+			if err != nil {
+				return nil, fmt.Errorf("строка %d: %w", n, err)
+			}
+			values[key] = list
+		default:
+			v, err := scalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("строка %d: %w", n, err)
+			}
+			values[key] = v
+		}
+	}
+	return values, nil
+}
+
+// stripComment отрезает # до конца строки, если он не внутри кавычек;
+// как в YAML, комментарий начинается с # в начале строки или после пробела
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// scalar строка без кавычек, в "двойных" (с экранированием, как в Go) или
+// в 'одинарных' (” внутри - это одна ')
+func scalar(s string) (string, error) {
+	switch {
+	case s == "":
+		return "", nil
+	case s[0] == '"':
+		return strconv.Unquote(s)
+	case s[0] == '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return "", errors.New("незакрытая кавычка")
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.ContainsAny(s[:1], "{[&*!|>%@`"):
+		return "", fmt.Errorf("значение %q не поддерживается", s)
+	}
+	return s, nil
+}
+
+// flowList список в квадратных скобках: [a, "b", 'c']
+func flowList(s string) ([]any, error) {
+	if s[len(s)-1] != ']' {
+		return nil, errors.New("список должен закрываться ] на той же строке")
+	}
+	list := []any{}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return list, nil
+	}
+	for _, item := range splitOutsideQuotes(inner) {
+		v, err := scalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// splitOutsideQuotes делит по запятым, кроме запятых внутри кавычек
+func splitOutsideQuotes(s string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
\ No newline at end of file
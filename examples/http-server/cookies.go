@@ -0,0 +1,257 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"clock"
+)
+
+// Куки: установка, чтение и удаление с атрибутами безопасности и
+// подписанные куки.
+//
+// Атрибуты, которые стоит выставлять осознанно:
+//   - HttpOnly - кука не видна document.cookie: XSS не украдет ее.
+//     Не ставят только на куки, которые читает сам фронтенд (тема);
+//   - Secure - кука уходит только по HTTPS;
+//   - SameSite - посылать ли куку с запросами, начатыми на чужом сайте:
+//     Strict - никогда, Lax - только при переходе по ссылке (GET),
+//     None - всегда (и только вместе с Secure);
+//   - Path и Domain - где кука действует. Удалить куку можно только с
+//     теми же Path и Domain, с которыми ее выставили;
+//   - MaxAge/Expires - без них кука живет до закрытия браузера.
+//
+// Значение куки приходит от клиента и может быть любым. Подпись HMAC
+// (signedCookies) не прячет значение, но позволяет заметить подмену:
+// клиент не может сменить тариф "free" на "pro", не зная секрета. Если
+// значение надо скрыть - его шифруют (AES-GCM) или хранят на сервере,
+// как сессии в session.go.
+
+var (
+	errCookieTampered = errors.New("cookie signature mismatch")
+	errCookieExpired  = errors.New("cookie expired")
+)
+
+// signedCookies подписывает и проверяет значения кук:
+//
+//	base64url(значение) . срок (unix) . base64url(HMAC-SHA256)
+//
+// В подпись входит и имя куки, иначе подписанное значение одной куки
+// можно было бы переложить в другую. Срок тоже подписан: кука с
+// истекшим MaxAge браузером удаляется, но клиент может прислать ее
+// вручную - сервер проверяет срок сам.
+type signedCookies struct {
+	// keys ключи подписи: первым подписываются новые куки, проверка
+	// принимает любой. Для смены секрета новый ставят первым, а старый
+	// убирают, когда истекут подписанные им куки.
+	keys  [][]byte
+	clock clock.Clock
+}
+
+func newSignedCookies(clk clock.Clock, keys ...[]byte) *signedCookies {
+	return &signedCookies{keys: keys, clock: clk}
+}
+
+// sign подписанное значение куки name, действительное до expires
+func (s *signedCookies) sign(name, value string, expires time.Time) string {
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(value)) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return unsigned + "." + enc.EncodeToString(cookieMAC(s.keys[0], name, unsigned))
+}
+
+// verify проверяет подпись и срок и возвращает исходное значение
+func (s *signedCookies) verify(name, signed string) (string, error) {
+	unsigned, sig, ok := cutLast(signed, ".")
+	if !ok {
+		return "", errCookieTampered
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", errCookieTampered
+	}
+	valid := false
+	for _, key := range s.keys {
+		// hmac.Equal - за постоянное время: по времени ответа нельзя
+		// подбирать подпись байт за байтом
+		if hmac.Equal(mac, cookieMAC(key, name, unsigned)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return "", errCookieTampered
+	}
+	
+	encoded, exp, _ := strings.Cut(unsigned, ".")
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return "", errCookieTampered
+	}
+	if !s.clock.Now().Before(time.Unix(expires, 0)) {
+		return "", errCookieExpired
+	}
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errCookieTampered
+	}
+	return string(value), nil
+}
+
+func cookieMAC(key []byte, name, unsigned string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0}) // Разделитель: без него имя "a" со значением "b..." подписывалось бы как имя "ab"
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// cookieSpec атрибуты демонстрационной куки
+type cookieSpec struct {
+	httpOnly bool
+	sameSite http.SameSite
+	maxAge   time.Duration
+	signed   bool
+}
+
+// demoCookies куки, которые можно выставить через /cookies/{name}.
+// Произвольные имена не принимаются: иначе клиент выставил бы себе
+// куку сессии или любую другую, которой доверяет сервер.
+var demoCookies = map[string]cookieSpec{
+	// Тему читает JavaScript, секрета в ней нет
+	"theme": {httpOnly: false, sameSite: http.SameSiteLaxMode, maxAge: 365 * 24 * time.Hour},
+	// Тариф определяет доступ - сервер должен заметить подмену
+	"plan": {httpOnly: true, sameSite: http.SameSiteLaxMode, maxAge: 30 * 24 * time.Hour, signed: true},
+	// Strict: не уходит даже при переходе по ссылке с другого сайта
+	"consent": {httpOnly: true, sameSite: http.SameSiteStrictMode, maxAge: 180 * 24 * time.Hour},
+}
+
+// cookieHandlers обработчики /cookies
+type cookieHandlers struct {
+	signer *signedCookies
+	secure bool // Secure-атрибут; выключают для http://localhost
+}
+
+func (h *cookieHandlers) cookie(name string, spec cookieSpec) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Path:     "/",
+		HttpOnly: spec.httpOnly,
+		Secure:   h.secure,
+		SameSite: spec.sameSite,
+	}
+}
+
+// validCookieValue значение без символов, которые запрещены в куке:
+// net/http молча выбросил бы их, и клиент получил бы не то, что прислал
+func validCookieValue(v string) bool {
+	if v == "" || len(v) > 256 {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c < 0x21 || c > 0x7E || c == '"' || c == ',' || c == ';' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// Установка: POST /cookies/{name} (форма: value=...)
+func (h *cookieHandlers) set(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	spec, ok := demoCookies[name]
+	if !ok {
+		http.Error(w, "Неизвестная кука", http.StatusNotFound)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<10)
+	value := r.FormValue("value")
+	if !validCookieValue(value) {
+		http.Error(w, "value: от 1 до 256 печатных ASCII-символов без пробелов, кавычек, запятых и ;", http.StatusBadRequest)
+		return
+	}
+	
+	c := h.cookie(name, spec)
+	expires := h.signer.clock.Now().Add(spec.maxAge)
+	c.Value = value
+	if spec.signed {
+		c.Value = h.signer.sign(name, value, expires)
+	}
+	c.MaxAge = int(spec.maxAge.Seconds())
+	c.Expires = expires // Для старых браузеров без Max-Age
+	http.SetCookie(w, c)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cookieValue значение куки в ответе GET /cookies
+type cookieValue struct {
+	Value  string `json:"value,omitempty"`
+	Signed bool   `json:"signed,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Чтение: GET /cookies - демонстрационные куки запроса; подписанные
+// проверяются
+func (h *cookieHandlers) list(w http.ResponseWriter, r *http.Request) {
+	result := make(map[string]cookieValue)
+	for name, spec := range demoCookies {
+		c, err := r.Cookie(name)
+		if err != nil {
+			continue // http.ErrNoCookie
+		}
+		if !spec.signed {
+			result[name] = cookieValue{Value: c.Value}
+			continue
+		}
+		value, err := h.signer.verify(name, c.Value)
+		if err != nil {
+			// Подмененной куке не доверяют, а ее значение не
+			// возвращают: это ввод, который мы не выпускали
+			result[name] = cookieValue{Signed: true, Error: err.Error()}
+			continue
+		}
+		result[name] = cookieValue{Value: value, Signed: true}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Удаление: DELETE /cookies/{name}. Браузер удаляет куку, получив ее
+// с MaxAge<0; имя, Path и Domain должны совпасть с установленной, иначе
+// это другая кука.
+func (h *cookieHandlers) delete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	spec, ok := demoCookies[name]
+	if !ok {
+		http.Error(w, "Неизвестная кука", http.StatusNotFound)
+		return
+	}
+	c := h.cookie(name, spec)
+	c.MaxAge = -1
+	c.Expires = time.Unix(1, 0)
+	http.SetCookie(w, c)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cookieRoutes маршруты /cookies
+func cookieRoutes(h *cookieHandlers) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /cookies", h.list)
+	mux.HandleFunc("POST /cookies/{name}", h.set)
+	mux.HandleFunc("DELETE /cookies/{name}", h.delete)
+	return mux
+}
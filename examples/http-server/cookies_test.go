@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"clock"
+)
+
+func TestSignedCookies_RoundTrip(t *testing.T) {
+	s := newSignedCookies(clock.NewRealClock(), []byte("secret-1"))
+	signed := s.sign("plan", "free", time.Now().Add(time.Hour))
+	value, err := s.verify("plan", signed)
+	if err != nil || value != "free" {
+		t.Errorf("Expected free, got %q, %v", value, err)
+	}
+}
+
+func TestSignedCookies_Tampering(t *testing.T) {
+	s := newSignedCookies(clock.NewRealClock(), []byte("secret-1"))
+	expires := time.Now().Add(time.Hour)
+	signed := s.sign("plan", "free", expires)
+	parts := strings.Split(signed, ".")
+	enc := base64.RawURLEncoding
+	
+	tests := []struct {
+		name   string
+		cookie string
+		value  string
+	}{
+		{"value replaced", "plan", enc.EncodeToString([]byte("pro")) + "." + parts[1] + "." + parts[2]},
+		{"expiry extended", "plan", parts[0] + ".9999999999." + parts[2]},
+		{"signature changed", "plan", parts[0] + "." + parts[1] + "." + enc.EncodeToString(make([]byte, 32))},
+		{"moved to other cookie", "theme", signed},
+		{"not signed", "plan", "free"},
+		{"bad base64", "plan", parts[0] + "." + parts[1] + ".!!!"},
+		{"other key", "plan", newSignedCookies(clock.NewRealClock(), []byte("secret-2")).sign("plan", "pro", expires)},
+	}
+	for _, tt := range tests {
+		if _, err := s.verify(tt.cookie, tt.value); err != errCookieTampered {
+			t.Errorf("%s: expected errCookieTampered, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestSignedCookies_Expired(t *testing.T) {
+	clk := clock.NewFakeClock(time.Now())
+	s := newSignedCookies(clk, []byte("secret-1"))
+	signed := s.sign("plan", "free", clk.Now().Add(time.Hour))
+	clk.Advance(2 * time.Hour)
+	if _, err := s.verify("plan", signed); err != errCookieExpired {
+		t.Errorf("Expected errCookieExpired, got %v", err)
+	}
+}
+
+func TestSignedCookies_KeyRotation(t *testing.T) {
+	old := newSignedCookies(clock.NewRealClock(), []byte("old"))
+	signed := old.sign("plan", "free", time.Now().Add(time.Hour))
+	
+	rotated := newSignedCookies(clock.NewRealClock(), []byte("new"), []byte("old"))
+	if value, err := rotated.verify("plan", signed); err != nil || value != "free" {
+		t.Errorf("Expected old cookie accepted after rotation, got %q, %v", value, err)
+	}
+	// Новые куки подписываются новым ключом
+	if _, err := old.verify("plan", rotated.sign("plan", "free", time.Now().Add(time.Hour))); err != errCookieTampered {
+		t.Errorf("Expected new cookie signed with new key, got %v", err)
+	}
+}
+
+func testCookieRoutes() http.Handler {
+	return cookieRoutes(&cookieHandlers{signer: newSignedCookies(clock.NewRealClock(), []byte("secret")), secure: true})
+}
+
+func TestCookieHandlers_SetAttributes(t *testing.T) {
+	h := testCookieRoutes()
+	tests := []struct {
+		name     string
+		httpOnly bool
+		sameSite http.SameSite
+	}{
+		{"theme", false, http.SameSiteLaxMode},
+		{"plan", true, http.SameSiteLaxMode},
+		{"consent", true, http.SameSiteStrictMode},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cookies/"+tt.name+"?value=v1", nil))
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected 204, got %d", tt.name, rec.Code)
+		}
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("%s: expected 1 cookie, got %d", tt.name, len(cookies))
+		}
+		c := cookies[0]
+		if c.HttpOnly != tt.httpOnly || c.SameSite != tt.sameSite || !c.Secure || c.Path != "/" || c.MaxAge <= 0 {
+			t.Errorf("%s: unexpected attributes %+v", tt.name, c)
+		}
+		if signed := demoCookies[tt.name].signed; signed == (c.Value == "v1") {
+			t.Errorf("%s: signed=%v, got value %q", tt.name, signed, c.Value)
+		}
+	}
+}
+
+func TestCookieHandlers_SetRejects(t *testing.T) {
+	h := testCookieRoutes()
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/cookies/session?value=x", http.StatusNotFound},
+		{"/cookies/theme?value=", http.StatusBadRequest},
+		{"/cookies/theme?value=a%3Bb", http.StatusBadRequest},
+		{"/cookies/theme?value=a%20b", http.StatusBadRequest},
+		{"/cookies/theme?value=" + strings.Repeat("x", 257), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+		if rec.Code != tt.expected {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.expected, rec.Code)
+		}
+	}
+}
+
+func TestCookieHandlers_List(t *testing.T) {
+	h := testCookieRoutes()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cookies/plan?value=free", nil))
+	plan := rec.Result().Cookies()[0]
+	
+	req := httptest.NewRequest(http.MethodGet, "/cookies", nil)
+	req.AddCookie(plan)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "other", Value: "x"})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	
+	var got map[string]cookieValue
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 2 || got["plan"] != (cookieValue{Value: "free", Signed: true}) || got["theme"].Value != "dark" {
+		t.Errorf("Unexpected cookies: %+v", got)
+	}
+	
+	// Подмененное значение не возвращается
+	req = httptest.NewRequest(http.MethodGet, "/cookies", nil)
+	req.AddCookie(&http.Cookie{Name: "plan", Value: "cHJv" + plan.Value[strings.Index(plan.Value, "."):]})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	got = nil
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got["plan"].Value != "" || got["plan"].Error == "" {
+		t.Errorf("Expected tampered plan rejected, got %+v", got["plan"])
+	}
+}
+
+func TestCookieHandlers_Delete(t *testing.T) {
+	h := testCookieRoutes()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/cookies/plan", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", rec.Code)
+	}
+	c := rec.Result().Cookies()[0]
+	if c.Name != "plan" || c.MaxAge >= 0 || c.Path != "/" || !c.HttpOnly {
+		t.Errorf("Expected expired plan cookie with same attributes, got %+v", c)
+	}
+	
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/cookies/unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
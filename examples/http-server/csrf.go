@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+)
+
+// Защита от CSRF для HTML-форм. Браузер прикладывает куки к POST на наш
+// сайт, даже если форму отправила страница злоумышленника, - и без
+// защиты чужой сайт добавил бы пользователя от имени жертвы.
+//
+// Токен синхронизатора: у каждого браузера своя CSRF-сессия - случайный
+// ID в куке, а токен - HMAC от этого ID. Токен вставляется в форму
+// скрытым полем, и POST без совпадающего токена получает 403. Чужая
+// страница может заставить браузер отправить куку, но прочитать ни
+// куку, ни нашу страницу с токеном не может.
+//
+// Токен вычисляется, а не хранится: нет записи на сервере на каждого
+// посетителя, и любая копия сервиса с тем же секретом проверит его.
+// SameSite=Lax (см. session.go) тоже защищает от CSRF, но не в старых
+// браузерах и не от соседних поддоменов - токен нужен в дополнение.
+
+// csrfField имя скрытого поля формы; для fetch и JSON - заголовок
+// csrfHeader
+const (
+	csrfField  = "csrf_token"
+	csrfHeader = "X-CSRF-Token"
+)
+
+const csrfTokenKey contextKey = "csrfToken"
+
+// csrfTokenFrom токен текущей CSRF-сессии для вставки в форму; есть
+// только за csrfMiddleware
+func csrfTokenFrom(r *http.Request) string {
+	token, _ := r.Context().Value(csrfTokenKey).(string)
+	return token
+}
+
+// csrfProtection выдает и проверяет CSRF-токены
+type csrfProtection struct {
+	secret []byte
+	secure bool // Кука только по HTTPS; выключают для localhost
+}
+
+func newCSRFProtection(secret []byte, secure bool) *csrfProtection {
+	return &csrfProtection{secret: secret, secure: secure}
+}
+
+// cookieName с префиксом __Host-, как у куки сессии: поддомен не
+// подменит CSRF-сессию своей, для которой знает токен
+func (c *csrfProtection) cookieName() string {
+	if c.secure {
+		return "__Host-csrf"
+	}
+	return "csrf"
+}
+
+// token токен CSRF-сессии id
+func (c *csrfProtection) token(id string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte("csrf:"))
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// valid совпадает ли присланный токен с токеном сессии. Сравнение за
+// постоянное время: по времени ответа токен не подобрать.
+func (c *csrfProtection) valid(id, token string) bool {
+	return token != "" && hmac.Equal([]byte(token), []byte(c.token(id)))
+}
+
+// safeMethod методы без побочных эффектов не проверяются: GET должен
+// открываться по ссылке с любого сайта. Поэтому GET и не должен ничего
+// менять.
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// csrfMiddleware выдает CSRF-сессию, если ее нет, кладет токен в
+// контекст для шаблона, а на POST и прочих изменяющих запросах сверяет
+// токен из поля csrf_token или заголовка X-CSRF-Token. Несовпадение -
+// 403.
+func csrfMiddleware(c *csrfProtection, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var id string
+		// 43 символа - длина ID из newSessionID (32 байта в base64url)
+		if cookie, err := r.Cookie(c.cookieName()); err == nil && len(cookie.Value) == 43 {
+			id = cookie.Value
+		} else {
+			// Новая сессия. POST с ней все равно отклоняется ниже:
+			// токена для только что созданного ID у клиента быть не может.
+			var err error
+			if id, err = newSessionID(); err != nil {
+				http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+				return
+			}
+			// Без MaxAge: сессионная кука живет до закрытия браузера,
+			// открытая форма работает, пока открыта вкладка
+			http.SetCookie(w, &http.Cookie{
+				Name:     c.cookieName(),
+				Value:    id,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   c.secure,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		
+		if !safeMethod(r.Method) {
+			token := r.Header.Get(csrfHeader)
+			if token == "" {
+				r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+				token = r.PostFormValue(csrfField)
+			}
+			if !c.valid(id, token) {
+				http.Error(w, "Неверный CSRF-токен: обновите страницу с формой и отправьте ее снова", http.StatusForbidden)
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), csrfTokenKey, c.token(id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
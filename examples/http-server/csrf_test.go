@@ -0,0 +1,120 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// csrfForm GET /form через middleware: кука CSRF-сессии и токен из
+// скрытого поля страницы
+func csrfForm(t *testing.T, h http.Handler) (*http.Cookie, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/form", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected CSRF cookie, got %d cookies", len(cookies))
+	}
+	_, rest, ok := strings.Cut(rec.Body.String(), `name="csrf_token" value="`)
+	token, _, _ := strings.Cut(rest, `"`)
+	if !ok || token == "" {
+		t.Fatalf("Expected token in form, got %s", rec.Body.String())
+	}
+	return cookies[0], token
+}
+
+func csrfPost(h http.Handler, cookie *http.Cookie, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCSRF_FormSubmit(t *testing.T) {
+	withUsers(t)
+	csrf := newCSRFProtection([]byte("secret"), true)
+	get := csrfMiddleware(csrf, http.HandlerFunc(userFormPage))
+	post := csrfMiddleware(csrf, http.HandlerFunc(userFormSubmit))
+	
+	cookie, token := csrfForm(t, get)
+	if cookie.Name != "__Host-csrf" || !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Unexpected cookie attributes: %+v", cookie)
+	}
+	_, otherToken := csrfForm(t, get)
+	
+	tests := []struct {
+		name     string
+		cookie   *http.Cookie
+		token    string
+		expected int
+	}{
+		{"no session", nil, token, http.StatusForbidden},
+		{"no token", cookie, "", http.StatusForbidden},
+		{"forged token", cookie, "forged", http.StatusForbidden},
+		{"token of other session", cookie, otherToken, http.StatusForbidden},
+		{"valid", cookie, token, http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		form := url.Values{"name": {"Анна"}, "email": {"anna@example.com"}}
+		if tt.token != "" {
+			form.Set(csrfField, tt.token)
+		}
+		if rec := csrfPost(post, tt.cookie, form); rec.Code != tt.expected {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, rec.Code)
+		}
+	}
+	if n := users.Len(); n != 1 {
+		t.Errorf("Expected only valid submit to create user, got %d users", n)
+	}
+}
+
+func TestCSRF_ErrorPageKeepsToken(t *testing.T) {
+	withUsers(t)
+	csrf := newCSRFProtection([]byte("secret"), false)
+	cookie, token := csrfForm(t, csrfMiddleware(csrf, http.HandlerFunc(userFormPage)))
+	
+	// Форма с ошибкой показывается снова - с тем же токеном, иначе
+	// исправленную форму нельзя было бы отправить
+	rec := csrfPost(csrfMiddleware(csrf, http.HandlerFunc(userFormSubmit)), cookie,
+		url.Values{csrfField: {token}, "name": {"Анна"}, "email": {"нет"}})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `value="`+token+`"`) {
+		t.Error("Expected re-rendered form to contain CSRF token")
+	}
+}
+
+func TestCSRF_Header(t *testing.T) {
+	csrf := newCSRFProtection([]byte("secret"), false)
+	h := csrfMiddleware(csrf, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	id, _ := newSessionID()
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "csrf", Value: id})
+	req.Header.Set(csrfHeader, csrf.token(id))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", rec.Code)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("Expected existing CSRF session to be kept")
+	}
+	
+	// Токен, подписанный другим секретом, не подходит
+	req.Header.Set(csrfHeader, newCSRFProtection([]byte("other"), false).token(id))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,118 @@
+// Package cursor - непрозрачные курсоры для постраничной выдачи:
+//
+//	codec := cursor.New(secret, time.Hour)
+//	next, _ := codec.Encode(userKey{Name: last.Name, ID: last.ID})
+//	...
+//	var after userKey
+//	err := codec.Decode(r.URL.Query().Get("cursor"), &after)
+//
+// Курсор хранит значения ключа последней выданной записи (keyset
+// pagination): следующая страница - записи строго после этого ключа.
+// В отличие от OFFSET, вставка или удаление записи выше по списку не
+// сдвигает страницы, а база не перебирает пропущенные строки.
+//
+// Формат тот же, что у JWT без заголовка:
+//
+//	base64url(JSON с ключом и сроком) . base64url(HMAC-SHA256)
+//
+// Курсор не зашифрован: ключ в нем читается. Подпись нужна, чтобы клиент
+// не подделал курсор и не заставил сервер искать по произвольному ключу
+// или с чужими фильтрами. Срок ограничивает жизнь сохраненных ссылок:
+// после изменения сортировки или формата ключа старые курсоры должны
+// перестать приниматься, а не вести в неожиданное место.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalid курсор поврежден, подделан или выпущен с другим секретом
+	ErrInvalid = errors.New("cursor: invalid")
+	// ErrExpired срок курсора истек; клиенту стоит начать с первой страницы
+	ErrExpired = errors.New("cursor: expired")
+)
+
+// Codec выпускает и проверяет курсоры. Безопасен для конкурентного
+// использования.
+type Codec struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// New создает Codec. ttl - срок жизни курсора; 0 - без срока.
+func New(secret []byte, ttl time.Duration) *Codec {
+	return &Codec{secret: secret, ttl: ttl, now: time.Now}
+}
+
+// payload содержимое курсора
+type payload struct {
+	Key       json.RawMessage `json:"k"`
+	ExpiresAt int64           `json:"exp,omitempty"`
+}
+
+// Encode кодирует значения ключа key (обычно структуру) в курсор
+func (c *Codec) Encode(key any) (string, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	p := payload{Key: raw}
+	if c.ttl > 0 {
+		p.ExpiresAt = c.now().Add(c.ttl).Unix()
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	body := enc.EncodeToString(data)
+	return body + "." + enc.EncodeToString(c.mac(body)), nil
+}
+
+// Decode проверяет подпись и срок курсора и раскладывает ключ в key
+func (c *Codec) Decode(cursor string, key any) error {
+	body, sig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return ErrInvalid
+	}
+	enc := base64.RawURLEncoding
+	mac, err := enc.DecodeString(sig)
+	if err != nil {
+		return ErrInvalid
+	}
+	// Подпись проверяется первой и за постоянное время: до этого
+	// содержимое курсора - непроверенный ввод
+	if !hmac.Equal(mac, c.mac(body)) {
+		return ErrInvalid
+	}
+	data, err := enc.DecodeString(body)
+	if err != nil {
+		return ErrInvalid
+	}
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return ErrInvalid
+	}
+	// Курсор без срока при включенном ttl выпущен до его включения
+	if c.ttl > 0 && (p.ExpiresAt == 0 || !c.now().Before(time.Unix(p.ExpiresAt, 0))) {
+		return ErrExpired
+	}
+	if err := json.Unmarshal(p.Key, key); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func (c *Codec) mac(body string) []byte {
+	m := hmac.New(sha256.New, c.secret)
+	m.Write([]byte(body))
+	return m.Sum(nil)
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type userKey struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	c := New([]byte("secret"), time.Hour)
+	s, err := c.Encode(userKey{Name: "Иван Иванов", ID: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Курсор идет в query без экранирования
+	if strings.ContainsAny(s, "+/=?&") {
+		t.Errorf("Expected URL-safe cursor, got %s", s)
+	}
+	var got userKey
+	if err := c.Decode(s, &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != (userKey{Name: "Иван Иванов", ID: 7}) {
+		t.Errorf("Expected {Иван Иванов 7}, got %+v", got)
+	}
+}
+
+func TestCodec_Tampering(t *testing.T) {
+	c := New([]byte("secret"), time.Hour)
+	s, _ := c.Encode(userKey{Name: "a", ID: 1})
+	body, sig, _ := strings.Cut(s, ".")
+	
+	// Подменяем ключ, сохранив подпись
+	forgedBody := strings.Replace(mustDecode(t, body), `"id":1`, `"id":999`, 1)
+	forged := base64.RawURLEncoding.EncodeToString([]byte(forgedBody)) + "." + sig
+	
+	other, _ := New([]byte("other"), time.Hour).Encode(userKey{Name: "a", ID: 1})
+	
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"пустой", ""},
+		{"без подписи", body},
+		{"подмененный ключ", forged},
+		{"чужой секрет", other},
+		{"подпись не base64", body + ".!!!"},
+		{"обрезанная подпись", s[:len(s)-2]},
+		{"мусор", "abc.def"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got userKey
+			if err := c.Decode(tt.cursor, &got); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCodec_WrongKeyType(t *testing.T) {
+	c := New([]byte("secret"), 0)
+	s, _ := c.Encode("строка вместо структуры")
+	var got userKey
+	if err := c.Decode(s, &got); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestCodec_Expiry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := New([]byte("secret"), time.Hour)
+	c.now = func() time.Time { return now }
+	s, _ := c.Encode(userKey{ID: 1})
+	
+	tests := []struct {
+		name     string
+		after    time.Duration
+		expected error
+	}{
+		{"свежий", time.Minute, nil},
+		{"на границе", time.Hour, ErrExpired},
+		{"истекший", 2 * time.Hour, ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.now = func() time.Time { return now.Add(tt.after) }
+			var got userKey
+			if err := c.Decode(s, &got); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestCodec_NoTTL(t *testing.T) {
+	noTTL := New([]byte("secret"), 0)
+	s, _ := noTTL.Encode(userKey{ID: 1})
+	
+	noTTL.now = func() time.Time { return time.Now().Add(10 * 365 * 24 * time.Hour) }
+	var got userKey
+	if err := noTTL.Decode(s, &got); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	
+	// Бессрочный курсор не принимается, когда срок включили
+	withTTL := New([]byte("secret"), time.Hour)
+	if err := withTTL.Decode(s, &got); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+}
+
+func mustDecode(t *testing.T, s string) string {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(b)
+}
\ No newline at end of file
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server-Sent Events: GET /api/events - поток событий от сервера к
+// клиенту поверх обычного HTTP-ответа, который не заканчивается.
+// Браузерный EventSource сам переподключается после обрыва и присылает
+// заголовок Last-Event-ID с номером последнего полученного события -
+// сервер досылает пропущенное из буфера последних событий.
+//
+// Тот же прием, но для графиков метрик, - в examples/dashboard.
+
+// event одно событие потока
+type event struct {
+	ID   uint64
+	Type string
+	Data []byte // JSON
+}
+
+// eventBroker раздает события всем подписчикам (fan-out) и хранит
+// последние size событий для досылки после переподключения
+type eventBroker struct {
+	mu     sync.Mutex
+	nextID uint64
+	recent []event // Последние события по возрастанию ID
+	size   int
+	subs   map[chan event]struct{}
+}
+
+func newEventBroker(size int) *eventBroker {
+	return &eventBroker{nextID: 1, size: size, subs: make(map[chan event]struct{})}
+}
+
+// publish рассылает событие. Подписчик, который не успевает читать,
+// отключается, а не тормозит остальных: его клиент переподключится и
+// получит пропущенное по Last-Event-ID.
+func (b *eventBroker) publish(typ string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e := event{ID: b.nextID, Type: typ, Data: data}
+	b.nextID++
+	b.recent = append(b.recent, e)
+	if len(b.recent) > b.size {
+		b.recent = b.recent[len(b.recent)-b.size:]
+	}
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// subscribe подписывает на события после lastID и возвращает уже
+// пропущенные; lastID 0 - только новые события. ok=false, если часть
+// пропущенного вытеснена из буфера: досылать уже нечего, клиенту нужно
+// перечитать состояние целиком.
+func (b *eventBroker) subscribe(lastID uint64) (missed []event, ch chan event, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ok = true
+	if lastID > 0 {
+		ok = len(b.recent) == 0 || lastID+1 >= b.recent[0].ID
+		for _, e := range b.recent {
+			if e.ID > lastID {
+				missed = append(missed, e)
+			}
+		}
+	}
+	ch = make(chan event, 16)
+	b.subs[ch] = struct{}{}
+	return missed, ch, ok
+}
+
+func (b *eventBroker) unsubscribe(ch chan event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// close отключает всех подписчиков: их обработчики завершаются, и
+// server.Shutdown не ждет бесконечных потоков. Клиенты переподключатся
+// к новому экземпляру сервера с Last-Event-ID.
+func (b *eventBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// subscribers число подключенных клиентов
+func (b *eventBroker) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// writeSSE пишет событие в формате text/event-stream: поля id, event и
+// data, пустая строка - конец события
+func writeSSE(w http.ResponseWriter, e event) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+	return err
+}
+
+// eventsHeartbeat как часто слать комментарий: прокси закрывают
+// соединения, по которым долго ничего не идет
+const eventsHeartbeat = 15 * time.Second
+
+// eventsHandler GET /api/events
+func eventsHandler(b *eventBroker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Потоковая передача не поддерживается", http.StatusInternalServerError)
+			return
+		}
+		
+		// Last-Event-ID шлет EventSource при переподключении; без него
+		// клиент получает только новые события
+		var lastID uint64
+		if s := r.Header.Get("Last-Event-ID"); s != "" {
+			id, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				http.Error(w, "Неверный Last-Event-ID", http.StatusBadRequest)
+				return
+			}
+			lastID = id
+		}
+		missed, ch, complete := b.subscribe(lastID)
+		defer b.unsubscribe(ch)
+		
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		// retry - пауза перед переподключением для EventSource, мс
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if !complete {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		for _, e := range missed {
+			if err := writeSSE(w, e); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		
+		heartbeat := time.NewTicker(eventsHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				// Клиент закрыл соединение. Shutdown контекст запроса
+				// не отменяет - об остановке сообщает закрытый ch
+				return
+			case e, ok := <-ch:
+				if !ok {
+					return // Отстал или сервер останавливается
+				}
+				if err := writeSSE(w, e); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// publishTicks раз в interval публикует событие "tick" с временем
+// сервера
+func publishTicks(b *eventBroker, interval time.Duration) func(ctx context.Context) {
+	return every(interval, func() {
+		b.publish("tick", map[string]string{"time": time.Now().Format(time.RFC3339)})
+	})
+}
+
+// readSSE читает из потока следующее событие, пропуская комментарии и
+// retry; клиентская сторона для примера и тестов (в браузере это
+// делает EventSource)
+func readSSE(r *bufio.Reader) (event, error) {
+	var e event
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return e, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "":
+			if e.Type != "" {
+				return e, nil
+			}
+		case "id":
+			e.ID, _ = strconv.ParseUint(value, 10, 64)
+		case "event":
+			e.Type = value
+		case "data":
+			e.Data = []byte(value)
+		}
+	}
+}
\ No newline at end of file
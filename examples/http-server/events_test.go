@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEventBrokerFanOut(t *testing.T) {
+	b := newEventBroker(10)
+	_, ch1, _ := b.subscribe(0)
+	_, ch2, _ := b.subscribe(0)
+	
+	b.publish("test", map[string]int{"n": 1})
+	for i, ch := range []chan event{ch1, ch2} {
+		select {
+		case e := <-ch:
+			if e.ID != 1 || e.Type != "test" || string(e.Data) != `{"n":1}` {
+				t.Errorf("Subscriber %d: unexpected event %+v", i+1, e)
+			}
+		default:
+			t.Errorf("Subscriber %d: expected event", i+1)
+		}
+	}
+	
+	b.unsubscribe(ch1)
+	b.unsubscribe(ch1) // Повторная отписка безопасна
+	if n := b.subscribers(); n != 1 {
+		t.Errorf("Expected 1 subscriber, got %d", n)
+	}
+}
+
+func TestEventBrokerResume(t *testing.T) {
+	b := newEventBroker(3)
+	for i := range 5 {
+		b.publish("test", i)
+	}
+	// В буфере события 3, 4, 5
+	
+	tests := []struct {
+		name       string
+		lastID     uint64
+		wantIDs    []uint64
+		wantResume bool
+	}{
+		{"new client", 0, nil, true},
+		{"up to date", 5, nil, true},
+		{"missed two", 3, []uint64{4, 5}, true},
+		{"oldest buffered is next", 2, []uint64{3, 4, 5}, true},
+		{"history evicted", 1, []uint64{3, 4, 5}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missed, ch, ok := b.subscribe(tt.lastID)
+			defer b.unsubscribe(ch)
+			if ok != tt.wantResume {
+				t.Errorf("Expected complete %v, got %v", tt.wantResume, ok)
+			}
+			var ids []uint64
+			for _, e := range missed {
+				ids = append(ids, e.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("Expected ids %v, got %v", tt.wantIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("Expected ids %v, got %v", tt.wantIDs, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestEventBrokerDropsSlowSubscriber(t *testing.T) {
+	b := newEventBroker(100)
+	_, slow, _ := b.subscribe(0)
+	for i := range cap(slow) + 1 {
+		b.publish("test", i)
+	}
+	if n := b.subscribers(); n != 0 {
+		t.Fatalf("Expected slow subscriber to be dropped, got %d subscribers", n)
+	}
+	// Канал закрыт после буферизованных событий: обработчик увидит
+	// закрытие и завершит ответ, а клиент переподключится
+	count := 0
+	for range slow {
+		count++
+	}
+	if count != cap(slow) {
+		t.Errorf("Expected %d buffered events, got %d", cap(slow), count)
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	b := newEventBroker(10)
+	srv := httptest.NewServer(eventsHandler(b))
+	defer srv.Close()
+	
+	connect := func(lastID string) (*http.Response, *bufio.Reader) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return resp, bufio.NewReader(resp.Body)
+	}
+	
+	resp, r := connect("")
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", ct)
+	}
+	b.publish("user_created", map[string]int{"id": 1})
+	e, err := readSSE(r)
+	if err != nil || e.ID != 1 || e.Type != "user_created" {
+		t.Fatalf("Expected user_created with id 1, got %+v (%v)", e, err)
+	}
+	
+	// Отключение клиента снимает подписку
+	resp.Body.Close()
+	waitFor(t, func() bool { return b.subscribers() == 0 })
+	
+	// Переподключение досылает пропущенное
+	b.publish("user_deleted", map[string]int{"id": 1})
+	resp, r = connect(strconv.FormatUint(e.ID, 10))
+	defer resp.Body.Close()
+	if e, err := readSSE(r); err != nil || e.ID != 2 || e.Type != "user_deleted" {
+		t.Errorf("Expected missed user_deleted with id 2, got %+v (%v)", e, err)
+	}
+	
+	bad, _ := connect("abc")
+	bad.Body.Close()
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for bad Last-Event-ID, got %d", bad.StatusCode)
+	}
+}
+
+// Без close потоки событий держали бы Shutdown до таймаута
+func TestEventsHandler_Shutdown(t *testing.T) {
+	b := newEventBroker(10)
+	srv := httptest.NewUnstartedServer(eventsHandler(b))
+	srv.Config.RegisterOnShutdown(b.close)
+	srv.Start()
+	defer srv.Close()
+	
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	waitFor(t, func() bool { return b.subscribers() == 1 })
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected Shutdown to finish, got %v", err)
+	}
+	if _, err := readSSE(bufio.NewReader(resp.Body)); err != io.EOF {
+		t.Errorf("Expected stream to end with EOF, got %v", err)
+	}
+}
+
+// waitFor ждет условия: обработчик замечает отключение клиента не сразу
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
\ No newline at end of file
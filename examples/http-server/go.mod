@@ -0,0 +1,13 @@
+module httpserver
+
+go 1.23
+
+require (
+	clock v0.0.0
+	trie v0.0.0
+)
+
+replace (
+	clock => ../clock
+	trie => ../trie
+)
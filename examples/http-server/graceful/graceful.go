@@ -0,0 +1,153 @@
+// Package graceful запускает HTTP-сервер вместе с фоновыми горутинами и
+// ресурсами и останавливает их в правильном порядке:
+//
+//  1. отмена ctx (обычно SIGINT/SIGTERM через signal.NotifyContext);
+//  2. server.Shutdown: новые соединения не принимаются, текущие запросы
+//     дорабатывают, простаивающие keep-alive соединения закрываются;
+//  3. фоновые горутины (Go) получают отмену контекста, их дожидаемся;
+//  4. ресурсы (OnClose) закрываются в обратном порядке регистрации -
+//     база закрывается после всех, кто ею пользуется.
+//
+// Все три этапа укладываются в общий ShutdownTimeout: оркестратор
+// (Kubernetes, systemd) после SIGTERM ждет ограниченное время и потом
+// убивает процесс, поэтому повисший запрос не должен съесть весь запас.
+package graceful
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"httpserver/safego"
+)
+
+// DefaultShutdownTimeout запас на остановку, если ShutdownTimeout не
+// задан; меньше 30 секунд, которые Kubernetes ждет по умолчанию
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Server HTTP-сервер с фоновыми горутинами и ресурсами, которые
+// останавливаются вместе с ним
+type Server struct {
+	HTTP            *http.Server
+	ShutdownTimeout time.Duration
+	Logger          *log.Logger
+
+	// ctx фоновых горутин: отменяется после остановки HTTP, а не по
+	// сигналу - обработчикам, которые дорабатывают, они еще нужны
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	workers []worker
+	closers []closer
+}
+
+type worker struct {
+	name string
+	done <-chan struct{}
+}
+
+type closer struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// New оборачивает srv; Handler и таймауты настраиваются в нем как обычно
+func New(srv *http.Server) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{HTTP: srv, Logger: log.Default(), ctx: ctx, cancel: cancel}
+}
+
+// Go запускает фоновую горутину через safego.Go. Ее контекст
+// отменяется при остановке после HTTP-сервера, и Run ждет ее
+// завершения. Запускать можно и до Run: очистки кэшей и сессий нужны с
+// самого старта.
+func (s *Server) Go(name string, fn func(ctx context.Context), opts ...safego.Option) {
+	done := safego.Go(s.ctx, name, fn, append([]safego.Option{safego.WithLogger(s.Logger)}, opts...)...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers = append(s.workers, worker{name: name, done: done})
+}
+
+// OnClose регистрирует закрытие ресурса: пула соединений с базой,
+// очереди, временного каталога. Вызывается после остановки HTTP и
+// фоновых горутин, в обратном порядке регистрации.
+func (s *Server) OnClose(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closers = append(s.closers, closer{name: name, fn: fn})
+}
+
+// Run слушает HTTP.Addr и обслуживает запросы до отмены ctx, затем
+// останавливает все по порядку. Возвращает ошибку запуска или ошибки
+// остановки; обычная остановка по ctx - nil.
+func (s *Server) Run(ctx context.Context) error {
+	addr := s.HTTP.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		// Сервер не стартовал, но горутины и ресурсы уже могли быть
+		// запущены - их все равно нужно остановить
+		return errors.Join(err, s.stop())
+	}
+	return s.Serve(ctx, l)
+}
+
+// Serve как Run, но на готовом listener - например, на 127.0.0.1:0 в
+// тестах
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.Logger.Printf("Сервер запущен на %s", l.Addr())
+		errCh <- s.HTTP.Serve(l)
+	}()
+	
+	select {
+	case err := <-errCh:
+		return errors.Join(err, s.stop())
+	case <-ctx.Done():
+	}
+	s.Logger.Println("Остановка...")
+	return s.stop()
+}
+
+// stop останавливает HTTP, горутины и ресурсы в пределах общего
+// таймаута. Если запросы не уложились, соединения закрываются
+// принудительно, но горутины и ресурсы все равно останавливаются.
+func (s *Server) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cmp.Or(s.ShutdownTimeout, DefaultShutdownTimeout))
+	defer cancel()
+	
+	var errs []error
+	if err := s.HTTP.Shutdown(ctx); err != nil {
+		s.HTTP.Close()
+		errs = append(errs, fmt.Errorf("http: %w", err))
+	}
+	
+	s.cancel()
+	s.mu.Lock()
+	workers, closers := s.workers, s.closers
+	s.mu.Unlock()
+	for _, w := range workers {
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("горутина %s не завершилась: %w", w.name, ctx.Err()))
+		}
+	}
+	
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", closers[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
\ No newline at end of file
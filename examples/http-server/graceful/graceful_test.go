@@ -0,0 +1,165 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// start запускает s.Serve на свободном порту; вернет адрес и канал с
+// результатом Serve
+func start(t *testing.T, ctx context.Context, s *Server) (string, <-chan error) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.Logger = log.New(io.Discard, "", 0)
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, l) }()
+	return "http://" + l.Addr().String(), done
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	s := New(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})})
+	
+	// Порядок остановки: HTTP, затем горутины, затем ресурсы в обратном
+	// порядке регистрации
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+	s.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		record("worker")
+	})
+	for _, name := range []string{"db", "cache"} {
+		s.OnClose(name, func(ctx context.Context) error {
+			record(name)
+			return nil
+		})
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	url, done := start(t, ctx, s)
+	type result struct {
+		body string
+		err  error
+	}
+	resp := make(chan result, 1)
+	go func() {
+		r, err := http.Get(url)
+		if err != nil {
+			resp <- result{err: err}
+			return
+		}
+		defer r.Body.Close()
+		b, err := io.ReadAll(r.Body)
+		resp <- result{string(b), err}
+	}()
+	
+	<-started
+	cancel()
+	// Пока запрос не завершен, Serve ждет его
+	select {
+	case err := <-done:
+		t.Fatalf("Expected Serve to wait for in-flight request, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	record("http")
+	close(release)
+	
+	if r := <-resp; r.err != nil || r.body != "done" {
+		t.Errorf("Expected in-flight request to complete, got %q, %v", r.body, r.err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := []string{"http", "worker", "cache", "db"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+	// Новые соединения после остановки не принимаются
+	if _, err := http.Get(url); err == nil {
+		t.Error("Expected connection error after shutdown")
+	}
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	// Запрос, который не заканчивается сам: закончится, только когда
+	// соединение закроют принудительно
+	s := New(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})})
+	s.ShutdownTimeout = 50 * time.Millisecond
+	closed := false
+	s.OnClose("db", func(ctx context.Context) error {
+		closed = true
+		return errors.New("уже закрыта")
+	})
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	url, done := start(t, ctx, s)
+	r, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer r.Body.Close()
+	cancel()
+	
+	select {
+	case err = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Serve to return after ShutdownTimeout")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if !closed || !strings.Contains(err.Error(), "db: уже закрыта") {
+		t.Errorf("Expected resources closed despite timeout and their errors reported, got %v", err)
+	}
+}
+
+func TestRun_ListenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer l.Close()
+	
+	// Адрес занят: Run возвращает ошибку сразу, но уже запущенное
+	// останавливает
+	s := New(&http.Server{Addr: l.Addr().String()})
+	s.Logger = log.New(io.Discard, "", 0)
+	stopped := make(chan struct{})
+	s.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+	if err := s.Run(context.Background()); err == nil {
+		t.Fatal("Expected listen error")
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("Expected worker to be stopped")
+	}
+}
\ No newline at end of file
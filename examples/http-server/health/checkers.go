@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Pinger то, что умеет проверить соединение: *sql.DB, клиенты Redis
+// и брокеров очередей
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping проверка соединения с базой. *sql.DB.PingContext берет
+// соединение из пула и при необходимости открывает новое, так что
+// проверка заметит и упавшую базу, и исчерпанный пул.
+func Ping(p Pinger) Checker {
+	return CheckerFunc(p.PingContext)
+}
+
+// HTTPGet проверка соседнего сервиса: GET url должен вернуть 2xx.
+// Обычно url - его собственный /readyz или /healthz. client - свой, с
+// транспортом сервиса; таймаут задает Registry через контекст.
+func HTTPGet(client *http.Client, url string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		// Дочитываем тело, чтобы соединение вернулось в пул
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s: %s", url, resp.Status)
+		}
+		return nil
+	})
+}
+
+// DiskSpace проверка свободного места на разделе с path: база и
+// загрузки, которым некуда писать, отвечают ошибками на каждый запрос
+func DiskSpace(path string, minFree uint64) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		free, err := freeSpace(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%s: свободно %d МБ, нужно не меньше %d МБ", path, free>>20, minFree>>20)
+		}
+		return nil
+	})
+}
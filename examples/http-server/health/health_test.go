@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ok(context.Context) error { return nil }
+
+func get(t *testing.T, h http.Handler) (int, Report) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+	var rep Report
+	if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("Unexpected error: %v\n%s", err, w.Body)
+	}
+	return w.Code, rep
+}
+
+func TestReadyHandler(t *testing.T) {
+	r := New()
+	r.Ready("db", CheckerFunc(ok))
+	r.Ready("cache", CheckerFunc(ok))
+	code, rep := get(t, r.ReadyHandler())
+	if code != http.StatusOK || rep.Status != StatusOK {
+		t.Errorf("Expected 200 ok, got %d %+v", code, rep)
+	}
+	if len(rep.Checks) != 2 || rep.Checks["db"].Status != StatusOK {
+		t.Errorf("Unexpected checks %+v", rep.Checks)
+	}
+
+	r.Ready("disk", CheckerFunc(func(context.Context) error { return errors.New("нет места") }))
+	code, rep = get(t, r.ReadyHandler())
+	if code != http.StatusServiceUnavailable || rep.Status != StatusFail {
+		t.Errorf("Expected 503 fail, got %d %+v", code, rep)
+	}
+	if rep.Checks["disk"].Error != "нет места" {
+		t.Errorf("Expected disk error in report, got %+v", rep.Checks["disk"])
+	}
+}
+
+func TestLiveAndReadySeparate(t *testing.T) {
+	r := New()
+	r.Ready("db", CheckerFunc(func(context.Context) error { return errors.New("down") }))
+	// База лежит, но процесс жив: перезапуск не нужен
+	if code, rep := get(t, r.LiveHandler()); code != http.StatusOK || len(rep.Checks) != 0 {
+		t.Errorf("Expected live 200 without checks, got %d %+v", code, rep)
+	}
+	if code, _ := get(t, r.ReadyHandler()); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected ready 503, got %d", code)
+	}
+}
+
+func TestOptional(t *testing.T) {
+	r := New()
+	r.Ready("db", CheckerFunc(ok))
+	r.Ready("recommendations", CheckerFunc(func(context.Context) error { return errors.New("down") }), Optional())
+	code, rep := get(t, r.ReadyHandler())
+	if code != http.StatusOK {
+		t.Errorf("Expected 200 with failed optional check, got %d", code)
+	}
+	if res := rep.Checks["recommendations"]; res.Status != StatusFail || !res.Optional {
+		t.Errorf("Expected optional failure in report, got %+v", res)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r := New()
+	release := make(chan struct{})
+	defer close(release)
+	// Проверка не смотрит на ctx - отчет все равно не ждет ее
+	r.Ready("stuck", CheckerFunc(func(context.Context) error {
+		<-release
+		return nil
+	}), WithTimeout(50*time.Millisecond))
+	r.Ready("slow", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	rep := r.CheckReady(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected checks to run in parallel within timeout, took %v", elapsed)
+	}
+	for _, name := range []string{"stuck", "slow"} {
+		if res := rep.Checks[name]; res.Status != StatusFail || !strings.HasPrefix(res.Error, "таймаут") {
+			t.Errorf("%s: expected timeout, got %+v", name, res)
+		}
+	}
+}
+
+func TestPanicIsFailure(t *testing.T) {
+	r := New()
+	r.Live("loop", CheckerFunc(func(context.Context) error { panic("boom") }))
+	rep := r.CheckLive(context.Background())
+	if rep.Status != StatusFail || !strings.Contains(rep.Checks["loop"].Error, "boom") {
+		t.Errorf("Expected panic reported as failure, got %+v", rep)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	r := New()
+	called := false
+	r.Ready("db", CheckerFunc(func(context.Context) error {
+		called = true
+		return nil
+	}))
+	r.Drain()
+	code, rep := get(t, r.ReadyHandler())
+	if code != http.StatusServiceUnavailable || rep.Reason == "" {
+		t.Errorf("Expected 503 with reason after Drain, got %d %+v", code, rep)
+	}
+	if called {
+		t.Error("Expected checks to be skipped after Drain")
+	}
+	if code, _ := get(t, r.LiveHandler()); code != http.StatusOK {
+		t.Errorf("Expected live 200 while draining, got %d", code)
+	}
+}
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) PingContext(context.Context) error { return p.err }
+
+func TestPing(t *testing.T) {
+	if err := Ping(fakePinger{}).Check(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Ping(fakePinger{err: errors.New("refused")}).Check(context.Background()); err == nil {
+		t.Error("Expected ping error")
+	}
+}
+
+func TestHTTPGet(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	c := HTTPGet(srv.Client(), srv.URL+"/readyz")
+	if err := c.Check(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	if err := c.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected 503 error, got %v", err)
+	}
+}
+
+func TestDiskSpace(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := freeSpace(dir); err != nil {
+		t.Skipf("Free space unavailable: %v", err)
+	}
+	if err := DiskSpace(dir, 1).Check(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := DiskSpace(dir, 1<<62).Check(context.Background()); err == nil {
+		t.Error("Expected error for unreachable minimum")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// JWT (RFC 7519) с подписью HS256, только на стандартной библиотеке:
+//
+//	base64url(заголовок) . base64url(claims) . base64url(HMAC-SHA256)
+//
+// Токен не зашифрован - claims может прочитать любой, кто его получил.
+// Подпись лишь гарантирует, что их выпустил сервер с этим секретом.
+// В реальном проекте берут библиотеку (github.com/golang-jwt/jwt/v5), но
+// проверки в ней те же, что в parseJWT ниже.
+
+var (
+	errInvalidToken = errors.New("invalid token")
+	errTokenExpired = errors.New("token expired")
+)
+
+// Типы токенов: access предъявляется в каждом запросе, refresh - только
+// для получения новой пары. Без поля typ refresh-токен с долгим сроком
+// годился бы и как access.
+const (
+	tokenAccess  = "access"
+	tokenRefresh = "refresh"
+)
+
+// jwtHeader заголовок всех наших токенов
+const jwtHeader = `{"alg":"HS256","typ":"JWT"}`
+
+// claims полезная нагрузка токена
+type claims struct {
+	Subject   int    `json:"sub"`
+	Type      string `json:"typ"`
+	ID        string `json:"jti,omitempty"` // Для отзыва refresh-токенов
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// signJWT выпускает токен с claims
+func signJWT(secret []byte, c claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(jwtHeader)) + "." + enc.EncodeToString(payload)
+	return unsigned + "." + enc.EncodeToString(jwtMAC(secret, unsigned)), nil
+}
+
+// parseJWT проверяет подпись и срок и возвращает claims. Алгоритм
+// берется не из заголовка, а фиксирован: классическая уязвимость JWT -
+// доверять "alg" токена и принять "none" или подписанный открытым
+// ключом HS256 вместо RS256.
+func parseJWT(secret []byte, token string, now time.Time) (claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims{}, errInvalidToken
+	}
+	enc := base64.RawURLEncoding
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return claims{}, errInvalidToken
+	}
+	// Подпись проверяется первой и за постоянное время: до этого
+	// содержимое токена - непроверенный ввод
+	if !hmac.Equal(sig, jwtMAC(secret, parts[0]+"."+parts[1])) {
+		return claims{}, errInvalidToken
+	}
+	
+	header, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return claims{}, errInvalidToken
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return claims{}, errInvalidToken
+	}
+	
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return claims{}, errInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return claims{}, errInvalidToken
+	}
+	if c.ExpiresAt == 0 || !now.Before(time.Unix(c.ExpiresAt, 0)) {
+		return claims{}, errTokenExpired
+	}
+	return c, nil
+}
+
+func jwtMAC(secret []byte, unsigned string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
\ No newline at end of file
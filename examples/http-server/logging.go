@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Журнал запросов на log/slog: одна запись на запрос с полями, а не
+// строка текста. По полям сборщик логов фильтрует и строит графики
+// (status>=500, p99 по duration), а по request_id собираются все записи
+// одного запроса - в том числе из обработчиков, которые берут его через
+// requestIDFrom. Формат (text или json) и уровень - из настроек.
+
+// statusRecorder запоминает код ответа и число байт тела. Код нужен
+// middleware после обработчика, а http.ResponseWriter его не отдает.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 && code >= 200 { // 1xx - промежуточные, ответ впереди
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK // Write без WriteHeader - это 200
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush нужен потокам вроде /api/events: они проверяют w.(http.Flusher),
+// и обертка без Flush сломала бы их
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap для http.ResponseController: дедлайны и прочее проходят к
+// настоящему ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+const requestIDKey contextKey = "requestID"
+
+// requestIDFrom ID запроса; есть только за loggingMiddleware
+func requestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// newRequestID 64 случайных бита: уникальность, а не секретность
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID ID от клиента или балансировщика принимается, только
+// если он короткий и из безопасных символов: иначе через заголовок
+// можно записать в журнал что угодно
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// loggingMiddleware пишет в logger запись о каждом запросе: метод, путь,
+// код, размер тела, длительность и request_id. ID берется из заголовка
+// X-Request-ID, если его прислал балансировщик, иначе создается;
+// возвращается в ответе тем же заголовком - клиент сообщит его в жалобе.
+// Уровень по коду: 5xx - Error, 4xx - Warn, остальное - Info.
+func loggingMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		
+		rec := &statusRecorder{ResponseWriter: w}
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK // Обработчик ничего не записал
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		// LogAttrs без ...any: атрибуты не упаковываются в interface
+		logger.LogAttrs(ctx, level, "запрос",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int64("bytes", rec.bytes),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("request_id", id),
+		)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  float64
+		bytes   float64
+		level   string
+	}{
+		{"Write без WriteHeader", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("привет")) }, 200, 12, "INFO"},
+		{"пустой ответ", func(w http.ResponseWriter, r *http.Request) {}, 200, 0, "INFO"},
+		{"404", func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }, 404, 19, "WARN"},
+		{"500", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(500) }, 500, 0, "ERROR"},
+		{"103 перед ответом", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusEarlyHints)
+			w.WriteHeader(http.StatusCreated)
+		}, 201, 0, "INFO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := loggingMiddleware(slog.New(slog.NewJSONHandler(&buf, nil)), tt.handler)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/users?page=2", nil))
+			
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Unexpected error: %v (%s)", err, buf.String())
+			}
+			expected := map[string]any{"level": tt.level, "method": "POST", "path": "/api/users", "status": tt.status, "bytes": tt.bytes}
+			for k, v := range expected {
+				if entry[k] != v {
+					t.Errorf("%s: expected %v, got %v", k, v, entry[k])
+				}
+			}
+			if _, ok := entry["duration"].(float64); !ok {
+				t.Errorf("Expected numeric duration, got %v", entry["duration"])
+			}
+		})
+	}
+}
+
+func TestLoggingMiddleware_RequestID(t *testing.T) {
+	tests := []struct {
+		header string
+		keep   bool
+	}{
+		{"lb-1234.abc_DEF", true},
+		{"", false},
+		{"с пробелом и кириллицей", false},
+		{"x\nlevel=ERROR", false}, // Подделка записи в text-формате
+		{strings.Repeat("a", 65), false},
+	}
+	for _, tt := range tests {
+		var seen string
+		var buf bytes.Buffer
+		h := loggingMiddleware(slog.New(slog.NewTextHandler(&buf, nil)), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = requestIDFrom(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", tt.header)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		
+		id := w.Header().Get("X-Request-ID")
+		if tt.keep && id != tt.header {
+			t.Errorf("Expected %q to be kept, got %q", tt.header, id)
+		}
+		if !tt.keep && (id == tt.header || len(id) != 16) {
+			t.Errorf("Expected new 16-char ID instead of %q, got %q", tt.header, id)
+		}
+		if seen != id || !strings.Contains(buf.String(), "request_id="+id) {
+			t.Errorf("Expected handler and log to see %q, got %q and %s", id, seen, buf.String())
+		}
+	}
+}
+
+func TestLoggingMiddleware_Flush(t *testing.T) {
+	h := loggingMiddleware(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("Expected http.Flusher")
+		}
+		flusher.Flush()
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+	if !w.Flushed {
+		t.Error("Expected Flush to reach the underlying writer")
+	}
+}
\ No newline at end of file
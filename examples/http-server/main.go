@@ -1,28 +1,81 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
+	
+	"clock"
+	"httpserver/config"
+	"httpserver/graceful"
+	"httpserver/health"
+	"httpserver/openapi"
+	"httpserver/router"
+	"httpserver/safego"
 )
 
 // User модель пользователя
 type User struct {
-	ID    int    `json:"id"`
+	ID    int    `json:"id" doc:"Назначается сервером, в запросе игнорируется"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
 
-// In-memory storage для пользователей
-var users = map[int]User{
-	1: {ID: 1, Name: "Иван Иванов", Email: "ivan@example.com"},
-	2: {ID: 2, Name: "Мария Петрова", Email: "maria@example.com"},
-}
+// In-memory storage для пользователей (см. userstore.go)
+var users = newUserStore(
+	User{ID: 1, Name: "Иван Иванов", Email: "ivan@example.com"},
+	User{ID: 2, Name: "Мария Петрова", Email: "maria@example.com"},
+)
+
+// Кэш поиска пользователей по ID (см. usercache.go)
+var cachedUsers = newUserCache(100)
+
+// События об изменениях пользователей для GET /api/events (см. events.go)
+var userEvents = newEventBroker(100)
+
+// cfg настройки сервера: по умолчанию, из файла, окружения и флагов
+// (см. config/); main загружает их до запуска примеров
+var cfg = config.Default()
+
+// app сервер всех примеров (http.DefaultServeMux) вместе с их фоновыми
+// горутинами и ресурсами; создается в main по cfg, запускается флагом
+// -serve и останавливается по Ctrl+C или SIGTERM (см. пример 4)
+var app *graceful.Server
+
+// checks проверки /healthz и /readyz (см. health/); примеры
+// регистрируют в нем свои зависимости
+var checks = health.New()
 
-var nextID = 3
+// logger журнал сервера: формат и уровень из cfg (log_format,
+// log_level); main заменяет им и slog.Default, так что старые вызовы
+// log.Printf тоже попадают в него (см. logging.go)
+var logger = slog.Default()
+
+func findUser(id int) (User, bool) {
+	return users.Get(id)
+}
 
 // Пример 1: Базовый HTTP сервер
 func basicHTTPServer() {
@@ -44,126 +97,156 @@ func basicHTTPServer() {
 		json.NewEncoder(w).Encode(response)
 	})
 	
-	fmt.Println("Сервер запущен на :8080")
+	fmt.Printf("Сервер запущен на %s\n", cfg.Addr)
 	// Запуск сервера (закомментирован для примера)
-	// log.Fatal(http.ListenAndServe(":8080", nil))
+	// log.Fatal(http.ListenAndServe(cfg.Addr, nil))
 }
 
 // Пример 2: REST API для пользователей
+// Маршруты с параметрами пути и методами - через router: ID приходит
+// в router.Param, а на неподходящий метод router сам отвечает 405.
+// Маршруты и их описание OpenAPI - в apidoc.go.
 func userAPI() {
 	fmt.Println("\n=== REST API для пользователей ===")
 	
-	// Получить всех пользователей
-	http.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			// Возвращаем всех пользователей
-			userList := make([]User, 0, len(users))
-			for _, user := range users {
-				userList = append(userList, user)
-			}
-			
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(userList)
-			
-		case http.MethodPost:
-			// Создаем нового пользователя
-			var user User
-			if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-				http.Error(w, "Неверный JSON", http.StatusBadRequest)
-				return
-			}
-			
-			user.ID = nextID
-			nextID++
-			users[user.ID] = user
-			
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusCreated)
-			json.NewEncoder(w).Encode(user)
-			
-		default:
-			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
-		}
-	})
+	r, spec := usersAPI(userRoutes())
 	
-	// Получить/обновить/удалить конкретного пользователя
-	http.HandleFunc("/api/users/", func(w http.ResponseWriter, r *http.Request) {
-		// Извлекаем ID из URL
-		idStr := r.URL.Path[len("/api/users/"):]
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			http.Error(w, "Неверный ID", http.StatusBadRequest)
+	// Оба пути нужны http.ServeMux: "/api/users/" ловит все под ним,
+	// дальше разбирает router
+	http.Handle("/api/users", r)
+	http.Handle("/api/users/", r)
+	http.Handle("/api/openapi.json", spec.Handler())
+	http.Handle("/api/docs", openapi.UI("/api/openapi.json"))
+	fmt.Println("Документация:", cfg.BaseURL()+"/api/docs")
+}
+
+// Получить список пользователей
+func listUsers(w http.ResponseWriter, r *http.Request) {
+	// Нечеткий поиск по имени: /api/users?search=иванов. Запрос из одних
+	// пробелов (?search=%20) - обычный список, а не поиск по пустой строке
+	if search := strings.TrimSpace(r.URL.Query().Get("search")); search != "" {
+		if len(search) > 100 {
+			http.Error(w, "Слишком длинный запрос", http.StatusBadRequest)
 			return
 		}
-		
-		switch r.Method {
-		case http.MethodGet:
-			// Получаем пользователя
-			user, exists := users[id]
-			if !exists {
-				http.Error(w, "Пользователь не найден", http.StatusNotFound)
-				return
-			}
-			
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(user)
-			
-		case http.MethodPut:
-			// Обновляем пользователя
-			user, exists := users[id]
-			if !exists {
-				http.Error(w, "Пользователь не найден", http.StatusNotFound)
-				return
-			}
-			
-			var updatedUser User
-			if err := json.NewDecoder(r.Body).Decode(&updatedUser); err != nil {
-				http.Error(w, "Неверный JSON", http.StatusBadRequest)
-				return
-			}
-			
-			updatedUser.ID = id // Сохраняем оригинальный ID
-			users[id] = updatedUser
-			
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(updatedUser)
-			
-		case http.MethodDelete:
-			// Удаляем пользователя
-			if _, exists := users[id]; !exists {
-				http.Error(w, "Пользователь не найден", http.StatusNotFound)
-				return
-			}
-			
-			delete(users, id)
-			w.WriteHeader(http.StatusNoContent)
-			
-		default:
-			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
-		}
-	})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(searchUsers(users.List(), search))
+		return
+	}
+	
+	// Фильтр, сортировка и страницы - см. pagination.go
+	listUsersPaged(w, r)
 }
 
-// Middleware для логирования
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		log.Printf("Запрос: %s %s", r.Method, r.URL.Path)
-		
-		// Вызываем следующий обработчик
-		next.ServeHTTP(w, r)
-		
-		log.Printf("Завершено за %v", time.Since(start))
-	})
+// Создать нового пользователя
+func createUser(w http.ResponseWriter, r *http.Request) {
+	var user User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		http.Error(w, "Неверный JSON", http.StatusBadRequest)
+		return
+	}
+	
+	user = users.Create(user)
+	userEvents.publish("user_created", user)
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// userID разбирает {id} из пути; при ошибке сам отвечает 400
+func userID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(router.Param(r, "id"))
+	if err != nil {
+		http.Error(w, "Неверный ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
 }
 
-// Middleware для CORS
-func corsMiddleware(next http.Handler) http.Handler {
+// Получить пользователя через кэш
+func getUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	
+	user, exists := cachedUsers.Get(id, findUser)
+	if !exists {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// Обновить пользователя
+func updateUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	if _, exists := users.Get(id); !exists {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	
+	var updatedUser User
+	if err := json.NewDecoder(r.Body).Decode(&updatedUser); err != nil {
+		http.Error(w, "Неверный JSON", http.StatusBadRequest)
+		return
+	}
+	
+	updatedUser.ID = id // Сохраняем оригинальный ID
+	if !users.Update(updatedUser) {
+		// Удалили, пока читали тело запроса
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	cachedUsers.Invalidate(id) // Иначе GET вернет старые данные
+	userEvents.publish("user_updated", updatedUser)
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedUser)
+}
+
+// Удалить пользователя
+func deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	if !users.Delete(id) {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	
+	cachedUsers.Invalidate(id)
+	userEvents.publish("user_deleted", map[string]int{"id": id})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Middleware для CORS. origins - из настроек (cors_origins): "*"
+// разрешает всем, иначе заголовки получает только origin из списка, и
+// браузер остальных ответ не покажет.
+func corsMiddleware(origins []string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		allowed := ""
+		if slices.Contains(origins, "*") {
+			allowed = "*"
+		} else {
+			// Ответ зависит от Origin: кэш не должен отдать его другому сайту
+			w.Header().Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); slices.Contains(origins, origin) {
+				allowed = origin
+			}
+		}
+		if allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		}
 		
 		// Обрабатываем preflight запросы
 		if r.Method == "OPTIONS" {
@@ -194,145 +277,184 @@ func middlewareExample() {
 	})
 	
 	// Оборачиваем маршрутизатор в middleware
-	handler := corsMiddleware(loggingMiddleware(mux))
+	handler := corsMiddleware(cfg.CORSOrigins, loggingMiddleware(logger, mux))
 	
-	// Создаем сервер
-	server := &http.Server{
-		Addr:    ":8081",
-		Handler: handler,
-	}
+	// Создаем сервер: адрес и таймауты из настроек
+	server := cfg.Server(handler)
 	
-	fmt.Println("Сервер с middleware запущен на :8081")
+	fmt.Printf("Сервер с middleware запущен на %s\n", server.Addr)
 	// Запуск сервера (закомментирован для примера)
 	// log.Fatal(server.ListenAndServe())
+	
+	// Один и тот же запрос в журнале обоих форматов: text читать
+	// глазами, json - разбирать сборщику логов
+	for _, format := range []string{"text", "json"} {
+		c := cfg
+		c.LogFormat = format
+		h := loggingMiddleware(c.Logger(os.Stdout), mux)
+		req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+		req.Header.Set("X-Request-ID", "demo-"+format)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
 }
 
-// Пример 4: Graceful shutdown
+// Пример 4: Graceful shutdown (см. graceful/)
+// Сервер получает сигнал посреди медленного запроса: запрос дорабатывает
+// и клиент получает ответ, после этого останавливаются фоновые горутины
+// и закрываются ресурсы. Здесь вместо сигнала - отмена контекста, в
+// main с флагом -serve - настоящие SIGINT/SIGTERM.
 func gracefulShutdown() {
 	fmt.Println("\n=== Graceful shutdown ===")
 	
+	// Шаги остановки собираются и печатаются в конце: печать прямо из
+	// горутин перемешала бы порядок
+	var mu sync.Mutex
+	var steps []string
+	step := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps = append(steps, s)
+	}
+	
+	started := make(chan struct{})
 	mux := http.NewServeMux()
-	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
-		response := map[string]string{
-			"status": "healthy",
-		}
-		
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+	mux.HandleFunc("/api/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprintln(w, "готово")
+		step("медленный запрос завершен")
+	})
+	srv := graceful.New(&http.Server{Handler: mux})
+	srv.Logger = log.New(io.Discard, "", 0)
+	srv.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		step("фоновая горутина остановлена")
+	})
+	srv.OnClose("db", func(ctx context.Context) error {
+		step("база закрыта")
+		return nil
 	})
 	
-	server := &http.Server{
-		Addr:    ":8082",
-		Handler: mux,
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, l) }()
 	
-	// Запуск сервера в отдельной горутине
+	resp := make(chan string, 1)
 	go func() {
-		fmt.Println("Сервер запущен на :8082")
-		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			log.Fatalf("Ошибка сервера: %v", err)
+		r, err := http.Get("http://" + l.Addr().String() + "/api/slow")
+		if err != nil {
+			resp <- err.Error()
+			return
 		}
+		defer r.Body.Close()
+		body, _ := io.ReadAll(r.Body)
+		resp <- strings.TrimSpace(string(body))
 	}()
 	
-	// Здесь мог бы быть код для ожидания сигнала завершения
-	// и корректной остановки сервера
-	fmt.Println("Для остановки сервера используйте Ctrl+C")
+	<-started
+	step("сигнал остановки посреди запроса")
+	cancel() // Как Ctrl+C
+	if err := <-done; err != nil {
+		log.Fatal(err)
+	}
+	for i, s := range steps {
+		fmt.Printf("%d. %s\n", i+1, s)
+	}
+	fmt.Printf("Клиент получил ответ: %s\n", <-resp)
+	fmt.Printf("Все примеры на %s с остановкой по Ctrl+C: go run . -serve\n", cfg.Addr)
 }
 
-// Пример 5: Работа с формами
+// Пример 5: Работа с формами (шаблоны - см. pages.go и templates/)
 func formHandling() {
 	fmt.Println("\n=== Работа с формами ===")
 	
-	// Страница с формой
-	http.HandleFunc("/form", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			// Отображаем форму
-			html := `
-<!DOCTYPE html>
-<html>
-<head>
-    <title>Форма пользователя</title>
-    <meta charset="UTF-8">
-</head>
-<body>
-    <h1>Добавить пользователя</h1>
-    <form method="POST" action="/form">
-        <label for="name">Имя:</label>
-        <input type="text" id="name" name="name" required><br><br>
-        
-        <label for="email">Email:</label>
-        <input type="email" id="email" name="email" required><br><br>
-        
-        <input type="submit" value="Добавить">
-    </form>
-</body>
-</html>`
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			fmt.Fprint(w, html)
-		} else if r.Method == http.MethodPost {
-			// Обрабатываем форму
-			if err := r.ParseForm(); err != nil {
-				http.Error(w, "Ошибка разбора формы", http.StatusBadRequest)
+	csrf := newCSRFProtection(secretFromEnv("CSRF_SECRET"), os.Getenv("SESSION_INSECURE") == "")
+	http.HandleFunc("GET /users", usersPage)
+	http.Handle("GET /form", csrfMiddleware(csrf, http.HandlerFunc(userFormPage)))
+	http.Handle("POST /form", csrfMiddleware(csrf, http.HandlerFunc(userFormSubmit)))
+	
+	// Ошибка в форме: страница показывается снова, введенное сохраняется
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader("name=<b>Пётр</b>&email=нет"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	userFormSubmit(rec, req)
+	fmt.Printf("POST /form с неверным email: %d, имя в разметке: %v\n",
+		rec.Code, strings.Contains(rec.Body.String(), `value="&lt;b&gt;Пётр&lt;/b&gt;"`))
+	
+	
+	// CSRF: POST без токена отклоняется, с токеном из формы - проходит.
+	// Демонстрация без Secure - по http cookiejar не вернул бы куку.
+	srv := httptest.NewServer(csrfMiddleware(newCSRFProtection(csrf.secret, false), http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				userFormPage(w, r)
 				return
 			}
-			
-			name := r.FormValue("name")
-			email := r.FormValue("email")
-			
-			// Создаем пользователя
-			user := User{
-				ID:    nextID,
-				Name:  name,
-				Email: email,
-			}
-			nextID++
-			users[user.ID] = user
-			
-			// Перенаправляем на список пользователей
-			http.Redirect(w, r, "/api/users", http.StatusSeeOther)
+			w.WriteHeader(http.StatusSeeOther) // Пользователь не создается
+		})))
+	defer srv.Close()
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar, CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	post := func(form url.Values) int {
+		resp, err := client.PostForm(srv.URL, form)
+		if err != nil {
+			log.Fatal(err)
 		}
-	})
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	form := url.Values{"name": {"Пётр"}, "email": {"petr@example.com"}}
+	fmt.Printf("POST /form без CSRF-сессии: %d\n", post(form))
+	
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	_, rest, _ := strings.Cut(string(page), `name="csrf_token" value="`)
+	token, _, _ := strings.Cut(rest, `"`)
+	fmt.Printf("POST /form с чужим токеном: %d\n", post(url.Values{"csrf_token": {"forged"}, "name": form["name"], "email": form["email"]}))
+	form.Set("csrf_token", token)
+	fmt.Printf("POST /form с токеном из формы: %d\n", post(form))
+	
+	fmt.Printf("Откройте %s/users и %s/form\n", cfg.BaseURL(), cfg.BaseURL())
+	fmt.Println("Для проверки по http без TLS: SESSION_INSECURE=1; секрет токенов: CSRF_SECRET")
 }
 
-// Пример 6: Загрузка файлов
+// Пример 6: Загрузка и скачивание файлов (см. upload.go)
+// Каталог - UPLOAD_DIR или временный; принимаются текст, картинки, PDF
+// и ZIP до 10 МБ - тип проверяется по содержимому
 func fileUpload() {
 	fmt.Println("\n=== Загрузка файлов ===")
 	
-	http.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			// Форма для загрузки файла
-			html := `
-<!DOCTYPE html>
-<html>
-<head>
-    <title>Загрузка файла</title>
-    <meta charset="UTF-8">
-</head>
-<body>
-    <h1>Загрузить файл</h1>
-    <form method="POST" enctype="multipart/form-data">
-        <input type="file" name="file" required><br><br>
-        <input type="submit" value="Загрузить">
-    </form>
-</body>
-</html>`
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			fmt.Fprint(w, html)
-		} else if r.Method == http.MethodPost {
-			// Обрабатываем загрузку файла
-			file, handler, err := r.FormFile("file")
-			if err != nil {
-				http.Error(w, "Ошибка получения файла", http.StatusBadRequest)
-				return
-			}
-			defer file.Close()
-			
-			fmt.Fprintf(w, "Файл загружен успешно!\n")
-			fmt.Fprintf(w, "Имя файла: %s\n", handler.Filename)
-			fmt.Fprintf(w, "Размер: %d байт\n", handler.Size)
-			fmt.Fprintf(w, "Content-Type: %s\n", handler.Header.Get("Content-Type"))
+	dir := os.Getenv("UPLOAD_DIR")
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "uploads"); err != nil {
+			log.Fatal(err)
 		}
-	})
+		app.OnClose("uploads", func(context.Context) error { return os.RemoveAll(dir) })
+	}
+	uploads = newFileStore(dir)
+	// Файлы из UPLOAD_DIR, загруженные до перезапуска, снова доступны
+	if err := uploads.load(); err != nil {
+		log.Fatal(err)
+	}
+	
+	http.HandleFunc("GET /upload", uploadPage)
+	http.HandleFunc("POST /upload", uploadSubmit)
+	http.HandleFunc("POST /api/files", uploadAPI)
+	http.HandleFunc("GET /files/{id}", downloadFile)
+	fmt.Println("curl -F file=@main.go", cfg.BaseURL()+"/upload")
+	fmt.Println("curl -F file=@main.go", cfg.BaseURL()+"/api/files", "# JSON с метаданными")
+	fmt.Println("Бенчмарк до и после: go test -run xxx -bench 'Upload|Download' -benchmem")
 }
 
 // Пример 7: JSON API с валидацией
@@ -368,19 +490,13 @@ func jsonAPIWithValidation() {
 			return
 		}
 		
-		// Проверка уникальности email
-		for _, existingUser := range users {
-			if existingUser.Email == user.Email {
-				http.Error(w, "Email уже существует", http.StatusBadRequest)
-				return
-			}
+		// Проверка уникальности email и создание - под одной блокировкой
+		user, ok := users.CreateUnique(user)
+		if !ok {
+			http.Error(w, "Email уже существует", http.StatusBadRequest)
+			return
 		}
 		
-		// Создаем пользователя
-		user.ID = nextID
-		nextID++
-		users[user.ID] = user
-		
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(user)
@@ -425,7 +541,466 @@ func staticFiles() {
 	})
 }
 
+// every вызывает fn раз в interval до отмены ctx. Фоновые очистки
+// запускаются через safego: паника в одной из них не должна ронять
+// сервер, а пропущенная очистка наверстается на следующем тике.
+func every(interval time.Duration, fn func()) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn()
+			}
+		}
+	}
+}
+
+var cleanupRestart = safego.WithRestart(time.Second, time.Minute)
+
+// secretFromEnv секрет подписи из переменной окружения name. Секрет
+// общий для всех копий сервиса и переживает перезапуск - иначе
+// выданные токены и куки станут недействительными. Если он не задан,
+// берется случайный: для примера годится, но после перезапуска все
+// подписанное им отвергается.
+func secretFromEnv(name string) []byte {
+	secret := []byte(os.Getenv(name))
+	if len(secret) >= 32 {
+		return secret
+	}
+	secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s не задан (нужно от 32 байт), используется случайный\n", name)
+	return secret
+}
+
+// Пример 9: JWT-аутентификация (см. jwt.go и auth.go)
+// Вход выдает пару токенов: короткий access для запросов и долгий
+// refresh для его обновления. Маршруты под /api/secure/ те же, что в
+// примере 2, но только с токеном, а менять и удалять можно лишь себя.
+func jwtAuthExample() {
+	fmt.Println("\n=== JWT-аутентификация ===")
+	
+	auth := newJWTAuth(clock.NewRealClock(), secretFromEnv("JWT_SECRET"), demoAccounts())
+	app.Go("jwt-cleanup", every(time.Hour, auth.deleteExpired), cleanupRestart)
+	
+	h := secureRoutes(auth)
+	for _, path := range []string{"/api/login", "/api/refresh", "/api/logout", "/api/secure/"} {
+		http.Handle(path, h)
+	}
+	
+	fmt.Println(`curl -d '{"email":"ivan@example.com","password":"ivan-password"}'`, cfg.BaseURL()+"/api/login")
+	fmt.Println(`curl -H "Authorization: Bearer $ACCESS"`, cfg.BaseURL()+"/api/secure/me")
+	fmt.Println(`curl -d "{\"refresh_token\":\"$REFRESH\"}"`, cfg.BaseURL()+"/api/refresh")
+}
+
+// Пример 10: Сессии на куках (см. session.go)
+// Тот же вход, что в примере 9, но вместо токена - кука с ID сессии,
+// а сама сессия хранится на сервере
+func sessionExample() {
+	fmt.Println("\n=== Сессии на куках ===")
+	
+	store := NewMemorySessionStore(clock.NewRealClock())
+	app.Go("session-cleanup", every(10*time.Minute, store.DeleteExpired), cleanupRestart)
+	
+	// Secure-кука не вернется от браузера по http://localhost: в
+	// production здесь true и сервер за HTTPS
+	sessions := newSessionManager(clock.NewRealClock(), store, demoAccounts(), os.Getenv("SESSION_INSECURE") == "")
+	http.Handle("/session/", sessionRoutes(sessions))
+	
+	fmt.Println(`curl -c jar -d '{"email":"ivan@example.com","password":"ivan-password"}'`, cfg.BaseURL()+"/session/login")
+	fmt.Println("curl -b jar", cfg.BaseURL()+"/session/me")
+	fmt.Println("curl -b jar -c jar -X POST", cfg.BaseURL()+"/session/logout")
+	fmt.Println("Для проверки по http без TLS: SESSION_INSECURE=1")
+}
+
+// Пример 11: Ограничение частоты запросов (см. ratelimit.go)
+func rateLimitExample() {
+	fmt.Println("\n=== Ограничение частоты запросов ===")
+	
+	rps, burst := 5.0, 10
+	if v, err := strconv.ParseFloat(os.Getenv("RATE_LIMIT_RPS"), 64); err == nil && v > 0 {
+		rps = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("RATE_LIMIT_BURST")); err == nil && v > 0 {
+		burst = v
+	}
+	limiter := newRateLimiter(clock.NewRealClock(), rps, burst)
+	app.Go("ratelimit-cleanup", every(time.Minute, limiter.deleteIdle), cleanupRestart)
+	
+	ping := rateLimitMiddleware(limiter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "pong")
+	}))
+	http.Handle("/api/ping", ping)
+	
+	// Всплеск от одного клиента: первые burst проходят, остальные - 429
+	counts := make(map[int]int)
+	var retryAfter string
+	for range burst + 5 {
+		req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+		rec := httptest.NewRecorder()
+		ping.ServeHTTP(rec, req)
+		counts[rec.Code]++
+		if rec.Code == http.StatusTooManyRequests {
+			retryAfter = rec.Header().Get("Retry-After")
+		}
+	}
+	fmt.Printf("Лимит %.0f rps, всплеск %d: 200 - %d, 429 - %d (Retry-After: %s)\n",
+		rps, burst, counts[http.StatusOK], counts[http.StatusTooManyRequests], retryAfter)
+	fmt.Println("Настройка: RATE_LIMIT_RPS и RATE_LIMIT_BURST")
+}
+
+// Пример 12: Server-Sent Events (см. events.go)
+// Изменения пользователей из примера 2 и периодический "tick" приходят
+// всем подключенным клиентам
+func eventsExample() {
+	fmt.Println("\n=== Server-Sent Events ===")
+	
+	app.Go("events-ticks", publishTicks(userEvents, 5*time.Second), cleanupRestart)
+	http.Handle("/api/events", eventsHandler(userEvents))
+	// Shutdown ждет текущие запросы, а поток событий сам не кончается:
+	// при остановке брокер отключает подписчиков
+	app.HTTP.RegisterOnShutdown(userEvents.close)
+	
+	srv := httptest.NewServer(eventsHandler(userEvents))
+	defer srv.Close()
+	connect := func(lastID uint64) (*http.Response, *bufio.Reader) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if lastID > 0 {
+			req.Header.Set("Last-Event-ID", strconv.FormatUint(lastID, 10))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return resp, bufio.NewReader(resp.Body)
+	}
+	create := func(name string) {
+		body := strings.NewReader(`{"name":"` + name + `","email":"demo@example.com"}`)
+		createUser(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/users", body))
+	}
+	
+	// Fan-out: одно событие получают оба клиента
+	respA, a := connect(0)
+	respB, b := connect(0)
+	defer respB.Body.Close()
+	create("Анна Новикова")
+	var lastA uint64
+	for _, c := range []struct {
+		name string
+		r    *bufio.Reader
+	}{{"A", a}, {"B", b}} {
+		e, err := readSSE(c.r)
+		if err != nil {
+			log.Fatal(err)
+		}
+		lastA = e.ID
+		fmt.Printf("Клиент %s: id=%d %s %s\n", c.name, e.ID, e.Type, e.Data)
+	}
+	
+	// Клиент A отключился и пропустил событие; при переподключении
+	// EventSource пришлет Last-Event-ID, и сервер дошлет пропущенное
+	respA.Body.Close()
+	create("Олег Морозов")
+	if e, err := readSSE(b); err == nil {
+		fmt.Printf("Клиент B: id=%d %s %s\n", e.ID, e.Type, e.Data)
+	}
+	respA, a = connect(lastA)
+	defer respA.Body.Close()
+	if e, err := readSSE(a); err == nil {
+		fmt.Printf("Клиент A после переподключения (Last-Event-ID: %d): id=%d %s\n", lastA, e.ID, e.Type)
+	}
+	
+	fmt.Println("curl -N", cfg.BaseURL()+"/api/events")
+}
+
+// Пример 13: Внедрение сбоев (см. chaos.go)
+// Клиент с таймаутом и повторами против сервера, который тормозит,
+// отвечает 503 и рвет соединения
+func chaosExample() {
+	fmt.Println("\n=== Внедрение сбоев ===")
+	
+	spec := os.Getenv("CHAOS")
+	if spec == "" {
+		spec = "/api latency=200ms@20 error=503@20 drop@10"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ping", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "pong")
+	})
+	handler, err := withChaos(spec, mux)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	
+	// Таймаут короче внедренной задержки: медленный ответ тоже сбой
+	client := &http.Client{Timeout: 100 * time.Millisecond}
+	get := func() string {
+		resp, err := client.Get(srv.URL + "/api/ping")
+		if err != nil {
+			if os.IsTimeout(err) {
+				return "таймаут"
+			}
+			return "обрыв"
+		}
+		defer resp.Body.Close()
+		return strconv.Itoa(resp.StatusCode)
+	}
+	
+	const n = 30
+	outcomes := make(map[string]int)
+	for range n {
+		outcomes[get()]++
+	}
+	// Обрывов среди исходов может не быть: http.Transport сам повторяет
+	// GET, если соединение из пула закрылось до ответа
+	fmt.Printf("Без повторов, %d запросов: %v\n", n, outcomes)
+	
+	// До 3 попыток на запрос: доля успешных заметно выше
+	ok := 0
+	for range n {
+		for range 3 {
+			if get() == "200" {
+				ok++
+				break
+			}
+		}
+	}
+	fmt.Printf("С повторами (до 3 попыток): успешно %d из %d\n", ok, n)
+	fmt.Println(`Свои правила: CHAOS="/api/users latency=300ms@50 error=503@10 drop@5"`)
+}
+
+// Пример 14: Проверки здоровья (см. health/)
+// /healthz - жив ли процесс, /readyz - готов ли он к запросам. Сбой
+// необязательной проверки виден в отчете, но трафик не снимает; в
+// начале остановки /readyz сразу отвечает 503.
+func healthExample() {
+	fmt.Println("\n=== Проверки здоровья ===")
+	
+	// Утечка горутин лечится перезапуском - это liveness
+	checks.Live("goroutines", health.CheckerFunc(func(context.Context) error {
+		if n := runtime.NumGoroutine(); n > 10000 {
+			return fmt.Errorf("%d горутин", n)
+		}
+		return nil
+	}))
+	checks.Ready("uploads-disk", health.DiskSpace(uploads.dir, 100<<20))
+	if cfg.DBPath != "" {
+		checks.Ready("users-db-disk", health.DiskSpace(filepath.Dir(cfg.DBPath), 10<<20))
+	}
+	client := &http.Client{}
+	if url := os.Getenv("HEALTH_DOWNSTREAM"); url != "" {
+		checks.Ready("downstream", health.HTTPGet(client, url), health.Optional())
+	}
+	http.Handle("/healthz", checks.LiveHandler())
+	http.Handle("/readyz", checks.ReadyHandler())
+	app.HTTP.RegisterOnShutdown(checks.Drain)
+	
+	// Демонстрация на отдельном Registry: соседний сервис сначала
+	// отвечает, потом падает
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	demo := health.New()
+	demo.Ready("uploads-disk", health.DiskSpace(uploads.dir, 100<<20))
+	demo.Ready("downstream", health.HTTPGet(downstream.Client(), downstream.URL+"/readyz"),
+		health.WithTimeout(200*time.Millisecond), health.Optional())
+	demo.Ready("db", health.CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done() // Зависшая база: ответа нет до таймаута
+		return ctx.Err()
+	}), health.WithTimeout(100*time.Millisecond))
+	readyz := func(title string) {
+		rec := httptest.NewRecorder()
+		demo.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		fmt.Printf("%s: %d %s", title, rec.Code, rec.Body)
+	}
+	readyz("База зависла")
+	downstream.Close()
+	
+	demo = health.New()
+	demo.Ready("downstream", health.HTTPGet(client, downstream.URL+"/readyz"), health.Optional())
+	readyz("Сосед недоступен, проверка необязательная")
+	demo.Drain()
+	readyz("Остановка")
+	
+	fmt.Println("curl", cfg.BaseURL()+"/readyz")
+	fmt.Println("Проверка соседнего сервиса: HEALTH_DOWNSTREAM=http://localhost:9090/readyz")
+}
+
+// Пример 15: Потоковый ответ (см. stream.go)
+// Одна и та же выгрузка потоком и целиком: время до первой строки,
+// остановка при отключении клиента и медленный клиент
+func streamingExample() {
+	fmt.Println("\n=== Потоковый ответ ===")
+	
+	export := newExporter()
+	http.HandleFunc("GET /api/export", export.stream)
+	http.HandleFunc("GET /api/export/buffered", export.buffered)
+	
+	finished := make(chan int, 1)
+	demo := newExporter()
+	demo.done = func(rows int, err error) { finished <- rows }
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream", demo.stream)
+	mux.HandleFunc("GET /buffered", demo.buffered)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	
+	const rows = 200000
+	get := func(ctx context.Context, path string) (*http.Response, *bufio.Reader, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?rows=%d", srv.URL, path, rows), nil)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp, bufio.NewReader(resp.Body), nil
+	}
+	
+	for _, path := range []string{"/stream", "/buffered"} {
+		start := time.Now()
+		resp, body, err := get(context.Background(), path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		body.ReadString('\n')
+		first := time.Since(start)
+		n, _ := io.Copy(io.Discard, body)
+		resp.Body.Close()
+		<-finished
+		length := resp.Header.Get("Content-Length")
+		if length == "" {
+			length = "нет, " + strings.Join(resp.TransferEncoding, ",")
+		}
+		fmt.Printf("%-10s первая строка через %-12v весь ответ %-12v %d КБ, Content-Length: %s\n",
+			path, first.Round(time.Microsecond), time.Since(start).Round(time.Millisecond), n>>10, length)
+	}
+	
+	// Клиент отключается через 20 мс: потоковый обработчик видит отмену
+	// контекста, буферизованный генерирует выгрузку до конца
+	for _, path := range []string{"/stream", "/buffered"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		if resp, body, err := get(ctx, path); err == nil {
+			io.Copy(io.Discard, body)
+			resp.Body.Close()
+		}
+		cancel()
+		fmt.Printf("%-10s клиент ушел через 20 мс, сгенерировано строк: %d из %d\n", path, <-finished, rows)
+	}
+	
+	// Медленный клиент: прочитал начало и перестал читать. Write в
+	// обработчике ждет, пока освободится буфер сокета, - генерация
+	// остановилась, лишние строки не копятся в памяти
+	before := demo.produced.Load()
+	resp, body, err := get(context.Background(), "/stream")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for range 1000 {
+		body.ReadString('\n')
+	}
+	time.Sleep(100 * time.Millisecond)
+	fmt.Printf("Клиент прочитал 1000 строк и ждет: сгенерировано %d из %d (столько вмещают буферы сокетов)\n", demo.produced.Load()-before, rows)
+	resp.Body.Close()
+	<-finished
+	
+	fmt.Println("curl -N", cfg.BaseURL()+"/api/export?rows=1000000", "| head")
+}
+
+// Пример 16: Куки (см. cookies.go)
+// Установка, чтение и удаление кук с разными атрибутами; подписанная
+// кука "plan" - клиент не может подменить ее значение
+func cookieExample() {
+	fmt.Println("\n=== Куки ===")
+	
+	// Предыдущий секрет после смены еще принимается, пока живут
+	// подписанные им куки
+	keys := [][]byte{secretFromEnv("COOKIE_SECRET")}
+	if prev := os.Getenv("COOKIE_SECRET_PREVIOUS"); prev != "" {
+		keys = append(keys, []byte(prev))
+	}
+	h := &cookieHandlers{signer: newSignedCookies(clock.NewRealClock(), keys...), secure: os.Getenv("SESSION_INSECURE") == ""}
+	routes := cookieRoutes(h)
+	http.Handle("/cookies", routes)
+	http.Handle("/cookies/", routes)
+	
+	// Демонстрация без Secure: httptest.Server работает по http, и
+	// cookiejar не вернул бы Secure-куки
+	srv := httptest.NewServer(cookieRoutes(&cookieHandlers{signer: h.signer}))
+	defer srv.Close()
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	do := func(method, path string) *http.Response {
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	list := func(title string, header http.Header) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/cookies", nil)
+		if header != nil {
+			req.Header = header
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		fmt.Printf("%s: %s", title, body)
+	}
+	
+	for _, v := range []struct{ name, value string }{{"theme", "dark"}, {"plan", "free"}, {"consent", "analytics"}} {
+		resp := do(http.MethodPost, "/cookies/"+v.name+"?value="+v.value)
+		fmt.Println("Set-Cookie:", resp.Header.Get("Set-Cookie"))
+	}
+	list("GET /cookies", nil)
+	
+	// Клиент правит куку руками: значение "plan" в base64url - первая
+	// часть до точки, подпись остается старой
+	u, _ := url.Parse(srv.URL)
+	var plan string
+	for _, c := range jar.Cookies(u) {
+		if c.Name == "plan" {
+			plan = c.Value
+		}
+	}
+	_, rest, _ := strings.Cut(plan, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte("pro")) + "." + rest
+	list("Подмененный plan", http.Header{"Cookie": {"theme=dark; plan=" + forged}})
+	
+	do(http.MethodDelete, "/cookies/theme")
+	list("После DELETE /cookies/theme", nil)
+	
+	fmt.Println("curl -i -X POST", cfg.BaseURL()+"/cookies/plan?value=free")
+	fmt.Println("Для проверки по http без TLS: SESSION_INSECURE=1; смена секрета: COOKIE_SECRET и COOKIE_SECRET_PREVIOUS")
+}
+
 func main() {
+	serve := flag.Bool("serve", false, "после примеров обслуживать их маршруты на -addr до Ctrl+C или SIGTERM")
+	var err error
+	if cfg, err = config.Load(flag.CommandLine, os.Args[1:], os.Getenv); err != nil {
+		log.Fatal(err)
+	}
+	logger = cfg.Logger(os.Stderr)
+	slog.SetDefault(logger)
+	app = graceful.New(cfg.Server(loggingMiddleware(logger, http.DefaultServeMux)))
+	app.ShutdownTimeout = cfg.ShutdownTimeout
+	if cfg.DBPath != "" {
+		if err := loadUsers(cfg.DBPath); err != nil {
+			log.Fatal(err)
+		}
+		// После остановки HTTP: ни один обработчик уже не меняет users
+		app.OnClose("users-db", func(context.Context) error { return saveUsers(cfg.DBPath) })
+	}
+	
 	basicHTTPServer()
 	userAPI()
 	middlewareExample()
@@ -434,7 +1009,30 @@ func main() {
 	fileUpload()
 	jsonAPIWithValidation()
 	staticFiles()
+	jwtAuthExample()
+	sessionExample()
+	rateLimitExample()
+	eventsExample()
+	chaosExample()
+	healthExample()
+	streamingExample()
+	cookieExample()
 	
 	fmt.Println("\n=== Все примеры HTTP серверов ===")
-	fmt.Println("Для запуска конкретного примера раскомментируйте соответствующий код в функции main")
+	if !*serve {
+		fmt.Println("Для запуска сервера со всеми примерами: go run . -serve")
+		return
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// После первого сигнала обработка снимается: второй Ctrl+C завершит
+	// процесс сразу, не дожидаясь зависшего запроса
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if err := app.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,238 @@
+// Package openapi - документ OpenAPI 3 из описания маршрутов на Go:
+//
+//	spec := openapi.New("Users API", "1.0.0")
+//	spec.Add(openapi.Operation{
+//		Method: http.MethodGet, Path: "/api/users/{id}",
+//		Summary: "Пользователь по ID",
+//		Params:  []openapi.Param{{Name: "id", In: openapi.InPath, Type: 0}},
+//		Responses: map[int]openapi.Response{
+//			200: {Description: "Пользователь", Body: User{}},
+//			404: {Description: "Не найден", Body: openapi.PlainText},
+//		},
+//	})
+//	http.Handle("/api/openapi.json", spec.Handler())
+//	http.Handle("/api/docs", openapi.UI("/api/openapi.json"))
+//
+// Схемы тел строятся по типам Go через reflect (см. schema.go): поля и
+// их имена берутся из тегов json, так что документация меняется вместе
+// со структурой, а не отдельно от нее. Описание поля - тег doc.
+//
+// Генерировать документ из кода или код из документа - выбор проекта.
+// Здесь первое: маршрут и его описание лежат рядом, и тест может
+// проверить, что описаны все маршруты. Обратный путь (oapi-codegen,
+// openapi-generator) удобнее, когда API сначала согласуют с другими
+// командами, а потом пишут.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Где передается параметр
+const (
+	InPath  = "path"
+	InQuery = "query"
+)
+
+// Param параметр пути или строки запроса
+type Param struct {
+	Name        string
+	In          string // InPath или InQuery
+	Description string
+	Required    bool     // Параметры пути обязательны всегда
+	Type        any      // Значение нужного типа: 0, "", false
+	Enum        []string // Допустимые значения, если их конечное число
+}
+
+// Response ответ с кодом статуса
+type Response struct {
+	Description string
+	Body        any // Значение типа тела; nil - ответ без тела
+}
+
+// PlainText тело ответа - обычный текст, как у http.Error
+var PlainText = plainText{}
+
+type plainText struct{}
+
+// Operation описание одного маршрута
+type Operation struct {
+	Method      string
+	Path        string // Шаблон с {параметрами}, как у router
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []Param
+	Request     any // Значение типа тела запроса; nil - без тела
+	Responses   map[int]Response
+}
+
+// Spec собирает документ по мере добавления операций. Операции
+// добавляются при старте, до Handler; конкурентный Add не поддерживается.
+type Spec struct {
+	doc     document
+	schemas *schemaRegistry
+}
+
+// New создает пустой документ
+func New(title, version string) *Spec {
+	s := &Spec{
+		doc: document{
+			OpenAPI: "3.0.3",
+			Info:    info{Title: title, Version: version},
+			Paths:   map[string]map[string]*operationObject{},
+		},
+		schemas: newSchemaRegistry(),
+	}
+	s.doc.Components.Schemas = s.schemas.schemas
+	return s
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Add добавляет операцию. Параметр пути без описания, повторная
+// операция или тип, который не выразить схемой, - ошибки программиста,
+// поэтому паника, как у router.Handle.
+func (s *Spec) Add(op Operation) {
+	where := op.Method + " " + op.Path
+	method := strings.ToLower(op.Method)
+	if s.doc.Paths[op.Path][method] != nil {
+		panic(fmt.Sprintf("openapi: %s добавлена дважды", where))
+	}
+	
+	o := &operationObject{
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   map[string]*responseObject{},
+	}
+	for _, m := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		if !slices.ContainsFunc(op.Params, func(p Param) bool { return p.In == InPath && p.Name == m[1] }) {
+			panic(fmt.Sprintf("openapi: %s: не описан параметр пути {%s}", where, m[1]))
+		}
+	}
+	for _, p := range op.Params {
+		if p.In == InPath && !strings.Contains(op.Path, "{"+p.Name+"}") {
+			panic(fmt.Sprintf("openapi: %s: параметра пути %s нет в шаблоне", where, p.Name))
+		}
+		schema := s.schema(where, p.Type)
+		for _, v := range p.Enum {
+			schema.Enum = append(schema.Enum, v)
+		}
+		o.Parameters = append(o.Parameters, &parameterObject{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.Required || p.In == InPath,
+			Schema:      schema,
+		})
+	}
+	if op.Request != nil {
+		o.RequestBody = &requestBodyObject{Required: true, Content: s.content(where, op.Request)}
+	}
+	for status, r := range op.Responses {
+		resp := &responseObject{Description: r.Description}
+		if r.Body != nil {
+			resp.Content = s.content(where, r.Body)
+		}
+		o.Responses[strconv.Itoa(status)] = resp
+	}
+	
+	if s.doc.Paths[op.Path] == nil {
+		s.doc.Paths[op.Path] = map[string]*operationObject{}
+	}
+	s.doc.Paths[op.Path][method] = o
+}
+
+func (s *Spec) schema(where string, v any) *schemaObject {
+	if v == nil {
+		return &schemaObject{Type: "string"}
+	}
+	schema, err := s.schemas.schemaFor(reflect.TypeOf(v))
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %s: %v", where, err))
+	}
+	return schema
+}
+
+func (s *Spec) content(where string, body any) map[string]*mediaType {
+	if _, ok := body.(plainText); ok {
+		return map[string]*mediaType{"text/plain": {Schema: &schemaObject{Type: "string"}}}
+	}
+	return map[string]*mediaType{"application/json": {Schema: s.schema(where, body)}}
+}
+
+// Handler отдает документ в JSON. Документ сериализуется один раз:
+// после старта он не меняется.
+func (s *Spec) Handler() http.Handler {
+	data, err := json.MarshalIndent(s.doc, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// Swagger UI с другого адреса (editor.swagger.io) тоже сможет
+		// загрузить документ
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Write(data)
+	})
+}
+
+// document документ OpenAPI 3. Поля - подмножество спецификации,
+// которого хватает для REST API с JSON.
+type document struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       info                                   `json:"info"`
+	Paths      map[string]map[string]*operationObject `json:"paths"`
+	Components struct {
+		Schemas map[string]*schemaObject `json:"schemas,omitempty"`
+	} `json:"components"`
+}
+
+type info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type operationObject struct {
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []*parameterObject         `json:"parameters,omitempty"`
+	RequestBody *requestBodyObject         `json:"requestBody,omitempty"`
+	Responses   map[string]*responseObject `json:"responses"`
+}
+
+type parameterObject struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Schema      *schemaObject `json:"schema"`
+}
+
+type requestBodyObject struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type responseObject struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schemaObject `json:"schema"`
+}
\ No newline at end of file
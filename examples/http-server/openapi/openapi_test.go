@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type base struct {
+	ID int `json:"id" doc:"Идентификатор"`
+}
+
+type node struct {
+	Value    string  `json:"value"`
+	Children []*node `json:"children,omitempty"`
+}
+
+type sample struct {
+	base
+	Name      string            `json:"name"`
+	Nick      *string           `json:"nick"`
+	Tags      []string          `json:"tags,omitempty"`
+	Address   address           `json:"address"`
+	Meta      map[string]int    `json:"meta,omitempty"`
+	Raw       []byte            `json:"raw,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+	Plain     bool              // Без тега: имя поля
+	Any       any               `json:"any,omitempty"`
+	hidden    int               // Неэкспортируемое: пропускается
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(data)
+}
+
+func TestSchemaFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"строка", "", `{"type":"string"}`},
+		{"int", 0, `{"type":"integer","format":"int64"}`},
+		{"int32", int32(0), `{"type":"integer","format":"int32"}`},
+		{"float64", 0.0, `{"type":"number","format":"double"}`},
+		{"bool", false, `{"type":"boolean"}`},
+		{"время", time.Time{}, `{"type":"string","format":"date-time"}`},
+		{"указатель", new(int), `{"type":"integer","format":"int64","nullable":true}`},
+		{"срез", []string{}, `{"type":"array","items":{"type":"string"}}`},
+		{"байты", []byte{}, `{"type":"string","format":"byte"}`},
+		{"map", map[string]bool{}, `{"type":"object","additionalProperties":{"type":"boolean"}}`},
+		{"структура", address{}, `{"$ref":"#/components/schemas/address"}`},
+		{"анонимная структура", struct {
+			A int `json:"a"`
+		}{}, `{"type":"object","properties":{"a":{"type":"integer","format":"int64"}},"required":["a"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newSchemaRegistry().schemaFor(reflect.TypeOf(tt.value))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := toJSON(t, s); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSchemaFor_Struct(t *testing.T) {
+	r := newSchemaRegistry()
+	if _, err := r.schemaFor(reflect.TypeFor[sample]()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := r.schemas["sample"]
+	if s == nil {
+		t.Fatal("Expected sample in components")
+	}
+	
+	var props []string
+	for k := range s.Properties {
+		props = append(props, k)
+	}
+	slices.Sort(props)
+	expected := []string{"Plain", "address", "any", "created_at", "id", "labels", "meta", "name", "nick", "raw", "tags"}
+	if !slices.Equal(props, expected) {
+		t.Errorf("Expected properties %v, got %v", expected, props)
+	}
+	// omitempty и указатели необязательны
+	required := []string{"id", "name", "address", "created_at", "Plain"}
+	if !slices.Equal(s.Required, required) {
+		t.Errorf("Expected required %v, got %v", required, s.Required)
+	}
+	if s.Properties["id"].Description != "Идентификатор" {
+		t.Errorf("Expected doc tag in description, got %q", s.Properties["id"].Description)
+	}
+	if r.schemas["address"] == nil {
+		t.Error("Expected nested struct in components")
+	}
+}
+
+func TestSchemaFor_Recursive(t *testing.T) {
+	r := newSchemaRegistry()
+	if _, err := r.schemaFor(reflect.TypeFor[node]()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := r.schemas["node"].Properties["children"].Items.Ref; got != "#/components/schemas/node" {
+		t.Errorf("Expected self reference, got %q", got)
+	}
+}
+
+func TestSchemaFor_Unsupported(t *testing.T) {
+	for _, v := range []any{make(chan int), map[int]string{}, struct{ F func() }{}} {
+		if _, err := newSchemaRegistry().schemaFor(reflect.TypeOf(v)); err == nil {
+			t.Errorf("Expected error for %T", v)
+		}
+	}
+}
+
+func newTestSpec() *Spec {
+	spec := New("Test API", "1.0.0")
+	spec.Add(Operation{
+		Method: http.MethodGet, Path: "/items/{id}", Summary: "Элемент",
+		Params: []Param{
+			{Name: "id", In: InPath, Type: 0},
+			{Name: "sort", In: InQuery, Type: "", Enum: []string{"a", "b"}},
+		},
+		Responses: map[int]Response{
+			200: {Description: "Элемент", Body: sample{}},
+			404: {Description: "Нет", Body: PlainText},
+		},
+	})
+	spec.Add(Operation{
+		Method: http.MethodPost, Path: "/items", Request: address{},
+		Responses: map[int]Response{204: {Description: "Создан"}},
+	})
+	return spec
+}
+
+func TestSpec_Handler(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestSpec().Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+	
+	var doc map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("Expected openapi 3.0.3, got %v", doc["openapi"])
+	}
+	body := w.Body.String()
+	for _, s := range []string{
+		`"/items/{id}"`, `"in": "path"`, `"required": true`, `"enum": [`,
+		`"text/plain"`, `"$ref": "#/components/schemas/sample"`, `"requestBody"`, `"204"`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected document to contain %s", s)
+		}
+	}
+	
+	w = httptest.NewRecorder()
+	newTestSpec().Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/openapi.json", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", w.Code)
+	}
+}
+
+func TestSpec_AddPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []Operation
+	}{
+		{"параметр пути не описан", []Operation{{Method: "GET", Path: "/items/{id}"}}},
+		{"лишний параметр пути", []Operation{{Method: "GET", Path: "/items", Params: []Param{{Name: "id", In: InPath, Type: 0}}}}},
+		{"повтор", []Operation{{Method: "GET", Path: "/items"}, {Method: "GET", Path: "/items"}}},
+		{"тип без схемы", []Operation{{Method: "POST", Path: "/items", Request: make(chan int)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic")
+				}
+			}()
+			spec := New("Test", "1")
+			for _, op := range tt.ops {
+				spec.Add(op)
+			}
+		})
+	}
+}
+
+func TestUI(t *testing.T) {
+	w := httptest.NewRecorder()
+	UIWithAssets("/api/openapi.json", "/static/swagger").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	body := w.Body.String()
+	for _, s := range []string{`url: "/api/openapi.json"`, `src="/static/swagger/swagger-ui-bundle.js"`} {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected page to contain %s, got:\n%s", s, body)
+		}
+	}
+}
\ No newline at end of file
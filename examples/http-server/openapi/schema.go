@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schemaObject схема JSON-значения в терминах OpenAPI 3.0
+type schemaObject struct {
+	Ref                  string                   `json:"$ref,omitempty"`
+	Type                 string                   `json:"type,omitempty"`
+	Format               string                   `json:"format,omitempty"`
+	Description          string                   `json:"description,omitempty"`
+	Nullable             bool                     `json:"nullable,omitempty"`
+	Enum                 []any                    `json:"enum,omitempty"`
+	Items                *schemaObject            `json:"items,omitempty"`
+	Properties           map[string]*schemaObject `json:"properties,omitempty"`
+	Required             []string                 `json:"required,omitempty"`
+	AdditionalProperties *schemaObject            `json:"additionalProperties,omitempty"`
+}
+
+// schemaRegistry именованные схемы из components/schemas. Структура
+// описывается один раз, а везде, где она встречается, ставится $ref -
+// так документ короче, а Swagger UI показывает тип по имени.
+type schemaRegistry struct {
+	schemas map[string]*schemaObject
+	types   map[string]reflect.Type
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*schemaObject{}, types: map[string]reflect.Type{}}
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// schemaFor схема для типа t по тем же правилам, по которым его
+// сериализует encoding/json
+func (r *schemaRegistry) schemaFor(t reflect.Type) (*schemaObject, error) {
+	if t == timeType {
+		return &schemaObject{Type: "string", Format: "date-time"}, nil
+	}
+	
+	switch t.Kind() {
+	case reflect.Pointer:
+		s, err := r.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		// В OpenAPI 3.0 поля рядом с $ref игнорируются: у ссылки на
+		// структуру nullable не выразить без allOf, и здесь его нет
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s, nil
+	case reflect.Bool:
+		return &schemaObject{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &schemaObject{Type: "integer", Format: "int64"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &schemaObject{Type: "integer", Format: "int32"}, nil
+	case reflect.Float32:
+		return &schemaObject{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &schemaObject{Type: "number", Format: "double"}, nil
+	case reflect.String:
+		return &schemaObject{Type: "string"}, nil
+	case reflect.Interface:
+		// Любое значение
+		return &schemaObject{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte encoding/json пишет строкой base64
+			return &schemaObject{Type: "string", Format: "byte"}, nil
+		}
+		items, err := r.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &schemaObject{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("ключ map %s не строка", t)
+		}
+		values, err := r.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &schemaObject{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return r.structSchema(t)
+	default:
+		return nil, fmt.Errorf("тип %s не сериализуется в JSON", t)
+	}
+}
+
+// structSchema ссылка на именованную структуру или сама схема анонимной
+func (r *schemaRegistry) structSchema(t reflect.Type) (*schemaObject, error) {
+	name := t.Name()
+	if name == "" {
+		return r.objectSchema(t)
+	}
+	ref := &schemaObject{Ref: "#/components/schemas/" + name}
+	if known, ok := r.types[name]; ok {
+		if known != t {
+			return nil, fmt.Errorf("два типа с именем %s: %s и %s", name, known.PkgPath(), t.PkgPath())
+		}
+		return ref, nil
+	}
+	// Тип регистрируется до разбора полей: иначе рекурсивная структура
+	// (дерево, список) разбиралась бы бесконечно
+	r.types[name] = t
+	s, err := r.objectSchema(t)
+	if err != nil {
+		delete(r.types, name)
+		return nil, err
+	}
+	r.schemas[name] = s
+	return ref, nil
+}
+
+// objectSchema поля структуры: имя из тега json, omitempty и указатели
+// необязательны, встроенные структуры без тега раскрываются в родителя
+func (r *schemaRegistry) objectSchema(t reflect.Type) (*schemaObject, error) {
+	s := &schemaObject{Type: "object", Properties: map[string]*schemaObject{}}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner, err := r.objectSchema(embedded)
+				if err != nil {
+					return nil, err
+				}
+				for k, v := range inner.Properties {
+					s.Properties[k] = v
+				}
+				s.Required = append(s.Required, inner.Required...)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		
+		fs, err := r.schemaFor(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		if doc := f.Tag.Get("doc"); doc != "" && fs.Ref == "" {
+			fs.Description = doc
+		}
+		s.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
\ No newline at end of file
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"net/http"
+)
+
+// Страница Swagger UI встроена в бинарник, а сам Swagger UI (скрипт и
+// стили, около 1.5 МБ) грузится с CDN. Для работы без интернета
+// скачайте swagger-ui-dist, отдайте его через http.FileServer и
+// передайте его адрес в UIAssets.
+
+//go:embed ui.html
+var uiHTML string
+
+var uiTemplate = template.Must(template.New("ui").Parse(uiHTML))
+
+// UIAssets адрес swagger-ui-dist по умолчанию
+const UIAssets = "https://unpkg.com/swagger-ui-dist@5"
+
+// UI отдает страницу Swagger UI для документа по адресу specURL
+func UI(specURL string) http.Handler {
+	return UIWithAssets(specURL, UIAssets)
+}
+
+// UIWithAssets то же, что UI, но со своим адресом swagger-ui-dist
+func UIWithAssets(specURL, assets string) http.Handler {
+	var buf bytes.Buffer
+	err := uiTemplate.Execute(&buf, map[string]string{
+		"Title":   "Документация API",
+		"SpecURL": specURL,
+		"Assets":  assets,
+	})
+	if err != nil {
+		panic(err)
+	}
+	page := buf.Bytes()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HTML-страницы на html/template. В отличие от text/template он знает
+// контекст вставки и экранирует значения по нему: имя пользователя
+// <script> в тексте станет &lt;script&gt;, в атрибуте href - безопасной
+// ссылкой. Склеивать HTML из строк, как раньше в примерах 5 и 6, можно
+// только пока в нем нет пользовательских данных.
+//
+//	templates/layout.html   - каркас страницы, вызывает "nav" и "content"
+//	templates/partials.html - общие куски: "nav", "field", "userRow"
+//	templates/<page>.html   - блок "content" страницы
+//
+// Шаблоны встроены в бинарник: запуск не зависит от рабочего каталога.
+//
+//go:embed templates
+var templateFS embed.FS
+
+// templateFuncs функции, доступные в шаблонах. Добавляются до разбора:
+// шаблон с неизвестной функцией не разберется.
+var templateFuncs = template.FuncMap{
+	"plural":   plural,
+	"initials": initials,
+	"bytes":    formatBytes,
+	"field":    newField,
+}
+
+// pageNames страницы; каждая разбирается вместе с layout и partials в
+// отдельный набор, иначе блоки "content" разных страниц перетирали бы
+// друг друга
+var pageNames = []string{"users", "form", "upload"}
+
+// pages разобранные шаблоны. Ошибка в шаблоне роняет программу при
+// старте, а не на первом запросе к странице.
+var pages = mustParsePages()
+
+func mustParsePages() map[string]*template.Template {
+	pages := make(map[string]*template.Template)
+	for _, name := range pageNames {
+		t, err := template.New(name).Funcs(templateFuncs).ParseFS(templateFS,
+			"templates/layout.html", "templates/partials.html", "templates/"+name+".html")
+		if err != nil {
+			log.Fatalf("Шаблон %s: %v", name, err)
+		}
+		pages[name] = t
+	}
+	return pages
+}
+
+// pageData данные для любой страницы; каждая берет свои поля
+type pageData struct {
+	Title     string
+	Users     []User
+	Form      User
+	Errors    map[string]string
+	Upload    *uploadInfo
+	CSRFToken string // Скрытое поле формы (см. csrf.go)
+}
+
+// uploadInfo загруженный файл: на странице, в ответе POST /api/files и
+// в файле метаданных рядом с содержимым (см. upload.go)
+type uploadInfo struct {
+	ID          string    `json:"id"`
+	Filename    string    `json:"filename"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	Created     time.Time `json:"created"`
+}
+
+// render выполняет шаблон в буфер и только потом пишет ответ: ошибка
+// посреди шаблона дает 500, а не полстраницы со статусом 200
+func render(w http.ResponseWriter, status int, name string, data pageData) {
+	var buf bytes.Buffer
+	if err := pages[name].ExecuteTemplate(&buf, "layout", data); err != nil {
+		log.Printf("Шаблон %s: %v", name, err)
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// usersPage список пользователей, GET /users
+func usersPage(w http.ResponseWriter, r *http.Request) {
+	// List уже отсортирован по ID: порядок обхода map случаен, и без
+	// сортировки строки прыгали бы между обновлениями страницы
+	render(w, http.StatusOK, "users", pageData{Title: "Пользователи", Users: users.List()})
+}
+
+// userFormPage форма добавления, GET /form
+func userFormPage(w http.ResponseWriter, r *http.Request) {
+	render(w, http.StatusOK, "form", pageData{Title: "Добавить пользователя", CSRFToken: csrfTokenFrom(r)})
+}
+
+// userFormSubmit добавление из формы, POST /form. При ошибках форма
+// показывается снова с введенными значениями и сообщениями у полей.
+// CSRF-токен проверяет csrfMiddleware до обработчика.
+func userFormSubmit(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Ошибка разбора формы", http.StatusBadRequest)
+		return
+	}
+	form := User{
+		Name:  strings.TrimSpace(r.PostFormValue("name")),
+		Email: strings.TrimSpace(r.PostFormValue("email")),
+	}
+	errs := make(map[string]string)
+	if form.Name == "" {
+		errs["name"] = "Имя обязательно"
+	}
+	if !strings.Contains(form.Email, "@") {
+		errs["email"] = "Неверный формат email"
+	}
+	if len(errs) > 0 {
+		render(w, http.StatusUnprocessableEntity, "form",
+			pageData{Title: "Добавить пользователя", Form: form, Errors: errs, CSRFToken: csrfTokenFrom(r)})
+		return
+	}
+	
+	form = users.Create(form)
+	userEvents.publish("user_created", form)
+	// Post/Redirect/Get: обновление страницы не отправит форму повторно
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// uploadPage форма загрузки, GET /upload
+func uploadPage(w http.ResponseWriter, r *http.Request) {
+	render(w, http.StatusOK, "upload", pageData{Title: "Загрузить файл"})
+}
+
+// uploadSubmit прием файла, POST /upload. Файл пишется на диск потоком
+// (см. upload.go). Имя файла приходит от клиента и выводится через
+// шаблон - экранированным.
+func uploadSubmit(w http.ResponseWriter, r *http.Request) {
+	info, ok := receiveUpload(w, r)
+	if !ok {
+		return
+	}
+	render(w, http.StatusOK, "upload", pageData{Title: "Загрузить файл", Upload: &info})
+}
+
+// plural выбирает форму слова для числа n: 1 пользователь,
+// 2 пользователя, 5 пользователей, 21 пользователь
+func plural(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
+
+// initials первые буквы слов имени: "Иван Иванов" -> "ИИ"
+func initials(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Fields(name) {
+		r := []rune(word)[0]
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// formatBytes размер в читаемом виде: 1536 -> "1.5 КБ"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d Б", n)
+	}
+	value, i := float64(n)/unit, 0
+	for value >= unit && i < 2 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, []string{"КБ", "МБ", "ГБ"}[i])
+}
+
+// field аргумент шаблона "field". Шаблон принимает одно значение, поэтому
+// несколько параметров собираются в структуру функцией.
+type field struct {
+	Name, Label, Type, Value, Error string
+}
+
+func newField(name, label, typ, value, err string) field {
+	return field{Name: name, Label: label, Type: typ, Value: value, Error: err}
+}
\ No newline at end of file
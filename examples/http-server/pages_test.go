@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "пользователей"},
+		{1, "пользователь"},
+		{2, "пользователя"},
+		{5, "пользователей"},
+		{11, "пользователей"},
+		{14, "пользователей"},
+		{21, "пользователь"},
+		{104, "пользователя"},
+		{111, "пользователей"},
+	}
+	for _, tt := range tests {
+		if got := plural(tt.n, "пользователь", "пользователя", "пользователей"); got != tt.expected {
+			t.Errorf("plural(%d): expected %s, got %s", tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	if got := initials("иван  Иванов"); got != "ИИ" {
+		t.Errorf("Expected ИИ, got %s", got)
+	}
+	if got := initials(""); got != "" {
+		t.Errorf("Expected empty initials, got %q", got)
+	}
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{512, "512 Б"},
+		{1536, "1.5 КБ"},
+		{5 << 20, "5.0 МБ"},
+		{3 << 40, "3072.0 ГБ"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.expected {
+			t.Errorf("formatBytes(%d): expected %s, got %s", tt.n, tt.expected, got)
+		}
+	}
+}
+
+// withUsers подменяет хранилище на время теста
+func withUsers(t *testing.T, list ...User) {
+	t.Helper()
+	saved := users
+	users = newUserStore(list...)
+	t.Cleanup(func() { users = saved })
+}
+
+func TestUsersPage(t *testing.T) {
+	withUsers(t,
+		User{ID: 1, Name: "Иван Иванов", Email: "ivan@example.com"},
+		User{ID: 2, Name: "<script>alert(1)</script>", Email: "x@example.com"},
+	)
+	w := httptest.NewRecorder()
+	usersPage(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	
+	body := w.Body.String()
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	for _, want := range []string{
+		"<title>Пользователи</title>",
+		"2 пользователя",
+		`<a href="mailto:ivan@example.com">`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected page to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "<script>") {
+		t.Error("Expected user name to be escaped")
+	}
+	// Строки по возрастанию ID
+	if strings.Index(body, "Иван Иванов") > strings.Index(body, "alert") {
+		t.Error("Expected users sorted by ID")
+	}
+}
+
+func TestUsersPage_Empty(t *testing.T) {
+	withUsers(t)
+	w := httptest.NewRecorder()
+	usersPage(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if !strings.Contains(w.Body.String(), "Пользователей пока нет") {
+		t.Errorf("Expected empty state, got %s", w.Body.String())
+	}
+}
+
+func TestUserFormSubmit(t *testing.T) {
+	tests := []struct {
+		name     string
+		form     url.Values
+		expected int
+		contains []string
+		created  bool
+	}{
+		{
+			name:     "успех",
+			form:     url.Values{"name": {"Анна"}, "email": {"anna@example.com"}},
+			expected: http.StatusSeeOther,
+			created:  true,
+		},
+		{
+			name:     "пустое имя",
+			form:     url.Values{"name": {"  "}, "email": {"anna@example.com"}},
+			expected: http.StatusUnprocessableEntity,
+			contains: []string{"Имя обязательно", `value="anna@example.com"`},
+		},
+		{
+			name:     "неверный email",
+			form:     url.Values{"name": {`"><b>`}, "email": {"anna"}},
+			expected: http.StatusUnprocessableEntity,
+			contains: []string{"Неверный формат email", `value="&#34;&gt;&lt;b&gt;"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withUsers(t)
+			req := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			userFormSubmit(w, req)
+			
+			if w.Code != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, w.Code)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("Expected page to contain %q", want)
+				}
+			}
+			if created := users.Len() == 1; created != tt.created {
+				t.Errorf("Expected created=%v, got %v", tt.created, created)
+			}
+			if tt.created && w.Header().Get("Location") != "/users" {
+				t.Errorf("Expected redirect to /users, got %s", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestUploadSubmit(t *testing.T) {
+	withUploads(t)
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "<img src=x>.txt")
+	part.Write(bytes.Repeat([]byte("a"), 2048))
+	mw.Close()
+	
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	uploadSubmit(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	page := w.Body.String()
+	if !strings.Contains(page, "&lt;img src=x&gt;.txt") || strings.Contains(page, "<img") {
+		t.Error("Expected escaped filename")
+	}
+	if !strings.Contains(page, "2.0 КБ") {
+		t.Error("Expected formatted size")
+	}
+}
+
+func TestPagesParsed(t *testing.T) {
+	for _, name := range pageNames {
+		w := httptest.NewRecorder()
+		render(w, http.StatusOK, name, pageData{Title: name})
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", name, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), `<a href="/users">`) {
+			t.Errorf("%s: expected nav partial", name)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"httpserver/cursor"
+)
+
+// Список пользователей GET /api/users с фильтром, сортировкой и
+// постраничной выдачей:
+//
+//	?q=иван        подстрока имени или email без учета регистра
+//	?sort=name     id (по умолчанию), name или email
+//	?limit=20      размер страницы, до maxLimit
+//	?page=2        номер страницы, с 1
+//	?cursor=...    курсор из next_cursor вместо page (см. cursor/)
+//
+// Ответ - конверт со страницей, числом подходящих записей и ссылками:
+//
+//	{"users": [...], "total": 42, "page": 2, "limit": 20,
+//	 "next": "/api/users?limit=20&page=3", "prev": "/api/users?limit=20&page=1",
+//	 "next_cursor": "eyJrIjp7..."}
+//
+// Порядок всегда полный: при равных name или email записи идут по ID.
+// Иначе порядок равных зависел бы от обхода map, и запись могла бы
+// попасть на две страницы сразу или ни на одну.
+//
+// page проще (можно перейти сразу на 5-ю страницу), но страницы
+// сдвигаются, если между запросами добавили или удалили запись выше.
+// Курсор продолжает ровно с последней выданной записи.
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// userSorts допустимые значения ?sort
+var userSorts = []string{"id", "name", "email"}
+
+// userCursors курсоры живут час: достаточно, чтобы пролистать список, и
+// не настолько долго, чтобы сохраненная ссылка пережила смену формата
+var userCursors = cursor.New(cursorSecret(), time.Hour)
+
+// cursorSecret секрет подписи курсоров. Как и JWT_SECRET, он должен быть
+// общим для всех копий сервиса: курсор, выданный одной копией, приходит
+// за следующей страницей на другую.
+func cursorSecret() []byte {
+	if s := os.Getenv("CURSOR_SECRET"); len(s) >= 32 {
+		return []byte(s)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal(err)
+	}
+	return secret
+}
+
+// userKey ключ последней выданной записи. Сортировка и фильтр входят в
+// курсор: продолжать список по другому порядку с этого ключа бессмысленно.
+type userKey struct {
+	Sort  string `json:"s"`
+	Q     string `json:"q,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+// pagedUsers страница пользователей. Теги doc - описание полей в
+// документе OpenAPI (см. apidoc.go).
+type pagedUsers struct {
+	Users      []User `json:"users"`
+	Total      int    `json:"total" doc:"Число записей, подходящих под фильтр"`
+	Page       int    `json:"page,omitempty" doc:"Номер страницы; нет при выдаче по курсору"`
+	Limit      int    `json:"limit"`
+	Next       string `json:"next,omitempty" doc:"Ссылка на следующую страницу"`
+	Prev       string `json:"prev,omitempty" doc:"Ссылка на предыдущую страницу"`
+	NextCursor string `json:"next_cursor,omitempty" doc:"Курсор для параметра cursor"`
+}
+
+// userQuery разобранные параметры списка
+type userQuery struct {
+	path  string // Путь для ссылок: список отдают /api/users и /api/secure/users
+	q     string
+	sort  string
+	limit int
+	page  int      // 0 при выдаче по курсору
+	after *userKey // nil при выдаче по номеру страницы
+}
+
+// parseUserQuery разбирает и проверяет параметры списка
+func parseUserQuery(v url.Values) (userQuery, error) {
+	uq := userQuery{
+		q:     strings.ToLower(strings.TrimSpace(v.Get("q"))),
+		sort:  cmp.Or(v.Get("sort"), "id"),
+		limit: defaultLimit,
+		page:  1,
+	}
+	if len(uq.q) > 100 {
+		return uq, errors.New("слишком длинный запрос")
+	}
+	if !slices.Contains(userSorts, uq.sort) {
+		return uq, fmt.Errorf("sort должен быть одним из %s", strings.Join(userSorts, ", "))
+	}
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxLimit {
+			return uq, fmt.Errorf("limit должен быть от 1 до %d", maxLimit)
+		}
+		uq.limit = n
+	}
+	if s := v.Get("page"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return uq, errors.New("page должен быть положительным числом")
+		}
+		uq.page = n
+	}
+	
+	c := v.Get("cursor")
+	if c == "" {
+		return uq, nil
+	}
+	if v.Has("page") {
+		return uq, errors.New("нужен либо page, либо cursor")
+	}
+	var key userKey
+	switch err := userCursors.Decode(c, &key); {
+	case errors.Is(err, cursor.ErrExpired):
+		return uq, errors.New("курсор устарел, начните с первой страницы")
+	case err != nil:
+		return uq, errors.New("неверный курсор")
+	}
+	if key.Sort != uq.sort || key.Q != uq.q {
+		return uq, errors.New("курсор выдан для другой сортировки или фильтра")
+	}
+	uq.page, uq.after = 0, &key
+	return uq, nil
+}
+
+// sortValue значение поля сортировки; для id пустое - сравнивается ID
+func sortValue(u User, sort string) string {
+	switch sort {
+	case "name":
+		return strings.ToLower(u.Name)
+	case "email":
+		return strings.ToLower(u.Email)
+	default:
+		return ""
+	}
+}
+
+// compareKeys полный порядок: поле сортировки, при равенстве - ID
+func compareKeys(aValue string, aID int, bValue string, bID int) int {
+	return cmp.Or(strings.Compare(aValue, bValue), cmp.Compare(aID, bID))
+}
+
+// link ссылка на страницу с теми же фильтром и сортировкой
+func (uq userQuery) link(page int, c string) string {
+	v := url.Values{"limit": {strconv.Itoa(uq.limit)}}
+	if uq.q != "" {
+		v.Set("q", uq.q)
+	}
+	if uq.sort != "id" {
+		v.Set("sort", uq.sort)
+	}
+	if c != "" {
+		v.Set("cursor", c)
+	} else {
+		v.Set("page", strconv.Itoa(page))
+	}
+	return uq.path + "?" + v.Encode()
+}
+
+// queryUsers отбирает, сортирует и режет на страницы all
+func queryUsers(all []User, uq userQuery) (pagedUsers, error) {
+	list := make([]User, 0, len(all))
+	for _, u := range all {
+		if uq.q == "" || strings.Contains(strings.ToLower(u.Name), uq.q) ||
+			strings.Contains(strings.ToLower(u.Email), uq.q) {
+			list = append(list, u)
+		}
+	}
+	slices.SortFunc(list, func(a, b User) int {
+		return compareKeys(sortValue(a, uq.sort), a.ID, sortValue(b, uq.sort), b.ID)
+	})
+	
+	var start int
+	if uq.after != nil {
+		// Первая запись строго после ключа курсора; сама запись из
+		// курсора могла быть удалена - ключ все равно задает место
+		start = len(list)
+		if i := slices.IndexFunc(list, func(u User) bool {
+			return compareKeys(sortValue(u, uq.sort), u.ID, uq.after.Value, uq.after.ID) > 0
+		}); i >= 0 {
+			start = i
+		}
+	} else {
+		start = min((uq.page-1)*uq.limit, len(list))
+	}
+	end := min(start+uq.limit, len(list))
+	
+	page := pagedUsers{Users: list[start:end], Total: len(list), Page: uq.page, Limit: uq.limit}
+	if end < len(list) {
+		last := list[end-1]
+		c, err := userCursors.Encode(userKey{Sort: uq.sort, Q: uq.q, Value: sortValue(last, uq.sort), ID: last.ID})
+		if err != nil {
+			return pagedUsers{}, err
+		}
+		page.NextCursor = c
+		if uq.after != nil {
+			page.Next = uq.link(0, c)
+		} else {
+			page.Next = uq.link(uq.page+1, "")
+		}
+	}
+	if uq.after == nil && uq.page > 1 {
+		// За последней страницей ссылка ведет на последнюю непустую
+		page.Prev = uq.link(min(uq.page-1, max(1, (len(list)+uq.limit-1)/uq.limit)), "")
+	}
+	return page, nil
+}
+
+// listUsersPaged обработчик списка с параметрами выше
+func listUsersPaged(w http.ResponseWriter, r *http.Request) {
+	uq, err := parseUserQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	uq.path = r.URL.Path
+	page, err := queryUsers(users.List(), uq)
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
\ No newline at end of file
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"httpserver/cursor"
+)
+
+func getUsersPage(t *testing.T, target string) (*httptest.ResponseRecorder, pagedUsers) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	listUsers(w, httptest.NewRequest(http.MethodGet, target, nil))
+	var page pagedUsers
+	if w.Code == http.StatusOK {
+		if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	return w, page
+}
+
+func ids(list []User) []int {
+	out := make([]int, 0, len(list))
+	for _, u := range list {
+		out = append(out, u.ID)
+	}
+	return out
+}
+
+var testUsers = []User{
+	{ID: 1, Name: "Иван Иванов", Email: "ivan@example.com"},
+	{ID: 2, Name: "Мария Петрова", Email: "maria@corp.example"},
+	{ID: 3, Name: "анна Смирнова", Email: "anna@example.com"},
+	{ID: 4, Name: "Иван Иванов", Email: "ivan2@example.com"},
+	{ID: 5, Name: "Борис Ким", Email: "boris@corp.example"},
+}
+
+func TestListUsers_Query(t *testing.T) {
+	withUsers(t, testUsers...)
+	tests := []struct {
+		name     string
+		target   string
+		expected []int
+		total    int
+		next     string
+		prev     string
+	}{
+		{"по умолчанию", "/api/users", []int{1, 2, 3, 4, 5}, 5, "", ""},
+		{"первая страница", "/api/users?limit=2", []int{1, 2}, 5, "/api/users?limit=2&page=2", ""},
+		{"средняя страница", "/api/users?limit=2&page=2", []int{3, 4}, 5,
+			"/api/users?limit=2&page=3", "/api/users?limit=2&page=1"},
+		{"последняя страница", "/api/users?limit=2&page=3", []int{5}, 5, "", "/api/users?limit=2&page=2"},
+		{"за последней", "/api/users?limit=2&page=9", []int{}, 5, "", "/api/users?limit=2&page=3"},
+		// Регистр не важен; равные имена идут по ID
+		{"по имени", "/api/users?sort=name", []int{3, 5, 1, 4, 2}, 5, "", ""},
+		// "ivan2@" раньше "ivan@": '2' меньше '@'
+		{"по email", "/api/users?sort=email", []int{3, 5, 4, 1, 2}, 5, "", ""},
+		{"фильтр по имени", "/api/users?q=ИВАН", []int{1, 4}, 2, "", ""},
+		{"фильтр по email", "/api/users?q=corp&sort=name", []int{5, 2}, 2, "", ""},
+		{"фильтр и страницы", "/api/users?q=example.com&limit=1&page=2&sort=email", []int{4}, 3,
+			"/api/users?limit=1&page=3&q=example.com&sort=email",
+			"/api/users?limit=1&page=1&q=example.com&sort=email"},
+		{"ничего не найдено", "/api/users?q=нет-такого", []int{}, 0, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, page := getUsersPage(t, tt.target)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+			}
+			if got := ids(page.Users); !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if page.Total != tt.total {
+				t.Errorf("Expected total %d, got %d", tt.total, page.Total)
+			}
+			if page.Next != tt.next {
+				t.Errorf("Expected next %q, got %q", tt.next, page.Next)
+			}
+			if page.Prev != tt.prev {
+				t.Errorf("Expected prev %q, got %q", tt.prev, page.Prev)
+			}
+		})
+	}
+}
+
+func TestListUsers_Deterministic(t *testing.T) {
+	withUsers(t, testUsers...)
+	_, first := getUsersPage(t, "/api/users?sort=name")
+	// Порядок обхода map меняется от запуска к запуску и между обходами
+	for range 20 {
+		if _, page := getUsersPage(t, "/api/users?sort=name"); !slices.Equal(ids(page.Users), ids(first.Users)) {
+			t.Fatalf("Expected stable order %v, got %v", ids(first.Users), ids(page.Users))
+		}
+	}
+}
+
+func TestListUsers_Cursor(t *testing.T) {
+	withUsers(t, testUsers...)
+	
+	var got []int
+	target := "/api/users?limit=2&sort=name"
+	for pages := 0; target != ""; pages++ {
+		if pages > 5 {
+			t.Fatal("Expected pagination to end")
+		}
+		w, page := getUsersPage(t, target)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+		}
+		got = append(got, ids(page.Users)...)
+		if pages == 0 {
+			// Дальше по ссылке с курсором: запись, добавленная выше
+			// текущей позиции, не сдвигает выдачу
+			users.Create(User{Name: "Алла Ахматова", Email: "alla@example.com"})
+			target = "/api/users?" + url.Values{
+				"limit": {"2"}, "sort": {"name"}, "cursor": {page.NextCursor},
+			}.Encode()
+			continue
+		}
+		if page.Page != 0 || page.Prev != "" {
+			t.Errorf("Expected no page and prev in cursor mode, got %d %q", page.Page, page.Prev)
+		}
+		target = page.Next
+	}
+	if expected := []int{3, 5, 1, 4, 2}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestListUsers_BadQuery(t *testing.T) {
+	withUsers(t, testUsers...)
+	byName, _ := userCursors.Encode(userKey{Sort: "name", Value: "анна смирнова", ID: 3})
+	forged, _ := cursor.New([]byte("чужой секрет"), time.Hour).Encode(userKey{Sort: "id"})
+	
+	// Курсор со сроком в наносекунду истекает сразу: срок хранится в секундах
+	saved := userCursors
+	userCursors = cursor.New([]byte("secret"), time.Nanosecond)
+	expired, _ := userCursors.Encode(userKey{Sort: "id"})
+	t.Cleanup(func() { userCursors = saved })
+	
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"limit 0", url.Values{"limit": {"0"}}},
+		{"limit больше максимума", url.Values{"limit": {"1000"}}},
+		{"limit не число", url.Values{"limit": {"много"}}},
+		{"page 0", url.Values{"page": {"0"}}},
+		{"неизвестная сортировка", url.Values{"sort": {"password"}}},
+		{"мусор вместо курсора", url.Values{"cursor": {"abc"}}},
+		{"подделанный курсор", url.Values{"cursor": {forged}}},
+		{"истекший курсор", url.Values{"cursor": {expired}}},
+		{"курсор и page", url.Values{"cursor": {byName}, "sort": {"name"}, "page": {"2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w, _ := getUsersPage(t, "/api/users?"+tt.query.Encode()); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", w.Code)
+			}
+		})
+	}
+	
+	// Курсор привязан к сортировке: с другой его не принять
+	userCursors = saved
+	if w, _ := getUsersPage(t, "/api/users?sort=email&cursor="+byName); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for cursor with another sort, got %d", w.Code)
+	}
+}
+func TestListUsers_LinksKeepPath(t *testing.T) {
+	withUsers(t, testUsers...)
+	// Тот же обработчик отдает /api/secure/users: ссылки ведут туда же
+	_, page := getUsersPage(t, "/api/secure/users?limit=2")
+	if page.Next != "/api/secure/users?limit=2&page=2" {
+		t.Errorf("Expected next on /api/secure/users, got %q", page.Next)
+	}
+}
\ No newline at end of file
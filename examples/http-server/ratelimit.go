@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"clock"
+)
+
+// Ограничение частоты запросов: token bucket на каждый IP. В ведре до
+// burst токенов, они пополняются со скоростью rps в секунду, запрос
+// забирает один. Короткий всплеск до burst запросов проходит (страница
+// с десятком картинок), а устойчивый поток ограничен rps.
+//
+// Счетчики в памяти процесса: при нескольких копиях сервиса каждая
+// считает свое, и клиент получает лимит, умноженный на число копий.
+// Общий лимит держат в Redis (INCR с EXPIRE или скрипт на Lua).
+
+// rateLimiter ведра клиентов
+type rateLimiter struct {
+	mu      sync.Mutex
+	rps     float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	clock   clock.Clock
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter создает ограничитель: rps запросов в секунду, всплеск до
+// burst. Время берется из clk: в тестах FakeClock вместо ожидания.
+func newRateLimiter(clk clock.Clock, rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rps:     rps,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		clock:   clk,
+	}
+}
+
+// allow забирает токен клиента key. Если токенов нет, возвращает false
+// и время, через которое появится следующий.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	now := l.clock.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	// Токены начисляются лениво - по прошедшему времени, без таймеров
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rps)
+	b.last = now
+	
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+}
+
+// deleteIdle удаляет ведра, которые успели наполниться: такой клиент
+// неотличим от нового, а без очистки map растет с каждым IP
+func (l *rateLimiter) deleteIdle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	full := time.Duration(l.burst / l.rps * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientIP адрес клиента из соединения. X-Forwarded-For здесь не
+// читается: без доверенного прокси перед сервером заголовок подделывает
+// кто угодно и получает свежее ведро на каждый запрос.
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// rateLimitMiddleware отвечает 429 с Retry-After (в целых секундах,
+// с округлением вверх), если клиент превысил лимит
+func rateLimitMiddleware(l *rateLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := l.allow(clientIP(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "Слишком много запросов", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"clock"
+)
+
+func TestRateLimiter(t *testing.T) {
+	clk := clock.NewFakeClock(time.Unix(1_700_000_000, 0))
+	l := newRateLimiter(clk, 2, 3) // 2 в секунду, всплеск 3
+	
+	for i := range 3 {
+		if ok, _ := l.allow("a"); !ok {
+			t.Fatalf("Request %d within burst was rejected", i+1)
+		}
+	}
+	ok, wait := l.allow("a")
+	if ok {
+		t.Fatal("Expected rejection after burst")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("Expected wait 500ms, got %v", wait)
+	}
+	
+	// Другой клиент не затронут
+	if ok, _ := l.allow("b"); !ok {
+		t.Error("Expected independent bucket for another key")
+	}
+	
+	clk.Advance(500 * time.Millisecond)
+	if ok, _ := l.allow("a"); !ok {
+		t.Error("Expected a token after 500ms")
+	}
+	if ok, _ := l.allow("a"); ok {
+		t.Error("Expected rejection: only one token refilled")
+	}
+	
+	// Долгий простой не копит больше burst
+	clk.Advance(time.Hour)
+	allowed := 0
+	for range 10 {
+		if ok, _ := l.allow("a"); ok {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Expected 3 requests after idle, got %d", allowed)
+	}
+}
+
+func TestRateLimiterDeleteIdle(t *testing.T) {
+	clk := clock.NewFakeClock(time.Unix(1_700_000_000, 0))
+	l := newRateLimiter(clk, 10, 10)
+	
+	l.allow("idle")
+	clk.Advance(500 * time.Millisecond)
+	l.allow("active")
+	// У idle ведро полное через секунду, у active - еще нет
+	clk.Advance(600 * time.Millisecond)
+	l.deleteIdle()
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("Expected idle bucket to be deleted")
+	}
+	if _, ok := l.buckets["active"]; !ok {
+		t.Error("Expected active bucket to be kept")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	clk := clock.NewFakeClock(time.Unix(1_700_000_000, 0))
+	l := newRateLimiter(clk, 0.5, 2) // токен раз в 2 секунды
+	h := rateLimitMiddleware(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	
+	do := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	
+	tests := []struct {
+		name       string
+		addr       string
+		want       int
+		retryAfter string
+	}{
+		{"first", "10.0.0.1:1000", http.StatusOK, ""},
+		{"second", "10.0.0.1:1001", http.StatusOK, ""},
+		{"limited, other port same ip", "10.0.0.1:1002", http.StatusTooManyRequests, "2"},
+		{"other ip", "10.0.0.2:1000", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(tt.addr)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Expected Retry-After %q, got %q", tt.retryAfter, got)
+			}
+		})
+	}
+	
+	// Retry-After округляется вверх: через 1.5 с ждать еще 0.5 с - это 1
+	clk.Advance(1500 * time.Millisecond)
+	if got := do("10.0.0.1:1003").Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", got)
+	}
+	clk.Advance(500 * time.Millisecond)
+	if rec := do("10.0.0.1:1004"); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 after waiting, got %d", rec.Code)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,228 @@
+// Package router - маршрутизатор с параметрами пути и методами:
+//
+//	r := router.New()
+//	r.HandleFunc(http.MethodGet, "/api/users/{id}", getUser)
+//	...
+//	id := router.Param(req, "id")
+//
+// Запрос на существующий путь с другим методом получает 405 и заголовок
+// Allow, на несуществующий - 404. Если статичный сегмент и параметр
+// подходят оба, выигрывает статичный: /api/users/me важнее
+// /api/users/{id}.
+//
+// С Go 1.22 то же самое умеет http.ServeMux: шаблоны "GET /api/users/{id}"
+// и r.PathValue("id"). Здесь маршрутизатор написан вручную, чтобы было
+// видно, что происходит внутри: разбор шаблонов, сопоставление по
+// сегментам, выбор самого конкретного маршрута.
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Router сопоставляет запросы с маршрутами. Маршруты регистрируются до
+// запуска сервера; после этого Router безопасен для конкурентных
+// запросов, так как только читает список.
+type Router struct {
+	routes []route
+
+	// NotFound вызывается, если путь не подошел ни к одному маршруту
+	NotFound http.Handler
+	// MethodNotAllowed вызывается, если путь подошел, а метод - нет;
+	// заголовок Allow к этому моменту уже выставлен
+	MethodNotAllowed http.Handler
+}
+
+type route struct {
+	method   string
+	pattern  string
+	segments []segment
+	handler  http.Handler
+}
+
+// segment часть шаблона между слешами: литерал или {параметр}
+type segment struct {
+	value string
+	param bool
+}
+
+// New создает пустой маршрутизатор
+func New() *Router {
+	return &Router{}
+}
+
+// Handle регистрирует обработчик для метода и шаблона. Ошибки в шаблоне
+// и повторная регистрация - ошибки программиста, поэтому паника, как у
+// http.ServeMux.
+func (rt *Router) Handle(method, pattern string, h http.Handler) {
+	segments, err := parsePattern(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("router: %s %s: %v", method, pattern, err))
+	}
+	for _, r := range rt.routes {
+		if r.method == method && sameShape(r.segments, segments) {
+			panic(fmt.Sprintf("router: %s %s conflicts with %s", method, pattern, r.pattern))
+		}
+	}
+	rt.routes = append(rt.routes, route{method: method, pattern: pattern, segments: segments, handler: h})
+}
+
+// HandleFunc регистрирует функцию-обработчик
+func (rt *Router) HandleFunc(method, pattern string, h func(http.ResponseWriter, *http.Request)) {
+	rt.Handle(method, pattern, http.HandlerFunc(h))
+}
+
+// ServeHTTP выбирает маршрут и вызывает его обработчик
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Путь делится по экранированной форме: закодированный слеш %2F
+	// внутри параметра не должен порождать лишний сегмент
+	parts := splitPath(r.URL.EscapedPath())
+	
+	var best *route
+	var bestParams map[string]string
+	var allowed []string
+	for i := range rt.routes {
+		route := &rt.routes[i]
+		params, ok := match(route.segments, parts)
+		if !ok {
+			continue
+		}
+		// HEAD обслуживается GET-обработчиком, как в http.ServeMux:
+		// сервер сам отбросит тело ответа
+		if route.method != r.Method && !(r.Method == http.MethodHead && route.method == http.MethodGet) {
+			allowed = append(allowed, route.method)
+			continue
+		}
+		if best == nil || moreSpecific(route.segments, best.segments) {
+			best, bestParams = route, params
+		}
+	}
+	
+	if best == nil {
+		if len(allowed) == 0 {
+			rt.notFound(w, r)
+			return
+		}
+		slices.Sort(allowed)
+		w.Header().Set("Allow", strings.Join(slices.Compact(allowed), ", "))
+		rt.methodNotAllowed(w, r)
+		return
+	}
+	if len(bestParams) > 0 {
+		r = r.WithContext(context.WithValue(r.Context(), paramsKey{}, bestParams))
+	}
+	best.handler.ServeHTTP(w, r)
+}
+
+func (rt *Router) notFound(w http.ResponseWriter, r *http.Request) {
+	if rt.NotFound != nil {
+		rt.NotFound.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, "Не найдено", http.StatusNotFound)
+}
+
+func (rt *Router) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	if rt.MethodNotAllowed != nil {
+		rt.MethodNotAllowed.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+}
+
+type paramsKey struct{}
+
+// Param возвращает значение параметра пути; пустая строка, если такого
+// параметра в маршруте нет
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
+
+func parsePattern(pattern string) ([]segment, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("pattern must start with /")
+	}
+	var segments []segment
+	seen := make(map[string]bool)
+	for _, part := range splitPath(pattern) {
+		if !strings.HasPrefix(part, "{") && !strings.HasSuffix(part, "}") {
+			if strings.ContainsAny(part, "{}") {
+				return nil, fmt.Errorf("segment %q mixes text and parameter", part)
+			}
+			segments = append(segments, segment{value: part})
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(part, "{"), "}")
+		if name == "" || len(name) != len(part)-2 || strings.ContainsAny(name, "{}") {
+			return nil, fmt.Errorf("bad parameter %q", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate parameter %q", name)
+		}
+		seen[name] = true
+		segments = append(segments, segment{value: name, param: true})
+	}
+	return segments, nil
+}
+
+// splitPath делит путь на сегменты; завершающий слеш не учитывается,
+// поэтому /api/users и /api/users/ - один и тот же путь
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func match(segments []segment, parts []string) (map[string]string, bool) {
+	if len(segments) != len(parts) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, s := range segments {
+		part, err := url.PathUnescape(parts[i])
+		if err != nil {
+			return nil, false
+		}
+		if !s.param {
+			if part != s.value {
+				return nil, false
+			}
+			continue
+		}
+		if part == "" {
+			return nil, false
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[s.value] = part
+	}
+	return params, true
+}
+
+// moreSpecific сравнивает маршруты, подошедшие к одному пути: в первом
+// различающемся сегменте литерал важнее параметра
+func moreSpecific(a, b []segment) bool {
+	for i := range a {
+		if a[i].param != b[i].param {
+			return !a[i].param
+		}
+	}
+	return false
+}
+
+// sameShape маршруты неразличимы: те же литералы на тех же местах и
+// параметры (с любыми именами) на тех же местах
+func sameShape(a, b []segment) bool {
+	return slices.EqualFunc(a, b, func(x, y segment) bool {
+		return x.param == y.param && (x.param || x.value == y.value)
+	})
+}
\ No newline at end of file
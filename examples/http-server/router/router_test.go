@@ -0,0 +1,132 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// echo отвечает именем маршрута и параметрами
+func echo(name string, params ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out := name
+		for _, p := range params {
+			out += " " + p + "=" + Param(r, p)
+		}
+		w.Write([]byte(out))
+	}
+}
+
+func newTestRouter() *Router {
+	r := New()
+	r.Handle(http.MethodGet, "/api/users", echo("list"))
+	r.Handle(http.MethodPost, "/api/users", echo("create"))
+	r.Handle(http.MethodGet, "/api/users/{id}", echo("get", "id"))
+	r.Handle(http.MethodDelete, "/api/users/{id}", echo("delete", "id"))
+	r.Handle(http.MethodGet, "/api/users/me", echo("me"))
+	r.Handle(http.MethodGet, "/api/users/{id}/posts/{post}", echo("post", "id", "post"))
+	r.Handle(http.MethodGet, "/", echo("root"))
+	return r
+}
+
+func TestRouter(t *testing.T) {
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+		wantAllow  string
+	}{
+		{"GET", "/api/users", 200, "list", ""},
+		{"GET", "/api/users/", 200, "list", ""},
+		{"POST", "/api/users", 200, "create", ""},
+		{"GET", "/api/users/42", 200, "get id=42", ""},
+		{"DELETE", "/api/users/42", 200, "delete id=42", ""},
+		{"GET", "/api/users/me", 200, "me", ""},
+		{"GET", "/api/users/7/posts/hello", 200, "post id=7 post=hello", ""},
+		{"GET", "/api/users/a%2Fb", 200, "get id=a/b", ""},
+		{"GET", "/api/users/%D0%B8%D0%B2%D0%B0%D0%BD", 200, "get id=иван", ""},
+		{"HEAD", "/api/users/42", 200, "", ""},
+		{"GET", "/", 200, "root", ""},
+		{"GET", "/api/users/42/posts", 404, "", ""},
+		{"GET", "/api/unknown", 404, "", ""},
+		{"PUT", "/api/users/42", 405, "", "DELETE, GET"},
+		{"DELETE", "/api/users", 405, "", "GET, POST"},
+	}
+	r := newTestRouter()
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantBody != "" && tt.method != "HEAD" && rec.Body.String() != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, rec.Body.String())
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Expected Allow %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
+
+func TestRouterCustomHandlers(t *testing.T) {
+	r := New()
+	r.Handle(http.MethodGet, "/items/{id}", echo("get"))
+	r.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"GET", "/nope", http.StatusTeapot},
+		{"POST", "/items/1", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, rec.Code)
+		}
+	}
+}
+
+func TestParamOutsideRouter(t *testing.T) {
+	if got := Param(httptest.NewRequest("GET", "/", nil), "id"); got != "" {
+		t.Errorf("Expected empty param, got %q", got)
+	}
+}
+
+func TestHandlePanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+	}{
+		{"no leading slash", []string{"api/users"}},
+		{"empty param", []string{"/users/{}"}},
+		{"mixed segment", []string{"/users/id{id}"}},
+		{"unclosed param", []string{"/users/{id"}},
+		{"duplicate param", []string{"/users/{id}/{id}"}},
+		{"duplicate route", []string{"/users/{id}", "/users/{name}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic")
+				}
+			}()
+			r := New()
+			for _, p := range tt.patterns {
+				r.Handle(http.MethodGet, p, echo("x"))
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// userMatch результат поиска с расстоянием до запроса
+type userMatch struct {
+	User
+	Distance int `json:"distance"`
+}
+
+// searchUsers - нечеткий поиск по имени для GET /api/users?search=...
+// Подстрока дает расстояние 0, иначе запрос сравнивается по Левенштейну
+// с началом каждого слова имени. Подробный разбор алгоритмов и их
+// трассировка - в examples/string-algorithms.
+func searchUsers(all []User, query string) []userMatch {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		// Пустая строка - подстрока любого имени: без проверки
+		// поиск вернул бы всех пользователей
+		return []userMatch{}
+	}
+	qLen := utf8.RuneCountInString(q)
+	maxDist := max(1, qLen/3) // Одна опечатка на каждые три символа
+	
+	matches := []userMatch{}
+	for _, u := range all {
+		name := strings.ToLower(u.Name)
+		best := -1
+		if strings.Contains(name, q) {
+			best = 0
+		}
+		for _, word := range strings.Fields(name) {
+			if best == 0 {
+				break
+			}
+			runes := []rune(word)
+			for _, n := range []int{qLen - 1, qLen, qLen + 1} {
+				if n <= 0 || n > len(runes) {
+					continue
+				}
+				if d := levenshtein([]rune(q), runes[:n]); best < 0 || d < best {
+					best = d
+				}
+			}
+		}
+		if best >= 0 && best <= maxDist {
+			matches = append(matches, userMatch{User: u, Distance: best})
+		}
+	}
+	
+	// Равные расстояния - по ID, порядок не зависит от входа
+	slices.SortFunc(matches, func(a, b userMatch) int {
+		return cmp.Or(cmp.Compare(a.Distance, b.Distance), cmp.Compare(a.ID, b.ID))
+	})
+	return matches
+}
+
+// levenshtein - редакционное расстояние с двумя строками таблицы
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestSearchUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []int
+	}{
+		{"подстрока", "иванов", []int{1, 4}},
+		{"опечатка", "петрво", []int{2}},
+		{"регистр и пробелы", "  КИМ ", []int{5}},
+		{"нет совпадений", "зззззз", []int{}},
+		{"пустой", "", []int{}},
+		{"только пробелы", " \t ", []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, m := range searchUsers(testUsers, tt.query) {
+				got = append(got, m.ID)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestListUsers_BlankSearch(t *testing.T) {
+	withUsers(t, testUsers...)
+	
+	// Пробелы в search - не поиск, а обычный список со страницами
+	w, page := getUsersPage(t, "/api/users?search=%20%20")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if page.Total != len(testUsers) {
+		t.Errorf("Expected total %d, got %d", len(testUsers), page.Total)
+	}
+	if got := ids(page.Users); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected %v, got %v", []int{1, 2, 3, 4, 5}, got)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"clock"
+	"httpserver/router"
+)
+
+// Сессии на куках - второй способ аутентификации рядом с JWT (auth.go).
+// В куке лежит только случайный ID, а данные сессии - на сервере. Отсюда
+// разница с JWT: выход и отзыв мгновенные (запись удалена - сессии нет),
+// зато каждый запрос обращается к хранилищу. Для браузера сессии обычно
+// проще и безопаснее: кука HttpOnly недоступна JavaScript, и XSS не может
+// ее украсть, а токен из localStorage - может.
+
+var errSessionNotFound = errors.New("session not found")
+
+// Session данные сессии на сервере
+type Session struct {
+	ID        string
+	UserID    int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// SessionStore хранилище сессий. Реализация в памяти годится для одного
+// процесса; при нескольких копиях сервиса сессии кладут в Redis или БД
+// (см. projects/blog), иначе запрос на другую копию "разлогинит"
+// пользователя.
+type SessionStore interface {
+	// Get возвращает действующую сессию или errSessionNotFound
+	Get(ctx context.Context, id string) (*Session, error)
+	// Save создает или обновляет сессию
+	Save(ctx context.Context, s *Session) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MemorySessionStore сессии в памяти процесса
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+	clock    clock.Clock
+}
+
+// NewMemorySessionStore создает пустое хранилище; срок сессий сверяется
+// с clk
+func NewMemorySessionStore(clk clock.Clock) *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]Session), clock: clk}
+}
+
+// Get реализует SessionStore. Возвращается копия: изменения вступают в
+// силу только через Save.
+func (m *MemorySessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, errSessionNotFound
+	}
+	if !m.clock.Now().Before(s.ExpiresAt) {
+		delete(m.sessions, id)
+		return nil, errSessionNotFound
+	}
+	return &s, nil
+}
+
+// Save реализует SessionStore
+func (m *MemorySessionStore) Save(ctx context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = *s
+	return nil
+}
+
+// Delete реализует SessionStore
+func (m *MemorySessionStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// DeleteExpired удаляет истекшие сессии: Get удаляет только те, к
+// которым обращаются, а брошенные копились бы вечно
+func (m *MemorySessionStore) DeleteExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	for id, s := range m.sessions {
+		if !now.Before(s.ExpiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// sessionManager вход, выход и кука сессии
+type sessionManager struct {
+	store    SessionStore
+	accounts *accounts
+	ttl      time.Duration // Без активности сессия истекает через ttl
+	secure   bool          // Кука только по HTTPS; выключают для localhost
+	clock    clock.Clock
+}
+
+func newSessionManager(clk clock.Clock, store SessionStore, accounts *accounts, secure bool) *sessionManager {
+	return &sessionManager{store: store, accounts: accounts, ttl: 24 * time.Hour, secure: secure, clock: clk}
+}
+
+// cookieName с префиксом __Host- браузер принимает куку, только если
+// она Secure, с Path=/ и без Domain, - поддомен не сможет ее подменить.
+// Без HTTPS префикс недоступен.
+func (m *sessionManager) cookieName() string {
+	if m.secure {
+		return "__Host-session"
+	}
+	return "session"
+}
+
+// setCookie выставляет куку сессии. HttpOnly - недоступна JavaScript;
+// SameSite=Lax - браузер не пошлет ее с POST с чужого сайта, это
+// основная защита от CSRF для JSON API.
+func (m *sessionManager) setCookie(w http.ResponseWriter, s *Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.cookieName(),
+		Value:    s.ID,
+		Path:     "/",
+		Expires:  s.ExpiresAt,
+		MaxAge:   int(s.ExpiresAt.Sub(m.clock.Now()).Seconds()),
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (m *sessionManager) clearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.cookieName(),
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// newSessionID 256 случайных бит: угадать чужую сессию перебором нельзя
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+const sessionKey contextKey = "session"
+
+// sessionFrom сессия запроса; есть только за sessionMiddleware
+func sessionFrom(r *http.Request) (*Session, bool) {
+	s, ok := r.Context().Value(sessionKey).(*Session)
+	return s, ok
+}
+
+// sessionMiddleware загружает сессию по куке в контекст запроса. Запрос
+// без сессии проходит дальше: нужен ли вход, решает requireSession или
+// сам обработчик.
+//
+// Срок скользящий: если прошло больше половины ttl, сессия продлевается.
+// Продлевать на каждом запросе незачем - это запись в хранилище на каждое
+// чтение.
+func sessionMiddleware(m *sessionManager, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(m.cookieName())
+		if err != nil || cookie.Value == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		s, err := m.store.Get(r.Context(), cookie.Value)
+		if errors.Is(err, errSessionNotFound) {
+			m.clearCookie(w) // Протухшая кука больше не нужна браузеру
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+		
+		if now := m.clock.Now(); s.ExpiresAt.Sub(now) < m.ttl/2 {
+			s.ExpiresAt = now.Add(m.ttl)
+			if err := m.store.Save(r.Context(), s); err == nil {
+				m.setCookie(w, s)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey, s)))
+	})
+}
+
+// requireSession пропускает только запросы с сессией
+func requireSession(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := sessionFrom(r); !ok {
+			http.Error(w, "Требуется вход", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Вход: POST /session/login {"email": "...", "password": "..."}
+func (m *sessionManager) login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		http.Error(w, "Неверный JSON", http.StatusBadRequest)
+		return
+	}
+	userID, ok := m.accounts.authenticate(req.Email, req.Password)
+	if !ok {
+		http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		return
+	}
+	
+	// Старая сессия удаляется, а новая всегда получает новый ID: иначе
+	// атакующий, заранее подсунувший жертве свой ID (session fixation),
+	// после ее входа оказался бы в ее сессии
+	if old, ok := sessionFrom(r); ok {
+		m.store.Delete(r.Context(), old.ID)
+	}
+	id, err := newSessionID()
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	now := m.clock.Now()
+	s := &Session{ID: id, UserID: userID, CreatedAt: now, ExpiresAt: now.Add(m.ttl)}
+	if err := m.store.Save(r.Context(), s); err != nil {
+		http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+		return
+	}
+	m.setCookie(w, s)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Выход: POST /session/logout
+func (m *sessionManager) logout(w http.ResponseWriter, r *http.Request) {
+	if s, ok := sessionFrom(r); ok {
+		if err := m.store.Delete(r.Context(), s.ID); err != nil {
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+	}
+	m.clearCookie(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Текущий пользователь: GET /session/me
+func sessionMe(w http.ResponseWriter, r *http.Request) {
+	s, _ := sessionFrom(r)
+	user, ok := cachedUsers.Get(s.UserID, findUser)
+	if !ok {
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// sessionRoutes маршруты под /session/, все за sessionMiddleware
+func sessionRoutes(m *sessionManager) http.Handler {
+	r := router.New()
+	r.HandleFunc(http.MethodPost, "/session/login", m.login)
+	r.HandleFunc(http.MethodPost, "/session/logout", m.logout)
+	r.HandleFunc(http.MethodGet, "/session/me", requireSession(sessionMe))
+	return sessionMiddleware(m, r)
+}
\ No newline at end of file
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"clock"
+)
+
+func TestMemorySessionStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	clk := clock.NewFakeClock(now)
+	store := NewMemorySessionStore(clk)
+	
+	s := &Session{ID: "a", UserID: 1, ExpiresAt: now.Add(time.Hour)}
+	if err := store.Save(ctx, s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, err := store.Get(ctx, "a")
+	if err != nil || got.UserID != 1 {
+		t.Fatalf("Expected session of user 1, got %+v, %v", got, err)
+	}
+	got.UserID = 2
+	if again, _ := store.Get(ctx, "a"); again.UserID != 1 {
+		t.Errorf("Expected stored session to be unaffected by copy, got user %d", again.UserID)
+	}
+	
+	store.Save(ctx, &Session{ID: "b", UserID: 2, ExpiresAt: now.Add(2 * time.Hour)})
+	clk.Advance(time.Hour)
+	if _, err := store.Get(ctx, "a"); !errors.Is(err, errSessionNotFound) {
+		t.Errorf("Expected expired session to be gone, got %v", err)
+	}
+	store.DeleteExpired()
+	if len(store.sessions) != 1 {
+		t.Errorf("Expected 1 session after cleanup, got %d", len(store.sessions))
+	}
+	
+	store.Delete(ctx, "b")
+	if _, err := store.Get(ctx, "b"); !errors.Is(err, errSessionNotFound) {
+		t.Errorf("Expected deleted session to be gone, got %v", err)
+	}
+}
+
+// sessionEnv сервер с сессиями и управляемыми часами
+type sessionEnv struct {
+	t       *testing.T
+	store   *MemorySessionStore
+	handler http.Handler
+	clock   *clock.FakeClock
+}
+
+func newSessionEnv(t *testing.T) *sessionEnv {
+	clk := clock.NewFakeClock(time.Unix(1_700_000_000, 0))
+	env := &sessionEnv{t: t, store: NewMemorySessionStore(clk), clock: clk}
+	m := newSessionManager(clk, env.store, demoAccounts(), true)
+	env.handler = sessionRoutes(m)
+	return env
+}
+
+func (e *sessionEnv) do(method, path string, cookie *http.Cookie, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	e.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (e *sessionEnv) login(cookie *http.Cookie) *http.Cookie {
+	e.t.Helper()
+	rec := e.do("POST", "/session/login", cookie, `{"email":"ivan@example.com","password":"ivan-password"}`)
+	if rec.Code != http.StatusNoContent {
+		e.t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	return sessionCookie(e.t, rec)
+}
+
+func sessionCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == "__Host-session" {
+			return c
+		}
+	}
+	t.Fatal("Expected session cookie")
+	return nil
+}
+
+func TestSessionLogin(t *testing.T) {
+	env := newSessionEnv(t)
+	cookie := env.login(nil)
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/" {
+		t.Errorf("Expected HttpOnly, Secure, SameSite=Lax cookie on /, got %+v", cookie)
+	}
+	
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		want   int
+	}{
+		{"with session", cookie, http.StatusOK},
+		{"no cookie", nil, http.StatusUnauthorized},
+		{"unknown id", &http.Cookie{Name: "__Host-session", Value: "forged"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := env.do("GET", "/session/me", tt.cookie, ""); rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+	
+	rec := env.do("POST", "/session/login", nil, `{"email":"ivan@example.com","password":"wrong"}`)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for wrong password, got %d", rec.Code)
+	}
+}
+
+func TestSessionFixation(t *testing.T) {
+	env := newSessionEnv(t)
+	first := env.login(nil)
+	second := env.login(first)
+	if second.Value == first.Value {
+		t.Fatal("Expected new session ID on login")
+	}
+	if rec := env.do("GET", "/session/me", first, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected old session to be invalidated, got %d", rec.Code)
+	}
+}
+
+func TestSessionLogout(t *testing.T) {
+	env := newSessionEnv(t)
+	cookie := env.login(nil)
+	rec := env.do("POST", "/session/logout", cookie, "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if c := sessionCookie(t, rec); c.MaxAge >= 0 {
+		t.Errorf("Expected cookie to be cleared, got MaxAge %d", c.MaxAge)
+	}
+	// Даже если браузер не удалит куку, сессии на сервере уже нет
+	if rec := env.do("GET", "/session/me", cookie, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 after logout, got %d", rec.Code)
+	}
+}
+
+func TestSessionSlidingExpiry(t *testing.T) {
+	env := newSessionEnv(t)
+	cookie := env.login(nil)
+	
+	// Меньше половины срока - без продления и без новой куки
+	env.clock.Advance(6 * time.Hour)
+	if rec := env.do("GET", "/session/me", cookie, ""); len(rec.Result().Cookies()) != 0 {
+		t.Errorf("Expected no cookie refresh, got %v", rec.Result().Cookies())
+	}
+	
+	// Больше половины - сессия продлена
+	env.clock.Advance(7 * time.Hour)
+	if rec := env.do("GET", "/session/me", cookie, ""); rec.Code != http.StatusOK || len(rec.Result().Cookies()) != 1 {
+		t.Fatalf("Expected refreshed cookie, got %d %v", rec.Code, rec.Result().Cookies())
+	}
+	env.clock.Advance(20 * time.Hour) // 33 часа от входа
+	if rec := env.do("GET", "/session/me", cookie, ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected extended session to be valid, got %d", rec.Code)
+	}
+	
+	// Без активности дольше ttl - истекла
+	env.clock.Advance(25 * time.Hour)
+	if rec := env.do("GET", "/session/me", cookie, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected idle session to expire, got %d", rec.Code)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Потоковый ответ: GET /api/export отдает большую выгрузку строками
+// NDJSON (по объекту JSON на строку) по мере генерации, а не собирает
+// ее в памяти целиком.
+//
+//   - Память: буферизованный ответ держит всю выгрузку (сотни мегабайт
+//     на большой таблице), потоковый - одну строку и буфер записи.
+//   - Первый байт: клиент получает начало сразу, а не после генерации
+//     последней строки.
+//   - Обратное давление (backpressure): Write блокируется, когда буфер
+//     отправки TCP полон, - медленный клиент сам тормозит генерацию, и
+//     данные не копятся в памяти сервера.
+//   - Отключение клиента: контекст запроса отменяется, генерация
+//     прекращается. Буферизованный обработчик узнает об этом, только
+//     когда все уже сгенерировал.
+//
+// Цена: код ответа и заголовки уходят с первым Flush, и ошибку на
+// середине выгрузки уже не превратить в 500 - ответ просто обрывается.
+// Поэтому в конце потока идет строка-итог: клиент без нее считает
+// выгрузку неполной. Content-Length заранее неизвестен, ответ идет с
+// Transfer-Encoding: chunked.
+
+// exportRow строка выгрузки
+type exportRow struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Created time.Time `json:"created"`
+}
+
+// exportTrailer последняя строка потока: признак полной выгрузки
+type exportTrailer struct {
+	Done bool `json:"done"`
+	Rows int  `json:"rows"`
+}
+
+const (
+	defaultExportRows = 10000
+	maxExportRows     = 10_000_000
+)
+
+// exporter обработчики выгрузки: потоковый и буферизованный для
+// сравнения
+type exporter struct {
+	flushRows     int           // Flush после стольких строк...
+	flushInterval time.Duration // ...или если с прошлого прошло столько
+	stallTimeout  time.Duration // Клиент не принимает данные дольше - обрыв
+
+	// rowCost имитирует стоимость строки (запрос к базе, форматирование);
+	// 0 - без задержки
+	rowCost time.Duration
+
+	// done вызывается после ответа: сколько строк сгенерировано и почему
+	// выгрузка кончилась (nil - полностью). Для журнала и демонстрации.
+	done func(rows int, err error)
+
+	produced atomic.Int64 // Строк сгенерировано всеми запросами
+}
+
+func newExporter() *exporter {
+	return &exporter{
+		flushRows:     500,
+		flushInterval: 200 * time.Millisecond,
+		stallTimeout:  30 * time.Second,
+		done: func(rows int, err error) {
+			if err != nil {
+				logger.Info("выгрузка прервана", "rows", rows, "error", err)
+			}
+		},
+	}
+}
+
+// generateRows выдает n строк по одной, как курсор базы данных.
+// Отмена ctx проверяется на каждой строке: отключившийся клиент
+// останавливает генерацию.
+func (e *exporter) generateRows(ctx context.Context, n int, yield func(exportRow) error) (int, error) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range n {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if e.rowCost > 0 {
+			time.Sleep(e.rowCost)
+		}
+		e.produced.Add(1)
+		row := exportRow{
+			ID:      i + 1,
+			Name:    "user-" + strconv.Itoa(i+1),
+			Email:   "user" + strconv.Itoa(i+1) + "@example.com",
+			Created: start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := yield(row); err != nil {
+			return i, err
+		}
+	}
+	return n, nil
+}
+
+// exportRows число строк из ?rows=
+func exportRows(w http.ResponseWriter, r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("rows")
+	if s == "" {
+		return defaultExportRows, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > maxExportRows {
+		http.Error(w, fmt.Sprintf("rows: число от 0 до %d", maxExportRows), http.StatusBadRequest)
+		return 0, false
+	}
+	return n, true
+}
+
+// stream GET /api/export: строка за строкой, Flush каждые flushRows
+// строк или flushInterval
+func (e *exporter) stream(w http.ResponseWriter, r *http.Request) {
+	n, ok := exportRows(w, r)
+	if !ok {
+		return
+	}
+	// ResponseController находит Flush и SetWriteDeadline и под
+	// обертками middleware (через Unwrap, см. logging.go)
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Без этого nginx копит ответ у себя, и поток снова становится
+	// буферизованным - только уже на прокси
+	w.Header().Set("X-Accel-Buffering", "no")
+	
+	enc := json.NewEncoder(w)
+	pending := 0
+	lastFlush := time.Now()
+	flush := func() error {
+		// Дедлайн на каждую порцию: клиент, который перестал читать,
+		// заблокировал бы Write навсегда. Отдельная порция, а не весь
+		// ответ: долгая выгрузка на медленном канале - не ошибка.
+		if e.stallTimeout > 0 {
+			if err := rc.SetWriteDeadline(time.Now().Add(e.stallTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+		}
+		pending = 0
+		lastFlush = time.Now()
+		return rc.Flush()
+	}
+	if err := flush(); err != nil { // Заголовки сразу: клиент видит 200 до первой строки
+		e.done(0, err)
+		return
+	}
+	
+	rows, err := e.generateRows(r.Context(), n, func(row exportRow) error {
+		// Encode пишет в буфер ResponseWriter (4 КБ); когда он полон,
+		// данные уходят в сеть и без Flush. Flush нужен, чтобы редкие
+		// строки не застревали в буфере.
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+		pending++
+		if pending >= e.flushRows || time.Since(lastFlush) >= e.flushInterval {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = enc.Encode(exportTrailer{Done: true, Rows: rows})
+	}
+	if err == nil {
+		err = flush()
+	}
+	e.done(rows, err)
+}
+
+// buffered GET /api/export/buffered: вся выгрузка в памяти, потом один
+// ответ с Content-Length. Проще, и ошибку можно вернуть кодом 500, но
+// память растет с размером выгрузки, а первый байт ждет последнюю строку.
+func (e *exporter) buffered(w http.ResponseWriter, r *http.Request) {
+	n, ok := exportRows(w, r)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Контекст не передается: так обычно и пишут, и отключение клиента
+	// замечается только при записи ответа
+	rows, err := e.generateRows(context.Background(), n, func(row exportRow) error {
+		return enc.Encode(row)
+	})
+	if err == nil {
+		err = enc.Encode(exportTrailer{Done: true, Rows: rows})
+	}
+	if err != nil {
+		http.Error(w, "Ошибка выгрузки", http.StatusInternalServerError)
+		e.done(rows, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	_, err = buf.WriteTo(w)
+	e.done(rows, err)
+}
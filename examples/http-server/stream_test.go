@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testExporter экспорт, который сообщает итог каждого ответа в канал
+func testExporter() (*exporter, chan error) {
+	e := newExporter()
+	results := make(chan error, 1)
+	e.done = func(rows int, err error) { results <- err }
+	return e, results
+}
+
+func TestExportStream_RowsAndTrailer(t *testing.T) {
+	e, results := testExporter()
+	rec := httptest.NewRecorder()
+	e.stream(rec, httptest.NewRequest(http.MethodGet, "/api/export?rows=3", nil))
+	if err := <-results; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected application/x-ndjson, got %s", ct)
+	}
+	if !rec.Flushed {
+		t.Error("Expected response to be flushed")
+	}
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 3 rows and trailer, got %d lines", len(lines))
+	}
+	var row exportRow
+	if err := json.Unmarshal([]byte(lines[2]), &row); err != nil || row.ID != 3 || row.Name != "user-3" {
+		t.Errorf("Expected row 3, got %+v, %v", row, err)
+	}
+	var trailer exportTrailer
+	if err := json.Unmarshal([]byte(lines[3]), &trailer); err != nil || !trailer.Done || trailer.Rows != 3 {
+		t.Errorf("Expected trailer with 3 rows, got %+v, %v", trailer, err)
+	}
+}
+
+func TestExport_BadRows(t *testing.T) {
+	e, _ := testExporter()
+	for _, q := range []string{"rows=-1", "rows=abc", "rows=100000000"} {
+		for _, h := range []http.HandlerFunc{e.stream, e.buffered} {
+			rec := httptest.NewRecorder()
+			h(rec, httptest.NewRequest(http.MethodGet, "/api/export?"+q, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", q, rec.Code)
+			}
+		}
+	}
+}
+
+func TestExportBuffered_ContentLength(t *testing.T) {
+	e, results := testExporter()
+	rec := httptest.NewRecorder()
+	e.buffered(rec, httptest.NewRequest(http.MethodGet, "/api/export/buffered?rows=5", nil))
+	if err := <-results; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+		t.Errorf("Expected Content-Length %d, got %s", rec.Body.Len(), got)
+	}
+	if n := strings.Count(rec.Body.String(), "\n"); n != 6 {
+		t.Errorf("Expected 6 lines, got %d", n)
+	}
+}
+
+func TestExportStream_StopsOnCancel(t *testing.T) {
+	e, results := testExporter()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/api/export?rows=1000", nil).WithContext(ctx)
+	e.stream(httptest.NewRecorder(), req)
+	if err := <-results; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if n := e.produced.Load(); n != 0 {
+		t.Errorf("Expected no rows after cancel, got %d", n)
+	}
+}
+
+// Строки приходят клиенту до конца генерации, а отключение клиента
+// останавливает ее
+func TestExportStream_Incremental(t *testing.T) {
+	e, results := testExporter()
+	e.rowCost = time.Millisecond
+	e.flushRows = 1
+	srv := httptest.NewServer(http.HandlerFunc(e.stream))
+	defer srv.Close()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?rows=5000", nil)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := bufio.NewReader(resp.Body).ReadString('\n'); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := e.produced.Load(); n >= 5000 {
+		t.Errorf("Expected first row before all rows generated, got %d generated", n)
+	}
+	
+	cancel()
+	select {
+	case err := <-results:
+		if err == nil {
+			t.Error("Expected error after client disconnect")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Handler did not stop after client disconnect")
+	}
+	if n := e.produced.Load(); n >= 5000 {
+		t.Errorf("Expected generation to stop early, got %d rows", n)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"trie"
+)
+
+// Подсказки по мере ввода (search-as-you-type): GET /api/users/suggest?q=
+//
+// В отличие от Complete (autocomplete.go), который ищет только по началу
+// полного имени, здесь в дерево кладется каждое слово имени: "пет"
+// найдет "Мария Петрова", а "мар пет" - ее же по двум словам сразу.
+// Индекс, как и у автодополнения, строится по users и перестраивается
+// после их изменений (usersIndex).
+//
+// Советы клиенту (браузеру), без которых эндпоинт не выдержит ввода:
+//   - debounce 150-300 мс: запрос уходит, когда пользователь сделал паузу,
+//     а не на каждую букву;
+//   - не спрашивать короче minSuggestQuery символов - на одну букву
+//     подходит полбазы, и такой список никому не нужен;
+//   - отменять предыдущий запрос (AbortController) и сверять поле q в
+//     ответе с текущим текстом: ответы могут прийти не по порядку, и
+//     подсказки для "ма" не должны затереть подсказки для "мар";
+//   - кэшировать ответы по q: при стирании буквы ответ уже есть. Сервер
+//     помогает заголовком Cache-Control.
+
+// minSuggestQuery минимальная длина запроса в символах
+const minSuggestQuery = 2
+
+// Suggestion подсказка
+type Suggestion struct {
+	User
+	Fuzzy bool `json:"fuzzy,omitempty"` // Найдено с опечаткой
+}
+
+// SuggestIndex индекс слов имен: слово -> номера пользователей
+type SuggestIndex struct {
+	words *trie.Trie[[]int]
+	users []User
+	// Слова и длина каждого имени, посчитанные заранее: на короткий
+	// запрос подходят тысячи имен, и разбирать их заново на каждый
+	// запрос дороже самого поиска в дереве
+	names   [][]string
+	nameLen []int
+}
+
+// NewSuggestIndex строит индекс по списку пользователей
+func NewSuggestIndex(users []User) *SuggestIndex {
+	idx := &SuggestIndex{
+		words:   trie.NewTrie[[]int](),
+		users:   users,
+		names:   make([][]string, len(users)),
+		nameLen: make([]int, len(users)),
+	}
+	for i, u := range users {
+		idx.names[i] = strings.Fields(normalize(u.Name))
+		idx.nameLen[i] = utf8.RuneCountInString(u.Name)
+		for _, w := range idx.names[i] {
+			existing, _ := idx.words.Get(w)
+			if !slices.Contains(existing, i) {
+				idx.words.Insert(w, append(existing, i))
+			}
+		}
+	}
+	return idx
+}
+
+// candidate пользователь, подошедший к запросу, и признаки для ранжирования
+type candidate struct {
+	user       int
+	firstWord  bool // Первое слово запроса - начало имени
+	exactWords int  // Слов запроса, совпавших со словом имени целиком
+	dist       int  // Опечаток (только при нечетком поиске)
+}
+
+// Suggest возвращает до limit подсказок. Каждое слово запроса должно
+// быть началом какого-то слова имени. Ранжирование, от важного к менее
+// важному:
+//  1. без опечаток выше, чем с опечатками;
+//  2. запрос совпадает с началом имени ("мар" -> "Мария Петрова" выше,
+//     чем "Иван Марков");
+//  3. больше слов совпало целиком ("иван" -> "Иван ..." выше "Иванна ...");
+//  4. имя короче - ближе к тому, что уже введено;
+//  5. по алфавиту, чтобы порядок был стабильным.
+//
+// Если точных совпадений нет, последнее слово ищется нечетко: обычно
+// опечатка - в том, что набирается прямо сейчас.
+func (idx *SuggestIndex) Suggest(q string, limit int) []Suggestion {
+	tokens := strings.Fields(normalize(q))
+	if len(tokens) == 0 {
+		return []Suggestion{}
+	}
+	
+	prefix := func(token string) map[int]int {
+		hits := make(map[int]int)
+		for _, users := range idx.words.WithPrefix(token) {
+			for _, u := range users {
+				hits[u] = 0
+			}
+		}
+		return hits
+	}
+	found := idx.match(tokens, func(i int) map[int]int { return prefix(tokens[i]) })
+	
+	last := len(tokens) - 1
+	if n := utf8.RuneCountInString(tokens[last]); len(found) == 0 && n >= 3 {
+		found = idx.match(tokens, func(i int) map[int]int {
+			if i != last {
+				return prefix(tokens[i])
+			}
+			hits := make(map[int]int)
+			for _, m := range idx.words.Fuzzy(tokens[i], n/3) {
+				for _, u := range m.Value {
+					if d, ok := hits[u]; !ok || m.Dist < d {
+						hits[u] = m.Dist
+					}
+				}
+			}
+			return hits
+		})
+	}
+	
+	slices.SortFunc(found, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(a.dist, b.dist),
+			compareBool(b.firstWord, a.firstWord),
+			cmp.Compare(b.exactWords, a.exactWords),
+			cmp.Compare(idx.nameLen[a.user], idx.nameLen[b.user]),
+			strings.Compare(idx.users[a.user].Name, idx.users[b.user].Name),
+		)
+	})
+	
+	result := make([]Suggestion, 0, min(limit, len(found)))
+	for _, c := range found[:min(limit, len(found))] {
+		result = append(result, Suggestion{User: idx.users[c.user], Fuzzy: c.dist > 0})
+	}
+	return result
+}
+
+// match пересекает пользователей, подошедших к каждому слову запроса;
+// lookup(i) возвращает пользователей i-го слова и число опечаток
+func (idx *SuggestIndex) match(tokens []string, lookup func(i int) map[int]int) []candidate {
+	var common map[int]int
+	for i := range tokens {
+		hits := lookup(i)
+		if common == nil {
+			common = hits
+			continue
+		}
+		for u, d := range common {
+			if hd, ok := hits[u]; ok {
+				common[u] = d + hd
+			} else {
+				delete(common, u)
+			}
+		}
+	}
+	
+	found := make([]candidate, 0, len(common))
+	for u, dist := range common {
+		words := idx.names[u]
+		c := candidate{user: u, dist: dist, firstWord: strings.HasPrefix(words[0], tokens[0])}
+		for _, t := range tokens {
+			if slices.Contains(words, t) {
+				c.exactWords++
+			}
+		}
+		found = append(found, c)
+	}
+	return found
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// suggestResponse ответ с исходным запросом: по нему клиент отбрасывает
+// ответы, пришедшие не по порядку
+type suggestResponse struct {
+	Query       string       `json:"q"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+var suggestIndex = newUsersIndex(NewSuggestIndex)
+
+// suggest GET /api/users/suggest?q=...&limit=...
+// Метод проверяет router: на другой ответит 405 сам.
+func suggest(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if n := utf8.RuneCountInString(strings.TrimSpace(q)); n > 100 {
+		http.Error(w, "Слишком длинный запрос", http.StatusBadRequest)
+		return
+	}
+	limit := 10
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 50 {
+			http.Error(w, "limit должен быть от 1 до 50", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	
+	// Короткий запрос - не ошибка: пользователь еще печатает, отвечаем
+	// пустым списком, чтобы клиенту не нужна была отдельная ветка
+	resp := suggestResponse{Query: q, Suggestions: []Suggestion{}}
+	if utf8.RuneCountInString(strings.TrimSpace(q)) >= minSuggestQuery {
+		resp.Suggestions = suggestIndex.get().Suggest(q, limit)
+	}
+	
+	// Подсказки могут отставать от базы на минуту - зато повторные
+	// запросы при стирании букв не доходят до сервера
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
\ No newline at end of file
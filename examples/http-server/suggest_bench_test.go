@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// go test -bench Suggest -benchmem
+// Сравнение с LIKE в SQLite - в suggest_sqlite_test.go (-tags sqlite).
+
+var (
+	benchFirst = []string{"Иван", "Мария", "Марина", "Марк", "Игорь", "Михаил", "Ирина", "Петр",
+		"Анна", "Алексей", "Ольга", "Дмитрий", "Елена", "Сергей", "Наталья", "Андрей"}
+	benchLast = []string{"Иванов", "Петров", "Соколов", "Лебедев", "Смирнов", "Козлов", "Орлов",
+		"Волков", "Морозов", "Новиков", "Федоров", "Макаров", "Никитин", "Захаров", "Зайцев",
+		"Павлов", "Семенов", "Голубев", "Виноградов", "Богданов"}
+	// Запросы, какими их набирает пользователь: префикс, два слова, опечатка
+	benchQueries = []string{"ма", "мар", "пет", "иван смир", "ол нов", "ан", "михаил", "виноградв"}
+)
+
+// benchUsers генерирует n пользователей "Имя Фамилия<номер>": номер
+// делает имена разными, а общие префиксы остаются как в жизни
+func benchUsers(n int) []User {
+	r := rand.New(rand.NewPCG(1, 2))
+	out := make([]User, n)
+	for i := range out {
+		out[i] = User{ID: i + 1, Name: fmt.Sprintf("%s %s%d",
+			benchFirst[r.IntN(len(benchFirst))], benchLast[r.IntN(len(benchLast))], i)}
+	}
+	return out
+}
+
+func BenchmarkSuggestTrie(b *testing.B) {
+	idx := NewSuggestIndex(benchUsers(10000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Suggest(benchQueries[i%len(benchQueries)], 10)
+	}
+}
+
+func BenchmarkSuggestIndexBuild(b *testing.B) {
+	users := benchUsers(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewSuggestIndex(users)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+// suggestUsers имена для тестов подсказок
+var suggestUsers = []User{
+	{ID: 1, Name: "Иван Иванов"},
+	{ID: 2, Name: "Мария Петрова"},
+	{ID: 3, Name: "Марина Соколова"},
+	{ID: 4, Name: "Марк Лебедев"},
+	{ID: 5, Name: "Иван Смирнов"},
+	{ID: 6, Name: "Игорь Козлов"},
+	{ID: 7, Name: "Михаил Орлов"},
+	{ID: 8, Name: "Ирина Волкова"},
+}
+
+func suggestIDs(s []Suggestion) []int {
+	ids := []int{}
+	for _, u := range s {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestSuggestRanking(t *testing.T) {
+	idx := NewSuggestIndex(append(slices.Clone(suggestUsers), User{ID: 9, Name: "Иванна Маркова"}, User{ID: 10, Name: "Петр Иванов"}))
+	
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"мар", []int{4, 2, 3, 9}},   // Сначала совпадение с началом имени, затем короче
+		{"пет", []int{10, 2}},        // Любое слово имени, не только первое
+		{"мар пет", []int{2}},        // Все слова запроса должны совпасть
+		{"ПЕТРОВА  Мар", []int{2}},   // Порядок слов и регистр не важны
+		{"иван", []int{1, 5, 9, 10}}, // Целое слово выше префикса: "Иван" раньше "Иванна"
+		{"зорро", []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := idx.Suggest(tt.query, 10)
+			if ids := suggestIDs(got); !slices.Equal(ids, tt.want) {
+				t.Errorf("Expected ids %v, got %v", tt.want, ids)
+			}
+			for _, s := range got {
+				if s.Fuzzy {
+					t.Errorf("Expected exact match for %q, got fuzzy %q", tt.query, s.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestFuzzy(t *testing.T) {
+	idx := NewSuggestIndex(suggestUsers)
+	
+	// Опечатка в последнем слове: "арлов" -> "Орлов"
+	got := idx.Suggest("мих арлов", 10)
+	if len(got) != 1 || got[0].ID != 7 || !got[0].Fuzzy {
+		t.Errorf("Expected fuzzy match for Михаил Орлов, got %+v", got)
+	}
+	// Короткое слово нечетко не ищется: на две буквы подошло бы все подряд
+	if got := idx.Suggest("ыв", 10); len(got) != 0 {
+		t.Errorf("Expected no suggestions, got %+v", got)
+	}
+	// Есть точное совпадение - нечеткий поиск не нужен
+	if got := idx.Suggest("орлов", 10); len(got) != 1 || got[0].Fuzzy {
+		t.Errorf("Expected exact match, got %+v", got)
+	}
+}
+
+func TestSuggestHandler(t *testing.T) {
+	withUsers(t, suggestUsers...)
+	r, _ := usersAPI(userRoutes())
+	
+	tests := []struct {
+		name    string
+		query   url.Values
+		status  int
+		wantIDs []int
+	}{
+		{"prefix", url.Values{"q": {"мар"}}, http.StatusOK, []int{4, 2, 3}},
+		{"limit", url.Values{"q": {"мар"}, "limit": {"1"}}, http.StatusOK, []int{4}},
+		{"short query", url.Values{"q": {"м"}}, http.StatusOK, []int{}},
+		{"empty query", url.Values{}, http.StatusOK, []int{}},
+		{"bad limit", url.Values{"q": {"мар"}, "limit": {"0"}}, http.StatusBadRequest, nil},
+		{"limit too big", url.Values{"q": {"мар"}, "limit": {"51"}}, http.StatusBadRequest, nil},
+		{"too long", url.Values{"q": {string(make([]rune, 101))}}, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/users/suggest?"+tt.query.Encode(), nil)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if tt.wantIDs == nil {
+				return
+			}
+			var resp suggestResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.Query != tt.query.Get("q") {
+				t.Errorf("Expected q %q, got %q", tt.query.Get("q"), resp.Query)
+			}
+			if ids := suggestIDs(resp.Suggestions); !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected ids %v, got %v", tt.wantIDs, ids)
+			}
+			if cc := rec.Header().Get("Cache-Control"); cc == "" {
+				t.Error("Expected Cache-Control header")
+			}
+		})
+	}
+	
+	// 405 отвечает router, а не обработчик
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/users/suggest?q=мар", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
+
+// Подсказки строятся по users: новый пользователь виден сразу
+func TestSuggest_FollowsUsers(t *testing.T) {
+	withUsers(t, suggestUsers...)
+	get := func() []int {
+		rec := httptest.NewRecorder()
+		suggest(rec, httptest.NewRequest(http.MethodGet, "/api/users/suggest?q="+url.QueryEscape("ол нов"), nil))
+		var resp suggestResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return suggestIDs(resp.Suggestions)
+	}
+	if ids := get(); len(ids) != 0 {
+		t.Fatalf("Expected no suggestions, got %v", ids)
+	}
+	
+	u := users.Create(User{Name: "Ольга Новикова"})
+	if ids := get(); !slices.Equal(ids, []int{u.ID}) {
+		t.Errorf("Expected created user %d, got %v", u.ID, ids)
+	}
+	users.Delete(u.ID)
+	if ids := get(); len(ids) != 0 {
+		t.Errorf("Expected deleted user to leave suggestions, got %v", ids)
+	}
+}
\ No newline at end of file
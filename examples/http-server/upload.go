@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Загрузка и скачивание файлов: POST /upload сохраняет файл, GET
+// /files/{id} отдает его обратно. Оба пути копируют поток через буфер
+// из sync.Pool, а не читают файл в память целиком.
+//
+// Что было раньше и почему это дорого (см. бенчмарки в upload_test.go):
+//   - r.FormFile вызывает ParseMultipartForm: файл до 32 МБ читается
+//     в память, больше - во временный файл, и только потом обработчик
+//     получает его. Память на запрос растет с размером файла.
+//   - io.Copy выделяет новый буфер на 32 КБ на каждый вызов. На сотне
+//     одновременных загрузок это 3 МБ мусора, которые собирает GC.
+//
+// Теперь части формы читаются потоком (r.MultipartReader), а буферы
+// переиспользуются: на загрузку 1 МБ выделяется около 15 КБ вместо
+// 4 МБ, на скачивание - пара сотен байт вместо 32 КБ.
+//
+// Что проверяется до записи на диск:
+//   - размер: Content-Length больше предела - 413 сразу, без чтения
+//     тела; без Content-Length (chunked) предел держит MaxBytesReader;
+//   - тип: Content-Type от клиента ничего не гарантирует, поэтому тип
+//     определяется по первым 512 байтам (http.DetectContentType) и
+//     сверяется со списком разрешенных. HTML и скрипты не проходят.
+//
+// Файлы переживают перезапуск: рядом с содержимым <id> лежит <id>.json
+// с метаданными, и при старте хранилище их перечитывает (load).
+
+// maxUploadSize предел тела запроса загрузки
+const maxUploadSize = 10 << 20
+
+// allowedUploadTypes типы, которые принимает загрузка, - по содержимому
+// файла, а не по заголовку от клиента
+var allowedUploadTypes = map[string]bool{
+	"text/plain":      true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"application/zip": true,
+}
+
+// copyBufPool буферы для copyBuffer. В пуле лежат *[]byte, а не []byte:
+// срез в interface{} - это выделение памяти при каждом Put, и пул
+// терял бы половину выгоды.
+var copyBufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 32<<10)
+		return &b
+	},
+}
+
+// readerOnly и writerOnly скрывают WriterTo и ReaderFrom. Без них
+// io.CopyBuffer не трогает переданный буфер: он отдает копирование
+// src.WriteTo или dst.ReadFrom, а *os.File и http.ResponseWriter, когда
+// не могут использовать sendfile, внутри вызывают io.Copy - с новым
+// буфером на 32 КБ.
+type readerOnly struct{ io.Reader }
+type writerOnly struct{ io.Writer }
+
+// copyBuffer копирует src в dst через буфер из пула.
+//
+// Цена: скачивание *os.File прямо в сокет могло бы пойти через sendfile
+// без копирования в память вообще. Но ResponseWriter, обернутый в
+// middleware (логирование, сжатие), ReadFrom и так не пропускает, и
+// тогда io.Copy выделял бы буфер на каждый запрос. Для раздачи обычных
+// файлов без оберток лучше http.ServeContent: он умеет и sendfile, и
+// Range, и If-Modified-Since.
+func copyBuffer(dst io.Writer, src io.Reader) (int64, error) {
+	bp := copyBufPool.Get().(*[]byte)
+	defer copyBufPool.Put(bp)
+	return io.CopyBuffer(writerOnly{dst}, readerOnly{src}, *bp)
+}
+
+// fileStore загруженные файлы: содержимое на диске под случайным ID,
+// имя и тип - в памяти. Имя от клиента в путь не попадает.
+type fileStore struct {
+	dir   string
+	mu    sync.Mutex
+	files map[string]uploadInfo
+}
+
+func newFileStore(dir string) *fileStore {
+	return &fileStore{dir: dir, files: make(map[string]uploadInfo)}
+}
+
+// uploads хранилище примера 6; каталог задается при запуске примера
+var uploads *fileStore
+
+var (
+	errUploadTooLarge  = errors.New("файл слишком большой")
+	errUnsupportedType = errors.New("тип файла не поддерживается")
+)
+
+// load перечитывает метаданные файлов, сохраненных до перезапуска, и
+// удаляет временные файлы оборванных загрузок
+func (s *fileStore) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".upload-") {
+			os.Remove(filepath.Join(s.dir, name))
+			continue
+		}
+		id, ok := strings.CutSuffix(name, ".json")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return err
+		}
+		var info uploadInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		// Содержимое без метаданных не видно, метаданные без содержимого -
+		// пропускаются: запись могла оборваться между двумя файлами
+		if info.ID != id {
+			return fmt.Errorf("%s: ID %q не совпадает с именем файла", name, info.ID)
+		}
+		if _, err := os.Stat(filepath.Join(s.dir, id)); err != nil {
+			continue
+		}
+		s.files[id] = info
+	}
+	return nil
+}
+
+// writeFileAtomic пишет data во временный файл в dir и переименовывает
+// в name: читатель видит либо старый файл, либо новый целиком
+func writeFileAtomic(dir, name string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// save пишет r в новый файл. Файл сначала пишется во временный и
+// переименовывается только целиком: оборванная загрузка не оставит
+// полфайла, доступного по ссылке. Метаданные пишутся следом так же.
+func (s *fileStore) save(name, contentType string, r io.Reader) (uploadInfo, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return uploadInfo{}, err
+	}
+	id := hex.EncodeToString(b)
+	
+	var n int64
+	err := writeFileAtomic(s.dir, id, func(w io.Writer) error {
+		var err error
+		n, err = copyBuffer(w, r)
+		return err
+	})
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return uploadInfo{}, errUploadTooLarge
+		}
+		return uploadInfo{}, err
+	}
+	
+	info := uploadInfo{
+		ID:          id,
+		Filename:    filepath.Base(name),
+		Size:        n,
+		ContentType: contentType,
+		Created:     time.Now().UTC(),
+	}
+	err = writeFileAtomic(s.dir, id+".json", func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(info)
+	})
+	if err != nil {
+		os.Remove(filepath.Join(s.dir, id))
+		return uploadInfo{}, err
+	}
+	s.mu.Lock()
+	s.files[id] = info
+	s.mu.Unlock()
+	return info, nil
+}
+
+// open открывает файл по ID
+func (s *fileStore) open(id string) (*os.File, uploadInfo, bool) {
+	s.mu.Lock()
+	info, ok := s.files[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, uploadInfo{}, false
+	}
+	f, err := os.Open(filepath.Join(s.dir, id))
+	if err != nil {
+		return nil, uploadInfo{}, false
+	}
+	return f, info, true
+}
+
+// nextFilePart первая часть формы с файлом в поле name
+func nextFilePart(r *http.Request, name string) (io.ReadCloser, string, string, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", "", err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			return nil, "", "", err
+		}
+		if part.FormName() == name && part.FileName() != "" {
+			return part, part.FileName(), part.Header.Get("Content-Type"), nil
+		}
+		part.Close()
+	}
+}
+
+// sniffType определяет тип по первым 512 байтам - столько смотрит
+// http.DetectContentType. Возвращает тип без параметров и reader, который
+// отдает прочитанное начало и остаток r: файл пишется целиком.
+func sniffType(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// receiveUpload общий прием файла для страницы и API: проверки, запись
+// на диск. При ошибке сам отвечает клиенту и возвращает false.
+func receiveUpload(w http.ResponseWriter, r *http.Request) (uploadInfo, bool) {
+	if r.ContentLength > maxUploadSize {
+		http.Error(w, "Файл больше 10 МБ", http.StatusRequestEntityTooLarge)
+		return uploadInfo{}, false
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	part, name, _, err := nextFilePart(r, "file")
+	if err != nil {
+		http.Error(w, "Ошибка получения файла", http.StatusBadRequest)
+		return uploadInfo{}, false
+	}
+	defer part.Close()
+	
+	contentType, body, err := sniffType(part)
+	if err == nil && !allowedUploadTypes[contentType] {
+		err = errUnsupportedType
+	}
+	var info uploadInfo
+	if err == nil {
+		info, err = uploads.save(name, contentType, body)
+	}
+	var maxErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, errUploadTooLarge), errors.As(err, &maxErr):
+		http.Error(w, "Файл больше 10 МБ", http.StatusRequestEntityTooLarge)
+		return uploadInfo{}, false
+	case errors.Is(err, errUnsupportedType):
+		http.Error(w, "Тип файла не поддерживается: "+contentType, http.StatusUnsupportedMediaType)
+		return uploadInfo{}, false
+	case err != nil:
+		logger.ErrorContext(r.Context(), "Ошибка сохранения файла",
+			slog.Any("err", err), slog.String("request_id", requestIDFrom(r.Context())))
+		http.Error(w, "Ошибка сохранения файла", http.StatusInternalServerError)
+		return uploadInfo{}, false
+	}
+	return info, true
+}
+
+// uploadedFile ответ POST /api/files: метаданные и ссылка на скачивание
+type uploadedFile struct {
+	uploadInfo
+	URL string `json:"url"`
+}
+
+// uploadAPI загрузка для программ, POST /api/files: тот же прием, что у
+// страницы, но в ответ - 201 и JSON с метаданными
+func uploadAPI(w http.ResponseWriter, r *http.Request) {
+	info, ok := receiveUpload(w, r)
+	if !ok {
+		return
+	}
+	url := "/files/" + info.ID
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", url)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(uploadedFile{uploadInfo: info, URL: url})
+}
+
+// downloadFile отдает загруженный файл, GET /files/{id}
+func downloadFile(w http.ResponseWriter, r *http.Request) {
+	f, info, ok := uploads.open(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	
+	// Тип от клиента не повторяется: загруженный text/html открылся бы
+	// как страница нашего сайта со всеми его куками
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": info.Filename}))
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	// Ошибку записи вернуть уже некому: заголовки ушли, клиент отключился
+	copyBuffer(w, f)
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// userCache LRU-кэш поиска пользователей по ID. Урезанная версия
+// examples/cache (там же LFU, TTL и singleflight): в учебном сервере
+// пользователи лежат в map, поэтому кэш здесь показывает схему
+// "читаем через кэш, при изменении инвалидируем", а не ускорение.
+type userCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[int]*list.Element
+	order    *list.List // Голова - самый свежий, хвост - на вытеснение
+
+	// loading загрузки, идущие прямо сейчас: load работает без
+	// блокировки, и Invalidate посреди загрузки не должен дать ей
+	// положить в кэш уже устаревшего пользователя
+	loading map[int]*pendingLoad
+
+	hits, misses int
+}
+
+// pendingLoad загрузки одного ID: сколько их идет и сколько раз ID
+// инвалидировали за это время. Запись живет, только пока n > 0.
+type pendingLoad struct {
+	n   int
+	gen uint64
+}
+
+func newUserCache(capacity int) *userCache {
+	return &userCache{
+		capacity: capacity,
+		items:    make(map[int]*list.Element),
+		order:    list.New(),
+		loading:  make(map[int]*pendingLoad),
+	}
+}
+
+// Get ищет пользователя в кэше, при промахе - через load
+func (c *userCache) Get(id int, load func(int) (User, bool)) (User, bool) {
+	c.mu.Lock()
+	if el, ok := c.items[id]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		user := el.Value.(User)
+		c.mu.Unlock()
+		return user, true
+	}
+	c.misses++
+	p := c.loading[id]
+	if p == nil {
+		p = &pendingLoad{}
+		c.loading[id] = p
+	}
+	p.n++
+	gen := p.gen
+	c.mu.Unlock()
+	
+	user, ok := load(id)
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stale := p.gen != gen
+	if p.n--; p.n == 0 {
+		delete(c.loading, id)
+	}
+	if !ok {
+		return User{}, false // Отсутствие не кэшируем
+	}
+	if stale {
+		// Пользователя изменили, пока шла загрузка: load мог прочитать
+		// старую версию. Вызывающему она годится - он спросил раньше
+		// изменения, - но в кэш не кладется.
+		return user, true
+	}
+	if el, ok := c.items[id]; ok {
+		el.Value = user
+		c.order.MoveToFront(el)
+		return user, true
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(User).ID)
+	}
+	c.items[id] = c.order.PushFront(user)
+	return user, true
+}
+
+// Invalidate удаляет пользователя из кэша после изменения и помечает
+// идущие загрузки устаревшими
+func (c *userCache) Invalidate(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p := c.loading[id]; p != nil {
+		p.gen++
+	}
+	if el, ok := c.items[id]; ok {
+		c.order.Remove(el)
+		delete(c.items, id)
+	}
+}
+
+// Stats возвращает число попаданий и промахов
+func (c *userCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
\ No newline at end of file
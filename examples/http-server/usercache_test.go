@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestUserCache_HitAndEviction(t *testing.T) {
+	c := newUserCache(2)
+	loads := 0
+	load := func(id int) (User, bool) {
+		loads++
+		return User{ID: id}, true
+	}
+	c.Get(1, load)
+	c.Get(2, load)
+	c.Get(1, load) // Попадание; 1 становится самым свежим
+	c.Get(3, load) // Вытесняет 2
+	c.Get(1, load)
+	c.Get(2, load)
+	if loads != 4 {
+		t.Errorf("Expected 4 loads, got %d", loads)
+	}
+	if hits, misses := c.Stats(); hits != 2 || misses != 4 {
+		t.Errorf("Expected 2 hits and 4 misses, got %d and %d", hits, misses)
+	}
+}
+
+// Invalidate между загрузкой и записью в кэш: загруженная до изменения
+// версия не должна остаться в кэше
+func TestUserCache_InvalidateDuringLoad(t *testing.T) {
+	c := newUserCache(10)
+	current := User{ID: 1, Name: "Старое имя"}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan User)
+	
+	go func() {
+		user, _ := c.Get(1, func(id int) (User, bool) {
+			loaded := current // Прочитали до изменения...
+			close(started)
+			<-release // ...и задержались перед записью в кэш
+			return loaded, true
+		})
+		done <- user
+	}()
+	
+	<-started
+	current = User{ID: 1, Name: "Новое имя"}
+	c.Invalidate(1)
+	close(release)
+	if user := <-done; user.Name != "Старое имя" {
+		t.Errorf("Expected in-flight load to return its result, got %s", user.Name)
+	}
+	
+	user, _ := c.Get(1, func(id int) (User, bool) { return current, true })
+	if user.Name != "Новое имя" {
+		t.Errorf("Expected new name after invalidation, got %s", user.Name)
+	}
+	if len(c.loading) != 0 {
+		t.Errorf("Expected no pending loads, got %d", len(c.loading))
+	}
+}
+
+func TestUserCache_NotFoundNotCached(t *testing.T) {
+	c := newUserCache(10)
+	if _, ok := c.Get(1, func(int) (User, bool) { return User{}, false }); ok {
+		t.Error("Expected user not found")
+	}
+	if user, ok := c.Get(1, func(id int) (User, bool) { return User{ID: id}, true }); !ok || user.ID != 1 {
+		t.Errorf("Expected user loaded after miss, got %+v", user)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Пользователи между перезапусками (настройка db_path): при старте
+// читаются из JSON-файла, при остановке записываются обратно. Это не
+// база данных - запись только при штатной остановке, после падения
+// изменения пропадут; настоящее хранилище - в projects/clean-arch.
+
+// loadUsers заменяет users содержимым файла. Файла еще нет - первый
+// запуск, остаются пользователи по умолчанию.
+func loadUsers(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var list []User
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	
+	users.Replace(list)
+	return nil
+}
+
+// saveUsers записывает users во временный файл и переименовывает: сбой
+// посреди записи не оставит обрезанный файл вместо прежнего
+func saveUsers(path string) error {
+	data, err := json.MarshalIndent(users.List(), "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".users-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // После Rename - уже не существует
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
\ No newline at end of file
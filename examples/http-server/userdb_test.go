@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUsersDB_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	withUsers(t, User{ID: 1, Name: "Иван", Email: "ivan@example.com"}, User{ID: 7, Name: "Анна", Email: "anna@example.com"})
+	saved := users.List()
+	
+	// Файла еще нет: остаются текущие пользователи
+	if err := loadUsers(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := saveUsers(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	users = newUserStore()
+	if err := loadUsers(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := users.List(); !reflect.DeepEqual(got, saved) {
+		t.Errorf("Expected %v, got %v", saved, got)
+	}
+	if u := users.Create(User{Name: "Олег"}); u.ID != 8 {
+		t.Errorf("Expected next ID 8, got %d", u.ID)
+	}
+	// Временных файлов не осталось
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected only users.json, got %d files", len(entries))
+	}
+}
+
+func TestUsersDB_Corrupted(t *testing.T) {
+	withUsers(t)
+	path := filepath.Join(t.TempDir(), "users.json")
+	os.WriteFile(path, []byte("{не json"), 0o600)
+	if err := loadUsers(path); err == nil {
+		t.Error("Expected error for corrupted file")
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name     string
+		origins  []string
+		origin   string
+		expected string
+	}{
+		{"все", []string{"*"}, "https://evil.example", "*"},
+		{"из списка", []string{"https://a.example", "https://b.example"}, "https://b.example", "https://b.example"},
+		{"не из списка", []string{"https://a.example"}, "https://evil.example", ""},
+		{"CORS выключен", nil, "https://a.example", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			corsMiddleware(tt.origins, ok).ServeHTTP(w, req)
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if tt.expected != "*" && w.Header().Get("Vary") != "Origin" {
+				t.Error("Expected Vary: Origin")
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"slices"
+	"sync"
+)
+
+// userStore пользователи в памяти процесса. С -serve обработчики
+// работают параллельно, а map не выдерживает одновременных чтения и
+// записи (fatal error: concurrent map read and map write), поэтому
+// map и счетчик ID доступны только под mu. Проверка и изменение
+// (email свободен - создать, пользователь есть - обновить) идут под
+// одной блокировкой, иначе между ними успел бы вклиниться другой запрос.
+type userStore struct {
+	mu      sync.RWMutex
+	users   map[int]User
+	nextID  int
+	version uint64 // Растет с каждым изменением (см. usersIndex)
+}
+
+// newUserStore создает хранилище с пользователями list; следующий ID -
+// после наибольшего
+func newUserStore(list ...User) *userStore {
+	s := &userStore{}
+	s.Replace(list)
+	return s
+}
+
+// Replace заменяет всех пользователей разом (загрузка из файла)
+func (s *userStore) Replace(list []User) {
+	users := make(map[int]User, len(list))
+	nextID := 1
+	for _, u := range list {
+		users[u.ID] = u
+		nextID = max(nextID, u.ID+1)
+	}
+	
+	s.mu.Lock()
+	s.users, s.nextID = users, nextID
+	s.version++
+	s.mu.Unlock()
+}
+
+// Get пользователь по ID
+func (s *userStore) Get(id int) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	u, ok := s.users[id]
+	return u, ok
+}
+
+// List копия всех пользователей по возрастанию ID: вызывающий
+// сортирует и фильтрует ее без блокировки
+func (s *userStore) List() []User {
+	list, _ := s.Snapshot()
+	return list
+}
+
+// Snapshot то же, что List, и версия, которой список соответствует
+func (s *userStore) Snapshot() ([]User, uint64) {
+	s.mu.RLock()
+	list := make([]User, 0, len(s.users))
+	for _, u := range s.users {
+		list = append(list, u)
+	}
+	version := s.version
+	s.mu.RUnlock()
+	
+	slices.SortFunc(list, func(a, b User) int { return a.ID - b.ID })
+	return list, version
+}
+
+// Version номер состояния: меняется при каждом изменении пользователей
+func (s *userStore) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// Len число пользователей
+func (s *userStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.users)
+}
+
+// Create добавляет пользователя с новым ID; ID из u игнорируется
+func (s *userStore) Create(u User) User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(u)
+}
+
+// CreateUnique как Create, но только если email еще ни у кого не занят
+func (s *userStore) CreateUnique(u User) (User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.users {
+		if existing.Email == u.Email {
+			return User{}, false
+		}
+	}
+	return s.create(u), true
+}
+
+func (s *userStore) create(u User) User {
+	u.ID = s.nextID
+	s.nextID++
+	s.users[u.ID] = u
+	s.version++
+	return u
+}
+
+// Update заменяет пользователя с ID u.ID; false - такого нет, и
+// удаленный пользователь не воскресает
+func (s *userStore) Update(u User) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[u.ID]; !ok {
+		return false
+	}
+	s.users[u.ID] = u
+	s.version++
+	return true
+}
+
+// Delete удаляет пользователя; false - его не было
+func (s *userStore) Delete(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return false
+	}
+	delete(s.users, id)
+	s.version++
+	return true
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Запускать с -race: до userStore обработчики писали в общую map без
+// блокировки, и параллельные POST и GET ловили гонку
+func TestUserStore_ConcurrentHandlers(t *testing.T) {
+	withUsers(t, testUsers...)
+	
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"name":"Пользователь %d","email":"u%d@example.com"}`, i, i)
+			w := httptest.NewRecorder()
+			createUser(w, httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(body)))
+			if w.Code != http.StatusCreated {
+				t.Errorf("Expected 201, got %d", w.Code)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			listUsers(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users?limit=5", nil))
+			listUsers(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users?search=иван", nil))
+			usersPage(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+		}()
+	}
+	wg.Wait()
+	
+	if got := users.Len(); got != len(testUsers)+writers {
+		t.Errorf("Expected %d users, got %d", len(testUsers)+writers, got)
+	}
+	// ID не повторяются: каждый Create получил свой
+	seen := make(map[int]bool)
+	for _, u := range users.List() {
+		if seen[u.ID] {
+			t.Errorf("Duplicate ID %d", u.ID)
+		}
+		seen[u.ID] = true
+	}
+}
+
+func TestUserStore_CreateUnique(t *testing.T) {
+	s := newUserStore(User{ID: 1, Name: "Иван", Email: "ivan@example.com"})
+	
+	var wg sync.WaitGroup
+	created := make(chan User, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if u, ok := s.CreateUnique(User{Name: "Анна", Email: "anna@example.com"}); ok {
+				created <- u
+			}
+		}()
+	}
+	wg.Wait()
+	close(created)
+	
+	if n := len(created); n != 1 {
+		t.Fatalf("Expected exactly one user with the same email, got %d", n)
+	}
+	if u := <-created; u.ID != 2 {
+		t.Errorf("Expected ID 2, got %d", u.ID)
+	}
+	if _, ok := s.CreateUnique(User{Email: "ivan@example.com"}); ok {
+		t.Error("Expected taken email to be rejected")
+	}
+}
+
+func TestUserStore_UpdateDeleted(t *testing.T) {
+	s := newUserStore(User{ID: 1, Name: "Иван"})
+	if !s.Delete(1) {
+		t.Fatal("Expected Delete to find user")
+	}
+	if s.Update(User{ID: 1, Name: "Пётр"}) {
+		t.Error("Expected Update of deleted user to fail")
+	}
+	if _, ok := s.Get(1); ok {
+		t.Error("Expected deleted user to stay deleted")
+	}
+	if s.Delete(1) {
+		t.Error("Expected second Delete to report missing user")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Request записанный запрос. URL - путь и query без хоста: кассета,
+// записанная с localhost:8080, проигрывается для любого адреса.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response записанный ответ
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Interaction пара запрос-ответ
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette записанные взаимодействия в порядке записи. Файл - обычный
+// JSON: его читают глазами в ревью и правят руками, например, чтобы
+// добавить ответ 503 для проверки повторов.
+//
+// Тела хранятся строкой, поэтому кассеты годятся для текстовых API
+// (JSON, HTML); двоичные ответы при записи испортятся.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+
+	mu   sync.Mutex
+	used []bool // Какие записи уже проиграны
+}
+
+// redactHeaders заголовки, которые не попадают в кассету: кассеты
+// коммитят в репозиторий вместе с тестами
+var redactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+func redact(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range redactHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, "REDACTED")
+		}
+	}
+	return h
+}
+
+// LoadCassette читает кассету из файла
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save пишет кассету во временный файл и переименовывает: прерванная
+// запись не оставит наполовину записанный JSON
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Add добавляет взаимодействие, убирая секретные заголовки
+func (c *Cassette) Add(i Interaction) {
+	i.Request.Header = redact(i.Request.Header)
+	i.Response.Header = redact(i.Response.Header)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, i)
+}
+
+// Match возвращает ответ на первый еще не проигранный запрос с тем же
+// методом, URL и телом. Одинаковые запросы получают ответы по порядку
+// записи: сценарий "503, затем 200" проигрывается так же, как прошел.
+func (c *Cassette) Match(method, url, body string) (Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.used) < len(c.Interactions) {
+		c.used = append(c.used, make([]bool, len(c.Interactions)-len(c.used))...)
+	}
+	for i, in := range c.Interactions {
+		req := in.Request
+		if !c.used[i] && req.Method == method && req.URL == url && req.Body == body {
+			c.used[i] = true
+			return in.Response, true
+		}
+	}
+	return Response{}, false
+}
+
+// Rewind делает все записи снова доступными для Match
+func (c *Cassette) Rewind() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.used = nil
+}
+
+// Len число записей
+func (c *Cassette) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.Interactions)
+}
\ No newline at end of file
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// flakyAPI первый GET отвечает 503, дальше 200; POST возвращает тело
+type flakyAPI struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (a *flakyAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+		return
+	}
+	a.mu.Lock()
+	a.calls++
+	first := a.calls == 1
+	a.mu.Unlock()
+	if first {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-cookie"})
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"id":1,"name":"Иван"}`)
+}
+
+type exchange struct {
+	method, path, body string
+	status             int
+	respBody           string
+}
+
+var script = []exchange{
+	{"GET", "/api/users/1", "", http.StatusServiceUnavailable, "try later\n"},
+	{"GET", "/api/users/1", "", http.StatusOK, `{"id":1,"name":"Иван"}`},
+	{"POST", "/api/users", `{"name":"Анна"}`, http.StatusCreated, `{"name":"Анна"}`},
+}
+
+func play(t *testing.T, client *http.Client, baseURL string) {
+	t.Helper()
+	for i, ex := range script {
+		req, _ := http.NewRequest(ex.method, baseURL+ex.path, strings.NewReader(ex.body))
+		req.Header.Set("Authorization", "Bearer secret-token")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Step %d: unexpected error: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != ex.status || string(body) != ex.respBody {
+			t.Errorf("Step %d: expected %d %q, got %d %q", i, ex.status, ex.respBody, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	target := httptest.NewServer(&flakyAPI{})
+	cassette := &Cassette{}
+	rec, err := NewRecorder(target.URL, cassette)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	proxy := httptest.NewServer(rec)
+	play(t, proxy.Client(), proxy.URL)
+	proxy.Close()
+	target.Close() // Дальше сеть не нужна
+	
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"secret-token", "secret-cookie"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be redacted from cassette", secret)
+		}
+	}
+	
+	loaded, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.Len() != len(script) {
+		t.Fatalf("Expected %d interactions, got %d", len(script), loaded.Len())
+	}
+	replayer := NewReplayer(loaded)
+	
+	t.Run("round tripper", func(t *testing.T) {
+		loaded.Rewind()
+		play(t, &http.Client{Transport: replayer}, "http://any-host")
+	})
+	t.Run("server", func(t *testing.T) {
+		loaded.Rewind()
+		srv := httptest.NewServer(replayer)
+		defer srv.Close()
+		play(t, srv.Client(), srv.URL)
+	})
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	c := &Cassette{Interactions: []Interaction{{
+		Request:  Request{Method: "GET", URL: "/api/users/1"},
+		Response: Response{Status: http.StatusOK, Body: "ok"},
+	}}}
+	replayer := NewReplayer(c)
+	client := &http.Client{Transport: replayer}
+	
+	tests := []struct {
+		name   string
+		method string
+		url    string
+	}{
+		{"other path", "GET", "http://api/api/users/2"},
+		{"other method", "DELETE", "http://api/api/users/1"},
+		{"other query", "GET", "http://api/api/users/1?full=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			_, err := client.Do(req)
+			if !errors.Is(err, ErrNoInteraction) {
+				t.Errorf("Expected ErrNoInteraction, got %v", err)
+			}
+		})
+	}
+	
+	// Запись проигрывается один раз; второй такой же запрос - не найден
+	if resp, err := client.Get("http://api/api/users/1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else {
+		resp.Body.Close()
+	}
+	rec := httptest.NewRecorder()
+	replayer.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users/1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for exhausted interaction, got %d", rec.Code)
+	}
+}
+
+func TestRecorderTargetDown(t *testing.T) {
+	target := httptest.NewServer(http.NotFoundHandler())
+	target.Close()
+	cassette := &Cassette{}
+	rec, _ := NewRecorder(target.URL, cassette)
+	
+	w := httptest.NewRecorder()
+	rec.ServeHTTP(w, httptest.NewRequest("GET", "/api/users/1", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", w.Code)
+	}
+	if cassette.Len() != 0 {
+		t.Errorf("Expected network error not to be recorded, got %d interactions", cassette.Len())
+	}
+	
+	if _, err := NewRecorder("localhost:8080", cassette); err == nil {
+		t.Error("Expected error for target without scheme")
+	}
+}
\ No newline at end of file
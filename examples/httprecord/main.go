@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Запись и проигрывание HTTP (кассеты в духе VCR): прокси пересылает
+// запросы настоящему API и записывает пары запрос-ответ в JSON-файл, а
+// потом отвечает из файла без сети. Так можно:
+//   - посмотреть, что на самом деле ходит по сети между клиентом и API;
+//   - сделать тесты клиента детерминированными: ответы не зависят от
+//     сервера, сети и времени (см. examples/http-client, testdata/);
+//   - работать с клиентом, когда API недоступен.
+//
+//   cassette.go - формат кассеты, поиск записи, удаление секретов
+//   proxy.go    - Recorder (прокси с записью) и Replayer (сервер и
+//                 http.RoundTripper для тестов)
+//
+// Запись (в другом терминале - examples/http-server на :8080):
+//   go run . -mode record -target http://localhost:8080 -cassette users.json
+//   curl localhost:8090/api/users/1
+//   Ctrl+C - кассета сохранена
+// Проигрывание:
+//   go run . -mode replay -cassette users.json
+//   curl localhost:8090/api/users/1
+//
+// Тесты: go test -race ./...
+
+// Config параметры запуска
+type Config struct {
+	Mode     string // record или replay
+	Target   string
+	Cassette string
+	Addr     string
+}
+
+// run запускает прокси и блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	var handler http.Handler
+	var cassette *Cassette
+	switch cfg.Mode {
+	case "record":
+		// Дописываем к существующей кассете, если она есть
+		c, err := LoadCassette(cfg.Cassette)
+		if errors.Is(err, os.ErrNotExist) {
+			c, err = &Cassette{}, nil
+		}
+		if err != nil {
+			return err
+		}
+		rec, err := NewRecorder(cfg.Target, c)
+		if err != nil {
+			return err
+		}
+		handler, cassette = rec, c
+		log.Printf("Запись: %s -> %s, кассета %s", cfg.Addr, cfg.Target, cfg.Cassette)
+	case "replay":
+		c, err := LoadCassette(cfg.Cassette)
+		if err != nil {
+			return err
+		}
+		handler = NewReplayer(c)
+		log.Printf("Проигрывание: %s, кассета %s (%d записей)", cfg.Addr, cfg.Cassette, c.Len())
+	default:
+		return fmt.Errorf("неизвестный режим %q: нужен record или replay", cfg.Mode)
+	}
+	
+	server := &http.Server{Addr: cfg.Addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	// Сохраняем после Shutdown: запросы в полете успеют записаться
+	if cassette != nil {
+		if saveErr := cassette.Save(cfg.Cassette); saveErr != nil {
+			return saveErr
+		}
+		log.Printf("Кассета %s: %d записей", cfg.Cassette, cassette.Len())
+	}
+	return err
+}
+
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.Mode, "mode", "replay", "record - прокси с записью, replay - ответы из кассеты")
+	flag.StringVar(&cfg.Target, "target", "http://localhost:8080", "адрес API для записи")
+	flag.StringVar(&cfg.Cassette, "cassette", "cassette.json", "файл кассеты")
+	flag.StringVar(&cfg.Addr, "addr", ":8090", "адрес прокси")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
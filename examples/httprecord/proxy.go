@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBody предел тела запроса и ответа, которое попадает в кассету
+const maxBody = 10 << 20
+
+// hopHeaders заголовки одного соединения (RFC 9110, раздел 7.6.1):
+// прокси их не пересылает
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = append([]string(nil), values...)
+	}
+	for _, name := range hopHeaders {
+		dst.Del(name)
+	}
+}
+
+// Recorder прокси: пересылает запросы на target и записывает каждую
+// пару запрос-ответ в кассету
+type Recorder struct {
+	target   *url.URL
+	client   *http.Client
+	cassette *Cassette
+}
+
+// NewRecorder создает записывающий прокси для target вида http://host:port
+func NewRecorder(target string, c *Cassette) (*Recorder, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("target должен быть вида http://host:port, получено %q", target)
+	}
+	return &Recorder{
+		target: u,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			// Редирект тоже ответ: записываем его, а не то, куда он ведет
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		cassette: c,
+	}, nil
+}
+
+// ServeHTTP реализует http.Handler
+func (p *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		http.Error(w, "Тело запроса слишком большое", http.StatusRequestEntityTooLarge)
+		return
+	}
+	out, err := http.NewRequestWithContext(r.Context(), r.Method, p.target.String()+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	copyHeader(out.Header, r.Header)
+	// Без Accept-Encoding транспорт Go сам запросит gzip и распакует
+	// ответ - в кассету попадет читаемый текст
+	out.Header.Del("Accept-Encoding")
+	
+	resp, err := p.client.Do(out)
+	if err != nil {
+		// Ошибка сети не записывается: ответа, который стоило бы
+		// проигрывать, не было
+		http.Error(w, "Цель недоступна: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		http.Error(w, "Ошибка чтения ответа: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	
+	header := make(http.Header)
+	copyHeader(header, resp.Header)
+	header.Del("Content-Length") // Посчитается заново при отдаче
+	p.cassette.Add(Interaction{
+		Request:  Request{Method: r.Method, URL: r.URL.RequestURI(), Header: r.Header, Body: string(body)},
+		Response: Response{Status: resp.StatusCode, Header: header, Body: string(respBody)},
+	})
+	writeResponse(w, Response{Status: resp.StatusCode, Header: header, Body: string(respBody)})
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	copyHeader(w.Header(), resp.Header)
+	w.Header().Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	w.WriteHeader(resp.Status)
+	io.WriteString(w, resp.Body)
+}
+
+// ErrNoInteraction в кассете нет подходящей записи
+var ErrNoInteraction = errors.New("httprecord: нет записи для запроса")
+
+// Replayer отвечает из кассеты без сети. Работает и как сервер
+// (http.Handler) - вместо настоящего API, - и как http.RoundTripper,
+// который подставляют в http.Client в тестах.
+type Replayer struct {
+	cassette *Cassette
+}
+
+// NewReplayer создает проигрыватель кассеты
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{cassette: c}
+}
+
+func (p *Replayer) match(r *http.Request) (Response, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(io.LimitReader(r.Body, maxBody)); err != nil {
+			return Response{}, err
+		}
+	}
+	resp, ok := p.cassette.Match(r.Method, r.URL.RequestURI(), string(body))
+	if !ok {
+		return Response{}, fmt.Errorf("%w: %s %s", ErrNoInteraction, r.Method, r.URL.RequestURI())
+	}
+	return resp, nil
+}
+
+// ServeHTTP реализует http.Handler. Незаписанный запрос получает 404 с
+// пояснением: это почти всегда значит, что кассету пора перезаписать.
+func (p *Replayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, err := p.match(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeResponse(w, resp)
+}
+
+// RoundTrip реализует http.RoundTripper. Незаписанный запрос - ошибка,
+// а не 404: тест должен упасть громко, а не проверять выдуманный ответ.
+func (p *Replayer) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+	resp, err := p.match(r)
+	if err != nil {
+		return nil, err
+	}
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		StatusCode:    resp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       r,
+	}, nil
+}
\ No newline at end of file
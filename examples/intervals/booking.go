@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Booking бронирование переговорной
+type Booking struct {
+	ID    int       `json:"id"`
+	Room  string    `json:"room"`
+	Title string    `json:"title"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (b Booking) interval() Interval[int64] {
+	return Interval[int64]{b.Start.Unix(), b.End.Unix()}
+}
+
+// ConflictError бронь пересекается с существующими
+type ConflictError struct {
+	Conflicts []Booking
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("room is busy: %d conflicting booking(s)", len(e.Conflicts))
+}
+
+var (
+	ErrInvalidRange = errors.New("start must be before end")
+	ErrNotFound     = errors.New("booking not found")
+)
+
+// Calendar расписание переговорных: по дереву интервалов на комнату.
+// Время хранится в Unix-секундах - int64 упорядочен, а time.Time нет.
+type Calendar struct {
+	mu     sync.Mutex
+	rooms  map[string]*IntervalTree[int64, Booking]
+	byID   map[int]Booking
+	nextID int
+}
+
+// NewCalendar создает пустое расписание
+func NewCalendar() *Calendar {
+	return &Calendar{
+		rooms:  make(map[string]*IntervalTree[int64, Booking]),
+		byID:   make(map[int]Booking),
+		nextID: 1,
+	}
+}
+
+// Book бронирует комнату, если интервал свободен. Проверка и вставка
+// выполняются под одной блокировкой, иначе два параллельных запроса
+// могли бы оба увидеть свободное время и оба забронировать его.
+func (c *Calendar) Book(b Booking) (Booking, error) {
+	if !b.Start.Before(b.End) {
+		return Booking{}, ErrInvalidRange
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	tree := c.rooms[b.Room]
+	if tree == nil {
+		tree = NewIntervalTree[int64, Booking]()
+		c.rooms[b.Room] = tree
+	}
+	if conflicts := tree.Overlapping(b.interval()); len(conflicts) > 0 {
+		err := &ConflictError{}
+		for _, e := range conflicts {
+			err.Conflicts = append(err.Conflicts, e.Value)
+		}
+		return Booking{}, err
+	}
+	
+	b.ID = c.nextID
+	c.nextID++
+	tree.Insert(b.interval(), b)
+	c.byID[b.ID] = b
+	return b, nil
+}
+
+// Cancel отменяет бронь
+func (c *Calendar) Cancel(id int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	b, ok := c.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	c.rooms[b.Room].Delete(b.interval(), func(v Booking) bool { return v.ID == id })
+	delete(c.byID, id)
+	return nil
+}
+
+// Between возвращает брони комнаты, пересекающиеся с [from, to)
+func (c *Calendar) Between(room string, from, to time.Time) []Booking {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	result := []Booking{}
+	if tree := c.rooms[room]; tree != nil {
+		for _, e := range tree.Overlapping(Interval[int64]{from.Unix(), to.Unix()}) {
+			result = append(result, e.Value)
+		}
+	}
+	return result
+}
+
+// Handler возвращает роутер API бронирования:
+//
+//	GET    /api/rooms/{room}/bookings?from=&to=  (RFC 3339, по умолчанию - сутки от from)
+//	POST   /api/rooms/{room}/bookings            {"title","start","end"}; 409 при конфликте
+//	DELETE /api/bookings/{id}
+func (c *Calendar) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{room}/bookings", c.handleList)
+	mux.HandleFunc("POST /api/rooms/{room}/bookings", c.handleBook)
+	mux.HandleFunc("DELETE /api/bookings/{id}", c.handleCancel)
+	return mux
+}
+
+func (c *Calendar) handleList(w http.ResponseWriter, r *http.Request) {
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from: expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+	to := from.Add(24 * time.Hour)
+	if s := r.URL.Query().Get("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			http.Error(w, "Invalid to: expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+	}
+	
+	writeJSON(w, http.StatusOK, c.Between(r.PathValue("room"), from, to))
+}
+
+func (c *Calendar) handleBook(w http.ResponseWriter, r *http.Request) {
+	var b Booking
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	b.Room = r.PathValue("room")
+	
+	created, err := c.Book(b)
+	var conflict *ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		// В ответе - с чем именно конфликт, чтобы клиент мог предложить другое время
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":     err.Error(),
+			"conflicts": conflict.Conflicts,
+		})
+	case errors.Is(err, ErrInvalidRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+	default:
+		writeJSON(w, http.StatusCreated, created)
+	}
+}
+
+func (c *Calendar) handleCancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+	if err := c.Cancel(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
\ No newline at end of file
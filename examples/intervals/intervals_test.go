@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIntervalTreeMatchesNaive(t *testing.T) {
+	tree := NewIntervalTree[int, int]()
+	var naive []Entry[int, int]
+	
+	for i := range 3000 {
+		if len(naive) > 0 && rand.IntN(4) == 0 {
+			// Удаляем случайный существующий интервал
+			j := rand.IntN(len(naive))
+			e := naive[j]
+			if !tree.Delete(e.Interval, func(v int) bool { return v == e.Value }) {
+				t.Fatalf("Delete(%v, %d) returned false", e.Interval, e.Value)
+			}
+			naive = slices.Delete(naive, j, j+1)
+		} else {
+			// Маленький диапазон дает много одинаковых Start
+			start := rand.IntN(200)
+			iv := Interval[int]{start, start + rand.IntN(20) + 1}
+			tree.Insert(iv, i)
+			naive = append(naive, Entry[int, int]{iv, i})
+		}
+		
+		start := rand.IntN(220)
+		q := Interval[int]{start, start + rand.IntN(10) + 1}
+		var want []int
+		for _, e := range naive {
+			if e.Interval.Overlaps(q) {
+				want = append(want, e.Value)
+			}
+		}
+		var got []int
+		for _, e := range tree.Overlapping(q) {
+			got = append(got, e.Value)
+		}
+		slices.Sort(want)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Overlapping(%v): expected %v, got %v", q, want, got)
+		}
+		if tree.AnyOverlap(q) != (len(want) > 0) {
+			t.Fatalf("AnyOverlap(%v) disagrees with Overlapping", q)
+		}
+	}
+	
+	if tree.Len() != len(naive) {
+		t.Errorf("Expected Len %d, got %d", len(naive), tree.Len())
+	}
+	all := tree.All()
+	if !slices.IsSortedFunc(all, func(a, b Entry[int, int]) int { return a.Interval.Start - b.Interval.Start }) {
+		t.Error("All must be sorted by Start")
+	}
+}
+
+func TestIntervalTreeDeleteMissing(t *testing.T) {
+	tree := NewIntervalTree[int, string]()
+	tree.Insert(Interval[int]{1, 5}, "a")
+	if tree.Delete(Interval[int]{1, 5}, func(v string) bool { return v == "b" }) {
+		t.Error("Expected false for non-matching value")
+	}
+	if tree.Delete(Interval[int]{1, 6}, func(string) bool { return true }) {
+		t.Error("Expected false for different interval")
+	}
+	if tree.Len() != 1 {
+		t.Errorf("Expected 1 interval, got %d", tree.Len())
+	}
+}
+
+func TestIntervalTreeBalanced(t *testing.T) {
+	tree := NewIntervalTree[int, int]()
+	for i := range 10000 {
+		tree.Insert(Interval[int]{i, i + 1}, i)
+	}
+	// Для 10000 узлов ожидаемая высота treap ~ 30; 100 - с большим запасом
+	if h := tree.height(); h > 100 {
+		t.Errorf("Tree is unbalanced: height %d", h)
+	}
+}
+
+func at(hhmm string) time.Time {
+	t, _ := time.Parse("2006-01-02 15:04", "2026-03-02 "+hhmm)
+	return t
+}
+
+func TestCalendarBook(t *testing.T) {
+	c := NewCalendar()
+	first, err := c.Book(Booking{Room: "orion", Start: at("10:00"), End: at("11:00")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	tests := []struct {
+		name       string
+		room       string
+		start, end string
+		wantErr    error
+	}{
+		{"back to back", "orion", "11:00", "12:00", nil},
+		{"ends at start", "orion", "09:00", "10:00", nil},
+		{"inside", "orion", "10:15", "10:45", &ConflictError{}},
+		{"covers", "orion", "08:00", "13:00", &ConflictError{}},
+		{"other room", "vega", "10:15", "10:45", nil},
+		{"empty", "vega", "15:00", "15:00", ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.Book(Booking{Room: tt.room, Start: at(tt.start), End: at(tt.end)})
+			var conflict *ConflictError
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case errors.As(tt.wantErr, &conflict) && !errors.As(err, &conflict):
+				t.Errorf("Expected conflict, got %v", err)
+			case tt.wantErr == ErrInvalidRange && !errors.Is(err, ErrInvalidRange):
+				t.Errorf("Expected ErrInvalidRange, got %v", err)
+			}
+		})
+	}
+	
+	if err := c.Cancel(first.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := c.Book(Booking{Room: "orion", Start: at("10:15"), End: at("10:45")}); err != nil {
+		t.Errorf("Expected slot to be free after cancel, got %v", err)
+	}
+	if err := c.Cancel(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCalendarConcurrentBooking(t *testing.T) {
+	c := NewCalendar()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	booked := 0
+	for range 50 {
+		wg.Go(func() {
+			if _, err := c.Book(Booking{Room: "orion", Start: at("10:00"), End: at("11:00")}); err == nil {
+				mu.Lock()
+				booked++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if booked != 1 {
+		t.Errorf("Expected exactly 1 successful booking, got %d", booked)
+	}
+}
\ No newline at end of file
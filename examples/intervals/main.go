@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+// Запуск демонстрации: go run .
+// Сервер бронирования: go run . -serve :8080
+//   curl -X POST -d '{"title":"Планерка","start":"2026-03-02T10:00:00Z","end":"2026-03-02T11:00:00Z"}' \
+//     localhost:8080/api/rooms/orion/bookings
+//   curl 'localhost:8080/api/rooms/orion/bookings?from=2026-03-02T00:00:00Z'
+
+// Пример 1: Запросы пересечений
+func basics() {
+	fmt.Println("=== Дерево интервалов ===")
+	
+	t := NewIntervalTree[int, string]()
+	for _, e := range []struct {
+		start, end int
+		name       string
+	}{
+		{15, 20, "A"}, {10, 30, "B"}, {17, 19, "C"}, {5, 20, "D"}, {12, 15, "E"}, {30, 40, "F"},
+	} {
+		t.Insert(Interval[int]{e.start, e.end}, e.name)
+	}
+	
+	for _, q := range []Interval[int]{{14, 16}, {20, 30}, {40, 50}, {0, 5}} {
+		fmt.Printf("пересечения с %v:", q)
+		for _, e := range t.Overlapping(q) {
+			fmt.Printf(" %s%v", e.Value, e.Interval)
+		}
+		fmt.Printf(" (есть: %v)\n", t.AnyOverlap(q))
+	}
+	
+	t.Delete(Interval[int]{10, 30}, func(string) bool { return true })
+	fmt.Printf("после удаления B, пересечения с [20, 30): %v\n", t.Overlapping(Interval[int]{20, 30}))
+}
+
+// Пример 2: Балансировка и сравнение с перебором
+func balance() {
+	fmt.Println("\n=== Балансировка ===")
+	
+	const n = 100000
+	t := NewIntervalTree[int, int]()
+	var all []Interval[int]
+	// Вставка по возрастанию - худший случай для несбалансированного дерева
+	for i := range n {
+		iv := Interval[int]{i * 10, i*10 + rand.IntN(50) + 1}
+		t.Insert(iv, i)
+		all = append(all, iv)
+	}
+	fmt.Printf("%d интервалов вставлены по возрастанию, высота дерева: %d\n", n, t.height())
+	
+	q := Interval[int]{n * 5, n*5 + 100}
+	
+	start := time.Now()
+	var found int
+	for range 1000 {
+		found = len(t.Overlapping(q))
+	}
+	treeTime := time.Since(start) / 1000
+	
+	start = time.Now()
+	var naive int
+	for range 1000 {
+		naive = 0
+		for _, iv := range all {
+			if iv.Overlaps(q) {
+				naive++
+			}
+		}
+	}
+	naiveTime := time.Since(start) / 1000
+	
+	fmt.Printf("запрос %v: дерево %d за %v, перебор %d за %v\n", q, found, treeTime, naive, naiveTime)
+}
+
+// Пример 3: API бронирования переговорных
+func bookingAPI() {
+	fmt.Println("\n=== Бронирование переговорных ===")
+	
+	server := httptest.NewServer(NewCalendar().Handler())
+	defer server.Close()
+	
+	book := func(title, start, end string) string {
+		return fmt.Sprintf(`{"title":%q,"start":"2026-03-02T%s:00Z","end":"2026-03-02T%s:00Z"}`, title, start, end)
+	}
+	requests := []struct {
+		method, path, body string
+	}{
+		{"POST", "/api/rooms/orion/bookings", book("Планерка", "10:00", "11:00")},
+		{"POST", "/api/rooms/orion/bookings", book("Ретро", "11:00", "12:00")},
+		{"POST", "/api/rooms/orion/bookings", book("Созвон", "10:30", "11:30")},
+		{"POST", "/api/rooms/vega/bookings", book("Созвон", "10:30", "11:30")},
+		{"POST", "/api/rooms/orion/bookings", book("Наоборот", "15:00", "14:00")},
+		{"DELETE", "/api/bookings/1", ""},
+		{"POST", "/api/rooms/orion/bookings", book("Созвон", "10:00", "10:45")},
+		{"GET", "/api/rooms/orion/bookings?from=2026-03-02T00:00:00Z", ""},
+	}
+	for _, rq := range requests {
+		req, _ := http.NewRequest(rq.method, server.URL+rq.path, strings.NewReader(rq.body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("%s %s -> %d %s\n", rq.method, rq.path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
+
+func main() {
+	addr := flag.String("serve", "", "Адрес для запуска сервера бронирования")
+	flag.Parse()
+	
+	if *addr != "" {
+		log.Printf("Сервер запущен на %s", *addr)
+		log.Fatal(http.ListenAndServe(*addr, NewCalendar().Handler()))
+	}
+	
+	basics()
+	balance()
+	bookingAPI()
+}
\ No newline at end of file
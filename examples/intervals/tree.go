@@ -0,0 +1,237 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Interval полуинтервал [Start, End): встреча 10:00-11:00 не конфликтует
+// со встречей 11:00-12:00, потому что конец не входит в интервал
+type Interval[K cmp.Ordered] struct {
+	Start, End K
+}
+
+// Overlaps проверяет пересечение двух полуинтервалов
+func (a Interval[K]) Overlaps(b Interval[K]) bool {
+	return a.Start < b.End && b.Start < a.End
+}
+
+func (a Interval[K]) String() string {
+	return fmt.Sprintf("[%v, %v)", a.Start, a.End)
+}
+
+// IntervalTree дерево интервалов: дерево поиска по Start, где каждый узел
+// дополнительно хранит наибольший End в своем поддереве (maxEnd). Если
+// maxEnd поддерева не больше начала запроса, ни один интервал в нем
+// не пересекается с запросом, и поддерево пропускается целиком.
+//
+// Балансировка - декартово дерево (treap): у узла случайный приоритет,
+// и по приоритетам дерево остается кучей. Ожидаемая глубина O(log n)
+// при любом порядке вставки, а код заметно короче красно-черного дерева.
+type IntervalTree[K cmp.Ordered, V any] struct {
+	root *node[K, V]
+	size int
+}
+
+type node[K cmp.Ordered, V any] struct {
+	iv          Interval[K]
+	value       V
+	maxEnd      K
+	priority    uint64
+	left, right *node[K, V]
+}
+
+// Entry интервал со значением
+type Entry[K cmp.Ordered, V any] struct {
+	Interval Interval[K]
+	Value    V
+}
+
+// NewIntervalTree создает пустое дерево
+func NewIntervalTree[K cmp.Ordered, V any]() *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{}
+}
+
+// Len возвращает число интервалов
+func (t *IntervalTree[K, V]) Len() int {
+	return t.size
+}
+
+// update пересчитывает maxEnd по детям; вызывается после любого
+// изменения поддерева, включая повороты
+func (n *node[K, V]) update() {
+	n.maxEnd = n.iv.End
+	if n.left != nil {
+		n.maxEnd = max(n.maxEnd, n.left.maxEnd)
+	}
+	if n.right != nil {
+		n.maxEnd = max(n.maxEnd, n.right.maxEnd)
+	}
+}
+
+func rotateRight[K cmp.Ordered, V any](n *node[K, V]) *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func rotateLeft[K cmp.Ordered, V any](n *node[K, V]) *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// Insert добавляет интервал. Одинаковые и пересекающиеся интервалы
+// допустимы - запрет конфликтов решает вызывающий код.
+func (t *IntervalTree[K, V]) Insert(iv Interval[K], value V) {
+	t.root = t.insert(t.root, &node[K, V]{iv: iv, value: value, maxEnd: iv.End, priority: rand.Uint64()})
+	t.size++
+}
+
+func (t *IntervalTree[K, V]) insert(n, nn *node[K, V]) *node[K, V] {
+	if n == nil {
+		return nn
+	}
+	if nn.iv.Start < n.iv.Start {
+		n.left = t.insert(n.left, nn)
+		if n.left.priority > n.priority {
+			n = rotateRight(n)
+		}
+	} else {
+		n.right = t.insert(n.right, nn)
+		if n.right.priority > n.priority {
+			n = rotateLeft(n)
+		}
+	}
+	n.update()
+	return n
+}
+
+// Delete удаляет первый интервал iv, для значения которого match вернул
+// true. Возвращает false, если такого интервала нет.
+func (t *IntervalTree[K, V]) Delete(iv Interval[K], match func(V) bool) bool {
+	var deleted bool
+	t.root = t.delete(t.root, iv, match, &deleted)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+func (t *IntervalTree[K, V]) delete(n *node[K, V], iv Interval[K], match func(V) bool, deleted *bool) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	switch {
+	case iv.Start < n.iv.Start:
+		n.left = t.delete(n.left, iv, match, deleted)
+	case iv.Start > n.iv.Start:
+		n.right = t.delete(n.right, iv, match, deleted)
+	case n.iv == iv && match(n.value):
+		*deleted = true
+		return t.merge(n.left, n.right)
+	default:
+		// Равные Start после поворотов могут оказаться в обоих поддеревьях
+		n.left = t.delete(n.left, iv, match, deleted)
+		if !*deleted {
+			n.right = t.delete(n.right, iv, match, deleted)
+		}
+	}
+	n.update()
+	return n
+}
+
+// merge объединяет два поддерева, где все ключи a не больше ключей b
+func (t *IntervalTree[K, V]) merge(a, b *node[K, V]) *node[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = t.merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = t.merge(a, b.left)
+	b.update()
+	return b
+}
+
+// Overlapping возвращает все интервалы, пересекающиеся с q, по возрастанию
+// Start. Сложность O(log n + k), где k - размер ответа.
+func (t *IntervalTree[K, V]) Overlapping(q Interval[K]) []Entry[K, V] {
+	var result []Entry[K, V]
+	t.overlapping(t.root, q, func(n *node[K, V]) bool {
+		result = append(result, Entry[K, V]{n.iv, n.value})
+		return true
+	})
+	return result
+}
+
+// AnyOverlap проверяет, есть ли хоть одно пересечение с q, и
+// останавливается на первом найденном
+func (t *IntervalTree[K, V]) AnyOverlap(q Interval[K]) bool {
+	found := false
+	t.overlapping(t.root, q, func(*node[K, V]) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// overlapping обходит пересекающиеся узлы по порядку; yield возвращает
+// false, чтобы остановить обход
+func (t *IntervalTree[K, V]) overlapping(n *node[K, V], q Interval[K], yield func(*node[K, V]) bool) bool {
+	// Все интервалы поддерева закончились до начала запроса
+	if n == nil || n.maxEnd <= q.Start {
+		return true
+	}
+	if !t.overlapping(n.left, q, yield) {
+		return false
+	}
+	// Узел и все правое поддерево начинаются не раньше конца запроса
+	if n.iv.Start >= q.End {
+		return true
+	}
+	if n.iv.Overlaps(q) && !yield(n) {
+		return false
+	}
+	return t.overlapping(n.right, q, yield)
+}
+
+// All возвращает все интервалы по возрастанию Start
+func (t *IntervalTree[K, V]) All() []Entry[K, V] {
+	result := make([]Entry[K, V], 0, t.size)
+	var walk func(*node[K, V])
+	walk = func(n *node[K, V]) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		result = append(result, Entry[K, V]{n.iv, n.value})
+		walk(n.right)
+	}
+	walk(t.root)
+	return result
+}
+
+// height возвращает высоту дерева - для демонстрации балансировки
+func (t *IntervalTree[K, V]) height() int {
+	var h func(*node[K, V]) int
+	h = func(n *node[K, V]) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + max(h(n.left), h(n.right))
+	}
+	return h(t.root)
+}
\ No newline at end of file
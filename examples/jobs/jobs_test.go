@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestQueue очередь на файле во временном каталоге с управляемыми
+// часами
+func newTestQueue(t *testing.T, path string) (*Queue, *time.Time) {
+	t.Helper()
+	db, err := openDB(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	q, err := NewQueue(db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Now()
+	q.now = func() time.Time { return now }
+	return q, &now
+}
+
+func TestQueue_ClaimExclusive(t *testing.T) {
+	q, _ := newTestQueue(t, filepath.Join(t.TempDir(), "jobs.db"))
+	const jobs = 20
+	for i := 0; i < jobs; i++ {
+		if _, err := q.Enqueue("email", "x"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	
+	// Воркеры забирают задачи одновременно: каждая выдается ровно раз
+	var mu sync.Mutex
+	claimed := make(map[int64]string)
+	var wg sync.WaitGroup
+	for w := 0; w < 5; w++ {
+		wg.Add(1)
+		go func(workerID string) {
+			defer wg.Done()
+			for {
+				job, err := q.claim(context.Background(), workerID)
+				if errors.Is(err, ErrNoJobs) {
+					return
+				}
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				mu.Lock()
+				if prev, ok := claimed[job.ID]; ok {
+					t.Errorf("Job %d claimed by %s and %s", job.ID, prev, workerID)
+				}
+				claimed[job.ID] = workerID
+				mu.Unlock()
+			}
+		}(fmt.Sprintf("worker-%d", w))
+	}
+	wg.Wait()
+	if len(claimed) != jobs {
+		t.Errorf("Expected %d claimed jobs, got %d", jobs, len(claimed))
+	}
+}
+
+func TestQueue_RetryWithSubSecondBackoff(t *testing.T) {
+	q, now := newTestQueue(t, filepath.Join(t.TempDir(), "jobs.db"))
+	q.Backoff = func(int) time.Duration { return 300 * time.Millisecond }
+	id, _ := q.Enqueue("flaky", "x")
+	
+	job, err := q.claim(context.Background(), "w1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := q.complete(job, errors.New("временная ошибка")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stored, _ := q.Get(id)
+	if stored.Status != StatusPending || stored.LastError == "" {
+		t.Errorf("Expected pending with error, got %+v", stored)
+	}
+	// Задержка не округляется до целых секунд
+	if d := stored.RunAt.Sub(now.Truncate(time.Millisecond)); d != 300*time.Millisecond {
+		t.Errorf("Expected retry in 300ms, got %v", d)
+	}
+	
+	*now = now.Add(200 * time.Millisecond)
+	if _, err := q.claim(context.Background(), "w1"); !errors.Is(err, ErrNoJobs) {
+		t.Errorf("Expected ErrNoJobs before backoff, got %v", err)
+	}
+	*now = now.Add(100 * time.Millisecond)
+	job, err = q.claim(context.Background(), "w1")
+	if err != nil || job.Attempts != 2 {
+		t.Fatalf("Expected second attempt, got %+v, %v", job, err)
+	}
+	if err := q.complete(job, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored, _ := q.Get(id); stored.Status != StatusDone {
+		t.Errorf("Expected done, got %s", stored.Status)
+	}
+}
+
+func TestQueue_FailsAfterMaxAttempts(t *testing.T) {
+	q, now := newTestQueue(t, filepath.Join(t.TempDir(), "jobs.db"))
+	q.Backoff = func(int) time.Duration { return time.Millisecond }
+	id, _ := q.Enqueue("broken", "x")
+	for i := 0; i < 3; i++ {
+		job, err := q.claim(context.Background(), "w1")
+		if err != nil {
+			t.Fatalf("Attempt %d: unexpected error: %v", i+1, err)
+		}
+		q.complete(job, errors.New("ошибка"))
+		*now = now.Add(time.Millisecond)
+	}
+	if stored, _ := q.Get(id); stored.Status != StatusFailed || stored.Attempts != 3 {
+		t.Errorf("Expected failed after 3 attempts, got %+v", stored)
+	}
+}
+
+// Процесс упал посреди задачи: после перезапуска она выдается снова,
+// когда истечет аренда
+func TestQueue_RecoveryAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	q, now := newTestQueue(t, path)
+	id, _ := q.Enqueue("email", "x")
+	lost, err := q.claim(context.Background(), "old-process")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.db.Close()
+	
+	restarted, now2 := newTestQueue(t, path)
+	*now2 = *now
+	restarted.Lease = time.Minute
+	if _, err := restarted.claim(context.Background(), "new-process"); !errors.Is(err, ErrNoJobs) {
+		t.Errorf("Expected ErrNoJobs while lease is active, got %v", err)
+	}
+	
+	*now2 = now2.Add(time.Minute)
+	job, err := restarted.claim(context.Background(), "new-process")
+	if err != nil {
+		t.Fatalf("Expected abandoned job, got %v", err)
+	}
+	if job.ID != id || job.Attempts != 2 || job.LockedBy != "new-process" {
+		t.Errorf("Unexpected job %+v", job)
+	}
+	
+	// Результат старого воркера после истечения аренды отбрасывается
+	if err := restarted.complete(lost, nil); !errors.Is(err, errLeaseLost) {
+		t.Errorf("Expected errLeaseLost, got %v", err)
+	}
+	if err := restarted.complete(job, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored, _ := restarted.Get(id); stored.Status != StatusDone {
+		t.Errorf("Expected done, got %s", stored.Status)
+	}
+}
+
+func TestQueue_AbandonedOnLastAttemptFails(t *testing.T) {
+	q, now := newTestQueue(t, filepath.Join(t.TempDir(), "jobs.db"))
+	q.Lease = time.Minute
+	id, _ := q.Enqueue("crash", "x")
+	for i := 0; i < 3; i++ {
+		if _, err := q.claim(context.Background(), "w1"); err != nil {
+			t.Fatalf("Claim %d: unexpected error: %v", i+1, err)
+		}
+		*now = now.Add(time.Minute) // Воркер так и не ответил
+	}
+	if _, err := q.claim(context.Background(), "w1"); !errors.Is(err, ErrNoJobs) {
+		t.Errorf("Expected ErrNoJobs, got %v", err)
+	}
+	if stored, _ := q.Get(id); stored.Status != StatusFailed {
+		t.Errorf("Expected failed, got %s", stored.Status)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Персистентная очередь задач: в отличие от пула воркеров на каналах,
+// задачи переживают перезапуск процесса, потому что лежат в SQLite.
+
+// openDB открывает файловую БД, пригодную для нескольких воркеров
+func openDB(path string) (*sql.DB, error) {
+	// _txlock=immediate - BEGIN IMMEDIATE для каждой транзакции
+	// _busy_timeout - ждать блокировку вместо мгновенной ошибки SQLITE_BUSY
+	// _journal_mode=WAL - читатели не блокируют писателя
+	dsn := fmt.Sprintf("file:%s?_txlock=immediate&_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+	
+	return db, nil
+}
+
+// jobsHandler отдает статус очереди: GET /api/jobs и GET /api/jobs/{id}
+func jobsHandler(q *Queue) http.Handler {
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+			return
+		}
+		
+		stats, err := q.Stats()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		
+		jobs, err := q.List(50)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"stats": stats,
+			"jobs":  jobs,
+		})
+	})
+	
+	mux.HandleFunc("/api/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.URL.Path[len("/api/jobs/"):], 10, 64)
+		if err != nil {
+			http.Error(w, "Неверный ID", http.StatusBadRequest)
+			return
+		}
+		
+		job, err := q.Get(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Задача не найдена", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	})
+	
+	return mux
+}
+
+// Пример 1: Постановка задач, в том числе отложенных
+func enqueueJobs(q *Queue) {
+	fmt.Println("=== Постановка задач ===")
+	
+	for i := 1; i <= 3; i++ {
+		id, err := q.Enqueue("email", fmt.Sprintf("user%d@example.com", i))
+		if err != nil {
+			log.Fatal("Ошибка постановки задачи:", err)
+		}
+		fmt.Printf("Задача email #%d поставлена\n", id)
+	}
+	
+	id, err := q.EnqueueAt("report", "daily", time.Now().Add(2*time.Second))
+	if err != nil {
+		log.Fatal("Ошибка постановки задачи:", err)
+	}
+	fmt.Printf("Отложенная задача report #%d выполнится через 2 секунды\n", id)
+	
+	id, err = q.Enqueue("flaky", "upload")
+	if err != nil {
+		log.Fatal("Ошибка постановки задачи:", err)
+	}
+	fmt.Printf("Нестабильная задача flaky #%d будет повторяться с backoff\n", id)
+}
+
+// Пример 2: Воркеры, повторы и статус через HTTP
+func processJobs(q *Queue, addr string) {
+	fmt.Println("\n=== Обработка задач ===")
+	
+	q.Register("email", func(ctx context.Context, job Job) error {
+		fmt.Printf("Отправлено письмо на %s (попытка %d)\n", job.Payload, job.Attempts)
+		return nil
+	})
+	
+	q.Register("report", func(ctx context.Context, job Job) error {
+		fmt.Printf("Сформирован отчет %s\n", job.Payload)
+		return nil
+	})
+	
+	// Падает два раза, на третьей попытке проходит
+	var calls atomic.Int32
+	q.Register("flaky", func(ctx context.Context, job Job) error {
+		if calls.Add(1) < 3 {
+			return errors.New("временная ошибка хранилища")
+		}
+		fmt.Printf("Задача flaky выполнена с попытки %d\n", job.Attempts)
+		return nil
+	})
+	
+	// Короткий backoff, чтобы пример не ждал долго
+	q.Backoff = func(attempt int) time.Duration {
+		return time.Duration(attempt) * 500 * time.Millisecond
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	
+	// Порт из флага -addr; :0 - любой свободный
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal("Ошибка запуска сервера:", err)
+	}
+	server := &http.Server{Handler: jobsHandler(q)}
+	go func() {
+		fmt.Printf("Статус очереди: http://%s/api/jobs\n", l.Addr())
+		if err := server.Serve(l); err != http.ErrServerClosed {
+			log.Printf("Ошибка сервера: %v", err)
+		}
+	}()
+	
+	q.Work(ctx, 3, 200*time.Millisecond)
+	server.Shutdown(context.Background())
+	
+	stats, err := q.Stats()
+	if err != nil {
+		log.Fatal("Ошибка получения статистики:", err)
+	}
+	fmt.Printf("Итог: %v\n", stats)
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "адрес HTTP-сервера статуса очереди")
+	flag.Parse()
+	
+	dir, err := os.MkdirTemp("", "jobs")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	
+	db, err := openDB(filepath.Join(dir, "jobs.db"))
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	q, err := NewQueue(db)
+	if err != nil {
+		log.Fatal("Ошибка инициализации очереди:", err)
+	}
+	
+	enqueueJobs(q)
+	processJobs(q, *addr)
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// AppendJSON дописывает u в dst в том же виде, что json.Marshal, и
+// возвращает расширенный срез - как strconv.AppendInt. Вызывающий сам
+// решает, где живет буфер: переиспользуя его между вызовами, кодирование
+// не выделяет память вовсе.
+//
+// Время вне годов 0-9999 json.Marshal отклоняет ошибкой; здесь такое
+// значение просто будет записано - проверка на совести вызывающего.
+func (u *User) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"id":`...)
+	dst = strconv.AppendInt(dst, u.ID, 10)
+	dst = append(dst, `,"name":`...)
+	dst = appendString(dst, u.Name)
+	dst = append(dst, `,"email":`...)
+	dst = appendString(dst, u.Email)
+	dst = append(dst, `,"active":`...)
+	dst = strconv.AppendBool(dst, u.Active)
+	dst = append(dst, `,"created_at":"`...)
+	dst = u.CreatedAt.AppendFormat(dst, time.RFC3339Nano)
+	dst = append(dst, `","tags":`...)
+	if u.Tags == nil {
+		dst = append(dst, "null"...)
+	} else {
+		dst = append(dst, '[')
+		for i, tag := range u.Tags {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendString(dst, tag)
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, '}')
+}
+
+const hex = "0123456789abcdef"
+
+// appendString строка JSON с теми же правилами экранирования, что у
+// encoding/json по умолчанию: кавычки и управляющие символы, <, > и &
+// (чтобы JSON можно было вставить в HTML), U+2028 и U+2029 (их не
+// понимает JavaScript до ES2019). Неверный UTF-8 заменяется на U+FFFD;
+// старые версии encoding/json писали его как \ufffd, новые - самим
+// символом, для разбора JSON это одно и то же.
+//
+// Большую часть строки составляют обычные символы, поэтому они
+// копируются кусками, а не по байту.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
\ No newline at end of file
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// Так выглядит код, который пишут генераторы easyjson и ffjson: для
+// каждого типа - функция, которая знает поля заранее и пишет их
+// в буфер без reflect. Здесь он написан вручную по образцу вывода
+// easyjson, чтобы пример не зависел от внешних пакетов и go generate:
+//
+//	go install github.com/mailru/easyjson/...@latest
+//	easyjson -all main.go   // создаст main_easyjson.go
+//
+// Отличие от AppendJSON в том, кто владеет буфером: writer генератора
+// растит свой буфер и в конце отдает копию, поэтому каждый вызов - это
+// минимум одно выделение. Зато сгенерированный код не устаревает:
+// добавили поле - перезапустили генератор.
+
+// jwriter упрощенный jwriter.Writer из easyjson
+type jwriter struct {
+	buf []byte
+}
+
+func (w *jwriter) RawByte(c byte)     { w.buf = append(w.buf, c) }
+func (w *jwriter) RawString(s string) { w.buf = append(w.buf, s...) }
+func (w *jwriter) String(s string)    { w.buf = appendString(w.buf, s) }
+func (w *jwriter) Int64(n int64)      { w.buf = strconv.AppendInt(w.buf, n, 10) }
+func (w *jwriter) Bool(v bool)        { w.buf = strconv.AppendBool(w.buf, v) }
+func (w *jwriter) BuildBytes() []byte { return w.buf }
+func (w *jwriter) Time(t time.Time) {
+	w.RawByte('"')
+	w.buf = t.AppendFormat(w.buf, time.RFC3339Nano)
+	w.RawByte('"')
+}
+
+// easyjsonEncodeUser в духе сгенерированного кода: поле за полем, с
+// префиксом-запятой, собранным на этапе генерации
+func easyjsonEncodeUser(out *jwriter, in *User) {
+	out.RawByte('{')
+	out.RawString(`"id":`)
+	out.Int64(in.ID)
+	out.RawString(`,"name":`)
+	out.String(in.Name)
+	out.RawString(`,"email":`)
+	out.String(in.Email)
+	out.RawString(`,"active":`)
+	out.Bool(in.Active)
+	out.RawString(`,"created_at":`)
+	out.Time(in.CreatedAt)
+	out.RawString(`,"tags":`)
+	if in.Tags == nil {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for i, v := range in.Tags {
+			if i > 0 {
+				out.RawByte(',')
+			}
+			out.String(v)
+		}
+		out.RawByte(']')
+	}
+	out.RawByte('}')
+}
+
+// MarshalGenerated аналог сгенерированного User.MarshalJSON
+func (u *User) MarshalGenerated() ([]byte, error) {
+	w := jwriter{buf: make([]byte, 0, 256)}
+	easyjsonEncodeUser(&w, u)
+	return w.BuildBytes(), nil
+}
+
+// generatedUser User с методом MarshalJSON, как после генерации.
+// Отдельный тип нужен, чтобы json.Marshal(User) в сравнении остался
+// кодированием через reflect.
+type generatedUser struct{ *User }
+
+func (g generatedUser) MarshalJSON() ([]byte, error) { return g.MarshalGenerated() }
\ No newline at end of file
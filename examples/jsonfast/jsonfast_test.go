@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// encoders все три способа; вывод каждого должен совпадать с json.Marshal
+var encoders = []struct {
+	name string
+	fn   func(u *User) []byte
+}{
+	{"generated", func(u *User) []byte { b, _ := u.MarshalGenerated(); return b }},
+	{"AppendJSON", func(u *User) []byte { return u.AppendJSON(nil) }},
+	{"json.Marshal(MarshalJSON)", func(u *User) []byte { b, _ := json.Marshal(generatedUser{u}); return b }},
+}
+
+func checkSame(t *testing.T, u *User) {
+	t.Helper()
+	expected, err := json.Marshal(u)
+	if err != nil {
+		t.Skipf("encoding/json отклоняет значение: %v", err)
+	}
+	expected = unescapeRuneError(expected)
+	for _, enc := range encoders {
+		if got := enc.fn(u); !bytes.Equal(got, expected) {
+			t.Errorf("%s: expected\n%s\ngot\n%s", enc.name, expected, got)
+		}
+	}
+}
+
+// unescapeRuneError заменяет экранированный \ufffd самим символом:
+// версии Go пишут замену неверного UTF-8 по-разному. Экранированная
+// обратная косая черта перед ufffd (\\ufffd) - это текст, его не трогаем.
+func unescapeRuneError(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		if b[i] != '\\' {
+			out = append(out, b[i])
+			continue
+		}
+		if bytes.HasPrefix(b[i:], []byte(`\ufffd`)) {
+			out = append(out, "\uFFFD"...)
+			i += len(`\ufffd`) - 1
+			continue
+		}
+		out = append(out, b[i], b[i+1]) // Другая escape-последовательность
+		i++
+	}
+	return out
+}
+
+func TestAppendJSON_MatchesEncodingJSON(t *testing.T) {
+	// Все поля заполнены: новое поле, забытое в ручном кодировщике,
+	// даст расхождение с encoding/json
+	full := sampleUser()
+	v := reflect.ValueOf(full).Elem()
+	for i := range v.NumField() {
+		if v.Field(i).IsZero() {
+			t.Fatalf("sampleUser: поле %s не заполнено", v.Type().Field(i).Name)
+		}
+	}
+	
+	tests := []struct {
+		name string
+		user *User
+	}{
+		{"заполненный", full},
+		{"нулевой", &User{}},
+		{"пустые теги", &User{Tags: []string{}}},
+		{"отрицательный ID", &User{ID: -1 << 63}},
+		{"экранирование", &User{Name: "\"\\/\b\f\n\r\t\x00\x1f\x7f <>&"}},
+		{"юникод", &User{Name: "Ёжик 🦔    中文"}},
+		{"неверный UTF-8", &User{Name: "a\xffb\xc0\xaf", Tags: []string{"\xed\xa0\x80"}}},
+		{"текст \\ufffd", &User{Name: `\ufffd \\ufffd`}},
+		{"часовой пояс", &User{CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("MSK", 3*3600))}},
+		{"наносекунды без нулей", &User{CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 100, time.UTC)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkSame(t, tt.user)
+		})
+	}
+}
+
+func TestAppendJSON_Appends(t *testing.T) {
+	u := &User{ID: 1}
+	got := u.AppendJSON([]byte("prefix:"))
+	expected := `prefix:{"id":1,"name":"","email":"","active":false,"created_at":"0001-01-01T00:00:00Z","tags":null}`
+	if string(got) != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestAppendJSON_NoAllocs(t *testing.T) {
+	u := sampleUser()
+	buf := make([]byte, 0, 512)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = u.AppendJSON(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Expected 0 allocations, got %.0f", allocs)
+	}
+}
+
+// Произвольные строки и время: вывод совпадает с encoding/json байт в байт
+func FuzzAppendJSON(f *testing.F) {
+	f.Add(int64(1), "Иван", "ivan@example.com", "tag", int64(0), true)
+	f.Add(int64(-5), "<script>", "\x00\xff", " ", int64(1700000000123456789), false)
+	
+	f.Fuzz(func(t *testing.T, id int64, name, email, tag string, nanos int64, active bool) {
+		u := &User{
+			ID:        id,
+			Name:      name,
+			Email:     email,
+			Active:    active,
+			CreatedAt: time.Unix(0, nanos).UTC(),
+			Tags:      []string{tag, name},
+		}
+		checkSame(t, u)
+	})
+}
+
+// go test -bench . -benchmem
+func BenchmarkMarshal(b *testing.B) {
+	u := sampleUser()
+	b.Run("encoding_json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			json.Marshal(u)
+		}
+	})
+	b.Run("encoding_json_MarshalJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			json.Marshal(generatedUser{u})
+		}
+	})
+	b.Run("generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			u.MarshalGenerated()
+		}
+	})
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, 512)
+		for i := 0; i < b.N; i++ {
+			buf = u.AppendJSON(buf[:0])
+		}
+		b.SetBytes(int64(len(buf)))
+	})
+}
+
+// Строки без спецсимволов - основной случай: копируются кусками
+func BenchmarkAppendString(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		s    string
+	}{
+		{"ascii", "ivan.ivanov@example.com"},
+		{"cyrillic", "Иван Иванович Иванов"},
+		{"escapes", "<a href=\"x\">\n\t</a>"},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			buf := make([]byte, 0, 128)
+			for i := 0; i < b.N; i++ {
+				buf = appendString(buf[:0], bm.s)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+)
+
+// Быстрое кодирование JSON: три способа закодировать одного и того же
+// пользователя и во что обходится каждый.
+//
+//   - encoding/json: reflect по полям на каждом вызове (описание типа
+//     кэшируется, но обход - нет), результат в новом срезе;
+//   - сгенерированный код, как у easyjson и ffjson (generated.go): поля
+//     известны заранее, reflect нет, но буфер свой на каждый вызов;
+//   - AppendJSON вручную (append.go): дописывает в буфер вызывающего,
+//     при переиспользовании буфера - ноль выделений.
+//
+// Когда это оправдано:
+//   - профиль (pprof) показывает, что кодирование JSON - заметная доля
+//     CPU или выделений, например отдача тысяч объектов в секунду или
+//     запись логов в JSON на горячем пути;
+//   - тип стабилен и прост: ручной кодировщик нужно менять при каждом
+//     новом поле, и забытое поле молча пропадет из ответа. Тест
+//     TestAppendJSON_MatchesEncodingJSON ловит это, сравнивая вывод с
+//     encoding/json на заполненной структуре.
+//
+// Когда нет: обычный REST-обработчик, где запрос в базу занимает
+// миллисекунды, а кодирование - микросекунды. Выигрыш в 3-5 раз на
+// кодировании там не виден, а поддержка второго кодировщика - видна.
+// Генератор - промежуточный вариант: почти та же скорость без ручной
+// поддержки, но шаг go generate в сборке и зависимость.
+//
+// Запуск: go run .
+// Бенчмарки: go test -bench . -benchmem
+// Фаззинг совпадения с encoding/json: go test -fuzz FuzzAppendJSON
+
+// User пользователь; теги json - формат, который повторяют все три
+// кодировщика
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags"`
+}
+
+func sampleUser() *User {
+	return &User{
+		ID:        42,
+		Name:      "Иван <Иванов>",
+		Email:     "ivan@example.com",
+		Active:    true,
+		CreatedAt: time.Date(2024, 3, 15, 10, 30, 0, 123456789, time.UTC),
+		Tags:      []string{"admin", "go", "\"кавычки\""},
+	}
+}
+
+// Пример 1: Один и тот же результат
+func sameOutput() {
+	fmt.Println("=== Один и тот же JSON ===")
+	
+	u := sampleUser()
+	std, err := json.Marshal(u)
+	if err != nil {
+		log.Fatal(err)
+	}
+	gen, _ := u.MarshalGenerated()
+	manual := u.AppendJSON(nil)
+	
+	fmt.Println(string(manual))
+	fmt.Printf("Сгенерированный совпадает: %v, AppendJSON совпадает: %v\n",
+		bytes.Equal(std, gen), bytes.Equal(std, manual))
+}
+
+// Пример 2: Время и выделения памяти. testing.Benchmark работает и вне
+// go test - удобно для быстрого сравнения прямо в примере.
+func compare() {
+	fmt.Println("\n=== Сравнение ===")
+	
+	u := sampleUser()
+	buf := make([]byte, 0, 512)
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"json.Marshal", func() { json.Marshal(u) }},
+		{"json.Marshal(MarshalJSON)", func() { json.Marshal(generatedUser{u}) }},
+		{"сгенерированный", func() { u.MarshalGenerated() }},
+		{"AppendJSON(nil)", func() { u.AppendJSON(nil) }},
+		{"AppendJSON(buf[:0])", func() { buf = u.AppendJSON(buf[:0]) }},
+	}
+	for _, c := range cases {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c.fn()
+			}
+		})
+		fmt.Printf("%-27s %6d нс/op %5d Б/op %3d allocs/op\n",
+			c.name, r.NsPerOp(), r.AllocedBytesPerOp(), r.AllocsPerOp())
+	}
+	// json.Marshal с MarshalJSON медленнее самого MarshalJSON: encoding/json
+	// проверяет и переписывает (compact) все, что вернул метод. А
+	// AppendJSON(nil) растит срез с нуля несколько раз - без буфера
+	// вызывающего он хуже генератора, который начинает с 256 байт.
+	fmt.Println("Вызов MarshalJSON через json.Marshal добавляет проверку и копию вывода")
+}
+
+// Пример 3: Поток объектов в переиспользуемый буфер. json.Encoder тоже
+// не выделяет память на объект: внутри у него пул буферов. Разница
+// остается только во времени - reflect против кода, знающего поля.
+func stream() {
+	fmt.Println("\n=== Поток объектов ===")
+	
+	users := make([]*User, 1000)
+	for i := range users {
+		u := *sampleUser()
+		u.ID = int64(i)
+		users[i] = &u
+	}
+	
+	var out bytes.Buffer
+	buf := make([]byte, 0, 512)
+	enc := json.NewEncoder(&out)
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		// JSON Lines: объект на строку, один буфер на весь поток
+		{"AppendJSON", func() {
+			for _, u := range users {
+				buf = u.AppendJSON(buf[:0])
+				buf = append(buf, '\n')
+				out.Write(buf)
+			}
+		}},
+		{"json.Encoder", func() {
+			for _, u := range users {
+				enc.Encode(u)
+			}
+		}},
+	}
+	for _, c := range cases {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				out.Reset()
+				c.fn()
+			}
+		})
+		fmt.Printf("%-12s 1000 объектов: %4d мкс, %d allocs\n", c.name, r.NsPerOp()/1000, r.AllocsPerOp())
+	}
+}
+
+func main() {
+	sameOutput()
+	compare()
+	stream()
+}
\ No newline at end of file
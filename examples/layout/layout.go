@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// Размер структуры - не сумма размеров полей. Каждое поле выравнивается
+// по своему типу (int64 и указатель - по 8 байт на 64-битной платформе,
+// int32 - по 4, bool - по 1), а размер всей структуры округляется до
+// выравнивания самого строгого поля, чтобы в массиве каждый элемент
+// тоже был выровнен. Промежутки заполняются пустыми байтами (padding).
+//
+// Компилятор Go поля не переставляет - порядок в исходнике и есть
+// порядок в памяти. Правило: от больших полей к маленьким. Проверка
+// всего проекта - анализатор fieldalignment из golang.org/x/tools:
+//
+//	go run golang.org/x/tools/go/analysis/passes/fieldalignment/cmd/fieldalignment@latest ./...
+//
+// Переставлять стоит то, чего в памяти миллионы (элементы больших
+// срезов, ключи map, узлы деревьев); структуру конфигурации ради восьми
+// байт читаемость терять не должна.
+
+// orderBad поля вперемешку: 7 полей, 3 байта флагов, но 48 байт
+//
+//	Active  bool   0  (+7 padding)
+//	ID      int64  8
+//	Deleted bool   16 (+3 padding)
+//	Score   int32  20
+//	Owner   *int   24
+//	Admin   bool   32 (+7 padding)
+//	Created int64  40
+type orderBad struct {
+	Active  bool
+	ID      int64
+	Deleted bool
+	Score   int32
+	Owner   *int
+	Admin   bool
+	Created int64
+}
+
+// orderGood те же поля от больших к маленьким: 32 байта, padding только
+// в конце (1 байт) - на миллионе элементов 16 МБ экономии
+type orderGood struct {
+	ID      int64
+	Created int64
+	Owner   *int
+	Score   int32
+	Active  bool
+	Deleted bool
+	Admin   bool
+}
+
+// trailingZero поле нулевого размера в конце получает padding: иначе
+// &s.End указывал бы за пределы структуры, на следующий объект, и
+// держал бы его живым для сборщика мусора. В начале или середине
+// struct{} ничего не стоит.
+type trailingZero struct {
+	N   int64
+	End struct{}
+}
+
+type leadingZero struct {
+	Start struct{}
+	N     int64
+}
+
+// counters64 атомарные 64-битные поля: на 32-битных платформах (386,
+// arm) атомарные операции с int64 требуют выравнивания по 8 байт, а
+// обычный int64 там выровнен по 4. atomic.Int64 выравнивается сам;
+// с голым int64 его приходилось ставить первым полем структуры.
+type counters64 struct {
+	Flag bool
+	Hits atomic.Int64 // Выровнен по 8 на любой платформе
+}
+
+// describe печатает поля структуры со смещениями и padding - то же,
+// что unsafe.Offsetof/Sizeof по каждому полю, но для любой структуры
+func describe(w io.Writer, v any) {
+	t := reflect.TypeOf(v)
+	fmt.Fprintf(w, "%s: Sizeof=%d Alignof=%d\n", t.Name(), t.Size(), t.Align())
+	var used uintptr
+	for i := range t.NumField() {
+		f := t.Field(i)
+		end := t.Size()
+		if i+1 < t.NumField() {
+			end = t.Field(i + 1).Offset
+		}
+		pad := end - f.Offset - f.Type.Size()
+		used += f.Type.Size()
+		fmt.Fprintf(w, "  %-8s %-13s offset %2d size %d", f.Name, f.Type, f.Offset, f.Type.Size())
+		if pad > 0 {
+			fmt.Fprintf(w, "  %s +%d padding", strings.Repeat("·", int(pad)), pad)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "  полезных байт %d из %d\n", used, t.Size())
+}
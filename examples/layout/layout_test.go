@@ -0,0 +1,80 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestSizes(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("Sizes are for 64-bit platforms")
+	}
+	for _, tc := range []struct {
+		name     string
+		got      uintptr
+		expected uintptr
+	}{
+		{"orderBad", unsafe.Sizeof(orderBad{}), 48},
+		{"orderGood", unsafe.Sizeof(orderGood{}), 32},
+		{"trailingZero", unsafe.Sizeof(trailingZero{}), 16},
+		{"leadingZero", unsafe.Sizeof(leadingZero{}), 8},
+		{"packed", unsafe.Sizeof(packed{}), 8},
+		{"padded", unsafe.Sizeof(padded{}), cacheLine},
+	} {
+		if tc.got != tc.expected {
+			t.Errorf("%s: expected Sizeof %d, got %d", tc.name, tc.expected, tc.got)
+		}
+	}
+}
+
+func TestCounters64_Aligned(t *testing.T) {
+	if off := unsafe.Offsetof(counters64{}.Hits); off%8 != 0 {
+		t.Errorf("Expected Hits offset aligned to 8, got %d", off)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	var b strings.Builder
+	describe(&b, orderBad{})
+	out := b.String()
+	if !strings.HasPrefix(out, "orderBad: Sizeof=") {
+		t.Errorf("Expected header line, got\n%s", out)
+	}
+	if strings.Count(out, "padding") != 3 {
+		t.Errorf("Expected 3 padding gaps, got\n%s", out)
+	}
+}
+
+func TestHammer_Total(t *testing.T) {
+	const workers, n = 4, 1000
+	for name, run := range map[string]func(int, int) int64{
+		"packed": hammer[packed],
+		"padded": hammer[padded],
+		"local":  hammerLocal,
+	} {
+		if got := run(workers, n); got != workers*n {
+			t.Errorf("%s: expected total %d, got %d", name, workers*n, got)
+		}
+	}
+}
+
+func BenchmarkSharing(b *testing.B) {
+	const n = 100_000
+	for _, v := range []struct {
+		name string
+		run  func(int, int) int64
+	}{
+		{"packed", hammer[packed]},
+		{"padded", hammer[padded]},
+		{"local", hammerLocal},
+	} {
+		b.Run(v.name, func(b *testing.B) {
+			workers := runtime.GOMAXPROCS(0) // Задается флагом -cpu
+			for i := 0; i < b.N; i++ {
+				v.run(workers, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+// Раскладка структур в памяти: порядок полей меняет размер структуры,
+// а соседство счетчиков разных горутин в одной строке кэша - скорость
+// записи в разы.
+//
+//   - layout.go - выравнивание, padding и порядок полей;
+//   - sharing.go - false sharing: счетчики вплотную и с padding;
+//   - layout_test.go - проверка размеров и бенчмарки.
+//
+// Запуск: go run .
+//
+// Бенчмарки (эффект виден только при GOMAXPROCS > 1):
+//
+//	go test -run=^$ -bench=Sharing -cpu=1,4,8
+//
+// Промахи кэша из-за false sharing на Linux:
+//
+//	perf stat -e cache-misses,LLC-load-misses go test -run=^$ -bench=Sharing/packed
+//	perf c2c record go test -run=^$ -bench=Sharing/packed  # строки кэша, за которые дерутся ядра
+
+// Пример 1: Порядок полей и размер структуры
+func sizes() {
+	fmt.Println("=== Порядок полей ===")
+	
+	fmt.Printf("unsafe.Sizeof(orderBad{})     = %d\n", unsafe.Sizeof(orderBad{}))
+	fmt.Printf("unsafe.Sizeof(orderGood{})    = %d\n", unsafe.Sizeof(orderGood{}))
+	fmt.Printf("unsafe.Sizeof(trailingZero{}) = %d\n", unsafe.Sizeof(trailingZero{}))
+	fmt.Printf("unsafe.Sizeof(leadingZero{})  = %d\n", unsafe.Sizeof(leadingZero{}))
+	fmt.Printf("unsafe.Alignof(counters64{}.Hits) = %d\n", unsafe.Alignof(counters64{}.Hits))
+	const n = 1_000_000
+	fmt.Printf("Срез из %d элементов: %d МБ против %d МБ\n",
+		n, n*unsafe.Sizeof(orderBad{})>>20, n*unsafe.Sizeof(orderGood{})>>20)
+	
+	fmt.Println()
+	describe(os.Stdout, orderBad{})
+	describe(os.Stdout, orderGood{})
+	describe(os.Stdout, trailingZero{})
+	describe(os.Stdout, counters64{})
+}
+
+// Пример 2: False sharing - одни и те же горутины, одна и та же
+// работа, разная раскладка счетчиков
+func sharing() {
+	workers := runtime.GOMAXPROCS(0)
+	const n = 1_000_000
+	fmt.Printf("\n=== False sharing, %d горутин по %d инкрементов ===\n", workers, n)
+	if workers == 1 {
+		fmt.Println("GOMAXPROCS=1: горутины не работают параллельно, разницы не будет")
+	}
+	
+	fmt.Printf("Sizeof: packed %d, padded %d\n", unsafe.Sizeof(packed{}), unsafe.Sizeof(padded{}))
+	var base float64
+	for _, v := range []struct {
+		name string
+		run  func(int, int) int64
+	}{
+		{"packed", hammer[packed]},
+		{"padded", hammer[padded]},
+		{"local", hammerLocal},
+	} {
+		var total int64
+		r := testing.Benchmark(func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				total = v.run(workers, n)
+			}
+		})
+		ms := float64(r.NsPerOp()) / 1e6
+		if base == 0 {
+			base = ms
+		}
+		fmt.Printf("  %-7s %8.2f мс/op  (%.1f× быстрее packed), сумма %d\n", v.name, ms, base/ms, total)
+	}
+}
+
+func main() {
+	sizes()
+	sharing()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// False sharing: процессор кэширует память строками по 64 байта (на
+// Apple M и части ARM - по 128). Когда ядро пишет в строку, копии этой
+// строки в кэшах других ядер становятся недействительными. Если
+// счетчики разных горутин лежат в одной строке, каждая запись одного
+// ядра выбивает строку у остальных - горутины ничего не делят по смыслу,
+// но дерутся за строку кэша, и запись идет со скоростью обмена между
+// ядрами, а не кэша L1.
+//
+// Лечится раскладкой: у каждого счетчика своя строка (padding до
+// cacheLine), или счет в локальной переменной и одна запись в конце.
+// На одном ядре эффекта нет - ядру не с кем делить строку.
+
+// cacheLine размер строки кэша на x86-64. Для переносимого кода есть
+// golang.org/x/sys/cpu.CacheLinePad с размером под платформу.
+const cacheLine = 64
+
+// counter счетчик горутины
+type counter interface {
+	add()
+	load() int64
+}
+
+// packed счетчики вплотную: восемь int64 в одной строке кэша
+type packed struct {
+	n atomic.Int64
+}
+
+func (c *packed) add()        { c.n.Add(1) }
+func (c *packed) load() int64 { return c.n.Load() }
+
+// padded счетчик на всю строку кэша: соседний начинается со следующей
+type padded struct {
+	n atomic.Int64
+	_ [cacheLine - 8]byte
+}
+
+func (c *padded) add()        { c.n.Add(1) }
+func (c *padded) load() int64 { return c.n.Load() }
+
+// hammer запускает workers горутин, каждая n раз увеличивает свой
+// счетчик из одного среза. Возвращает сумму - для проверки.
+func hammer[C any, P interface {
+	*C
+	counter
+}](workers, n int) int64 {
+	slots := make([]C, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := P(&slots[i])
+			for range n {
+				c.add()
+			}
+		}()
+	}
+	wg.Wait()
+	
+	var total int64
+	for i := range slots {
+		total += P(&slots[i]).load()
+	}
+	return total
+}
+
+// hammerLocal считает в локальной переменной и пишет в общий срез один
+// раз: соседство в памяти уже не важно, строку трогают workers раз за
+// все время, а не workers*n
+func hammerLocal(workers, n int) int64 {
+	slots := make([]packed, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local int64
+			for range n {
+				local++
+			}
+			slots[i].n.Add(local)
+		}()
+	}
+	wg.Wait()
+	
+	var total int64
+	for i := range slots {
+		total += slots[i].load()
+	}
+	return total
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Elector выборы лидера на блокировке с арендой. Все кандидаты пытаются
+// захватить одну блокировку; кто захватил - лидер, пока продлевает
+// аренду. Если лидер упал или потерял связь с хранилищем, аренда
+// истекает и ее забирает другой кандидат.
+type Elector struct {
+	lock  Locker
+	name  string // Имя блокировки - одно на всех кандидатов
+	id    string // Уникальный идентификатор кандидата
+	ttl   time.Duration
+	renew time.Duration // Как часто продлевать аренду и пытаться захватить
+
+	leader atomic.Bool
+}
+
+// NewElector создает кандидата. Аренда продлевается трижды за ttl:
+// одно-два неудачных продления из-за сбоя сети лидерство не теряют.
+func NewElector(lock Locker, name, id string, ttl time.Duration) *Elector {
+	return &Elector{lock: lock, name: name, id: id, ttl: ttl, renew: ttl / 3}
+}
+
+// IsLeader сообщает, лидер ли кандидат сейчас
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run участвует в выборах до отмены ctx. Став лидером, вызывает lead с
+// контекстом, который отменяется при потере лидерства; lead обязан на
+// это реагировать и вернуть управление. При отмене ctx лидер освобождает
+// блокировку, чтобы преемник не ждал конца аренды.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context, token int64)) {
+	ticker := time.NewTicker(e.renew)
+	defer ticker.Stop()
+	for {
+		// Время засекается до запроса: аренда на сервере началась не
+		// раньше, и локальный срок получается с запасом
+		start := time.Now()
+		lease, err := e.lock.Acquire(ctx, e.name, e.id, e.ttl)
+		switch {
+		case err == nil:
+			e.lead(ctx, lease, start, lead)
+		case !errors.Is(err, ErrNotAcquired) && ctx.Err() == nil:
+			log.Printf("[%s] Ошибка захвата блокировки: %v", e.id, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead держит лидерство: продлевает аренду, пока это удается, и
+// останавливает lead не позже, чем аренда истечет по локальным часам
+func (e *Elector) lead(ctx context.Context, lease Lease, start time.Time, lead func(context.Context, int64)) {
+	log.Printf("[%s] Стал лидером, токен %d", e.id, lease.Token)
+	e.leader.Store(true)
+	defer e.leader.Store(false)
+	
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leaderCtx, lease.Token)
+	}()
+	stepDown := func(reason string) {
+		cancel()
+		<-done
+		log.Printf("[%s] Больше не лидер: %s", e.id, reason)
+	}
+	
+	// expiry срабатывает, когда аренда истекла бы без продлений: после
+	// этого другой кандидат вправе ее забрать, и мы обязаны уже не
+	// работать - даже если хранилище недоступно и мы этого не узнаем.
+	// Срок берется с запасом в десятую часть ttl: остановка lead и
+	// расхождение часов тоже занимают время.
+	expiry := time.NewTimer(time.Until(e.deadline(start)))
+	defer expiry.Stop()
+	ticker := time.NewTicker(e.renew)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			stepDown("остановка")
+			releaseCtx, cancelRelease := context.WithTimeout(context.Background(), time.Second)
+			defer cancelRelease()
+			if err := e.lock.Release(releaseCtx, lease); err != nil {
+				log.Printf("[%s] Ошибка освобождения блокировки: %v", e.id, err)
+			}
+			return
+		
+		case <-done:
+			// lead завершился сам - уступаем лидерство
+			e.lock.Release(context.Background(), lease)
+			log.Printf("[%s] Работа лидера завершена", e.id)
+			return
+		
+		case <-expiry.C:
+			stepDown("аренда истекла, продлить не удалось")
+			return
+		
+		case <-ticker.C:
+			start := time.Now()
+			// Продление не может ждать дольше периода: зависший запрос
+			// не должен задержать реакцию на истечение аренды
+			renewCtx, cancelRenew := context.WithTimeout(ctx, e.renew)
+			renewed, err := e.lock.Renew(renewCtx, lease, e.ttl)
+			cancelRenew()
+			if errors.Is(err, ErrLockLost) {
+				stepDown("аренду забрал другой кандидат")
+				return
+			}
+			if err != nil {
+				// Временная ошибка: лидерство сохраняется до expiry
+				log.Printf("[%s] Ошибка продления: %v", e.id, err)
+				continue
+			}
+			lease = renewed
+			expiry.Reset(time.Until(e.deadline(start)))
+		}
+	}
+}
+
+// deadline момент, когда лидер обязан остановиться, если аренда,
+// запрошенная в start, не продлена
+func (e *Elector) deadline(start time.Time) time.Time {
+	return start.Add(e.ttl - e.ttl/10)
+}
\ No newline at end of file
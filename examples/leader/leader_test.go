@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestDB(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := openDB(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+	if err := migrate(context.Background(), db); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return path
+}
+
+// newTestLock блокировка с управляемыми часами
+func newTestLock(t *testing.T) (*SQLiteLock, *time.Time) {
+	t.Helper()
+	db, err := openDB(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lock := NewSQLiteLock(db)
+	lock.now = func() time.Time { return now }
+	return lock, &now
+}
+
+func TestSQLiteLockLease(t *testing.T) {
+	lock, now := newTestLock(t)
+	ctx := context.Background()
+	
+	a, err := lock.Acquire(ctx, "job", "a", 10*time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a.Token != 1 {
+		t.Errorf("Expected token 1, got %d", a.Token)
+	}
+	if _, err := lock.Acquire(ctx, "job", "b", 10*time.Second); !errors.Is(err, ErrNotAcquired) {
+		t.Errorf("Expected ErrNotAcquired, got %v", err)
+	}
+	if _, err := lock.Acquire(ctx, "other", "b", 10*time.Second); err != nil {
+		t.Errorf("Expected independent lock, got %v", err)
+	}
+	
+	// Продление сдвигает срок: через 15 секунд от захвата аренда жива
+	*now = now.Add(8 * time.Second)
+	if a, err = lock.Renew(ctx, a, 10*time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	*now = now.Add(7 * time.Second)
+	if h, err := lock.Holder(ctx, "job"); err != nil || h.Owner != "a" {
+		t.Errorf("Expected holder a, got %+v, %v", h, err)
+	}
+	
+	// Без продления аренда истекает, и ее забирает другой с новым токеном
+	*now = now.Add(5 * time.Second)
+	if _, err := lock.Holder(ctx, "job"); !errors.Is(err, ErrNotAcquired) {
+		t.Errorf("Expected expired lease, got %v", err)
+	}
+	b, err := lock.Acquire(ctx, "job", "b", 10*time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if b.Token != 2 {
+		t.Errorf("Expected token 2, got %d", b.Token)
+	}
+	if _, err := lock.Renew(ctx, a, 10*time.Second); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Expected ErrLockLost for old owner, got %v", err)
+	}
+	
+	// Освобождение старым владельцем не трогает чужую аренду
+	if err := lock.Release(ctx, a); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if h, _ := lock.Holder(ctx, "job"); h.Owner != "b" {
+		t.Errorf("Expected holder b, got %+v", h)
+	}
+	if err := lock.Release(ctx, b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c, err := lock.Acquire(ctx, "job", "c", 10*time.Second)
+	if err != nil {
+		t.Fatalf("Expected immediate acquire after release, got %v", err)
+	}
+	if c.Token != 3 {
+		t.Errorf("Expected token 3 after release, got %d", c.Token)
+	}
+}
+
+func TestSQLiteLockExpiredRenew(t *testing.T) {
+	lock, now := newTestLock(t)
+	ctx := context.Background()
+	a, err := lock.Acquire(ctx, "job", "a", time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	*now = now.Add(time.Second)
+	if _, err := lock.Renew(ctx, a, time.Second); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Expected ErrLockLost for expired lease, got %v", err)
+	}
+}
+
+func TestSQLiteLockConcurrentAcquire(t *testing.T) {
+	path := newTestDB(t)
+	ctx := context.Background()
+	const candidates = 10
+	
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := range candidates {
+		db, err := openDB(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer db.Close()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := NewSQLiteLock(db).Acquire(ctx, "job", string(rune('a'+i)), time.Minute)
+			if err == nil {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrNotAcquired) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("Expected exactly 1 winner, got %d", winners)
+	}
+}
+
+func TestJournalRejectsStaleToken(t *testing.T) {
+	db, err := openDB(newTestDB(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+	journal := NewJournal(db)
+	ctx := context.Background()
+	
+	tests := []struct {
+		token   int64
+		wantErr error
+	}{
+		{1, nil},
+		{1, nil},
+		{2, nil},
+		{1, ErrStaleToken}, // Проснувшийся старый лидер
+		{3, nil},
+	}
+	for _, tt := range tests {
+		err := journal.Record(ctx, Run{Job: "report", Node: "n", Token: tt.token, RanAt: time.Now()})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Token %d: expected %v, got %v", tt.token, tt.wantErr, err)
+		}
+	}
+	runs, err := journal.Runs(ctx, "report")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(runs) != 4 {
+		t.Errorf("Expected 4 accepted runs, got %d", len(runs))
+	}
+}
+
+// leaderCount считает одновременных лидеров
+type leaderCount struct {
+	mu      sync.Mutex
+	current int
+	max     int
+	terms   []int64
+}
+
+func (c *leaderCount) lead(ctx context.Context, token int64) {
+	c.mu.Lock()
+	c.current++
+	c.max = max(c.max, c.current)
+	c.terms = append(c.terms, token)
+	c.mu.Unlock()
+	<-ctx.Done()
+	c.mu.Lock()
+	c.current--
+	c.mu.Unlock()
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestElectorFailover(t *testing.T) {
+	path := newTestDB(t)
+	const ttl = 300 * time.Millisecond
+	var count leaderCount
+	
+	type candidate struct {
+		lock    *partitionLock
+		elector *Elector
+		stop    context.CancelFunc
+		done    chan struct{}
+	}
+	var candidates []*candidate
+	for _, id := range []string{"a", "b", "c"} {
+		db, err := openDB(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer db.Close()
+		lock := &partitionLock{Locker: NewSQLiteLock(db)}
+		ctx, stop := context.WithCancel(context.Background())
+		c := &candidate{lock: lock, elector: NewElector(lock, "job", id, ttl), stop: stop, done: make(chan struct{})}
+		go func() {
+			defer close(c.done)
+			c.elector.Run(ctx, count.lead)
+		}()
+		defer func() {
+			stop()
+			<-c.done
+		}()
+		candidates = append(candidates, c)
+	}
+	leader := func(except *candidate) *candidate {
+		for _, c := range candidates {
+			if c != except && c.elector.IsLeader() {
+				return c
+			}
+		}
+		return nil
+	}
+	
+	waitFor(t, 2*time.Second, func() bool { return leader(nil) != nil })
+	first := leader(nil)
+	
+	// Отрезанный лидер сдает лидерство сам, другой занимает место
+	first.lock.partitioned.Store(true)
+	waitFor(t, 3*ttl, func() bool { return !first.elector.IsLeader() })
+	waitFor(t, 3*ttl, func() bool { return leader(first) != nil })
+	first.lock.partitioned.Store(false)
+	
+	// Штатная остановка освобождает блокировку: преемник быстрее ttl
+	second := leader(nil)
+	stopped := time.Now()
+	second.stop()
+	<-second.done
+	waitFor(t, 3*ttl, func() bool { return leader(second) != nil })
+	if elapsed := time.Since(stopped); elapsed >= ttl {
+		t.Errorf("Expected failover faster than ttl after release, took %v", elapsed)
+	}
+	
+	count.mu.Lock()
+	defer count.mu.Unlock()
+	if count.max != 1 {
+		t.Errorf("Expected at most 1 leader at a time, got %d", count.max)
+	}
+	for i := 1; i < len(count.terms); i++ {
+		if count.terms[i] <= count.terms[i-1] {
+			t.Errorf("Expected increasing tokens, got %v", count.terms)
+		}
+	}
+	if len(count.terms) < 3 {
+		t.Errorf("Expected at least 3 terms, got %v", count.terms)
+	}
+}
\ No newline at end of file
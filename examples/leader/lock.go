@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Ошибки блокировки
+var (
+	ErrNotAcquired = errors.New("lock is held by another owner")
+	ErrLockLost    = errors.New("lease expired or taken over")
+)
+
+// Lease аренда блокировки. Token растет с каждым новым владельцем - это
+// fencing token: хранилище, куда пишет лидер, отвергает записи со
+// старым токеном. Без него лидер, уснувший на GC-паузе дольше срока
+// аренды, проснется и запишет поверх нового лидера, не узнав, что его
+// уже сменили.
+type Lease struct {
+	Name      string
+	Owner     string
+	Token     int64
+	ExpiresAt time.Time
+}
+
+// Locker блокировка с арендой. Реализация на SQLite работает для
+// процессов на одной машине (общий файл БД); между машинами то же самое
+// делают на Redis:
+//
+//	захват:     SET lock:<name> <owner> NX PX <ttl>
+//	продление:  EVAL "if redis.call('GET', KEYS[1]) == ARGV[1] then
+//	                    return redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+//	                  return 0" 1 lock:<name> <owner> <ttl>
+//	токен:      INCR lock:<name>:token после успешного захвата
+//
+// Проверка владельца и продление в Redis обязаны быть одним Lua-скриптом:
+// между GET и PEXPIRE аренда может истечь и достаться другому.
+type Locker interface {
+	// Acquire захватывает свободную или истекшую блокировку
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, error)
+	// Renew продлевает аренду, если она еще принадлежит владельцу
+	Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error)
+	// Release освобождает блокировку досрочно
+	Release(ctx context.Context, lease Lease) error
+}
+
+// SQLiteLock аренды в таблице SQLite. Каждая операция - один UPDATE или
+// upsert с условием в WHERE: проверка "свободна ли" и захват атомарны
+// без явных транзакций.
+type SQLiteLock struct {
+	db  *sql.DB
+	now func() time.Time // Подменяется в тестах
+}
+
+// NewSQLiteLock создает блокировки поверх db
+func NewSQLiteLock(db *sql.DB) *SQLiteLock {
+	return &SQLiteLock{db: db, now: time.Now}
+}
+
+// Migrate создает таблицу аренд. Строка блокировки не удаляется даже
+// при освобождении: в ней хранится последний выданный токен.
+func (l *SQLiteLock) Migrate(ctx context.Context) error {
+	_, err := l.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS leases (
+		name TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		token INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL
+	)`)
+	return err
+}
+
+// Acquire захватывает блокировку, если ее нет или аренда истекла. Время
+// берется с часов процесса: для нескольких машин их расхождение должно
+// быть много меньше ttl, иначе часы одной из них решат, что чужая
+// аренда уже истекла.
+func (l *SQLiteLock) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, error) {
+	now := l.now()
+	lease := Lease{Name: name, Owner: owner, ExpiresAt: now.Add(ttl)}
+	// Если строка есть и аренда жива, WHERE в DO UPDATE не пропускает
+	// обновление и RETURNING не возвращает строк
+	err := l.db.QueryRowContext(ctx, `
+		INSERT INTO leases (name, owner, token, expires_at) VALUES (?, ?, 1, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			owner = excluded.owner, token = leases.token + 1, expires_at = excluded.expires_at
+		WHERE leases.expires_at <= ?
+		RETURNING token`,
+		name, owner, lease.ExpiresAt.UnixMilli(), now.UnixMilli()).Scan(&lease.Token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Lease{}, ErrNotAcquired
+	}
+	if err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Renew продлевает живую аренду. Истекшую не продлевает, даже если ее
+// никто не успел забрать: лидер, пропустивший срок, должен был уже
+// остановиться, и молча продолжить - значит работать без гарантий.
+func (l *SQLiteLock) Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error) {
+	now := l.now()
+	expires := now.Add(ttl)
+	res, err := l.db.ExecContext(ctx, `
+		UPDATE leases SET expires_at = ?
+		WHERE name = ? AND owner = ? AND token = ? AND expires_at > ?`,
+		expires.UnixMilli(), lease.Name, lease.Owner, lease.Token, now.UnixMilli())
+	if err != nil {
+		return Lease{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return Lease{}, ErrLockLost
+	}
+	lease.ExpiresAt = expires
+	return lease, nil
+}
+
+// Release освобождает блокировку, если она еще наша. Следующий
+// кандидат захватит ее сразу, не дожидаясь конца аренды.
+func (l *SQLiteLock) Release(ctx context.Context, lease Lease) error {
+	_, err := l.db.ExecContext(ctx, `
+		UPDATE leases SET expires_at = 0 WHERE name = ? AND owner = ? AND token = ?`,
+		lease.Name, lease.Owner, lease.Token)
+	return err
+}
+
+// Holder возвращает текущую живую аренду; ErrNotAcquired - если
+// блокировка свободна
+func (l *SQLiteLock) Holder(ctx context.Context, name string) (Lease, error) {
+	lease := Lease{Name: name}
+	var expires int64
+	err := l.db.QueryRowContext(ctx, `
+		SELECT owner, token, expires_at FROM leases WHERE name = ? AND expires_at > ?`,
+		name, l.now().UnixMilli()).Scan(&lease.Owner, &lease.Token, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Lease{}, ErrNotAcquired
+	}
+	if err != nil {
+		return Lease{}, err
+	}
+	lease.ExpiresAt = time.UnixMilli(expires)
+	return lease, nil
+}
\ No newline at end of file
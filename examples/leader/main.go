@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Распределенная блокировка и выборы лидера: несколько экземпляров
+// сервиса, и только один из них - лидер - запускает задачи по
+// расписанию. Лидерство - это аренда блокировки, которую нужно
+// продлевать; упавший лидер перестает продлевать, и через ttl его место
+// занимает другой.
+//
+//   lock.go      - блокировка с арендой и fencing token на SQLite
+//                  (и как то же сделать на Redis)
+//   election.go  - цикл выборов: захват, продление, сдача лидерства
+//   scheduler.go - работа лидера и журнал, отвергающий устаревший токен
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск: go run .  - три узла в одном процессе, сбой и смена лидера
+// Отдельными процессами, в нескольких терминалах:
+//   go run . -db /tmp/leader.db -node a
+//   go run . -db /tmp/leader.db -node b
+// и остановить лидера через Ctrl+C (преемник сразу) или kill -9
+// (преемник через ttl).
+
+const (
+	lockName = "scheduler"
+	jobName  = "daily-report"
+)
+
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	if err := NewSQLiteLock(db).Migrate(ctx); err != nil {
+		return err
+	}
+	return NewJournal(db).Migrate(ctx)
+}
+
+// partitionLock декоратор, имитирующий потерю связи узла с хранилищем:
+// пока partitioned, все операции завершаются ошибкой
+type partitionLock struct {
+	Locker
+	partitioned atomic.Bool
+}
+
+var errPartitioned = errors.New("network partition")
+
+func (p *partitionLock) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, error) {
+	if p.partitioned.Load() {
+		return Lease{}, errPartitioned
+	}
+	return p.Locker.Acquire(ctx, name, owner, ttl)
+}
+
+func (p *partitionLock) Renew(ctx context.Context, lease Lease, ttl time.Duration) (Lease, error) {
+	if p.partitioned.Load() {
+		return Lease{}, errPartitioned
+	}
+	return p.Locker.Renew(ctx, lease, ttl)
+}
+
+func (p *partitionLock) Release(ctx context.Context, lease Lease) error {
+	if p.partitioned.Load() {
+		return errPartitioned
+	}
+	return p.Locker.Release(ctx, lease)
+}
+
+// node экземпляр сервиса со своим подключением к БД, как у отдельного
+// процесса
+type node struct {
+	id      string
+	db      *sql.DB
+	lock    *partitionLock
+	elector *Elector
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+func startNode(path, id string, ttl time.Duration) (*node, error) {
+	db, err := openDB(path)
+	if err != nil {
+		return nil, err
+	}
+	lock := &partitionLock{Locker: NewSQLiteLock(db)}
+	scheduler := NewScheduler(NewJournal(db), id, jobName, ttl/2)
+	ctx, stop := context.WithCancel(context.Background())
+	n := &node{id: id, db: db, lock: lock, elector: NewElector(lock, lockName, id, ttl), stop: stop, done: make(chan struct{})}
+	go func() {
+		defer close(n.done)
+		n.elector.Run(ctx, scheduler.Lead)
+	}()
+	return n, nil
+}
+
+func (n *node) shutdown() {
+	n.stop()
+	<-n.done
+	n.db.Close()
+}
+
+// waitLeader ждет, пока лидером станет кто-то из узлов
+func waitLeader(nodes []*node, except *node, timeout time.Duration) *node {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		for _, n := range nodes {
+			if n != except && n.elector.IsLeader() {
+				return n
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// Пример 1: три узла, лидер один
+// Пример 2: лидер теряет связь с БД - сдает лидерство по истечении
+// аренды, преемник захватывает блокировку
+// Пример 3: лидер останавливается штатно и освобождает блокировку -
+// преемник не ждет ttl
+func demo(path string) error {
+	const ttl = 600 * time.Millisecond
+	var nodes []*node
+	var wg sync.WaitGroup
+	defer func() {
+		for _, n := range nodes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				n.shutdown()
+			}()
+		}
+		wg.Wait()
+	}()
+	for _, id := range []string{"node-a", "node-b", "node-c"} {
+		n, err := startNode(path, id, ttl)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, n)
+	}
+	
+	fmt.Println("=== Выборы ===")
+	leader := waitLeader(nodes, nil, 2*time.Second)
+	if leader == nil {
+		return errors.New("no leader elected")
+	}
+	time.Sleep(2 * ttl)
+	
+	fmt.Printf("\n=== Потеря связи: %s отрезан от БД ===\n", leader.id)
+	cut := time.Now()
+	leader.lock.partitioned.Store(true)
+	next := waitLeader(nodes, leader, 3*ttl)
+	if next == nil {
+		return errors.New("no failover after partition")
+	}
+	fmt.Printf("Новый лидер %s через %v (ttl %v)\n", next.id, time.Since(cut).Round(10*time.Millisecond), ttl)
+	leader.lock.partitioned.Store(false)
+	time.Sleep(2 * ttl)
+	
+	fmt.Printf("\n=== Штатная остановка лидера %s ===\n", next.id)
+	stopped := time.Now()
+	next.shutdown()
+	nodes = removeNode(nodes, next)
+	third := waitLeader(nodes, nil, 3*ttl)
+	if third == nil {
+		return errors.New("no failover after shutdown")
+	}
+	fmt.Printf("Новый лидер %s через %v - блокировка освобождена, ждать ttl не нужно\n",
+		third.id, time.Since(stopped).Round(10*time.Millisecond))
+	time.Sleep(ttl)
+	return nil
+}
+
+func removeNode(nodes []*node, n *node) []*node {
+	for i := range nodes {
+		if nodes[i] == n {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
+// printJournal показывает, что задачу в каждый момент выполнял один
+// узел, а токены растут со сменой лидера
+func printJournal(path string) error {
+	db, err := openDB(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := NewJournal(db).Runs(context.Background(), jobName)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n=== Журнал запусков ===")
+	for i := 0; i < len(runs); {
+		j := i
+		for j < len(runs) && runs[j].Token == runs[i].Token {
+			j++
+		}
+		fmt.Printf("  токен %d: %s, запусков: %d\n", runs[i].Token, runs[i].Node, j-i)
+		i = j
+	}
+	return nil
+}
+
+// runNode один узел до Ctrl+C - для запуска в нескольких терминалах
+func runNode(path, id string, ttl time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	db, err := openDB(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := migrate(ctx, db); err != nil {
+		return err
+	}
+	scheduler := NewScheduler(NewJournal(db), id, jobName, time.Second)
+	log.Printf("[%s] Кандидат запущен", id)
+	NewElector(NewSQLiteLock(db), lockName, id, ttl).Run(ctx, scheduler.Lead)
+	return nil
+}
+
+func main() {
+	dbPath := flag.String("db", "", "путь к файлу SQLite (по умолчанию - временный)")
+	nodeID := flag.String("node", "", "запустить один узел с этим именем")
+	ttl := flag.Duration("ttl", 5*time.Second, "срок аренды для -node")
+	flag.Parse()
+	log.SetFlags(log.Ltime | log.Lmicroseconds)
+	
+	path := *dbPath
+	if path == "" {
+		dir, err := os.MkdirTemp("", "leader")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		path = filepath.Join(dir, "leader.db")
+	}
+	
+	if *nodeID != "" {
+		if err := runNode(path, *nodeID, *ttl); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	
+	db, err := openDB(path)
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	err = migrate(context.Background(), db)
+	db.Close()
+	if err != nil {
+		log.Fatal("Ошибка миграции:", err)
+	}
+	if err := demo(path); err != nil {
+		log.Fatal(err)
+	}
+	if err := printJournal(path); err != nil {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrStaleToken запись от лидера, которого уже сменили
+var ErrStaleToken = errors.New("stale fencing token")
+
+// Journal хранилище, которое проверяет fencing token: принимает запись,
+// только если токен не меньше последнего принятого. Проверка стоит на
+// стороне хранилища, а не лидера - лидер как раз может не знать, что
+// его сменили.
+type Journal struct {
+	db *sql.DB
+}
+
+// NewJournal создает журнал запусков
+func NewJournal(db *sql.DB) *Journal {
+	return &Journal{db: db}
+}
+
+// Migrate создает таблицы журнала
+func (j *Journal) Migrate(ctx context.Context) error {
+	_, err := j.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS fences (
+		resource TEXT PRIMARY KEY,
+		token INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job TEXT NOT NULL,
+		node TEXT NOT NULL,
+		token INTEGER NOT NULL,
+		ran_at INTEGER NOT NULL
+	);`)
+	return err
+}
+
+// Run запись о запуске задачи
+type Run struct {
+	Job   string
+	Node  string
+	Token int64
+	RanAt time.Time
+}
+
+// Record записывает запуск задачи, если токен не устарел. Проверка
+// токена и запись - в одной транзакции.
+func (j *Journal) Record(ctx context.Context, r Run) error {
+	tx, err := j.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	var token int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO fences (resource, token) VALUES (?, ?)
+		ON CONFLICT (resource) DO UPDATE SET token = excluded.token
+		WHERE excluded.token >= fences.token
+		RETURNING token`, r.Job, r.Token).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrStaleToken
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO runs (job, node, token, ran_at) VALUES (?, ?, ?, ?)`,
+		r.Job, r.Node, r.Token, r.RanAt.UnixMilli()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Runs возвращает запуски задачи по порядку
+func (j *Journal) Runs(ctx context.Context, job string) ([]Run, error) {
+	rows, err := j.db.QueryContext(ctx, `SELECT job, node, token, ran_at FROM runs WHERE job = ? ORDER BY id`, job)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var runs []Run
+	for rows.Next() {
+		var r Run
+		var ranAt int64
+		if err := rows.Scan(&r.Job, &r.Node, &r.Token, &ranAt); err != nil {
+			return nil, err
+		}
+		r.RanAt = time.UnixMilli(ranAt)
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// Scheduler работа лидера: запускает задачу по расписанию. Работает
+// только на лидере - иначе каждый экземпляр сервиса отправил бы свою
+// копию ежедневного отчета.
+type Scheduler struct {
+	journal  *Journal
+	node     string
+	job      string
+	interval time.Duration
+}
+
+// NewScheduler создает планировщик одной задачи
+func NewScheduler(journal *Journal, node, job string, interval time.Duration) *Scheduler {
+	return &Scheduler{journal: journal, node: node, job: job, interval: interval}
+}
+
+// Lead запускает задачу до отмены ctx; подходит как функция для
+// Elector.Run
+func (s *Scheduler) Lead(ctx context.Context, token int64) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := s.journal.Record(ctx, Run{Job: s.job, Node: s.node, Token: token, RanAt: time.Now()})
+		switch {
+		case errors.Is(err, ErrStaleToken):
+			log.Printf("[%s] Запись отклонена: токен %d устарел", s.node, token)
+			return
+		case err != nil && ctx.Err() == nil:
+			log.Printf("[%s] Ошибка записи: %v", s.node, err)
+		case err == nil:
+			log.Printf("[%s] Задача %s выполнена, токен %d", s.node, s.job, token)
+		}
+	}
+}
\ No newline at end of file
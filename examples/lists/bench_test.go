@@ -0,0 +1,144 @@
+package main
+
+import (
+	"container/list"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Нагрузка с частыми вставками: в начало, в середину и упорядоченная.
+// Запуск: go test -bench=. -benchmem
+
+const benchN = 10000
+
+func BenchmarkPushFront(b *testing.B) {
+	b.Run("slice", func(b *testing.B) {
+		for b.Loop() {
+			var s []int
+			for i := 0; i < benchN; i++ {
+				s = slices.Insert(s, 0, i) // Сдвиг всех элементов: O(n)
+			}
+		}
+	})
+	b.Run("doubly", func(b *testing.B) {
+		for b.Loop() {
+			var l DoublyList[int]
+			for i := 0; i < benchN; i++ {
+				l.PushFront(i)
+			}
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		for b.Loop() {
+			l := list.New()
+			for i := 0; i < benchN; i++ {
+				l.PushFront(i)
+			}
+		}
+	})
+}
+
+func BenchmarkPushBack(b *testing.B) {
+	b.Run("slice", func(b *testing.B) {
+		for b.Loop() {
+			var s []int
+			for i := 0; i < benchN; i++ {
+				s = append(s, i)
+			}
+		}
+	})
+	b.Run("singly", func(b *testing.B) {
+		for b.Loop() {
+			var l SinglyList[int]
+			for i := 0; i < benchN; i++ {
+				l.PushBack(i)
+			}
+		}
+	})
+}
+
+// Вставка в середину по известному узлу: список O(1), слайс O(n)
+func BenchmarkInsertMiddle(b *testing.B) {
+	b.Run("slice", func(b *testing.B) {
+		for b.Loop() {
+			s := make([]int, 0, benchN)
+			for i := 0; i < benchN; i++ {
+				s = slices.Insert(s, len(s)/2, i)
+			}
+		}
+	})
+	b.Run("doubly", func(b *testing.B) {
+		for b.Loop() {
+			var l DoublyList[int]
+			mid := l.PushBack(0)
+			for i := 1; i < benchN; i++ {
+				l.InsertAfter(i, mid)
+			}
+		}
+	})
+}
+
+// Упорядоченные вставки случайных ключей с последующим обходом по порядку
+func BenchmarkOrderedInsert(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchN)
+	
+	b.Run("sorted slice", func(b *testing.B) {
+		for b.Loop() {
+			s := make([]int, 0, benchN)
+			for _, k := range keys {
+				i, _ := slices.BinarySearch(s, k)
+				s = slices.Insert(s, i, k)
+			}
+		}
+	})
+	b.Run("skiplist", func(b *testing.B) {
+		for b.Loop() {
+			s := NewSkipList[int, struct{}](1)
+			for _, k := range keys {
+				s.Set(k, struct{}{})
+			}
+			for range s.All() {
+			}
+		}
+	})
+	b.Run("map+sort", func(b *testing.B) {
+		for b.Loop() {
+			m := make(map[int]struct{})
+			for _, k := range keys {
+				m[k] = struct{}{}
+			}
+			sorted := make([]int, 0, len(m))
+			for k := range m {
+				sorted = append(sorted, k)
+			}
+			slices.Sort(sorted)
+		}
+	})
+}
+
+// Поиск: map вне конкуренции, но не хранит порядок
+func BenchmarkLookup(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchN)
+	s := NewSkipList[int, int](1)
+	m := make(map[int]int)
+	for _, k := range keys {
+		s.Set(k, k)
+		m[k] = k
+	}
+	
+	b.Run("skiplist", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			s.Get(keys[i%benchN])
+			i++
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		i := 0
+		for b.Loop() {
+			_ = m[keys[i%benchN]]
+			i++
+		}
+	})
+}
\ No newline at end of file
@@ -0,0 +1,138 @@
+package main
+
+import "iter"
+
+// DoublyList двусвязный список с ограничителем (sentinel): root.next - первый
+// элемент, root.prev - последний. Ограничитель избавляет от проверок на nil
+// при вставке и удалении. Так же устроен container/list.
+type DoublyList[T any] struct {
+	root Element[T]
+	size int
+}
+
+// Element узел двусвязного списка. Получив его при вставке, можно
+// удалить узел или вставить рядом за O(1) - на этом строится LRU-кеш.
+type Element[T any] struct {
+	Value      T
+	prev, next *Element[T]
+	list       *DoublyList[T]
+}
+
+// Next возвращает следующий элемент или nil
+func (e *Element[T]) Next() *Element[T] {
+	if n := e.next; e.list != nil && n != &e.list.root {
+		return n
+	}
+	return nil
+}
+
+// Prev возвращает предыдущий элемент или nil
+func (e *Element[T]) Prev() *Element[T] {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+func (l *DoublyList[T]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+func (l *DoublyList[T]) insertAfter(v T, at *Element[T]) *Element[T] {
+	e := &Element[T]{Value: v, prev: at, next: at.next, list: l}
+	at.next.prev = e
+	at.next = e
+	l.size++
+	return e
+}
+
+// PushFront добавляет элемент в начало
+func (l *DoublyList[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insertAfter(v, &l.root)
+}
+
+// PushBack добавляет элемент в конец
+func (l *DoublyList[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insertAfter(v, l.root.prev)
+}
+
+// InsertAfter вставляет элемент после mark
+func (l *DoublyList[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insertAfter(v, mark)
+}
+
+// Remove удаляет элемент за O(1)
+func (l *DoublyList[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		e.prev.next = e.next
+		e.next.prev = e.prev
+		// Обнуляем ссылки: удаленный узел не должен удерживать соседей
+		e.prev, e.next, e.list = nil, nil, nil
+		l.size--
+	}
+	return e.Value
+}
+
+// MoveToFront перемещает элемент в начало
+func (l *DoublyList[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = &l.root
+	e.next = l.root.next
+	l.root.next.prev = e
+	l.root.next = e
+}
+
+// Front возвращает первый элемент или nil
+func (l *DoublyList[T]) Front() *Element[T] {
+	if l.size == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back возвращает последний элемент или nil
+func (l *DoublyList[T]) Back() *Element[T] {
+	if l.size == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// Len возвращает число элементов
+func (l *DoublyList[T]) Len() int {
+	return l.size
+}
+
+// All перебирает элементы от начала к концу
+func (l *DoublyList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward перебирает элементы от конца к началу
+func (l *DoublyList[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Back(); e != nil; e = e.Prev() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestSinglyList(t *testing.T) {
+	var l SinglyList[int]
+	for i := 1; i <= 3; i++ {
+		l.PushBack(i)
+	}
+	l.PushFront(0)
+	
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("Expected [0 1 2 3], got %v", got)
+	}
+	
+	l.Reverse()
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{3, 2, 1, 0}) {
+		t.Errorf("Expected [3 2 1 0], got %v", got)
+	}
+	
+	// После разворота хвост должен указывать на новый последний элемент
+	l.PushBack(-1)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []int{3, 2, 1, 0, -1}) {
+		t.Errorf("Expected [3 2 1 0 -1], got %v", got)
+	}
+	
+	for l.Len() > 0 {
+		l.PopFront()
+	}
+	if _, ok := l.PopFront(); ok {
+		t.Error("Expected PopFront on empty list to fail")
+	}
+}
+
+func TestDoublyList(t *testing.T) {
+	var l DoublyList[string]
+	a := l.PushBack("a")
+	b := l.PushBack("b")
+	l.PushFront("start")
+	l.InsertAfter("a2", a)
+	
+	if got := slices.Collect(l.All()); !slices.Equal(got, []string{"start", "a", "a2", "b"}) {
+		t.Errorf("Unexpected order: %v", got)
+	}
+	
+	l.Remove(a)
+	l.MoveToFront(b)
+	if got := slices.Collect(l.All()); !slices.Equal(got, []string{"b", "start", "a2"}) {
+		t.Errorf("Unexpected order: %v", got)
+	}
+	if got := slices.Collect(l.Backward()); !slices.Equal(got, []string{"a2", "start", "b"}) {
+		t.Errorf("Unexpected reverse order: %v", got)
+	}
+	
+	// Повторное удаление и вставка после чужого узла безопасны
+	l.Remove(a)
+	if l.Len() != 3 {
+		t.Errorf("Expected len 3, got %d", l.Len())
+	}
+	var other DoublyList[string]
+	if other.InsertAfter("x", b) != nil {
+		t.Error("Expected InsertAfter with foreign mark to fail")
+	}
+}
+
+func TestSkipListMatchesMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	s := NewSkipList[int, int](7)
+	model := map[int]int{}
+	
+	for i := 0; i < 20000; i++ {
+		k := rng.Intn(1000)
+		switch rng.Intn(3) {
+		case 0, 1:
+			s.Set(k, i)
+			model[k] = i
+		case 2:
+			_, existed := model[k]
+			if s.Delete(k) != existed {
+				t.Fatalf("Step %d: Delete(%d) mismatch", i, k)
+			}
+			delete(model, k)
+		}
+	}
+	
+	if s.Len() != len(model) {
+		t.Fatalf("Expected len %d, got %d", len(model), s.Len())
+	}
+	
+	var keys []int
+	for k, v := range s.All() {
+		if model[k] != v {
+			t.Errorf("Key %d: expected %d, got %d", k, model[k], v)
+		}
+		keys = append(keys, k)
+	}
+	if !sort.IntsAreSorted(keys) {
+		t.Error("Expected keys in ascending order")
+	}
+}
+
+func TestSkipListRange(t *testing.T) {
+	s := NewSkipList[int, string](1)
+	for _, k := range []int{50, 10, 40, 20, 30} {
+		s.Set(k, "")
+	}
+	
+	var got []int
+	for k := range s.Range(15, 40) {
+		got = append(got, k)
+	}
+	if !slices.Equal(got, []int{20, 30}) {
+		t.Errorf("Expected [20 30], got %v", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+func printComplexity() {
+	fmt.Println("=== Сложность операций ===")
+	fmt.Println(`
+Операция              Слайс      Односвязный  Двусвязный   Skip list     map
+--------------------  ---------  -----------  -----------  ------------  ------
+Доступ по индексу     O(1)       O(n)         O(n)         O(n)          -
+Поиск по ключу        O(n)*      O(n)         O(n)         O(log n)      O(1)
+Вставка в начало      O(n)       O(1)         O(1)         -             -
+Вставка в конец       O(1)**     O(1)         O(1)         -             -
+Вставка по узлу       O(n)       O(1)***      O(1)         O(log n)      O(1)
+Удаление по узлу      O(n)       O(n)         O(1)         O(log n)      O(1)
+Упорядоченный обход   после sort -            -            O(n)          нет
+Диапазон [a, b)       O(log n)*  -            -            O(log n + k)  нет
+
+*   в отсортированном слайсе - бинарный поиск, но вставка остается O(n)
+**  амортизированно
+*** только после известного узла
+
+На практике слайс часто быстрее списка даже там, где асимптотика хуже:
+элементы лежат подряд и хорошо попадают в кеш процессора, а узлы списка
+разбросаны по куче. См. бенчмарки: go test -bench=. -benchmem`)
+}
+
+// Пример 1: Односвязный список
+func singlyExample() {
+	fmt.Println("\n=== Односвязный список ===")
+	
+	var l SinglyList[string]
+	for _, s := range []string{"b", "c", "d"} {
+		l.PushBack(s)
+	}
+	l.PushFront("a")
+	fmt.Println("Список:", slices.Collect(l.All()))
+	
+	l.Reverse()
+	fmt.Println("Развернут:", slices.Collect(l.All()))
+	
+	v, _ := l.PopFront()
+	fmt.Printf("PopFront: %s, осталось %d\n", v, l.Len())
+}
+
+// Пример 2: Двусвязный список - история браузера
+func doublyExample() {
+	fmt.Println("\n=== Двусвязный список ===")
+	
+	var history DoublyList[string]
+	var pages []*Element[string]
+	for _, url := range []string{"/home", "/catalog", "/item/42", "/cart"} {
+		pages = append(pages, history.PushBack(url))
+	}
+	fmt.Println("История:", slices.Collect(history.All()))
+	fmt.Println("В обратном порядке:", slices.Collect(history.Backward()))
+	
+	// Удаление и перемещение по ссылке на узел - O(1), без поиска
+	history.Remove(pages[1])
+	history.MoveToFront(pages[3])
+	fmt.Println("После Remove и MoveToFront:", slices.Collect(history.All()))
+}
+
+// Пример 3: Skip list - упорядоченный словарь
+func skipListExample() {
+	fmt.Println("\n=== Skip list ===")
+	
+	scores := NewSkipList[int, string](1)
+	players := map[string]int{"alice": 1200, "bob": 950, "carol": 1480, "dave": 1100, "eve": 1330}
+	for name, score := range players {
+		scores.Set(score, name)
+	}
+	
+	fmt.Println("По возрастанию рейтинга:")
+	for score, name := range scores.All() {
+		fmt.Printf("  %d %s\n", score, name)
+	}
+	
+	fmt.Println("Рейтинг в [1100, 1400):")
+	for score, name := range scores.Range(1100, 1400) {
+		fmt.Printf("  %d %s\n", score, name)
+	}
+	
+	scores.Delete(950)
+	_, ok := scores.Get(950)
+	fmt.Println("950 после удаления:", ok)
+	
+	big := NewSkipList[int, struct{}](42)
+	for i := 0; i < 10000; i++ {
+		big.Set(i, struct{}{})
+	}
+	fmt.Println("Узлов на уровнях для 10000 ключей (каждый ~вдвое меньше):")
+	for i, n := range big.Levels() {
+		fmt.Printf("  уровень %2d: %5d %s\n", i, n, strings.Repeat("#", (n+199)/200))
+	}
+}
+
+func main() {
+	printComplexity()
+	singlyExample()
+	doublyExample()
+	skipListExample()
+}
\ No newline at end of file
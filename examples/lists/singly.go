@@ -0,0 +1,81 @@
+package main
+
+import "iter"
+
+// SinglyList односвязный список: каждый узел знает только следующий.
+// Вставка в начало O(1), в конец O(1) благодаря указателю на хвост,
+// удаление из конца - O(n): предыдущий узел приходится искать.
+type SinglyList[T any] struct {
+	head, tail *singlyNode[T]
+	size       int
+}
+
+type singlyNode[T any] struct {
+	value T
+	next  *singlyNode[T]
+}
+
+// PushFront добавляет элемент в начало
+func (l *SinglyList[T]) PushFront(v T) {
+	n := &singlyNode[T]{value: v, next: l.head}
+	l.head = n
+	if l.tail == nil {
+		l.tail = n
+	}
+	l.size++
+}
+
+// PushBack добавляет элемент в конец
+func (l *SinglyList[T]) PushBack(v T) {
+	n := &singlyNode[T]{value: v}
+	if l.tail == nil {
+		l.head, l.tail = n, n
+	} else {
+		l.tail.next = n
+		l.tail = n
+	}
+	l.size++
+}
+
+// PopFront удаляет и возвращает первый элемент
+func (l *SinglyList[T]) PopFront() (v T, ok bool) {
+	if l.head == nil {
+		return v, false
+	}
+	n := l.head
+	l.head = n.next
+	if l.head == nil {
+		l.tail = nil
+	}
+	l.size--
+	return n.value, true
+}
+
+// Reverse разворачивает список на месте - классическая задача с собеседований
+func (l *SinglyList[T]) Reverse() {
+	var prev *singlyNode[T]
+	cur := l.head
+	l.tail = l.head
+	for cur != nil {
+		next := cur.next
+		cur.next = prev
+		prev, cur = cur, next
+	}
+	l.head = prev
+}
+
+// Len возвращает число элементов
+func (l *SinglyList[T]) Len() int {
+	return l.size
+}
+
+// All перебирает элементы от начала к концу
+func (l *SinglyList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := l.head; n != nil; n = n.next {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+	"math/rand"
+)
+
+// SkipList - упорядоченный словарь на списке с "экспресс-полосами".
+// Нижний уровень - обычный отсортированный связный список; каждый
+// следующий содержит примерно половину узлов предыдущего. Поиск спускается
+// сверху вниз, пропуская большие участки: O(log n) в среднем.
+//
+// По сравнению со сбалансированными деревьями: проще реализация
+// (нет поворотов), легко сделать конкурентной. Используется в Redis
+// (sorted sets), LevelDB/RocksDB (memtable).
+type SkipList[K cmp.Ordered, V any] struct {
+	head  *skipNode[K, V]
+	level int // Текущее число уровней
+	size  int
+	rng   *rand.Rand
+}
+
+const (
+	maxLevel = 32
+	// Вероятность продвинуть узел на уровень выше
+	promoteP = 0.5
+)
+
+type skipNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*skipNode[K, V] // next[i] - следующий узел на уровне i
+}
+
+// NewSkipList создает пустой список. seed делает структуру воспроизводимой.
+func NewSkipList[K cmp.Ordered, V any](seed int64) *SkipList[K, V] {
+	return &SkipList[K, V]{
+		head:  &skipNode[K, V]{next: make([]*skipNode[K, V], maxLevel)},
+		level: 1,
+		rng:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *SkipList[K, V]) randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && s.rng.Float64() < promoteP {
+		lvl++
+	}
+	return lvl
+}
+
+// findPrev заполняет update[i] последним узлом на уровне i с ключом < key
+func (s *SkipList[K, V]) findPrev(key K, update []*skipNode[K, V]) *skipNode[K, V] {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// Set вставляет или обновляет значение
+func (s *SkipList[K, V]) Set(key K, value V) {
+	var update [maxLevel]*skipNode[K, V]
+	if n := s.findPrev(key, update[:]); n != nil && n.key == key {
+		n.value = value
+		return
+	}
+	
+	lvl := s.randomLevel()
+	if lvl > s.level {
+		for i := s.level; i < lvl; i++ {
+			update[i] = s.head
+		}
+		s.level = lvl
+	}
+	
+	n := &skipNode[K, V]{key: key, value: value, next: make([]*skipNode[K, V], lvl)}
+	for i := 0; i < lvl; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	s.size++
+}
+
+// Get ищет значение по ключу
+func (s *SkipList[K, V]) Get(key K) (v V, ok bool) {
+	if n := s.findPrev(key, nil); n != nil && n.key == key {
+		return n.value, true
+	}
+	return v, false
+}
+
+// Delete удаляет ключ; возвращает false, если его не было
+func (s *SkipList[K, V]) Delete(key K) bool {
+	var update [maxLevel]*skipNode[K, V]
+	n := s.findPrev(key, update[:])
+	if n == nil || n.key != key {
+		return false
+	}
+	
+	for i := 0; i < len(n.next); i++ {
+		update[i].next[i] = n.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.size--
+	return true
+}
+
+// Len возвращает число ключей
+func (s *SkipList[K, V]) Len() int {
+	return s.size
+}
+
+// All перебирает пары в порядке возрастания ключей
+func (s *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := s.head.next[0]; n != nil; n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range перебирает пары с ключами в [from, to) - то, чего не умеет map
+func (s *SkipList[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := s.findPrev(from, nil); n != nil && n.key < to; n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Levels возвращает число узлов на каждом уровне - для наглядности
+func (s *SkipList[K, V]) Levels() []int {
+	counts := make([]int, s.level)
+	for i := 0; i < s.level; i++ {
+		for n := s.head.next[i]; n != nil; n = n.next[i] {
+			counts[i]++
+		}
+	}
+	return counts
+}
\ No newline at end of file
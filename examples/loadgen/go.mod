@@ -0,0 +1,7 @@
+module loadgen
+
+go 1.22
+
+require streamstats v0.0.0
+
+replace streamstats => ../streamstats
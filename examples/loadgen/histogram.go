@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Histogram гистограмма задержек с логарифмическими корзинами.
+// Память постоянна при любом числе запросов, а точность -
+// около 10% на каждом порядке величины, чего хватает для перцентилей.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []int64 // buckets[i] - запросы в [bound(i-1), bound(i))
+	count   int64
+	sum     time.Duration
+	min     time.Duration
+	max     time.Duration
+}
+
+const (
+	histMin    = 10 * time.Microsecond
+	histGrowth = 1.1
+	histSize   = 150 // 10мкс * 1.1^150 ~ 16 минут
+)
+
+// NewHistogram создает пустую гистограмму
+func NewHistogram() *Histogram {
+	return &Histogram{buckets: make([]int64, histSize+1)}
+}
+
+func bucketFor(d time.Duration) int {
+	if d < histMin {
+		return 0
+	}
+	i := int(math.Log(float64(d)/float64(histMin))/math.Log(histGrowth)) + 1
+	if i > histSize {
+		return histSize
+	}
+	return i
+}
+
+func bucketUpper(i int) time.Duration {
+	return time.Duration(float64(histMin) * math.Pow(histGrowth, float64(i)))
+}
+
+// Record добавляет одно измерение
+func (h *Histogram) Record(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	
+	h.buckets[bucketFor(d)]++
+	h.count++
+	h.sum += d
+	if h.count == 1 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// Percentile возвращает верхнюю границу корзины, в которую попал p-й перцентиль
+func (h *Histogram) Percentile(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	
+	if h.count == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(p / 100 * float64(h.count)))
+	var seen int64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			return min(bucketUpper(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// Print выводит сводку и текстовую гистограмму
+func (h *Histogram) Print(w io.Writer) {
+	h.mu.Lock()
+	count, sum, lo, hi := h.count, h.sum, h.min, h.max
+	h.mu.Unlock()
+	
+	if count == 0 {
+		fmt.Fprintln(w, "Нет измерений")
+		return
+	}
+	
+	fmt.Fprintf(w, "Задержка: min=%v avg=%v max=%v\n", lo, sum/time.Duration(count), hi)
+	for _, p := range []float64{50, 90, 95, 99, 99.9} {
+		fmt.Fprintf(w, "  p%-5v %v\n", p, h.Percentile(p))
+	}
+	
+	// Схлопываем корзины в 10 строк для наглядности
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	first, last := -1, 0
+	for i, n := range h.buckets {
+		if n > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	step := (last-first)/10 + 1
+	var maxRow int64
+	rows := make([]int64, 0, 10)
+	for i := first; i <= last; i += step {
+		var n int64
+		for j := i; j < i+step && j <= last; j++ {
+			n += h.buckets[j]
+		}
+		rows = append(rows, n)
+		maxRow = max(maxRow, n)
+	}
+	for r, n := range rows {
+		upper := bucketUpper(min(first+(r+1)*step-1, last))
+		bar := strings.Repeat("#", int(40*n/maxRow))
+		fmt.Fprintf(w, "  <%-10v %7d %s\n", upper.Round(time.Microsecond), n, bar)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"streamstats"
+)
+
+// Config параметры нагрузки
+type Config struct {
+	URL         string
+	Concurrency int
+	Duration    time.Duration
+	Rampdown    time.Duration
+	Timeout     time.Duration
+	Progress    time.Duration // Интервал живого прогресса, 0 - выключен
+}
+
+// Result итоги прогона
+type Result struct {
+	Latency  *Histogram
+	mu       sync.Mutex
+	statuses map[string]int
+	total    atomic.Int64
+	
+	// Живые показатели: текущий rps за последние 5 секунд, сглаженная
+	// задержка и потоковый p99 - видно, как ведет себя сервис во время
+	// теста, а не только в итоге
+	rate *streamstats.RateCounter
+	avg  *streamstats.EWMA
+	p99  *streamstats.P2
+}
+
+func (r *Result) count(status string) {
+	r.mu.Lock()
+	r.statuses[status]++
+	r.mu.Unlock()
+}
+
+// Run генерирует нагрузку до истечения Duration или отмены ctx.
+// Последние Rampdown воркеры останавливаются по одному,
+// поэтому нагрузка снижается плавно, а не обрывается разом.
+func Run(ctx context.Context, cfg Config) *Result {
+	res := &Result{
+		Latency:  NewHistogram(),
+		statuses: make(map[string]int),
+		rate:     streamstats.NewRateCounter(5*time.Second, 10),
+		avg:      streamstats.NewEWMA(time.Second),
+		p99:      streamstats.NewP2(0.99),
+	}
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: cfg.Concurrency,
+		},
+	}
+	
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+	
+	if cfg.Progress > 0 {
+		go res.report(ctx, cfg.Progress, os.Stdout)
+	}
+	
+	rampStart := cfg.Duration - cfg.Rampdown
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		// Воркер i останавливается в свою долю фазы снижения
+		stopAfter := cfg.Duration
+		if cfg.Rampdown > 0 {
+			stopAfter = rampStart + cfg.Rampdown*time.Duration(i+1)/time.Duration(cfg.Concurrency)
+		}
+		workerCtx, workerCancel := context.WithTimeout(ctx, stopAfter)
+		
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer workerCancel()
+			for workerCtx.Err() == nil {
+				hit(workerCtx, client, cfg.URL, res)
+			}
+		}()
+	}
+	
+	wg.Wait()
+	return res
+}
+
+// hit выполняет один запрос и записывает результат
+func hit(ctx context.Context, client *http.Client, url string, res *Result) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		res.count("invalid request")
+		return
+	}
+	
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		// Запросы, прерванные окончанием теста, не считаем ошибками сервера
+		if ctx.Err() == nil {
+			res.count("error")
+			res.total.Add(1)
+		}
+		return
+	}
+	io.Copy(io.Discard, resp.Body) // Дочитываем тело, чтобы соединение вернулось в пул
+	resp.Body.Close()
+	
+	now := time.Now()
+	latency := now.Sub(start)
+	res.Latency.Record(latency)
+	res.rate.Add(1)
+	res.avg.Observe(float64(latency), now)
+	res.p99.Add(float64(latency))
+	res.count(fmt.Sprint(resp.StatusCode))
+	res.total.Add(1)
+}
+
+// report печатает живой прогресс каждые interval до отмены ctx
+func (r *Result) report(ctx context.Context, interval time.Duration, w io.Writer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Fprintf(w, "[%4.1fs] %6.0f rps  задержка ~%v  p99 ~%v\n",
+				time.Since(start).Seconds(), r.rate.Rate(),
+				time.Duration(r.avg.Value()).Round(time.Microsecond),
+				time.Duration(r.p99.Value()).Round(time.Microsecond))
+		}
+	}
+}
+
+// Print выводит отчет
+func (r *Result) Print(w io.Writer, elapsed time.Duration) {
+	total := r.total.Load()
+	fmt.Fprintf(w, "Запросов: %d за %v (%.0f rps)\n", total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+	
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.statuses))
+	for k := range r.statuses {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintln(w, "Статусы:")
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %-8s %d\n", k, r.statuses[k])
+	}
+	r.mu.Unlock()
+	
+	r.Latency.Print(w)
+}
+
+// localUsersAPI поднимает in-process копию users API, если цель не указана
+func localUsersAPI() *httptest.Server {
+	users := []map[string]any{
+		{"id": 1, "name": "Иван Иванов", "email": "ivan@example.com"},
+		{"id": 2, "name": "Мария Петрова", "email": "maria@example.com"},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Имитация работы с разбросом и редкими ошибками
+		time.Sleep(time.Duration(rand.Intn(3000)) * time.Microsecond)
+		if rand.Intn(100) == 0 {
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(users)
+	}))
+}
+
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.URL, "url", "", "цель, например http://localhost:8080/api/users (по умолчанию - встроенный сервер)")
+	flag.IntVar(&cfg.Concurrency, "c", 10, "число параллельных воркеров")
+	flag.DurationVar(&cfg.Duration, "d", 5*time.Second, "длительность теста")
+	flag.DurationVar(&cfg.Rampdown, "rampdown", time.Second, "длительность плавного снижения нагрузки в конце")
+	flag.DurationVar(&cfg.Timeout, "timeout", 2*time.Second, "таймаут одного запроса")
+	flag.DurationVar(&cfg.Progress, "progress", time.Second, "интервал живого прогресса (0 - выключить)")
+	flag.Parse()
+	
+	if cfg.Concurrency < 1 || cfg.Rampdown > cfg.Duration {
+		log.Fatal("Нужно -c >= 1 и -rampdown <= -d")
+	}
+	
+	if cfg.URL == "" {
+		srv := localUsersAPI()
+		defer srv.Close()
+		cfg.URL = srv.URL + "/api/users"
+	}
+	
+	// Ctrl+C завершает тест досрочно, но отчет все равно печатается
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	
+	fmt.Printf("Нагрузка на %s: %d воркеров, %v (снижение %v)\n\n", cfg.URL, cfg.Concurrency, cfg.Duration, cfg.Rampdown)
+	start := time.Now()
+	res := Run(ctx, cfg)
+	res.Print(os.Stdout, time.Since(start))
+}
\ No newline at end of file
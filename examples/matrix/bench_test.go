@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// go test -bench Mul -benchmem
+func BenchmarkMul(b *testing.B) {
+	const n = 256
+	x, y := random(n), random(n)
+	workers := runtime.GOMAXPROCS(0)
+	
+	variants := []struct {
+		name string
+		mul  func() (*Matrix, error)
+	}{
+		{"naive", func() (*Matrix, error) { return x.MulNaive(y) }},
+		{"ikj", func() (*Matrix, error) { return x.Mul(y) }},
+		{"blocked-32", func() (*Matrix, error) { return x.MulBlocked(y, 32) }},
+		{"blocked-64", func() (*Matrix, error) { return x.MulBlocked(y, 64) }},
+		{fmt.Sprintf("parallel-64-x%d", workers), func() (*Matrix, error) { return x.MulParallel(y, 64, workers) }},
+	}
+	for _, v := range variants {
+		b.Run(v.name, func(b *testing.B) {
+			for b.Loop() {
+				v.mul()
+			}
+		})
+	}
+}
+
+func BenchmarkConvolve(b *testing.B) {
+	src := FromGray(testImage(320, 240))
+	kernel := GaussianKernel(2, 1)
+	for b.Loop() {
+		src.Convolve(kernel)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Свертка: каждый пиксель результата - взвешенная сумма соседей,
+// веса задает ядро. Изображение в оттенках серого - это та же
+// матрица яркостей, поэтому все фильтры ниже - операции над Matrix.
+
+// Ядра 3x3
+var (
+	BoxBlur, _ = FromRows([][]float64{
+		{1, 1, 1},
+		{1, 1, 1},
+		{1, 1, 1},
+	})
+	Sharpen, _ = FromRows([][]float64{
+		{0, -1, 0},
+		{-1, 5, -1},
+		{0, -1, 0},
+	})
+	SobelX, _ = FromRows([][]float64{
+		{-1, 0, 1},
+		{-2, 0, 2},
+		{-1, 0, 1},
+	})
+)
+
+// GaussianKernel ядро размытия по Гауссу размером (2r+1)x(2r+1),
+// нормированное к сумме 1, чтобы не менять общую яркость
+func GaussianKernel(r int, sigma float64) *Matrix {
+	k := New(2*r+1, 2*r+1)
+	var sum float64
+	for i := -r; i <= r; i++ {
+		for j := -r; j <= r; j++ {
+			v := math.Exp(-float64(i*i+j*j) / (2 * sigma * sigma))
+			k.Set(i+r, j+r, v)
+			sum += v
+		}
+	}
+	return k.Scale(1 / sum)
+}
+
+// FromGray переводит изображение в матрицу яркостей [0, 1]
+func FromGray(img *image.Gray) *Matrix {
+	b := img.Bounds()
+	m := New(b.Dy(), b.Dx())
+	for y := range m.rows {
+		for x := range m.cols {
+			m.Set(y, x, float64(img.GrayAt(b.Min.X+x, b.Min.Y+y).Y)/255)
+		}
+	}
+	return m
+}
+
+// ToGray переводит матрицу обратно, обрезая значения до [0, 1]
+func (m *Matrix) ToGray() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, m.cols, m.rows))
+	for y := range m.rows {
+		for x := range m.cols {
+			v := min(max(m.At(y, x), 0), 1)
+			img.SetGray(x, y, color.Gray{Y: uint8(math.Round(v * 255))})
+		}
+	}
+	return img
+}
+
+// Convolve применяет ядро к матрице. За краем изображения берется
+// ближайший краевой пиксель - иначе по краям появится темная рамка.
+// Строго говоря, это корреляция (ядро не отражается), но для
+// симметричных ядер разницы нет, и в обработке изображений обе
+// операции обычно называют сверткой.
+func (m *Matrix) Convolve(kernel *Matrix) *Matrix {
+	ry, rx := kernel.rows/2, kernel.cols/2
+	out := New(m.rows, m.cols)
+	for y := range m.rows {
+		for x := range m.cols {
+			var sum float64
+			for ky := range kernel.rows {
+				sy := min(max(y+ky-ry, 0), m.rows-1)
+				row := m.Row(sy)
+				for kx, w := range kernel.Row(ky) {
+					sx := min(max(x+kx-rx, 0), m.cols-1)
+					sum += w * row[sx]
+				}
+			}
+			out.Set(y, x, sum)
+		}
+	}
+	return out
+}
+
+// EdgeMagnitude величина градиента по Собелю: sqrt(gx^2 + gy^2).
+// Ядро по вертикали - транспонированное SobelX.
+func (m *Matrix) EdgeMagnitude() *Matrix {
+	gx := m.Convolve(SobelX)
+	gy := m.Convolve(SobelX.Transpose())
+	out := New(m.rows, m.cols)
+	for i := range out.data {
+		out.data[i] = math.Hypot(gx.data[i], gy.data[i])
+	}
+	return out
+}
+
+// testImage рисует круг на горизонтальном градиенте
+func testImage(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	cx, cy, r := float64(w)/2, float64(h)/2, float64(min(w, h))/3
+	for y := range h {
+		for x := range w {
+			v := uint8(40 + 80*x/w)
+			if math.Hypot(float64(x)-cx, float64(y)-cy) < r {
+				v = 220
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
\ No newline at end of file
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Запуск: go run .
+// Бенчмарки: go test -bench . -benchmem
+// Изображения примера 3 сохраняются во временный каталог (путь в выводе).
+
+// Пример 1: Основные операции
+func basics() {
+	fmt.Println("=== Основные операции ===")
+	
+	a, _ := FromRows([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+	b, _ := FromRows([][]float64{
+		{7, 8},
+		{9, 10},
+		{11, 12},
+	})
+	
+	c, _ := a.Mul(b)
+	fmt.Printf("A (2x3) * B (3x2) =\n%s", c)
+	fmt.Printf("Aᵀ =\n%s", a.Transpose())
+	
+	ai, _ := a.Mul(Identity(3))
+	fmt.Printf("A * I == A: %v\n", ai.Equal(a, 0))
+	
+	// (AB)ᵀ = BᵀAᵀ
+	left := c.Transpose()
+	right, _ := b.Transpose().Mul(a.Transpose())
+	fmt.Printf("(AB)ᵀ == BᵀAᵀ: %v\n", left.Equal(right, 1e-12))
+	
+	_, err := a.Mul(a)
+	fmt.Printf("A * A: %v\n", err)
+}
+
+func random(n int) *Matrix {
+	m := New(n, n)
+	for i := range m.data {
+		m.data[i] = rand.Float64()
+	}
+	return m
+}
+
+// Пример 2: Порядок циклов, блоки и горутины
+func performance() {
+	fmt.Println("\n=== Умножение 512x512 ===")
+	
+	const n = 512
+	a, b := random(n), random(n)
+	workers := runtime.GOMAXPROCS(0)
+	
+	var reference *Matrix
+	variants := []struct {
+		name string
+		mul  func() (*Matrix, error)
+	}{
+		{"наивное i-j-k", func() (*Matrix, error) { return a.MulNaive(b) }},
+		{"i-k-j", func() (*Matrix, error) { return a.Mul(b) }},
+		{"блоки 64", func() (*Matrix, error) { return a.MulBlocked(b, 64) }},
+		{fmt.Sprintf("блоки 64, горутин: %d", workers), func() (*Matrix, error) { return a.MulParallel(b, 64, workers) }},
+	}
+	for _, v := range variants {
+		start := time.Now()
+		c, _ := v.mul()
+		elapsed := time.Since(start)
+		if reference == nil {
+			reference = c
+		}
+		// 2n³ операций с плавающей точкой (умножение и сложение)
+		gflops := 2 * float64(n*n*n) / elapsed.Seconds() / 1e9
+		fmt.Printf("%-26s %10v  %5.2f GFLOPS  совпадает: %v\n",
+			v.name, elapsed.Round(time.Millisecond), gflops, c.Equal(reference, 1e-9))
+	}
+}
+
+// Пример 3: Свертка изображения
+func convolution() {
+	fmt.Println("\n=== Свертка изображения ===")
+	
+	dir, err := os.MkdirTemp("", "matrix-")
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	src := FromGray(testImage(240, 160))
+	filters := []struct {
+		name string
+		out  *Matrix
+	}{
+		{"original", src},
+		{"box-blur", src.Convolve(BoxBlur.Scale(1.0 / 9))},
+		{"gaussian", src.Convolve(GaussianKernel(3, 1.5))},
+		{"sharpen", src.Convolve(Sharpen)},
+		{"edges", src.EdgeMagnitude().Scale(0.25)},
+	}
+	for _, f := range filters {
+		path := filepath.Join(dir, f.name+".png")
+		if err := savePNG(path, f.out.ToGray()); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%-9s %s\n", f.name, preview(f.out, 60))
+	}
+	fmt.Println("PNG сохранены в", dir)
+}
+
+// preview рисует среднюю строку матрицы символами по яркости; каждый
+// символ - максимум своего отрезка строки, чтобы тонкие края не терялись
+func preview(m *Matrix, width int) string {
+	const shades = " .:-=+*#%@"
+	row := m.Row(m.rows / 2)
+	var sb strings.Builder
+	for i := range width {
+		v := 0.0
+		for _, x := range row[i*len(row)/width : (i+1)*len(row)/width] {
+			v = max(v, x)
+		}
+		v = min(v, 1)
+		sb.WriteByte(shades[int(v*float64(len(shades)-1))])
+	}
+	return "|" + sb.String() + "|"
+}
+
+func savePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func main() {
+	basics()
+	performance()
+	convolution()
+}
\ No newline at end of file
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrShape размеры матриц не подходят для операции
+var ErrShape = errors.New("matrix shape mismatch")
+
+// Matrix плотная матрица float64. Элементы лежат в одном слайсе по
+// строкам (row-major): строка - непрерывный кусок памяти, и проход
+// по ней идет по кеш-линиям подряд. [][]float64 разбросал бы строки
+// по куче и добавил бы косвенное обращение на каждый элемент.
+type Matrix struct {
+	rows, cols int
+	data       []float64
+}
+
+// New создает нулевую матрицу rows x cols
+func New(rows, cols int) *Matrix {
+	return &Matrix{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+// Identity создает единичную матрицу n x n
+func Identity(n int) *Matrix {
+	m := New(n, n)
+	for i := range n {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// FromRows создает матрицу из строк одинаковой длины
+func FromRows(rows [][]float64) (*Matrix, error) {
+	if len(rows) == 0 {
+		return New(0, 0), nil
+	}
+	m := New(len(rows), len(rows[0]))
+	for i, row := range rows {
+		if len(row) != m.cols {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d: %w", i, len(row), m.cols, ErrShape)
+		}
+		copy(m.data[i*m.cols:], row)
+	}
+	return m, nil
+}
+
+// Dims возвращает число строк и столбцов
+func (m *Matrix) Dims() (rows, cols int) {
+	return m.rows, m.cols
+}
+
+// At возвращает элемент (i, j)
+func (m *Matrix) At(i, j int) float64 {
+	return m.data[i*m.cols+j]
+}
+
+// Set устанавливает элемент (i, j)
+func (m *Matrix) Set(i, j int, v float64) {
+	m.data[i*m.cols+j] = v
+}
+
+// Row возвращает строку i без копирования
+func (m *Matrix) Row(i int) []float64 {
+	return m.data[i*m.cols : (i+1)*m.cols]
+}
+
+// Transpose возвращает транспонированную матрицу
+func (m *Matrix) Transpose() *Matrix {
+	t := New(m.cols, m.rows)
+	for i := range m.rows {
+		for j := range m.cols {
+			t.Set(j, i, m.At(i, j))
+		}
+	}
+	return t
+}
+
+// Scale возвращает матрицу, умноженную на число
+func (m *Matrix) Scale(k float64) *Matrix {
+	r := New(m.rows, m.cols)
+	for i, v := range m.data {
+		r.data[i] = v * k
+	}
+	return r
+}
+
+// MulNaive умножение по определению: c[i][j] = sum a[i][k] * b[k][j].
+// Внутренний цикл идет по столбцу b - шаг в cols элементов, и на
+// больших матрицах почти каждое обращение - промах кеша.
+func (m *Matrix) MulNaive(b *Matrix) (*Matrix, error) {
+	if m.cols != b.rows {
+		return nil, fmt.Errorf("%dx%d * %dx%d: %w", m.rows, m.cols, b.rows, b.cols, ErrShape)
+	}
+	c := New(m.rows, b.cols)
+	for i := range m.rows {
+		for j := range b.cols {
+			var sum float64
+			for k := range m.cols {
+				sum += m.At(i, k) * b.At(k, j)
+			}
+			c.Set(i, j, sum)
+		}
+	}
+	return c, nil
+}
+
+// Mul умножение с порядком циклов i-k-j: a[i][k] фиксирован, а b и c
+// читаются и пишутся по строкам подряд. Та же арифметика, но в разы
+// быстрее на больших матрицах - только за счет доступа к памяти.
+func (m *Matrix) Mul(b *Matrix) (*Matrix, error) {
+	if m.cols != b.rows {
+		return nil, fmt.Errorf("%dx%d * %dx%d: %w", m.rows, m.cols, b.rows, b.cols, ErrShape)
+	}
+	c := New(m.rows, b.cols)
+	for i := range m.rows {
+		cRow := c.Row(i)
+		for k := range m.cols {
+			a := m.At(i, k)
+			for j, bv := range b.Row(k) {
+				cRow[j] += a * bv
+			}
+		}
+	}
+	return c, nil
+}
+
+// Equal сравнивает матрицы с допуском eps: после сложений в разном
+// порядке результаты float64 расходятся в последних битах
+func (m *Matrix) Equal(b *Matrix, eps float64) bool {
+	if m.rows != b.rows || m.cols != b.cols {
+		return false
+	}
+	for i, v := range m.data {
+		if math.Abs(v-b.data[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Matrix) String() string {
+	var sb strings.Builder
+	for i := range m.rows {
+		sb.WriteString("[")
+		for j := range m.cols {
+			if j > 0 {
+				sb.WriteString(" ")
+			}
+			fmt.Fprintf(&sb, "%6.2f", m.At(i, j))
+		}
+		sb.WriteString("]\n")
+	}
+	return sb.String()
+}
\ No newline at end of file
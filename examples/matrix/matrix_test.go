@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestMulVariantsAgree(t *testing.T) {
+	// Размеры не кратны блоку - проверяем хвосты
+	for _, dims := range [][3]int{{1, 1, 1}, {3, 5, 2}, {37, 41, 29}, {100, 70, 130}} {
+		a, b := New(dims[0], dims[1]), New(dims[1], dims[2])
+		for i := range a.data {
+			a.data[i] = float64(i%7) - 3
+		}
+		for i := range b.data {
+			b.data[i] = float64(i%5) * 0.5
+		}
+		
+		want, err := a.MulNaive(b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for name, mul := range map[string]func() (*Matrix, error){
+			"ikj":      func() (*Matrix, error) { return a.Mul(b) },
+			"blocked":  func() (*Matrix, error) { return a.MulBlocked(b, 16) },
+			"parallel": func() (*Matrix, error) { return a.MulParallel(b, 8, 4) },
+		} {
+			got, err := mul()
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if !got.Equal(want, 1e-9) {
+				t.Errorf("%s %v: result differs from naive", name, dims)
+			}
+		}
+	}
+}
+
+func TestMulShapeError(t *testing.T) {
+	a, b := New(2, 3), New(2, 3)
+	if _, err := a.Mul(b); !errors.Is(err, ErrShape) {
+		t.Errorf("Expected ErrShape, got %v", err)
+	}
+	if _, err := a.MulParallel(b, 8, 2); !errors.Is(err, ErrShape) {
+		t.Errorf("Expected ErrShape, got %v", err)
+	}
+	if _, err := FromRows([][]float64{{1, 2}, {3}}); !errors.Is(err, ErrShape) {
+		t.Errorf("Expected ErrShape for ragged rows, got %v", err)
+	}
+}
+
+func TestIdentityAndTranspose(t *testing.T) {
+	a, _ := FromRows([][]float64{{1, 2, 3}, {4, 5, 6}})
+	left, _ := Identity(2).Mul(a)
+	right, _ := a.Mul(Identity(3))
+	if !left.Equal(a, 0) || !right.Equal(a, 0) {
+		t.Error("Expected I*A == A*I == A")
+	}
+	if r, c := a.Transpose().Dims(); r != 3 || c != 2 {
+		t.Errorf("Expected 3x2, got %dx%d", r, c)
+	}
+	if !a.Transpose().Transpose().Equal(a, 0) {
+		t.Error("Expected (Aᵀ)ᵀ == A")
+	}
+}
+
+func TestConvolve(t *testing.T) {
+	flat := New(5, 5)
+	for i := range flat.data {
+		flat.data[i] = 0.5
+	}
+	
+	// Нормированные ядра не меняют однородное изображение, в том числе у краев
+	for name, k := range map[string]*Matrix{
+		"box":      BoxBlur.Scale(1.0 / 9),
+		"gaussian": GaussianKernel(2, 1),
+		"sharpen":  Sharpen,
+	} {
+		if !flat.Convolve(k).Equal(flat, 1e-12) {
+			t.Errorf("%s changed a flat image", name)
+		}
+	}
+	if e := flat.EdgeMagnitude(); !e.Equal(New(5, 5), 1e-12) {
+		t.Error("Expected no edges on a flat image")
+	}
+	
+	// Вертикальная ступенька: градиент только на границе
+	step := New(3, 6)
+	for y := range 3 {
+		for x := 3; x < 6; x++ {
+			step.Set(y, x, 1)
+		}
+	}
+	e := step.EdgeMagnitude()
+	if e.At(1, 0) != 0 || e.At(1, 5) != 0 || e.At(1, 2) == 0 || e.At(1, 3) == 0 {
+		t.Errorf("Unexpected edge response:\n%s", e)
+	}
+}
+
+func TestGaussianKernelNormalized(t *testing.T) {
+	k := GaussianKernel(3, 1.5)
+	var sum float64
+	for _, v := range k.data {
+		sum += v
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("Expected sum 1, got %v", sum)
+	}
+}
+
+func TestGrayRoundTrip(t *testing.T) {
+	img := testImage(20, 10)
+	back := FromGray(img).ToGray()
+	if back.Bounds() != image.Rect(0, 0, 20, 10) {
+		t.Fatalf("Unexpected bounds %v", back.Bounds())
+	}
+	for i, v := range img.Pix {
+		if back.Pix[i] != v {
+			t.Fatalf("Pixel %d: expected %d, got %d", i, v, back.Pix[i])
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MulBlocked умножение блоками block x block. Блоки a, b и c вместе
+// помещаются в кеш L1/L2, и каждый загруженный элемент используется
+// block раз, прежде чем будет вытеснен.
+func (m *Matrix) MulBlocked(b *Matrix, block int) (*Matrix, error) {
+	if m.cols != b.rows {
+		return nil, fmt.Errorf("%dx%d * %dx%d: %w", m.rows, m.cols, b.rows, b.cols, ErrShape)
+	}
+	c := New(m.rows, b.cols)
+	for ii := 0; ii < m.rows; ii += block {
+		mulRowBlock(m, b, c, ii, min(ii+block, m.rows), block)
+	}
+	return c, nil
+}
+
+// MulParallel блочное умножение, где полосы строк c раздаются workers
+// горутинам. Каждая горутина пишет только в свои строки c, поэтому
+// блокировки не нужны, а a и b только читаются.
+func (m *Matrix) MulParallel(b *Matrix, block, workers int) (*Matrix, error) {
+	if m.cols != b.rows {
+		return nil, fmt.Errorf("%dx%d * %dx%d: %w", m.rows, m.cols, b.rows, b.cols, ErrShape)
+	}
+	c := New(m.rows, b.cols)
+	
+	bands := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ii := range bands {
+				mulRowBlock(m, b, c, ii, min(ii+block, m.rows), block)
+			}
+		}()
+	}
+	for ii := 0; ii < m.rows; ii += block {
+		bands <- ii
+	}
+	close(bands)
+	wg.Wait()
+	return c, nil
+}
+
+// mulRowBlock считает строки [i0, i1) результата, проходя k и j блоками
+func mulRowBlock(a, b, c *Matrix, i0, i1, block int) {
+	for kk := 0; kk < a.cols; kk += block {
+		k1 := min(kk+block, a.cols)
+		for jj := 0; jj < b.cols; jj += block {
+			j1 := min(jj+block, b.cols)
+			for i := i0; i < i1; i++ {
+				cRow := c.Row(i)[jj:j1]
+				for k := kk; k < k1; k++ {
+					av := a.At(i, k)
+					bRow := b.Row(k)[jj:j1]
+					for j, bv := range bRow {
+						cRow[j] += av * bv
+					}
+				}
+			}
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// RequestInfo данные, которые сохраняются на время обработки запроса
+type RequestInfo struct {
+	ID      string
+	Path    string
+	Started time.Time
+	Body    []byte
+}
+
+// RequestTracker хранит информацию о запросах по ID
+type RequestTracker interface {
+	Track(info *RequestInfo)
+	Get(id string) (*RequestInfo, bool)
+	Len() int
+}
+
+// LeakyTracker - утечка: записи добавляются, но никогда не удаляются.
+// Карта растет вместе с числом запросов, и GC не может освободить
+// ни саму карту, ни тела запросов, на которые она ссылается.
+type LeakyTracker struct {
+	mu       sync.Mutex
+	requests map[string]*RequestInfo
+}
+
+func NewLeakyTracker() *LeakyTracker {
+	return &LeakyTracker{requests: make(map[string]*RequestInfo)}
+}
+
+func (t *LeakyTracker) Track(info *RequestInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests[info.ID] = info
+}
+
+func (t *LeakyTracker) Get(id string) (*RequestInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info, ok := t.requests[id]
+	return info, ok
+}
+
+func (t *LeakyTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.requests)
+}
+
+// TTLTracker - исправленная версия: записи старше ttl удаляются
+// фоновой горутиной, размер карты ограничен нагрузкой за ttl
+type TTLTracker struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	requests map[string]*RequestInfo
+	stop     chan struct{}
+}
+
+func NewTTLTracker(ttl time.Duration) *TTLTracker {
+	t := &TTLTracker{
+		ttl:      ttl,
+		requests: make(map[string]*RequestInfo),
+		stop:     make(chan struct{}),
+	}
+	go t.evictLoop()
+	return t
+}
+
+func (t *TTLTracker) Track(info *RequestInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests[info.ID] = info
+}
+
+func (t *TTLTracker) Get(id string) (*RequestInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info, ok := t.requests[id]
+	if !ok || time.Since(info.Started) > t.ttl {
+		return nil, false
+	}
+	return info, true
+}
+
+func (t *TTLTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.requests)
+}
+
+// Close останавливает фоновую очистку; без него утечет уже горутина
+func (t *TTLTracker) Close() {
+	close(t.stop)
+}
+
+func (t *TTLTracker) evictLoop() {
+	ticker := time.NewTicker(t.ttl / 2)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-t.stop:
+			return
+		case now := <-ticker.C:
+			t.evict(now)
+		}
+	}
+}
+
+func (t *TTLTracker) evict(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	
+	for id, info := range t.requests {
+		if now.Sub(info.Started) > t.ttl {
+			delete(t.requests, id)
+		}
+	}
+	
+	// Карта в Go не уменьшает число бакетов после delete. Если пик был
+	// большим, а затем нагрузка упала, карту имеет смысл пересоздать.
+	if len(t.requests) == 0 {
+		t.requests = make(map[string]*RequestInfo)
+	}
+}
\ No newline at end of file
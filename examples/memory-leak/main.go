@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Пошаговый поиск утечки памяти.
+//
+// 1. Запустите версию с утечкой; программа снимет два профиля кучи:
+//      go run . -mode=leaky
+//    Созданы heap-1.pb.gz (после разогрева) и heap-2.pb.gz (позже).
+//
+// 2. Посмотрите, кто держит память во втором профиле:
+//      go tool pprof -sample_index=inuse_space -top heap-2.pb.gz
+//    inuse_space - живые объекты сейчас; alloc_space - все выделения
+//    за время жизни (полезен для поиска лишних аллокаций, а не утечек).
+//
+// 3. Главный шаг - разница профилей. Всё, что стабильно, вычитается,
+//    остается только то, что выросло между двумя точками:
+//      go tool pprof -base heap-1.pb.gz -top heap-2.pb.gz
+//      go tool pprof -base heap-1.pb.gz -list 'handleRequest' heap-2.pb.gz
+//      go tool pprof -base heap-1.pb.gz -http=:8081 heap-2.pb.gz
+//    В выводе рост приходится на handleRequest (тело запроса) и
+//    LeakyTracker.Track (бакеты карты) - память держит карта трекера.
+//
+// 4. На работающем сервисе профили снимают через net/http/pprof:
+//      curl -o heap-1.pb.gz http://localhost:6060/debug/pprof/heap
+//      ... подождать ...
+//      curl -o heap-2.pb.gz http://localhost:6060/debug/pprof/heap
+//    Параметр ?gc=1 запускает GC перед снятием профиля.
+//
+// 5. Запустите исправленную версию и сравните профили:
+//      go run . -mode=fixed
+//    Разница между heap-1 и heap-2 близка к нулю, размер трекера стабилен.
+
+var requestSeq atomic.Int64
+
+// handleRequest имитирует обработку запроса, которая регистрирует его в трекере
+func handleRequest(tracker RequestTracker, path string) {
+	id := strconv.FormatInt(requestSeq.Add(1), 10)
+	info := &RequestInfo{
+		ID:      id,
+		Path:    path,
+		Started: time.Now(),
+		Body:    make([]byte, 1024), // Тело запроса удерживается вместе с записью
+	}
+	tracker.Track(info)
+	
+	// ... обработка ...
+	
+	// Ошибка: запись нужна только на время обработки, но никто ее не удаляет.
+	// Простейшее исправление здесь - defer delete; TTL нужен, когда запись
+	// должна жить дольше запроса (дедупликация, идемпотентность, трассировка).
+}
+
+// generateLoad выполняет n запросов
+func generateLoad(tracker RequestTracker, n int) {
+	for i := 0; i < n; i++ {
+		handleRequest(tracker, fmt.Sprintf("/api/items/%d", i%100))
+	}
+}
+
+// writeHeapProfile сохраняет профиль кучи в файл
+func writeHeapProfile(name string) {
+	// Профиль кучи отражает состояние на момент последнего GC;
+	// явный вызов делает данные актуальными
+	runtime.GC()
+	
+	f, err := os.Create(name)
+	if err != nil {
+		log.Fatal("Ошибка создания профиля:", err)
+	}
+	defer f.Close()
+	
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		log.Fatal("Ошибка записи профиля:", err)
+	}
+	fmt.Println("Профиль записан:", name)
+}
+
+// printHeap выводит размер кучи и трекера
+func printHeap(stage string, tracker RequestTracker) {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Printf("%-18s куча: %6.1f MiB, записей в трекере: %d\n",
+		stage, float64(m.HeapAlloc)/(1<<20), tracker.Len())
+}
+
+func main() {
+	mode := flag.String("mode", "leaky", "leaky или fixed")
+	serve := flag.Bool("serve", false, "оставить pprof-сервер работать на :6060")
+	flag.Parse()
+	
+	var tracker RequestTracker
+	switch *mode {
+	case "leaky":
+		tracker = NewLeakyTracker()
+	case "fixed":
+		ttl := NewTTLTracker(200 * time.Millisecond)
+		defer ttl.Close()
+		tracker = ttl
+	default:
+		log.Fatalf("Неизвестный режим %q", *mode)
+	}
+	
+	go func() {
+		log.Println(http.ListenAndServe("localhost:6060", nil))
+	}()
+	
+	fmt.Printf("=== Режим %s ===\n", *mode)
+	
+	// Разогрев: в первый профиль попадает все, что выделяется один раз
+	generateLoad(tracker, 20000)
+	time.Sleep(300 * time.Millisecond)
+	printHeap("После разогрева:", tracker)
+	writeHeapProfile("heap-1.pb.gz")
+	
+	// Продолжаем нагрузку: при утечке память растет линейно
+	for i := 0; i < 5; i++ {
+		generateLoad(tracker, 20000)
+		time.Sleep(300 * time.Millisecond)
+		printHeap(fmt.Sprintf("Волна %d:", i+1), tracker)
+	}
+	writeHeapProfile("heap-2.pb.gz")
+	
+	// Без этого компилятор считает tracker мертвым после последнего
+	// использования, и GC соберет карту до снятия второго профиля.
+	// В настоящем сервисе трекер жив, пока жив сервер.
+	runtime.KeepAlive(tracker)
+	
+	fmt.Println("\nСравните профили:")
+	fmt.Println("  go tool pprof -base heap-1.pb.gz -top heap-2.pb.gz")
+	
+	if *serve {
+		fmt.Println("pprof: http://localhost:6060/debug/pprof/")
+		select {}
+	}
+}
\ No newline at end of file
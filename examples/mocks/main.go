@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Моки генерируются из интерфейса, а не пишутся руками:
+//   go install go.uber.org/mock/mockgen@latest
+//   go install github.com/matryer/moq@latest
+//   go generate ./...
+//
+//go:generate mockgen -source=main.go -destination=mock_repository_test.go -package=main UserRepository
+//go:generate moq -out moq_repository_test.go . UserRepository:UserRepositoryMoq
+
+// ErrNotFound пользователь не найден
+var ErrNotFound = errors.New("пользователь не найден")
+
+// UserRepository тот же интерфейс, что и в examples/testing
+type UserRepository interface {
+	GetUser(id int) (string, error)
+	SaveUser(id int, name string) error
+}
+
+// UserService сервис с бизнес-логикой поверх репозитория
+type UserService struct {
+	repo UserRepository
+}
+
+// NewUserService создает сервис
+func NewUserService(repo UserRepository) *UserService {
+	return &UserService{repo: repo}
+}
+
+// Rename меняет имя существующего пользователя.
+// Имя нормализуется, а сохранение пропускается, если имя не изменилось.
+func (s *UserService) Rename(id int, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("имя не может быть пустым")
+	}
+	
+	current, err := s.repo.GetUser(id)
+	if err != nil {
+		return fmt.Errorf("получение пользователя %d: %w", id, err)
+	}
+	
+	if current == name {
+		return nil
+	}
+	
+	return s.repo.SaveUser(id, name)
+}
+
+// InMemoryUserRepository написанный руками fake: рабочая реализация в памяти
+type InMemoryUserRepository struct {
+	users map[int]string
+}
+
+// NewInMemoryUserRepository создает пустой репозиторий
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{users: make(map[int]string)}
+}
+
+// GetUser возвращает имя пользователя
+func (r *InMemoryUserRepository) GetUser(id int) (string, error) {
+	name, ok := r.users[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return name, nil
+}
+
+// SaveUser сохраняет имя пользователя
+func (r *InMemoryUserRepository) SaveUser(id int, name string) error {
+	r.users[id] = name
+	return nil
+}
+
+// Пример 1: Сервис с fake-репозиторием
+func fakeExample() {
+	fmt.Println("=== Сервис с fake-репозиторием ===")
+	
+	repo := NewInMemoryUserRepository()
+	repo.SaveUser(1, "Иван")
+	
+	service := NewUserService(repo)
+	if err := service.Rename(1, "  Иван Иванов  "); err != nil {
+		fmt.Println("Ошибка:", err)
+	}
+	
+	name, _ := repo.GetUser(1)
+	fmt.Printf("Новое имя: %q\n", name)
+	
+	if err := service.Rename(42, "Никто"); err != nil {
+		fmt.Println("Ошибка:", err)
+	}
+}
+
+// Пример 2: Сравнение подходов
+func compareApproaches() {
+	fmt.Println("\n=== Fake vs сгенерированные моки ===")
+	fmt.Println(`Ручной fake (InMemoryUserRepository):
+  + проверяет поведение через состояние: "после Rename имя стало X"
+  + тесты не ломаются при рефакторинге, который не меняет результат
+  - не видно, сколько раз и с какими аргументами вызывались методы
+  - нужно поддерживать руками при изменении интерфейса
+
+gomock (mock_repository_test.go):
+  + ожидания: EXPECT().GetUser(1).Return("Иван", nil).Times(1)
+  + матчеры аргументов: gomock.Any(), gomock.Eq(), gomock.Cond(...)
+  + неожиданный вызов или невыполненное ожидание - тест падает сам
+  - тест знает детали реализации и ломается при их изменении
+
+moq (moq_repository_test.go):
+  + простые структуры с полями-функциями, без DSL ожиданий
+  + записывает вызовы: len(mock.SaveUserCalls())
+  - проверки вызовов пишутся руками
+
+Тесты: go test -v (нужны go.uber.org/mock и go generate)`)
+}
+
+func main() {
+	fakeExample()
+	compareApproaches()
+}
\ No newline at end of file
@@ -0,0 +1,124 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package main
+
+import (
+	"sync"
+)
+
+// Ensure, that UserRepositoryMoq does implement UserRepository.
+// If this is not the case, regenerate this file with moq.
+var _ UserRepository = &UserRepositoryMoq{}
+
+// UserRepositoryMoq is a mock implementation of UserRepository.
+//
+//	func TestSomethingThatUsesUserRepository(t *testing.T) {
+//
+//		// make and configure a mocked UserRepository
+//		mockedUserRepository := &UserRepositoryMoq{
+//			GetUserFunc: func(id int) (string, error) {
+//				panic("mock out the GetUser method")
+//			},
+//			SaveUserFunc: func(id int, name string) error {
+//				panic("mock out the SaveUser method")
+//			},
+//		}
+//
+//		// use mockedUserRepository in code that requires UserRepository
+//		// and then make assertions.
+//
+//	}
+type UserRepositoryMoq struct {
+	// GetUserFunc mocks the GetUser method.
+	GetUserFunc func(id int) (string, error)
+
+	// SaveUserFunc mocks the SaveUser method.
+	SaveUserFunc func(id int, name string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetUser holds details about calls to the GetUser method.
+		GetUser []struct {
+			// ID is the id argument value.
+			ID int
+		}
+		// SaveUser holds details about calls to the SaveUser method.
+		SaveUser []struct {
+			// ID is the id argument value.
+			ID int
+			// Name is the name argument value.
+			Name string
+		}
+	}
+	lockGetUser  sync.RWMutex
+	lockSaveUser sync.RWMutex
+}
+
+// GetUser calls GetUserFunc.
+func (mock *UserRepositoryMoq) GetUser(id int) (string, error) {
+	if mock.GetUserFunc == nil {
+		panic("UserRepositoryMoq.GetUserFunc: method is nil but UserRepository.GetUser was just called")
+	}
+	callInfo := struct {
+		ID int
+	}{
+		ID: id,
+	}
+	mock.lockGetUser.Lock()
+	mock.calls.GetUser = append(mock.calls.GetUser, callInfo)
+	mock.lockGetUser.Unlock()
+	return mock.GetUserFunc(id)
+}
+
+// GetUserCalls gets all the calls that were made to GetUser.
+// Check the length with:
+//
+//	len(mockedUserRepository.GetUserCalls())
+func (mock *UserRepositoryMoq) GetUserCalls() []struct {
+	ID int
+} {
+	var calls []struct {
+		ID int
+	}
+	mock.lockGetUser.RLock()
+	calls = mock.calls.GetUser
+	mock.lockGetUser.RUnlock()
+	return calls
+}
+
+// SaveUser calls SaveUserFunc.
+func (mock *UserRepositoryMoq) SaveUser(id int, name string) error {
+	if mock.SaveUserFunc == nil {
+		panic("UserRepositoryMoq.SaveUserFunc: method is nil but UserRepository.SaveUser was just called")
+	}
+	callInfo := struct {
+		ID   int
+		Name string
+	}{
+		ID:   id,
+		Name: name,
+	}
+	mock.lockSaveUser.Lock()
+	mock.calls.SaveUser = append(mock.calls.SaveUser, callInfo)
+	mock.lockSaveUser.Unlock()
+	return mock.SaveUserFunc(id, name)
+}
+
+// SaveUserCalls gets all the calls that were made to SaveUser.
+// Check the length with:
+//
+//	len(mockedUserRepository.SaveUserCalls())
+func (mock *UserRepositoryMoq) SaveUserCalls() []struct {
+	ID   int
+	Name string
+} {
+	var calls []struct {
+		ID   int
+		Name string
+	}
+	mock.lockSaveUser.RLock()
+	calls = mock.calls.SaveUser
+	mock.lockSaveUser.RUnlock()
+	return calls
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+// Пример 1: gomock - ожидания и количество вызовов
+func TestRename_Gomock(t *testing.T) {
+	ctrl := gomock.NewController(t) // Finish вызывается автоматически через t.Cleanup
+	repo := NewMockUserRepository(ctrl)
+	
+	// Порядок важен: сначала чтение, потом запись
+	gomock.InOrder(
+		repo.EXPECT().GetUser(1).Return("Иван", nil).Times(1),
+		repo.EXPECT().SaveUser(1, "Иван Иванов").Return(nil).Times(1),
+	)
+	
+	service := NewUserService(repo)
+	if err := service.Rename(1, "  Иван Иванов "); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// Пример 2: gomock - проверка того, что метод НЕ вызывался
+func TestRename_Gomock_SameNameSkipsSave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockUserRepository(ctrl)
+	
+	repo.EXPECT().GetUser(gomock.Any()).Return("Иван", nil)
+	repo.EXPECT().SaveUser(gomock.Any(), gomock.Any()).Times(0)
+	
+	service := NewUserService(repo)
+	if err := service.Rename(1, "Иван"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// Пример 3: gomock - матчеры аргументов и ошибки
+func TestRename_Gomock_Matchers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockUserRepository(ctrl)
+	
+	positiveID := gomock.Cond(func(x any) bool { return x.(int) > 0 })
+	
+	repo.EXPECT().GetUser(positiveID).Return("", ErrNotFound)
+	
+	service := NewUserService(repo)
+	err := service.Rename(7, "Петр")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	
+	// Пустое имя отсеивается до обращения к репозиторию:
+	// любой вызов repo здесь провалил бы тест как неожиданный
+	if err := service.Rename(7, "   "); err == nil {
+		t.Fatal("Expected error for empty name, but got none")
+	}
+}
+
+// Пример 4: moq - поля-функции и записанные вызовы
+func TestRename_Moq(t *testing.T) {
+	repo := &UserRepositoryMoq{
+		GetUserFunc: func(id int) (string, error) {
+			return "Иван", nil
+		},
+		SaveUserFunc: func(id int, name string) error {
+			return nil
+		},
+	}
+	
+	service := NewUserService(repo)
+	if err := service.Rename(1, "Иван Иванов"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	calls := repo.SaveUserCalls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 SaveUser call, got %d", len(calls))
+	}
+	if calls[0].ID != 1 || calls[0].Name != "Иван Иванов" {
+		t.Errorf("Unexpected SaveUser args: %+v", calls[0])
+	}
+}
+
+// Пример 5: тот же сценарий с ручным fake - проверяем состояние, а не вызовы
+func TestRename_Fake(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	repo.SaveUser(1, "Иван")
+	
+	service := NewUserService(repo)
+	if err := service.Rename(1, "Иван Иванов"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	name, err := repo.GetUser(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name != "Иван Иванов" {
+		t.Errorf("Expected 'Иван Иванов', got '%s'", name)
+	}
+}
\ No newline at end of file
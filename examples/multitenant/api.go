@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewServer собирает цепочку: арендатор -> лимит арендатора -> маршруты
+//
+//	GET    /api/tenant           текущий арендатор
+//	GET    /api/projects
+//	POST   /api/projects         {"name"}
+//	GET    /api/projects/{id}
+//	DELETE /api/projects/{id}
+func NewServer(resolver *Resolver, limiter *TenantLimiter, projects *Projects) http.Handler {
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("GET /api/tenant", func(w http.ResponseWriter, r *http.Request) {
+		t, _ := TenantFrom(r.Context())
+		writeJSON(w, http.StatusOK, t)
+	})
+	
+	mux.HandleFunc("GET /api/projects", func(w http.ResponseWriter, r *http.Request) {
+		list, err := projects.List(r.Context())
+		if err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+	
+	mux.HandleFunc("POST /api/projects", func(w http.ResponseWriter, r *http.Request) {
+		// tenant_id в теле не принимается: арендатор берется только из
+		// контекста, иначе клиент мог бы создать проект у соседа
+		var req struct {
+			Name string `json:"name"`
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		p, err := projects.Create(r.Context(), name)
+		if err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, p)
+	})
+	
+	mux.HandleFunc("GET /api/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		p, err := projects.Get(r.Context(), id)
+		if err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	})
+	
+	mux.HandleFunc("DELETE /api/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err := projects.Delete(r.Context(), id); err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	
+	return resolver.Middleware(limiter.Middleware(mux))
+}
+
+// errorStatus переводит ошибки в HTTP-статусы. Внутренние ошибки
+// (в том числе ErrUnscoped - это баг в коде) клиенту не показываются.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrTenantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrProjectExists):
+		return http.StatusConflict
+	}
+	log.Printf("Внутренняя ошибка: %v", err)
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	if status == http.StatusInternalServerError {
+		msg = "internal error"
+	}
+	writeJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Multi-tenant API: одна БД и один сервис на несколько компаний.
+// Арендатор определяется по поддомену (acme.localhost) или заголовку
+// X-Tenant, кладется в контекст, и дальше все запросы к данным
+// фильтруются по его tenant_id.
+//
+//   tenant.go - арендаторы, определение арендатора, лимит по тарифу
+//   store.go  - декоратор TenantDB и хранилище проектов
+//   api.go    - маршруты
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск демонстрации: go run .
+// Сервер: go run . -serve :8080 -db tenants.db
+//   curl -H 'X-Tenant: acme' -d '{"name":"Сайт"}' localhost:8080/api/projects
+//   curl -H 'X-Tenant: acme' localhost:8080/api/projects
+//   curl acme.localhost:8080/api/tenant
+
+func openDB(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// seed создает демонстрационных арендаторов, если их еще нет
+func seed(ctx context.Context, tenants *Tenants) {
+	plans := []struct {
+		slug, name string
+		rate       float64
+		burst      int
+	}{
+		{"acme", "Acme Corp", 5, 10},   // Платный тариф
+		{"globex", "Globex Inc", 1, 5}, // Бесплатный тариф
+	}
+	for _, p := range plans {
+		if _, err := tenants.BySlug(ctx, p.slug); err == nil {
+			continue
+		}
+		if _, err := tenants.Create(ctx, p.slug, p.name, p.rate, p.burst); err != nil {
+			log.Fatal("Ошибка создания арендатора:", err)
+		}
+	}
+}
+
+// call отправляет запрос от имени арендатора через поддомен
+func call(srv *httptest.Server, tenant, method, path, body string) (int, string) {
+	req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if tenant != "" {
+		req.Host = tenant + ".localhost"
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, strings.TrimSpace(string(data))
+}
+
+// Пример 1: Данные арендаторов не пересекаются
+func isolation(srv *httptest.Server) {
+	fmt.Println("=== Изоляция арендаторов ===")
+	
+	status, body := call(srv, "acme", http.MethodPost, "/api/projects", `{"name":"Сайт"}`)
+	fmt.Printf("acme создает проект: %d %s\n", status, body)
+	call(srv, "globex", http.MethodPost, "/api/projects", `{"name":"Сайт"}`) // То же имя у другого арендатора - можно
+	
+	status, body = call(srv, "acme", http.MethodGet, "/api/projects", "")
+	fmt.Printf("Проекты acme: %d %s\n", status, body)
+	status, body = call(srv, "globex", http.MethodGet, "/api/projects", "")
+	fmt.Printf("Проекты globex: %d %s\n", status, body)
+	
+	// globex пробует прочитать и удалить проект acme по его ID
+	status, body = call(srv, "globex", http.MethodGet, "/api/projects/1", "")
+	fmt.Printf("globex читает проект 1: %d %s\n", status, body)
+	status, _ = call(srv, "globex", http.MethodDelete, "/api/projects/1", "")
+	fmt.Printf("globex удаляет проект 1: %d\n", status)
+	status, _ = call(srv, "acme", http.MethodGet, "/api/projects/1", "")
+	fmt.Printf("acme читает проект 1: %d\n", status)
+	
+	status, body = call(srv, "", http.MethodGet, "/api/projects", "")
+	fmt.Printf("Без арендатора: %d %s\n", status, body)
+	status, body = call(srv, "initech", http.MethodGet, "/api/projects", "")
+	fmt.Printf("Неизвестный арендатор: %d %s\n", status, body)
+	fmt.Println()
+}
+
+// Пример 2: Лимит по тарифу у каждого свой
+func rateLimits(srv *httptest.Server) {
+	fmt.Println("=== Лимиты по тарифу ===")
+	for _, tenant := range []string{"globex", "acme"} {
+		var codes []int
+		for range 6 {
+			status, _ := call(srv, tenant, http.MethodGet, "/api/tenant", "")
+			codes = append(codes, status)
+		}
+		fmt.Printf("%s, 6 запросов подряд: %v\n", tenant, codes)
+	}
+	fmt.Println()
+}
+
+// Пример 3: Декоратор не пропускает запрос без tenant_id
+func guardrail(db *sql.DB, tenants *Tenants) {
+	fmt.Println("=== Защита от запроса без фильтра ===")
+	ctx := context.Background()
+	tdb := NewTenantDB(db)
+	
+	_, err := tdb.QueryContext(ctx, `SELECT id FROM projects WHERE tenant_id = :tenant_id`)
+	fmt.Printf("Без арендатора в контексте: %v (ErrNoTenant: %v)\n", err, errors.Is(err, ErrNoTenant))
+	
+	acme, _ := tenants.BySlug(ctx, "acme")
+	_, err = tdb.QueryContext(WithTenant(ctx, acme), `SELECT id FROM projects`)
+	fmt.Printf("Забыли фильтр: %v (ErrUnscoped: %v)\n", err, errors.Is(err, ErrUnscoped))
+}
+
+func main() {
+	addr := flag.String("serve", "", "Адрес для запуска сервера")
+	dbPath := flag.String("db", "", "Файл БД (по умолчанию временный)")
+	baseDomain := flag.String("domain", "localhost", "Домен, поддомены которого - арендаторы")
+	flag.Parse()
+	
+	if *dbPath == "" {
+		dir, err := os.MkdirTemp("", "multitenant")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		*dbPath = filepath.Join(dir, "tenants.db")
+	}
+	db, err := openDB(*dbPath)
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	ctx := context.Background()
+	if err := Migrate(ctx, db); err != nil {
+		log.Fatal("Ошибка миграции:", err)
+	}
+	tenants := NewTenants(db)
+	seed(ctx, tenants)
+	newServer := func() http.Handler {
+		return NewServer(NewResolver(tenants, *baseDomain), NewTenantLimiter(), NewProjects(NewTenantDB(db)))
+	}
+	
+	if *addr != "" {
+		log.Printf("Сервер запущен на %s", *addr)
+		log.Fatal(http.ListenAndServe(*addr, newServer()))
+	}
+	
+	// У каждого примера свой сервер, чтобы лимиты начинались с полного ведра
+	for _, demo := range []func(*httptest.Server){isolation, rateLimits} {
+		srv := httptest.NewServer(newServer())
+		demo(srv)
+		srv.Close()
+	}
+	guardrail(db, tenants)
+}
\ No newline at end of file
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fixture struct {
+	tenants      *Tenants
+	projects     *Projects
+	acme, globex Tenant
+	handler      http.Handler
+	limiter      *TenantLimiter
+}
+
+func newFixture(t *testing.T) *fixture {
+	db, err := openDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	ctx := context.Background()
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f := &fixture{tenants: NewTenants(db), projects: NewProjects(NewTenantDB(db)), limiter: NewTenantLimiter()}
+	if f.acme, err = f.tenants.Create(ctx, "acme", "Acme Corp", 100, 100); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.globex, err = f.tenants.Create(ctx, "globex", "Globex Inc", 100, 100); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f.handler = NewServer(NewResolver(f.tenants, "example.com"), f.limiter, f.projects)
+	return f
+}
+
+func (f *fixture) do(host, header, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = host
+	if header != "" {
+		req.Header.Set(TenantHeader, header)
+	}
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResolver(t *testing.T) {
+	f := newFixture(t)
+	
+	tests := []struct {
+		name   string
+		host   string
+		header string
+		status int
+		slug   string
+	}{
+		{"subdomain", "acme.example.com", "", 200, "acme"},
+		{"subdomain with port", "globex.example.com:8080", "", 200, "globex"},
+		{"subdomain case", "ACME.Example.com", "", 200, "acme"},
+		{"header", "api.internal", "globex", 200, "globex"},
+		{"subdomain wins over header", "acme.example.com", "globex", 200, "acme"},
+		{"nested subdomain ignored", "x.acme.example.com", "", 400, ""},
+		{"base domain only", "example.com", "", 400, ""},
+		{"unknown tenant", "initech.example.com", "", 404, ""},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := f.do(tt.host, tt.header, http.MethodGet, "/api/tenant", "")
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if tt.slug == "" {
+				return
+			}
+			var got Tenant
+			json.NewDecoder(rec.Body).Decode(&got)
+			if got.Slug != tt.slug {
+				t.Errorf("Expected tenant %q, got %q", tt.slug, got.Slug)
+			}
+		})
+	}
+}
+
+func TestTenantDB(t *testing.T) {
+	f := newFixture(t)
+	tdb := f.projects.db
+	ctx := WithTenant(context.Background(), f.acme)
+	
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		query string
+		err   error
+	}{
+		{"no tenant", context.Background(), `SELECT id FROM projects WHERE tenant_id = :tenant_id`, ErrNoTenant},
+		{"unscoped", ctx, `SELECT id FROM projects`, ErrUnscoped},
+		{"unscoped by literal id", ctx, `SELECT id FROM projects WHERE tenant_id = 2`, ErrUnscoped},
+		{"scoped", ctx, `SELECT id FROM projects WHERE tenant_id = :tenant_id`, nil},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tdb.QueryContext(tt.ctx, tt.query)
+			if err == nil {
+				rows.Close()
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected QueryContext error %v, got %v", tt.err, err)
+			}
+			if _, err := tdb.ExecContext(tt.ctx, strings.Replace(tt.query, "SELECT id FROM", "DELETE FROM", 1)); !errors.Is(err, tt.err) {
+				t.Errorf("Expected ExecContext error %v, got %v", tt.err, err)
+			}
+			var id int64
+			err = tdb.QueryRowContext(tt.ctx, tt.query).Scan(&id)
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Expected QueryRowContext error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestProjectsIsolation(t *testing.T) {
+	f := newFixture(t)
+	acme := WithTenant(context.Background(), f.acme)
+	globex := WithTenant(context.Background(), f.globex)
+	
+	a, err := f.projects.Create(acme, "Сайт")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Одинаковые имена у разных арендаторов не конфликтуют
+	g, err := f.projects.Create(globex, "Сайт")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := f.projects.Create(acme, "Сайт"); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("Expected ErrProjectExists, got %v", err)
+	}
+	
+	list, err := f.projects.List(globex)
+	if err != nil || len(list) != 1 || list[0].ID != g.ID {
+		t.Errorf("Expected only globex project, got %+v, %v", list, err)
+	}
+	if _, err := f.projects.Get(globex, a.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another tenant's project, got %v", err)
+	}
+	if err := f.projects.Delete(globex, a.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound on deleting another tenant's project, got %v", err)
+	}
+	if got, err := f.projects.Get(acme, a.ID); err != nil || got.Name != "Сайт" {
+		t.Errorf("Expected acme project to survive, got %+v, %v", got, err)
+	}
+}
+
+func TestAPIIsolation(t *testing.T) {
+	f := newFixture(t)
+	
+	rec := f.do("acme.example.com", "", http.MethodPost, "/api/projects", `{"name":"Сайт","tenant_id":2}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body)
+	}
+	
+	tests := []struct {
+		name   string
+		host   string
+		method string
+		path   string
+		status int
+	}{
+		{"owner reads", "acme.example.com", http.MethodGet, "/api/projects/1", 200},
+		{"other tenant reads", "globex.example.com", http.MethodGet, "/api/projects/1", 404},
+		{"other tenant deletes", "globex.example.com", http.MethodDelete, "/api/projects/1", 404},
+		{"owner still reads", "acme.example.com", http.MethodGet, "/api/projects/1", 200},
+		{"owner deletes", "acme.example.com", http.MethodDelete, "/api/projects/1", 204},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := f.do(tt.host, "", tt.method, tt.path, ""); rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+	
+	// tenant_id из тела игнорируется: проект создан у acme
+	rec = f.do("globex.example.com", "", http.MethodGet, "/api/projects", "")
+	if body := strings.TrimSpace(rec.Body.String()); body != "[]" {
+		t.Errorf("Expected empty list for globex, got %s", body)
+	}
+}
+
+func TestTenantRateLimits(t *testing.T) {
+	f := newFixture(t)
+	now := time.Now()
+	f.limiter.now = func() time.Time { return now }
+	if _, err := f.tenants.Create(context.Background(), "hooli", "Hooli", 1, 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	// hooli на бесплатном тарифе исчерпывает свой лимит (burst 2)
+	for i, want := range []int{200, 200, 429} {
+		if rec := f.do("hooli.example.com", "", http.MethodGet, "/api/tenant", ""); rec.Code != want {
+			t.Fatalf("Request %d: expected status %d, got %d", i+1, want, rec.Code)
+		}
+	}
+	rec := f.do("hooli.example.com", "", http.MethodGet, "/api/tenant", "")
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", rec.Header().Get("Retry-After"))
+	}
+	
+	// acme это не касается
+	for i := range 50 {
+		if rec := f.do("acme.example.com", "", http.MethodGet, "/api/tenant", ""); rec.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected acme to be unaffected, got %d", i+1, rec.Code)
+		}
+	}
+	
+	// Через секунду у hooli появляется новый токен
+	now = now.Add(time.Second)
+	if rec := f.do("hooli.example.com", "", http.MethodGet, "/api/tenant", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected hooli to recover after refill, got %d", rec.Code)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Ошибки изоляции
+var (
+	ErrNoTenant = errors.New("no tenant in context")
+	ErrUnscoped = errors.New("query is not scoped by :tenant_id")
+	ErrNotFound = errors.New("not found")
+)
+
+// Migrate создает таблицы. У каждой таблицы с данными арендаторов
+// есть tenant_id, и индексы начинаются с него.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS tenants (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		slug TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		rate_limit REAL NOT NULL,
+		burst INTEGER NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS projects (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+		name TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		UNIQUE (tenant_id, name)
+	);`)
+	return err
+}
+
+// TenantDB декоратор над *sql.DB: каждый запрос должен ссылаться на
+// параметр :tenant_id, а значение подставляется из контекста. Забыть
+// фильтр или подставить чужой ID из запроса пользователя не получится -
+// такой запрос не выполнится. Это страховка от ошибки разработчика, а не
+// от злонамеренного SQL: тексты запросов пишутся в коде. На уровне самой
+// БД ту же гарантию дает Row-Level Security в Postgres.
+type TenantDB struct {
+	db *sql.DB
+}
+
+// NewTenantDB оборачивает подключение
+func NewTenantDB(db *sql.DB) *TenantDB {
+	return &TenantDB{db: db}
+}
+
+// scope проверяет запрос и добавляет к аргументам tenant_id
+func (d *TenantDB) scope(ctx context.Context, query string, args []any) ([]any, error) {
+	t, ok := TenantFrom(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+	if !strings.Contains(query, ":tenant_id") {
+		return nil, fmt.Errorf("%w: %s", ErrUnscoped, strings.Join(strings.Fields(query), " "))
+	}
+	return append(args, sql.Named("tenant_id", t.ID)), nil
+}
+
+// ExecContext выполняет запрос в рамках арендатора из ctx
+func (d *TenantDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	args, err := d.scope(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+// QueryContext выполняет запрос в рамках арендатора из ctx
+func (d *TenantDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	args, err := d.scope(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext выполняет запрос в рамках арендатора из ctx;
+// ошибка проверки вернется из Scan
+func (d *TenantDB) QueryRowContext(ctx context.Context, query string, args ...any) scanner {
+	args, err := d.scope(ctx, query, args)
+	if err != nil {
+		return errRow{err}
+	}
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// Project данные арендатора
+type Project struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Projects хранилище проектов. Работает только через TenantDB, поэтому
+// ни в одном методе нет параметра tenantID - его нельзя перепутать.
+// Все параметры именованные: так :tenant_id не сдвигает позиционные ?.
+type Projects struct {
+	db *TenantDB
+}
+
+// NewProjects создает хранилище
+func NewProjects(db *TenantDB) *Projects {
+	return &Projects{db: db}
+}
+
+// Create создает проект
+func (s *Projects) Create(ctx context.Context, name string) (Project, error) {
+	p := Project{Name: name, CreatedAt: time.Now().UTC().Truncate(time.Millisecond)}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO projects (tenant_id, name, created_at) VALUES (:tenant_id, :name, :created_at)
+		RETURNING id`,
+		sql.Named("name", name), sql.Named("created_at", p.CreatedAt.UnixMilli())).Scan(&p.ID)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return Project{}, ErrProjectExists
+	}
+	return p, err
+}
+
+// ErrProjectExists проект с таким именем у арендатора уже есть
+var ErrProjectExists = errors.New("project already exists")
+
+// List возвращает проекты арендатора
+func (s *Projects) List(ctx context.Context) ([]Project, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, created_at FROM projects WHERE tenant_id = :tenant_id ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	projects := []Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+// Get возвращает проект арендатора. Чужой проект для запроса не
+// существует: ответ тот же, что для несуществующего ID, чтобы нельзя
+// было перебором узнать, какие ID заняты.
+func (s *Projects) Get(ctx context.Context, id int64) (Project, error) {
+	p, err := scanProject(s.db.QueryRowContext(ctx, `
+		SELECT id, name, created_at FROM projects WHERE tenant_id = :tenant_id AND id = :id`,
+		sql.Named("id", id)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Project{}, ErrNotFound
+	}
+	return p, err
+}
+
+// Delete удаляет проект арендатора
+func (s *Projects) Delete(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM projects WHERE tenant_id = :tenant_id AND id = :id`,
+		sql.Named("id", id))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanProject(s scanner) (Project, error) {
+	var p Project
+	var createdAt int64
+	err := s.Scan(&p.ID, &p.Name, &createdAt)
+	p.CreatedAt = time.UnixMilli(createdAt).UTC()
+	return p, err
+}
\ No newline at end of file
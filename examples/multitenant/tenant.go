@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tenant арендатор: компания со своими данными и своим тарифом
+type Tenant struct {
+	ID        int64   `json:"id"`
+	Slug      string  `json:"slug"` // Поддомен: acme.example.com
+	Name      string  `json:"name"`
+	RateLimit float64 `json:"rate_limit"` // Запросов в секунду по тарифу
+	Burst     int     `json:"burst"`
+}
+
+// ErrTenantNotFound арендатора с таким поддоменом нет
+var ErrTenantNotFound = errors.New("tenant not found")
+
+type tenantKey struct{}
+
+// WithTenant кладет арендатора в контекст. Все, что ниже по стеку,
+// берет его только отсюда, а не из параметров запроса.
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// TenantFrom достает арендатора из контекста
+func TenantFrom(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(tenantKey{}).(Tenant)
+	return t, ok
+}
+
+// Tenants справочник арендаторов. Единственная таблица, которая
+// читается без фильтра по tenant_id: по ней арендатора и находят.
+type Tenants struct {
+	db *sql.DB
+}
+
+// NewTenants создает справочник
+func NewTenants(db *sql.DB) *Tenants {
+	return &Tenants{db: db}
+}
+
+// Create добавляет арендатора
+func (s *Tenants) Create(ctx context.Context, slug, name string, rateLimit float64, burst int) (Tenant, error) {
+	t := Tenant{Slug: slug, Name: name, RateLimit: rateLimit, Burst: burst}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO tenants (slug, name, rate_limit, burst) VALUES (?, ?, ?, ?) RETURNING id`,
+		slug, name, rateLimit, burst).Scan(&t.ID)
+	return t, err
+}
+
+// BySlug ищет арендатора по поддомену
+func (s *Tenants) BySlug(ctx context.Context, slug string) (Tenant, error) {
+	var t Tenant
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, slug, name, rate_limit, burst FROM tenants WHERE slug = ?`, slug).
+		Scan(&t.ID, &t.Slug, &t.Name, &t.RateLimit, &t.Burst)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tenant{}, ErrTenantNotFound
+	}
+	return t, err
+}
+
+// TenantHeader заголовок с арендатором для клиентов без поддомена
+// (мобильные приложения, сервисы). Ему можно верить, только если его
+// ставит шлюз после аутентификации; иначе любой клиент выдаст себя
+// за чужого арендатора.
+const TenantHeader = "X-Tenant"
+
+// Resolver определяет арендатора запроса
+type Resolver struct {
+	tenants    *Tenants
+	baseDomain string // acme.<baseDomain> -> acme
+}
+
+// NewResolver создает резолвер для поддоменов baseDomain
+func NewResolver(tenants *Tenants, baseDomain string) *Resolver {
+	return &Resolver{tenants: tenants, baseDomain: baseDomain}
+}
+
+// slug берет арендатора из поддомена, а если его нет - из заголовка
+func (res *Resolver) slug(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	if sub, ok := strings.CutSuffix(host, "."+res.baseDomain); ok && sub != "" && !strings.Contains(sub, ".") {
+		return sub
+	}
+	return strings.ToLower(r.Header.Get(TenantHeader))
+}
+
+// Middleware кладет арендатора в контекст; без арендатора запрос
+// дальше не идет
+func (res *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := res.slug(r)
+		if slug == "" {
+			writeError(w, http.StatusBadRequest, "tenant is required: use a subdomain or the "+TenantHeader+" header")
+			return
+		}
+		t, err := res.tenants.BySlug(r.Context(), slug)
+		if err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), t)))
+	})
+}
+
+// TenantLimiter token bucket на каждого арендатора со скоростью из его
+// тарифа (как RateLimiter в projects/urlshortener, но лимит у каждого
+// свой). Шумный арендатор исчерпывает только свое ведро и не мешает
+// остальным.
+type TenantLimiter struct {
+	mu      sync.Mutex
+	buckets map[int64]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTenantLimiter создает ограничитель
+func NewTenantLimiter() *TenantLimiter {
+	return &TenantLimiter{buckets: make(map[int64]*bucket), now: time.Now}
+}
+
+// Allow забирает токен арендатора. Если токенов нет, возвращает false
+// и время, через которое появится следующий.
+func (l *TenantLimiter) Allow(t Tenant) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	now := l.now()
+	burst := float64(t.Burst)
+	b, ok := l.buckets[t.ID]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[t.ID] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*t.RateLimit)
+	b.last = now
+	
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / t.RateLimit * float64(time.Second))
+}
+
+// Middleware отвечает 429, если арендатор превысил свой лимит.
+// Ставится после Resolver.Middleware.
+func (l *TenantLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, _ := TenantFrom(r.Context())
+		if ok, wait := l.Allow(t); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "tenant rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+package main
+
+import "fmt"
+
+// Calculator калькулятор из examples/testing
+type Calculator struct{}
+
+// Add складывает два числа
+func (c Calculator) Add(a, b int) int {
+	return a + b
+}
+
+// Subtract вычитает второе число из первого
+func (c Calculator) Subtract(a, b int) int {
+	return a - b
+}
+
+// Multiply умножает два числа
+func (c Calculator) Multiply(a, b int) int {
+	return a * b
+}
+
+// Divide делит первое число на второе
+func (c Calculator) Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("деление на ноль")
+	}
+	return a / b, nil
+}
+
+// Clamp ограничивает x диапазоном [lo, hi]
+func (c Calculator) Clamp(x, lo, hi int) int {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
\ No newline at end of file
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Мутационное тестирование: в код вносятся маленькие ошибки (мутанты),
+// и для каждой проверяется, упадут ли тесты. Выживший мутант означает,
+// что тесты не заметили бы такую ошибку в реальном коде.
+//
+// Готовые инструменты:
+//   go install github.com/avito-tech/go-mutesting/cmd/go-mutesting@latest
+//   go-mutesting ./...
+//   go install github.com/go-gremlins/gremlins/cmd/gremlins@latest
+//   gremlins unleash
+//
+// Ниже - минимальная реализация той же идеи на go/ast,
+// чтобы было видно, как это устроено.
+
+// mutations таблица замен операторов
+var mutations = map[token.Token][]token.Token{
+	token.ADD:  {token.SUB},
+	token.SUB:  {token.ADD},
+	token.MUL:  {token.QUO},
+	token.QUO:  {token.MUL},
+	token.LSS:  {token.LEQ, token.GTR},
+	token.LEQ:  {token.LSS},
+	token.GTR:  {token.GEQ, token.LSS},
+	token.GEQ:  {token.GTR},
+	token.EQL:  {token.NEQ},
+	token.NEQ:  {token.EQL},
+	token.LAND: {token.LOR},
+	token.LOR:  {token.LAND},
+}
+
+// Mutant одна мутация в исходном файле
+type Mutant struct {
+	File string
+	Pos  token.Position
+	From token.Token
+	To   token.Token
+}
+
+func (m Mutant) String() string {
+	return fmt.Sprintf("%s:%d:%d %s -> %s", m.File, m.Pos.Line, m.Pos.Column, m.From, m.To)
+}
+
+// findMutants перечисляет все возможные мутации файла
+func findMutants(file string) ([]Mutant, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	
+	var mutants []Mutant
+	ast.Inspect(f, func(n ast.Node) bool {
+		expr, ok := n.(*ast.BinaryExpr)
+		if !ok {
+			return true
+		}
+		for _, to := range mutations[expr.Op] {
+			mutants = append(mutants, Mutant{File: file, Pos: fset.Position(expr.OpPos), From: expr.Op, To: to})
+		}
+		return true
+	})
+	return mutants, nil
+}
+
+// applyMutant возвращает исходник файла с одной мутацией
+func applyMutant(m Mutant) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, m.File, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	
+	ast.Inspect(f, func(n ast.Node) bool {
+		if expr, ok := n.(*ast.BinaryExpr); ok && fset.Position(expr.OpPos) == m.Pos {
+			expr.Op = m.To
+			return false
+		}
+		return true
+	})
+	
+	var buf bytes.Buffer
+	err = printer.Fprint(&buf, fset, f)
+	return buf.Bytes(), err
+}
+
+// runTests копирует пакет во временный каталог с мутантом и запускает go test.
+// true означает, что тесты упали, то есть мутант убит.
+func runTests(m Mutant, tags string) (bool, error) {
+	dir, err := os.MkdirTemp("", "mutant")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+	
+	files, _ := filepath.Glob("*.go")
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return false, err
+		}
+		if name == m.File {
+			if src, err = applyMutant(m); err != nil {
+				return false, err
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), src, 0o644); err != nil {
+			return false, err
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module mutant\n\ngo 1.22\n"), 0o644)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	
+	cmd := exec.CommandContext(ctx, "go", "test", "-count=1", "-tags="+tags, ".")
+	cmd.Dir = dir
+	return cmd.Run() != nil, nil
+}
+
+// Пример 1: Прогон мутантов против набора тестов
+func mutate(tags string) {
+	fmt.Printf("=== Мутанты против тестов (-tags=%q) ===\n", tags)
+	
+	var killed, total int
+	for _, file := range []string{"calculator.go", "validation.go"} {
+		mutants, err := findMutants(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range mutants {
+			dead, err := runTests(m, tags)
+			if err != nil {
+				log.Fatal(err)
+			}
+			total++
+			if dead {
+				killed++
+				continue
+			}
+			fmt.Println("  выжил:", m)
+		}
+	}
+	
+	fmt.Printf("Убито %d из %d мутантов (mutation score %.0f%%)\n\n", killed, total, 100*float64(killed)/float64(total))
+}
+
+func main() {
+	only := flag.String("tags", "", "прогнать только один набор: weak или пусто для сильных тестов")
+	flag.Parse()
+	
+	if flag.NFlag() > 0 {
+		mutate(*only)
+		return
+	}
+	
+	// До: слабые тесты из weak_test.go
+	mutate("weak")
+	// После: табличные тесты с граничными значениями из strong_test.go
+	mutate("")
+	
+	fmt.Println(strings.TrimSpace(`
+Оба набора покрывают 100% строк calculator.go и validation.go
+(go test -coverprofile=c.out && go tool cover -func=c.out, то же с -tags=weak),
+но слабый пропускает ошибки на границах (< вместо <=) и в симметричных
+входах (0+0 == 0-0). Мутационное тестирование измеряет качество проверок,
+а не только то, что строки выполнились.
+
+Два мутанта Clamp выживают и против сильных тестов: при x == lo
+обе ветки возвращают одно и то же число. Это эквивалентные мутанты -
+поведение программы не изменилось, и никакой тест их не убьет.`))
+}
\ No newline at end of file
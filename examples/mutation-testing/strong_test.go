@@ -0,0 +1,79 @@
+//go:build !weak
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Сильные тесты: табличные случаи с несимметричными входами
+// и значениями ровно на границах условий
+
+func TestCalculator(t *testing.T) {
+	calc := Calculator{}
+	
+	tests := []struct {
+		name     string
+		got      int
+		expected int
+	}{
+		{"add", calc.Add(7, 3), 10},
+		{"subtract", calc.Subtract(7, 3), 4},
+		{"multiply", calc.Multiply(7, 3), 21},
+		{"clamp below", calc.Clamp(-1, 0, 10), 0},
+		{"clamp at lo", calc.Clamp(0, 0, 10), 0},
+		{"clamp at hi", calc.Clamp(10, 0, 10), 10},
+		{"clamp above", calc.Clamp(11, 0, 10), 10},
+		{"clamp inside", calc.Clamp(5, 0, 10), 5},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, tt.got)
+			}
+		})
+	}
+}
+
+func TestCalculator_Divide(t *testing.T) {
+	calc := Calculator{}
+	
+	result, err := calc.Divide(21, 3)
+	if err != nil || result != 7 {
+		t.Errorf("Divide(21, 3) = %d, %v; expected 7", result, err)
+	}
+	
+	if _, err := calc.Divide(21, 0); err == nil {
+		t.Error("Expected error for division by zero, but got none")
+	}
+}
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{"name 1 char", ValidateName("И"), true},
+		{"name 2 chars", ValidateName("Ян"), false},
+		{"name 50 chars", ValidateName(strings.Repeat("я", 50)), false},
+		{"name 51 chars", ValidateName(strings.Repeat("я", 51)), true},
+		{"email 4 chars", ValidateEmail("a@bc"), true},
+		{"email 5 chars", ValidateEmail("a@b.c"), false},
+		{"email without @", ValidateEmail("ivan.example.com"), true},
+		{"age 17", ValidateAge(17), true},
+		{"age 18", ValidateAge(18), false},
+		{"age 120", ValidateAge(120), false},
+		{"age 121", ValidateAge(121), true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%v, got %v", tt.wantErr, tt.err)
+			}
+		})
+	}
+}
\ No newline at end of file
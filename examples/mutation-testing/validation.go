@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateName имя от 2 до 50 символов
+func ValidateName(name string) error {
+	n := utf8.RuneCountInString(strings.TrimSpace(name))
+	if n < 2 || n > 50 {
+		return errors.New("имя должно быть от 2 до 50 символов")
+	}
+	return nil
+}
+
+// ValidateEmail упрощенная проверка формата email, как в examples/http-server
+func ValidateEmail(email string) error {
+	if len(email) < 5 || !strings.Contains(email, "@") {
+		return errors.New("неверный формат email")
+	}
+	return nil
+}
+
+// ValidateAge возраст от 18 до 120 включительно
+func ValidateAge(age int) error {
+	if age < 18 {
+		return errors.New("пользователь должен быть совершеннолетним")
+	}
+	if age > 120 {
+		return errors.New("неправдоподобный возраст")
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrMismatch пароль не совпадает с хешем
+var ErrMismatch = errors.New("пароль не совпадает")
+
+// ErrInvalidHash строка хеша повреждена или в неизвестном формате
+var ErrInvalidHash = errors.New("неверный формат хеша")
+
+// Hasher хеширует и проверяет пароли.
+// Хеш - самодостаточная строка: алгоритм, параметры и соль хранятся в ней,
+// поэтому параметры можно повышать, не ломая старые записи.
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) error
+	// NeedsRehash сообщает, что хеш создан с устаревшими параметрами
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher - bcrypt с настраиваемой стоимостью (2^cost итераций).
+// Ограничение: bcrypt учитывает только первые 72 байта пароля.
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(b), err
+}
+
+func (h BcryptHasher) Verify(password, encoded string) error {
+	// Сравнение внутри CompareHashAndPassword выполняется за постоянное время
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatch
+	}
+	return err
+}
+
+func (h BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.Cost
+}
+
+// Argon2Params параметры argon2id
+type Argon2Params struct {
+	Memory  uint32 // КиБ
+	Time    uint32 // число проходов
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// DefaultArgon2Params - рекомендация RFC 9106 для ограниченной памяти
+// (вариант "второй рекомендуемый": 64 МиБ, 3 прохода)
+var DefaultArgon2Params = Argon2Params{
+	Memory:  64 * 1024,
+	Time:    3,
+	Threads: 4,
+	SaltLen: 16,
+	KeyLen:  32,
+}
+
+// Argon2Hasher - argon2id, победитель Password Hashing Competition.
+// В отличие от bcrypt требует много памяти, что дорого для GPU/ASIC-перебора.
+type Argon2Hasher struct {
+	Params Argon2Params
+}
+
+// Hash возвращает строку в формате PHC:
+// $argon2id$v=19$m=65536,t=3,p=4$<соль>$<хеш>
+func (h Argon2Hasher) Hash(password string) (string, error) {
+	p := h.Params
+	salt := make([]byte, p.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+	
+	b64 := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (h Argon2Hasher) Verify(password, encoded string) error {
+	p, salt, key, err := decodeArgon2(encoded)
+	if err != nil {
+		return err
+	}
+	
+	// Пересчитываем с параметрами из хеша, а не текущими
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	
+	// == или bytes.Equal завершаются на первом различающемся байте,
+	// и по времени ответа можно подбирать хеш побайтно
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+func (h Argon2Hasher) NeedsRehash(encoded string) bool {
+	p, _, _, err := decodeArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	return p.Memory < h.Params.Memory || p.Time < h.Params.Time || p.Threads < h.Params.Threads
+}
+
+func decodeArgon2(encoded string) (p Argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrInvalidHash
+	}
+	
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	
+	b64 := base64.RawStdEncoding
+	if salt, err = b64.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if key, err = b64.DecodeString(parts[5]); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	return p, salt, key, nil
+}
+
+// MultiHasher хеширует основным алгоритмом, но проверяет любым известным -
+// так мигрируют с bcrypt на argon2id без сброса паролей
+type MultiHasher struct {
+	Primary Argon2Hasher
+	Legacy  BcryptHasher
+}
+
+func (h MultiHasher) Hash(password string) (string, error) {
+	return h.Primary.Hash(password)
+}
+
+func (h MultiHasher) Verify(password, encoded string) error {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return h.Primary.Verify(password, encoded)
+	case strings.HasPrefix(encoded, "$2"):
+		return h.Legacy.Verify(password, encoded)
+	default:
+		return ErrInvalidHash
+	}
+}
+
+func (h MultiHasher) NeedsRehash(encoded string) bool {
+	return !strings.HasPrefix(encoded, "$argon2id$") || h.Primary.NeedsRehash(encoded)
+}
\ No newline at end of file
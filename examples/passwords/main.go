@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// go get golang.org/x/crypto github.com/mattn/go-sqlite3
+
+// Пример 1: Выбор стоимости bcrypt по времени хеширования.
+// Ориентир - 250-500 мс на сервере входа; каждый +1 к cost удваивает время.
+func bcryptCosts() {
+	fmt.Println("=== Стоимость bcrypt ===")
+	
+	for cost := 10; cost <= 13; cost++ {
+		h := BcryptHasher{Cost: cost}
+		start := time.Now()
+		hash, err := h.Hash("correct horse battery staple")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("cost=%d: %8v  %s\n", cost, time.Since(start).Round(time.Millisecond), hash)
+	}
+	
+	// Одинаковые пароли дают разные хеши: соль случайная
+	h := BcryptHasher{Cost: bcrypt.MinCost}
+	a, _ := h.Hash("secret123")
+	b, _ := h.Hash("secret123")
+	fmt.Printf("Хеши одного пароля совпадают: %v\n", a == b)
+	
+	// Все, что после 72-го байта, bcrypt игнорирует
+	long := string(make([]byte, 72))
+	hash, err := h.Hash(long + "A")
+	if err != nil {
+		// Новые версии x/crypto возвращают ErrPasswordTooLong
+		fmt.Println("Пароль длиннее 72 байт:", err)
+	} else {
+		fmt.Printf("Пароль длиннее 72 байт: проверка с другим хвостом = %v\n", h.Verify(long+"B", hash) == nil)
+	}
+}
+
+// Пример 2: Параметры argon2id.
+// Память важнее числа проходов: она делает перебор на GPU дорогим.
+func argon2Params() {
+	fmt.Println("\n=== Параметры argon2id ===")
+	
+	configs := []Argon2Params{
+		{Memory: 19 * 1024, Time: 2, Threads: 1, SaltLen: 16, KeyLen: 32}, // минимум OWASP
+		{Memory: 64 * 1024, Time: 3, Threads: 4, SaltLen: 16, KeyLen: 32}, // RFC 9106
+		{Memory: 256 * 1024, Time: 2, Threads: 4, SaltLen: 16, KeyLen: 32},
+	}
+	
+	for _, p := range configs {
+		h := Argon2Hasher{Params: p}
+		start := time.Now()
+		hash, err := h.Hash("correct horse battery staple")
+		if err != nil {
+			log.Fatal(err)
+		}
+		elapsed := time.Since(start)
+		fmt.Printf("m=%6d KiB t=%d p=%d: %8v  %s\n", p.Memory, p.Time, p.Threads, elapsed.Round(time.Millisecond), hash)
+	}
+	
+	h := Argon2Hasher{Params: DefaultArgon2Params}
+	hash, _ := h.Hash("secret123")
+	fmt.Println("Верный пароль:", h.Verify("secret123", hash))
+	fmt.Println("Неверный пароль:", h.Verify("secret124", hash))
+	fmt.Println("Поврежденный хеш:", h.Verify("secret123", "$argon2id$v=19$oops"))
+}
+
+// Пример 3: Сравнение за постоянное время
+func constantTimeCompare() {
+	fmt.Println("\n=== Сравнение за постоянное время ===")
+	
+	secret := []byte("a3f9c2e17b8d4056")
+	guesses := []string{"0000000000000000", "a3f9000000000000", "a3f9c2e17b8d4050"}
+	
+	for _, g := range guesses {
+		// Обычное сравнение выходит на первом отличии: чем длиннее верный
+		// префикс, тем дольше работает - это утечка через время
+		eq := string(secret) == g
+		// ConstantTimeCompare всегда проходит всю длину
+		ct := subtle.ConstantTimeCompare(secret, []byte(g)) == 1
+		fmt.Printf("%s: == %v, ConstantTimeCompare %v\n", g, eq, ct)
+	}
+	fmt.Println("Хеши паролей, токены и HMAC сравнивайте только через subtle")
+}
+
+// Пример 4: Регистрация и вход
+func registrationFlow() {
+	fmt.Println("\n=== Регистрация и вход ===")
+	
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	// Снижаем параметры, чтобы пример работал быстро
+	fast := Argon2Params{Memory: 19 * 1024, Time: 2, Threads: 1, SaltLen: 16, KeyLen: 32}
+	hasher := MultiHasher{
+		Primary: Argon2Hasher{Params: fast},
+		Legacy:  BcryptHasher{Cost: bcrypt.MinCost},
+	}
+	store, err := NewUserStore(db, hasher)
+	if err != nil {
+		log.Fatal("Ошибка инициализации:", err)
+	}
+	
+	id, err := store.Register("Иван", "Ivan@Example.com", "correct horse")
+	fmt.Printf("Регистрация: id=%d, err=%v\n", id, err)
+	
+	_, err = store.Register("Иван 2", "ivan@example.com", "another pass")
+	fmt.Println("Повторная регистрация:", err, errors.Is(err, ErrEmailTaken))
+	
+	_, err = store.Register("Петр", "petr@example.com", "short")
+	fmt.Println("Короткий пароль:", err)
+	
+	u, err := store.Login("ivan@example.com", "correct horse")
+	if err == nil {
+		fmt.Printf("Вход: %s <%s>\n", u.Name, u.Email)
+	}
+	
+	_, err = store.Login("ivan@example.com", "wrong password")
+	fmt.Println("Неверный пароль:", err)
+	
+	// Время ответа для несуществующего email сопоставимо с неверным паролем
+	start := time.Now()
+	_, err = store.Login("nobody@example.com", "whatever")
+	fmt.Printf("Несуществующий email: %v (%v)\n", err, time.Since(start).Round(time.Millisecond))
+	
+	// Миграция: пользователь со старым bcrypt-хешем
+	legacy, _ := BcryptHasher{Cost: bcrypt.MinCost}.Hash("old password")
+	db.Exec(`INSERT INTO users (name, email, password_hash) VALUES (?, ?, ?)`, "Анна", "anna@example.com", legacy)
+	
+	u, err = store.Login("anna@example.com", "old password")
+	if err != nil {
+		log.Fatal("Ошибка входа:", err)
+	}
+	fmt.Printf("Вход со старым хешем, хеш обновлен: %.30s...\n", u.PasswordHash)
+}
+
+func main() {
+	bcryptCosts()
+	argon2Params()
+	constantTimeCompare()
+	registrationFlow()
+}
\ No newline at end of file
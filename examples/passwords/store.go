@@ -0,0 +1,111 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCredentials - одна ошибка для неверного email и неверного пароля,
+// чтобы по ответу нельзя было узнать, зарегистрирован ли адрес
+var ErrInvalidCredentials = errors.New("неверный email или пароль")
+
+// ErrEmailTaken email уже зарегистрирован
+var ErrEmailTaken = errors.New("email уже зарегистрирован")
+
+// User пользователь из таблицы users
+type User struct {
+	ID           int64
+	Name         string
+	Email        string
+	PasswordHash string
+}
+
+// UserStore регистрация и вход поверх таблицы users
+type UserStore struct {
+	db     *sql.DB
+	hasher Hasher
+
+	// dummyHash проверяется для несуществующих пользователей,
+	// чтобы время ответа не выдавало, есть ли такой email
+	dummyHash string
+}
+
+// NewUserStore создает таблицу users с колонкой password_hash
+func NewUserStore(db *sql.DB, hasher Hasher) (*UserStore, error) {
+	query := `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL,
+		password_hash TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+	
+	if _, err := db.Exec(query); err != nil {
+		return nil, err
+	}
+	
+	dummy, err := hasher.Hash("dummy-password")
+	if err != nil {
+		return nil, err
+	}
+	
+	return &UserStore{db: db, hasher: hasher, dummyHash: dummy}, nil
+}
+
+// Register хеширует пароль и сохраняет пользователя.
+// Сам пароль не сохраняется и не логируется.
+func (s *UserStore) Register(name, email, password string) (int64, error) {
+	if len(password) < 8 {
+		return 0, errors.New("пароль должен быть не короче 8 символов")
+	}
+	
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return 0, err
+	}
+	
+	result, err := s.db.Exec(`INSERT INTO users (name, email, password_hash) VALUES (?, ?, ?)`,
+		name, strings.ToLower(email), hash)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return 0, ErrEmailTaken
+		}
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// Login проверяет пароль и при необходимости обновляет хеш
+func (s *UserStore) Login(email, password string) (*User, error) {
+	var u User
+	err := s.db.QueryRow(`SELECT id, name, email, password_hash FROM users WHERE email = ?`,
+		strings.ToLower(email)).Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Тратим столько же времени, сколько на проверку настоящего хеша
+		s.hasher.Verify(password, s.dummyHash)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := s.hasher.Verify(password, u.PasswordHash); err != nil {
+		if errors.Is(err, ErrMismatch) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	
+	// Пароль известен только в момент входа - это единственная возможность
+	// перехешировать его с новыми параметрами или новым алгоритмом
+	if s.hasher.NeedsRehash(u.PasswordHash) {
+		if hash, err := s.hasher.Hash(password); err == nil {
+			s.db.Exec(`UPDATE users SET password_hash = ? WHERE id = ?`, hash, u.ID)
+			u.PasswordHash = hash
+		}
+	}
+	
+	return &u, nil
+}
\ No newline at end of file
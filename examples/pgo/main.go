@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PGO (profile-guided optimization) от начала до конца: компилятор
+// получает CPU-профиль работающей программы и оптимизирует то, что в нем
+// горячее - встраивает горячие функции сверх обычного бюджета и
+// девиртуализирует частые вызовы интерфейсов. Доступно с Go 1.21.
+//
+//   - webapp/ - сервис поиска по каталогу с /debug/pprof/;
+//   - ../loadgen - генератор нагрузки, тот же, что в examples/loadgen.
+//
+// Шаги, которые выполняет пример:
+//  1. собрать webapp без профиля (-pgo=off) и loadgen;
+//  2. дать нагрузку и одновременно снять CPU-профиль с
+//     /debug/pprof/profile - так профиль снимают и с боевого сервиса;
+//  3. пересобрать webapp с -pgo=cpu.pprof;
+//  4. прогнать обе сборки поочередно под одинаковой нагрузкой и
+//     сравнить rps и время процессора на запрос. Поочередно - чтобы
+//     фоновый шум машины доставался обеим сборкам поровну.
+//
+// Запуск (из каталога examples/pgo): go run .
+// Дольше и точнее: go run . -d 20s -rounds 5
+// Оставить профиль и бинарники: go run . -keep
+//
+// Ожидаемый выигрыш - единицы процентов (в Go 1.21-1.22 обычно 2-7%):
+// PGO не меняет алгоритмы. На машине с одним-двумя ядрами loadgen и
+// сервер делят процессор, и разброс rps между прогонами бывает больше
+// самого выигрыша - тогда смотрите на время процессора на запрос и
+// увеличивайте -d и -rounds. Что именно сделал компилятор, пример
+// печатает сам; вручную:
+//
+//	go build -pgo=cpu.pprof -gcflags=-m=1 ./webapp 2>&1 | grep PGO
+//
+// В своем проекте профиль из боя кладут рядом с main-пакетом под именем
+// default.pgo и коммитят: go build по умолчанию (-pgo=auto) подхватывает
+// его сам. Профиль не обязан быть свежим - после правок кода компилятор
+// применяет то, что еще совпадает, и сборка остается корректной. Но
+// функции ищутся по именам: профиль бенчмарка из go test -cpuprofile
+// для main-пакета не подойдет - в тестовом бинарнике это pgo/webapp.X,
+// а не main.X.
+
+// query запрос, которым loadgen нагружает webapp
+const query = "/api/search?q=%D0%B0&tag=%D1%85%D0%B8%D1%82&limit=20" // q=а&tag=хит
+
+// server запущенная сборка webapp
+type server struct {
+	url string
+	cmd *exec.Cmd
+}
+
+// goBuild go build с аргументами, вывод компилятора - в stderr
+func goBuild(args ...string) error {
+	cmd := exec.Command("go", append([]string{"build"}, args...)...)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	return cmd.Run()
+}
+
+// buildLoadgen собирает ../loadgen. Каталог вне модуля, поэтому сборка
+// по списку файлов, как go run *.go в остальных примерах.
+func buildLoadgen(out string) error {
+	files, err := filepath.Glob("../loadgen/*.go")
+	if err != nil {
+		return err
+	}
+	files = slices.DeleteFunc(files, func(f string) bool { return strings.HasSuffix(f, "_test.go") })
+	if len(files) == 0 {
+		return errors.New("не найден ../loadgen: запускайте из каталога examples/pgo")
+	}
+	return goBuild(append([]string{"-o", out}, files...)...)
+}
+
+// start запускает сборку на свободном порту; адрес - первая строка вывода
+func start(name, bin string) (*server, error) {
+	cmd := exec.Command(bin, "-addr", "127.0.0.1:0")
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("%s: нет адреса в выводе: %w", name, err)
+	}
+	return &server{url: strings.TrimSpace(line), cmd: cmd}, nil
+}
+
+func (s *server) stop() {
+	s.cmd.Process.Kill()
+	s.cmd.Wait()
+}
+
+// totalRe строка итога loadgen: "Запросов: 12345 за 10s (1234 rps)"
+var totalRe = regexp.MustCompile(`Запросов: (\d+) за \S+ \((\d+) rps\)`)
+
+// loadResult итог одного прогона loadgen
+type loadResult struct {
+	requests int
+	rps      float64
+}
+
+// parseLoad достает итог из отчета loadgen
+func parseLoad(out []byte) (loadResult, error) {
+	m := totalRe.FindSubmatch(out)
+	if m == nil {
+		return loadResult{}, fmt.Errorf("в выводе loadgen нет итога:\n%s", out)
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	rps, _ := strconv.ParseFloat(string(m[2]), 64)
+	return loadResult{requests: n, rps: rps}, nil
+}
+
+// load нагружает url в течение d
+func load(loadgen, url string, c int, d time.Duration) (loadResult, error) {
+	out, err := exec.Command(loadgen,
+		"-url", url+query,
+		"-c", strconv.Itoa(c),
+		"-d", d.String(),
+		"-rampdown", "0",
+		"-progress", "0",
+	).Output()
+	if err != nil {
+		return loadResult{}, fmt.Errorf("loadgen: %w", err)
+	}
+	return parseLoad(out)
+}
+
+// fetchProfile снимает CPU-профиль за seconds секунд в файл path
+func fetchProfile(url string, seconds int, path string) error {
+	client := &http.Client{Timeout: time.Duration(seconds+10) * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/debug/pprof/profile?seconds=%d", url, seconds))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("профиль: %s: %s", resp.Status, body)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// inlined решения компилятора по webapp: встраивание и девиртуализация
+func inlined(pgo string) (map[string]bool, error) {
+	cmd := exec.Command("go", "build", "-pgo="+pgo, "-gcflags=-m=1", "-o", os.DevNull, "./webapp")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, out)
+	}
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.Contains(line, "inlining call to") || strings.Contains(line, "devirtualizing") {
+			lines[line] = true
+		}
+	}
+	return lines, nil
+}
+
+// printPGODecisions печатает то, что компилятор сделал благодаря профилю:
+// решения сборки с профилем, которых нет в сборке без него
+func printPGODecisions(profile string) error {
+	plain, err := inlined("off")
+	if err != nil {
+		return err
+	}
+	tuned, err := inlined(profile)
+	if err != nil {
+		return err
+	}
+	var added []string
+	for line := range tuned {
+		if !plain[line] {
+			added = append(added, line)
+		}
+	}
+	slices.Sort(added)
+	fmt.Println("Что добавил профиль:")
+	for _, line := range added {
+		fmt.Printf("  %s\n", line)
+	}
+	return nil
+}
+
+// median медиана: один неудачный прогон не искажает итог, как среднее
+func median(xs []float64) float64 {
+	s := slices.Clone(xs)
+	slices.Sort(s)
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
+
+// collectProfile шаг 2: нагрузка на сборку без PGO и профиль с нее же
+func collectProfile(loadgen, bin, profile string, c int, d time.Duration) error {
+	srv, err := start("без PGO", bin)
+	if err != nil {
+		return err
+	}
+	defer srv.stop()
+	
+	// Профиль снимается, пока идет нагрузка: начинаем чуть позже
+	// loadgen и заканчиваем чуть раньше, чтобы не захватить простой
+	seconds := max(1, int(d.Seconds())-1)
+	done := make(chan error, 1)
+	go func() {
+		_, err := load(loadgen, srv.url, c, d)
+		done <- err
+	}()
+	time.Sleep(500 * time.Millisecond)
+	if err := fetchProfile(srv.url, seconds, profile); err != nil {
+		return err
+	}
+	if err := <-done; err != nil {
+		return err
+	}
+	
+	fmt.Printf("Профиль: %s, самое горячее:\n", profile)
+	top := exec.Command("go", "tool", "pprof", "-top", "-nodecount=8", bin, profile)
+	top.Stdout, top.Stderr = os.Stdout, io.Discard
+	return top.Run()
+}
+
+// measure один прогон сборки: свежий процесс, прогрев, нагрузка. Кроме
+// rps возвращает процессорное время сервера на запрос: loadgen делит с
+// сервером одну машину, и rps шумит от всего, что на ней происходит, а
+// время процессора, потраченное самим сервером, - намного меньше.
+func measure(loadgen, name, bin string, c int, d time.Duration) (loadResult, time.Duration, error) {
+	srv, err := start(name, bin)
+	if err != nil {
+		return loadResult{}, 0, err
+	}
+	// Прогрев: соединения, кеши процессора, рост кучи до рабочего размера
+	warm, err := load(loadgen, srv.url, c, time.Second)
+	if err != nil {
+		srv.stop()
+		return loadResult{}, 0, err
+	}
+	res, err := load(loadgen, srv.url, c, d)
+	srv.stop()
+	if err != nil {
+		return loadResult{}, 0, err
+	}
+	// Время процессора за весь процесс, вместе с построением каталога и
+	// прогревом - они одинаковы у обеих сборок
+	state := srv.cmd.ProcessState
+	cpu := (state.UserTime() + state.SystemTime()) / time.Duration(warm.requests+res.requests)
+	return res, cpu, nil
+}
+
+// compare шаг 4: сборки поочередно, rounds раз; порядок внутри прогона
+// меняется, чтобы ни одной сборке не доставалось всегда первое место
+func compare(loadgen string, builds [][2]string, c int, d time.Duration, rounds int) {
+	rps := make([][]float64, len(builds))
+	cpu := make([][]float64, len(builds))
+	for r := range rounds {
+		fmt.Printf("Прогон %d:\n", r+1)
+		for k := range builds {
+			i := k
+			if r%2 == 1 {
+				i = len(builds) - 1 - k
+			}
+			res, perReq, err := measure(loadgen, builds[i][0], builds[i][1], c, d)
+			if err != nil {
+				log.Fatal(err)
+			}
+			rps[i] = append(rps[i], res.rps)
+			cpu[i] = append(cpu[i], float64(perReq))
+			fmt.Printf("  %-8s %6.0f rps, %v процессора на запрос\n", builds[i][0], res.rps, perReq.Round(time.Microsecond))
+		}
+	}
+	
+	fmt.Println("Медианы:")
+	for i, b := range builds {
+		r, t := median(rps[i]), median(cpu[i])
+		fmt.Printf("  %-8s %6.0f rps (%+.1f%%), %v на запрос (%+.1f%%)\n", b[0],
+			r, (r/median(rps[0])-1)*100, time.Duration(t).Round(time.Microsecond), (t/median(cpu[0])-1)*100)
+	}
+}
+
+func main() {
+	d := flag.Duration("d", 10*time.Second, "длительность одного прогона нагрузки")
+	c := flag.Int("c", 8, "число параллельных воркеров loadgen")
+	rounds := flag.Int("rounds", 3, "число поочередных прогонов каждой сборки")
+	keep := flag.Bool("keep", false, "не удалять каталог с профилем и бинарниками")
+	flag.Parse()
+	
+	if *d < 2*time.Second || *rounds < 1 || *c < 1 {
+		log.Fatal("Нужно -d >= 2s, -rounds >= 1 и -c >= 1")
+	}
+	
+	work, err := os.MkdirTemp("", "pgo-")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *keep {
+		fmt.Printf("Рабочий каталог: %s\n", work)
+	} else {
+		defer os.RemoveAll(work)
+	}
+	var (
+		loadgen = filepath.Join(work, "loadgen")
+		plain   = filepath.Join(work, "webapp")
+		tuned   = filepath.Join(work, "webapp-pgo")
+		profile = filepath.Join(work, "cpu.pprof")
+	)
+	
+	fmt.Println("=== 1. Сборка без PGO ===")
+	if err := buildLoadgen(loadgen); err != nil {
+		log.Fatal(err)
+	}
+	if err := goBuild("-pgo=off", "-o", plain, "./webapp"); err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Printf("\n=== 2. Нагрузка %v и CPU-профиль ===\n", *d)
+	if err := collectProfile(loadgen, plain, profile, *c, *d); err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Println("\n=== 3. Сборка с PGO ===")
+	if err := goBuild("-pgo="+profile, "-o", tuned, "./webapp"); err != nil {
+		log.Fatal(err)
+	}
+	if err := printPGODecisions(profile); err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Printf("\n=== 4. Сравнение: %d прогона по %v ===\n", *rounds, *d)
+	compare(loadgen, [][2]string{{"без PGO", plain}, {"с PGO", tuned}}, *c, *d, *rounds)
+}
\ No newline at end of file
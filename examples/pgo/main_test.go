@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestParseLoad(t *testing.T) {
+	out := []byte("Нагрузка на http://127.0.0.1:1/api/search: 8 воркеров\n\nЗапросов: 4321 за 10.001s (432 rps)\nСтатусы:\n  200      4321\n")
+	res, err := parseLoad(out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res != (loadResult{requests: 4321, rps: 432}) {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if _, err := parseLoad([]byte("panic: boom")); err == nil {
+		t.Error("Expected error without summary line")
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		xs       []float64
+		expected float64
+	}{
+		{[]float64{5}, 5},
+		{[]float64{3, 100, 1}, 3},
+		{[]float64{4, 1, 3, 2}, 2.5},
+	}
+	for _, tt := range tests {
+		if got := median(tt.xs); got != tt.expected {
+			t.Errorf("median(%v): expected %v, got %v", tt.xs, tt.expected, got)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Каталог и поиск по нему - работа, которая упирается в процессор, а не
+// в сеть: на каждый запрос обходятся все товары. Именно на таком коде
+// видно PGO: горячие вызовы интерфейсов (catalogFolder.fold на каждую
+// букву, matcher.match на каждый товар) компилятор по профилю
+// девиртуализирует - прямой вызов самой частой реализации с проверкой
+// типа - и встраивает, а горячие indexFold и score встраивает сверх
+// обычного бюджета.
+
+// product товар каталога
+type product struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Price    int      `json:"price"` // В копейках
+	Rating   float64  `json:"rating"`
+	Tags     []string `json:"tags"`
+
+	lower string // Name в нижнем регистре, считается один раз
+}
+
+var (
+	adjectives = []string{"Красный", "Быстрый", "Тихий", "Легкий", "Умный", "Прочный", "Компактный", "Складной"}
+	nouns      = []string{"чайник", "велосипед", "рюкзак", "фонарь", "стул", "зонт", "термос", "пылесос"}
+	categories = []string{"дом", "спорт", "туризм", "электроника"}
+	tagPool    = []string{"новинка", "хит", "скидка", "эко", "подарок", "доставка"}
+)
+
+// makeCatalog детерминированный каталог из n товаров: один и тот же на
+// каждом запуске, чтобы прогоны до и после PGO были сравнимы
+func makeCatalog(n int) []product {
+	rnd := rand.New(rand.NewSource(1))
+	catalog := make([]product, n)
+	for i := range catalog {
+		p := &catalog[i]
+		p.ID = i + 1
+		p.Name = fmt.Sprintf("%s %s %d", adjectives[rnd.Intn(len(adjectives))], nouns[rnd.Intn(len(nouns))], rnd.Intn(1000))
+		p.Category = categories[rnd.Intn(len(categories))]
+		p.Price = 100 * (100 + rnd.Intn(50000))
+		p.Rating = float64(rnd.Intn(51)) / 10
+		for _, t := range tagPool {
+			if rnd.Intn(4) == 0 {
+				p.Tags = append(p.Tags, t)
+			}
+		}
+	}
+	return catalog
+}
+
+// matcher условие фильтра
+type matcher interface {
+	match(p *product) bool
+}
+
+// textMatcher подстрока в названии без учета регистра и с ё = е;
+// q уже приведен через fold
+type textMatcher struct{ q []rune }
+
+func (m textMatcher) match(p *product) bool { return indexFold(p.Name, m.q) >= 0 }
+
+// folder правила сравнения букв при поиске. Они зависят от языка
+// каталога, поэтому спрятаны за интерфейсом; в примере реализация одна.
+type folder interface {
+	fold(r rune) rune
+}
+
+// ruFolder русский и латиница: нижний регистр, ё = е
+type ruFolder struct{}
+
+func (ruFolder) fold(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'а' && r <= 'я':
+		return r
+	case r >= 'A' && r <= 'Z':
+		return r + 'a' - 'A'
+	case r >= 'А' && r <= 'Я':
+		return r + 'а' - 'А'
+	case r == 'Ё' || r == 'ё':
+		return 'е'
+	case r < utf8.RuneSelf:
+		return r
+	}
+	return unicode.ToLower(r)
+}
+
+// catalogFolder правила каталога. Вызов catalogFolder.fold - на каждую
+// букву каждого названия, самый горячий в сервисе: без профиля это
+// вызов через интерфейс, с профилем - проверка типа и встроенное тело
+// ruFolder.fold.
+var catalogFolder folder = ruFolder{}
+
+// fold буква по правилам каталога
+func fold(r rune) rune { return catalogFolder.fold(r) }
+
+// foldString руны строки после fold - так хранится запрос
+func foldString(s string) []rune {
+	rs := []rune(s)
+	for i, r := range rs {
+		rs[i] = fold(r)
+	}
+	return rs
+}
+
+// indexFold позиция (в байтах) первого вхождения q в s с точностью до
+// fold или -1. Наивный поиск: названия короткие.
+func indexFold(s string, q []rune) int {
+	if len(q) == 0 {
+		return 0
+	}
+	for i, r := range s {
+		if fold(r) != q[0] {
+			continue
+		}
+		k := 1
+		for _, r := range s[i+utf8.RuneLen(r):] {
+			if k == len(q) || fold(r) != q[k] {
+				break
+			}
+			k++
+		}
+		if k == len(q) {
+			return i
+		}
+	}
+	return -1
+}
+
+// priceMatcher цена в диапазоне [min, max]
+type priceMatcher struct{ min, max int }
+
+func (m priceMatcher) match(p *product) bool { return p.Price >= m.min && p.Price <= m.max }
+
+// tagMatcher товар с тегом
+type tagMatcher struct{ tag string }
+
+func (m tagMatcher) match(p *product) bool {
+	for _, t := range p.Tags {
+		if t == m.tag {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryMatcher товар из категории
+type categoryMatcher struct{ category string }
+
+func (m categoryMatcher) match(p *product) bool { return p.Category == m.category }
+
+// result найденный товар с оценкой релевантности
+type result struct {
+	*product
+	Score float64 `json:"score"`
+}
+
+// score релевантность: совпадение в начале слова, рейтинг, теги и цена
+func score(p *product, q []rune) float64 {
+	s := p.Rating
+	if len(q) > 0 {
+		switch i := indexFold(p.Name, q); {
+		case i == 0:
+			s += 3
+		case i > 0 && p.Name[i-1] == ' ':
+			s += 2
+		case i > 0:
+			s += 1
+		}
+	}
+	for _, t := range p.Tags {
+		switch t {
+		case "хит":
+			s += 1.5
+		case "скидка":
+			s += 1
+		case "новинка":
+			s += 0.5
+		}
+	}
+	switch {
+	case p.Price < 1000_00:
+		s += 0.5
+	case p.Price > 30000_00:
+		s -= 0.5
+	}
+	return s
+}
+
+// search товары, подходящие под все условия, лучшие limit по оценке.
+// Возвращает и общее число найденных.
+func search(catalog []product, ms []matcher, q []rune, limit int) ([]result, int) {
+	top := make([]result, 0, limit+1)
+	total := 0
+	for i := range catalog {
+		p := &catalog[i]
+		ok := true
+		for _, m := range ms {
+			if !m.match(p) {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		total++
+		
+		// Вставка в отсортированный top: limit маленький, куча не нужна
+		r := result{p, score(p, q)}
+		if len(top) == limit && r.Score <= top[limit-1].Score {
+			continue
+		}
+		j := len(top)
+		top = append(top, r)
+		for j > 0 && top[j-1].Score < r.Score {
+			top[j] = top[j-1]
+			j--
+		}
+		top[j] = r
+		if len(top) > limit {
+			top = top[:limit]
+		}
+	}
+	return top, total
+}
\ No newline at end of file
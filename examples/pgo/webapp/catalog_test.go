@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestIndexFold(t *testing.T) {
+	tests := []struct {
+		s, q     string
+		expected int
+	}{
+		{"Красный чайник", "чайник", 15},
+		{"Красный чайник", "ЧАЙНИК", 15},
+		{"Красный чайник", "кра", 0},
+		{"Ёлочный шар", "елоч", 0},
+		{"Легкий Зонт 5", "зонт 5", 13},
+		{"Легкий зонт", "зонты", -1},
+		{"ааб", "аб", 2}, // Возврат после частичного совпадения
+		{"Straße", "STRASSE", -1},
+		{"любое", "", 0},
+	}
+	for _, tt := range tests {
+		if got := indexFold(tt.s, foldString(tt.q)); got != tt.expected {
+			t.Errorf("indexFold(%q, %q): expected %d, got %d", tt.s, tt.q, tt.expected, got)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	catalog := makeCatalog(2000)
+	v, _ := url.ParseQuery("q=чайник&tag=хит&min=100&max=20000&limit=5")
+	ms, q, limit, err := parseQuery(v)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	items, total := search(catalog, ms, q, limit)
+	
+	// Тот же фильтр простым перебором
+	expected := 0
+	for i := range catalog {
+		p := &catalog[i]
+		if indexFold(p.Name, q) >= 0 && p.Price >= 100_00 && p.Price <= 20000_00 && (tagMatcher{"хит"}).match(p) {
+			expected++
+		}
+	}
+	if total != expected || expected == 0 {
+		t.Fatalf("Expected total %d, got %d", expected, total)
+	}
+	if len(items) != min(limit, total) {
+		t.Fatalf("Expected %d items, got %d", min(limit, total), len(items))
+	}
+	for i := 1; i < len(items); i++ {
+		if items[i].Score > items[i-1].Score {
+			t.Errorf("Expected descending scores, got %v after %v", items[i].Score, items[i-1].Score)
+		}
+	}
+}
+
+func TestSearchHandler(t *testing.T) {
+	h := searchHandler(makeCatalog(500))
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{"q=Зонт&limit=3", http.StatusOK},
+		{"category=спорт", http.StatusOK},
+		{"min=дорого", http.StatusBadRequest},
+		{"max=-1", http.StatusBadRequest},
+		{"limit=0", http.StatusBadRequest},
+		{"limit=101", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/api/search?"+url.PathEscape(tt.query), nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d: %s", tt.query, tt.status, w.Code, w.Body)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var resp struct {
+			Total int
+			Items []struct{ Name string }
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Total == 0 || len(resp.Items) == 0 {
+			t.Errorf("%s: expected results, got %+v", tt.query, resp)
+		}
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	catalog := makeCatalog(catalogSize)
+	for _, raw := range []string{"q=а&tag=хит", "category=спорт&min=500&max=20000&tag=скидка"} {
+		v, _ := url.ParseQuery(raw)
+		ms, q, limit, _ := parseQuery(v)
+		b.Run(raw, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				search(catalog, ms, q, limit)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"strings"
+)
+
+// Веб-приложение для демонстрации PGO: поиск по каталогу товаров и
+// профилировщик на /debug/pprof/. Обычно его запускает ../main.go, но
+// можно и вручную:
+//
+//	go run ./webapp -addr localhost:8090
+//	curl 'http://localhost:8090/api/search?q=чайник&max=5000&tag=хит'
+//	curl -o cpu.pprof 'http://localhost:8090/debug/pprof/profile?seconds=10'
+//
+// Первая строка вывода - адрес, на котором слушает сервер: с -addr
+// 127.0.0.1:0 порт выбирает система, и запускающий узнает его отсюда.
+
+// catalogSize число товаров: на каждый запрос обходятся все
+const catalogSize = 20000
+
+// searchResponse ответ /api/search
+type searchResponse struct {
+	Total int      `json:"total"`
+	Items []result `json:"items"`
+}
+
+// searchHandler GET /api/search?q=&category=&tag=&min=&max=&limit=
+// Цены в рублях, limit от 1 до 100, по умолчанию 20.
+func searchHandler(catalog []product) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		ms, q, limit, err := parseQuery(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		items, total := search(catalog, ms, q, limit)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(searchResponse{Total: total, Items: items})
+	}
+}
+
+// parseQuery превращает параметры запроса в условия поиска
+func parseQuery(query map[string][]string) ([]matcher, []rune, int, error) {
+	get := func(k string) string {
+		if v := query[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	var ms []matcher
+	q := foldString(strings.TrimSpace(get("q")))
+	if len(q) > 0 {
+		ms = append(ms, textMatcher{q})
+	}
+	if c := get("category"); c != "" {
+		ms = append(ms, categoryMatcher{c})
+	}
+	if t := get("tag"); t != "" {
+		ms = append(ms, tagMatcher{t})
+	}
+	
+	price := priceMatcher{min: 0, max: int(^uint(0) >> 1)}
+	for _, b := range []struct {
+		key string
+		dst *int
+	}{{"min", &price.min}, {"max", &price.max}} {
+		if s := get(b.key); s != "" {
+			rub, err := strconv.Atoi(s)
+			if err != nil || rub < 0 {
+				return nil, nil, 0, fmt.Errorf("%s: нужна цена в рублях, получено %q", b.key, s)
+			}
+			*b.dst = rub * 100
+		}
+	}
+	if get("min") != "" || get("max") != "" {
+		ms = append(ms, price)
+	}
+	
+	limit := 20
+	if s := get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 100 {
+			return nil, nil, 0, fmt.Errorf("limit: нужно число от 1 до 100, получено %q", s)
+		}
+		limit = n
+	}
+	return ms, q, limit, nil
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8090", "адрес сервера")
+	flag.Parse()
+	
+	mux := http.NewServeMux()
+	mux.Handle("/api/search", searchHandler(makeCatalog(catalogSize)))
+	// Профилировщик на том же порту - только для демонстрации; в бою
+	// его выносят на отдельный внутренний порт
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("http://%s\n", l.Addr())
+	log.Fatal(http.Serve(l, mux))
+}
\ No newline at end of file
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CA удостоверяющий центр: сертификат и ключ, которым он подписывает другие
+type CA struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// Issued выпущенный сертификат вместе с ключом
+type Issued struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// newKey создает ключ ECDSA P-256: короче и быстрее RSA при той же стойкости
+func newKey() (crypto.Signer, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// newSerial - серийный номер должен быть уникальным в пределах CA;
+// случайные 128 бит это гарантируют без хранения состояния
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// subjectKeyID вычисляется из публичного ключа (RFC 5280, метод 1)
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(der)
+	return sum[:], nil
+}
+
+// NewCA создает самоподписанный корневой сертификат
+func NewCA(commonName string, validity time.Duration) (*CA, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+	skid, err := subjectKeyID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"GoLearn"}},
+		NotBefore:    now.Add(-5 * time.Minute), // Допуск на расхождение часов
+		NotAfter:     now.Add(validity),
+		
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		// MaxPathLenZero: CA подписывает только конечные сертификаты,
+		// промежуточные CA под ним не пройдут проверку
+		MaxPathLen:     0,
+		MaxPathLenZero: true,
+		KeyUsage:       x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		SubjectKeyId:   skid,
+	}
+	
+	// Самоподписанный: шаблон и родитель совпадают
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// issue подписывает конечный сертификат
+func (ca *CA) issue(tmpl *x509.Certificate, validity time.Duration) (*Issued, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+	skid, err := subjectKeyID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	
+	now := time.Now()
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = now.Add(-5 * time.Minute)
+	tmpl.NotAfter = now.Add(validity)
+	tmpl.BasicConstraintsValid = true
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.SubjectKeyId = skid
+	
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Issued{Cert: cert, Key: key}, nil
+}
+
+// IssueServer выпускает серверный сертификат.
+// Браузеры и Go проверяют только SAN (DNSNames, IPAddresses);
+// CommonName для проверки имени хоста игнорируется с Go 1.15.
+func (ca *CA) IssueServer(hosts []string, validity time.Duration) (*Issued, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("нужен хотя бы один хост")
+	}
+	
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: hosts[0]},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	return ca.issue(tmpl, validity)
+}
+
+// IssueClient выпускает клиентский сертификат для mTLS.
+// CommonName здесь - идентификатор клиента, который сервер прочитает
+// из r.TLS.PeerCertificates[0].Subject.CommonName.
+func (ca *CA) IssueClient(name string, validity time.Duration) (*Issued, error) {
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	return ca.issue(tmpl, validity)
+}
+
+// VerifyChain проверяет, что сертификат выпущен CA и подходит для usage
+func VerifyChain(root, cert *x509.Certificate, host string, usage x509.ExtKeyUsage, at time.Time) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		DNSName:     host,
+		KeyUsages:   []x509.ExtKeyUsage{usage},
+		CurrentTime: at,
+	})
+	return err
+}
+
+// certPEM и keyPEM кодируют сертификат и ключ в PEM
+func certPEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func keyPEM(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// WritePair записывает <name>.pem и <name>-key.pem.
+// Если передан CA, к сертификату добавляется его сертификат (bundle) -
+// так сервер отдает клиенту всю цепочку.
+func WritePair(dir, name string, cert *x509.Certificate, key crypto.Signer, chain ...*x509.Certificate) error {
+	bundle := certPEM(cert)
+	for _, c := range chain {
+		bundle = append(bundle, certPEM(c)...)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".pem"), bundle, 0o644); err != nil {
+		return err
+	}
+	
+	k, err := keyPEM(key)
+	if err != nil {
+		return err
+	}
+	// Ключ доступен только владельцу
+	return os.WriteFile(filepath.Join(dir, name+"-key.pem"), k, 0o600)
+}
+
+// LoadCA читает CA, записанный WritePair
+func LoadCA(dir string) (*CA, error) {
+	certData, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keyData, err := os.ReadFile(filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		return nil, err
+	}
+	
+	block, _ := pem.Decode(certData)
+	if block == nil {
+		return nil, errors.New("ca.pem: PEM-блок не найден")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	
+	block, _ = pem.Decode(keyData)
+	if block == nil {
+		return nil, errors.New("ca-key.pem: PEM-блок не найден")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("ключ %T не умеет подписывать", key)
+	}
+	return &CA{Cert: cert, Key: signer}, nil
+}
\ No newline at end of file
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Инструмент для выпуска сертификатов без openssl.
+//
+//   go run . init -out ./certs -hosts localhost,127.0.0.1 -clients alice,bob
+//
+// создает в ./certs:
+//   ca.pem, ca-key.pem          - корневой сертификат и ключ CA
+//   server.pem, server-key.pem  - сертификат сервера (с цепочкой) и ключ
+//   <client>.pem, <client>-key.pem - клиентские сертификаты для mTLS
+//
+// Эти файлы используют примеры TLS и mTLS:
+//   server.pem/server-key.pem -> http.ListenAndServeTLS
+//   ca.pem -> tls.Config.RootCAs у клиента и ClientCAs у сервера
+//
+// go run . без аргументов - демонстрация в памяти.
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 90 * 24 * time.Hour
+)
+
+func initCommand(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	out := fs.String("out", "certs", "каталог для файлов")
+	hosts := fs.String("hosts", "localhost,127.0.0.1,::1", "SAN серверного сертификата через запятую")
+	clients := fs.String("clients", "client", "имена клиентских сертификатов через запятую")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	
+	// Повторный запуск не пересоздает CA: иначе все ранее выпущенные
+	// сертификаты перестанут проходить проверку
+	ca, err := LoadCA(*out)
+	if err != nil {
+		ca, err = NewCA("GoLearn Dev CA", caValidity)
+		if err != nil {
+			return err
+		}
+		if err := WritePair(*out, "ca", ca.Cert, ca.Key); err != nil {
+			return err
+		}
+		fmt.Println("Создан CA:", filepath.Join(*out, "ca.pem"))
+	}
+	
+	server, err := ca.IssueServer(strings.Split(*hosts, ","), leafValidity)
+	if err != nil {
+		return err
+	}
+	if err := WritePair(*out, "server", server.Cert, server.Key, ca.Cert); err != nil {
+		return err
+	}
+	fmt.Println("Выпущен серверный сертификат:", *hosts)
+	
+	for _, name := range strings.Split(*clients, ",") {
+		client, err := ca.IssueClient(name, leafValidity)
+		if err != nil {
+			return err
+		}
+		if err := WritePair(*out, name, client.Cert, client.Key, ca.Cert); err != nil {
+			return err
+		}
+		fmt.Println("Выпущен клиентский сертификат:", name)
+	}
+	return nil
+}
+
+// Пример 1: Проверка цепочек
+func verifyChains(ca *CA, server, client *Issued) {
+	fmt.Println("\n=== Проверка цепочек ===")
+	
+	check := func(name string, err error) {
+		if err != nil {
+			fmt.Printf("%-40s ошибка: %v\n", name, err)
+		} else {
+			fmt.Printf("%-40s OK\n", name)
+		}
+	}
+	
+	now := time.Now()
+	check("сервер, localhost", VerifyChain(ca.Cert, server.Cert, "localhost", x509.ExtKeyUsageServerAuth, now))
+	check("сервер, 127.0.0.1", VerifyChain(ca.Cert, server.Cert, "127.0.0.1", x509.ExtKeyUsageServerAuth, now))
+	check("сервер, example.com", VerifyChain(ca.Cert, server.Cert, "example.com", x509.ExtKeyUsageServerAuth, now))
+	check("сервер через 100 дней", VerifyChain(ca.Cert, server.Cert, "localhost", x509.ExtKeyUsageServerAuth, now.AddDate(0, 0, 100)))
+	check("клиент, ClientAuth", VerifyChain(ca.Cert, client.Cert, "", x509.ExtKeyUsageClientAuth, now))
+	check("клиент как сервер", VerifyChain(ca.Cert, client.Cert, "", x509.ExtKeyUsageServerAuth, now))
+	
+	other, _ := NewCA("Чужой CA", caValidity)
+	check("сервер, чужой CA", VerifyChain(other.Cert, server.Cert, "localhost", x509.ExtKeyUsageServerAuth, now))
+}
+
+// Пример 2: mTLS с выпущенными сертификатами
+func mutualTLS(ca *CA, server, client *Issued) {
+	fmt.Println("\n=== mTLS ===")
+	
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+	
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "привет, %s", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{server.Cert.Raw}, PrivateKey: server.Key, Leaf: server.Cert}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	// Ошибки рукопожатия сервер пишет в лог; в примере они ожидаемы
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	
+	get := func(certs []tls.Certificate) {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      pool,
+			Certificates: certs,
+		}}}
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			fmt.Println("Ошибка:", err)
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		fmt.Printf("%d %s\n", resp.StatusCode, body)
+	}
+	
+	get([]tls.Certificate{{Certificate: [][]byte{client.Cert.Raw}, PrivateKey: client.Key}})
+	get(nil) // Без клиентского сертификата сервер разорвет рукопожатие
+}
+
+func demo() {
+	fmt.Println("=== Выпуск сертификатов ===")
+	
+	ca, err := NewCA("GoLearn Dev CA", caValidity)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server, err := ca.IssueServer([]string{"localhost", "127.0.0.1"}, leafValidity)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := ca.IssueClient("alice", leafValidity)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	for _, c := range []*x509.Certificate{ca.Cert, server.Cert, client.Cert} {
+		fmt.Printf("%-16s выдан: %-16s CA=%-5v DNS=%v IP=%v до %s\n",
+			c.Subject.CommonName, c.Issuer.CommonName, c.IsCA, c.DNSNames, c.IPAddresses, c.NotAfter.Format("2006-01-02"))
+	}
+	
+	verifyChains(ca, server, client)
+	mutualTLS(ca, server, client)
+	
+	dir, err := os.MkdirTemp("", "pki")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	
+	fmt.Println("\n=== Запись PEM ===")
+	if err := initCommand([]string{"-out", dir, "-clients", "alice,bob"}); err != nil {
+		log.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		info, _ := e.Info()
+		fmt.Printf("  %-16s %v\n", e.Name(), info.Mode())
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		if err := initCommand(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	demo()
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-plugin"
+
+	"plugins/exporter"
+	"plugins/rpcplugin"
+)
+
+// Плагины: приложение экспортирует таблицу в форматы, о которых ничего
+// не знает при сборке. Хост находит плагины в каталоге и загружает их
+// двумя способами:
+//
+//   exporter/         - интерфейс Exporter, общий для хоста и плагинов,
+//                       и загрузка через стандартный пакет plugin
+//   rpcplugin/        - то же через hashicorp/go-plugin (net/rpc)
+//   plugins/markdown  - плагин-библиотека (.so) для пакета plugin
+//   plugins/csv       - плагин-процесс для go-plugin
+//
+//   стандартный plugin            hashicorp/go-plugin
+//   .so в адресном пространстве   отдельный процесс, вызовы по RPC
+//   только Linux/macOS/FreeBSD    любая ОС
+//   та же версия Go и зависимостей любая версия Go
+//   паника плагина роняет хост    падение плагина - ошибка вызова
+//   вызов как обычной функции     каждый вызов сериализуется
+//
+// hashicorp/go-plugin и его зависимости закреплены в go.mod и go.sum.
+//
+// Запуск:
+//   go build -buildmode=plugin -o bin/markdown.so ./plugins/markdown
+//   go build -o bin/exporter-csv ./plugins/csv
+//   go run ./cmd/host -dir bin -out out
+//
+// Новый формат добавляется сборкой еще одного файла в bin, без
+// пересборки хоста.
+
+// loadAll загружает все найденные плагины. Плагин, который не удалось
+// загрузить, пропускается: один сломанный плагин не должен мешать
+// работе остальных.
+func loadAll(dir string, registry *exporter.Registry) {
+	native, err := exporter.Discover(dir, "*.so")
+	if err != nil {
+		log.Fatal("Ошибка поиска плагинов:", err)
+	}
+	for _, path := range native {
+		exp, err := exporter.LoadNative(path)
+		if err != nil {
+			log.Printf("Плагин %s пропущен: %v", path, err)
+			continue
+		}
+		register(registry, exp, path, "plugin")
+	}
+	
+	// plugin.Discover из go-plugin делает то же, что exporter.Discover
+	processes, err := plugin.Discover(rpcplugin.Pattern, dir)
+	if err != nil {
+		log.Fatal("Ошибка поиска плагинов:", err)
+	}
+	for _, path := range processes {
+		exp, _, err := rpcplugin.Load(path)
+		if err != nil {
+			log.Printf("Плагин %s пропущен: %v", path, err)
+			continue
+		}
+		register(registry, exp, path, "go-plugin")
+	}
+}
+
+func register(registry *exporter.Registry, exp exporter.Exporter, path, kind string) {
+	if err := registry.Register(exp); err != nil {
+		log.Printf("Плагин %s пропущен: %v", path, err)
+		return
+	}
+	log.Printf("Загружен плагин %q из %s (%s)", exp.Name(), path, kind)
+}
+
+func main() {
+	dir := flag.String("dir", "bin", "Каталог с плагинами")
+	out := flag.String("out", "", "Каталог для файлов (по умолчанию вывод в консоль)")
+	flag.Parse()
+	
+	// Останавливает процессы плагинов go-plugin при выходе
+	defer plugin.CleanupClients()
+	
+	registry := exporter.NewRegistry()
+	loadAll(*dir, registry)
+	if len(registry.Names()) == 0 {
+		log.Printf("В %s нет плагинов, см. инструкцию по сборке в начале файла", *dir)
+		return
+	}
+	
+	table := exporter.Table{
+		Columns: []string{"ID", "Имя", "Email"},
+		Rows: [][]string{
+			{"1", "Иван Иванов", "ivan@example.com"},
+			{"2", "Мария, Петрова", "maria@example.com"},
+			{"3", "A|B", "ab@example.com"},
+		},
+	}
+	
+	for _, name := range registry.Names() {
+		exp, _ := registry.Get(name)
+		data, err := exp.Export(table)
+		if err != nil {
+			log.Printf("Ошибка экспорта в %s: %v", name, err)
+			continue
+		}
+		if *out == "" {
+			fmt.Printf("=== %s ===\n%s\n", name, data)
+			continue
+		}
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			log.Fatal(err)
+		}
+		path := filepath.Join(*out, "users."+exp.Extension())
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %s\n", name, path)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,110 @@
+// Package exporter - контракт между приложением и плагинами экспорта.
+// Хост и плагины зависят только от этого пакета, но не друг от друга.
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+)
+
+// Table данные для экспорта. Только простые типы, чтобы таблица
+// без изменений передавалась и через net/rpc (gob).
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Exporter плагин, превращающий таблицу в файл какого-то формата
+type Exporter interface {
+	Name() string      // Формат: "csv", "markdown"
+	Extension() string // Расширение файла без точки
+	Export(t Table) ([]byte, error)
+}
+
+// Symbol имя переменной, которую ищет LoadNative в .so-файле:
+//
+//	var Exporter exporter.Exporter = myExporter{}
+const Symbol = "Exporter"
+
+// ErrBadPlugin плагин загрузился, но не реализует Exporter
+var ErrBadPlugin = errors.New("plugin does not export " + Symbol)
+
+// LoadNative загружает плагин, собранный с -buildmode=plugin.
+//
+// Стандартный пакет plugin работает только на Linux, FreeBSD и macOS,
+// плагин нельзя выгрузить, а собирать его нужно той же версией Go, с
+// теми же флагами (-race, -trimpath) и теми же версиями всех общих
+// пакетов, что и хост. Иначе plugin.Open вернет ошибку "plugin was built
+// with a different version of package ...". Поэтому такие плагины
+// подходят, когда хост и плагины собираются вместе, а для сторонних
+// плагинов лучше отдельные процессы (см. пакет rpcplugin).
+func LoadNative(path string) (Exporter, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup(Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrBadPlugin)
+	}
+	// Lookup переменной возвращает указатель на нее
+	exp, ok := sym.(*Exporter)
+	if !ok || *exp == nil {
+		return nil, fmt.Errorf("%s: %w (got %T)", path, ErrBadPlugin, sym)
+	}
+	return *exp, nil
+}
+
+// Discover возвращает файлы плагинов в dir по шаблону, например "*.so".
+// Шаблон, а не все файлы подряд, чтобы не загрузить случайный файл.
+func Discover(dir, pattern string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Registry плагины по имени формата
+type Registry struct {
+	exporters map[string]Exporter
+}
+
+// NewRegistry создает пустой реестр
+func NewRegistry() *Registry {
+	return &Registry{exporters: make(map[string]Exporter)}
+}
+
+// Register добавляет плагин; два плагина одного формата - ошибка
+// конфигурации, и молча выбирать один из них нельзя
+func (r *Registry) Register(e Exporter) error {
+	if _, ok := r.exporters[e.Name()]; ok {
+		return fmt.Errorf("exporter %q already registered", e.Name())
+	}
+	r.exporters[e.Name()] = e
+	return nil
+}
+
+// Get возвращает плагин формата
+func (r *Registry) Get(name string) (Exporter, bool) {
+	e, ok := r.exporters[name]
+	return e, ok
+}
+
+// Names возвращает форматы по алфавиту
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.exporters))
+	for name := range r.exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
\ No newline at end of file
@@ -0,0 +1,124 @@
+package exporter_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"plugins/exporter"
+)
+
+// buildPlugin собирает плагин с -buildmode=plugin и теми же флагами,
+// что и тестовый бинарник, иначе plugin.Open откажется его загружать.
+// Тесты во внешнем пакете exporter_test по той же причине: внутренние
+// тесты компилируются вместе с пакетом и меняют его версию.
+func buildPlugin(t *testing.T, pkg string) string {
+	t.Helper()
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "freebsd" {
+		t.Skip("plugin is not supported on " + runtime.GOOS)
+	}
+	if testing.Short() {
+		t.Skip("building a plugin is slow")
+	}
+	
+	args := []string{"build", "-buildmode=plugin"}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "-race" && s.Value == "true" {
+				args = append(args, "-race")
+			}
+		}
+	}
+	out := filepath.Join(t.TempDir(), filepath.Base(pkg)+".so")
+	cmd := exec.Command("go", append(args, "-o", out, pkg)...)
+	cmd.Dir = ".."
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("Cannot build plugin (cgo required): %v\n%s", err, output)
+	}
+	return out
+}
+
+func TestLoadNative(t *testing.T) {
+	path := buildPlugin(t, "./plugins/markdown")
+	
+	exp, err := exporter.LoadNative(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if exp.Name() != "markdown" || exp.Extension() != "md" {
+		t.Errorf("Expected markdown/md, got %s/%s", exp.Name(), exp.Extension())
+	}
+	
+	data, err := exp.Export(exporter.Table{Columns: []string{"ID", "Name"}, Rows: [][]string{{"1", "A|B"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "| ID | Name |\n| --- | --- |\n| 1 | A\\|B |\n"
+	if string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+	
+	// Повторная загрузка того же файла возвращает тот же плагин
+	if again, err := exporter.LoadNative(path); err != nil || again.Name() != "markdown" {
+		t.Errorf("Expected repeated load to succeed, got %v", err)
+	}
+}
+
+func TestLoadNativeErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPlugin := filepath.Join(dir, "broken.so")
+	os.WriteFile(notPlugin, []byte("not an ELF file"), 0o644)
+	
+	if _, err := exporter.LoadNative(notPlugin); err == nil {
+		t.Error("Expected error for a file that is not a plugin")
+	}
+	if _, err := exporter.LoadNative(filepath.Join(dir, "missing.so")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.so", "a.so", "readme.txt"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+	}
+	
+	paths, err := exporter.Discover(dir, "*.so")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != "a.so" || filepath.Base(paths[1]) != "b.so" {
+		t.Errorf("Expected [a.so b.so], got %v", paths)
+	}
+	if _, err := exporter.Discover(filepath.Join(dir, "missing"), "*.so"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected ErrNotExist for missing dir, got %v", err)
+	}
+}
+
+type fakeExporter struct{ name string }
+
+func (f fakeExporter) Name() string                          { return f.name }
+func (f fakeExporter) Extension() string                     { return f.name }
+func (f fakeExporter) Export(exporter.Table) ([]byte, error) { return nil, nil }
+
+func TestRegistry(t *testing.T) {
+	r := exporter.NewRegistry()
+	for _, name := range []string{"csv", "markdown"} {
+		if err := r.Register(fakeExporter{name}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := r.Register(fakeExporter{"csv"}); err == nil {
+		t.Error("Expected error for duplicate exporter")
+	}
+	if names := r.Names(); len(names) != 2 || names[0] != "csv" || names[1] != "markdown" {
+		t.Errorf("Expected [csv markdown], got %v", names)
+	}
+	if _, ok := r.Get("xml"); ok {
+		t.Error("Expected unknown exporter to be missing")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"plugins/exporter"
+	"plugins/rpcplugin"
+)
+
+// Плагин для hashicorp/go-plugin: обычная программа, которую запускает
+// хост. Имя файла должно подходить под rpcplugin.Pattern:
+//
+//   go build -o bin/exporter-csv ./plugins/csv
+//
+// Запущенный вручную, он сообщит, что это плагин, и завершится.
+
+type csvExporter struct{}
+
+func (csvExporter) Name() string      { return "csv" }
+func (csvExporter) Extension() string { return "csv" }
+
+func (csvExporter) Export(t exporter.Table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Columns); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(t.Rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func main() {
+	rpcplugin.Serve(csvExporter{})
+}
\ No newline at end of file
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"plugins/exporter"
+)
+
+// Плагин для стандартного пакета plugin. Собирается как разделяемая
+// библиотека, main не вызывается:
+//
+//   go build -buildmode=plugin -o bin/markdown.so ./plugins/markdown
+//
+// Хост ищет переменную с именем exporter.Symbol.
+
+// Exporter точка входа плагина
+var Exporter exporter.Exporter = markdownExporter{}
+
+type markdownExporter struct{}
+
+func (markdownExporter) Name() string      { return "markdown" }
+func (markdownExporter) Extension() string { return "md" }
+
+func (markdownExporter) Export(t exporter.Table) ([]byte, error) {
+	var buf bytes.Buffer
+	writeRow(&buf, t.Columns)
+	sep := make([]string, len(t.Columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(&buf, sep)
+	for _, row := range t.Rows {
+		writeRow(&buf, row)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeRow(buf *bytes.Buffer, cells []string) {
+	buf.WriteString("|")
+	for _, c := range cells {
+		buf.WriteString(" " + strings.ReplaceAll(c, "|", `\|`) + " |")
+	}
+	buf.WriteString("\n")
+}
+
+// main не вызывается, но без него пакет не проходит go vet ./...
+func main() {}
\ No newline at end of file
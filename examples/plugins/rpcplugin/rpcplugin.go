@@ -0,0 +1,132 @@
+// Package rpcplugin подключает exporter.Exporter через hashicorp/go-plugin:
+// плагин - отдельный исполняемый файл, хост запускает его дочерним
+// процессом и вызывает методы по net/rpc.
+//
+// В отличие от стандартного plugin, плагин можно собрать любой версией Go
+// и на любой ОС, его падение не роняет хост, а процесс можно остановить.
+// Цена - сериализация каждого вызова и отдельный процесс на плагин.
+package rpcplugin
+
+import (
+	"net/rpc"
+	"os/exec"
+
+	"github.com/hashicorp/go-plugin"
+
+	"plugins/exporter"
+)
+
+// Handshake проверяется при запуске плагина. Cookie защищает от запуска
+// плагина как обычной программы, а ProtocolVersion - от старого плагина
+// с несовместимым интерфейсом.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "EXPORTER_PLUGIN",
+	MagicCookieValue: "b1c2c3a5-exporter",
+}
+
+// Name под этим именем плагин регистрируется с обеих сторон
+const Name = "exporter"
+
+// Pattern шаблон имен исполняемых файлов плагинов
+const Pattern = "exporter-*"
+
+// Plugin реализует plugin.Plugin: на стороне плагина отдает RPC-сервер
+// вокруг Impl, на стороне хоста - клиент с интерфейсом exporter.Exporter
+type Plugin struct {
+	Impl exporter.Exporter
+}
+
+// Server вызывается в процессе плагина
+func (p *Plugin) Server(*plugin.MuxBroker) (any, error) {
+	return &RPCServer{Impl: p.Impl}, nil
+}
+
+// Client вызывается в процессе хоста
+func (*Plugin) Client(_ *plugin.MuxBroker, c *rpc.Client) (any, error) {
+	return &RPCClient{client: c}, nil
+}
+
+// RPCClient exporter.Exporter, методы которого выполняются в плагине.
+// Ошибки транспорта (плагин упал) возвращаются как обычные ошибки.
+type RPCClient struct {
+	client *rpc.Client
+}
+
+// Name реализует exporter.Exporter
+func (c *RPCClient) Name() string {
+	var name string
+	if err := c.client.Call("Plugin.Name", struct{}{}, &name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// Extension реализует exporter.Exporter
+func (c *RPCClient) Extension() string {
+	var ext string
+	if err := c.client.Call("Plugin.Extension", struct{}{}, &ext); err != nil {
+		return ""
+	}
+	return ext
+}
+
+// Export реализует exporter.Exporter
+func (c *RPCClient) Export(t exporter.Table) ([]byte, error) {
+	var out []byte
+	err := c.client.Call("Plugin.Export", t, &out)
+	return out, err
+}
+
+// RPCServer методы в форме, которую требует net/rpc
+type RPCServer struct {
+	Impl exporter.Exporter
+}
+
+func (s *RPCServer) Name(_ struct{}, name *string) error {
+	*name = s.Impl.Name()
+	return nil
+}
+
+func (s *RPCServer) Extension(_ struct{}, ext *string) error {
+	*ext = s.Impl.Extension()
+	return nil
+}
+
+func (s *RPCServer) Export(t exporter.Table, out *[]byte) error {
+	data, err := s.Impl.Export(t)
+	*out = data
+	return err
+}
+
+// Serve запускается из main плагина и не возвращается
+func Serve(impl exporter.Exporter) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         map[string]plugin.Plugin{Name: &Plugin{Impl: impl}},
+	})
+}
+
+// Load запускает плагин и возвращает его Exporter. Процесс плагина
+// живет, пока не вызван Kill у возвращенного клиента (или
+// plugin.CleanupClients при завершении хоста).
+func Load(path string) (exporter.Exporter, *plugin.Client, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          map[string]plugin.Plugin{Name: &Plugin{}},
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolNetRPC},
+		Managed:          true,
+	})
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, nil, err
+	}
+	raw, err := rpcClient.Dispense(Name)
+	if err != nil {
+		client.Kill()
+		return nil, nil, err
+	}
+	return raw.(exporter.Exporter), client, nil
+}
\ No newline at end of file
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Предвыделение срезов и map: если число элементов известно заранее
+// (или известна верхняя граница), сказать об этом make - одно
+// выделение вместо десятка и никаких копий при росте.
+//
+//   - prealloc.go - варианты заполнения срезов и map;
+//   - prealloc_test.go - проверка, что результат одинаков, и бенчмарки.
+//
+// Запуск: go run .
+// Бенчмарки: go test -run=^$ -bench=. -benchmem
+//
+// Когда что:
+//   - размер известен точно - make([]T, n) и запись по индексу или
+//     make([]T, 0, n) и append;
+//   - известна только верхняя граница (отбор, фильтр) - make([]T, 0,
+//     max) и append; лишняя емкость - цена одного выделения;
+//   - размер знает только база (GetAllUsers в examples/database) -
+//     обычный append: отдельный COUNT(*) перед SELECT - лишний запрос
+//     с проходом по таблице, он дороже всех копий при росте, а к
+//     чтению строк число уже может устареть;
+//   - срез в горячем цикле - переиспользовать через buf[:0];
+//   - фильтр, после которого исходный срез не нужен - на месте;
+//   - map из известного числа ключей - make(map[K]V, n).
+//
+// Не надо предвыделять "на всякий случай": make([]T, 0, 1<<20) под
+// десяток элементов держит мегабайты, а make с длиной вместо емкости -
+// частая ошибка: append допишет после n нулевых элементов.
+
+// Пример 1: Как растет емкость при append по одному
+func growth() {
+	fmt.Println("=== Рост емкости ===")
+	
+	caps := capGrowth(5000)
+	fmt.Printf("append до 5000 элементов - %d выделений, емкости: %v\n", len(caps), caps)
+	fmt.Println("Шаг не ровно вдвое: емкость округляется до класса размера аллокатора")
+	
+	// make с длиной вместо емкости
+	wrong := make([]User, 3)
+	wrong = append(wrong, makeUser(0))
+	fmt.Printf("make([]User, 3) и append: len=%d, первый ID=%d (нулевой элемент)\n", len(wrong), wrong[0].ID)
+}
+
+// Пример 2: Срезы - время и выделения на 10, 1000 и 100000 элементов
+func compareSlices() {
+	fmt.Println("\n=== Срезы ===")
+	
+	for _, n := range []int{10, 1000, 100_000} {
+		fmt.Printf("%d элементов:\n", n)
+		buf := collectCap(n)
+		for _, v := range []struct {
+			name string
+			fn   func()
+		}{
+			{"append без емкости", func() { sink = collectAppend(n) }},
+			{"make(0, n) + append", func() { sink = collectCap(n) }},
+			{"make(n) + индекс", func() { sink = collectIndex(n) }},
+			{"buf[:0] + append", func() { buf = collectReuse(buf, n) }},
+		} {
+			report(v.name, v.fn)
+		}
+	}
+}
+
+// Пример 3: Отбор половины элементов - в новый срез и на месте
+func compareFilter() {
+	fmt.Println("\n=== Фильтр ===")
+	
+	even := func(u User) bool { return u.ID%2 == 0 }
+	src := collectIndex(10_000)
+	work := make([]User, len(src))
+	report("в новый срез", func() { sink = filterNew(src, even) })
+	report("на месте", func() {
+		copy(work, src) // Фильтр на месте портит вход
+		filterInPlace(work, even)
+	})
+}
+
+// Пример 4: Map с подсказкой размера и без
+func compareMaps() {
+	fmt.Println("\n=== Map ===")
+	
+	for _, n := range []int{100, 10_000} {
+		users := collectIndex(n)
+		fmt.Printf("%d ключей:\n", n)
+		report("make(map)", func() { sinkMap = indexByID(users) })
+		report("make(map, n)", func() { sinkMap = indexByIDSized(users) })
+	}
+}
+
+// sink приемник результатов: без него компилятор мог бы выбросить вызов
+// или разместить небольшой срез на стеке
+var (
+	sink    []User
+	sinkMap map[int]User
+)
+
+func report(name string, fn func()) {
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fn()
+		}
+	})
+	fmt.Printf("  %-22s %10d нс/op %10d Б/op %4d allocs/op\n",
+		name, r.NsPerOp(), r.AllocedBytesPerOp(), r.AllocsPerOp())
+}
+
+func main() {
+	growth()
+	compareSlices()
+	compareFilter()
+	compareMaps()
+}
@@ -0,0 +1,127 @@
+package main
+
+// User как в examples/database: строка таблицы users
+type User struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+// Срез без емкости растет удвоениями (с 256 элементов - примерно в 1.25
+// раза): каждый рост - новое выделение и копия всего, что уже есть.
+// Для n элементов это ~log(n) выделений и ~2n скопированных элементов.
+// Если n известен заранее, хватает одного выделения.
+
+// collectAppend append в пустой срез - как GetAllUsers в examples/database
+func collectAppend(n int) []User {
+	var users []User
+	for i := range n {
+		users = append(users, makeUser(i))
+	}
+	return users
+}
+
+// collectCap make с емкостью и append: одно выделение, а длина растет
+// сама - удобно, когда часть элементов может отсеяться
+func collectCap(n int) []User {
+	users := make([]User, 0, n)
+	for i := range n {
+		users = append(users, makeUser(i))
+	}
+	return users
+}
+
+// collectIndex make с длиной и запись по индексу: так же одно
+// выделение, но все n элементов обязаны быть заполнены - иначе в конце
+// останутся нулевые значения
+func collectIndex(n int) []User {
+	users := make([]User, n)
+	for i := range users {
+		users[i] = makeUser(i)
+	}
+	return users
+}
+
+// collectReuse переиспользует срез вызывающего: s[:0] оставляет
+// емкость, и со второго вызова выделений нет совсем. Годится, пока
+// результат не уходит дальше - следующий вызов его перезапишет.
+func collectReuse(buf []User, n int) []User {
+	buf = buf[:0]
+	for i := range n {
+		buf = append(buf, makeUser(i))
+	}
+	return buf
+}
+
+// filterNew отбор в новый срез
+func filterNew(users []User, keep func(User) bool) []User {
+	var out []User
+	for _, u := range users {
+		if keep(u) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// filterInPlace отбор на месте: users[:0] делит массив с users, и
+// запись идет поверх уже просмотренных элементов. Исходный срез после
+// вызова испорчен; хвост зануляется, чтобы строки в нем не держали
+// память (то же делает slices.DeleteFunc).
+func filterInPlace(users []User, keep func(User) bool) []User {
+	out := users[:0]
+	for _, u := range users {
+		if keep(u) {
+			out = append(out, u)
+		}
+	}
+	clear(users[len(out):])
+	return out
+}
+
+// Map без подсказки тоже растет: при заполнении на 7/8 (до Go 1.24 -
+// 6.5 ключа на корзину из 8) таблица удваивается и все ключи
+// переносятся заново. make(map, n) сразу выделяет место под n ключей.
+
+// indexByID индекс пользователей по ID без подсказки размера
+func indexByID(users []User) map[int]User {
+	m := make(map[int]User)
+	for _, u := range users {
+		m[u.ID] = u
+	}
+	return m
+}
+
+// indexByIDSized то же с make(map, len(users))
+func indexByIDSized(users []User) map[int]User {
+	m := make(map[int]User, len(users))
+	for _, u := range users {
+		m[u.ID] = u
+	}
+	return m
+}
+
+var (
+	names  = []string{"Иван", "Мария", "Олег", "Анна"}
+	emails = []string{"ivan@example.com", "maria@example.com", "oleg@example.com", "anna@example.com"}
+)
+
+// makeUser без выделений: в замерах видны только выделения под срез
+func makeUser(i int) User {
+	return User{ID: i + 1, Name: names[i%len(names)], Email: emails[i%len(emails)]}
+}
+
+// capGrowth емкости, через которые проходит срез при append по одному
+// элементу до n
+func capGrowth(n int) []int {
+	var s []User
+	caps := []int{}
+	for range n {
+		before := cap(s)
+		s = append(s, User{})
+		if cap(s) != before {
+			caps = append(caps, cap(s))
+		}
+	}
+	return caps
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCollect_SameResult(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 1000} {
+		expected := collectAppend(n)
+		buf := make([]User, 5)
+		for name, got := range map[string][]User{
+			"cap":   collectCap(n),
+			"index": collectIndex(n),
+			"reuse": collectReuse(buf, n),
+		} {
+			if len(got) != n || !slices.Equal(got, expected) {
+				t.Errorf("%s, n=%d: expected %d users equal to append, got %d", name, n, n, len(got))
+			}
+		}
+	}
+}
+
+func TestCollect_Allocs(t *testing.T) {
+	const n = 1000
+	buf := collectCap(n)
+	// Результат в sink: иначе после встраивания компилятор мог бы
+	// разместить срез на стеке
+	tests := []struct {
+		name     string
+		fn       func()
+		expected float64
+	}{
+		{"cap", func() { sink = collectCap(n) }, 1},
+		{"index", func() { sink = collectIndex(n) }, 1},
+		{"reuse", func() { buf = collectReuse(buf, n) }, 0},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(10, tt.fn); got != tt.expected {
+			t.Errorf("%s: expected %v allocs, got %v", tt.name, tt.expected, got)
+		}
+	}
+	if got := testing.AllocsPerRun(10, func() { sink = collectAppend(n) }); got < 5 {
+		t.Errorf("Expected append without capacity to grow several times, got %v allocs", got)
+	}
+}
+
+func TestFilterInPlace(t *testing.T) {
+	even := func(u User) bool { return u.ID%2 == 0 }
+	src := collectIndex(101)
+	expected := filterNew(src, even)
+	work := slices.Clone(src)
+	got := filterInPlace(work, even)
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected %d users, got %d", len(expected), len(got))
+	}
+	// Хвост исходного массива занулен - строки в нем не держат память
+	for i, u := range work[len(got):] {
+		if u != (User{}) {
+			t.Fatalf("Expected zeroed tail at %d, got %+v", len(got)+i, u)
+		}
+	}
+	if testing.AllocsPerRun(10, func() { filterInPlace(work, even) }) != 0 {
+		t.Error("Expected in-place filter to not allocate")
+	}
+}
+
+func TestIndexByID(t *testing.T) {
+	users := collectIndex(500)
+	m, sized := indexByID(users), indexByIDSized(users)
+	if len(m) != len(users) || !maps.Equal(m, sized) {
+		t.Errorf("Expected equal maps of %d users, got %d and %d", len(users), len(m), len(sized))
+	}
+}
+
+func TestCapGrowth(t *testing.T) {
+	caps := capGrowth(1000)
+	if caps[0] != 1 || caps[len(caps)-1] < 1000 || !slices.IsSorted(caps) {
+		t.Errorf("Unexpected growth %v", caps)
+	}
+}
+
+func BenchmarkCollect(b *testing.B) {
+	for _, n := range []int{10, 1000, 100_000} {
+		buf := collectCap(n)
+		for _, v := range []struct {
+			name string
+			fn   func() []User
+		}{
+			{"Append", func() []User { return collectAppend(n) }},
+			{"Cap", func() []User { return collectCap(n) }},
+			{"Index", func() []User { return collectIndex(n) }},
+			{"Reuse", func() []User { buf = collectReuse(buf, n); return buf }},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", v.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					sink = v.fn()
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	even := func(u User) bool { return u.ID%2 == 0 }
+	src := collectIndex(10_000)
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = filterNew(src, even)
+		}
+	})
+	b.Run("InPlace", func(b *testing.B) {
+		b.ReportAllocs()
+		work := make([]User, len(src))
+		for i := 0; i < b.N; i++ {
+			copy(work, src)
+			sink = filterInPlace(work, even)
+		}
+	})
+}
+
+func BenchmarkIndexByID(b *testing.B) {
+	for _, n := range []int{100, 10_000} {
+		users := collectIndex(n)
+		b.Run(fmt.Sprintf("NoHint/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sinkMap = indexByID(users)
+			}
+		})
+		b.Run(fmt.Sprintf("Sized/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sinkMap = indexByIDSized(users)
+			}
+		})
+	}
+}
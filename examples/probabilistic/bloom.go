@@ -0,0 +1,80 @@
+package main
+
+import "math"
+
+// Bloom фильтр Блума: множество, которое отвечает "точно нет" или
+// "возможно да". Ложноположительные ответы бывают с заданной вероятностью,
+// ложноотрицательных нет. Памяти нужно ~1.2 байта на элемент при 1%
+// ошибок - независимо от размера самих ключей.
+type Bloom struct {
+	bits []uint64
+	m    uint64 // Число бит
+	k    uint64 // Число хеш-функций
+	n    uint64 // Добавлено элементов
+}
+
+// NewBloom рассчитывает фильтр на n элементов с долей ложных
+// срабатываний p: m = -n·ln(p) / ln²(2), k = m/n · ln(2)
+func NewBloom(n int, p float64) *Bloom {
+	if n <= 0 || p <= 0 || p >= 1 {
+		panic("bloom: need n > 0 and 0 < p < 1")
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	words := (uint64(m) + 63) / 64
+	return &Bloom{
+		bits: make([]uint64, words),
+		m:    words * 64,
+		k:    uint64(k),
+	}
+}
+
+// Add добавляет ключ
+func (b *Bloom) Add(key []byte) {
+	h1, h2 := hashPair(key)
+	for i := range b.k {
+		pos := (h1 + i*h2) % b.m
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+	b.n++
+}
+
+// Test возвращает false, если ключа точно нет
+func (b *Bloom) Test(key []byte) bool {
+	h1, h2 := hashPair(key)
+	for i := range b.k {
+		pos := (h1 + i*h2) % b.m
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestAndAdd проверяет и добавляет ключ за один проход
+func (b *Bloom) TestAndAdd(key []byte) bool {
+	h1, h2 := hashPair(key)
+	present := true
+	for i := range b.k {
+		pos := (h1 + i*h2) % b.m
+		mask := uint64(1) << (pos % 64)
+		if b.bits[pos/64]&mask == 0 {
+			present = false
+			b.bits[pos/64] |= mask
+		}
+	}
+	b.n++
+	return present
+}
+
+// FalsePositiveRate оценивает текущую вероятность ложного срабатывания:
+// (1 - e^(-kn/m))^k. Растет по мере заполнения; когда она превышает
+// целевую, фильтр пора пересоздавать с большим n.
+func (b *Bloom) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(b.k*b.n)/float64(b.m)), float64(b.k))
+}
+
+// Params возвращает размер в битах и число хеш-функций
+func (b *Bloom) Params() (m, k uint64) {
+	return b.m, b.k
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package main
+
+import "math"
+
+// CountMin скетч частот: оценивает, сколько раз встречался ключ, в
+// фиксированной памяти. Оценка никогда не меньше истинной и с
+// вероятностью 1-delta превышает ее не более чем на epsilon·N,
+// где N - сумма всех добавленных значений.
+//
+// depth строк по width счетчиков; каждая строка со своим хешем.
+// Коллизии только увеличивают счетчики, поэтому минимум по строкам -
+// лучшая оценка.
+type CountMin struct {
+	counts [][]uint64
+	width  uint64
+	total  uint64
+}
+
+// NewCountMin создает скетч: width = ⌈e/epsilon⌉, depth = ⌈ln(1/delta)⌉
+func NewCountMin(epsilon, delta float64) *CountMin {
+	if epsilon <= 0 || delta <= 0 || delta >= 1 {
+		panic("countmin: need epsilon > 0 and 0 < delta < 1")
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	counts := make([][]uint64, depth)
+	for i := range counts {
+		counts[i] = make([]uint64, width)
+	}
+	return &CountMin{counts: counts, width: width}
+}
+
+// Add увеличивает счетчик ключа на n
+func (c *CountMin) Add(key []byte, n uint64) {
+	h1, h2 := hashPair(key)
+	for i, row := range c.counts {
+		row[(h1+uint64(i)*h2)%c.width] += n
+	}
+	c.total += n
+}
+
+// Estimate возвращает оценку частоты ключа сверху
+func (c *CountMin) Estimate(key []byte) uint64 {
+	h1, h2 := hashPair(key)
+	est := uint64(math.MaxUint64)
+	for i, row := range c.counts {
+		est = min(est, row[(h1+uint64(i)*h2)%c.width])
+	}
+	return est
+}
+
+// Total возвращает сумму всех добавленных значений
+func (c *CountMin) Total() uint64 {
+	return c.total
+}
+
+// Size возвращает число счетчиков
+func (c *CountMin) Size() int {
+	return len(c.counts) * int(c.width)
+}
\ No newline at end of file
@@ -0,0 +1,26 @@
+package main
+
+import "hash/fnv"
+
+// hashPair дает два независимых 64-битных хеша ключа. Из них строятся
+// k хешей как h1 + i*h2 (Kirsch-Mitzenmacher): точность та же, что у k
+// отдельных хеш-функций, а ключ хешируется один раз.
+func hashPair(key []byte) (h1, h2 uint64) {
+	h := fnv.New64a()
+	h.Write(key)
+	h1 = h.Sum64()
+	h2 = mix(h1)
+	h2 |= 1 // Нулевой шаг дал бы k одинаковых позиций
+	return h1, h2
+}
+
+// mix - финализатор splitmix64: перемешивает биты, чтобы h2 не был
+// линейно связан с h1
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
\ No newline at end of file
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Пример 1: Параметры и реальная доля ошибок фильтра Блума
+func bloomRates() {
+	fmt.Println("=== Фильтр Блума ===")
+	
+	const n = 100000
+	for _, p := range []float64{0.1, 0.01, 0.001} {
+		b := NewBloom(n, p)
+		for i := range n {
+			b.Add([]byte("member-" + strconv.Itoa(i)))
+		}
+		
+		fp := 0
+		for i := range n {
+			if b.Test([]byte("other-" + strconv.Itoa(i))) {
+				fp++
+			}
+		}
+		m, k := b.Params()
+		fmt.Printf("p=%-6v m=%7d бит (%5.1f КБ) k=%2d  реальная доля ошибок %.4f\n",
+			p, m, float64(m)/8/1024, k, float64(fp)/n)
+	}
+	fmt.Printf("Для сравнения: map[string]struct{} на %d ключей - порядка %d КБ\n", n, n*40/1024)
+}
+
+// Deduplicator отсеивает повторно доставленные вебхуки по X-Request-ID.
+// Отправители повторяют доставку при таймаутах, поэтому обработчик
+// должен быть идемпотентным. Фильтр Блума стоит перед точным хранилищем
+// (в реальном сервисе - таблица в БД): "точно новый" ID обрабатывается
+// без запроса к хранилищу, и только "возможно виденный" проверяется
+// точно. Сам по себе фильтр не годится - ложное срабатывание молча
+// потеряло бы настоящее событие.
+type Deduplicator struct {
+	mu     sync.Mutex
+	filter *Bloom
+	store  map[string]bool // Точное хранилище обработанных ID
+
+	storeLookups int // Сколько раз пришлось идти в хранилище
+}
+
+// NewDeduplicator создает фильтр на expected ID с долей ложных срабатываний p
+func NewDeduplicator(expected int, p float64) *Deduplicator {
+	return &Deduplicator{filter: NewBloom(expected, p), store: make(map[string]bool)}
+}
+
+// Seen возвращает true для уже обработанного ID и запоминает новый
+func (d *Deduplicator) Seen(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	if d.filter.TestAndAdd([]byte(id)) {
+		d.storeLookups++
+		if d.store[id] {
+			return true
+		}
+	}
+	d.store[id] = true
+	return false
+}
+
+// webhookHandler обрабатывает каждое событие ровно один раз
+func webhookHandler(d *Deduplicator, process func(id string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			http.Error(w, "X-Request-ID is required", http.StatusBadRequest)
+			return
+		}
+		// 200, а не 409: отправитель должен прекратить повторы
+		if d.Seen(id) {
+			fmt.Fprintln(w, "duplicate")
+			return
+		}
+		process(id)
+		fmt.Fprintln(w, "processed")
+	}
+}
+
+// Пример 2: Дедупликация вебхуков
+func webhookDedup() {
+	fmt.Println("\n=== Дедупликация вебхуков ===")
+	
+	dedup := NewDeduplicator(10000, 0.01)
+	processed := 0
+	server := httptest.NewServer(webhookHandler(dedup, func(string) { processed++ }))
+	defer server.Close()
+	
+	// 2000 событий, каждое пятое доставлено повторно
+	rng := rand.New(rand.NewSource(1))
+	sent, duplicates := 0, 0
+	for i := range 2000 {
+		deliveries := 1
+		if rng.Intn(5) == 0 {
+			deliveries = 2
+			duplicates++
+		}
+		for range deliveries {
+			req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+			req.Header.Set("X-Request-ID", "evt_"+strconv.Itoa(i))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				log.Fatal(err)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			sent++
+		}
+	}
+	
+	fmt.Printf("Доставок: %d, повторов: %d, обработано: %d\n", sent, duplicates, processed)
+	fmt.Printf("Обращений к точному хранилищу: %d (вместо %d без фильтра)\n", dedup.storeLookups, sent)
+	fmt.Printf("Из них ложных срабатываний фильтра: %d\n", dedup.storeLookups-duplicates)
+}
+
+// Пример 3: Частоты в потоке событий
+func countMinSketch() {
+	fmt.Println("\n=== Count-min sketch ===")
+	
+	// Запросы от 50 000 клиентов, немногие из которых очень активны
+	const events = 1000000
+	rng := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rng, 1.2, 1, 50000)
+	
+	cm := NewCountMin(0.001, 0.01)
+	exact := make(map[string]uint64)
+	for range events {
+		client := "10.0." + strconv.FormatUint(zipf.Uint64(), 10)
+		cm.Add([]byte(client), 1)
+		exact[client]++
+	}
+	
+	type pair struct {
+		client string
+		count  uint64
+	}
+	var top []pair
+	for c, n := range exact {
+		top = append(top, pair{c, n})
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].count > top[j].count })
+	
+	fmt.Printf("Скетч: %d счетчиков, допуск ошибки ε·N = %.0f\n", cm.Size(), 0.001*float64(cm.Total()))
+	fmt.Printf("%-12s %8s %8s\n", "клиент", "точно", "оценка")
+	for _, p := range append(top[:5:5], top[len(top)/2], top[len(top)-1]) {
+		fmt.Printf("%-12s %8d %8d\n", p.client, p.count, cm.Estimate([]byte(p.client)))
+	}
+	fmt.Println("Вывод: частым ключам скетч точен относительно, редким - только в пределах ε·N")
+}
+
+func main() {
+	bloomRates()
+	webhookDedup()
+	countMinSketch()
+}
\ No newline at end of file
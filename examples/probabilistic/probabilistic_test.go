@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestBloomNoFalseNegatives(t *testing.T) {
+	b := NewBloom(1000, 0.01)
+	for i := range 1000 {
+		b.Add([]byte(strconv.Itoa(i)))
+	}
+	for i := range 1000 {
+		if !b.Test([]byte(strconv.Itoa(i))) {
+			t.Fatalf("False negative for %d", i)
+		}
+	}
+}
+
+// Эмпирическая доля ложных срабатываний должна быть близка к заданной
+func TestBloomFalsePositiveRate(t *testing.T) {
+	const n = 50000
+	for _, p := range []float64{0.05, 0.01, 0.001} {
+		t.Run(strconv.FormatFloat(p, 'g', -1, 64), func(t *testing.T) {
+			b := NewBloom(n, p)
+			for i := range n {
+				b.Add([]byte("in-" + strconv.Itoa(i)))
+			}
+			
+			const probes = 200000
+			fp := 0
+			for i := range probes {
+				if b.Test([]byte("out-" + strconv.Itoa(i))) {
+					fp++
+				}
+			}
+			rate := float64(fp) / probes
+			t.Logf("target %v, measured %.5f, estimated %.5f", p, rate, b.FalsePositiveRate())
+			if rate > p*1.5 {
+				t.Errorf("Expected false positive rate near %v, got %.5f", p, rate)
+			}
+		})
+	}
+}
+
+func TestBloomOverfillDegrades(t *testing.T) {
+	b := NewBloom(1000, 0.01)
+	for i := range 5000 { // В пять раз больше расчетного
+		b.Add([]byte(strconv.Itoa(i)))
+	}
+	if b.FalsePositiveRate() < 0.1 {
+		t.Errorf("Expected high estimated rate for overfilled filter, got %.4f", b.FalsePositiveRate())
+	}
+}
+
+func TestCountMinErrorBounds(t *testing.T) {
+	const (
+		epsilon = 0.001
+		delta   = 0.01
+		events  = 200000
+	)
+	rng := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rng, 1.1, 1, 20000)
+	cm := NewCountMin(epsilon, delta)
+	exact := make(map[string]uint64)
+	for range events {
+		key := strconv.FormatUint(zipf.Uint64(), 10)
+		cm.Add([]byte(key), 1)
+		exact[key]++
+	}
+	
+	bound := uint64(epsilon * events)
+	exceeded := 0
+	for key, n := range exact {
+		est := cm.Estimate([]byte(key))
+		if est < n {
+			t.Fatalf("Key %s: estimate %d below true count %d", key, est, n)
+		}
+		if est-n > bound {
+			exceeded++
+		}
+	}
+	
+	// Гарантия вероятностная: граница может быть превышена для доли
+	// ключей не больше delta
+	if rate := float64(exceeded) / float64(len(exact)); rate > delta {
+		t.Errorf("Expected at most %v of keys above ε·N, got %.4f", delta, rate)
+	}
+	if cm.Total() != events {
+		t.Errorf("Expected total %d, got %d", events, cm.Total())
+	}
+}
+
+func TestDeduplicator(t *testing.T) {
+	d := NewDeduplicator(100, 0.01)
+	for i := range 100 {
+		if d.Seen("evt_" + strconv.Itoa(i)) {
+			t.Fatalf("Event %d reported as duplicate on first delivery", i)
+		}
+	}
+	for i := range 100 {
+		if !d.Seen("evt_" + strconv.Itoa(i)) {
+			t.Fatalf("Event %d not detected as duplicate", i)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// RunContract общий набор тестов, который обязана пройти любая реализация
+// UserRepository. newRepo должен возвращать пустое хранилище.
+func RunContract(t *testing.T, newRepo func(t *testing.T) UserRepository) {
+	ctx := context.Background()
+	
+	t.Run("create assigns distinct IDs", func(t *testing.T) {
+		repo := newRepo(t)
+		a := &User{Name: "A", Email: "a@example.com"}
+		b := &User{Name: "B", Email: "b@example.com"}
+		mustCreate(t, repo, a)
+		mustCreate(t, repo, b)
+		
+		if a.ID == 0 || b.ID == 0 || a.ID == b.ID {
+			t.Errorf("Expected distinct non-zero IDs, got %d and %d", a.ID, b.ID)
+		}
+	})
+	
+	t.Run("get returns created user", func(t *testing.T) {
+		repo := newRepo(t)
+		u := &User{Name: "Иван", Email: "ivan@example.com"}
+		mustCreate(t, repo, u)
+		
+		got, err := repo.GetByID(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if *got != *u {
+			t.Errorf("Expected %+v, got %+v", *u, *got)
+		}
+	})
+	
+	t.Run("duplicate email on create", func(t *testing.T) {
+		repo := newRepo(t)
+		mustCreate(t, repo, &User{Name: "A", Email: "same@example.com"})
+		
+		err := repo.Create(ctx, &User{Name: "B", Email: "same@example.com"})
+		if !errors.Is(err, ErrDuplicateEmail) {
+			t.Errorf("Expected ErrDuplicateEmail, got %v", err)
+		}
+	})
+	
+	t.Run("duplicate email on update", func(t *testing.T) {
+		repo := newRepo(t)
+		mustCreate(t, repo, &User{Name: "A", Email: "a@example.com"})
+		b := &User{Name: "B", Email: "b@example.com"}
+		mustCreate(t, repo, b)
+		
+		b.Email = "a@example.com"
+		if err := repo.Update(ctx, b); !errors.Is(err, ErrDuplicateEmail) {
+			t.Errorf("Expected ErrDuplicateEmail, got %v", err)
+		}
+	})
+	
+	t.Run("update keeps own email", func(t *testing.T) {
+		repo := newRepo(t)
+		u := &User{Name: "A", Email: "a@example.com"}
+		mustCreate(t, repo, u)
+		
+		u.Name = "A2"
+		if err := repo.Update(ctx, u); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got, _ := repo.GetByID(ctx, u.ID)
+		if got.Name != "A2" {
+			t.Errorf("Expected 'A2', got '%s'", got.Name)
+		}
+	})
+	
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		
+		if _, err := repo.GetByID(ctx, 404); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetByID: expected ErrNotFound, got %v", err)
+		}
+		if err := repo.Update(ctx, &User{ID: 404, Email: "x@example.com"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Update: expected ErrNotFound, got %v", err)
+		}
+		if err := repo.Delete(ctx, 404); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Delete: expected ErrNotFound, got %v", err)
+		}
+	})
+	
+	t.Run("delete removes user", func(t *testing.T) {
+		repo := newRepo(t)
+		u := &User{Name: "A", Email: "a@example.com"}
+		mustCreate(t, repo, u)
+		
+		if err := repo.Delete(ctx, u.ID); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := repo.GetByID(ctx, u.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound after delete, got %v", err)
+		}
+		// Email освобождается после удаления
+		mustCreate(t, repo, &User{Name: "A", Email: "a@example.com"})
+	})
+	
+	t.Run("list is ordered by ID", func(t *testing.T) {
+		repo := newRepo(t)
+		
+		users, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if users == nil || len(users) != 0 {
+			t.Errorf("Expected empty non-nil list, got %#v", users)
+		}
+		
+		for _, email := range []string{"c@example.com", "a@example.com", "b@example.com"} {
+			mustCreate(t, repo, &User{Name: email, Email: email})
+		}
+		users, _ = repo.List(ctx)
+		if len(users) != 3 {
+			t.Fatalf("Expected 3 users, got %d", len(users))
+		}
+		for i := 1; i < len(users); i++ {
+			if users[i-1].ID >= users[i].ID {
+				t.Errorf("List is not ordered by ID: %+v", users)
+			}
+		}
+	})
+}
+
+func mustCreate(t *testing.T, repo UserRepository, u *User) {
+	t.Helper()
+	if err := repo.Create(context.Background(), u); err != nil {
+		t.Fatalf("Create(%+v): %v", *u, err)
+	}
+}
\ No newline at end of file
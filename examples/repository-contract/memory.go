@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryRepository реализация в памяти
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	users  map[int64]User
+	nextID int64
+}
+
+// NewMemoryRepository создает пустое хранилище
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{users: make(map[int64]User), nextID: 1}
+}
+
+func (r *MemoryRepository) emailTaken(email string, exceptID int64) bool {
+	for id, u := range r.users {
+		if u.Email == email && id != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// Create сохраняет пользователя
+func (r *MemoryRepository) Create(ctx context.Context, u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.emailTaken(u.Email, 0) {
+		return ErrDuplicateEmail
+	}
+	u.ID = r.nextID
+	r.nextID++
+	r.users[u.ID] = *u
+	return nil
+}
+
+// GetByID возвращает пользователя
+func (r *MemoryRepository) GetByID(ctx context.Context, id int64) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &u, nil
+}
+
+// List возвращает пользователей в порядке ID
+func (r *MemoryRepository) List(ctx context.Context) ([]User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	users := make([]User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// Update обновляет пользователя
+func (r *MemoryRepository) Update(ctx context.Context, u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if _, ok := r.users[u.ID]; !ok {
+		return ErrNotFound
+	}
+	if r.emailTaken(u.Email, u.ID) {
+		return ErrDuplicateEmail
+	}
+	r.users[u.ID] = *u
+	return nil
+}
+
+// Delete удаляет пользователя
+func (r *MemoryRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if _, ok := r.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// Ошибки, одинаковые для всех реализаций: вызывающий код проверяет
+// errors.Is и не знает, какая БД под капотом
+var (
+	ErrNotFound       = errors.New("пользователь не найден")
+	ErrDuplicateEmail = errors.New("email уже существует")
+)
+
+// User модель пользователя
+type User struct {
+	ID    int64
+	Name  string
+	Email string
+}
+
+// UserRepository контракт хранилища пользователей
+type UserRepository interface {
+	// Create сохраняет пользователя и заполняет u.ID
+	Create(ctx context.Context, u *User) error
+	// GetByID возвращает ErrNotFound, если пользователя нет
+	GetByID(ctx context.Context, id int64) (*User, error)
+	// List возвращает всех пользователей в порядке ID
+	List(ctx context.Context) ([]User, error)
+	// Update возвращает ErrNotFound или ErrDuplicateEmail
+	Update(ctx context.Context, u *User) error
+	// Delete возвращает ErrNotFound, если пользователя нет
+	Delete(ctx context.Context, id int64) error
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	RunContract(t, func(t *testing.T) UserRepository {
+		return NewMemoryRepository()
+	})
+}
+
+func TestSQLiteRepository(t *testing.T) {
+	RunContract(t, func(t *testing.T) UserRepository {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		
+		repo, err := NewSQLRepository(db, SQLite)
+		if err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+		return repo
+	})
+}
+
+// Postgres нужен запущенный сервер:
+// docker run -d -p 5432:5432 -e POSTGRES_PASSWORD=secret -e POSTGRES_DB=test postgres:16
+func TestPostgresRepository(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN не задан")
+	}
+	
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	RunContract(t, func(t *testing.T) UserRepository {
+		repo, err := NewSQLRepository(db, Postgres)
+		if err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+		// Подтесты идут последовательно, поэтому достаточно очищать таблицу
+		if _, err := db.Exec(`TRUNCATE users RESTART IDENTITY`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		return repo
+	})
+}
\ No newline at end of file
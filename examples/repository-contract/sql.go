@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Dialect различия между SQLite и Postgres
+type Dialect struct {
+	Name string
+	// Rebind переписывает плейсхолдеры ? в синтаксис драйвера
+	Rebind func(query string) string
+	// IsUniqueViolation распознает нарушение уникальности
+	IsUniqueViolation func(err error) bool
+	// Schema DDL таблицы users
+	Schema string
+}
+
+// SQLite диалект для github.com/mattn/go-sqlite3
+var SQLite = Dialect{
+	Name:   "sqlite",
+	Rebind: func(q string) string { return q },
+	IsUniqueViolation: func(err error) bool {
+		return strings.Contains(err.Error(), "UNIQUE constraint failed")
+	},
+	Schema: `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL
+	);`,
+}
+
+// Postgres диалект для github.com/lib/pq
+var Postgres = Dialect{
+	Name: "postgres",
+	Rebind: func(q string) string {
+		var b strings.Builder
+		n := 0
+		for _, r := range q {
+			if r == '?' {
+				n++
+				fmt.Fprintf(&b, "$%d", n)
+				continue
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	},
+	IsUniqueViolation: func(err error) bool {
+		// SQLSTATE 23505 unique_violation
+		return strings.Contains(err.Error(), "23505") || strings.Contains(err.Error(), "duplicate key")
+	},
+	Schema: `
+	CREATE TABLE IF NOT EXISTS users (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL
+	);`,
+}
+
+// SQLRepository реализация поверх database/sql
+type SQLRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSQLRepository создает хранилище и таблицу
+func NewSQLRepository(db *sql.DB, dialect Dialect) (*SQLRepository, error) {
+	if _, err := db.Exec(dialect.Schema); err != nil {
+		return nil, err
+	}
+	return &SQLRepository{db: db, dialect: dialect}, nil
+}
+
+func (r *SQLRepository) q(query string) string {
+	return r.dialect.Rebind(query)
+}
+
+// mapErr переводит ошибки драйвера в ошибки контракта
+func (r *SQLRepository) mapErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == sql.ErrNoRows:
+		return ErrNotFound
+	case r.dialect.IsUniqueViolation(err):
+		return ErrDuplicateEmail
+	}
+	return err
+}
+
+// Create сохраняет пользователя. RETURNING поддерживают обе БД
+// (SQLite с версии 3.35), в отличие от LastInsertId, которого нет в pq.
+func (r *SQLRepository) Create(ctx context.Context, u *User) error {
+	err := r.db.QueryRowContext(ctx, r.q(`INSERT INTO users (name, email) VALUES (?, ?) RETURNING id`),
+		u.Name, u.Email).Scan(&u.ID)
+	return r.mapErr(err)
+}
+
+// GetByID возвращает пользователя
+func (r *SQLRepository) GetByID(ctx context.Context, id int64) (*User, error) {
+	var u User
+	err := r.db.QueryRowContext(ctx, r.q(`SELECT id, name, email FROM users WHERE id = ?`), id).
+		Scan(&u.ID, &u.Name, &u.Email)
+	if err != nil {
+		return nil, r.mapErr(err)
+	}
+	return &u, nil
+}
+
+// List возвращает пользователей в порядке ID
+func (r *SQLRepository) List(ctx context.Context) ([]User, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, email FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	users := []User{}
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// Update обновляет пользователя
+func (r *SQLRepository) Update(ctx context.Context, u *User) error {
+	result, err := r.db.ExecContext(ctx, r.q(`UPDATE users SET name = ?, email = ? WHERE id = ?`), u.Name, u.Email, u.ID)
+	if err != nil {
+		return r.mapErr(err)
+	}
+	return affectedOne(result)
+}
+
+// Delete удаляет пользователя
+func (r *SQLRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, r.q(`DELETE FROM users WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+	return affectedOne(result)
+}
+
+func affectedOne(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
\ No newline at end of file
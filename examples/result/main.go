@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Result[T] и Option[T] пришли из Rust и функциональных языков. В Go их
+// нет специально: (T, error) и (T, bool) с if err != nil делают то же
+// самое без обертки, и весь стандартный код написан так. Пример
+// показывает, где обертка все-таки помогает и где только мешает.
+//
+// Помогает:
+//   - в каналах: chan Result[T] несет значение и ошибку вместе, вместо
+//     двух каналов, которые легко рассинхронизировать (пример 1);
+//   - в срезах и полях: []Result[T] - итог пакетной операции, где часть
+//     элементов упала, а остальные нужны (Collect);
+//   - Option в полях структур, где "не задано" отличается от нуля, если
+//     *T неудобен (пример 3).
+//
+// Мешает:
+//   - в возвращаемых значениях функций: func F() Result[T] вместо
+//     func F() (T, error) ломает совместимость со всем остальным кодом,
+//     и вызывающему все равно придется вызвать Get;
+//   - в длинных цепочках Map/AndThen: ошибка не говорит, на каком шаге
+//     она случилась, если шаг сам не обернул ее через fmt.Errorf("...: %w"),
+//     а отладчик и стек показывают анонимные функции (пример 2).
+
+type user struct {
+	ID   int
+	Name string
+	Age  int
+}
+
+// parseUser разбирает строку "id,name,age"
+func parseUser(line string) (user, error) {
+	parts := strings.Split(line, ",")
+	if len(parts) != 3 {
+		return user{}, fmt.Errorf("ожидалось 3 поля, получено %d", len(parts))
+	}
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return user{}, fmt.Errorf("id: %w", err)
+	}
+	age, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return user{}, fmt.Errorf("age: %w", err)
+	}
+	return user{ID: id, Name: parts[1], Age: age}, nil
+}
+
+var errUnderage = errors.New("младше 18 лет")
+
+func validateUser(u user) (user, error) {
+	if u.Age < 18 {
+		return u, fmt.Errorf("%s: %w", u.Name, errUnderage)
+	}
+	return u, nil
+}
+
+var lines = []string{
+	"1,Иван Иванов,30",
+	"2,Мария Петрова,abc",
+	"3,Марк Лебедев,17",
+	"4,Ирина Волкова,25",
+	"5,Игорь",
+}
+
+// Пример 1: Result в канале пула воркеров
+func channelExample() {
+	fmt.Println("=== Result в канале ===")
+	
+	// С двумя каналами, results и errs, читатель должен слушать оба через
+	// select и сам понять, когда закончились оба; забыл вычитать errs -
+	// воркер навсегда заблокирован на отправке. С одним каналом
+	// Result[user] у каждой задачи ровно один ответ.
+	jobs := make(chan int)
+	results := make(chan Result[user])
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := Of(parseUser(lines[i]))
+				// Ошибка без номера строки бесполезна: контекст добавляем
+				// до того, как результат уйдет в канал
+				if err := r.Err(); err != nil {
+					r = Err[user](fmt.Errorf("строка %d: %w", i+1, err))
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		for i := range lines {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	
+	var all []Result[user]
+	for r := range results {
+		all = append(all, r)
+	}
+	users, err := Collect(all)
+	fmt.Printf("Разобрано: %d из %d\n", len(users), len(lines))
+	if err != nil {
+		fmt.Printf("Ошибки:\n%v\n", err)
+	}
+}
+
+// Пример 2: Цепочка Map/AndThen против if err != nil
+func chainExample() {
+	fmt.Println("\n=== Цепочка против if err != nil ===")
+	
+	// Через Result: шаги читаются сверху вниз, проверки ошибок спрятаны
+	greetResult := func(line string) Result[string] {
+		parsed := Of(parseUser(line))
+		valid := AndThen(parsed, func(u user) Result[user] { return Of(validateUser(u)) })
+		return Map(valid, func(u user) string { return "Привет, " + u.Name })
+	}
+	
+	// Идиоматично: столько же строк, ошибки видны, контекст добавляется
+	// там, где он известен, и это обычный код, понятный любому на Go
+	greet := func(line string) (string, error) {
+		u, err := parseUser(line)
+		if err != nil {
+			return "", fmt.Errorf("разбор: %w", err)
+		}
+		if _, err := validateUser(u); err != nil {
+			return "", err
+		}
+		return "Привет, " + u.Name, nil
+	}
+	
+	for _, line := range lines[:3] {
+		s, err := greetResult(line).Get()
+		fmt.Printf("Result:     %-22q %v\n", s, err)
+		s, err = greet(line)
+		fmt.Printf("if err:     %-22q %v\n", s, err)
+	}
+	// errors.Is работает в обоих вариантах: Result хранит ту же ошибку
+	_, err := greetResult(lines[2]).Get()
+	fmt.Println("errors.Is(err, errUnderage):", errors.Is(err, errUnderage))
+}
+
+// Пример 3: Option для необязательных настроек
+func optionExample() {
+	fmt.Println("\n=== Option ===")
+	
+	env := map[string]string{"PORT": "9090", "WORKERS": "ноль"}
+	parseInt := func(s string) Option[int] {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return None[int]()
+		}
+		return Some(n)
+	}
+	for _, key := range []string{"PORT", "WORKERS", "TIMEOUT"} {
+		v := AndThenOption(Lookup(env, key), parseInt).ValueOr(-1)
+		fmt.Printf("%-8s %d\n", key, v)
+	}
+	
+	// То же с comma-ok; здесь Option ничего не дает - ветки короткие,
+	// а "нет значения" и "не разобралось" легко различить, если нужно
+	port := 8080
+	if s, ok := env["PORT"]; ok {
+		if n, err := strconv.Atoi(s); err == nil {
+			port = n
+		}
+	}
+	fmt.Println("PORT через comma-ok:", port)
+	
+	// Где Option удобнее указателя: поле "задано ли" в значении, которое
+	// копируется, - копия не делит данные с оригиналом
+	type patch struct {
+		Name Option[string]
+		Age  Option[int]
+	}
+	p := patch{Age: Some(31)}
+	fmt.Println("Имя меняется:", p.Name.IsSome(), " возраст:", p.Age.ValueOr(0))
+}
+
+func main() {
+	channelExample()
+	chainExample()
+	optionExample()
+	fmt.Println("\nТесты: go test -race ./...")
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package main
+
+// Option значение, которого может не быть. Идиоматичные замены: пара
+// (T, bool) для возврата из функции и *T для поля структуры. Option
+// честнее указателя - его не разыменуешь без проверки, и он не делит
+// данные с тем, кто его создал, - но JSON, database/sql и чужие
+// библиотеки его не знают.
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some значение есть
+func Some[T any](v T) Option[T] {
+	return Option[T]{value: v, ok: true}
+}
+
+// None значения нет
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// Lookup ключ из map как Option
+func Lookup[K comparable, V any](m map[K]V, k K) Option[V] {
+	v, ok := m[k]
+	return Option[V]{value: v, ok: ok}
+}
+
+// Get возвращает обычную пару (T, bool)
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// IsSome true, если значение есть
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// ValueOr значение или def
+func (o Option[T]) ValueOr(def T) T {
+	if !o.ok {
+		return def
+	}
+	return o.value
+}
+
+// MapOption применяет f к значению, если оно есть
+func MapOption[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return Some(f(o.value))
+}
+
+// AndThenOption продолжает цепочку шагом, который сам может не дать
+// значения
+func AndThenOption[T, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return f(o.value)
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package main
+
+import "errors"
+
+// Result значение или ошибка в одном объекте. В Go для этого есть пара
+// (T, error), и почти всегда она лучше (см. main.go). Result нужен там,
+// где пару некуда вернуть: в канале, в срезе, в поле структуры.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok успешный результат
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err результат с ошибкой; err не должна быть nil
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("result: Err(nil)")
+	}
+	return Result[T]{err: err}
+}
+
+// Of собирает Result из обычной пары: Of(strconv.Atoi(s))
+func Of[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Result[T]{value: v}
+}
+
+// Get возвращает обычную пару (T, error) - выход обратно в
+// идиоматичный Go
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// IsOk true, если ошибки нет
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err ошибка или nil
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// ValueOr значение или def при ошибке
+func (r Result[T]) ValueOr(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Map применяет f к значению; ошибка проходит без изменений. Это
+// функция, а не метод: у методов в Go не бывает своих параметров типа,
+// и r.Map(f) не смог бы превратить Result[T] в Result[U].
+func Map[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Ok(f(r.value))
+}
+
+// AndThen продолжает цепочку шагом, который сам может завершиться
+// ошибкой. Первая ошибка останавливает цепочку.
+func AndThen[T, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return f(r.value)
+}
+
+// Collect разбирает срез результатов: значения успешных и все ошибки,
+// объединенные errors.Join (nil, если ошибок нет)
+func Collect[T any](results []Result[T]) ([]T, error) {
+	values := make([]T, 0, len(results))
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		values = append(values, r.value)
+	}
+	return values, errors.Join(errs...)
+}
\ No newline at end of file
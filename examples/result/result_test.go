@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+var errTest = errors.New("test")
+
+func TestResult(t *testing.T) {
+	double := func(n int) int { return 2 * n }
+	positive := func(n int) Result[int] {
+		if n <= 0 {
+			return Err[int](errTest)
+		}
+		return Ok(n)
+	}
+	
+	tests := []struct {
+		name    string
+		in      Result[int]
+		want    int
+		wantErr error
+	}{
+		{"ok", Ok(2), 4, nil},
+		{"err passes through", Err[int](errTest), 0, errTest},
+		{"and then fails", Ok(-1), 0, errTest},
+		{"from pair", Of(strconv.Atoi("21")), 42, nil},
+		{"from pair with error", Of(strconv.Atoi("x")), 0, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Map(AndThen(tt.in, positive), double).Get()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResultMapNotCalledOnError(t *testing.T) {
+	called := false
+	Map(Err[int](errTest), func(n int) string {
+		called = true
+		return ""
+	})
+	if called {
+		t.Error("Expected Map to skip f on error")
+	}
+	if v := Err[int](errTest).ValueOr(7); v != 7 {
+		t.Errorf("Expected default 7, got %d", v)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	values, err := Collect([]Result[int]{Ok(1), Err[int](errA), Ok(3), Err[int](errB)})
+	if !slices.Equal(values, []int{1, 3}) {
+		t.Errorf("Expected [1 3], got %v", values)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Expected both errors, got %v", err)
+	}
+	if _, err := Collect([]Result[int]{Ok(1)}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestOption(t *testing.T) {
+	m := map[string]string{"a": "1", "b": "x"}
+	parse := func(s string) Option[int] {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return None[int]()
+		}
+		return Some(n)
+	}
+	
+	tests := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{"a", 10, true},
+		{"b", 0, false}, // Есть в map, но не число
+		{"c", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			o := MapOption(AndThenOption(Lookup(m, tt.key), parse), func(n int) int { return n * 10 })
+			got, ok := o.Get()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Expected (%d, %v), got (%d, %v)", tt.want, tt.ok, got, ok)
+			}
+			if o.IsSome() != tt.ok {
+				t.Errorf("Expected IsSome %v", tt.ok)
+			}
+		})
+	}
+	if v := None[string]().ValueOr("def"); v != "def" {
+		t.Errorf("Expected def, got %q", v)
+	}
+}
+
+func TestParseUsersInPool(t *testing.T) {
+	var results []Result[user]
+	for _, line := range lines {
+		results = append(results, Of(parseUser(line)))
+	}
+	users, err := Collect(results)
+	if len(users) != 3 {
+		t.Errorf("Expected 3 parsed users, got %d", len(users))
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expected syntax error in joined errors, got %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// Запуск с разными настройками GC:
+// GOGC=50 go run .        - GC чаще, куча меньше
+// GOGC=400 go run .       - GC реже, куча больше
+// GOGC=off GOMEMLIMIT=64MiB go run . - GC только при приближении к лимиту
+
+var sink [][]byte
+
+// Пример 1: runtime.MemStats
+func memStats() {
+	fmt.Println("=== runtime.MemStats ===")
+	
+	// ReadMemStats останавливает мир (STW) - не вызывайте его в горячем цикле
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	
+	fmt.Printf("HeapAlloc:    %s (живые объекты в куче)\n", formatBytes(m.HeapAlloc))
+	fmt.Printf("HeapSys:      %s (получено от ОС под кучу)\n", formatBytes(m.HeapSys))
+	fmt.Printf("TotalAlloc:   %s (выделено за все время)\n", formatBytes(m.TotalAlloc))
+	fmt.Printf("Sys:          %s (всего получено от ОС)\n", formatBytes(m.Sys))
+	fmt.Printf("Mallocs/Frees: %d/%d\n", m.Mallocs, m.Frees)
+	fmt.Printf("NumGC:        %d, NextGC: %s\n", m.NumGC, formatBytes(m.NextGC))
+}
+
+// Пример 2: runtime/metrics - стабильный API без STW
+func runtimeMetrics() {
+	fmt.Println("\n=== runtime/metrics ===")
+	
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/gc/heap/goal:bytes"},
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/sched/goroutines:goroutines"},
+		{Name: "/sched/gomaxprocs:threads"},
+		{Name: "/gc/gogc:percent"},
+		{Name: "/gc/gomemlimit:bytes"},
+	}
+	metrics.Read(samples)
+	
+	for _, s := range samples {
+		switch s.Value.Kind() {
+		case metrics.KindUint64:
+			fmt.Printf("%-40s %d\n", s.Name, s.Value.Uint64())
+		case metrics.KindFloat64:
+			fmt.Printf("%-40s %f\n", s.Name, s.Value.Float64())
+		case metrics.KindBad:
+			// Метрика не поддерживается этой версией Go
+			fmt.Printf("%-40s не поддерживается\n", s.Name)
+		}
+	}
+	
+	// Полный список метрик с описаниями
+	fmt.Printf("Всего метрик доступно: %d\n", len(metrics.All()))
+}
+
+// Пример 3: История пауз GC
+func gcPauses() {
+	fmt.Println("\n=== Паузы GC ===")
+	
+	for i := 0; i < 5; i++ {
+		allocate(20 << 20)
+		runtime.GC() // Принудительный сбор - только для демонстрации
+	}
+	
+	var stats debug.GCStats
+	stats.PauseQuantiles = make([]time.Duration, 5) // min, 25%, 50%, 75%, max
+	debug.ReadGCStats(&stats)
+	
+	fmt.Printf("Циклов GC: %d, суммарная пауза: %v\n", stats.NumGC, stats.PauseTotal)
+	for i, p := range stats.Pause {
+		if i == 5 {
+			break
+		}
+		fmt.Printf("  пауза #%d: %v\n", i+1, p) // Pause[0] - самая свежая
+	}
+	fmt.Printf("Квантили: min=%v p50=%v max=%v\n",
+		stats.PauseQuantiles[0], stats.PauseQuantiles[2], stats.PauseQuantiles[4])
+	
+	// То же распределение без STW - гистограмма из runtime/metrics
+	sample := []metrics.Sample{{Name: "/sched/pauses/total/gc:seconds"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() == metrics.KindFloat64Histogram {
+		h := sample[0].Value.Float64Histogram()
+		var total uint64
+		for _, c := range h.Counts {
+			total += c
+		}
+		fmt.Printf("Пауз в гистограмме: %d\n", total)
+	}
+}
+
+// Пример 4: Горутины и GOMAXPROCS в реальном времени
+func liveScheduler() {
+	fmt.Println("\n=== Планировщик ===")
+	
+	fmt.Printf("NumCPU: %d, GOMAXPROCS: %d\n", runtime.NumCPU(), runtime.GOMAXPROCS(0))
+	
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-stop
+		}()
+	}
+	fmt.Printf("Горутин после запуска 100: %d\n", runtime.NumGoroutine())
+	
+	close(stop)
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+	fmt.Printf("Горутин после завершения: %d\n", runtime.NumGoroutine())
+	
+	// GOMAXPROCS(n) меняет значение и возвращает предыдущее
+	prev := runtime.GOMAXPROCS(1)
+	fmt.Printf("GOMAXPROCS: %d -> %d\n", prev, runtime.GOMAXPROCS(0))
+	runtime.GOMAXPROCS(prev)
+}
+
+// allocate выделяет n байт мелкими кусками, удерживая лишь последние
+func allocate(n int) {
+	sink = sink[:0]
+	for allocated := 0; allocated < n; allocated += 64 << 10 {
+		sink = append(sink, make([]byte, 64<<10))
+		if len(sink) > 64 {
+			sink = sink[1:]
+		}
+	}
+}
+
+// workload выделяет total байт и возвращает число циклов GC и пиковую кучу
+func workload(total int) (cycles uint32, peak uint64, elapsed time.Duration) {
+	runtime.GC()
+	var before, m runtime.MemStats
+	runtime.ReadMemStats(&before)
+	
+	start := time.Now()
+	for i := 0; i < total/(8<<20); i++ {
+		allocate(8 << 20)
+		runtime.ReadMemStats(&m)
+		if m.HeapAlloc > peak {
+			peak = m.HeapAlloc
+		}
+	}
+	return m.NumGC - before.NumGC, peak, time.Since(start)
+}
+
+// Пример 5: Влияние GOGC и GOMEMLIMIT
+func gcTuning() {
+	fmt.Println("\n=== GOGC и GOMEMLIMIT ===")
+	
+	// SetGCPercent и SetMemoryLimit - программные аналоги переменных окружения
+	origGOGC := debug.SetGCPercent(100)
+	origLimit := debug.SetMemoryLimit(-1) // -1 - только прочитать текущее значение
+	defer debug.SetGCPercent(origGOGC)
+	defer debug.SetMemoryLimit(origLimit)
+	
+	configs := []struct {
+		name  string
+		gogc  int
+		limit int64
+	}{
+		{"GOGC=25", 25, origLimit},
+		{"GOGC=100", 100, origLimit},
+		{"GOGC=400", 400, origLimit},
+		{"GOGC=off, GOMEMLIMIT=32MiB", -1, 32 << 20},
+	}
+	
+	for _, c := range configs {
+		debug.SetGCPercent(c.gogc)
+		debug.SetMemoryLimit(c.limit)
+		
+		cycles, peak, elapsed := workload(400 << 20)
+		fmt.Printf("%-28s циклов GC: %4d, пик кучи: %9s, время: %v\n",
+			c.name, cycles, formatBytes(peak), elapsed.Round(time.Millisecond))
+	}
+	
+	// GOGC=off без GOMEMLIMIT - куча растет, пока хватает памяти.
+	// Лимит мягкий: если живых данных больше лимита, GC будет работать
+	// почти непрерывно (death spiral), поэтому лимит ставят с запасом.
+}
+
+func formatBytes(b uint64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fGiB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.1fMiB", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.1fKiB", float64(b)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", b)
+	}
+}
+
+func main() {
+	watch := flag.Duration("watch", 0, "печатать метрики с заданным интервалом (например, 1s)")
+	flag.Parse()
+	
+	fmt.Printf("GOGC=%q GOMEMLIMIT=%q\n\n", os.Getenv("GOGC"), os.Getenv("GOMEMLIMIT"))
+	
+	if *watch > 0 {
+		watchMetrics(*watch)
+		return
+	}
+	
+	memStats()
+	runtimeMetrics()
+	gcPauses()
+	liveScheduler()
+	gcTuning()
+}
+
+// watchMetrics выводит метрики в реальном времени под нагрузкой
+func watchMetrics(interval time.Duration) {
+	go func() {
+		for {
+			allocate(16 << 20)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/sched/goroutines:goroutines"},
+	}
+	for range time.Tick(interval) {
+		metrics.Read(samples)
+		fmt.Printf("куча: %9s  циклов GC: %5d  горутин: %d\n",
+			formatBytes(samples[0].Value.Uint64()), samples[1].Value.Uint64(), samples[2].Value.Uint64())
+	}
+}
\ No newline at end of file
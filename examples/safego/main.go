@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Пример 1: Паника в фоновой горутине не роняет процесс
+func recoverExample() {
+	fmt.Println("=== Паника без перезапуска ===")
+	
+	done := Go(context.Background(), "report", func(ctx context.Context) {
+		var totals map[string]int
+		totals["users"]++ // assignment to entry in nil map
+	})
+	<-done
+	fmt.Println("Процесс жив, паника в логе вместе со стеком")
+}
+
+// Пример 2: Перезапуск с растущей паузой
+func restartExample() {
+	fmt.Println("\n=== Перезапуск после паники ===")
+	
+	// Обработчик падает на первых трех задачах, затем работает
+	var attempt atomic.Int32
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := Go(ctx, "queue-worker", func(ctx context.Context) {
+		n := attempt.Add(1)
+		fmt.Printf("Запуск %d\n", n)
+		if n <= 3 {
+			panic(fmt.Sprintf("битая задача %d", n))
+		}
+		fmt.Println("Очередь обработана")
+	}, WithRestart(100*time.Millisecond, time.Second), WithLogger(log.New(os.Stdout, "", 0)))
+	<-done
+}
+
+// Пример 3: Счетчик паник для мониторинга
+func metricExample() {
+	fmt.Println("\n=== Счетчик паник ===")
+	
+	// Тот же объект отдает /debug/vars, если импортирован expvar и
+	// запущен HTTP-сервер; рост счетчика - повод для алерта
+	fmt.Println("safego_panics:", expvar.Get("safego_panics"))
+}
+
+func main() {
+	recoverExample()
+	restartExample()
+	metricExample()
+	fmt.Println("\nТесты: go test -race ./...")
+}
\ No newline at end of file
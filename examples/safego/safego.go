@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// Паника в горутине без recover роняет весь процесс: defer в main ее не
+// видит, а recover работает только в той горутине, где случилась паника.
+// Go - обертка для фоновых горутин, которые должны пережить сбой:
+// очистка кэша, обработчик очереди, периодический отчет.
+//
+// Это не замена обработке ошибок. Паника означает баг, и состояние после
+// нее может быть испорчено, поэтому для критичного кода честнее упасть и
+// перезапуститься целиком (см. examples/crash, где safeGo пишет отчет и
+// завершает процесс). Go уместна там, где сбой одной итерации не
+// портит общие данные, а остановка всего сервиса дороже пропущенной
+// работы.
+
+// Panics число паник по именам горутин; видно в /debug/vars как
+// "safego_panics" (см. examples/expvar)
+var Panics = expvar.NewMap("safego_panics")
+
+// Option настройка Go
+type Option func(*options)
+
+type options struct {
+	restart    bool
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	logger     *log.Logger
+}
+
+// WithRestart перезапускает горутину после паники. Пауза перед
+// перезапуском растет вдвое от min до max, чтобы горутина, падающая
+// сразу при старте, не крутилась в цикле и не забивала лог. Если
+// горутина проработала дольше max, сбой считается разовым и пауза
+// снова начинается с min.
+func WithRestart(min, max time.Duration) Option {
+	return func(o *options) {
+		o.restart = true
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+// WithLogger пишет сообщения о панике в l вместо log.Default()
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) { o.logger = l }
+}
+
+// Go запускает fn в отдельной горутине с именем name. Паника в fn
+// перехватывается, попадает в лог вместе со стеком и в счетчик Panics.
+// Обычное завершение fn не перезапускается: если работа закончилась,
+// повторять ее незачем.
+//
+// Возвращаемый канал закрывается, когда горутина завершилась
+// окончательно: fn вернулась, упала без WithRestart или отменен ctx.
+func Go(ctx context.Context, name string, fn func(ctx context.Context), opts ...Option) <-chan struct{} {
+	o := options{logger: log.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		backoff := o.minBackoff
+		for {
+			start := time.Now()
+			if !runSafe(ctx, name, fn, o.logger) || !o.restart || ctx.Err() != nil {
+				return
+			}
+			if time.Since(start) > o.maxBackoff {
+				backoff = o.minBackoff
+			}
+			o.logger.Printf("safego: %s: перезапуск через %v", name, backoff)
+			
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			backoff = min(2*backoff, o.maxBackoff)
+		}
+	}()
+	return done
+}
+
+// runSafe вызывает fn и сообщает, была ли паника
+func runSafe(ctx context.Context, name string, fn func(ctx context.Context), logger *log.Logger) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			Panics.Add(name, 1)
+			logger.Printf("safego: паника в горутине %s: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+	fn(ctx)
+	return false
+}
\ No newline at end of file
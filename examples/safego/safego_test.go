@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// syncBuffer буфер для лога, в который пишет другая горутина
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+var discard = WithLogger(log.New(io.Discard, "", 0))
+
+func waitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Goroutine did not finish")
+	}
+}
+
+func panicCount(name string) int64 {
+	if v := Panics.Get(name); v != nil {
+		return v.(interface{ Value() int64 }).Value()
+	}
+	return 0
+}
+
+func TestGoRecoversPanic(t *testing.T) {
+	var logBuf syncBuffer
+	done := Go(context.Background(), "test-recover", func(ctx context.Context) {
+		panic("boom")
+	}, WithLogger(log.New(&logBuf, "", 0)))
+	waitDone(t, done)
+	
+	out := logBuf.String()
+	for _, want := range []string{"test-recover", "boom", "safego_test.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log to contain %q, got %q", want, out)
+		}
+	}
+	if n := panicCount("test-recover"); n != 1 {
+		t.Errorf("Expected 1 panic, got %d", n)
+	}
+}
+
+func TestGoRestart(t *testing.T) {
+	tests := []struct {
+		name      string
+		panics    int32 // Сколько первых запусков падают
+		restart   bool
+		wantCalls int32
+	}{
+		{"no panic", 0, true, 1},
+		{"panic without restart", 5, false, 1},
+		{"restart until success", 3, true, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			opts := []Option{discard}
+			if tt.restart {
+				opts = append(opts, WithRestart(time.Millisecond, 10*time.Millisecond))
+			}
+			done := Go(context.Background(), "test-restart", func(ctx context.Context) {
+				if calls.Add(1) <= tt.panics {
+					panic("boom")
+				}
+			}, opts...)
+			waitDone(t, done)
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestGoBackoff(t *testing.T) {
+	var logBuf syncBuffer
+	var calls atomic.Int32
+	done := Go(context.Background(), "test-backoff", func(ctx context.Context) {
+		if calls.Add(1) <= 4 {
+			panic("boom")
+		}
+	}, WithRestart(time.Millisecond, 3*time.Millisecond), WithLogger(log.New(&logBuf, "", 0)))
+	waitDone(t, done)
+	
+	var delays []string
+	for _, line := range strings.Split(logBuf.String(), "\n") {
+		if _, d, ok := strings.Cut(line, "перезапуск через "); ok {
+			delays = append(delays, d)
+		}
+	}
+	want := []string{"1ms", "2ms", "3ms", "3ms"}
+	if strings.Join(delays, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected delays %v, got %v", want, delays)
+	}
+}
+
+func TestGoCancelDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	done := Go(ctx, "test-cancel", func(ctx context.Context) {
+		calls.Add(1)
+		panic("boom")
+	}, WithRestart(time.Hour, time.Hour), discard)
+	
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	waitDone(t, done)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 call, got %d", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Сага (process manager): заказ проходит через три сервиса - склад,
+// платежи и доставку. Общей транзакции у них нет, поэтому каждый шаг
+// фиксируется сразу, а при сбое уже сделанное отменяется компенсирующими
+// действиями в обратном порядке. Состояние саги лежит в SQLite и
+// переживает перезапуск процесса.
+//
+//   saga.go  - оркестратор, хранилище состояния, таймауты и откаты
+//   order.go - фейковые сервисы и шаги саги заказа
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск: go run .
+// С сохранением между запусками: go run . -db saga.db
+
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// services набор фейковых сервисов для одного примера
+type services struct {
+	inv  *Inventory
+	pay  *Payments
+	ship *Shipping
+}
+
+func newServices() services {
+	return services{
+		inv:  NewInventory(map[string]int{"book": 5}),
+		pay:  NewPayments(10000_00),
+		ship: NewShipping(),
+	}
+}
+
+func (s services) report(o Order) {
+	fmt.Printf("  склад: %d шт., списано: %d коп., отправлен: %v\n",
+		s.inv.Stock(o.SKU), s.pay.Charged(o.ID), s.ship.Shipped(o.ID))
+}
+
+// runID делает ID заказов уникальными между запусками с -db
+var runID = time.Now().Format("150405")
+
+func printSaga(inst *Instance) {
+	fmt.Printf("  сага %s: %s, шаг %d", inst.ID, inst.Status, inst.Step)
+	if inst.Error != "" {
+		fmt.Printf(", ошибка: %s", inst.Error)
+	}
+	fmt.Println()
+}
+
+// Пример 1: Успешный заказ и откаты при разных сбоях
+func scenarios(store *Store) {
+	fmt.Println("=== Сага заказа ===")
+	ctx := context.Background()
+	
+	cases := []struct {
+		name  string
+		order Order
+		setup func(s services, saga *Orchestrator[Order])
+	}{
+		{"Успешный заказ", Order{SKU: "book", Qty: 2, Amount: 1500_00, Address: "Москва"}, nil},
+		{"Нет товара", Order{SKU: "book", Qty: 10, Amount: 1500_00, Address: "Москва"}, nil},
+		{"Платеж отклонен", Order{SKU: "book", Qty: 1, Amount: 50000_00, Address: "Москва"}, nil},
+		{"Доставка недоступна", Order{SKU: "book", Qty: 1, Amount: 700_00, Address: "Москва"},
+			func(s services, _ *Orchestrator[Order]) { s.ship.Down = true }},
+		{"Таймаут оплаты", Order{SKU: "book", Qty: 1, Amount: 700_00, Address: "Москва"},
+			func(s services, saga *Orchestrator[Order]) {
+				// Платеж проходит, но ответ не успевает - откатываем и его
+				s.pay.Slow(1, 200*time.Millisecond)
+				saga.Steps[1].Timeout = 50 * time.Millisecond
+			}},
+	}
+	
+	for i, c := range cases {
+		s := newServices()
+		saga := NewOrderSaga(store, s.inv, s.pay, s.ship)
+		if c.setup != nil {
+			c.setup(s, saga)
+		}
+		c.order.ID = fmt.Sprintf("order-%s-%d", runID, i+1)
+		
+		fmt.Printf("\n%s:\n", c.name)
+		inst, err := saga.Start(ctx, c.order.ID, c.order)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printSaga(inst)
+		s.report(c.order)
+	}
+}
+
+// Пример 2: Процесс упал посреди шага и продолжил после перезапуска
+func restart(store *Store) {
+	fmt.Println("\n=== Перезапуск оркестратора ===")
+	s := newServices()
+	s.pay.Slow(1, 300*time.Millisecond)
+	order := Order{ID: "order-restart-" + runID, SKU: "book", Qty: 1, Amount: 900_00, Address: "Казань"}
+	
+	// Процесс "падает" во время оплаты: деньги уже списаны,
+	// но шаг в БД не отмечен выполненным
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	inst, err := NewOrderSaga(store, s.inv, s.pay, s.ship).Start(ctx, order.ID, order)
+	fmt.Printf("Остановка: %v\n", err)
+	printSaga(inst)
+	
+	// Новый процесс при старте продолжает незавершенные саги. Шаг оплаты
+	// выполнится повторно, но платеж идемпотентен по ID заказа.
+	count, err := NewOrderSaga(store, s.inv, s.pay, s.ship).ResumeAll(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Продолжено саг: %d\n", count)
+	inst, _ = store.Get(context.Background(), order.ID)
+	printSaga(inst)
+	s.report(order)
+	fmt.Printf("  обращений к платежам: %d\n", s.pay.Calls())
+}
+
+// Пример 3: Откат не удался - сагу разбирает человек
+func stuck(store *Store) {
+	fmt.Println("\n=== Неудачная компенсация ===")
+	s := newServices()
+	s.ship.Down = true
+	saga := NewOrderSaga(store, s.inv, s.pay, s.ship)
+	saga.Backoff = 10 * time.Millisecond
+	
+	// Склад отказывает на возврате резерва
+	saga.Steps[0].Compensate = func(ctx context.Context, o *Order) error { return ErrUnavailable }
+	
+	order := Order{ID: "order-stuck-" + runID, SKU: "book", Qty: 1, Amount: 500_00, Address: "Омск"}
+	inst, err := saga.Start(context.Background(), order.ID, order)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printSaga(inst)
+	s.report(order)
+}
+
+func main() {
+	dbPath := flag.String("db", "", "Файл БД (по умолчанию временный)")
+	flag.Parse()
+	
+	if *dbPath == "" {
+		dir, err := os.MkdirTemp("", "saga")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		*dbPath = filepath.Join(dir, "saga.db")
+	}
+	db, err := openDB(*dbPath)
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	store := NewStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		log.Fatal("Ошибка миграции:", err)
+	}
+	
+	scenarios(store)
+	restart(store)
+	stuck(store)
+}
\ No newline at end of file
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Ошибки фейковых сервисов
+var (
+	ErrUnavailable = errors.New("service unavailable")
+	ErrOutOfStock  = errors.New("out of stock")
+	ErrDeclined    = errors.New("payment declined")
+)
+
+// Order данные саги заказа. Reserved, Payment и Tracking заполняют шаги:
+// они нужны компенсациям и сохраняются вместе с состоянием саги.
+type Order struct {
+	ID       string `json:"id"`
+	SKU      string `json:"sku"`
+	Qty      int    `json:"qty"`
+	Amount   int64  `json:"amount"` // В копейках
+	Address  string `json:"address"`
+	Reserved bool   `json:"reserved"`
+	Payment  string `json:"payment,omitempty"`
+	Tracking string `json:"tracking,omitempty"`
+}
+
+// remote имитирует сетевой вызов: сервис может лежать, а ответ -
+// задерживаться. Задержка идет после действия: клиент может не
+// дождаться ответа, хотя действие уже выполнено.
+type remote struct {
+	mu      sync.Mutex
+	Down    bool
+	slow    int
+	latency time.Duration
+	calls   int
+}
+
+// Slow задерживает ответы на следующие n вызовов
+func (r *remote) Slow(n int, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.slow, r.latency = n, latency
+}
+
+func (r *remote) call(ctx context.Context, action func() error) error {
+	r.mu.Lock()
+	r.calls++
+	if r.Down {
+		r.mu.Unlock()
+		return ErrUnavailable
+	}
+	err := action()
+	var latency time.Duration
+	if r.slow > 0 {
+		r.slow--
+		latency = r.latency
+	}
+	r.mu.Unlock()
+	
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(latency):
+		return err
+	}
+}
+
+// Calls число обращений к сервису
+func (r *remote) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+// Inventory склад. Резерв привязан к заказу, поэтому повторный
+// Reserve того же заказа не списывает товар второй раз.
+type Inventory struct {
+	remote
+	stock    map[string]int
+	reserved map[string]int // orderID -> количество
+}
+
+// NewInventory создает склад с остатками
+func NewInventory(stock map[string]int) *Inventory {
+	return &Inventory{stock: stock, reserved: make(map[string]int)}
+}
+
+// Reserve резервирует товар под заказ
+func (s *Inventory) Reserve(ctx context.Context, orderID, sku string, qty int) error {
+	return s.call(ctx, func() error {
+		if _, ok := s.reserved[orderID]; ok {
+			return nil
+		}
+		if s.stock[sku] < qty {
+			return ErrOutOfStock
+		}
+		s.stock[sku] -= qty
+		s.reserved[orderID] = qty
+		return nil
+	})
+}
+
+// Release возвращает резерв на склад. Резерва нет - нечего возвращать.
+func (s *Inventory) Release(ctx context.Context, orderID, sku string) error {
+	return s.call(ctx, func() error {
+		s.stock[sku] += s.reserved[orderID]
+		delete(s.reserved, orderID)
+		return nil
+	})
+}
+
+// Stock остаток товара
+func (s *Inventory) Stock(sku string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stock[sku]
+}
+
+// Payments платежный сервис; Limit - максимальная сумма платежа
+type Payments struct {
+	remote
+	Limit   int64
+	charged map[string]int64 // orderID -> сумма
+}
+
+// NewPayments создает платежный сервис
+func NewPayments(limit int64) *Payments {
+	return &Payments{Limit: limit, charged: make(map[string]int64)}
+}
+
+// Charge списывает деньги за заказ и возвращает ID платежа
+func (p *Payments) Charge(ctx context.Context, orderID string, amount int64) (string, error) {
+	err := p.call(ctx, func() error {
+		if _, ok := p.charged[orderID]; ok {
+			return nil
+		}
+		if amount > p.Limit {
+			return ErrDeclined
+		}
+		p.charged[orderID] = amount
+		return nil
+	})
+	return "pay-" + orderID, err
+}
+
+// Refund возвращает деньги за заказ
+func (p *Payments) Refund(ctx context.Context, orderID string) error {
+	return p.call(ctx, func() error {
+		delete(p.charged, orderID)
+		return nil
+	})
+}
+
+// Charged сумма, списанная за заказ
+func (p *Payments) Charged(orderID string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.charged[orderID]
+}
+
+// Shipping служба доставки
+type Shipping struct {
+	remote
+	shipments map[string]string // orderID -> адрес
+}
+
+// NewShipping создает службу доставки
+func NewShipping() *Shipping {
+	return &Shipping{shipments: make(map[string]string)}
+}
+
+// Ship создает отправку и возвращает трек-номер
+func (s *Shipping) Ship(ctx context.Context, orderID, address string) (string, error) {
+	err := s.call(ctx, func() error {
+		if address == "" {
+			return errors.New("address is required")
+		}
+		s.shipments[orderID] = address
+		return nil
+	})
+	return "track-" + orderID, err
+}
+
+// Cancel отменяет отправку
+func (s *Shipping) Cancel(ctx context.Context, orderID string) error {
+	return s.call(ctx, func() error {
+		delete(s.shipments, orderID)
+		return nil
+	})
+}
+
+// Shipped отправлен ли заказ
+func (s *Shipping) Shipped(orderID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.shipments[orderID]
+	return ok
+}
+
+// SagaOrder тип саги в таблице sagas
+const SagaOrder = "order"
+
+// NewOrderSaga описывает процесс заказа: резерв товара -> оплата ->
+// доставка. ID заказа служит ключом идемпотентности во всех сервисах.
+func NewOrderSaga(store *Store, inv *Inventory, pay *Payments, ship *Shipping) *Orchestrator[Order] {
+	return NewOrchestrator(store, SagaOrder,
+		Step[Order]{
+			Name:    "reserve",
+			Timeout: 2 * time.Second,
+			Action: func(ctx context.Context, o *Order) error {
+				if err := inv.Reserve(ctx, o.ID, o.SKU, o.Qty); err != nil {
+					return err
+				}
+				o.Reserved = true
+				return nil
+			},
+			Compensate: func(ctx context.Context, o *Order) error {
+				if err := inv.Release(ctx, o.ID, o.SKU); err != nil {
+					return err
+				}
+				o.Reserved = false
+				return nil
+			},
+		},
+		Step[Order]{
+			Name:    "charge",
+			Timeout: 3 * time.Second,
+			Action: func(ctx context.Context, o *Order) error {
+				id, err := pay.Charge(ctx, o.ID, o.Amount)
+				if err != nil {
+					return err
+				}
+				o.Payment = id
+				return nil
+			},
+			Compensate: func(ctx context.Context, o *Order) error {
+				if err := pay.Refund(ctx, o.ID); err != nil {
+					return err
+				}
+				o.Payment = ""
+				return nil
+			},
+		},
+		Step[Order]{
+			Name:    "ship",
+			Timeout: 5 * time.Second,
+			Action: func(ctx context.Context, o *Order) error {
+				tracking, err := ship.Ship(ctx, o.ID, o.Address)
+				if err != nil {
+					return err
+				}
+				o.Tracking = tracking
+				return nil
+			},
+			Compensate: func(ctx context.Context, o *Order) error {
+				if err := ship.Cancel(ctx, o.ID); err != nil {
+					return err
+				}
+				o.Tracking = ""
+				return nil
+			},
+		},
+	)
+}
\ No newline at end of file
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Статусы саги
+const (
+	SagaRunning      = "running"
+	SagaCompensating = "compensating"
+	SagaCompleted    = "completed"
+	SagaCompensated  = "compensated" // Сага не удалась, но все откачено
+	SagaFailed       = "failed"      // Откат не удался: нужен человек
+)
+
+// ErrSagaNotFound сага с таким ID не сохранена
+var ErrSagaNotFound = errors.New("saga not found")
+
+// Step шаг саги: действие и компенсирующее действие, которое отменяет
+// его результат. Обе функции должны быть идемпотентными: после падения
+// процесса шаг может выполниться повторно.
+type Step[T any] struct {
+	Name       string
+	Timeout    time.Duration
+	Action     func(ctx context.Context, data *T) error
+	Compensate func(ctx context.Context, data *T) error // nil - откатывать нечего
+}
+
+// Instance сохраненное состояние саги. Step - индекс следующего шага
+// при выполнении и число шагов, которые еще нужно откатить, при
+// компенсации.
+type Instance struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Status    string          `json:"status"`
+	Step      int             `json:"step"`
+	Data      json.RawMessage `json:"data"`
+	Error     string          `json:"error,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Store хранит состояние саг в SQLite
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewStore создает хранилище
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, now: time.Now}
+}
+
+// Migrate создает таблицу sagas
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS sagas (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		status TEXT NOT NULL,
+		step INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_sagas_status ON sagas(type, status);`)
+	return err
+}
+
+// Create сохраняет новую сагу в статусе running
+func (s *Store) Create(ctx context.Context, inst *Instance) error {
+	inst.Status, inst.Step, inst.UpdatedAt = SagaRunning, 0, s.now()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO sagas (id, type, status, step, data, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		inst.ID, inst.Type, inst.Status, inst.Step, string(inst.Data), inst.UpdatedAt.UnixMilli())
+	return err
+}
+
+// Save записывает состояние после очередного шага
+func (s *Store) Save(ctx context.Context, inst *Instance) error {
+	inst.UpdatedAt = s.now()
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE sagas SET status = ?, step = ?, data = ?, error = ?, updated_at = ? WHERE id = ?`,
+		inst.Status, inst.Step, string(inst.Data), inst.Error, inst.UpdatedAt.UnixMilli(), inst.ID)
+	return err
+}
+
+// Get возвращает сагу по ID
+func (s *Store) Get(ctx context.Context, id string) (*Instance, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, type, status, step, data, error, updated_at FROM sagas WHERE id = ?`, id)
+	inst, err := scanInstance(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSagaNotFound
+	}
+	return inst, err
+}
+
+// Unfinished возвращает ID саг типа typ, которые прервались на полпути
+func (s *Store) Unfinished(ctx context.Context, typ string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id FROM sagas WHERE type = ? AND status IN (?, ?) ORDER BY updated_at`,
+		typ, SagaRunning, SagaCompensating)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInstance(s scanner) (*Instance, error) {
+	var inst Instance
+	var data string
+	var updatedAt int64
+	if err := s.Scan(&inst.ID, &inst.Type, &inst.Status, &inst.Step, &data, &inst.Error, &updatedAt); err != nil {
+		return nil, err
+	}
+	inst.Data = json.RawMessage(data)
+	inst.UpdatedAt = time.UnixMilli(updatedAt)
+	return &inst, nil
+}
+
+// Orchestrator выполняет шаги саги по порядку и сохраняет состояние
+// после каждого. Если шаг не удался, уже выполненные шаги откатываются
+// в обратном порядке. Рассчитан на один процесс-оркестратор: две копии,
+// продолжающие одну сагу, выполнили бы шаги дважды.
+type Orchestrator[T any] struct {
+	Type  string
+	Steps []Step[T]
+	store *Store
+
+	// CompensationAttempts сколько раз пытаться откатить шаг, прежде
+	// чем сдаться и перевести сагу в failed
+	CompensationAttempts int
+	Backoff              time.Duration
+}
+
+// NewOrchestrator создает оркестратор саг типа typ
+func NewOrchestrator[T any](store *Store, typ string, steps ...Step[T]) *Orchestrator[T] {
+	return &Orchestrator[T]{
+		Type:                 typ,
+		Steps:                steps,
+		store:                store,
+		CompensationAttempts: 3,
+		Backoff:              100 * time.Millisecond,
+	}
+}
+
+// Start сохраняет новую сагу и выполняет ее
+func (o *Orchestrator[T]) Start(ctx context.Context, id string, data T) (*Instance, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	inst := &Instance{ID: id, Type: o.Type, Data: raw}
+	if err := o.store.Create(ctx, inst); err != nil {
+		return nil, fmt.Errorf("create saga: %w", err)
+	}
+	return o.Resume(ctx, id)
+}
+
+// ResumeAll продолжает саги, прерванные остановкой процесса.
+// Вызывается при старте оркестратора.
+func (o *Orchestrator[T]) ResumeAll(ctx context.Context) (int, error) {
+	ids, err := o.store.Unfinished(ctx, o.Type)
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if _, err := o.Resume(ctx, id); err != nil {
+			return i, fmt.Errorf("resume saga %s: %w", id, err)
+		}
+	}
+	return len(ids), nil
+}
+
+// Resume продолжает сагу с сохраненного шага. Если ctx отменен посреди
+// шага, состояние не меняется: после перезапуска шаг выполнится заново.
+func (o *Orchestrator[T]) Resume(ctx context.Context, id string) (*Instance, error) {
+	inst, err := o.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var data T
+	if err := json.Unmarshal(inst.Data, &data); err != nil {
+		return nil, fmt.Errorf("decode saga data: %w", err)
+	}
+	
+	for inst.Status == SagaRunning {
+		step := o.Steps[inst.Step]
+		err := runStep(ctx, step.Timeout, step.Action, &data)
+		if ctx.Err() != nil {
+			return inst, ctx.Err()
+		}
+		if err != nil {
+			log.Printf("Сага %s: шаг %s не удался: %v", inst.ID, step.Name, err)
+			inst.Status, inst.Error = SagaCompensating, fmt.Sprintf("%s: %v", step.Name, err)
+			// Отказ сервиса значит, что шаг ничего не сделал. Таймаут - нет:
+			// неизвестно, успел ли сервис выполнить действие, поэтому
+			// откатываем и сам упавший шаг.
+			if errors.Is(err, context.DeadlineExceeded) {
+				inst.Step++
+			}
+		} else if inst.Step++; inst.Step == len(o.Steps) {
+			inst.Status = SagaCompleted
+		}
+		if err := o.save(ctx, inst, &data); err != nil {
+			return inst, err
+		}
+	}
+	
+	for inst.Status == SagaCompensating {
+		if inst.Step == 0 {
+			inst.Status = SagaCompensated
+		} else {
+			step := o.Steps[inst.Step-1]
+			if err := o.compensate(ctx, step, &data); err != nil {
+				if ctx.Err() != nil {
+					return inst, ctx.Err()
+				}
+				log.Printf("Сага %s: откат шага %s не удался: %v", inst.ID, step.Name, err)
+				inst.Status = SagaFailed
+				inst.Error += fmt.Sprintf("; compensate %s: %v", step.Name, err)
+			} else {
+				inst.Step--
+			}
+		}
+		if err := o.save(ctx, inst, &data); err != nil {
+			return inst, err
+		}
+	}
+	return inst, nil
+}
+
+// compensate повторяет откат шага: в отличие от действия, сдаваться
+// после первой ошибки нельзя - иначе деньги останутся списанными
+func (o *Orchestrator[T]) compensate(ctx context.Context, step Step[T], data *T) error {
+	if step.Compensate == nil {
+		return nil
+	}
+	var err error
+	for attempt := 1; attempt <= o.CompensationAttempts; attempt++ {
+		if err = runStep(ctx, step.Timeout, step.Compensate, data); err == nil {
+			return nil
+		}
+		if attempt < o.CompensationAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(o.Backoff * time.Duration(attempt)):
+			}
+		}
+	}
+	return err
+}
+
+func (o *Orchestrator[T]) save(ctx context.Context, inst *Instance, data *T) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	inst.Data = raw
+	return o.store.Save(ctx, inst)
+}
+
+// runStep выполняет действие с таймаутом шага
+func runStep[T any](ctx context.Context, timeout time.Duration, fn func(context.Context, *T) error, data *T) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx, data)
+}
\ No newline at end of file
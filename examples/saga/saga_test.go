@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "saga.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	store := NewStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return store
+}
+
+func newTestSaga(t *testing.T) (*Orchestrator[Order], services) {
+	s := newServices()
+	saga := NewOrderSaga(newTestStore(t), s.inv, s.pay, s.ship)
+	saga.Backoff = time.Millisecond
+	return saga, s
+}
+
+func TestOrderSaga(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   Order
+		setup   func(s services, saga *Orchestrator[Order])
+		status  string
+		stock   int
+		charged int64
+		shipped bool
+	}{
+		{
+			name:    "completed",
+			order:   Order{ID: "1", SKU: "book", Qty: 2, Amount: 100, Address: "Москва"},
+			status:  SagaCompleted,
+			stock:   3,
+			charged: 100,
+			shipped: true,
+		},
+		{
+			name:   "out of stock",
+			order:  Order{ID: "1", SKU: "book", Qty: 10, Amount: 100, Address: "Москва"},
+			status: SagaCompensated,
+			stock:  5,
+		},
+		{
+			name:   "payment declined releases stock",
+			order:  Order{ID: "1", SKU: "book", Qty: 1, Amount: 1 << 40, Address: "Москва"},
+			status: SagaCompensated,
+			stock:  5,
+		},
+		{
+			name:   "shipping down refunds and releases",
+			order:  Order{ID: "1", SKU: "book", Qty: 1, Amount: 100, Address: "Москва"},
+			setup:  func(s services, _ *Orchestrator[Order]) { s.ship.Down = true },
+			status: SagaCompensated,
+			stock:  5,
+		},
+		{
+			name:  "payment timeout refunds charge",
+			order: Order{ID: "1", SKU: "book", Qty: 1, Amount: 100, Address: "Москва"},
+			setup: func(s services, saga *Orchestrator[Order]) {
+				s.pay.Slow(1, 100*time.Millisecond)
+				saga.Steps[1].Timeout = 10 * time.Millisecond
+			},
+			status: SagaCompensated,
+			stock:  5,
+		},
+		{
+			name:  "failed compensation",
+			order: Order{ID: "1", SKU: "book", Qty: 1, Amount: 100, Address: "Москва"},
+			setup: func(s services, saga *Orchestrator[Order]) {
+				s.ship.Down = true
+				saga.Steps[0].Compensate = func(context.Context, *Order) error { return ErrUnavailable }
+			},
+			status: SagaFailed,
+			stock:  4,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saga, s := newTestSaga(t)
+			if tt.setup != nil {
+				tt.setup(s, saga)
+			}
+			
+			inst, err := saga.Start(context.Background(), tt.order.ID, tt.order)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if inst.Status != tt.status {
+				t.Errorf("Expected status %s, got %s (%s)", tt.status, inst.Status, inst.Error)
+			}
+			if got := s.inv.Stock("book"); got != tt.stock {
+				t.Errorf("Expected stock %d, got %d", tt.stock, got)
+			}
+			if got := s.pay.Charged(tt.order.ID); got != tt.charged {
+				t.Errorf("Expected charged %d, got %d", tt.charged, got)
+			}
+			if got := s.ship.Shipped(tt.order.ID); got != tt.shipped {
+				t.Errorf("Expected shipped %v, got %v", tt.shipped, got)
+			}
+			
+			saved, err := saga.store.Get(context.Background(), tt.order.ID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if saved.Status != inst.Status || saved.Step != inst.Step {
+				t.Errorf("Expected saved state %s/%d, got %s/%d", inst.Status, inst.Step, saved.Status, saved.Step)
+			}
+		})
+	}
+}
+
+func TestCompensationRetries(t *testing.T) {
+	saga, s := newTestSaga(t)
+	s.ship.Down = true
+	
+	calls := 0
+	release := saga.Steps[0].Compensate
+	saga.Steps[0].Compensate = func(ctx context.Context, o *Order) error {
+		if calls++; calls < saga.CompensationAttempts {
+			return ErrUnavailable
+		}
+		return release(ctx, o)
+	}
+	
+	inst, err := saga.Start(context.Background(), "1", Order{ID: "1", SKU: "book", Qty: 1, Amount: 100, Address: "Москва"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inst.Status != SagaCompensated {
+		t.Errorf("Expected status %s, got %s", SagaCompensated, inst.Status)
+	}
+	if calls != saga.CompensationAttempts {
+		t.Errorf("Expected %d attempts, got %d", saga.CompensationAttempts, calls)
+	}
+}
+
+func TestResumeAfterRestart(t *testing.T) {
+	saga, s := newTestSaga(t)
+	s.pay.Slow(1, time.Second)
+	order := Order{ID: "1", SKU: "book", Qty: 1, Amount: 100, Address: "Москва"}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	inst, err := saga.Start(ctx, order.ID, order)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline, got %v", err)
+	}
+	if inst.Status != SagaRunning || inst.Step != 1 {
+		t.Fatalf("Expected running at step 1, got %s at %d", inst.Status, inst.Step)
+	}
+	
+	restarted := NewOrderSaga(saga.store, s.inv, s.pay, s.ship)
+	count, err := restarted.ResumeAll(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 resumed saga, got %d", count)
+	}
+	
+	inst, _ = saga.store.Get(context.Background(), order.ID)
+	if inst.Status != SagaCompleted {
+		t.Errorf("Expected status %s, got %s", SagaCompleted, inst.Status)
+	}
+	// Оплата выполнилась дважды, но списание одно
+	if s.pay.Calls() != 2 || s.pay.Charged(order.ID) != 100 {
+		t.Errorf("Expected 2 idempotent charge calls, got %d calls and %d charged", s.pay.Calls(), s.pay.Charged(order.ID))
+	}
+	if s.inv.Stock("book") != 4 {
+		t.Errorf("Expected stock 4, got %d", s.inv.Stock("book"))
+	}
+	
+	if count, _ := restarted.ResumeAll(context.Background()); count != 0 {
+		t.Errorf("Expected nothing to resume, got %d", count)
+	}
+}
+
+func TestResumeCompensation(t *testing.T) {
+	saga, s := newTestSaga(t)
+	ctx := context.Background()
+	order := Order{ID: "1", SKU: "book", Qty: 1, Amount: 100, Address: "Москва"}
+	
+	// Процесс упал во время отката: резерв сделан, оплата уже откачена
+	s.inv.Reserve(ctx, order.ID, order.SKU, order.Qty)
+	inst := &Instance{ID: order.ID, Type: SagaOrder, Data: []byte(`{"id":"1","sku":"book","qty":1,"reserved":true}`)}
+	if err := saga.store.Create(ctx, inst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	inst.Status, inst.Step = SagaCompensating, 1
+	if err := saga.store.Save(ctx, inst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	count, err := saga.ResumeAll(ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("Expected 1 resumed saga, got %d, %v", count, err)
+	}
+	inst, _ = saga.store.Get(ctx, order.ID)
+	if inst.Status != SagaCompensated {
+		t.Errorf("Expected status %s, got %s", SagaCompensated, inst.Status)
+	}
+	if s.inv.Stock("book") != 5 {
+		t.Errorf("Expected stock restored to 5, got %d", s.inv.Stock("book"))
+	}
+}
+
+func TestResumeMissing(t *testing.T) {
+	saga, _ := newTestSaga(t)
+	if _, err := saga.Resume(context.Background(), "nope"); !errors.Is(err, ErrSagaNotFound) {
+		t.Errorf("Expected ErrSagaNotFound, got %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+// УЯЗВИМЫЕ обработчики. Они подключаются только с флагом -insecure-lab
+// и существуют, чтобы показать атаку. Не копируйте этот код.
+
+// insecureSearch - SQL-инъекция: ввод пользователя склеивается с запросом.
+// q = ' OR '1'='1 превращает условие в истинное для всех строк,
+// q = ' UNION SELECT id, email, password, role FROM users -- выгружает пароли.
+func insecureSearch(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("name")
+		query := "SELECT id, name, email, role FROM users WHERE name = '" + q + "'"
+		
+		rows, err := db.Query(query)
+		if err != nil {
+			// Вдобавок текст ошибки БД уходит клиенту и помогает атакующему
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer rows.Close()
+		
+		for rows.Next() {
+			var u User
+			if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", u.ID, u.Name, u.Email, u.Role)
+		}
+	}
+}
+
+// insecureLogin - обход аутентификации: email = admin@example.com' --
+// закомментирует проверку пароля
+func insecureLogin(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.FormValue("email")
+		password := r.FormValue("password")
+		
+		query := fmt.Sprintf("SELECT name, role FROM users WHERE email = '%s' AND password = '%s'", email, password)
+		var name, role string
+		if err := db.QueryRow(query).Scan(&name, &role); err != nil {
+			http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, "Вход выполнен: %s (%s)\n", name, role)
+	}
+}
+
+// text/template не знает об HTML и выводит данные как есть
+var insecureCommentsTmpl = template.Must(template.New("comments").Parse(`<html><body>
+<h1>Комментарии</h1>
+{{range .}}<div class="comment"><b>{{.Author}}</b>: {{.Text}}</div>
+{{end}}</body></html>`))
+
+// insecureComments - хранимый XSS: комментарий <script>...</script>
+// выполнится в браузере каждого, кто откроет страницу
+func insecureComments(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			db.Exec(`INSERT INTO comments (author, text) VALUES (?, ?)`, r.FormValue("author"), r.FormValue("text"))
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+		
+		comments, err := listComments(db)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		insecureCommentsTmpl.Execute(w, comments)
+	}
+}
+
+// insecureGreet - отраженный XSS: параметр из URL попадает в страницу.
+// Ссылку /insecure/greet?name=<script>...</script> достаточно прислать жертве.
+func insecureGreet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<p>Привет, %s!</p>", r.URL.Query().Get("name"))
+}
\ No newline at end of file
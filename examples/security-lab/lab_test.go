@@ -0,0 +1,169 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newLabServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	
+	db, err := openLabDB()
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	srv := httptest.NewServer(newMux(db, true))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, srv *httptest.Server, path string, query url.Values) (int, string) {
+	t.Helper()
+	
+	resp, err := http.Get(srv.URL + path + "?" + query.Encode())
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func post(t *testing.T, srv *httptest.Server, path string, form url.Values) (int, string) {
+	t.Helper()
+	
+	resp, err := http.PostForm(srv.URL+path, form)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestInsecureRoutesRequireFlag(t *testing.T) {
+	db, err := openLabDB()
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+	
+	srv := httptest.NewServer(newMux(db, false))
+	defer srv.Close()
+	
+	if code, _ := get(t, srv, "/insecure/search", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 without -insecure-lab, got %d", code)
+	}
+}
+
+func TestSQLInjectionTautology(t *testing.T) {
+	srv := newLabServer(t)
+	payload := url.Values{"name": {"' OR '1'='1"}}
+	
+	_, body := get(t, srv, "/insecure/search", payload)
+	if !strings.Contains(body, "admin@example.com") {
+		t.Errorf("Expected exploit to dump all users, got %q", body)
+	}
+	
+	_, body = get(t, srv, "/secure/search", payload)
+	if body != "" {
+		t.Errorf("Expected no rows from secure handler, got %q", body)
+	}
+}
+
+func TestSQLInjectionUnion(t *testing.T) {
+	srv := newLabServer(t)
+	payload := url.Values{"name": {"' UNION SELECT id, email, password, role FROM users --"}}
+	
+	_, body := get(t, srv, "/insecure/search", payload)
+	if !strings.Contains(body, "S3cr3t!") {
+		t.Errorf("Expected exploit to leak passwords, got %q", body)
+	}
+	
+	_, body = get(t, srv, "/secure/search", payload)
+	if strings.Contains(body, "S3cr3t!") {
+		t.Errorf("Secure handler leaked passwords: %q", body)
+	}
+}
+
+func TestSQLInjectionLoginBypass(t *testing.T) {
+	srv := newLabServer(t)
+	payload := url.Values{"email": {"admin@example.com' --"}, "password": {"wrong"}}
+	
+	code, body := post(t, srv, "/insecure/login", payload)
+	if code != http.StatusOK || !strings.Contains(body, "admin") {
+		t.Errorf("Expected login bypass, got %d %q", code, body)
+	}
+	
+	code, _ = post(t, srv, "/secure/login", payload)
+	if code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 from secure handler, got %d", code)
+	}
+	
+	// Легитимный вход работает в обеих версиях
+	code, _ = post(t, srv, "/secure/login", url.Values{"email": {"ivan@example.com"}, "password": {"ivan-pass"}})
+	if code != http.StatusOK {
+		t.Errorf("Expected 200 for valid credentials, got %d", code)
+	}
+}
+
+func TestSecureListIgnoresUnknownSort(t *testing.T) {
+	srv := newLabServer(t)
+	
+	code, body := get(t, srv, "/secure/list", url.Values{"sort": {"(SELECT password FROM users)"}})
+	if code != http.StatusOK || !strings.HasPrefix(body, "1\t") {
+		t.Errorf("Expected fallback to ORDER BY id, got %d %q", code, body)
+	}
+}
+
+const xssPayload = "<script>alert(document.cookie)</script>"
+
+func TestStoredXSS(t *testing.T) {
+	srv := newLabServer(t)
+	
+	// Обе версии пишут в одну таблицу - разница только в выводе
+	post(t, srv, "/insecure/comments", url.Values{"author": {"mallory"}, "text": {xssPayload}})
+	
+	_, body := get(t, srv, "/insecure/comments", nil)
+	if !strings.Contains(body, xssPayload) {
+		t.Errorf("Expected raw script in insecure page, got %q", body)
+	}
+	
+	resp, err := http.Get(srv.URL + "/secure/comments")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	
+	if strings.Contains(string(raw), "<script>") {
+		t.Errorf("Secure page contains unescaped script: %q", raw)
+	}
+	if !strings.Contains(string(raw), "&lt;script&gt;") {
+		t.Errorf("Expected escaped payload, got %q", raw)
+	}
+	if resp.Header.Get("Content-Security-Policy") == "" {
+		t.Error("Expected Content-Security-Policy header")
+	}
+}
+
+func TestReflectedXSS(t *testing.T) {
+	srv := newLabServer(t)
+	payload := url.Values{"name": {xssPayload}}
+	
+	_, body := get(t, srv, "/insecure/greet", payload)
+	if !strings.Contains(body, xssPayload) {
+		t.Errorf("Expected reflected script, got %q", body)
+	}
+	
+	_, body = get(t, srv, "/secure/greet", payload)
+	if strings.Contains(body, "<script>") {
+		t.Errorf("Secure greeting contains unescaped script: %q", body)
+	}
+}
\ No newline at end of file
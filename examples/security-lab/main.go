@@ -0,0 +1,66 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Лаборатория SQL-инъекций и XSS.
+//
+//   go run .                 - только исправленные обработчики на /secure/
+//   go run . -insecure-lab   - плюс уязвимые на /insecure/ (слушает только localhost)
+//
+// Попробуйте одни и те же запросы на обоих префиксах:
+//   curl "localhost:8080/insecure/search?name=' OR '1'='1"
+//   curl "localhost:8080/insecure/search?name=' UNION SELECT id, email, password, role FROM users --"
+//   curl -d "email=admin@example.com' --" -d "password=x" localhost:8080/insecure/login
+//   curl -d "author=x" -d "text=<script>alert(document.cookie)</script>" localhost:8080/insecure/comments
+//   открыть в браузере localhost:8080/insecure/greet?name=<script>alert(1)</script>
+//
+// go test . доказывает, что каждая атака срабатывает на /insecure/ и не срабатывает на /secure/.
+
+// newMux собирает маршруты; уязвимые подключаются только по явному флагу
+func newMux(db *sql.DB, insecureLab bool) *http.ServeMux {
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("/secure/search", secureSearch(db))
+	mux.HandleFunc("/secure/list", secureList(db))
+	mux.HandleFunc("/secure/login", secureLogin(db))
+	mux.HandleFunc("/secure/comments", secureComments(db))
+	mux.HandleFunc("/secure/greet", secureGreet)
+	
+	if insecureLab {
+		mux.HandleFunc("/insecure/search", insecureSearch(db))
+		mux.HandleFunc("/insecure/login", insecureLogin(db))
+		mux.HandleFunc("/insecure/comments", insecureComments(db))
+		mux.HandleFunc("/insecure/greet", insecureGreet)
+	}
+	
+	return mux
+}
+
+func main() {
+	insecureLab := flag.Bool("insecure-lab", false, "подключить намеренно уязвимые обработчики")
+	flag.Parse()
+	
+	db, err := openLabDB()
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	addr := ":8080"
+	if *insecureLab {
+		// Уязвимый сервер не должен быть доступен из сети
+		addr = "127.0.0.1:8080"
+		fmt.Println("ВНИМАНИЕ: подключены уязвимые обработчики /insecure/*")
+	}
+	
+	fmt.Println("Сервер запущен на", addr)
+	log.Fatal(http.ListenAndServe(addr, newMux(db, *insecureLab)))
+}
\ No newline at end of file
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Исправленные версии тех же обработчиков.
+
+// secureSearch - параметризованный запрос: значение передается драйверу
+// отдельно от текста SQL и никогда не интерпретируется как код
+func secureSearch(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("name")
+		
+		rows, err := db.Query("SELECT id, name, email, role FROM users WHERE name = ?", q)
+		if err != nil {
+			// Подробности - в лог, клиенту - общее сообщение
+			log.Printf("Ошибка поиска: %v", err)
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+		defer rows.Close()
+		
+		for rows.Next() {
+			var u User
+			if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role); err != nil {
+				log.Printf("Ошибка чтения: %v", err)
+				http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", u.ID, u.Name, u.Email, u.Role)
+		}
+	}
+}
+
+// orderColumns - имена колонок и направление сортировки нельзя передать
+// параметром (? подставляет только значения), поэтому их берут из белого списка
+var orderColumns = map[string]string{
+	"name":  "name",
+	"email": "email",
+	"id":    "id",
+}
+
+// secureList - сортировка по полю из запроса без инъекции в ORDER BY
+func secureList(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		column, ok := orderColumns[r.URL.Query().Get("sort")]
+		if !ok {
+			column = "id"
+		}
+		
+		rows, err := db.Query("SELECT id, name FROM users ORDER BY " + column)
+		if err != nil {
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+		defer rows.Close()
+		
+		for rows.Next() {
+			var id int
+			var name string
+			rows.Scan(&id, &name)
+			fmt.Fprintf(w, "%d\t%s\n", id, name)
+		}
+	}
+}
+
+// secureLogin - параметры вместо склейки. В реальном приложении пароль
+// хранится как хеш (см. examples/passwords), здесь - как в уязвимой версии,
+// чтобы отличалась только защита от инъекции.
+func secureLogin(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var name, role string
+		err := db.QueryRow("SELECT name, role FROM users WHERE email = ? AND password = ?",
+			r.FormValue("email"), r.FormValue("password")).Scan(&name, &role)
+		if err != nil {
+			http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, "Вход выполнен: %s (%s)\n", name, role)
+	}
+}
+
+// html/template экранирует данные с учетом контекста:
+// в тексте, атрибутах, URL и JavaScript - по-разному
+var secureCommentsTmpl = template.Must(template.New("comments").Parse(`<html><body>
+<h1>Комментарии</h1>
+{{range .}}<div class="comment"><b>{{.Author}}</b>: {{.Text}}</div>
+{{end}}</body></html>`))
+
+// secureComments - тот же шаблон, но через html/template и с CSP
+func secureComments(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			if _, err := db.Exec(`INSERT INTO comments (author, text) VALUES (?, ?)`,
+				r.FormValue("author"), r.FormValue("text")); err != nil {
+				http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+				return
+			}
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+		
+		comments, err := listComments(db)
+		if err != nil {
+			http.Error(w, "Внутренняя ошибка", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		// Второй рубеж: даже если экранирование где-то пропущено,
+		// браузер не выполнит встроенные скрипты
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'self'")
+		secureCommentsTmpl.Execute(w, comments)
+	}
+}
+
+// secureGreet - ручное экранирование, когда шаблон не используется
+func secureGreet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<p>Привет, %s!</p>", template.HTMLEscapeString(r.URL.Query().Get("name")))
+}
+
+func listComments(db *sql.DB) ([]Comment, error) {
+	rows, err := db.Query(`SELECT author, text FROM comments ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var comments []Comment
+	for rows.Next() {
+		var c Comment
+		if err := rows.Scan(&c.Author, &c.Text); err != nil {
+			return nil, err
+		}
+		c.Author = strings.TrimSpace(c.Author)
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// User пользователь лаборатории
+type User struct {
+	ID    int
+	Name  string
+	Email string
+	Role  string
+}
+
+// Comment комментарий, введенный пользователем
+type Comment struct {
+	Author string
+	Text   string
+}
+
+// openLabDB создает БД в памяти с тестовыми данными
+func openLabDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	// Для :memory: каждое соединение - отдельная БД
+	db.SetMaxOpenConns(1)
+	
+	query := `
+	CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL,
+		password TEXT NOT NULL,
+		role TEXT NOT NULL DEFAULT 'user'
+	);
+	CREATE TABLE comments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		author TEXT NOT NULL,
+		text TEXT NOT NULL
+	);
+	INSERT INTO users (name, email, password, role) VALUES
+		('Иван', 'ivan@example.com', 'ivan-pass', 'user'),
+		('Мария', 'maria@example.com', 'maria-pass', 'user'),
+		('admin', 'admin@example.com', 'S3cr3t!', 'admin');`
+	
+	if _, err := db.Exec(query); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("инициализация БД: %w", err)
+	}
+	return db, nil
+}
\ No newline at end of file
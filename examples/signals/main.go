@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Config настройки, которые можно перечитать по SIGHUP
+type Config struct {
+	Greeting string `json:"greeting"`
+	Delay    string `json:"delay"`
+}
+
+var current atomic.Pointer[Config]
+
+func loadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	current.Store(&cfg)
+	return nil
+}
+
+// Пример 1: Graceful shutdown через signal.NotifyContext
+func runServer(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		cfg := current.Load()
+		if d, err := time.ParseDuration(cfg.Delay); err == nil {
+			time.Sleep(d) // Имитация долгого запроса, который нужно дождаться
+		}
+		fmt.Fprintln(w, cfg.Greeting)
+	})
+	
+	server := &http.Server{Addr: addr, Handler: mux}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	log.Println("Получен сигнал, завершаем активные запросы (до 10с)...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// Пример 2: SIGHUP - перечитать конфигурацию без перезапуска
+func watchReload(ctx context.Context, path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := loadConfig(path); err != nil {
+				// Ошибка в новом файле не должна ронять сервер: оставляем старую версию
+				log.Printf("SIGHUP: конфигурация не перечитана: %v", err)
+				continue
+			}
+			log.Printf("SIGHUP: конфигурация перечитана: %+v", *current.Load())
+		}
+	}
+}
+
+// Пример 3: Двойной Ctrl+C - второй сигнал завершает процесс немедленно
+func forceQuitOnSecondSignal(ctx context.Context, stop context.CancelFunc) {
+	<-ctx.Done()
+	// После stop() NotifyContext больше не перехватывает сигналы,
+	// и повторный SIGINT обрабатывается по умолчанию - процесс завершается.
+	stop()
+	log.Println("Нажмите Ctrl+C еще раз для немедленного выхода")
+}
+
+// forceQuitManual тот же паттерн вручную, с кодом выхода и сообщением
+func forceQuitManual() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	
+	go func() {
+		sig := <-sigs
+		log.Printf("Сигнал %v: плавное завершение", sig)
+		cancel()
+		
+		sig = <-sigs
+		log.Printf("Повторный сигнал %v: принудительный выход", sig)
+		os.Exit(130) // 128 + SIGINT, как у shell
+	}()
+	return ctx
+}
+
+// Пример 4: Игнорирование SIGHUP
+func ignoreHangup() {
+	// Без обработчика SIGHUP (закрытие терминала) завершает процесс.
+	// signal.Ignore - альтернатива nohup для фоновых задач.
+	signal.Ignore(syscall.SIGHUP)
+}
+
+func main() {
+	mode := flag.String("mode", "demo", "demo | serve | manual | ignore-hup")
+	addr := flag.String("addr", ":8080", "адрес сервера")
+	flag.Parse()
+	
+	cfgPath := filepath.Join(os.TempDir(), "signals-config.json")
+	os.WriteFile(cfgPath, []byte(`{"greeting": "Привет!", "delay": "2s"}`), 0o644)
+	if err := loadConfig(cfgPath); err != nil {
+		log.Fatal(err)
+	}
+	
+	fmt.Printf("PID %d, конфигурация: %s\n", os.Getpid(), cfgPath)
+	printPlatformNotes()
+	
+	switch *mode {
+	case "serve":
+		// Ручная проверка:
+		//   curl localhost:8080 & kill -TERM <pid>  - запрос завершится до выхода
+		//   kill -HUP <pid>                          - перечитать конфигурацию
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go watchReload(ctx, cfgPath)
+		go forceQuitOnSecondSignal(ctx, stop)
+		go watchExtraSignals(ctx)
+		if err := runServer(ctx, *addr); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	
+	case "manual":
+		ctx := forceQuitManual()
+		log.Println("Работаем... Ctrl+C - плавно, второй Ctrl+C - сразу")
+		<-ctx.Done()
+		log.Println("Очистка ресурсов занимает 5 секунд")
+		time.Sleep(5 * time.Second)
+	
+	case "ignore-hup":
+		ignoreHangup()
+		log.Println("SIGHUP игнорируется: kill -HUP", os.Getpid(), "ничего не сделает; Ctrl+C для выхода")
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		<-ctx.Done()
+	
+	default:
+		demo(cfgPath, *addr)
+	}
+	log.Println("Процесс завершен")
+}
+
+// demo отправляет сигналы самому себе, чтобы пример работал без терминала
+func demo(cfgPath, addr string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go watchReload(ctx, cfgPath)
+	
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		os.WriteFile(cfgPath, []byte(`{"greeting": "Здравствуйте!", "delay": "500ms"}`), 0o644)
+		sendSelf(syscall.SIGHUP)
+		
+		// Запрос, который начнется до SIGTERM и должен успешно завершиться
+		go func() {
+			resp, err := http.Get("http://localhost" + addr)
+			if err != nil {
+				log.Printf("Запрос: %v", err)
+				return
+			}
+			resp.Body.Close()
+			log.Printf("Запрос во время завершения: %s", resp.Status)
+		}()
+		
+		time.Sleep(200 * time.Millisecond)
+		sendSelf(syscall.SIGTERM)
+	}()
+	
+	if err := runServer(ctx, addr); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond) // Даем напечатать лог запроса
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+)
+
+// watchExtraSignals SIGUSR1 - вывести статистику процесса (есть только в Unix)
+func watchExtraSignals(ctx context.Context) {
+	usr1 := make(chan os.Signal, 1)
+	signal.Notify(usr1, syscall.SIGUSR1)
+	defer signal.Stop(usr1)
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-usr1:
+			var m runtime.MemStats
+			runtime.ReadMemStats(&m)
+			log.Printf("SIGUSR1: горутин %d, heap %d KB", runtime.NumGoroutine(), m.HeapAlloc/1024)
+		}
+	}
+}
+
+func sendSelf(sig syscall.Signal) {
+	syscall.Kill(os.Getpid(), sig)
+}
+
+func printPlatformNotes() {
+	fmt.Println("Unix: kill -HUP/-TERM/-USR1", os.Getpid())
+}
\ No newline at end of file
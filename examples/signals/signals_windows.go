@@ -0,0 +1,23 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// В Windows нет SIGUSR1, а SIGHUP и SIGTERM определены только как константы:
+// процессу доставляется лишь os.Interrupt (Ctrl+C, Ctrl+Break) и закрытие
+// консоли, которое Go превращает в SIGTERM. kill из другого процесса
+// (TerminateProcess) перехватить нельзя - он всегда "kill -9".
+func watchExtraSignals(ctx context.Context) {}
+
+// sendSelf в Windows недоступен: os.Process.Signal умеет только os.Kill,
+// поэтому demo здесь ждет настоящего Ctrl+C
+func sendSelf(sig syscall.Signal) {}
+
+func printPlatformNotes() {
+	fmt.Println("Windows: доступны только Ctrl+C и закрытие консоли; SIGHUP-перезагрузка не сработает")
+}
\ No newline at end of file
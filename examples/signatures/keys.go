@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// EncodePrivateKey кодирует приватный ключ в PEM (PKCS#8).
+// PKCS#8 подходит для любых алгоритмов, в отличие от PKCS#1 (только RSA).
+func EncodePrivateKey(key crypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodePublicKey кодирует публичный ключ в PEM (PKIX, SubjectPublicKeyInfo)
+func EncodePublicKey(key crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// DecodePrivateKey разбирает PEM с приватным ключом
+func DecodePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("PEM-блок не найден")
+	}
+	
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("ключ %T не умеет подписывать", key)
+		}
+		return signer, nil
+	case "RSA PRIVATE KEY":
+		// Старый формат openssl genrsa
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип PEM %q", block.Type)
+	}
+}
+
+// DecodePublicKey разбирает PEM с публичным ключом
+func DecodePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("PEM-блок PUBLIC KEY не найден")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// Sign подписывает сообщение ключом Ed25519 или RSA.
+// Ed25519 подписывает само сообщение; RSA - его SHA-256 хеш,
+// схема PSS предпочтительнее устаревшей PKCS#1 v1.5.
+func Sign(key crypto.Signer, message []byte) ([]byte, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, message), nil
+	case *rsa.PrivateKey:
+		digest := sha256Sum(message)
+		return rsa.SignPSS(rand.Reader, k, crypto.SHA256, digest, nil)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый ключ %T", key)
+	}
+}
+
+// ErrBadSignature подпись не прошла проверку
+var ErrBadSignature = errors.New("подпись неверна")
+
+// Verify проверяет подпись, созданную Sign
+func Verify(key crypto.PublicKey, message, sig []byte) error {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, message, sig) {
+			return ErrBadSignature
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPSS(k, crypto.SHA256, sha256Sum(message), sig, nil); err != nil {
+			return ErrBadSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("неподдерживаемый ключ %T", key)
+	}
+}
+
+func sha256Sum(b []byte) []byte {
+	h := crypto.SHA256.New()
+	h.Write(b)
+	return h.Sum(nil)
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// License лицензия, выдаваемая клиенту.
+// Публичный ключ встраивается в приложение, приватный остается у продавца:
+// проверить лицензию можно офлайн, а выпустить новую - только продавцу.
+type License struct {
+	Customer  string    `json:"customer"`
+	Plan      string    `json:"plan"`
+	Seats     int       `json:"seats"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+const licenseSeparator = "\n--\n"
+
+// IssueLicense возвращает файл лицензии: JSON, разделитель и подпись в base64
+func IssueLicense(key crypto.Signer, lic License) ([]byte, error) {
+	payload, err := json.Marshal(lic)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := Sign(key, payload)
+	if err != nil {
+		return nil, err
+	}
+	
+	var buf bytes.Buffer
+	buf.Write(payload)
+	buf.WriteString(licenseSeparator)
+	buf.WriteString(base64.StdEncoding.EncodeToString(sig))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// ErrLicenseExpired срок действия лицензии истек
+var ErrLicenseExpired = errors.New("срок действия лицензии истек")
+
+// VerifyLicense проверяет подпись и срок действия.
+// Подпись проверяется по исходным байтам JSON, до разбора.
+func VerifyLicense(key crypto.PublicKey, file []byte, now time.Time) (*License, error) {
+	payload, sigB64, ok := bytes.Cut(file, []byte(licenseSeparator))
+	if !ok {
+		return nil, errors.New("неверный формат файла лицензии")
+	}
+	
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sigB64)))
+	if err != nil {
+		return nil, fmt.Errorf("неверная подпись: %w", err)
+	}
+	if err := Verify(key, payload, sig); err != nil {
+		return nil, err
+	}
+	
+	var lic License
+	if err := json.Unmarshal(payload, &lic); err != nil {
+		return nil, err
+	}
+	if now.After(lic.ExpiresAt) {
+		return &lic, ErrLicenseExpired
+	}
+	return &lic, nil
+}
\ No newline at end of file
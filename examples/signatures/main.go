@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// Пример 1: Генерация ключей и PEM
+func keysAndPEM() (crypto.Signer, crypto.Signer) {
+	fmt.Println("=== Генерация ключей ===")
+	
+	// Ed25519: ключ 32 байта, подпись 64 байта, быстрая и детерминированная
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	// RSA: не меньше 2048 бит; генерация заметно медленнее
+	start := time.Now()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("RSA-2048 сгенерирован за %v\n", time.Since(start).Round(time.Millisecond))
+	
+	privPEM, err := EncodePrivateKey(edKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pubPEM, err := EncodePublicKey(edKey.Public())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s%s", privPEM, pubPEM)
+	
+	// Загрузка обратно из PEM
+	loaded, err := DecodePrivateKey(privPEM)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Ключ восстановлен из PEM:", loaded.(ed25519.PrivateKey).Equal(edKey))
+	
+	return edKey, rsaKey
+}
+
+// Пример 2: Подпись и проверка
+func signAndVerify(edKey, rsaKey crypto.Signer) {
+	fmt.Println("\n=== Подпись и проверка ===")
+	
+	message := []byte(`{"order_id":42,"amount":1500}`)
+	
+	for _, key := range []crypto.Signer{edKey, rsaKey} {
+		start := time.Now()
+		sig, err := Sign(key, message)
+		if err != nil {
+			log.Fatal(err)
+		}
+		signTime := time.Since(start)
+		
+		fmt.Printf("%T: подпись %d байт за %v\n", key, len(sig), signTime.Round(time.Microsecond))
+		fmt.Println("  проверка:", Verify(key.Public(), message, sig))
+		
+		altered := bytes.Replace(message, []byte("1500"), []byte("9500"), 1)
+		fmt.Println("  измененное сообщение:", Verify(key.Public(), altered, sig))
+	}
+	
+	// Ed25519 детерминирован: одно сообщение - одна подпись.
+	// RSA-PSS рандомизирован: подписи разные, но обе верны.
+	s1, _ := Sign(edKey, message)
+	s2, _ := Sign(edKey, message)
+	fmt.Println("Ed25519 подписи совпадают:", bytes.Equal(s1, s2))
+	s1, _ = Sign(rsaKey, message)
+	s2, _ = Sign(rsaKey, message)
+	fmt.Println("RSA-PSS подписи совпадают:", bytes.Equal(s1, s2))
+}
+
+// Пример 3: Подписанные вебхуки
+func webhooks(key crypto.Signer) {
+	fmt.Println("\n=== Вебхуки ===")
+	
+	srv := httptest.NewServer(webhookHandler(key.Public()))
+	defer srv.Close()
+	
+	send := func(body []byte, at time.Time, tamper bool) {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader(body))
+		if err := SignWebhook(req, key, body, at); err != nil {
+			log.Fatal(err)
+		}
+		if tamper {
+			req.Body = io.NopCloser(bytes.NewReader(bytes.ToUpper(body)))
+			req.ContentLength = int64(len(body))
+		}
+		
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		reply, _ := io.ReadAll(resp.Body)
+		fmt.Printf("%d %s\n", resp.StatusCode, bytes.TrimSpace(reply))
+	}
+	
+	event := []byte(`{"event":"payment.succeeded","id":"evt_1"}`)
+	send(event, time.Now(), false)
+	send(event, time.Now(), true)
+	send(event, time.Now().Add(-time.Hour), false)
+	
+	// Эти же функции подписи лежат в основе JWT с алгоритмами EdDSA и PS256
+	// (заголовок.полезная_нагрузка подписывается так же, как payload здесь)
+}
+
+// Пример 4: Файл лицензии
+func licenses(key crypto.Signer) {
+	fmt.Println("\n=== Лицензии ===")
+	
+	file, err := IssueLicense(key, License{
+		Customer:  "ООО Ромашка",
+		Plan:      "enterprise",
+		Seats:     50,
+		ExpiresAt: time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s", file)
+	
+	lic, err := VerifyLicense(key.Public(), file, time.Now())
+	fmt.Printf("Проверка: %+v, err=%v\n", lic, err)
+	
+	// Клиент пытается увеличить число мест
+	forged := bytes.Replace(file, []byte(`"seats":50`), []byte(`"seats":500`), 1)
+	_, err = VerifyLicense(key.Public(), forged, time.Now())
+	fmt.Println("Подделка:", err)
+	
+	_, err = VerifyLicense(key.Public(), file, time.Now().AddDate(2, 0, 0))
+	fmt.Println("Через два года:", err)
+	
+	// Подпись другим ключом
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherFile, _ := IssueLicense(otherKey, *lic)
+	_, err = VerifyLicense(key.Public(), otherFile, time.Now())
+	fmt.Println("Чужой ключ:", err)
+	
+	pub := key.Public().(ed25519.PublicKey)
+	fmt.Println("Публичный ключ для встраивания в приложение:", hex.EncodeToString(pub))
+}
+
+func main() {
+	edKey, rsaKey := keysAndPEM()
+	signAndVerify(edKey, rsaKey)
+	webhooks(edKey)
+	licenses(edKey)
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Подпись вебхуков асимметричным ключом: отправитель подписывает приватным,
+// получатели проверяют опубликованным публичным. В отличие от HMAC с общим
+// секретом, получатель не может сам подделать сообщение от имени отправителя.
+
+const (
+	headerSignature = "X-Signature"
+	headerTimestamp = "X-Signature-Timestamp"
+
+	// Запросы старше допуска отклоняются - защита от повторной отправки
+	webhookTolerance = 5 * time.Minute
+)
+
+// signedPayload - временная метка входит в подпись, иначе ее можно подменить
+func signedPayload(timestamp string, body []byte) []byte {
+	return append([]byte(timestamp+"."), body...)
+}
+
+// SignWebhook выставляет заголовки подписи запроса
+func SignWebhook(req *http.Request, key crypto.Signer, body []byte, now time.Time) error {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig, err := Sign(key, signedPayload(ts, body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(headerTimestamp, ts)
+	req.Header.Set(headerSignature, base64.StdEncoding.EncodeToString(sig))
+	return nil
+}
+
+// VerifyWebhook проверяет подпись и возраст запроса, возвращает тело
+func VerifyWebhook(r *http.Request, key crypto.PublicKey, now time.Time) ([]byte, error) {
+	ts := r.Header.Get(headerTimestamp)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, errors.New("нет временной метки")
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return nil, fmt.Errorf("временная метка вне допуска: %v", age.Round(time.Second))
+	}
+	
+	sig, err := base64.StdEncoding.DecodeString(r.Header.Get(headerSignature))
+	if err != nil {
+		return nil, errors.New("неверная кодировка подписи")
+	}
+	
+	// Подпись считается по сырому телу: повторная сериализация JSON
+	// может изменить порядок полей или пробелы
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := Verify(key, signedPayload(ts, body), sig); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// webhookHandler принимает только подписанные события
+func webhookHandler(key crypto.PublicKey) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := VerifyWebhook(r, key, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, "принято: %s", body)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// Copy-on-write снимок: данные, которые читают на каждом запросе, а
+// меняют раз в минуты (флаги функций, таблица маршрутов, настройки),
+// хранятся как неизменяемое значение за atomic.Pointer. Читатель берет
+// указатель одной атомарной загрузкой и работает со снимком без
+// блокировок. Писатель копирует снимок, меняет копию и публикует ее
+// атомарной заменой указателя; старый снимок дочитывают те, кто уже
+// его взял, а потом его собирает GC.
+//
+// Почему не sync.RWMutex: RLock - атомарная запись в общий счетчик
+// читателей. На многих ядрах все читатели пишут в одну строку кэша, и
+// она мечется между ядрами (как в examples/layout) - чтение под RWMutex
+// перестает масштабироваться. atomic.Pointer.Load - только чтение, и
+// строка кэша остается общей для всех ядер.
+//
+// Цена: каждое изменение копирует данные целиком. Годится для
+// небольших данных с редкой записью; для большой часто меняемой map -
+// шардирование или sync.Map.
+
+// Flag флаг функции с постепенным включением: percent процентов
+// пользователей видят функцию
+type Flag struct {
+	Percent int
+}
+
+// enabledFor одинаковый ответ для одного пользователя при каждом
+// запросе: процент берется от ID, а не случайно
+func (f Flag) enabledFor(userID int) bool {
+	return userID%100 < f.Percent
+}
+
+// Flags флаги функций
+type Flags interface {
+	Enabled(name string, userID int) bool
+	Set(name string, percent int)
+}
+
+// mutexFlags map под RWMutex
+type mutexFlags struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+func newMutexFlags() *mutexFlags {
+	return &mutexFlags{flags: make(map[string]Flag)}
+}
+
+func (f *mutexFlags) Enabled(name string, userID int) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.flags[name].enabledFor(userID)
+}
+
+func (f *mutexFlags) Set(name string, percent int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags[name] = Flag{Percent: percent}
+}
+
+// snapshotFlags неизменяемая map за atomic.Pointer. Опубликованную map
+// никто не меняет - только заменяет целиком.
+type snapshotFlags struct {
+	current atomic.Pointer[map[string]Flag]
+
+	// mu только для писателей: два Set одновременно скопировали бы
+	// один и тот же снимок, и изменение первого потерялось бы при
+	// публикации второго. Читатели mu не трогают.
+	mu sync.Mutex
+}
+
+func newSnapshotFlags() *snapshotFlags {
+	f := &snapshotFlags{}
+	f.current.Store(&map[string]Flag{})
+	return f
+}
+
+func (f *snapshotFlags) Enabled(name string, userID int) bool {
+	return (*f.current.Load())[name].enabledFor(userID)
+}
+
+func (f *snapshotFlags) Set(name string, percent int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	next := maps.Clone(*f.current.Load())
+	next[name] = Flag{Percent: percent}
+	f.current.Store(&next)
+}
+
+// Replace публикует новый набор флагов целиком - как при перечитывании
+// файла настроек. Вызывающий не должен менять flags после вызова.
+func (f *snapshotFlags) Replace(flags map[string]Flag) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current.Store(&flags)
+}
+
+// Snapshot текущий снимок: все проверки одного запроса по одному
+// снимку видят согласованное состояние, даже если флаги сменятся
+// посреди запроса. Менять возвращенную map нельзя.
+func (f *snapshotFlags) Snapshot() map[string]Flag {
+	return *f.current.Load()
+}
+
+// casFlags писатели без мьютекса: CompareAndSwap публикует копию, только
+// если снимок не сменился с момента чтения, иначе все заново. При
+// редкой записи повторов почти не бывает; при частой - мьютекс честнее.
+type casFlags struct {
+	current atomic.Pointer[map[string]Flag]
+}
+
+func newCASFlags() *casFlags {
+	f := &casFlags{}
+	f.current.Store(&map[string]Flag{})
+	return f
+}
+
+func (f *casFlags) Enabled(name string, userID int) bool {
+	return (*f.current.Load())[name].enabledFor(userID)
+}
+
+func (f *casFlags) Set(name string, percent int) {
+	for {
+		old := f.current.Load()
+		next := maps.Clone(*old)
+		next[name] = Flag{Percent: percent}
+		if f.current.CompareAndSwap(old, &next) {
+			return
+		}
+	}
+}
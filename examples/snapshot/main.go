@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Снимки copy-on-write для горячего пути чтения: флаги функций и
+// таблица маршрутов, которые читаются на каждом запросе и меняются
+// редко. Сравнение с RWMutex под параллельным чтением.
+//
+//   - flags.go - флаги функций: RWMutex, снимок с мьютексом писателей,
+//     снимок с CompareAndSwap;
+//   - routes.go - таблица маршрутов с заменой целиком;
+//   - snapshot_test.go - тесты (с -race) и бенчмарки.
+//
+// Запуск: go run .
+// Бенчмарки по числу ядер:
+//
+//	go test -run=^$ -bench=. -cpu=1,2,4,8
+//
+// На одном ядре разница - цена двух атомарных записей RLock/RUnlock
+// против одной загрузки, единицы наносекунд. С числом ядер время
+// RWMutex на операцию растет, снимка - нет.
+
+var routes = []Route{
+	{"/", "web"},
+	{"/api/", "api-v1"},
+	{"/api/users/", "users"},
+	{"/api/orders/", "orders"},
+	{"/static/", "cdn"},
+}
+
+// Пример 1: Один снимок на запрос - согласованное состояние
+func consistentView() {
+	fmt.Println("=== Снимок на запрос ===")
+	
+	flags := newSnapshotFlags()
+	flags.Replace(map[string]Flag{"new-checkout": {Percent: 100}, "new-cart": {Percent: 100}})
+	
+	// Запрос берет снимок один раз в начале...
+	snap := flags.Snapshot()
+	// ...посреди запроса флаги выключают вместе
+	flags.Replace(map[string]Flag{"new-checkout": {Percent: 0}, "new-cart": {Percent: 0}})
+	
+	// По снимку обе проверки согласованы: новая корзина и новое
+	// оформление заказа. Два отдельных Enabled могли бы дать новую
+	// корзину со старым оформлением.
+	fmt.Printf("По снимку: new-cart=%v new-checkout=%v\n",
+		snap["new-cart"].enabledFor(42), snap["new-checkout"].enabledFor(42))
+	fmt.Printf("Следующий запрос: new-cart=%v new-checkout=%v\n",
+		flags.Enabled("new-cart", 42), flags.Enabled("new-checkout", 42))
+	
+	r := newSnapshotRouter(routes)
+	backend, _ := r.Lookup("/api/users/7")
+	fmt.Printf("Таблица v%d: /api/users/7 -> %s\n", r.Version(), backend)
+	// Новая таблица собирается целиком и публикуется одной заменой
+	next := slices.Clone(routes)
+	next[2].Backend = "users-v2"
+	r.Replace(next)
+	backend, _ = r.Lookup("/api/users/7")
+	fmt.Printf("Таблица v%d: /api/users/7 -> %s\n", r.Version(), backend)
+}
+
+// withWriter запускает писателя, который меняет данные каждые
+// interval, пока идет fn: редкая запись на фоне постоянного чтения
+func withWriter(interval time.Duration, write func(i int), fn func()) {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				write(i)
+			}
+		}
+	}()
+	fn()
+	close(stop)
+	wg.Wait()
+}
+
+// Пример 2: Чтение флагов из многих горутин при редкой записи
+func compareFlags() {
+	fmt.Printf("\n=== Флаги: чтение из %d горутин при GOMAXPROCS=%d, запись раз в миллисекунду ===\n",
+		4*runtime.GOMAXPROCS(0), runtime.GOMAXPROCS(0))
+	
+	for _, v := range []struct {
+		name  string
+		flags Flags
+	}{
+		{"RWMutex", newMutexFlags()},
+		{"atomic.Pointer", newSnapshotFlags()},
+		{"atomic.Pointer + CAS", newCASFlags()},
+	} {
+		for i := range 20 {
+			v.flags.Set(fmt.Sprintf("flag-%d", i), i*5)
+		}
+		var r testing.BenchmarkResult
+		withWriter(time.Millisecond, func(i int) { v.flags.Set("flag-0", i%100) }, func() {
+			r = testing.Benchmark(func(b *testing.B) {
+				b.SetParallelism(4)
+				b.RunParallel(func(pb *testing.PB) {
+					user := 0
+					for pb.Next() {
+						v.flags.Enabled("flag-7", user)
+						user++
+					}
+				})
+			})
+		})
+		fmt.Printf("  %-22s %6.1f нс/op\n", v.name, float64(r.T.Nanoseconds())/float64(r.N))
+	}
+}
+
+// Пример 3: Поиск маршрута при редкой замене таблицы
+func compareRouters() {
+	fmt.Println("\n=== Маршруты: поиск при замене таблицы раз в миллисекунду ===")
+	
+	for _, v := range []struct {
+		name   string
+		router Router
+	}{
+		{"RWMutex", newMutexRouter(routes)},
+		{"atomic.Pointer", newSnapshotRouter(routes)},
+	} {
+		var r testing.BenchmarkResult
+		withWriter(time.Millisecond, func(int) { v.router.Replace(routes) }, func() {
+			r = testing.Benchmark(func(b *testing.B) {
+				b.SetParallelism(4)
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						v.router.Lookup("/api/orders/42")
+					}
+				})
+			})
+		})
+		fmt.Printf("  %-22s %6.1f нс/op\n", v.name, float64(r.T.Nanoseconds())/float64(r.N))
+	}
+	if runtime.GOMAXPROCS(0) == 1 {
+		fmt.Println("GOMAXPROCS=1: горутины не читают параллельно, разница видна только на нескольких ядрах")
+	}
+}
+
+func main() {
+	consistentView()
+	compareFlags()
+	compareRouters()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Route маршрут: запросы с путем, начинающимся на Prefix, уходят на
+// Backend
+type Route struct {
+	Prefix  string
+	Backend string
+}
+
+// routeTable снимок таблицы маршрутов, подготовленный для поиска:
+// префиксы от длинных к коротким, первый совпавший - самый длинный.
+// После публикации не меняется.
+type routeTable struct {
+	routes  []Route
+	version int
+}
+
+func newRouteTable(routes []Route, version int) *routeTable {
+	sorted := slices.Clone(routes)
+	slices.SortStableFunc(sorted, func(a, b Route) int {
+		return cmp.Compare(len(b.Prefix), len(a.Prefix))
+	})
+	return &routeTable{routes: sorted, version: version}
+}
+
+func (t *routeTable) lookup(path string) (string, bool) {
+	for _, r := range t.routes {
+		if strings.HasPrefix(path, r.Prefix) {
+			return r.Backend, true
+		}
+	}
+	return "", false
+}
+
+// Router таблица маршрутов
+type Router interface {
+	Lookup(path string) (string, bool)
+	Replace(routes []Route)
+}
+
+// mutexRouter таблица под RWMutex
+type mutexRouter struct {
+	mu    sync.RWMutex
+	table *routeTable
+}
+
+func newMutexRouter(routes []Route) *mutexRouter {
+	return &mutexRouter{table: newRouteTable(routes, 1)}
+}
+
+func (r *mutexRouter) Lookup(path string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.table.lookup(path)
+}
+
+func (r *mutexRouter) Replace(routes []Route) {
+	t := newRouteTable(routes, 0)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.version = r.table.version + 1
+	r.table = t
+}
+
+// snapshotRouter таблица за atomic.Pointer. Новая таблица сортируется
+// до публикации - читатели никогда не видят ее наполовину готовой.
+type snapshotRouter struct {
+	table atomic.Pointer[routeTable]
+	mu    sync.Mutex // Только для писателей: версии по порядку
+}
+
+func newSnapshotRouter(routes []Route) *snapshotRouter {
+	r := &snapshotRouter{}
+	r.table.Store(newRouteTable(routes, 1))
+	return r
+}
+
+func (r *snapshotRouter) Lookup(path string) (string, bool) {
+	return r.table.Load().lookup(path)
+}
+
+func (r *snapshotRouter) Replace(routes []Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.table.Store(newRouteTable(routes, r.table.Load().version+1))
+}
+
+// Version номер опубликованной таблицы
+func (r *snapshotRouter) Version() int {
+	return r.table.Load().version
+}
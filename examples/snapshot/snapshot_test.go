@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func flagImpls() map[string]func() Flags {
+	return map[string]func() Flags{
+		"mutex":    func() Flags { return newMutexFlags() },
+		"snapshot": func() Flags { return newSnapshotFlags() },
+		"cas":      func() Flags { return newCASFlags() },
+	}
+}
+
+func TestFlags_Enabled(t *testing.T) {
+	for name, newFlags := range flagImpls() {
+		f := newFlags()
+		if f.Enabled("beta", 1) {
+			t.Errorf("%s: expected unknown flag to be disabled", name)
+		}
+		f.Set("beta", 30)
+		if !f.Enabled("beta", 129) || f.Enabled("beta", 130) {
+			t.Errorf("%s: expected users with ID%%100 < 30 enabled", name)
+		}
+	}
+}
+
+// Параллельные Set не теряют изменений: писатели копируют снимок по
+// очереди (мьютекс или повтор CAS)
+func TestFlags_ConcurrentSetNoLostUpdates(t *testing.T) {
+	for name, newFlags := range flagImpls() {
+		f := newFlags()
+		const writers = 50
+		var wg sync.WaitGroup
+		for i := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				f.Set(fmt.Sprintf("flag-%d", i), 100)
+			}()
+		}
+		wg.Wait()
+		for i := range writers {
+			if !f.Enabled(fmt.Sprintf("flag-%d", i), 0) {
+				t.Errorf("%s: flag-%d lost", name, i)
+			}
+		}
+	}
+}
+
+func TestSnapshotFlags_SnapshotUnchangedBySet(t *testing.T) {
+	f := newSnapshotFlags()
+	f.Set("a", 100)
+	snap := f.Snapshot()
+	f.Set("a", 0)
+	f.Set("b", 100)
+	if snap["a"].Percent != 100 || len(snap) != 1 {
+		t.Errorf("Expected old snapshot to stay unchanged, got %v", snap)
+	}
+}
+
+func TestRouters_LongestPrefix(t *testing.T) {
+	for name, r := range map[string]Router{
+		"mutex":    newMutexRouter(routes),
+		"snapshot": newSnapshotRouter(routes),
+	} {
+		tests := []struct {
+			path     string
+			expected string
+		}{
+			{"/api/users/7", "users"},
+			{"/api/orders", "api-v1"},
+			{"/static/app.js", "cdn"},
+			{"/about", "web"},
+		}
+		for _, tt := range tests {
+			if got, ok := r.Lookup(tt.path); !ok || got != tt.expected {
+				t.Errorf("%s, %s: expected %s, got %s", name, tt.path, tt.expected, got)
+			}
+		}
+		r.Replace([]Route{{"/api/", "api-v2"}})
+		if got, _ := r.Lookup("/api/users/7"); got != "api-v2" {
+			t.Errorf("%s: expected api-v2 after Replace, got %s", name, got)
+		}
+		if _, ok := r.Lookup("/about"); ok {
+			t.Errorf("%s: expected no route for /about after Replace", name)
+		}
+	}
+}
+
+func TestSnapshotRouter_Version(t *testing.T) {
+	r := newSnapshotRouter(routes)
+	for range 3 {
+		r.Replace(routes)
+	}
+	if r.Version() != 4 {
+		t.Errorf("Expected version 4, got %d", r.Version())
+	}
+}
+
+// Читатели и писатели одновременно - для go test -race: снимок после
+// публикации никто не меняет, поэтому гонок нет
+func TestConcurrentReadWrite(t *testing.T) {
+	f := newSnapshotFlags()
+	r := newSnapshotRouter(routes)
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				f.Enabled("a", 1)
+				_ = len(f.Snapshot())
+				if _, ok := r.Lookup("/api/x"); !ok {
+					t.Error("Expected route for /api/x")
+					return
+				}
+			}
+		}()
+	}
+	for i := range 100 {
+		f.Set("a", i%100)
+		r.Replace(routes)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
+
+func BenchmarkFlagsRead(b *testing.B) {
+	for name, newFlags := range flagImpls() {
+		f := newFlags()
+		for i := range 20 {
+			f.Set(fmt.Sprintf("flag-%d", i), i*5)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				user := 0
+				for pb.Next() {
+					f.Enabled("flag-7", user)
+					user++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkFlagsReadWrite чтение с писателем на фоне: запись раз в
+// 100 мкс, на каждую - десятки тысяч чтений
+func BenchmarkFlagsReadWrite(b *testing.B) {
+	for name, newFlags := range flagImpls() {
+		f := newFlags()
+		b.Run(name, func(b *testing.B) {
+			withWriter(100*time.Microsecond, func(i int) { f.Set("flag", i%100) }, func() {
+				b.RunParallel(func(pb *testing.PB) {
+					user := 0
+					for pb.Next() {
+						f.Enabled("flag", user)
+						user++
+					}
+				})
+			})
+		})
+	}
+}
+
+func BenchmarkRouterLookup(b *testing.B) {
+	for name, r := range map[string]Router{
+		"mutex":    newMutexRouter(routes),
+		"snapshot": newSnapshotRouter(routes),
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					r.Lookup("/api/orders/42")
+				}
+			})
+		})
+	}
+}
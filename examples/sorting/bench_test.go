@@ -0,0 +1,48 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func BenchmarkSort(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		for _, o := range orderings {
+			input := o.gen(n, rand.New(rand.NewSource(1)))
+			for _, a := range algorithms {
+				// Квадратичная сортировка на больших случайных данных
+				// занимает минуты и ничего нового не показывает
+				if a.name == "insertion" && n > 10000 && o.name != "sorted" && o.name != "nearly sorted" {
+					continue
+				}
+				b.Run(strconv.Itoa(n)+"/"+o.name+"/"+a.name, func(b *testing.B) {
+					s := make([]int, n)
+					for b.Loop() {
+						b.StopTimer()
+						copy(s, input)
+						b.StartTimer()
+						a.sort(s)
+					}
+				})
+			}
+		}
+	}
+}
+
+// Выигрыш от глубины параллелизма; при GOMAXPROCS=1 его нет вовсе,
+// остаются только накладные расходы на горутины
+func BenchmarkParallelDepth(b *testing.B) {
+	input := rand.New(rand.NewSource(1)).Perm(1000000)
+	for _, depth := range []int{0, 1, 2, 3, 4, 6} {
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			s := make([]int, len(input))
+			for b.Loop() {
+				b.StopTimer()
+				copy(s, input)
+				b.StartTimer()
+				ParallelMergeSort(s, depth)
+			}
+		})
+	}
+}
\ No newline at end of file
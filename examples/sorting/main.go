@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// Запуск бенчмарков по размерам и порядкам входных данных:
+//   go test -bench=. -benchtime=20x
+
+// Алгоритмы для сравнения
+var algorithms = []struct {
+	name string
+	sort func([]int)
+}{
+	{"insertion", InsertionSort[int]},
+	{"merge", MergeSort[int]},
+	{"quick", QuickSort[int]},
+	{"heap", HeapSort[int]},
+	{"parallel merge", func(s []int) { ParallelMergeSort(s, parallelDepth()) }},
+	{"slices.Sort", slices.Sort[[]int]},
+}
+
+// parallelDepth: log2(GOMAXPROCS) уровней дают по горутине на ядро
+func parallelDepth() int {
+	return bits.Len(uint(runtime.GOMAXPROCS(0)))
+}
+
+// Порядки входных данных: каждый бьет по слабому месту какого-то алгоритма
+var orderings = []struct {
+	name string
+	gen  func(n int, rng *rand.Rand) []int
+}{
+	{"random", func(n int, rng *rand.Rand) []int { return rng.Perm(n) }},
+	{"sorted", func(n int, _ *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		return s
+	}},
+	{"reversed", func(n int, _ *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = n - i
+		}
+		return s
+	}},
+	{"nearly sorted", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		for range n / 100 { // 1% случайных перестановок
+			i, j := rng.Intn(n), rng.Intn(n)
+			s[i], s[j] = s[j], s[i]
+		}
+		return s
+	}},
+	{"few unique", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = rng.Intn(10)
+		}
+		return s
+	}},
+}
+
+// Пример 1: Проверка против slices.Sort
+func correctness() {
+	fmt.Println("=== Проверка корректности ===")
+	
+	rng := rand.New(rand.NewSource(1))
+	for _, a := range algorithms {
+		ok := true
+		for _, o := range orderings {
+			for _, n := range []int{0, 1, 2, 13, 1000} {
+				s := o.gen(n, rng)
+				want := slices.Clone(s)
+				slices.Sort(want)
+				a.sort(s)
+				if !slices.Equal(s, want) {
+					ok = false
+					fmt.Printf("  %s ошибается на %s n=%d\n", a.name, o.name, n)
+				}
+			}
+		}
+		fmt.Printf("%-15s ok=%v\n", a.name, ok)
+	}
+}
+
+// Пример 2: Время на разных входах
+func timings() {
+	fmt.Println("\n=== Время сортировки 200 000 чисел ===")
+	
+	const n = 200000
+	rng := rand.New(rand.NewSource(1))
+	fmt.Printf("%-15s", "")
+	for _, o := range orderings {
+		fmt.Printf("%14s", o.name)
+	}
+	fmt.Println()
+	
+	for _, a := range algorithms {
+		fmt.Printf("%-15s", a.name)
+		for _, o := range orderings {
+			s := o.gen(n, rng)
+			// Вставки на случайных данных - это минуты, показываем только
+			// там, где они хороши
+			if a.name == "insertion" && o.name != "sorted" && o.name != "nearly sorted" {
+				fmt.Printf("%14s", "-")
+				continue
+			}
+			start := time.Now()
+			a.sort(s)
+			fmt.Printf("%14v", time.Since(start).Round(10*time.Microsecond))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("(GOMAXPROCS=%d)\n", runtime.GOMAXPROCS(0))
+}
+
+func main() {
+	correctness()
+	timings()
+}
\ No newline at end of file
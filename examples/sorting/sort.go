@@ -0,0 +1,186 @@
+package main
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"sync"
+)
+
+// Все функции сортируют на месте по возрастанию и работают с любым
+// упорядоченным типом. Сложность указана для среднего случая.
+
+// InsertionSort O(n²), но O(n) на почти отсортированных данных и очень
+// быстрая на маленьких слайсах - поэтому ее используют внутри гибридных
+// сортировок (и внутри slices.Sort) для коротких отрезков.
+func InsertionSort[T cmp.Ordered](s []T) {
+	for i := 1; i < len(s); i++ {
+		v := s[i]
+		j := i
+		for j > 0 && s[j-1] > v {
+			s[j] = s[j-1]
+			j--
+		}
+		s[j] = v
+	}
+}
+
+// insertionThreshold - ниже этой длины рекурсивным сортировкам выгоднее
+// переключиться на вставки
+const insertionThreshold = 12
+
+// MergeSort O(n log n) всегда, устойчивая, но требует O(n) доп. памяти
+func MergeSort[T cmp.Ordered](s []T) {
+	buf := make([]T, len(s))
+	mergeSort(s, buf)
+}
+
+func mergeSort[T cmp.Ordered](s, buf []T) {
+	if len(s) <= insertionThreshold {
+		InsertionSort(s)
+		return
+	}
+	mid := len(s) / 2
+	mergeSort(s[:mid], buf[:mid])
+	mergeSort(s[mid:], buf[mid:])
+	merge(s, mid, buf)
+}
+
+// merge сливает отсортированные половины s[:mid] и s[mid:]
+func merge[T cmp.Ordered](s []T, mid int, buf []T) {
+	if s[mid-1] <= s[mid] {
+		return // Уже по порядку: частый случай на почти отсортированных данных
+	}
+	copy(buf, s)
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(s) {
+		// <=, а не <: при равенстве берем из левой половины - это и
+		// делает сортировку устойчивой
+		if buf[i] <= buf[j] {
+			s[k] = buf[i]
+			i++
+		} else {
+			s[k] = buf[j]
+			j++
+		}
+		k++
+	}
+	copy(s[k:], buf[i:mid])
+	copy(s[k:], buf[j:len(s)])
+}
+
+// QuickSort O(n log n) в среднем, на месте, неустойчивая. Опорный
+// элемент - медиана трех случайных: на уже отсортированных данных наивный
+// выбор первого элемента дает O(n²) и переполнение стека, а медиану трех
+// фиксированных позиций (первый, средний, последний) ломают входы вроде
+// "пилы" - в том числе те, что получаются после разбиения. Разбиение на три
+// части (<, ==, >) нужно для данных с множеством повторов: при
+// разбиении на две равные элементы снова и снова попадают в одну
+// половину, и сортировка тоже деградирует до O(n²).
+func QuickSort[T cmp.Ordered](s []T) {
+	for len(s) > insertionThreshold {
+		lt, gt := partition3(s)
+		// Рекурсия в меньшую часть, цикл по большей: глубина стека O(log n)
+		if lt < len(s)-gt {
+			QuickSort(s[:lt])
+			s = s[gt:]
+		} else {
+			QuickSort(s[gt:])
+			s = s[:lt]
+		}
+	}
+	InsertionSort(s)
+}
+
+// partition3 переставляет s так, что s[:lt] < pivot, s[lt:gt] == pivot,
+// s[gt:] > pivot (задача о голландском флаге)
+func partition3[T cmp.Ordered](s []T) (lt, gt int) {
+	n := len(s)
+	pivot := medianOfThree(s[rand.IntN(n)], s[rand.IntN(n)], s[rand.IntN(n)])
+	lt, i, gt := 0, 0, len(s)
+	for i < gt {
+		switch {
+		case s[i] < pivot:
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		case s[i] > pivot:
+			gt--
+			s[gt], s[i] = s[i], s[gt]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+func medianOfThree[T cmp.Ordered](a, b, c T) T {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b = c
+	}
+	return max(a, b)
+}
+
+// HeapSort O(n log n) всегда, на месте, неустойчивая. Гарантия худшего
+// случая без доп. памяти, но обращения к памяти прыгают по слайсу,
+// поэтому на практике медленнее quicksort.
+func HeapSort[T cmp.Ordered](s []T) {
+	n := len(s)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(s, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDown(s, 0, end)
+	}
+}
+
+// siftDown опускает s[i] в max-куче s[:n]
+func siftDown[T cmp.Ordered](s []T, i, n int) {
+	for {
+		largest := i
+		if l := 2*i + 1; l < n && s[l] > s[largest] {
+			largest = l
+		}
+		if r := 2*i + 2; r < n && s[r] > s[largest] {
+			largest = r
+		}
+		if largest == i {
+			return
+		}
+		s[i], s[largest] = s[largest], s[i]
+		i = largest
+	}
+}
+
+// parallelThreshold - отрезки короче сортируются последовательно:
+// запуск горутины стоит больше, чем сортировка нескольких тысяч чисел
+const parallelThreshold = 4096
+
+// ParallelMergeSort сортирует половины в отдельных горутинах. Глубина
+// параллелизма ограничена depth: 2^depth горутин достаточно, чтобы
+// занять все ядра, а дальше они только мешают друг другу.
+func ParallelMergeSort[T cmp.Ordered](s []T, depth int) {
+	buf := make([]T, len(s))
+	parallelMergeSort(s, buf, depth)
+}
+
+func parallelMergeSort[T cmp.Ordered](s, buf []T, depth int) {
+	if depth <= 0 || len(s) < parallelThreshold {
+		mergeSort(s, buf)
+		return
+	}
+	mid := len(s) / 2
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMergeSort(s[:mid], buf[:mid], depth-1)
+	}()
+	// Вторую половину сортирует текущая горутина, а не новая
+	parallelMergeSort(s[mid:], buf[mid:], depth-1)
+	wg.Wait()
+	merge(s, mid, buf)
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/quick"
+)
+
+func TestSortsMatchSlicesSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, a := range algorithms {
+		for _, o := range orderings {
+			for _, n := range []int{0, 1, 2, 3, insertionThreshold, insertionThreshold + 1, 1000, parallelThreshold*4 + 7} {
+				if a.name == "insertion" && n > 1000 {
+					continue
+				}
+				t.Run(a.name+"/"+o.name+"/"+strconv.Itoa(n), func(t *testing.T) {
+					s := o.gen(n, rng)
+					want := slices.Clone(s)
+					slices.Sort(want)
+					a.sort(s)
+					if !slices.Equal(s, want) {
+						t.Errorf("Result differs from slices.Sort")
+					}
+				})
+			}
+		}
+	}
+}
+
+// Свойство: для любого входа результат совпадает с slices.Sort
+func TestSortsProperty(t *testing.T) {
+	sorts := map[string]func([]float64){
+		"merge":    MergeSort[float64],
+		"quick":    QuickSort[float64],
+		"heap":     HeapSort[float64],
+		"parallel": func(s []float64) { ParallelMergeSort(s, 2) },
+	}
+	for name, sortFn := range sorts {
+		t.Run(name, func(t *testing.T) {
+			f := func(s []float64) bool {
+				want := slices.Clone(s)
+				slices.Sort(want)
+				sortFn(s)
+				return slices.Equal(s, want)
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	"streamstats"
+)
+
+// Потоковая статистика: значения приходят по одному, хранить их все
+// нельзя, а ответ нужен в любой момент. Сами типы - в пакете
+// streamstats (корень модуля); его же импортируют examples/loadgen
+// (живой прогресс), examples/expvar (метрики) и examples/dashboard.
+//
+// Запуск: go run ./cmd/streamstats
+
+// Пример 1: Скользящее окно
+func slidingWindow() {
+	fmt.Println("=== Скользящее окно ===")
+	
+	// Виртуальные часы, чтобы не ждать реальные секунды
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rc := streamstats.NewRateCounterWithNow(10*time.Second, 10, func() time.Time { return now })
+	
+	// 5 секунд по 100 rps, потом тишина; замер - в конце каждой секунды
+	for sec := range 15 {
+		if sec < 5 {
+			rc.Add(100)
+		}
+		now = now.Add(time.Second)
+		fmt.Printf("t=%2ds  событий в окне %4d, %5.1f rps\n", sec+1, rc.Count(), rc.Rate())
+	}
+}
+
+// Пример 2: EWMA
+func ewma() {
+	fmt.Println("\n=== EWMA задержки ===")
+	
+	start := time.Now()
+	fast := streamstats.NewEWMA(time.Second)
+	slow := streamstats.NewEWMA(10 * time.Second)
+	
+	// Запрос каждые 100мс; на 5-й секунде задержка скачет с 20 до 200 мс
+	for i := range 100 {
+		at := start.Add(time.Duration(i) * 100 * time.Millisecond)
+		latency := 20.0
+		if i >= 50 {
+			latency = 200
+		}
+		fast.Observe(latency, at)
+		slow.Observe(latency, at)
+		if i%10 == 9 {
+			fmt.Printf("t=%4.1fs  полураспад 1с: %6.1f мс   10с: %6.1f мс\n", float64(i+1)/10, fast.Value(), slow.Value())
+		}
+	}
+}
+
+// Пример 3: Перцентили алгоритмом P²
+func percentiles() {
+	fmt.Println("\n=== Перцентили P² ===")
+	
+	rng := rand.New(rand.NewSource(1))
+	distributions := []struct {
+		name string
+		gen  func() float64
+	}{
+		{"равномерное", func() float64 { return rng.Float64() * 100 }},
+		{"нормальное", func() float64 { return 50 + 10*rng.NormFloat64() }},
+		{"логнормальное", func() float64 { return math.Exp(3 + 0.8*rng.NormFloat64()) }}, // Типичная форма задержек
+	}
+	
+	for _, d := range distributions {
+		estimators := map[float64]*streamstats.P2{0.5: streamstats.NewP2(0.5), 0.9: streamstats.NewP2(0.9), 0.99: streamstats.NewP2(0.99)}
+		values := make([]float64, 100000)
+		for i := range values {
+			values[i] = d.gen()
+			for _, e := range estimators {
+				e.Add(values[i])
+			}
+		}
+		slices.Sort(values)
+		
+		fmt.Printf("%s:\n", d.name)
+		for _, p := range []float64{0.5, 0.9, 0.99} {
+			exact := values[int(p*float64(len(values)-1))]
+			est := estimators[p].Value()
+			fmt.Printf("  p%-3v точно %8.2f  P² %8.2f  ошибка %5.2f%%\n", p*100, exact, est, 100*math.Abs(est-exact)/exact)
+		}
+	}
+	fmt.Println("Память P²: 5 маркеров; точный перцентиль: все 100 000 значений")
+}
+
+func main() {
+	slidingWindow()
+	ewma()
+	percentiles()
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package streamstats
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// EWMA экспоненциально взвешенное скользящее среднее: каждое новое
+// значение сдвигает среднее на долю alpha. Старые значения не хранятся,
+// их вес убывает геометрически - одно число вместо окна.
+//
+// Здесь alpha зависит от времени между наблюдениями: при редких событиях
+// каждое весит больше, при частых - меньше. Так среднее "забывает"
+// прошлое за заданное время независимо от частоты событий. Половина
+// веса приходится на последние halfLife.
+type EWMA struct {
+	mu       sync.Mutex
+	halfLife time.Duration
+	value    float64
+	last     time.Time
+	started  bool
+}
+
+// NewEWMA создает среднее с периодом полураспада halfLife
+func NewEWMA(halfLife time.Duration) *EWMA {
+	return &EWMA{halfLife: halfLife}
+}
+
+// Observe учитывает значение x, полученное в момент at
+func (e *EWMA) Observe(x float64, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	if !e.started {
+		e.value, e.last, e.started = x, at, true
+		return
+	}
+	dt := at.Sub(e.last)
+	if dt < 0 {
+		dt = 0
+	}
+	// alpha = 1 - 2^(-dt/halfLife)
+	alpha := 1 - math.Exp2(-float64(dt)/float64(e.halfLife))
+	e.value += alpha * (x - e.value)
+	e.last = at
+}
+
+// Value возвращает текущее среднее
+func (e *EWMA) Value() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.value
+}
\ No newline at end of file
@@ -0,0 +1,3 @@
+module streamstats
+
+go 1.22
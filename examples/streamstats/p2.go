@@ -0,0 +1,130 @@
+package streamstats
+
+import (
+	"slices"
+	"sync"
+)
+
+// P2 оценивает квантиль потока алгоритмом P² (Jain, Chlamtac, 1985) за
+// O(1) памяти: хранятся пять маркеров - минимум, p/2, p, (1+p)/2 и
+// максимум. С каждым наблюдением маркеры сдвигаются к своим желаемым
+// позициям, а их высоты подправляются параболической интерполяцией.
+// Точность хуже точного перцентиля, но для p50-p99 на гладких
+// распределениях ошибка обычно в пределах нескольких процентов.
+type P2 struct {
+	mu      sync.Mutex
+	p       float64
+	q       [5]float64 // Высоты маркеров
+	n       [5]float64 // Реальные позиции маркеров
+	want    [5]float64 // Желаемые позиции
+	dWant   [5]float64 // Прирост желаемых позиций на одно наблюдение
+	count   int
+	initial []float64
+}
+
+// NewP2 создает оценщик квантиля p (0 < p < 1)
+func NewP2(p float64) *P2 {
+	return &P2{
+		p:       p,
+		dWant:   [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+		initial: make([]float64, 0, 5),
+	}
+}
+
+// Add учитывает наблюдение
+func (e *P2) Add(x float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.count++
+	
+	// Первые пять наблюдений просто сохраняются
+	if e.count <= 5 {
+		e.initial = append(e.initial, x)
+		if e.count == 5 {
+			slices.Sort(e.initial)
+			copy(e.q[:], e.initial)
+			for i := range 5 {
+				e.n[i] = float64(i + 1)
+				e.want[i] = 1 + 4*e.dWant[i]
+			}
+		}
+		return
+	}
+	
+	// Ячейка k, в которую попало x; крайние маркеры расширяются
+	var k int
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		k = 0
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		// q[0] <= x < q[4], поэтому цикл остановится не дальше k=3
+		for x >= e.q[k+1] {
+			k++
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := range 5 {
+		e.want[i] += e.dWant[i]
+	}
+	
+	// Средние маркеры, отставшие от желаемой позиции на 1 и более,
+	// сдвигаются на одну позицию
+	for i := 1; i <= 3; i++ {
+		d := e.want[i] - e.n[i]
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1
+			}
+			q := e.parabolic(i, sign)
+			if e.q[i-1] < q && q < e.q[i+1] {
+				e.q[i] = q
+			} else {
+				e.q[i] = e.linear(i, sign)
+			}
+			e.n[i] += sign
+		}
+	}
+}
+
+// parabolic - формула P²: парабола через соседние маркеры
+func (e *P2) parabolic(i int, d float64) float64 {
+	return e.q[i] + d/(e.n[i+1]-e.n[i-1])*
+		((e.n[i]-e.n[i-1]+d)*(e.q[i+1]-e.q[i])/(e.n[i+1]-e.n[i])+
+			(e.n[i+1]-e.n[i]-d)*(e.q[i]-e.q[i-1])/(e.n[i]-e.n[i-1]))
+}
+
+// linear - запасной вариант, если парабола вышла за соседей
+func (e *P2) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return e.q[i] + d*(e.q[j]-e.q[i])/(e.n[j]-e.n[i])
+}
+
+// Value возвращает текущую оценку квантиля
+func (e *P2) Value() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.count == 0 {
+		return 0
+	}
+	if e.count < 5 {
+		// Мало данных - точный квантиль по отсортированной копии
+		s := slices.Clone(e.initial)
+		slices.Sort(s)
+		return s[int(e.p*float64(len(s)-1))]
+	}
+	return e.q[2]
+}
+
+// Count возвращает число наблюдений
+func (e *P2) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.count
+}
\ No newline at end of file
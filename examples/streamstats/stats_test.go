@@ -0,0 +1,111 @@
+package streamstats
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateCounterWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rc := NewRateCounterWithNow(5*time.Second, 5, func() time.Time { return now })
+	
+	for range 5 {
+		rc.Add(10)
+		now = now.Add(time.Second)
+	}
+	// Первая секунда уже вышла из окна: остались 4 корзины по 10
+	if got := rc.Count(); got != 40 {
+		t.Errorf("Expected 40 events in window, got %d", got)
+	}
+	
+	now = now.Add(3 * time.Second)
+	if got := rc.Count(); got != 10 {
+		t.Errorf("Expected 10 events after 3s, got %d", got)
+	}
+	
+	// Пропуск дольше окна обнуляет все
+	now = now.Add(time.Hour)
+	if got := rc.Count(); got != 0 {
+		t.Errorf("Expected empty window, got %d", got)
+	}
+	rc.Add(5)
+	if got := rc.Rate(); got != 1 {
+		t.Errorf("Expected rate 1/s, got %v", got)
+	}
+}
+
+func TestEWMAHalfLife(t *testing.T) {
+	start := time.Unix(0, 0)
+	e := NewEWMA(time.Second)
+	e.Observe(0, start)
+	
+	// Через полураспад значение проходит половину пути к новому
+	e.Observe(100, start.Add(time.Second))
+	if got := e.Value(); math.Abs(got-50) > 1e-9 {
+		t.Errorf("Expected 50 after one half-life, got %v", got)
+	}
+	
+	// Частота наблюдений не влияет: 10 шагов по 100мс = 1 полураспад
+	e2 := NewEWMA(time.Second)
+	e2.Observe(0, start)
+	for i := 1; i <= 10; i++ {
+		e2.Observe(100, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	if got := e2.Value(); math.Abs(got-50) > 1e-9 {
+		t.Errorf("Expected 50 regardless of sampling rate, got %v", got)
+	}
+}
+
+func TestP2Accuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	gens := map[string]func() float64{
+		"uniform":   func() float64 { return rng.Float64() * 1000 },
+		"normal":    func() float64 { return 500 + 100*rng.NormFloat64() },
+		"lognormal": func() float64 { return math.Exp(3 + 0.8*rng.NormFloat64()) },
+	}
+	for name, gen := range gens {
+		for _, p := range []float64{0.5, 0.9, 0.99} {
+			t.Run(name+"/"+strconv.FormatFloat(p, 'g', -1, 64), func(t *testing.T) {
+				e := NewP2(p)
+				values := make([]float64, 50000)
+				for i := range values {
+					values[i] = gen()
+					e.Add(values[i])
+				}
+				slices.Sort(values)
+				exact := values[int(p*float64(len(values)-1))]
+				if rel := math.Abs(e.Value()-exact) / exact; rel > 0.05 {
+					t.Errorf("Expected within 5%% of %v, got %v (%.1f%%)", exact, e.Value(), rel*100)
+				}
+			})
+		}
+	}
+}
+
+func TestP2FewValues(t *testing.T) {
+	e := NewP2(0.5)
+	if e.Value() != 0 {
+		t.Errorf("Expected 0 for empty estimator, got %v", e.Value())
+	}
+	for _, v := range []float64{5, 1, 3} {
+		e.Add(v)
+	}
+	if e.Value() != 3 {
+		t.Errorf("Expected exact median 3, got %v", e.Value())
+	}
+}
+
+func TestP2Monotonic(t *testing.T) {
+	// На возрастающей последовательности оценка должна расти вместе с ней
+	e := NewP2(0.5)
+	for i := range 10000 {
+		e.Add(float64(i))
+	}
+	if got := e.Value(); math.Abs(got-5000) > 100 {
+		t.Errorf("Expected median near 5000, got %v", got)
+	}
+}
\ No newline at end of file
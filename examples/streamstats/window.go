@@ -0,0 +1,90 @@
+// Package streamstats потоковая статистика: значения приходят по одному,
+// хранить их все нельзя, а ответ нужен в любой момент. Скользящее окно
+// (RateCounter), EWMA и перцентили P² - общие для examples/loadgen,
+// examples/expvar и examples/dashboard; демонстрация - cmd/streamstats.
+package streamstats
+
+import (
+	"sync"
+	"time"
+)
+
+// RateCounter считает события за скользящее окно ("запросов за последние
+// 10 секунд"). Окно разбито на корзины фиксированной длины; по мере хода
+// времени старые корзины обнуляются и переиспользуются, поэтому память
+// постоянна, а точность - одна корзина. Хранить метку каждого события
+// было бы точнее, но память росла бы вместе с нагрузкой.
+type RateCounter struct {
+	mu      sync.Mutex
+	buckets []int64
+	width   time.Duration // Длина одной корзины
+	head    int           // Индекс корзины текущего момента
+	headAt  time.Time     // Начало корзины head
+	started time.Time
+	now     func() time.Time
+}
+
+// NewRateCounter создает счетчик на окно window из n корзин
+func NewRateCounter(window time.Duration, n int) *RateCounter {
+	return NewRateCounterWithNow(window, n, time.Now)
+}
+
+// NewRateCounterWithNow то же с источником времени now: тесты и
+// примеры двигают время сами, не дожидаясь реальных секунд
+func NewRateCounterWithNow(window time.Duration, n int, now func() time.Time) *RateCounter {
+	width := window / time.Duration(n)
+	start := now()
+	return &RateCounter{
+		buckets: make([]int64, n),
+		width:   width,
+		headAt:  start.Truncate(width),
+		started: start,
+		now:     now,
+	}
+}
+
+// advance сдвигает head к текущему моменту, обнуляя пройденные корзины
+func (c *RateCounter) advance() {
+	now := c.now()
+	steps := int(now.Sub(c.headAt) / c.width)
+	if steps <= 0 {
+		return
+	}
+	for i := range min(steps, len(c.buckets)) {
+		c.buckets[(c.head+1+i)%len(c.buckets)] = 0
+	}
+	c.head = (c.head + steps) % len(c.buckets)
+	c.headAt = c.headAt.Add(time.Duration(steps) * c.width)
+}
+
+// Add учитывает n событий в текущий момент
+func (c *RateCounter) Add(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+	c.buckets[c.head] += n
+}
+
+// Count возвращает число событий в окне
+func (c *RateCounter) Count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+	var sum int64
+	for _, n := range c.buckets {
+		sum += n
+	}
+	return sum
+}
+
+// Rate возвращает событий в секунду, усредненно по окну. Пока счетчик
+// живет меньше окна, делить нужно на прожитое время, иначе в начале
+// rps занижен.
+func (c *RateCounter) Rate() float64 {
+	count := c.Count()
+	window := c.width * time.Duration(len(c.buckets))
+	if age := c.now().Sub(c.started); age < window {
+		window = max(age, c.width)
+	}
+	return float64(count) / window.Seconds()
+}
\ No newline at end of file
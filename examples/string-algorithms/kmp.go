@@ -0,0 +1,57 @@
+package main
+
+import "strings"
+
+// PrefixFunction возвращает для каждой позиции i длину наибольшего
+// собственного префикса pattern[:i+1], который одновременно его суффикс.
+// Для "abab" это [0 0 1 2]: после "abab" совпадение "ab" можно продолжить,
+// не возвращаясь назад.
+func PrefixFunction(pattern string) []int {
+	pi := make([]int, len(pattern))
+	k := 0
+	for i := 1; i < len(pattern); i++ {
+		for k > 0 && pattern[i] != pattern[k] {
+			k = pi[k-1]
+		}
+		if pattern[i] == pattern[k] {
+			k++
+		}
+		pi[i] = k
+	}
+	return pi
+}
+
+// KMPSearch находит все вхождения pattern в text за O(n+m) (Кнут-Моррис-Пратт).
+// Наивный поиск при несовпадении сдвигает образец на 1 и сравнивает заново -
+// O(n·m) на строках вроде "aaaa...ab". KMP по префикс-функции знает, какая
+// часть образца уже совпала, и никогда не двигает указатель по тексту назад.
+// Возвращает байтовые смещения.
+func KMPSearch(text, pattern string, t *Tracer) []int {
+	if pattern == "" {
+		return nil
+	}
+	pi := PrefixFunction(pattern)
+	t.Printf("префикс-функция %q: %v\n", pattern, pi)
+	
+	var matches []int
+	k := 0 // Сколько символов образца совпало
+	for i := 0; i < len(text); i++ {
+		for k > 0 && text[i] != pattern[k] {
+			t.Printf("  i=%-2d %q != %q, откат k: %d -> %d\n", i, text[i], pattern[k], k, pi[k-1])
+			k = pi[k-1]
+		}
+		if text[i] == pattern[k] {
+			k++
+		}
+		if t.Enabled() {
+			t.Printf("  i=%-2d %s|%s  совпало %d\n", i, text[:i+1], strings.Repeat(" ", len(text)-i-1), k)
+		}
+		if k == len(pattern) {
+			start := i - len(pattern) + 1
+			t.Printf("  вхождение с позиции %d\n", start)
+			matches = append(matches, start)
+			k = pi[k-1]
+		}
+	}
+	return matches
+}
\ No newline at end of file
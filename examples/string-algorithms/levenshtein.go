@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Levenshtein возвращает редакционное расстояние - минимальное число
+// вставок, удалений и замен символов, превращающих a в b. Работает с
+// рунами, поэтому "ё" и "е" - одна замена, а не две байтовые.
+//
+// d[i][j] - расстояние между a[:i] и b[:j]:
+//
+//	d[i][j] = min(d[i-1][j] + 1,         // удаление a[i-1]
+//	              d[i][j-1] + 1,         // вставка b[j-1]
+//	              d[i-1][j-1] + cost)    // замена, cost = 0 при равенстве
+//
+// Для ответа нужна только предыдущая строка таблицы, поэтому память
+// O(min(len(a), len(b))), время O(len(a)·len(b)).
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra // Строка таблицы по более короткому слову
+	}
+	
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// LevenshteinTable строит полную таблицу и восстанавливает по ней
+// последовательность правок - то, что показывают diff-утилиты
+func LevenshteinTable(a, b string, t *Tracer) (int, []string) {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+	
+	if t.Enabled() {
+		var sb strings.Builder
+		sb.WriteString("     ε")
+		for _, r := range rb {
+			fmt.Fprintf(&sb, "%3c", r)
+		}
+		sb.WriteString("\n")
+		for i := range d {
+			label := 'ε'
+			if i > 0 {
+				label = ra[i-1]
+			}
+			fmt.Fprintf(&sb, "  %c", label)
+			for _, v := range d[i] {
+				fmt.Fprintf(&sb, "%3d", v)
+			}
+			sb.WriteString("\n")
+		}
+		t.Printf("%s", sb.String())
+	}
+	
+	// Обратный проход от правого нижнего угла
+	var ops []string
+	i, j := len(ra), len(rb)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && ra[i-1] == rb[j-1] && d[i][j] == d[i-1][j-1]:
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			ops = append(ops, fmt.Sprintf("заменить %c на %c (позиция %d)", ra[i-1], rb[j-1], i-1))
+			i, j = i-1, j-1
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			ops = append(ops, fmt.Sprintf("удалить %c (позиция %d)", ra[i-1], i-1))
+			i--
+		default:
+			ops = append(ops, fmt.Sprintf("вставить %c (позиция %d)", rb[j-1], i))
+			j--
+		}
+	}
+	// Правки собраны с конца; в этом порядке позиции не сдвигаются
+	return d[len(ra)][len(rb)], ops
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Пример 1: KMP по шагам
+func kmp() {
+	fmt.Println("=== Кнут-Моррис-Пратт ===")
+	
+	matches := KMPSearch("abababca", "abab", NewTracer(os.Stdout))
+	fmt.Println("Вхождения:", matches)
+	
+	fmt.Println("\nПрефикс-функции:")
+	for _, p := range []string{"aaaa", "abcab", "abacaba"} {
+		fmt.Printf("  %-8s %v\n", p, PrefixFunction(p))
+	}
+}
+
+// Пример 2: Рабин-Карп по шагам
+func rabinKarp() {
+	fmt.Println("\n=== Рабин-Карп ===")
+	
+	matches := RabinKarpSearch("GET /api/users/api", "api", NewTracer(os.Stdout))
+	fmt.Println("Вхождения:", matches)
+}
+
+// Пример 3: Расстояние Левенштейна и восстановление правок
+func levenshtein() {
+	fmt.Println("\n=== Левенштейн ===")
+	
+	d, ops := LevenshteinTable("котик", "скотина", NewTracer(os.Stdout))
+	fmt.Println("Расстояние:", d)
+	for _, op := range ops {
+		fmt.Println(" ", op)
+	}
+	
+	for _, pair := range [][2]string{{"kitten", "sitting"}, {"Мария", "Марья"}, {"", "abc"}} {
+		fmt.Printf("%q -> %q: %d\n", pair[0], pair[1], Levenshtein(pair[0], pair[1]))
+	}
+}
+
+var users = []User{
+	{1, "Иван Иванов", "ivan@example.com"},
+	{2, "Мария Петрова", "maria@example.com"},
+	{3, "Марина Петренко", "marina@example.com"},
+	{4, "Пётр Сидоров", "petr@example.com"},
+	{5, "Ирина Иванова", "irina@example.com"},
+}
+
+// usersHandler GET /api/users?search=... - нечеткий поиск по имени
+func usersHandler(users []User) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		search := r.URL.Query().Get("search")
+		if search == "" {
+			json.NewEncoder(w).Encode(users)
+			return
+		}
+		if len(search) > 100 {
+			// Левенштейн квадратичен по длине - длинный запрос это DoS
+			http.Error(w, "Search query too long", http.StatusBadRequest)
+			return
+		}
+		
+		matches := SearchUsers(users, search)
+		if matches == nil {
+			matches = []Match{}
+		}
+		json.NewEncoder(w).Encode(matches)
+	}
+}
+
+// Пример 4: Нечеткий поиск пользователей
+func fuzzySearch() {
+	fmt.Println("\n=== GET /api/users?search= ===")
+	
+	server := httptest.NewServer(usersHandler(users))
+	defer server.Close()
+	
+	for _, q := range []string{"иванов", "петрва", "мари", "сидорв", "ирна", "смирнов"} {
+		resp, err := http.Get(server.URL + "/api/users?search=" + url.QueryEscape(q))
+		if err != nil {
+			log.Fatal(err)
+		}
+		var matches []Match
+		json.NewDecoder(resp.Body).Decode(&matches)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		
+		var found []string
+		for _, m := range matches {
+			found = append(found, fmt.Sprintf("%s(%d)", m.Name, m.Distance))
+		}
+		fmt.Printf("%-10s %s\n", q, strings.Join(found, ", "))
+	}
+}
+
+func main() {
+	kmp()
+	rabinKarp()
+	levenshtein()
+	fuzzySearch()
+}
\ No newline at end of file
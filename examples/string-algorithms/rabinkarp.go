@@ -0,0 +1,57 @@
+package main
+
+// Параметры полиномиального хеша: основание и простой модуль. Модуль
+// меньше 2^32, поэтому произведение двух остатков помещается в uint64.
+const (
+	rkBase = 256
+	rkMod  = 4294967291 // Наибольшее простое меньше 2^32
+)
+
+// RabinKarpSearch находит все вхождения pattern в text скользящим хешем:
+// хеш следующего окна получается из предыдущего за O(1) - убрать старший
+// символ, сдвинуть, добавить новый. Совпадение хешей проверяется
+// сравнением строк, так как разные строки могут иметь одинаковый хеш.
+// В среднем O(n+m); главное преимущество - поиск сразу многих образцов
+// одной длины (сравнение хеша окна с множеством хешей).
+func RabinKarpSearch(text, pattern string, t *Tracer) []int {
+	n, m := len(text), len(pattern)
+	if m == 0 || m > n {
+		return nil
+	}
+	
+	// high = base^(m-1) mod p - вес старшего символа окна
+	high := uint64(1)
+	for range m - 1 {
+		high = high * rkBase % rkMod
+	}
+	
+	var hp, hw uint64
+	for i := range m {
+		hp = (hp*rkBase + uint64(pattern[i])) % rkMod
+		hw = (hw*rkBase + uint64(text[i])) % rkMod
+	}
+	t.Printf("хеш образца %q = %d\n", pattern, hp)
+	
+	var matches []int
+	for i := 0; ; i++ {
+		t.Printf("  окно %d %q хеш %d", i, text[i:i+m], hw)
+		if hw == hp {
+			if text[i:i+m] == pattern {
+				t.Printf("  совпадение\n")
+				matches = append(matches, i)
+			} else {
+				t.Printf("  коллизия хеша\n")
+			}
+		} else {
+			t.Printf("\n")
+		}
+		
+		if i+m >= n {
+			break
+		}
+		// Прибавляем rkMod перед вычитанием, чтобы не уйти в минус
+		hw = (hw + rkMod - uint64(text[i])*high%rkMod) % rkMod
+		hw = (hw*rkBase + uint64(text[i+m])) % rkMod
+	}
+	return matches
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// User пользователь
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Match пользователь с расстоянием до запроса
+type Match struct {
+	User
+	Distance int `json:"distance"`
+}
+
+// SearchUsers ищет пользователей по имени с допуском опечаток и
+// сортирует по близости. Точное вхождение подстроки - расстояние 0.
+// Иначе запрос сравнивается с началом каждого слова имени: "петрва"
+// находит "Петрова" (одна пропущенная буква), а "иван" - "Иванов".
+func SearchUsers(users []User, query string) []Match {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil
+	}
+	qLen := utf8.RuneCountInString(q)
+	maxDist := max(1, qLen/3) // Одна опечатка на каждые три символа
+	
+	var matches []Match
+	for _, u := range users {
+		if d, ok := nameDistance(strings.ToLower(u.Name), q, qLen); ok && d <= maxDist {
+			matches = append(matches, Match{User: u, Distance: d})
+		}
+	}
+	slices.SortFunc(matches, func(a, b Match) int {
+		return cmp.Or(cmp.Compare(a.Distance, b.Distance), strings.Compare(a.Name, b.Name))
+	})
+	return matches
+}
+
+func nameDistance(name, q string, qLen int) (int, bool) {
+	if len(KMPSearch(name, q, nil)) > 0 {
+		return 0, true
+	}
+	best, found := 0, false
+	for _, word := range strings.Fields(name) {
+		// Префикс слова той же длины плюс-минус одна руна: запрос
+		// может быть короче слова (автодополнение) или с лишней буквой
+		runes := []rune(word)
+		for _, n := range []int{qLen - 1, qLen, qLen + 1} {
+			if n <= 0 || n > len(runes) {
+				continue
+			}
+			d := Levenshtein(string(runes[:n]), q)
+			if !found || d < best {
+				best, found = d, true
+			}
+		}
+	}
+	return best, found
+}
\ No newline at end of file
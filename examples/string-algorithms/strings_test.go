@@ -0,0 +1,131 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// naiveSearch - эталон для сверки
+func naiveSearch(text, pattern string) []int {
+	var matches []int
+	for i := 0; i+len(pattern) <= len(text) && pattern != ""; i++ {
+		if text[i:i+len(pattern)] == pattern {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func TestSearchAlgorithms(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+	}{
+		{"abababca", "abab"},
+		{"aaaaaaab", "aab"},
+		{"hello", "world"},
+		{"abc", ""},
+		{"ab", "abc"},
+		{"привет, мир, мир", "мир"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text+"/"+tt.pattern, func(t *testing.T) {
+			want := naiveSearch(tt.text, tt.pattern)
+			if got := KMPSearch(tt.text, tt.pattern, nil); !slices.Equal(got, want) {
+				t.Errorf("KMP: expected %v, got %v", want, got)
+			}
+			if got := RabinKarpSearch(tt.text, tt.pattern, nil); !slices.Equal(got, want) {
+				t.Errorf("Rabin-Karp: expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSearchRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "ab"[rng.Intn(2)] // Маленький алфавит - много частичных совпадений
+		}
+		return string(b)
+	}
+	for range 500 {
+		text, pattern := randString(rng.Intn(50)), randString(1+rng.Intn(5))
+		want := naiveSearch(text, pattern)
+		if got := KMPSearch(text, pattern, nil); !slices.Equal(got, want) {
+			t.Fatalf("KMP(%q, %q): expected %v, got %v", text, pattern, want, got)
+		}
+		if got := RabinKarpSearch(text, pattern, nil); !slices.Equal(got, want) {
+			t.Fatalf("Rabin-Karp(%q, %q): expected %v, got %v", text, pattern, want, got)
+		}
+	}
+}
+
+func TestPrefixFunction(t *testing.T) {
+	if got := PrefixFunction("abacaba"); !slices.Equal(got, []int{0, 0, 1, 0, 1, 2, 3}) {
+		t.Errorf("Expected [0 0 1 0 1 2 3], got %v", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"Мария", "Марья", 1},
+		{"ёж", "еж", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+			if got := Levenshtein(tt.b, tt.a); got != tt.want {
+				t.Errorf("Expected symmetric distance %d, got %d", tt.want, got)
+			}
+			d, ops := LevenshteinTable(tt.a, tt.b, nil)
+			if d != tt.want || len(ops) != tt.want {
+				t.Errorf("Table: expected %d with %d ops, got %d with %v", tt.want, tt.want, d, ops)
+			}
+		})
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int // ID в порядке ранжирования
+	}{
+		{"иванов", []int{1, 5}},    // Подстрока в обоих
+		{"петрва", []int{2, 3}},    // Пропущена буква; Петренко дальше
+		{"ИВАН", []int{1, 5}},      // Регистр не важен
+		{"смирнов", nil},           // Нет похожих
+		{"марина", []int{3, 5, 2}}, // Точное вхождение выше опечаток
+		{"   ", nil},               // Пустой запрос
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var ids []int
+			for _, m := range SearchUsers(users, tt.query) {
+				ids = append(ids, m.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, ids)
+			}
+		})
+	}
+}
+
+func TestTracerOutput(t *testing.T) {
+	var sb strings.Builder
+	KMPSearch("aab", "ab", NewTracer(&sb))
+	if !strings.Contains(sb.String(), "вхождение с позиции 1") {
+		t.Errorf("Expected trace to mention match, got:\n%s", sb.String())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// Tracer печатает ход алгоритма по шагам. Нулевой указатель - трассировка
+// выключена, поэтому алгоритмы вызывают Printf без проверок.
+type Tracer struct {
+	w io.Writer
+}
+
+// NewTracer создает трассировщик, пишущий в w
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+// Printf выводит строку трассировки
+func (t *Tracer) Printf(format string, args ...any) {
+	if t == nil {
+		return
+	}
+	fmt.Fprintf(t.w, format, args...)
+}
+
+// Enabled сообщает, включена ли трассировка (чтобы не готовить дорогой вывод зря)
+func (t *Tracer) Enabled() bool {
+	return t != nil
+}
\ No newline at end of file
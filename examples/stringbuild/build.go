@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// row строка выгрузки пользователей: то, что отдают страница /users и
+// CSV-экспорт в examples/http-server
+type row struct {
+	ID     int
+	Name   string
+	Email  string
+	Active bool
+}
+
+func makeRows(n int) []row {
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i] = row{
+			ID:     i + 1,
+			Name:   "Пользователь " + strconv.Itoa(i+1),
+			Email:  "user" + strconv.Itoa(i+1) + "@example.com",
+			Active: i%3 != 0,
+		}
+	}
+	return rows
+}
+
+// Шесть способов собрать один и тот же CSV. Поля здесь без запятых и
+// кавычек; для произвольных данных нужен encoding/csv - он, как и
+// writeCSV, пишет в io.Writer.
+
+// csvPlus + в цикле: каждая итерация копирует всю строку заново, время
+// и память растут как n²
+func csvPlus(rows []row) string {
+	s := "id,name,email,active\n"
+	for _, r := range rows {
+		s += strconv.Itoa(r.ID) + "," + r.Name + "," + r.Email + "," + strconv.FormatBool(r.Active) + "\n"
+	}
+	return s
+}
+
+// csvSprintf fmt.Sprintf в цикле: та же квадратичная склейка плюс
+// разбор формата и упаковка аргументов в interface
+func csvSprintf(rows []row) string {
+	s := "id,name,email,active\n"
+	for _, r := range rows {
+		s += fmt.Sprintf("%d,%s,%s,%t\n", r.ID, r.Name, r.Email, r.Active)
+	}
+	return s
+}
+
+// csvFprintf fmt.Fprintf в strings.Builder: склейка линейная, но
+// fmt по-прежнему выделяет память на аргументы
+func csvFprintf(rows []row) string {
+	var b strings.Builder
+	b.WriteString("id,name,email,active\n")
+	for _, r := range rows {
+		fmt.Fprintf(&b, "%d,%s,%s,%t\n", r.ID, r.Name, r.Email, r.Active)
+	}
+	return b.String()
+}
+
+// csvBuilder strings.Builder без Grow: буфер растет удвоением, старые
+// копии уходят сборщику мусора - около log2(n) выделений
+func csvBuilder(rows []row) string {
+	var b strings.Builder
+	writeCSV(&b, rows)
+	return b.String()
+}
+
+// csvBuilderGrow strings.Builder с Grow по точному размеру: одно
+// выделение под результат, и String() отдает его без копирования
+func csvBuilderGrow(rows []row) string {
+	var b strings.Builder
+	b.Grow(csvSize(rows))
+	writeCSV(&b, rows)
+	return b.String()
+}
+
+// csvBuffer bytes.Buffer с Grow: столько же выделений при записи, но
+// String() копирует - у Builder такой копии нет. Buffer нужен, когда
+// данные еще и читают (он io.Reader) или переиспользуют через Reset.
+func csvBuffer(rows []row) string {
+	var b bytes.Buffer
+	b.Grow(csvSize(rows))
+	writeCSV(&b, rows)
+	return b.String()
+}
+
+// csvWriter общее у strings.Builder и bytes.Buffer
+type csvWriter interface {
+	io.Writer
+	io.StringWriter
+}
+
+// writeCSV пишет CSV в w. Числа форматируются в один буфер на весь
+// вызов: strconv.Itoa вернул бы новую строку на каждую строку CSV.
+func writeCSV(w csvWriter, rows []row) {
+	w.WriteString("id,name,email,active\n")
+	var num [20]byte
+	for _, r := range rows {
+		w.Write(strconv.AppendInt(num[:0], int64(r.ID), 10))
+		w.WriteString(",")
+		w.WriteString(r.Name)
+		w.WriteString(",")
+		w.WriteString(r.Email)
+		w.WriteString(",")
+		w.WriteString(strconv.FormatBool(r.Active))
+		w.WriteString("\n")
+	}
+}
+
+// csvSize точный размер CSV: посчитать дешевле, чем копировать при росте
+func csvSize(rows []row) int {
+	n := len("id,name,email,active\n")
+	for _, r := range rows {
+		n += intLen(r.ID) + len(r.Name) + len(r.Email) + len("false") + 4
+		if r.Active {
+			n--
+		}
+	}
+	return n
+}
+
+// intLen число цифр в неотрицательном n
+func intLen(n int) int {
+	l := 1
+	for n >= 10 {
+		n /= 10
+		l++
+	}
+	return l
+}
+
+// htmlTable таблица для страницы пользователей. Тот же Builder с Grow,
+// но размер оценен с запасом: после экранирования его не узнать
+// заранее, а лишние байты дешевле лишнего удвоения.
+func htmlTable(rows []row) string {
+	var b strings.Builder
+	b.Grow(128 + len(rows)*128)
+	b.WriteString("<table>\n<tr><th>ID</th><th>Имя</th><th>Email</th></tr>\n")
+	var num [20]byte
+	for _, r := range rows {
+		b.WriteString("<tr><td>")
+		b.Write(strconv.AppendInt(num[:0], int64(r.ID), 10))
+		b.WriteString("</td><td>")
+		b.WriteString(html.EscapeString(r.Name))
+		b.WriteString("</td><td>")
+		b.WriteString(html.EscapeString(r.Email))
+		b.WriteString("</td></tr>\n")
+	}
+	b.WriteString("</table>\n")
+	return b.String()
+}
+
+// tableTmpl та же таблица через html/template, как страницы в
+// examples/http-server: экранирование по контексту и reflect на каждое
+// поле - медленнее, но безопаснее ручной склейки
+var tableTmpl = template.Must(template.New("table").Parse(`<table>
+<tr><th>ID</th><th>Имя</th><th>Email</th></tr>
+{{range .}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.Email}}</td></tr>
+{{end}}</table>
+`))
+
+func htmlTemplate(w io.Writer, rows []row) error {
+	return tableTmpl.Execute(w, rows)
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestVariantsSameOutput(t *testing.T) {
+	for _, n := range []int{0, 1, 9, 10, 100, 1000} {
+		rows := makeRows(n)
+		expected := csvBuilderGrow(rows)
+		// Grow по точному размеру: ошибка в csvSize дала бы лишнее
+		// удвоение, а не неверный результат, поэтому проверяется отдельно
+		if len(expected) != csvSize(rows) {
+			t.Errorf("%d rows: expected csvSize %d, got %d", n, len(expected), csvSize(rows))
+		}
+		for _, v := range variants {
+			if got := v.fn(rows); got != expected {
+				t.Errorf("%s, %d rows: expected\n%s\ngot\n%s", v.name, n, expected, got)
+			}
+		}
+	}
+}
+
+func TestHTMLTable_MatchesTemplate(t *testing.T) {
+	rows := []row{
+		{ID: 1, Name: `<script>alert("x")</script>`, Email: "a&b@example.com"},
+		{ID: 22, Name: "O'Brien", Email: "ob@example.com"},
+	}
+	var tmpl strings.Builder
+	if err := htmlTemplate(&tmpl, rows); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := htmlTable(rows)
+	if got != tmpl.String() {
+		t.Errorf("Expected\n%s\ngot\n%s", tmpl.String(), got)
+	}
+	if strings.Contains(got, "<script>") {
+		t.Error("Expected name to be escaped")
+	}
+}
+
+func TestCSVHandlers(t *testing.T) {
+	rows := makeRows(5000) // Больше буфера bufio
+	expected := csvBuilderGrow(rows)
+	for name, h := range map[string]http.HandlerFunc{
+		"string": csvStringHandler(rows),
+		"stream": csvStreamHandler(rows),
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h(w, httptest.NewRequest(http.MethodGet, "/users.csv", nil))
+			if w.Body.String() != expected {
+				t.Error("Expected response body to match CSV")
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+				t.Errorf("Expected text/csv, got %q", ct)
+			}
+		})
+	}
+}
+
+var sinkString string
+
+// go test -run=^$ -bench=CSV -benchmem
+func BenchmarkCSV(b *testing.B) {
+	for _, v := range []struct {
+		name string
+		fn   func([]row) string
+	}{
+		{"Plus", csvPlus},
+		{"Sprintf", csvSprintf},
+		{"Fprintf", csvFprintf},
+		{"Builder", csvBuilder},
+		{"BuilderGrow", csvBuilderGrow},
+		{"Buffer", csvBuffer},
+	} {
+		for _, n := range []int{10, 100, 1000} {
+			rows := makeRows(n)
+			b.Run(v.name+"/"+strconv.Itoa(n), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(csvSize(rows)))
+				for i := 0; i < b.N; i++ {
+					sinkString = v.fn(rows)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkHTML(b *testing.B) {
+	rows := makeRows(1000)
+	b.Run("Builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = htmlTable(rows)
+		}
+	})
+	b.Run("Template", func(b *testing.B) {
+		b.ReportAllocs()
+		w := discardResponse{h: http.Header{}}
+		for i := 0; i < b.N; i++ {
+			htmlTemplate(w, rows)
+		}
+	})
+}
\ No newline at end of file
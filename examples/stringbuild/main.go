@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Склейка строк: + в цикле, fmt.Sprintf, strings.Builder с Grow и без,
+// bytes.Buffer - на задаче, которая встречается в каждом веб-сервисе:
+// собрать большой CSV-экспорт или HTML-таблицу пользователей.
+//
+//   - build.go - варианты сборки CSV и HTML;
+//   - build_test.go - проверка, что все варианты дают одно и то же, и
+//     бенчмарки по размерам.
+//
+// Итог коротко:
+//   - + и Sprintf в цикле квадратичны: на 10 строках незаметно, на
+//     тысячах - сотни мегабайт мусора на один ответ;
+//   - strings.Builder линеен; Grow по известному размеру убирает
+//     промежуточные копии, String() не копирует результат;
+//   - bytes.Buffer так же быстр при записи, но String() копирует;
+//   - для HTTP-ответа строка целиком вообще не нужна: писать сразу в
+//     ResponseWriter (через bufio) - память не зависит от размера.
+//
+// Запуск: go run .
+// Профиль памяти: go run . -memprofile mem.out
+//
+//	go tool pprof -sample_index=alloc_space -top mem.out
+//
+// Бенчмарки и профиль из них:
+//
+//	go test -run=^$ -bench=. -benchmem
+//	go test -run=^$ -bench=CSV/Plus/1000 -memprofile mem.out
+//	go tool pprof -sample_index=alloc_space -list=csvPlus mem.out
+
+type variant struct {
+	name string
+	fn   func([]row) string
+}
+
+var variants = []variant{
+	{"+ в цикле", csvPlus},
+	{"Sprintf в цикле", csvSprintf},
+	{"Fprintf в Builder", csvFprintf},
+	{"Builder", csvBuilder},
+	{"Builder + Grow", csvBuilderGrow},
+	{"bytes.Buffer + Grow", csvBuffer},
+}
+
+// Пример 1: Все варианты дают один и тот же результат
+func sameOutput() {
+	fmt.Println("=== Один и тот же результат ===")
+	
+	rows := makeRows(3)
+	expected := csvBuilderGrow(rows)
+	fmt.Print(expected)
+	for _, v := range variants {
+		if got := v.fn(rows); got != expected {
+			fmt.Printf("%s: расхождение\n", v.name)
+		}
+	}
+	
+	var tmpl strings.Builder
+	if err := htmlTemplate(&tmpl, rows); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("HTML вручную и через html/template совпадает: %v\n", tmpl.String() == htmlTable(rows))
+}
+
+// Пример 2: Время и выделения на 100 и 2000 строк. Рост в 20 раз по
+// строкам дает рост в ~20 раз у линейных вариантов и в сотни раз у
+// квадратичных.
+func compare() {
+	fmt.Println("\n=== Время и память ===")
+	
+	for _, n := range []int{100, 2000} {
+		rows := makeRows(n)
+		fmt.Printf("%d строк (%d КБ):\n", n, csvSize(rows)/1024)
+		for _, v := range variants {
+			r := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					v.fn(rows)
+				}
+			})
+			fmt.Printf("  %-20s %10d нс/op %10d Б/op %6d allocs/op\n",
+				v.name, r.NsPerOp(), r.AllocedBytesPerOp(), r.AllocsPerOp())
+		}
+	}
+}
+
+// Пример 3: Сколько памяти выделено на один экспорт. runtime.MemStats
+// считает все выделения, в том числе уже собранные сборщиком мусора -
+// это и есть нагрузка на GC. С флагом -memprofile то же видно в pprof
+// с точностью до строки кода. Профиль - выборка (по умолчанию одно
+// выделение на 512 КБ), но сотни мегабайт от + в нее попадают наверняка.
+func allocations(profile string) {
+	fmt.Println("\n=== Выделено памяти на экспорт 5000 строк ===")
+	
+	rows := makeRows(5000)
+	size := csvSize(rows)
+	for _, v := range variants {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		v.fn(rows)
+		runtime.ReadMemStats(&after)
+		total := after.TotalAlloc - before.TotalAlloc
+		fmt.Printf("  %-20s %8.1f МБ (%.0f× размер CSV)\n",
+			v.name, float64(total)/(1<<20), float64(total)/float64(size))
+	}
+	
+	if profile == "" {
+		return
+	}
+	f, err := os.Create(profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	// allocs - все выделения с начала программы; heap - только живые
+	if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Профиль записан: go tool pprof -sample_index=alloc_space -top %s\n", profile)
+}
+
+// csvStringHandler собирает ответ целиком и отдает одной записью:
+// Content-Length известен, но в памяти - весь файл
+func csvStringHandler(rows []row) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := csvBuilderGrow(rows)
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Length", strconv.Itoa(len(s)))
+		io.WriteString(w, s)
+	}
+}
+
+// csvStreamHandler пишет CSV сразу в ответ через bufio: в памяти только
+// буфер в 32 КБ, сколько бы ни было строк. Ответ уходит с
+// Transfer-Encoding: chunked.
+func csvStreamHandler(rows []row) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		bw := bufio.NewWriterSize(w, 32<<10)
+		writeCSV(bw, rows)
+		bw.Flush()
+	}
+}
+
+// htmlTemplateHandler как страницы в examples/http-server
+func htmlTemplateHandler(rows []row) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		htmlTemplate(w, rows)
+	}
+}
+
+func htmlStringHandler(rows []row) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, htmlTable(rows))
+	}
+}
+
+// discardResponse ResponseWriter без хранения тела: httptest.Recorder
+// сам копил бы ответ, и это попало бы в замер. WriteString есть и у
+// настоящего ResponseWriter сервера: без него io.WriteString копировал
+// бы строку в []byte.
+type discardResponse struct{ h http.Header }
+
+func (d discardResponse) Header() http.Header             { return d.h }
+func (discardResponse) Write(p []byte) (int, error)       { return len(p), nil }
+func (discardResponse) WriteString(s string) (int, error) { return len(s), nil }
+func (discardResponse) WriteHeader(int)                   {}
+
+// Пример 4: Большой ответ HTTP - собрать строку или писать потоком
+func httpResponses() {
+	fmt.Println("\n=== Ответ HTTP на 20000 строк ===")
+	
+	rows := makeRows(20000)
+	req, _ := http.NewRequest(http.MethodGet, "/users.csv", nil)
+	for _, h := range []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"CSV строкой", csvStringHandler(rows)},
+		{"CSV потоком", csvStreamHandler(rows)},
+		{"HTML Builder", htmlStringHandler(rows)},
+		{"HTML html/template", htmlTemplateHandler(rows)},
+	} {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				h.handler(discardResponse{h: http.Header{}}, req)
+			}
+		})
+		fmt.Printf("  %-20s %8.2f мс/op %8d КБ/op %7d allocs/op\n",
+			h.name, float64(r.NsPerOp())/1e6, r.AllocedBytesPerOp()/1024, r.AllocsPerOp())
+	}
+	fmt.Println("html/template дороже, зато экранирует по контексту сам; ручная склейка HTML - только там, где профиль показал шаблон")
+}
+
+func main() {
+	memprofile := flag.String("memprofile", "", "записать профиль выделений памяти в файл")
+	flag.Parse()
+	
+	sameOutput()
+	compare()
+	allocations(*memprofile)
+	httpResponses()
+}
\ No newline at end of file
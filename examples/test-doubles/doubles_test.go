@@ -0,0 +1,215 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// ---------- Fake: рабочая реализация в памяти ----------
+
+type FakeUserRepository struct {
+	users map[int]string
+}
+
+func NewFakeUserRepository() *FakeUserRepository {
+	return &FakeUserRepository{users: make(map[int]string)}
+}
+
+func (f *FakeUserRepository) GetUser(id int) (string, error) {
+	name, ok := f.users[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return name, nil
+}
+
+func (f *FakeUserRepository) SaveUser(id int, name string) error {
+	f.users[id] = name
+	return nil
+}
+
+// Пример 1: Fake - проверяем результат сценария, а не вызовы
+func TestUserService_Fake(t *testing.T) {
+	service := NewUserService(NewFakeUserRepository())
+	
+	if err := service.Register(1, "  Иван "); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	greeting, err := service.Greeting(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if greeting != "Привет, Иван!" {
+		t.Errorf("Expected 'Привет, Иван!', got '%s'", greeting)
+	}
+	
+	if err := service.Register(1, "Петр"); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+// ---------- Stub: заготовленные ответы ----------
+
+type StubUserRepository struct {
+	name    string
+	getErr  error
+	saveErr error
+}
+
+func (s StubUserRepository) GetUser(id int) (string, error) {
+	return s.name, s.getErr
+}
+
+func (s StubUserRepository) SaveUser(id int, name string) error {
+	return s.saveErr
+}
+
+// Пример 2: Stub - ветки с ошибками, которые на fake не воспроизвести
+func TestUserService_Stub(t *testing.T) {
+	dbDown := errors.New("connection refused")
+	
+	tests := []struct {
+		name     string
+		repo     StubUserRepository
+		expected string
+		wantErr  error
+	}{
+		{"known user", StubUserRepository{name: "Мария"}, "Привет, Мария!", nil},
+		{"unknown user", StubUserRepository{getErr: ErrNotFound}, "Привет, гость!", nil},
+		{"database down", StubUserRepository{getErr: dbDown}, "", dbDown},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			greeting, err := NewUserService(tt.repo).Greeting(1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if greeting != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, greeting)
+			}
+		})
+	}
+	
+	t.Run("save error is propagated", func(t *testing.T) {
+		repo := StubUserRepository{getErr: ErrNotFound, saveErr: dbDown}
+		if err := NewUserService(repo).Register(1, "Иван"); !errors.Is(err, dbDown) {
+			t.Errorf("Expected %v, got %v", dbDown, err)
+		}
+	})
+}
+
+// ---------- Spy: записывает вызовы для последующих проверок ----------
+
+type saveCall struct {
+	id   int
+	name string
+}
+
+type SpyUserRepository struct {
+	FakeUserRepository
+	getCalls  []int
+	saveCalls []saveCall
+}
+
+func NewSpyUserRepository() *SpyUserRepository {
+	return &SpyUserRepository{FakeUserRepository: *NewFakeUserRepository()}
+}
+
+func (s *SpyUserRepository) GetUser(id int) (string, error) {
+	s.getCalls = append(s.getCalls, id)
+	return s.FakeUserRepository.GetUser(id)
+}
+
+func (s *SpyUserRepository) SaveUser(id int, name string) error {
+	s.saveCalls = append(s.saveCalls, saveCall{id, name})
+	return s.FakeUserRepository.SaveUser(id, name)
+}
+
+// Пример 3: Spy - проверяем, ЧТО было сохранено и что лишнего не было
+func TestUserService_Spy(t *testing.T) {
+	spy := NewSpyUserRepository()
+	service := NewUserService(spy)
+	
+	service.Register(1, "  Иван  ")
+	service.Register(1, "Петр") // дубликат - сохранения быть не должно
+	service.Register(2, "   ")  // пустое имя - репозиторий не трогаем
+	
+	if len(spy.saveCalls) != 1 {
+		t.Fatalf("Expected 1 SaveUser call, got %d: %v", len(spy.saveCalls), spy.saveCalls)
+	}
+	if spy.saveCalls[0] != (saveCall{1, "Иван"}) {
+		t.Errorf("Expected name to be trimmed, got %+v", spy.saveCalls[0])
+	}
+	if len(spy.getCalls) != 2 {
+		t.Errorf("Expected 2 GetUser calls, got %d", len(spy.getCalls))
+	}
+}
+
+// ---------- Mock: ожидания задаются заранее и проверяются самим моком ----------
+
+type expectation struct {
+	method string
+	args   []any
+	ret    []any
+}
+
+type MockUserRepository struct {
+	t        *testing.T
+	expected []expectation
+	calls    int
+}
+
+func NewMockUserRepository(t *testing.T) *MockUserRepository {
+	m := &MockUserRepository{t: t}
+	// Невыполненные ожидания проверяются по завершении теста
+	t.Cleanup(func() {
+		if m.calls < len(m.expected) {
+			t.Errorf("Не выполнены ожидания: %v", m.expected[m.calls:])
+		}
+	})
+	return m
+}
+
+func (m *MockUserRepository) Expect(method string, args []any, ret ...any) *MockUserRepository {
+	m.expected = append(m.expected, expectation{method, args, ret})
+	return m
+}
+
+func (m *MockUserRepository) next(method string, args ...any) []any {
+	m.t.Helper()
+	if m.calls >= len(m.expected) {
+		m.t.Fatalf("Неожиданный вызов %s%v", method, args)
+	}
+	exp := m.expected[m.calls]
+	m.calls++
+	if exp.method != method || fmt.Sprintf("%v", exp.args) != fmt.Sprintf("%v", args) {
+		m.t.Fatalf("Ожидался %s%v, получен %s%v", exp.method, exp.args, method, args)
+	}
+	return exp.ret
+}
+
+func (m *MockUserRepository) GetUser(id int) (string, error) {
+	ret := m.next("GetUser", id)
+	err, _ := ret[1].(error)
+	return ret[0].(string), err
+}
+
+func (m *MockUserRepository) SaveUser(id int, name string) error {
+	ret := m.next("SaveUser", id, name)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// Пример 4: Mock - строгий протокол: сначала проверка, затем ровно одно сохранение
+func TestUserService_Mock(t *testing.T) {
+	mock := NewMockUserRepository(t).
+		Expect("GetUser", []any{1}, "", ErrNotFound).
+		Expect("SaveUser", []any{1, "Иван"}, nil)
+	
+	if err := NewUserService(mock).Register(1, "Иван"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound пользователь не найден
+var ErrNotFound = errors.New("пользователь не найден")
+
+// ErrAlreadyExists пользователь уже существует
+var ErrAlreadyExists = errors.New("пользователь уже существует")
+
+// UserRepository зависимость, которую подменяют в тестах
+type UserRepository interface {
+	GetUser(id int) (string, error)
+	SaveUser(id int, name string) error
+}
+
+// UserService получает репозиторий через конструктор (DI),
+// поэтому в тестах ему можно передать любой из дублеров
+type UserService struct {
+	repo UserRepository
+}
+
+// NewUserService создает сервис
+func NewUserService(repo UserRepository) *UserService {
+	return &UserService{repo: repo}
+}
+
+// Register создает пользователя, если ID еще свободен
+func (s *UserService) Register(id int, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("имя не может быть пустым")
+	}
+	
+	_, err := s.repo.GetUser(id)
+	if err == nil {
+		return ErrAlreadyExists
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("проверка пользователя %d: %w", id, err)
+	}
+	
+	return s.repo.SaveUser(id, name)
+}
+
+// Greeting возвращает приветствие; неизвестных пользователей называет гостями
+func (s *UserService) Greeting(id int) (string, error) {
+	name, err := s.repo.GetUser(id)
+	if errors.Is(err, ErrNotFound) {
+		return "Привет, гость!", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Привет, %s!", name), nil
+}
+
+// Пример 1: Таксономия тестовых дублеров
+func printTaxonomy() {
+	fmt.Println("=== Тестовые дублеры ===")
+	fmt.Println(`Все дублеры реализуют один интерфейс UserRepository (см. doubles_test.go).
+
+Fake - упрощенная, но рабочая реализация (map вместо БД).
+  Когда: тестируем поведение через состояние, сценарий из нескольких шагов.
+  Плюс: тесты не зависят от того, КАК сервис вызывает репозиторий.
+
+Stub - возвращает заранее заданные ответы, ничего не проверяет.
+  Когда: нужно загнать код в ветку, которую трудно воспроизвести
+  (ошибка БД, таймаут, конкретные данные).
+
+Spy - stub, который еще и записывает вызовы; проверки делает тест после действия.
+  Когда: важен побочный эффект - что именно сохранили, сколько раз вызвали.
+
+Mock - заранее запрограммирован ожиданиями и сам проверяет их.
+  Когда: протокол взаимодействия и есть требование (порядок, точное число вызовов).
+  Минус: самые хрупкие тесты, повторяют реализацию.
+
+Правило: начинайте с fake, stub для ошибок, spy/mock - только когда
+взаимодействие нельзя проверить через результат.
+
+Запуск: go test -v`)
+}
+
+// Пример 2: Сервис с настоящей (in-memory) реализацией
+func serviceExample() {
+	fmt.Println("\n=== Сервис в работе ===")
+	
+	repo := &mapRepository{users: map[int]string{}}
+	service := NewUserService(repo)
+	
+	fmt.Println("Register(1, Иван):", service.Register(1, "Иван"))
+	fmt.Println("Register(1, Петр):", service.Register(1, "Петр"))
+	
+	greeting, _ := service.Greeting(1)
+	fmt.Println(greeting)
+	greeting, _ = service.Greeting(2)
+	fmt.Println(greeting)
+}
+
+// mapRepository реализация для демонстрации в main
+type mapRepository struct {
+	users map[int]string
+}
+
+func (r *mapRepository) GetUser(id int) (string, error) {
+	name, ok := r.users[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return name, nil
+}
+
+func (r *mapRepository) SaveUser(id int, name string) error {
+	r.users[id] = name
+	return nil
+}
+
+func main() {
+	printTaxonomy()
+	serviceExample()
+}
\ No newline at end of file
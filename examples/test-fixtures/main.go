@@ -0,0 +1,48 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Пример 1: Хранилище, которое покрыто тестами в store_test.go
+func storeExample() {
+	fmt.Println("=== UserStore ===")
+	
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		log.Fatal("Ошибка подключения к БД:", err)
+	}
+	defer db.Close()
+	
+	store := NewUserStore(db)
+	if err := store.Migrate(); err != nil {
+		log.Fatal("Ошибка миграции:", err)
+	}
+	
+	id, _ := store.Create("Иван Иванов", "ivan@example.com")
+	_, err = store.Create("Иван Дубликат", "ivan@example.com")
+	fmt.Printf("Создан пользователь %d, повторный email: %v\n", id, err)
+}
+
+// Пример 2: Что демонстрируют тесты
+func printLesson() {
+	fmt.Println("\n=== TestMain, фикстуры и t.Cleanup ===")
+	fmt.Println(`store_test.go:
+  TestMain    - один раз создает временный каталог и шаблонную БД
+                с миграциями и тестовыми данными, удаляет их после m.Run
+  newTestDB   - копирует шаблон в t.TempDir(): у каждого теста своя БД,
+                поэтому тесты с t.Parallel() не мешают друг другу
+  t.Cleanup   - закрывает ресурсы; выполняется в обратном порядке
+                регистрации, даже если тест упал через t.Fatal
+
+Запуск: go test -v -count=1`)
+}
+
+func main() {
+	storeExample()
+	printLesson()
+}
\ No newline at end of file
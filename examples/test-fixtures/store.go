@@ -0,0 +1,69 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ErrDuplicateEmail email уже занят
+var ErrDuplicateEmail = errors.New("email уже существует")
+
+// User модель пользователя
+type User struct {
+	ID    int64
+	Name  string
+	Email string
+}
+
+// UserStore хранилище пользователей в SQLite
+type UserStore struct {
+	db *sql.DB
+}
+
+// NewUserStore создает хранилище
+func NewUserStore(db *sql.DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+// Migrate создает таблицы
+func (s *UserStore) Migrate() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL
+	);`
+	
+	_, err := s.db.Exec(query)
+	return err
+}
+
+// Create добавляет пользователя
+func (s *UserStore) Create(name, email string) (int64, error) {
+	result, err := s.db.Exec(`INSERT INTO users (name, email) VALUES (?, ?)`, name, email)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return 0, ErrDuplicateEmail
+		}
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// Get возвращает пользователя по ID
+func (s *UserStore) Get(id int64) (*User, error) {
+	var u User
+	err := s.db.QueryRow(`SELECT id, name, email FROM users WHERE id = ?`, id).Scan(&u.ID, &u.Name, &u.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Count возвращает количество пользователей
+func (s *UserStore) Count() (int, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&n)
+	return n, err
+}
\ No newline at end of file
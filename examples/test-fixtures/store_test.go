@@ -0,0 +1,178 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// templateDB путь к шаблонной БД, подготовленной в TestMain
+var templateDB string
+
+// Пример 1: TestMain - общая подготовка для всех тестов пакета
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "fixtures")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "setup:", err)
+		os.Exit(1)
+	}
+	
+	templateDB = filepath.Join(dir, "template.db")
+	if err := buildTemplate(templateDB); err != nil {
+		fmt.Fprintln(os.Stderr, "setup:", err)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	
+	code := m.Run()
+	
+	// defer здесь не сработает: os.Exit не выполняет отложенные вызовы
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// buildTemplate создает БД со схемой и тестовыми данными
+func buildTemplate(path string) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	
+	store := NewUserStore(db)
+	if err := store.Migrate(); err != nil {
+		return err
+	}
+	
+	seed := []User{
+		{Name: "Иван Иванов", Email: "ivan@example.com"},
+		{Name: "Мария Петрова", Email: "maria@example.com"},
+	}
+	for _, u := range seed {
+		if _, err := store.Create(u.Name, u.Email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Пример 2: Фикстура - своя копия БД на каждый тест
+func newTestDB(t *testing.T) *UserStore {
+	t.Helper()
+	
+	// t.TempDir удаляется автоматически после теста
+	path := filepath.Join(t.TempDir(), "test.db")
+	if err := copyFile(templateDB, path); err != nil {
+		t.Fatalf("copy template: %v", err)
+	}
+	
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	
+	return NewUserStore(db)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Пример 3: Параллельные тесты с изолированными фикстурами.
+// Каждый тест видит ровно двух seed-пользователей, что бы ни делали соседи.
+func TestUserStore_Create(t *testing.T) {
+	t.Parallel()
+	store := newTestDB(t)
+	
+	id, err := store.Create("Петр Сидоров", "petr@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	user, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.Email != "petr@example.com" {
+		t.Errorf("Expected 'petr@example.com', got '%s'", user.Email)
+	}
+	
+	if n, _ := store.Count(); n != 3 {
+		t.Errorf("Expected 3 users, got %d", n)
+	}
+}
+
+func TestUserStore_DuplicateEmail(t *testing.T) {
+	t.Parallel()
+	store := newTestDB(t)
+	
+	_, err := store.Create("Другой Иван", "ivan@example.com")
+	if !errors.Is(err, ErrDuplicateEmail) {
+		t.Fatalf("Expected ErrDuplicateEmail, got %v", err)
+	}
+}
+
+func TestUserStore_SeedData(t *testing.T) {
+	t.Parallel()
+	
+	tests := []struct {
+		id    int64
+		email string
+	}{
+		{1, "ivan@example.com"},
+		{2, "maria@example.com"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			t.Parallel()
+			store := newTestDB(t)
+			
+			user, err := store.Get(tt.id)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if user.Email != tt.email {
+				t.Errorf("Expected '%s', got '%s'", tt.email, user.Email)
+			}
+		})
+	}
+}
+
+// Пример 4: Порядок t.Cleanup - LIFO, как у defer
+func TestCleanupOrder(t *testing.T) {
+	var order []string
+	
+	t.Run("inner", func(t *testing.T) {
+		t.Cleanup(func() { order = append(order, "first registered") })
+		t.Cleanup(func() { order = append(order, "second registered") })
+	})
+	
+	expected := []string{"second registered", "first registered"}
+	if fmt.Sprint(order) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+}
\ No newline at end of file
@@ -291,6 +291,7 @@ func BenchmarkCalculator_Multiply(b *testing.B) {
 }
 
 // Пример 8: Примеры для fuzz тестирования (Go 1.18+)
+// Рабочие fuzz-цели с seed corpus: examples/fuzzing
 // func FuzzCalculator_Add(f *testing.F) {
 // 	// Добавляем seed corpus
 // 	f.Add(1, 2)
@@ -335,6 +336,7 @@ func BenchmarkCalculator_Multiply(b *testing.B) {
 // }
 
 // Пример 10: Setup и teardown
+// TestMain, t.Cleanup и изолированные фикстуры: examples/test-fixtures
 func setupTest() *Calculator {
 	fmt.Println("Setup test")
 	return Calculator{}
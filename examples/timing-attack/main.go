@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Почему существует crypto/subtle.
+//
+// Обычное сравнение завершается на первом несовпавшем байте. Если сервер
+// проверяет токен так, время ответа растет с длиной совпавшего префикса,
+// и атакующий подбирает токен по одному символу: 16 вариантов на символ
+// вместо 16^64 на весь токен. ConstantTimeCompare всегда просматривает
+// все байты, и время не зависит от того, где начинается различие.
+//
+// По сети разница в наносекунды тонет в шуме, но атакующий компенсирует
+// это числом запросов и статистикой. Здесь измерения локальные.
+
+// naiveEqual - то, что делает любой наивный код (и по сути ==):
+// выход при первом различии
+func naiveEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func constantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// sink не дает компилятору выбросить сравнение
+var sink bool
+
+// measure возвращает samples замеров; каждый - среднее время одного
+// сравнения по batch вызовам (один вызов короче разрешения таймера)
+func measure(cmp func(a, b []byte) bool, secret, guess []byte, samples, batch int) []float64 {
+	result := make([]float64, samples)
+	for s := range result {
+		start := time.Now()
+		for i := 0; i < batch; i++ {
+			sink = cmp(secret, guess)
+		}
+		result[s] = float64(time.Since(start).Nanoseconds()) / float64(batch)
+	}
+	return result
+}
+
+func median(xs []float64) float64 {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
+
+// guessWithPrefix - строка той же длины, совпадающая с secret на n первых байт
+func guessWithPrefix(secret []byte, n int) []byte {
+	guess := make([]byte, len(secret))
+	copy(guess, secret[:n])
+	for i := n; i < len(guess); i++ {
+		guess[i] = secret[i] ^ 0xFF // Гарантированно другой байт
+	}
+	return guess
+}
+
+// histogram печатает распределения замеров для нескольких серий
+// в общих корзинах, чтобы было видно, пересекаются ли они
+func histogram(series map[string][]float64, order []string) {
+	var all []float64
+	for _, xs := range series {
+		all = append(all, xs...)
+	}
+	sort.Float64s(all)
+	// Верхнюю границу берем по 99-му перцентилю, чтобы выбросы не сжали график
+	lo, hi := all[0], all[len(all)*99/100]
+	
+	const buckets = 16
+	width := (hi - lo) / buckets
+	if width == 0 {
+		width = 1
+	}
+	
+	for _, name := range order {
+		counts := make([]int, buckets)
+		for _, x := range series[name] {
+			i := int((x - lo) / width)
+			if i >= buckets {
+				i = buckets - 1
+			}
+			counts[i]++
+		}
+		fmt.Printf("  %s (медиана %.1f нс)\n", name, median(series[name]))
+		for i, c := range counts {
+			if c == 0 {
+				continue
+			}
+			fmt.Printf("    %6.1f нс |%s %d\n", lo+float64(i)*width, strings.Repeat("#", (c+3)/4), c)
+		}
+	}
+}
+
+// Пример 1: Распределение времени для раннего и позднего различия
+func distributions(secret []byte) {
+	const samples, batch = 200, 2000
+	
+	early := guessWithPrefix(secret, 0)
+	late := guessWithPrefix(secret, len(secret)-1)
+	
+	for _, c := range []struct {
+		name string
+		cmp  func(a, b []byte) bool
+	}{
+		{"Наивное сравнение", naiveEqual},
+		{"subtle.ConstantTimeCompare", constantTimeEqual},
+	} {
+		fmt.Printf("\n=== %s ===\n", c.name)
+		series := map[string][]float64{
+			"различие в 1-м байте ": measure(c.cmp, secret, early, samples, batch),
+			"различие в 64-м байте": measure(c.cmp, secret, late, samples, batch),
+		}
+		histogram(series, []string{"различие в 1-м байте ", "различие в 64-м байте"})
+	}
+}
+
+// Пример 2: Время растет с длиной совпавшего префикса
+func prefixScan(secret []byte) {
+	fmt.Println("\n=== Время от длины совпавшего префикса ===")
+	fmt.Println("  префикс   наивное   constant-time")
+	
+	for _, n := range []int{0, 8, 16, 32, 48, 63} {
+		guess := guessWithPrefix(secret, n)
+		naive := median(measure(naiveEqual, secret, guess, 50, 2000))
+		ct := median(measure(constantTimeEqual, secret, guess, 50, 2000))
+		fmt.Printf("  %7d %8.1f нс %10.1f нс\n", n, naive, ct)
+	}
+}
+
+// Пример 3: Подбор первых символов токена по времени.
+// Для каждой позиции перебираем 16 hex-символов и берем самый медленный.
+func recoverPrefix(secret []byte, positions int) {
+	fmt.Println("\n=== Атака: подбор префикса ===")
+	
+	const alphabet = "0123456789abcdef"
+	known := []byte{}
+	for pos := 0; pos < positions; pos++ {
+		best, bestTime := byte(0), 0.0
+		for _, c := range []byte(alphabet) {
+			guess := guessWithPrefix(secret, 0)
+			copy(guess, known)
+			guess[pos] = c
+			t := median(measure(naiveEqual, secret, guess, 30, 5000))
+			if t > bestTime {
+				best, bestTime = c, t
+			}
+		}
+		known = append(known, best)
+		fmt.Printf("  позиция %d: '%c' (верно: '%c')\n", pos, best, secret[pos])
+	}
+	fmt.Printf("  восстановлено %q, токен начинается с %q\n", known, secret[:positions])
+	fmt.Println("  Тот же перебор против ConstantTimeCompare дает случайный результат:")
+	fmt.Println("  все варианты выполняются одинаково долго.")
+}
+
+func main() {
+	raw := make([]byte, 32)
+	rand.Read(raw)
+	secret := []byte(hex.EncodeToString(raw)) // 64 hex-символа, как API-токен
+	
+	distributions(secret)
+	prefixScan(secret)
+	recoverPrefix(secret, 4)
+	
+	fmt.Println("\nВыводы:")
+	fmt.Println("  - секреты (токены, HMAC, хеши) сравнивайте через subtle.ConstantTimeCompare")
+	fmt.Println("  - hmac.Equal делает то же для MAC")
+	fmt.Println("  - ConstantTimeCompare сразу возвращает 0 при разной длине: длину скрыть")
+	fmt.Println("    нельзя, поэтому сравнивают значения фиксированной длины (хеши)")
+}
\ No newline at end of file
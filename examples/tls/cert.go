@@ -0,0 +1,139 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Самоподписанный сертификат подписан собственным ключом, без CA.
+// Браузер и curl ему не доверяют, пока сертификат явно не добавлен в
+// доверенные (curl --cacert cert.pem). Годится для разработки и
+// внутренних стендов; для публичного сервиса нужен сертификат от CA
+// (Let's Encrypt, golang.org/x/crypto/acme/autocert), для своей
+// инфраструктуры и mTLS - свой CA (examples/pki).
+
+const (
+	certFile = "cert.pem"
+	keyFile  = "key.pem"
+	// renewBefore сертификат перевыпускается заранее, а не в день истечения
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// generateSelfSigned создает сертификат и ключ ECDSA P-256 в PEM для
+// hosts: имена попадают в DNSNames, адреса - в IPAddresses. Клиенты
+// проверяют имя только по этим полям (SAN), CommonName давно не
+// учитывается.
+func generateSelfSigned(hosts []string, validity time.Duration, now time.Time) (certPEM, keyPEM []byte, err error) {
+	if len(hosts) == 0 {
+		return nil, nil, errors.New("нужен хотя бы один хост")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hosts[0]},
+		// Минута в прошлое - на случай расхождения часов клиента и сервера
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// usable проверяет, что сертификат из файла еще послужит: не истекает в
+// ближайшие renewBefore и покрывает все hosts
+func usable(certPath string, hosts []string, now time.Time) bool {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return false
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || now.Add(renewBefore).After(cert.NotAfter) {
+		return false
+	}
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureCert возвращает пути к сертификату и ключу в dir, выпуская
+// новую пару, если файлов нет, срок подходит к концу или список хостов
+// изменился. Повторный запуск берет тот же сертификат - иначе его
+// пришлось бы заново добавлять в доверенные после каждого перезапуска.
+func ensureCert(dir string, hosts []string, validity time.Duration, now time.Time) (certPath, keyPath string, err error) {
+	certPath, keyPath = filepath.Join(dir, certFile), filepath.Join(dir, keyFile)
+	if usable(certPath, hosts, now) {
+		if _, err := os.Stat(keyPath); err == nil {
+			return certPath, keyPath, nil
+		}
+	}
+	
+	certPEM, keyPEM, err := generateSelfSigned(hosts, validity, now)
+	if err != nil {
+		return "", "", fmt.Errorf("выпуск сертификата: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+	// Ключ доступен только владельцу
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(certPath, certPEM, 0o644); err != nil {
+		return "", "", err
+	}
+	return certPath, keyPath, nil
+}
+
+// tlsConfig настройки TLS сервера. Шифры для TLS 1.3 Go выбирает сам и
+// не дает их менять; для 1.2 его список по умолчанию тоже безопасен,
+// поэтому задается только минимальная версия: TLS 1.0 и 1.1 устарели
+// (RFC 8996).
+func tlsConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12}
+}
\ No newline at end of file
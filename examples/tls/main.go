@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// HTTPS-сервер с самоподписанным сертификатом. При первом запуске
+// сертификат и ключ выпускаются в -certs и дальше переиспользуются, пока
+// не подойдет срок или не поменяется список хостов.
+//
+//   cert.go   - выпуск самоподписанного сертификата, ensureCert
+//   server.go - редирект HTTP -> HTTPS, HSTS, обработчики
+//
+//   go run .
+//   curl --cacert certs/cert.pem https://localhost:8443/
+//   curl -i http://localhost:8080/users?page=2   # 308 на https://localhost:8443/users?page=2
+//
+// Без --cacert curl откажется подключаться, браузер покажет
+// предупреждение: сертификат никто, кроме нас самих, не подписывал.
+// Сертификат от CA для публичного домена - golang.org/x/crypto/acme/autocert,
+// свой CA и mTLS - examples/pki.
+//
+// Тесты: go test -race ./...
+
+// Config параметры запуска
+type Config struct {
+	Addr     string // адрес HTTPS
+	HTTPAddr string // адрес редиректа с HTTP; пустой - без редиректа
+	CertDir  string
+	Hosts    []string
+	Validity time.Duration
+}
+
+// run запускает серверы и блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	certPath, keyPath, err := ensureCert(cfg.CertDir, cfg.Hosts, cfg.Validity, time.Now())
+	if err != nil {
+		return err
+	}
+	_, httpsPort, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return err
+	}
+	
+	servers := []*http.Server{{
+		Addr:              cfg.Addr,
+		Handler:           routes(),
+		TLSConfig:         tlsConfig(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}}
+	errCh := make(chan error, 2)
+	go func() { errCh <- servers[0].ListenAndServeTLS(certPath, keyPath) }()
+	log.Printf("HTTPS на %s, сертификат %s", cfg.Addr, certPath)
+	
+	if cfg.HTTPAddr != "" {
+		redirect := &http.Server{
+			Addr:              cfg.HTTPAddr,
+			Handler:           redirectToHTTPS(httpsPort),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		servers = append(servers, redirect)
+		go func() { errCh <- redirect.ListenAndServe() }()
+		log.Printf("Редирект HTTP -> HTTPS на %s", cfg.HTTPAddr)
+	}
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var errs []error
+	for _, s := range servers {
+		errs = append(errs, s.Shutdown(shutdownCtx))
+	}
+	return errors.Join(errs...)
+}
+
+func main() {
+	var cfg Config
+	var hosts string
+	flag.StringVar(&cfg.Addr, "addr", ":8443", "адрес HTTPS")
+	flag.StringVar(&cfg.HTTPAddr, "http", ":8080", "адрес редиректа с HTTP, пустой - отключить")
+	flag.StringVar(&cfg.CertDir, "certs", "certs", "каталог сертификата и ключа")
+	flag.StringVar(&hosts, "hosts", "localhost,127.0.0.1,::1", "имена и адреса в сертификате")
+	flag.DurationVar(&cfg.Validity, "validity", 365*24*time.Hour, "срок действия нового сертификата")
+	flag.Parse()
+	cfg.Hosts = strings.Split(hosts, ",")
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// redirectToHTTPS отвечает на любой запрос по HTTP редиректом на тот же
+// путь по HTTPS. 308, а не 301: с 301 браузер может превратить POST в
+// GET и потерять тело. httpsPort - порт HTTPS-сервера; 443 в адрес не
+// пишется.
+func redirectToHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
+
+// hsts добавляет Strict-Transport-Security: побывав на сайте по HTTPS,
+// браузер год не пойдет на него по HTTP даже по ссылке http:// - редирект
+// выше больше не нужен, и перехватить первый HTTP-запрос нельзя.
+// Включают, когда HTTPS точно работает: отменить заголовок у уже
+// запомнивших его браузеров можно только дождавшись max-age.
+func hsts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routes обработчики HTTPS-сервера
+func routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		// r.TLS заполнен только для запросов, пришедших по TLS
+		version := "нет TLS"
+		if r.TLS != nil {
+			version = tlsVersionName(r.TLS.Version)
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("Привет по HTTPS! Протокол: " + r.Proto + ", " + version + "\n"))
+	})
+	return hsts(mux)
+}
+
+func tlsVersionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return "TLS (устаревший)"
+	}
+}
\ No newline at end of file
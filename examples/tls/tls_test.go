@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func parseCert(t *testing.T, certPEM []byte) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatal("Expected PEM block")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return cert
+}
+
+func TestGenerateSelfSigned(t *testing.T) {
+	now := time.Now()
+	certPEM, _, err := generateSelfSigned([]string{"localhost", "127.0.0.1"}, time.Hour, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cert := parseCert(t, certPEM)
+	
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: host, Roots: roots, CurrentTime: now}); err != nil {
+			t.Errorf("Expected %s to verify, got %v", host, err)
+		}
+	}
+	if err := cert.VerifyHostname("example.com"); err == nil {
+		t.Error("Expected example.com to be rejected")
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: roots, CurrentTime: now.Add(2 * time.Hour)}); err == nil {
+		t.Error("Expected expired certificate to be rejected")
+	}
+	
+	if _, _, err := generateSelfSigned(nil, time.Hour, now); err == nil {
+		t.Error("Expected error for empty hosts")
+	}
+}
+
+func TestEnsureCert(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	hosts := []string{"localhost"}
+	validity := 90 * 24 * time.Hour
+	
+	certPath, keyPath, err := ensureCert(dir, hosts, validity, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected key mode 0600, got %o", perm)
+	}
+	first, _ := os.ReadFile(certPath)
+	
+	tests := []struct {
+		name    string
+		hosts   []string
+		now     time.Time
+		renewed bool
+	}{
+		{"тот же сертификат", hosts, now.Add(time.Hour), false},
+		{"новый хост", []string{"localhost", "api.local"}, now, true},
+		{"срок подходит к концу", hosts, now.Add(validity - renewBefore + time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Каждый случай начинает с первого сертификата
+			if err := os.WriteFile(certPath, first, 0o644); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, _, err := ensureCert(dir, tt.hosts, validity, tt.now); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got, _ := os.ReadFile(certPath)
+			if renewed := string(got) != string(first); renewed != tt.renewed {
+				t.Errorf("Expected renewed=%v, got %v", tt.renewed, renewed)
+			}
+		})
+	}
+}
+
+func TestServer_TLS(t *testing.T) {
+	certPath, keyPath, err := ensureCert(t.TempDir(), []string{"127.0.0.1"}, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	server := httptest.NewUnstartedServer(routes())
+	server.TLS = tlsConfig()
+	server.TLS.Certificates = []tls.Certificate{pair}
+	server.StartTLS()
+	defer server.Close()
+	
+	t.Run("доверенный сертификат", func(t *testing.T) {
+		certPEM, _ := os.ReadFile(certPath)
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM(certPEM)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200, got %d", resp.StatusCode)
+		}
+		if resp.Header.Get("Strict-Transport-Security") == "" {
+			t.Error("Expected HSTS header")
+		}
+		if resp.TLS == nil || resp.TLS.Version < tls.VersionTLS12 {
+			t.Errorf("Expected TLS 1.2+, got %+v", resp.TLS)
+		}
+	})
+	
+	t.Run("недоверенный сертификат", func(t *testing.T) {
+		// Системные корни: самоподписанный сертификат в них не входит
+		client := &http.Client{Transport: &http.Transport{}}
+		if _, err := client.Get(server.URL); err == nil {
+			t.Error("Expected certificate verification error")
+		}
+	})
+	
+	t.Run("TLS 1.1 отклоняется", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			MaxVersion:         tls.VersionTLS11,
+		}}}
+		if _, err := client.Get(server.URL); err == nil {
+			t.Error("Expected handshake error for TLS 1.1")
+		}
+	})
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		port     string
+		host     string
+		target   string
+		expected string
+	}{
+		{"порт по умолчанию", "443", "example.com", "/users?page=2", "https://example.com/users?page=2"},
+		{"порт из запроса отбрасывается", "443", "example.com:80", "/", "https://example.com/"},
+		{"свой порт", "8443", "localhost:8080", "/api", "https://localhost:8443/api"},
+		{"IPv6", "8443", "[::1]:8080", "/", "https://[::1]:8443/"},
+		{"IPv6 на 443", "443", "[::1]:8080", "/", "https://[::1]/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			r.Host = tt.host
+			w := httptest.NewRecorder()
+			redirectToHTTPS(tt.port).ServeHTTP(w, r)
+			
+			if w.Code != http.StatusPermanentRedirect {
+				t.Errorf("Expected 308, got %d", w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCertFilesInDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "certs")
+	certPath, keyPath, err := ensureCert(dir, []string{"localhost"}, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if certPath != filepath.Join(dir, certFile) || keyPath != filepath.Join(dir, keyFile) {
+		t.Errorf("Expected files in %s, got %s, %s", dir, certPath, keyPath)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Пример 1: Случайные токены
+func randomTokens() {
+	fmt.Println("=== Случайные токены ===")
+	
+	for _, n := range []int{16, 32} {
+		token, err := NewToken(n)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d байт (%d бит): %s\n", n, n*8, token)
+	}
+}
+
+// Пример 2: API-ключи с префиксом и контрольной суммой
+func apiKeys() {
+	fmt.Println("\n=== API-ключи ===")
+	
+	key, err := NewAPIKey("gl_live")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Ключ:", key)
+	fmt.Println("Хеш для хранения:", HashKey(key))
+	
+	_, err = ParseAPIKey(key, "gl_live")
+	fmt.Println("Проверка формата:", err)
+	
+	typo := key[:10] + "X" + key[11:]
+	if typo == key {
+		typo = key[:10] + "Y" + key[11:]
+	}
+	_, err = ParseAPIKey(typo, "gl_live")
+	fmt.Println("Опечатка в ключе:", err)
+	
+	_, err = ParseAPIKey(key, "gl_test")
+	fmt.Println("Ключ другой среды:", err)
+}
+
+// Пример 3: Хранилище и middleware
+func middleware() {
+	fmt.Println("\n=== Middleware ===")
+	
+	store := NewKeyStore("gl_live")
+	key, rec, err := store.Issue("billing-service")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Выдан ключ %s для %s (в интерфейсе: ...%s)\n", rec.ID, rec.Owner, rec.Hint)
+	
+	handler := APIKeyMiddleware(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec, _ := KeyRecordFromContext(r.Context())
+		fmt.Fprintf(w, "привет, %s", rec.Owner)
+	}))
+	
+	call := func(name string, header, value string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/invoices", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		body, _ := io.ReadAll(rec.Body)
+		fmt.Printf("%-22s %d %s\n", name, rec.Code, strings.TrimSpace(string(body)))
+	}
+	
+	call("Bearer", "Authorization", "Bearer "+key)
+	call("X-API-Key", "X-API-Key", key)
+	call("без ключа", "", "")
+	other, _ := NewAPIKey("gl_live")
+	call("чужой ключ", "X-API-Key", other)
+	
+	store.Revoke(rec.ID)
+	call("отозванный ключ", "X-API-Key", key)
+}
+
+func main() {
+	randomTokens()
+	apiKeys()
+	middleware()
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type contextKey string
+
+const apiKeyRecordKey contextKey = "apiKeyRecord"
+
+// APIKeyMiddleware пропускает только запросы с действительным ключом
+// в заголовке Authorization: Bearer <ключ> или X-API-Key
+func APIKeyMiddleware(store *KeyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if key == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Требуется API-ключ", http.StatusUnauthorized)
+			return
+		}
+		
+		rec, err := store.Verify(key)
+		if err != nil {
+			// Одинаковый ответ для неверного формата и неизвестного ключа
+			http.Error(w, "Недействительный API-ключ", http.StatusUnauthorized)
+			return
+		}
+		
+		ctx := context.WithValue(r.Context(), apiKeyRecordKey, rec)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// KeyRecordFromContext возвращает запись ключа, прошедшего проверку
+func KeyRecordFromContext(ctx context.Context) (*APIKeyRecord, bool) {
+	rec, ok := ctx.Value(apiKeyRecordKey).(*APIKeyRecord)
+	return rec, ok
+}
\ No newline at end of file
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewToken возвращает случайный URL-безопасный токен из n байт энтропии.
+// 32 байта (256 бит) достаточно для токенов сессий, сброса пароля и т.п.
+func NewToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	// RawURLEncoding - без '+', '/' и '=': токен можно класть в URL и cookie
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Формат API-ключа: <префикс>_<секрет><контрольная сумма>
+//
+//   gl_live_UVsV2px3Fa7EylBSeK9Uds5yYFa84pBm2b8ZD6
+//   ^^^^^^^ префикс: кто выдал и для какой среды
+//           ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^ 32 символа base62 (~190 бит)
+//                                           ^^^^^^ CRC32 секрета в base62
+//
+// Префикс позволяет сканерам секретов (GitHub secret scanning, gitleaks)
+// находить утекшие ключи, а контрольная сумма - отсеивать опечатки и
+// случайные строки без обращения к хранилищу.
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	secretLen      = 32
+	checksumLen    = 6
+	lookupLen      = 8 // Начало секрета, по которому ищется запись
+)
+
+var (
+	// ErrMalformedKey ключ не соответствует формату или контрольной сумме
+	ErrMalformedKey = errors.New("неверный формат API-ключа")
+	// ErrUnknownKey ключ не найден, отозван или не совпал
+	ErrUnknownKey = errors.New("неизвестный API-ключ")
+)
+
+// randomBase62 возвращает n равномерно распределенных символов base62.
+// rand.Int вместо b%62 - остаток от деления дает смещение распределения.
+func randomBase62(n int) (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(base62Alphabet)))
+	for i := 0; i < n; i++ {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(base62Alphabet[v.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// encodeBase62 кодирует число фиксированным числом символов
+func encodeBase62(v uint32, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = base62Alphabet[v%62]
+		v /= 62
+	}
+	return string(buf)
+}
+
+func checksum(secret string) string {
+	return encodeBase62(crc32.ChecksumIEEE([]byte(secret)), checksumLen)
+}
+
+// NewAPIKey создает ключ с заданным префиксом, например "gl_live"
+func NewAPIKey(prefix string) (string, error) {
+	secret, err := randomBase62(secretLen)
+	if err != nil {
+		return "", err
+	}
+	return prefix + "_" + secret + checksum(secret), nil
+}
+
+// ParseAPIKey проверяет формат и контрольную сумму.
+// Это не проверка подлинности - только дешевый фильтр мусора.
+func ParseAPIKey(key, prefix string) (secret string, err error) {
+	body, ok := strings.CutPrefix(key, prefix+"_")
+	if !ok || len(body) != secretLen+checksumLen {
+		return "", ErrMalformedKey
+	}
+	secret, sum := body[:secretLen], body[secretLen:]
+	if strings.Trim(secret, base62Alphabet) != "" {
+		return "", ErrMalformedKey
+	}
+	if checksum(secret) != sum {
+		return "", ErrMalformedKey
+	}
+	return secret, nil
+}
+
+// HashKey возвращает SHA-256 ключа. В отличие от паролей, медленный
+// хеш (bcrypt, argon2) здесь не нужен: у ключа ~190 бит энтропии,
+// перебор невозможен, а проверка выполняется на каждый запрос.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyRecord запись о ключе. Сам ключ не хранится - только хеш;
+// утечка базы не дает рабочих ключей.
+type APIKeyRecord struct {
+	ID        string
+	Owner     string
+	Hash      string
+	Hint      string // Последние символы для отображения в интерфейсе
+	CreatedAt time.Time
+	Revoked   bool
+}
+
+// KeyStore хранилище API-ключей в памяти
+type KeyStore struct {
+	prefix string
+
+	mu       sync.RWMutex
+	byLookup map[string]*APIKeyRecord
+}
+
+// NewKeyStore создает хранилище ключей с префиксом prefix
+func NewKeyStore(prefix string) *KeyStore {
+	return &KeyStore{prefix: prefix, byLookup: make(map[string]*APIKeyRecord)}
+}
+
+// Issue выпускает ключ. Полный ключ возвращается один раз -
+// показать его пользователю повторно невозможно.
+func (s *KeyStore) Issue(owner string) (string, *APIKeyRecord, error) {
+	for {
+		key, err := NewAPIKey(s.prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		secret, _ := ParseAPIKey(key, s.prefix)
+		lookup := secret[:lookupLen]
+		
+		s.mu.Lock()
+		if _, exists := s.byLookup[lookup]; exists {
+			// Коллизия первых 8 символов (62^8 вариантов) - просто выпускаем новый
+			s.mu.Unlock()
+			continue
+		}
+		rec := &APIKeyRecord{
+			ID:        lookup,
+			Owner:     owner,
+			Hash:      HashKey(key),
+			Hint:      key[len(key)-4:],
+			CreatedAt: time.Now(),
+		}
+		s.byLookup[lookup] = rec
+		issued := *rec
+		s.mu.Unlock()
+		return key, &issued, nil
+	}
+}
+
+// Verify находит запись по началу секрета и сравнивает хеши
+// за постоянное время, не раскрывая по времени ответа, сколько
+// байт хеша совпало. Запись копируется под блокировкой: Revoke
+// меняет Revoked под Lock, и чтение поля после RUnlock было бы гонкой.
+// Issue и Verify возвращают копии по той же причине.
+func (s *KeyStore) Verify(key string) (*APIKeyRecord, error) {
+	secret, err := ParseAPIKey(key, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+	
+	s.mu.RLock()
+	stored, ok := s.byLookup[secret[:lookupLen]]
+	var rec APIKeyRecord
+	if ok {
+		rec = *stored
+	}
+	s.mu.RUnlock()
+	if !ok || rec.Revoked {
+		return nil, ErrUnknownKey
+	}
+	
+	if subtle.ConstantTimeCompare([]byte(HashKey(key)), []byte(rec.Hash)) != 1 {
+		return nil, ErrUnknownKey
+	}
+	return &rec, nil
+}
+
+// Revoke отзывает ключ по ID
+func (s *KeyStore) Revoke(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.byLookup[id]
+	if ok {
+		rec.Revoked = true
+	}
+	return ok
+}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewToken(t *testing.T) {
+	a, err := NewToken(32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, _ := NewToken(32)
+	
+	if a == b {
+		t.Error("Expected different tokens")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(a)
+	if err != nil {
+		t.Fatalf("Token is not URL-safe base64: %v", err)
+	}
+	if len(raw) != 32 {
+		t.Errorf("Expected 32 bytes, got %d", len(raw))
+	}
+}
+
+func TestAPIKeyFormat(t *testing.T) {
+	key, err := NewAPIKey("gl_test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if !strings.HasPrefix(key, "gl_test_") {
+		t.Errorf("Expected prefix gl_test_, got %s", key)
+	}
+	if _, err := ParseAPIKey(key, "gl_test"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestParseAPIKeyRejectsMalformed(t *testing.T) {
+	key, _ := NewAPIKey("gl_test")
+	secret := strings.TrimPrefix(key, "gl_test_")
+	
+	// Меняем один символ секрета на соседний в алфавите
+	i := strings.IndexByte(base62Alphabet, secret[0])
+	flipped := "gl_test_" + string(base62Alphabet[(i+1)%62]) + secret[1:]
+	
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"empty", ""},
+		{"wrong prefix", strings.Replace(key, "gl_test", "gl_live", 1)},
+		{"truncated", key[:len(key)-1]},
+		{"bad checksum", flipped},
+		{"invalid chars", "gl_test_" + strings.Repeat("-", secretLen+checksumLen)},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAPIKey(tt.key, "gl_test"); !errors.Is(err, ErrMalformedKey) {
+				t.Errorf("Expected ErrMalformedKey, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyStore(t *testing.T) {
+	store := NewKeyStore("gl_test")
+	key, rec, err := store.Issue("svc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if strings.Contains(rec.Hash, key) || rec.Hash != HashKey(key) {
+		t.Error("Expected only the hash of the key to be stored")
+	}
+	
+	got, err := store.Verify(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Owner != "svc" {
+		t.Errorf("Expected owner svc, got %s", got.Owner)
+	}
+	
+	other, _ := NewAPIKey("gl_test")
+	if _, err := store.Verify(other); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+	
+	store.Revoke(rec.ID)
+	if _, err := store.Verify(key); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey after revoke, got %v", err)
+	}
+}
+
+// Запускать с -race: Verify читает Revoked, пока Revoke его пишет
+func TestKeyStore_ConcurrentVerifyRevoke(t *testing.T) {
+	store := NewKeyStore("gl_test")
+	key, rec, err := store.Issue("svc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if got, err := store.Verify(key); err == nil && got.Revoked {
+					t.Error("Expected revoked key to be rejected")
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		store.Revoke(rec.ID)
+	}()
+	wg.Wait()
+	
+	// После возврата Revoke ключ не проходит ни в одной горутине
+	if _, err := store.Verify(key); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey after revoke, got %v", err)
+	}
+	// Выданная запись - копия: отзыв не меняет ее за спиной вызывающего
+	if rec.Revoked {
+		t.Error("Expected issued record to be a snapshot")
+	}
+}
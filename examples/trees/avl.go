@@ -0,0 +1,214 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// AVL самобалансирующееся дерево: высоты поддеревьев любого узла
+// различаются не больше чем на 1. После вставки или удаления баланс
+// восстанавливается поворотами, поэтому высота всегда O(log n),
+// даже при вставке отсортированных ключей.
+type AVL[K cmp.Ordered, V any] struct {
+	root *avlNode[K, V]
+	size int
+}
+
+type avlNode[K cmp.Ordered, V any] struct {
+	key         K
+	value       V
+	height      int
+	left, right *avlNode[K, V]
+}
+
+func height[K cmp.Ordered, V any](n *avlNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *avlNode[K, V]) update() {
+	n.height = 1 + max(height(n.left), height(n.right))
+}
+
+// balanceFactor > 1 - перевешивает левое поддерево, < -1 - правое
+func (n *avlNode[K, V]) balanceFactor() int {
+	return height(n.left) - height(n.right)
+}
+
+// rotateRight поворачивает поддерево вправо, rotateLeft - зеркально:
+//
+//	    y            x
+//	   / \          / \
+//	  x   C   ->   A   y
+//	 / \              / \
+//	A   B            B   C
+func rotateRight[K cmp.Ordered, V any](y *avlNode[K, V]) *avlNode[K, V] {
+	x := y.left
+	y.left = x.right
+	x.right = y
+	y.update()
+	x.update()
+	return x
+}
+
+func rotateLeft[K cmp.Ordered, V any](x *avlNode[K, V]) *avlNode[K, V] {
+	y := x.right
+	x.right = y.left
+	y.left = x
+	x.update()
+	y.update()
+	return y
+}
+
+// rebalance восстанавливает баланс узла одним или двумя поворотами
+func rebalance[K cmp.Ordered, V any](n *avlNode[K, V]) *avlNode[K, V] {
+	n.update()
+	bf := n.balanceFactor()
+	
+	if bf > 1 {
+		// Случай левый-правый: сначала выпрямляем левое поддерево
+		if n.left.balanceFactor() < 0 {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	}
+	if bf < -1 {
+		if n.right.balanceFactor() > 0 {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
+
+// Put вставляет или обновляет значение
+func (t *AVL[K, V]) Put(key K, value V) {
+	var inserted bool
+	t.root, inserted = avlInsert(t.root, key, value)
+	if inserted {
+		t.size++
+	}
+}
+
+func avlInsert[K cmp.Ordered, V any](n *avlNode[K, V], key K, value V) (*avlNode[K, V], bool) {
+	if n == nil {
+		return &avlNode[K, V]{key: key, value: value, height: 1}, true
+	}
+	
+	var inserted bool
+	switch {
+	case key < n.key:
+		n.left, inserted = avlInsert(n.left, key, value)
+	case key > n.key:
+		n.right, inserted = avlInsert(n.right, key, value)
+	default:
+		n.value = value
+		return n, false
+	}
+	return rebalance(n), inserted
+}
+
+// Get ищет значение по ключу
+func (t *AVL[K, V]) Get(key K) (v V, ok bool) {
+	n := t.root
+	for n != nil {
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return v, false
+}
+
+// Delete удаляет ключ
+func (t *AVL[K, V]) Delete(key K) bool {
+	var deleted bool
+	t.root, deleted = avlDelete(t.root, key)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+func avlDelete[K cmp.Ordered, V any](n *avlNode[K, V], key K) (*avlNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	
+	var deleted bool
+	switch {
+	case key < n.key:
+		n.left, deleted = avlDelete(n.left, key)
+	case key > n.key:
+		n.right, deleted = avlDelete(n.right, key)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.key, n.value = successor.key, successor.value
+		n.right, _ = avlDelete(n.right, successor.key)
+		deleted = true
+	}
+	return rebalance(n), deleted
+}
+
+// Len возвращает число ключей
+func (t *AVL[K, V]) Len() int {
+	return t.size
+}
+
+// Height возвращает высоту дерева
+func (t *AVL[K, V]) Height() int {
+	return height(t.root)
+}
+
+// All перебирает пары по возрастанию ключей
+func (t *AVL[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var stack []*avlNode[K, V]
+		n := t.root
+		for n != nil || len(stack) > 0 {
+			for n != nil {
+				stack = append(stack, n)
+				n = n.left
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key, n.value) {
+				return
+			}
+			n = n.right
+		}
+	}
+}
+
+// String рисует дерево боком: корень слева, правое поддерево сверху
+func (t *AVL[K, V]) String() string {
+	var sb strings.Builder
+	var draw func(n *avlNode[K, V], depth int)
+	draw = func(n *avlNode[K, V], depth int) {
+		if n == nil {
+			return
+		}
+		draw(n.right, depth+1)
+		fmt.Fprintf(&sb, "%s%v\n", strings.Repeat("    ", depth), n.key)
+		draw(n.left, depth+1)
+	}
+	draw(t.root, 0)
+	return sb.String()
+}
\ No newline at end of file
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+// BST несбалансированное двоичное дерево поиска: для каждого узла
+// ключи левого поддерева меньше, правого - больше. Операции O(h),
+// где h - высота: O(log n) на случайных данных, но O(n) на
+// отсортированных, когда дерево вырождается в список.
+type BST[K cmp.Ordered, V any] struct {
+	root *bstNode[K, V]
+	size int
+}
+
+type bstNode[K cmp.Ordered, V any] struct {
+	key         K
+	value       V
+	left, right *bstNode[K, V]
+}
+
+// Put вставляет или обновляет значение
+func (t *BST[K, V]) Put(key K, value V) {
+	link := &t.root
+	for *link != nil {
+		n := *link
+		switch {
+		case key < n.key:
+			link = &n.left
+		case key > n.key:
+			link = &n.right
+		default:
+			n.value = value
+			return
+		}
+	}
+	*link = &bstNode[K, V]{key: key, value: value}
+	t.size++
+}
+
+// Get ищет значение по ключу
+func (t *BST[K, V]) Get(key K) (v V, ok bool) {
+	n := t.root
+	for n != nil {
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return v, false
+}
+
+// Delete удаляет ключ. Три случая:
+// нет детей - узел просто убирается; один ребенок - занимает место узла;
+// два ребенка - узел заменяется минимумом правого поддерева.
+func (t *BST[K, V]) Delete(key K) bool {
+	var deleted bool
+	t.root, deleted = bstDelete(t.root, key)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+func bstDelete[K cmp.Ordered, V any](n *bstNode[K, V], key K) (*bstNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	
+	var deleted bool
+	switch {
+	case key < n.key:
+		n.left, deleted = bstDelete(n.left, key)
+		return n, deleted
+	case key > n.key:
+		n.right, deleted = bstDelete(n.right, key)
+		return n, deleted
+	}
+	
+	if n.left == nil {
+		return n.right, true
+	}
+	if n.right == nil {
+		return n.left, true
+	}
+	
+	successor := n.right
+	for successor.left != nil {
+		successor = successor.left
+	}
+	n.key, n.value = successor.key, successor.value
+	n.right, _ = bstDelete(n.right, successor.key)
+	return n, true
+}
+
+// Len возвращает число ключей
+func (t *BST[K, V]) Len() int {
+	return t.size
+}
+
+// Height возвращает высоту дерева (пустое - 0)
+func (t *BST[K, V]) Height() int {
+	return bstHeight(t.root)
+}
+
+func bstHeight[K cmp.Ordered, V any](n *bstNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(bstHeight(n.left), bstHeight(n.right))
+}
+
+// All - симметричный (in-order) обход: ключи по возрастанию.
+// Итератор на явном стеке вместо рекурсии: yield может прервать обход,
+// а глубина вырожденного дерева не переполнит стек горутины.
+func (t *BST[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var stack []*bstNode[K, V]
+		n := t.root
+		for n != nil || len(stack) > 0 {
+			for n != nil {
+				stack = append(stack, n)
+				n = n.left
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key, n.value) {
+				return
+			}
+			n = n.right
+		}
+	}
+}
+
+// PreOrder - прямой обход (узел, левое, правое): порядок, в котором
+// дерево можно сериализовать и восстановить той же формы
+func (t *BST[K, V]) PreOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if t.root == nil {
+			return
+		}
+		stack := []*bstNode[K, V]{t.root}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key) {
+				return
+			}
+			if n.right != nil {
+				stack = append(stack, n.right)
+			}
+			if n.left != nil {
+				stack = append(stack, n.left)
+			}
+		}
+	}
+}
+
+// PostOrder - обратный обход (левое, правое, узел): дети раньше
+// родителя, так освобождают ресурсы или вычисляют размеры поддеревьев
+func (t *BST[K, V]) PostOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		var walk func(n *bstNode[K, V]) bool
+		walk = func(n *bstNode[K, V]) bool {
+			if n == nil {
+				return true
+			}
+			return walk(n.left) && walk(n.right) && yield(n.key)
+		}
+		walk(t.root)
+	}
+}
+
+// LevelOrder - обход по уровням (в ширину)
+func (t *BST[K, V]) LevelOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if t.root == nil {
+			return
+		}
+		queue := []*bstNode[K, V]{t.root}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n.key) {
+				return
+			}
+			if n.left != nil {
+				queue = append(queue, n.left)
+			}
+			if n.right != nil {
+				queue = append(queue, n.right)
+			}
+		}
+	}
+}
\ No newline at end of file
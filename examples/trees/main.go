@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Пример 1: Обходы BST
+func traversals() {
+	fmt.Println("=== Обходы BST ===")
+	
+	var t BST[int, string]
+	for _, k := range []int{50, 30, 70, 20, 40, 60, 80} {
+		t.Put(k, fmt.Sprint("v", k))
+	}
+	
+	var inOrder []int
+	for k := range t.All() {
+		inOrder = append(inOrder, k)
+	}
+	fmt.Println("In-order:   ", inOrder)
+	fmt.Println("Pre-order:  ", slices.Collect(t.PreOrder()))
+	fmt.Println("Post-order: ", slices.Collect(t.PostOrder()))
+	fmt.Println("Level-order:", slices.Collect(t.LevelOrder()))
+	
+	// Итератор можно прервать: первые три ключа больше 25
+	fmt.Print("Первые три > 25:")
+	count := 0
+	for k := range t.All() {
+		if k <= 25 {
+			continue
+		}
+		fmt.Print(" ", k)
+		if count++; count == 3 {
+			break
+		}
+	}
+	fmt.Println()
+	
+	t.Delete(30) // Узел с двумя детьми
+	fmt.Println("После удаления 30:", slices.Collect(t.LevelOrder()))
+}
+
+// Пример 2: Вырождение BST и AVL на отсортированных данных
+func degeneration() {
+	fmt.Println("\n=== Отсортированные ключи ===")
+	
+	const n = 1023
+	var bst BST[int, struct{}]
+	var avl AVL[int, struct{}]
+	for i := 0; i < n; i++ {
+		bst.Put(i, struct{}{})
+		avl.Put(i, struct{}{})
+	}
+	fmt.Printf("n = %d, идеальная высота = %d\n", n, int(math.Ceil(math.Log2(n+1))))
+	fmt.Printf("BST высота: %d (список)\n", bst.Height())
+	fmt.Printf("AVL высота: %d\n", avl.Height())
+	
+	var randomBST BST[int, struct{}]
+	for _, k := range rand.New(rand.NewSource(1)).Perm(n) {
+		randomBST.Put(k, struct{}{})
+	}
+	fmt.Printf("BST на случайных ключах: %d\n", randomBST.Height())
+}
+
+// Пример 3: Повороты AVL
+func rotations() {
+	fmt.Println("\n=== Повороты AVL ===")
+	
+	var t AVL[int, struct{}]
+	for _, k := range []int{1, 2, 3, 4, 5, 6, 7} {
+		t.Put(k, struct{}{})
+	}
+	fmt.Println("После вставки 1..7 по порядку:")
+	fmt.Print(t.String())
+}
+
+func main() {
+	traversals()
+	degeneration()
+	rotations()
+}
\ No newline at end of file
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+// checkAVL проверяет инварианты поддерева и возвращает его высоту:
+// порядок ключей, корректность сохраненных высот и баланс
+func checkAVL(t *testing.T, n *avlNode[int, int], lo, hi *int) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if (lo != nil && n.key <= *lo) || (hi != nil && n.key >= *hi) {
+		t.Fatalf("Key %d violates BST order", n.key)
+	}
+	
+	lh := checkAVL(t, n.left, lo, &n.key)
+	rh := checkAVL(t, n.right, &n.key, hi)
+	
+	if d := lh - rh; d > 1 || d < -1 {
+		t.Fatalf("Node %d is unbalanced: left %d, right %d", n.key, lh, rh)
+	}
+	h := 1 + max(lh, rh)
+	if n.height != h {
+		t.Fatalf("Node %d stores height %d, actual %d", n.key, n.height, h)
+	}
+	return h
+}
+
+func sortedUnique(keys []int) []int {
+	s := slices.Clone(keys)
+	slices.Sort(s)
+	return slices.Compact(s)
+}
+
+// Свойство: после любой последовательности вставок дерево сбалансировано
+// и обходит ровно уникальные вставленные ключи по возрастанию
+func TestAVLInsertProperty(t *testing.T) {
+	property := func(keys []int) bool {
+		var tree AVL[int, int]
+		for _, k := range keys {
+			tree.Put(k, k)
+		}
+		checkAVL(t, tree.root, nil, nil)
+		
+		var got []int
+		for k := range tree.All() {
+			got = append(got, k)
+		}
+		want := sortedUnique(keys)
+		return slices.Equal(got, want) && tree.Len() == len(want)
+	}
+	
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Свойство: удаления сохраняют баланс и удаляют ровно нужные ключи
+func TestAVLDeleteProperty(t *testing.T) {
+	property := func(keys []int16, deletes []int16) bool {
+		var tree AVL[int, int]
+		model := map[int]bool{}
+		for _, k := range keys {
+			tree.Put(int(k), 0)
+			model[int(k)] = true
+		}
+		for _, k := range deletes {
+			if tree.Delete(int(k)) != model[int(k)] {
+				return false
+			}
+			delete(model, int(k))
+			checkAVL(t, tree.root, nil, nil)
+		}
+		
+		for k := range model {
+			if _, ok := tree.Get(k); !ok {
+				return false
+			}
+		}
+		return tree.Len() == len(model)
+	}
+	
+	if err := quick.Check(property, &quick.Config{MaxCount: 300}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Свойство: высота AVL не превышает 1.44*log2(n+2)
+func TestAVLHeightBound(t *testing.T) {
+	var tree AVL[int, int]
+	for i := 0; i < 1<<16; i++ {
+		tree.Put(i, i) // Худший случай для BST
+	}
+	if h := tree.Height(); h > 24 {
+		t.Errorf("Expected height <= 24 for 65536 keys, got %d", h)
+	}
+}
+
+func TestBSTProperty(t *testing.T) {
+	property := func(keys []int8, deletes []int8) bool {
+		var tree BST[int8, bool]
+		model := map[int8]bool{}
+		for _, k := range keys {
+			tree.Put(k, true)
+			model[k] = true
+		}
+		for _, k := range deletes {
+			tree.Delete(k)
+			delete(model, k)
+		}
+		
+		var got []int8
+		for k := range tree.All() {
+			got = append(got, k)
+		}
+		return sort.SliceIsSorted(got, func(i, j int) bool { return got[i] < got[j] }) &&
+			len(got) == len(model) && tree.Len() == len(model)
+	}
+	
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBSTTraversals(t *testing.T) {
+	var tree BST[int, struct{}]
+	for _, k := range []int{4, 2, 6, 1, 3, 5, 7} {
+		tree.Put(k, struct{}{})
+	}
+	
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"pre-order", slices.Collect(tree.PreOrder()), []int{4, 2, 1, 3, 6, 5, 7}},
+		{"post-order", slices.Collect(tree.PostOrder()), []int{1, 3, 2, 5, 7, 6, 4}},
+		{"level-order", slices.Collect(tree.LevelOrder()), []int{4, 2, 6, 1, 3, 5, 7}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, tt.got)
+			}
+		})
+	}
+}
+
+func TestIteratorEarlyExit(t *testing.T) {
+	var tree AVL[int, int]
+	for i := 0; i < 100; i++ {
+		tree.Put(i, i)
+	}
+	
+	var got []int
+	for k := range tree.All() {
+		if k == 3 {
+			break
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Expected [0 1 2], got %v", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"trie"
+)
+
+// Префиксное дерево - пакет trie в корне модуля; здесь демонстрация.
+// На нем построены автодополнение и подсказки по мере ввода в
+// examples/http-server (GET /api/users/autocomplete и
+// GET /api/users/suggest) - по живому списку пользователей.
+//
+// Запуск демонстрации: go run ./cmd/trie
+
+// Пример 1: Вставка, поиск и удаление
+func basics() {
+	fmt.Println("=== Основные операции ===")
+	
+	t := trie.NewTrie[int]()
+	for i, w := range []string{"go", "gopher", "golang", "goroutine", "git", "grpc"} {
+		t.Insert(w, i)
+	}
+	
+	v, ok := t.Get("golang")
+	fmt.Printf("Get(golang) = %d, %v\n", v, ok)
+	_, ok = t.Get("gol")
+	fmt.Printf("Get(gol) = %v (префикс, но не ключ)\n", ok)
+	
+	fmt.Print("Префикс \"go\":")
+	for k := range t.WithPrefix("go") {
+		fmt.Print(" ", k)
+	}
+	fmt.Println()
+	
+	t.Delete("go")
+	fmt.Printf("После Delete(go): %d ключей, gopher на месте: ", t.Len())
+	_, ok = t.Get("gopher")
+	fmt.Println(ok)
+}
+
+// Пример 2: Нечеткий поиск
+func fuzzy() {
+	fmt.Println("\n=== Нечеткий поиск ===")
+	
+	t := trie.NewTrie[struct{}]()
+	for _, w := range []string{"context", "contains", "constant", "concurrency", "channel", "close"} {
+		t.Insert(w, struct{}{})
+	}
+	
+	for _, q := range []string{"conetx", "chanel", "cnst"} {
+		fmt.Printf("%q:", q)
+		for _, m := range t.Fuzzy(q, 2) {
+			fmt.Printf(" %s(%d)", m.Key, m.Dist)
+		}
+		fmt.Println()
+	}
+}
+
+func main() {
+	basics()
+	fuzzy()
+}
\ No newline at end of file
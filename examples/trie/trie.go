@@ -0,0 +1,194 @@
+// Package trie префиксное дерево с поиском по префиксу и нечетким
+// поиском. Демонстрация и подсказки по мере ввода - cmd/trie;
+// автодополнение имен на нем строит examples/http-server.
+package trie
+
+import (
+	"iter"
+	"slices"
+)
+
+// Trie префиксное дерево по рунам: ключи с общим началом делят один путь,
+// поэтому поиск по префиксу стоит O(len(prefix)) независимо от числа ключей.
+type Trie[V any] struct {
+	root node[V]
+	size int
+}
+
+type node[V any] struct {
+	children map[rune]*node[V]
+	value    V
+	terminal bool // В этом узле заканчивается ключ
+}
+
+// NewTrie создает пустое дерево
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// Insert добавляет ключ или заменяет его значение
+func (t *Trie[V]) Insert(key string, v V) {
+	n := &t.root
+	for _, r := range key {
+		if n.children == nil {
+			n.children = make(map[rune]*node[V])
+		}
+		child, ok := n.children[r]
+		if !ok {
+			child = &node[V]{}
+			n.children[r] = child
+		}
+		n = child
+	}
+	if !n.terminal {
+		t.size++
+	}
+	n.value = v
+	n.terminal = true
+}
+
+// Get возвращает значение по точному ключу
+func (t *Trie[V]) Get(key string) (V, bool) {
+	n := t.find(key)
+	if n == nil || !n.terminal {
+		var zero V
+		return zero, false
+	}
+	return n.value, true
+}
+
+// Delete удаляет ключ и освобождает ставшие пустыми узлы
+func (t *Trie[V]) Delete(key string) bool {
+	runes := []rune(key)
+	path := make([]*node[V], 0, len(runes)+1)
+	n := &t.root
+	path = append(path, n)
+	for _, r := range runes {
+		n = n.children[r]
+		if n == nil {
+			return false
+		}
+		path = append(path, n)
+	}
+	if !n.terminal {
+		return false
+	}
+	
+	var zero V
+	n.value = zero
+	n.terminal = false
+	t.size--
+	
+	// Поднимаемся вверх, отрезая листья без ключей
+	for i := len(runes); i > 0; i-- {
+		child := path[i]
+		if child.terminal || len(child.children) > 0 {
+			break
+		}
+		delete(path[i-1].children, runes[i-1])
+	}
+	return true
+}
+
+// Len возвращает число ключей
+func (t *Trie[V]) Len() int {
+	return t.size
+}
+
+// WithPrefix перечисляет ключи с заданным префиксом в лексикографическом порядке
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := t.find(prefix)
+		if n == nil {
+			return
+		}
+		walk(n, []rune(prefix), yield)
+	}
+}
+
+func (t *Trie[V]) find(key string) *node[V] {
+	n := &t.root
+	for _, r := range key {
+		n = n.children[r]
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+func walk[V any](n *node[V], key []rune, yield func(string, V) bool) bool {
+	if n.terminal && !yield(string(key), n.value) {
+		return false
+	}
+	for _, r := range sortedRunes(n.children) {
+		if !walk(n.children[r], append(key, r), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedRunes[V any](children map[rune]*node[V]) []rune {
+	runes := make([]rune, 0, len(children))
+	for r := range children {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+	return runes
+}
+
+// Match результат нечеткого поиска
+type Match[V any] struct {
+	Key   string
+	Value V
+	Dist  int // Расстояние Левенштейна между запросом и началом ключа
+}
+
+// Fuzzy ищет ключи, начало которых отличается от query не более чем на
+// maxDist правок (вставка, удаление, замена руны). Так автодополнение
+// переживает опечатки: "мра" найдет "мария".
+//
+// Обход дерева считает строку таблицы Левенштейна для каждого узла на
+// основе строки родителя, поэтому общий префикс обрабатывается один раз.
+// Ветви, где минимум строки уже больше maxDist, отсекаются.
+func (t *Trie[V]) Fuzzy(query string, maxDist int) []Match[V] {
+	q := []rune(query)
+	row := make([]int, len(q)+1)
+	for i := range row {
+		row[i] = i
+	}
+	
+	var matches []Match[V]
+	var search func(n *node[V], key []rune, prev []int, best int)
+	search = func(n *node[V], key []rune, prev []int, best int) {
+		// prev[len(q)] - расстояние от запроса до текущего префикса ключа;
+		// лучшее по пути и есть расстояние до ключа как автодополнения
+		best = min(best, prev[len(q)])
+		if n.terminal && best <= maxDist {
+			matches = append(matches, Match[V]{Key: string(key), Value: n.value, Dist: best})
+		}
+		for _, r := range sortedRunes(n.children) {
+			cur := make([]int, len(q)+1)
+			cur[0] = prev[0] + 1
+			rowMin := cur[0]
+			for i := 1; i <= len(q); i++ {
+				cost := 1
+				if q[i-1] == r {
+					cost = 0
+				}
+				cur[i] = min(cur[i-1]+1, prev[i]+1, prev[i-1]+cost)
+				rowMin = min(rowMin, cur[i])
+			}
+			if rowMin <= maxDist || best <= maxDist {
+				search(n.children[r], append(key, r), cur, best)
+			}
+		}
+	}
+	search(&t.root, nil, row, len(q)+1)
+	
+	slices.SortStableFunc(matches, func(a, b Match[V]) int {
+		return a.Dist - b.Dist
+	})
+	return matches
+}
\ No newline at end of file
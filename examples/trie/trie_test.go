@@ -0,0 +1,117 @@
+package trie
+
+import (
+	"slices"
+	"testing"
+)
+
+func collectKeys[V any](t *Trie[V], prefix string) []string {
+	var keys []string
+	for k := range t.WithPrefix(prefix) {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestTrieInsertGetDelete(t *testing.T) {
+	tr := NewTrie[int]()
+	words := []string{"go", "gopher", "golang", "git", "мир", "мирный"}
+	for i, w := range words {
+		tr.Insert(w, i)
+	}
+	tr.Insert("go", 100) // Замена не увеличивает размер
+	
+	if tr.Len() != len(words) {
+		t.Errorf("Expected %d keys, got %d", len(words), tr.Len())
+	}
+	if v, ok := tr.Get("go"); !ok || v != 100 {
+		t.Errorf("Expected go=100, got %d (ok=%v)", v, ok)
+	}
+	if _, ok := tr.Get("gop"); ok {
+		t.Error("Expected prefix gop not to be a key")
+	}
+	
+	if !tr.Delete("мир") {
+		t.Fatal("Expected Delete(мир) to succeed")
+	}
+	if tr.Delete("мир") {
+		t.Error("Expected second Delete(мир) to fail")
+	}
+	if _, ok := tr.Get("мирный"); !ok {
+		t.Error("Expected мирный to survive deletion of its prefix")
+	}
+	
+	tr.Delete("мирный")
+	if _, ok := tr.root.children['м']; ok {
+		t.Error("Expected empty branch to be pruned")
+	}
+}
+
+func TestTrieWithPrefix(t *testing.T) {
+	tr := NewTrie[struct{}]()
+	for _, w := range []string{"goroutine", "go", "golang", "gopher", "grpc"} {
+		tr.Insert(w, struct{}{})
+	}
+	
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"go", []string{"go", "golang", "gopher", "goroutine"}},
+		{"gop", []string{"gopher"}},
+		{"", []string{"go", "golang", "gopher", "goroutine", "grpc"}},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got := collectKeys(tr, tt.prefix)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+	
+	// Досрочная остановка итератора
+	for range tr.WithPrefix("go") {
+		break
+	}
+}
+
+func TestTrieFuzzy(t *testing.T) {
+	tr := NewTrie[struct{}]()
+	for _, w := range []string{"context", "contains", "channel", "close"} {
+		tr.Insert(w, struct{}{})
+	}
+	
+	tests := []struct {
+		query   string
+		maxDist int
+		want    string
+		dist    int
+	}{
+		{"cont", 0, "contains", 0},
+		{"chanel", 1, "channel", 1},
+		{"conetx", 2, "context", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			matches := tr.Fuzzy(tt.query, tt.maxDist)
+			if len(matches) == 0 {
+				t.Fatal("Expected at least one match")
+			}
+			for _, m := range matches {
+				if m.Dist > tt.maxDist {
+					t.Errorf("Match %s has distance %d > %d", m.Key, m.Dist, tt.maxDist)
+				}
+			}
+			i := slices.IndexFunc(matches, func(m Match[struct{}]) bool { return m.Key == tt.want })
+			if i < 0 || matches[i].Dist != tt.dist {
+				t.Errorf("Expected %s(%d) among %v", tt.want, tt.dist, matches)
+			}
+		})
+	}
+	
+	if matches := tr.Fuzzy("xyz", 1); len(matches) != 0 {
+		t.Errorf("Expected no matches, got %v", matches)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+)
+
+// Использование:
+//   go run . version          - короткая строка
+//   go run . version -json    - все поля, включая зависимости
+//   go run . serve            - HTTP-сервер с /version на :8080
+//   go run .                  - демонстрация всего сразу
+
+// versionHandler отдает сведения о сборке: удобно проверить,
+// какая версия реально задеплоена на конкретный инстанс
+func versionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	info := Get()
+	// Список зависимостей раскрывает детали сборки - отдаем его только по запросу
+	if r.URL.Query().Get("deps") != "1" {
+		info.Deps = nil
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// versionCommand реализует подкоманду version
+func versionCommand(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "вывести в формате JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	
+	info := Get()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	fmt.Println("golearn", info)
+	return nil
+}
+
+func demo() {
+	fmt.Println("=== Подкоманда version ===")
+	versionCommand(nil)
+	
+	fmt.Println("\n=== GET /version ===")
+	rec := httptest.NewRecorder()
+	versionHandler(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	fmt.Print(rec.Body.String())
+	
+	fmt.Println("\nСборка с версией:")
+	fmt.Println(`  go build -ldflags "-X main.version=v1.0.0 -X main.commit=$(git rev-parse HEAD)" -o golearn .`)
+	fmt.Println("  ./golearn version")
+	fmt.Println("Встроенные метаданные любого бинарника: go version -m ./golearn")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		demo()
+		return
+	}
+	
+	switch os.Args[1] {
+	case "version":
+		if err := versionCommand(os.Args[2:]); err != nil {
+			os.Exit(2)
+		}
+	case "serve":
+		http.HandleFunc("/version", versionHandler)
+		log.Println("Версия:", Get())
+		log.Fatal(http.ListenAndServe(":8080", nil))
+	default:
+		fmt.Fprintf(os.Stderr, "Неизвестная команда %q\n", os.Args[1])
+		os.Exit(2)
+	}
+}
\ No newline at end of file
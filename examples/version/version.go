@@ -0,0 +1,112 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// Значения подставляются при сборке через -ldflags:
+//
+//	go build -ldflags "-X main.version=v1.4.0 \
+//	  -X main.commit=$(git rev-parse --short HEAD) \
+//	  -X main.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" .
+//
+// -X работает только с переменными типа string (не константами)
+// и требует полного пути пакета: для библиотеки это было бы
+// github.com/user/project/pkg/version.version.
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// Module зависимость из go.mod, попавшая в бинарник
+type Module struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Replace string `json:"replace,omitempty"`
+}
+
+// Info сведения о сборке
+type Info struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	Date      string   `json:"date"`
+	Modified  bool     `json:"modified"`
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+	Module    string   `json:"module,omitempty"`
+	Deps      []Module `json:"deps,omitempty"`
+}
+
+// Get собирает Info. Значения из -ldflags имеют приоритет; если их нет,
+// используются VCS-метаданные, которые go build (Go 1.18+) сам встраивает
+// при сборке внутри git-репозитория (флаг -buildvcs, по умолчанию auto).
+func Get() Info {
+	info := Info{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		// Бинарник собран без поддержки модулей
+		return info
+	}
+	
+	info.Module = bi.Main.Path
+	// go install module@v1.2.3 проставляет версию модуля сам
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = s.Value
+			}
+		case "vcs.modified":
+			info.Modified, _ = strconv.ParseBool(s.Value)
+		}
+	}
+	
+	for _, dep := range bi.Deps {
+		m := Module{Path: dep.Path, Version: dep.Version}
+		if dep.Replace != nil {
+			m.Replace = dep.Replace.Path + " " + dep.Replace.Version
+		}
+		info.Deps = append(info.Deps, m)
+	}
+	
+	return info
+}
+
+// String возвращает короткую строку для логов и вывода CLI
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		c := i.Commit
+		if len(c) > 12 {
+			c = c[:12]
+		}
+		s += " (" + c
+		if i.Modified {
+			s += ", dirty"
+		}
+		s += ")"
+	}
+	if t, err := time.Parse(time.RFC3339, i.Date); err == nil {
+		s += " built " + t.UTC().Format("2006-01-02 15:04")
+	}
+	return s + " " + i.GoVersion + " " + i.Platform
+}
\ No newline at end of file
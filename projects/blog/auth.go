@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ошибки входа
+var (
+	ErrBadCredentials = errors.New("invalid username or password")
+	ErrNoSession      = errors.New("no session")
+)
+
+// Пароль хранится как PBKDF2-SHA256 со случайной солью. bcrypt и argon2
+// из examples/passwords подходят не хуже; PBKDF2 взят потому, что он
+// пишется на crypto/hmac в двадцать строк, и блог зависит только от
+// драйвера SQLite. Число итераций - рекомендация OWASP; оно хранится в
+// самом хеше, поэтому его можно поднять, не ломая старые пароли.
+const pbkdf2KeyLength = 32
+
+// pbkdf2Iterations переменная, а не константа: тесты уменьшают ее, чтобы
+// не тратить по секунде на каждый вход
+var pbkdf2Iterations = 600_000
+
+// HashPassword возвращает строку "pbkdf2-sha256$итерации$соль$хеш"
+func HashPassword(password string) string {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	key := pbkdf2Key([]byte(password), salt, pbkdf2Iterations, pbkdf2KeyLength)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", pbkdf2Iterations, enc.EncodeToString(salt), enc.EncodeToString(key))
+}
+
+// CheckPassword сравнивает пароль с хешем за постоянное время
+func CheckPassword(password, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter < 1 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err1 := enc.DecodeString(parts[2])
+	want, err2 := enc.DecodeString(parts[3])
+	if err1 != nil || err2 != nil || len(want) == 0 {
+		return false
+	}
+	got := pbkdf2Key([]byte(password), salt, iter, len(want))
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// pbkdf2Key PBKDF2-HMAC-SHA256 по RFC 8018. Начиная с Go 1.24 то же
+// самое есть в стандартной библиотеке - crypto/pbkdf2.
+func pbkdf2Key(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		// U1 = PRF(соль || номер блока), Ui = PRF(Ui-1), блок = U1 ^ ... ^ Uiter
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write([]byte{byte(block >> 24), byte(block >> 16), byte(block >> 8), byte(block)})
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iter; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// Auth пользователи и сессии. Сессия хранится на сервере, в cookie -
+// только случайный токен. В БД лежит SHA-256 токена: утечка таблицы
+// sessions не дает готовых cookie для входа.
+type Auth struct {
+	db     *sql.DB
+	ttl    time.Duration
+	secure bool // Cookie только по HTTPS
+
+	// dummyHash сравнивается при входе с несуществующим логином, чтобы
+	// по времени ответа нельзя было узнать, какие логины есть
+	dummyHash string
+}
+
+// NewAuth создает сервис входа
+func NewAuth(db *sql.DB, ttl time.Duration, secure bool) *Auth {
+	return &Auth{db: db, ttl: ttl, secure: secure, dummyHash: HashPassword("dummy password")}
+}
+
+// SetPassword создает пользователя или меняет ему пароль. Все сессии
+// пользователя при этом закрываются.
+func (a *Auth) SetPassword(ctx context.Context, username, password string) error {
+	if len(password) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
+	_, err := a.db.ExecContext(ctx, `
+		INSERT INTO users (username, password_hash) VALUES (?, ?)
+		ON CONFLICT (username) DO UPDATE SET password_hash = excluded.password_hash`, username, HashPassword(password))
+	if err != nil {
+		return err
+	}
+	_, err = a.db.ExecContext(ctx, `
+		DELETE FROM sessions WHERE user_id = (SELECT id FROM users WHERE username = ?)`, username)
+	return err
+}
+
+// Session вошедший пользователь
+type Session struct {
+	UserID   int64
+	Username string
+	CSRF     string // Токен для скрытого поля форм
+}
+
+// Login проверяет пароль и создает сессию; возвращает токен для cookie
+func (a *Auth) Login(ctx context.Context, username, password string, now time.Time) (string, error) {
+	var userID int64
+	var hash string
+	err := a.db.QueryRowContext(ctx, `SELECT id, password_hash FROM users WHERE username = ?`, username).
+		Scan(&userID, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		CheckPassword(password, a.dummyHash)
+		return "", ErrBadCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+	if !CheckPassword(password, hash) {
+		return "", ErrBadCredentials
+	}
+	
+	token, csrf := randomToken(), randomToken()
+	_, err = a.db.ExecContext(ctx, `INSERT INTO sessions (token_hash, user_id, csrf, expires_at) VALUES (?, ?, ?, ?)`,
+		hashToken(token), userID, csrf, now.Add(a.ttl).UnixMilli())
+	return token, err
+}
+
+// Lookup находит живую сессию по токену
+func (a *Auth) Lookup(ctx context.Context, token string, now time.Time) (Session, error) {
+	var s Session
+	err := a.db.QueryRowContext(ctx, `
+		SELECT u.id, u.username, s.csrf FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = ? AND s.expires_at > ?`, hashToken(token), now.UnixMilli()).
+		Scan(&s.UserID, &s.Username, &s.CSRF)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, ErrNoSession
+	}
+	return s, err
+}
+
+// Logout удаляет сессию
+func (a *Auth) Logout(ctx context.Context, token string) error {
+	_, err := a.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ?`, hashToken(token))
+	return err
+}
+
+// DeleteExpired удаляет истекшие сессии
+func (a *Auth) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := a.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, now.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// sessionCookie имя cookie. Префикс __Host- браузер принимает только
+// с Secure, Path=/ и без Domain, поэтому его нельзя подложить с
+// поддомена; без HTTPS (локальная разработка) префикс не ставится.
+func (a *Auth) sessionCookie() string {
+	if a.secure {
+		return "__Host-session"
+	}
+	return "session"
+}
+
+// SetCookie отдает браузеру токен сессии. HttpOnly - недоступен из
+// JavaScript; SameSite=Lax - не отправляется с POST с чужих сайтов.
+func (a *Auth) SetCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     a.sessionCookie(),
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(a.ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   a.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearCookie удаляет cookie сессии
+func (a *Auth) ClearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: a.sessionCookie(), Value: "", Path: "/", MaxAge: -1,
+		HttpOnly: true, Secure: a.secure, SameSite: http.SameSiteLaxMode})
+}
+
+type sessionKey struct{}
+
+// SessionFrom возвращает сессию, положенную RequireLogin
+func SessionFrom(ctx context.Context) (Session, bool) {
+	s, ok := ctx.Value(sessionKey{}).(Session)
+	return s, ok
+}
+
+// RequireLogin пускает только вошедших; остальных отправляет на вход.
+// Изменяющие запросы дополнительно проверяют CSRF-токен из формы:
+// SameSite защищает не во всех браузерах и не от соседних поддоменов.
+func (a *Auth) RequireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(a.sessionCookie())
+		if err != nil {
+			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+			return
+		}
+		s, err := a.Lookup(r.Context(), cookie.Value, time.Now())
+		if errors.Is(err, ErrNoSession) {
+			a.ClearCookie(w)
+			http.Redirect(w, r, "/admin/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		if r.Method == http.MethodPost &&
+			subtle.ConstantTimeCompare([]byte(r.PostFormValue("csrf")), []byte(s.CSRF)) != 1 {
+			http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey{}, s)))
+	})
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
\ No newline at end of file
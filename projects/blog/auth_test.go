@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func init() {
+	// Полные 600 000 итераций под -race - секунды на каждый вход
+	pbkdf2Iterations = 1000
+}
+
+func TestPBKDF2Vectors(t *testing.T) {
+	// Векторы PBKDF2-HMAC-SHA256 из RFC 7914, раздел 11
+	tests := []struct {
+		password, salt string
+		iter, keyLen   int
+		want           string
+	}{
+		{"passwd", "salt", 1, 64, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+			"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, 64, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+			"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			got := hex.EncodeToString(pbkdf2Key([]byte(tt.password), []byte(tt.salt), tt.iter, tt.keyLen))
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash := HashPassword("correct horse")
+	if HashPassword("correct horse") == hash {
+		t.Error("Expected different salt for each hash")
+	}
+	
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		want     bool
+	}{
+		{"correct", "correct horse", hash, true},
+		{"wrong", "correct horsE", hash, false},
+		{"empty", "", hash, false},
+		{"garbage hash", "correct horse", "not-a-hash", false},
+		{"unknown algorithm", "correct horse", "md5$1$c2FsdA$aGFzaA", false},
+		{"zero iterations", "correct horse", "pbkdf2-sha256$0$c2FsdA$aGFzaA", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckPassword(tt.password, tt.encoded); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAuthSessions(t *testing.T) {
+	store := newTestStore(t)
+	auth := NewAuth(store.db, time.Hour, false)
+	ctx := context.Background()
+	now := time.Now()
+	
+	if err := auth.SetPassword(ctx, "admin", "short"); err == nil {
+		t.Error("Expected error for short password")
+	}
+	if err := auth.SetPassword(ctx, "admin", "secret123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := auth.Login(ctx, "admin", "wrong-password", now); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("Expected ErrBadCredentials, got %v", err)
+	}
+	if _, err := auth.Login(ctx, "nobody", "secret123", now); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("Expected ErrBadCredentials, got %v", err)
+	}
+	
+	token, err := auth.Login(ctx, "admin", "secret123", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s, err := auth.Lookup(ctx, token, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.Username != "admin" || s.CSRF == "" {
+		t.Errorf("Unexpected session: %+v", s)
+	}
+	
+	// В базе только хеш токена: сам токен по таблице не найти
+	var n int
+	store.db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE token_hash = ?`, token).Scan(&n)
+	if n != 0 {
+		t.Error("Expected token to be stored hashed")
+	}
+	
+	if _, err := auth.Lookup(ctx, token, now.Add(2*time.Hour)); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Expected expired session, got %v", err)
+	}
+	if deleted, _ := auth.DeleteExpired(ctx, now.Add(2*time.Hour)); deleted != 1 {
+		t.Errorf("Expected 1 expired session deleted, got %d", deleted)
+	}
+	
+	// Смена пароля закрывает все сессии
+	token, _ = auth.Login(ctx, "admin", "secret123", now)
+	if err := auth.SetPassword(ctx, "admin", "new-secret"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := auth.Lookup(ctx, token, now); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Expected session closed after password change, got %v", err)
+	}
+	
+	token, _ = auth.Login(ctx, "admin", "new-secret", now)
+	if err := auth.Logout(ctx, token); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := auth.Lookup(ctx, token, now); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Expected ErrNoSession after logout, got %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// RSS 2.0: канал с последними постами. Структуры повторяют XML один в
+// один, encoding/xml сам экранирует заголовки и текст.
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+// atomLink ссылка канала на самого себя - ее просят валидаторы фидов
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// WriteFeed пишет RSS по опубликованным постам. baseURL нужен потому,
+// что ссылки в фиде обязаны быть абсолютными: читалка открывает их вне
+// сайта. В description кладется HTML поста - читалки его отображают, а
+// encoding/xml экранирует его как текст.
+func WriteFeed(w io.Writer, title, baseURL string, posts []Post) error {
+	ch := rssChannel{
+		Title:       title,
+		Link:        baseURL + "/",
+		Description: "Последние записи " + title,
+		Self:        atomLink{Href: baseURL + "/feed.xml", Rel: "self", Type: "application/rss+xml"},
+	}
+	if len(posts) > 0 {
+		// Посты отсортированы по дате публикации, первый - самый свежий
+		ch.LastBuildDate = posts[0].PublishedAt.Format(time.RFC1123Z)
+	}
+	for _, p := range posts {
+		link := baseURL + "/posts/" + p.Slug
+		ch.Items = append(ch.Items, rssItem{
+			Title:       p.Title,
+			Link:        link,
+			GUID:        rssGUID{Value: link, IsPermaLink: true},
+			PubDate:     p.PublishedAt.Format(time.RFC1123Z),
+			Description: string(p.HTML),
+		})
+	}
+	
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(rss{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: ch})
+}
\ No newline at end of file
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Блог - итоговый проект, собранный из уроков:
+//   markdown.go - Markdown в HTML с экранированием и списком разрешенных
+//                 схем ссылок (examples/security-lab)
+//   store.go    - посты в SQLite, пагинация, миграции (examples/database)
+//   auth.go     - пароли PBKDF2, серверные сессии, CSRF, сравнение за
+//                 постоянное время (examples/passwords, examples/timing-attack)
+//   feed.go     - RSS 2.0 через encoding/xml
+//   server.go   - html/template с общим layout, админка, шаблоны и статика
+//                 встроены через embed (examples/http-server)
+//   main.go     - конфигурация, graceful shutdown (examples/signals)
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск:
+//   BLOG_ADMIN_PASSWORD=secret123 go run . -db blog.db -admin admin
+//   открыть http://localhost:8080/admin и войти; читатели видят /
+//   curl localhost:8080/feed.xml
+//
+// Деплой одним файлом: шаблоны и CSS встроены через embed, поэтому
+//   CGO_ENABLED=1 go build -o blog . && scp blog server:
+// и на сервере нужен только файл базы. Пароль задается переменной
+// окружения, а не флагом: аргументы процесса видны всем в ps.
+// За HTTPS-прокси запускать с -secure и -base-url https://blog.example.com.
+//
+// Тесты: go test -race ./...
+
+// Config параметры блога
+type Config struct {
+	Addr       string
+	DBPath     string
+	Title      string
+	BaseURL    string
+	Secure     bool
+	SessionTTL time.Duration
+	Admin      string // Создать или обновить пользователя при старте
+	Password   string
+}
+
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// run запускает блог и блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+	
+	store := NewStore(db)
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	auth := NewAuth(db, cfg.SessionTTL, cfg.Secure)
+	if cfg.Admin != "" {
+		if err := auth.SetPassword(ctx, cfg.Admin, cfg.Password); err != nil {
+			return fmt.Errorf("set admin password: %w", err)
+		}
+		log.Printf("Пароль пользователя %s обновлен", cfg.Admin)
+	}
+	
+	srv, err := NewServer(store, auth, cfg.Title, strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil {
+		return fmt.Errorf("parse templates: %w", err)
+	}
+	httpServer := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           srv.Routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Блог запущен на %s", cfg.Addr)
+		errCh <- httpServer.ListenAndServe()
+	}()
+	
+	// Истекшие сессии не найдет Lookup, но строки остаются в таблице -
+	// раз в час их удаляем
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if n, err := auth.DeleteExpired(ctx, time.Now()); err != nil {
+				log.Printf("Ошибка очистки сессий: %v", err)
+			} else if n > 0 {
+				log.Printf("Удалено истекших сессий: %d", n)
+			}
+		}
+	}()
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.Addr, "addr", ":8080", "адрес HTTP-сервера")
+	flag.StringVar(&cfg.DBPath, "db", "blog.db", "путь к файлу SQLite")
+	flag.StringVar(&cfg.Title, "title", "Блог о Go", "название блога")
+	flag.StringVar(&cfg.BaseURL, "base-url", "http://localhost:8080", "внешний адрес блога для ссылок в RSS")
+	flag.BoolVar(&cfg.Secure, "secure", false, "блог работает по HTTPS: cookie с флагом Secure")
+	flag.DurationVar(&cfg.SessionTTL, "session-ttl", 7*24*time.Hour, "время жизни сессии")
+	flag.StringVar(&cfg.Admin, "admin", "", "создать пользователя с паролем из BLOG_ADMIN_PASSWORD")
+	flag.Parse()
+	cfg.Password = os.Getenv("BLOG_ADMIN_PASSWORD")
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Markdown переводит подмножество Markdown в HTML:
+//
+//	# Заголовки (1-6 уровней)      ```lang ... ``` блоки кода
+//	абзацы через пустую строку     `код` внутри строки
+//	- списки и 1. нумерованные     **жирный** и *курсив*
+//	> цитаты                       [ссылки](https://...) и ![картинки](/img.png)
+//	--- разделитель
+//
+// В настоящем блоге здесь стоит github.com/yuin/goldmark: полный
+// CommonMark, таблицы, расширения. Своя реализация - чтобы проект
+// собирался без зависимостей, кроме SQLite, и чтобы было видно главное:
+// весь текст автора экранируется до разметки, поэтому HTML и <script>
+// из поста не попадут на страницу. Результат помечен template.HTML -
+// это обещание шаблону, что строка безопасна, и держится оно только на
+// экранировании здесь.
+func Markdown(src string) template.HTML {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	renderBlocks(&b, lines)
+	return template.HTML(b.String())
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	ulRe      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	olRe      = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	hrRe      = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		
+		switch {
+		case trimmed == "":
+			i++
+		
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			i++
+			start := i
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				i++
+			}
+			code := strings.Join(lines[start:i], "\n")
+			i++ // Закрывающая ``` (или конец текста)
+			b.WriteString("<pre><code")
+			if lang != "" {
+				b.WriteString(` class="language-` + html.EscapeString(strings.Fields(lang)[0]) + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(code) + "</code></pre>\n")
+		
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(m[1])))
+			b.WriteString("<h" + level + ">" + inline(m[2]) + "</h" + level + ">\n")
+			i++
+		
+		case hrRe.MatchString(trimmed):
+			b.WriteString("<hr>\n")
+			i++
+		
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+				i++
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quote)
+			b.WriteString("</blockquote>\n")
+		
+		case ulRe.MatchString(line), olRe.MatchString(line):
+			re, tag := ulRe, "ul"
+			if !ulRe.MatchString(line) {
+				re, tag = olRe, "ol"
+			}
+			b.WriteString("<" + tag + ">\n")
+			for i < len(lines) && re.MatchString(lines[i]) {
+				b.WriteString("<li>" + inline(re.FindStringSubmatch(lines[i])[1]) + "</li>\n")
+				i++
+			}
+			b.WriteString("</" + tag + ">\n")
+		
+		default:
+			// Абзац - до пустой строки или начала другого блока
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines[i])) {
+				para = append(para, strings.TrimSpace(lines[i]))
+				i++
+			}
+			b.WriteString("<p>" + inline(strings.Join(para, "\n")) + "</p>\n")
+		}
+	}
+}
+
+func startsBlock(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "```") || strings.HasPrefix(t, ">") || headingRe.MatchString(t) ||
+		hrRe.MatchString(t) || ulRe.MatchString(line) || olRe.MatchString(line)
+}
+
+var (
+	codeSpanRe = regexp.MustCompile("`([^`]+)`")
+	imageRe    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldRe     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicRe   = regexp.MustCompile(`\*([^*]+)\*`)
+)
+
+// inline размечает строку. Код внутри `...` не размечается, поэтому
+// текст делится на куски кода и обычного текста.
+func inline(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range codeSpanRe.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(inlineText(s[last:m[0]]))
+		b.WriteString("<code>" + html.EscapeString(s[m[2]:m[3]]) + "</code>")
+		last = m[1]
+	}
+	b.WriteString(inlineText(s[last:]))
+	return b.String()
+}
+
+func inlineText(s string) string {
+	// Сначала экранирование, потом разметка: все теги ниже добавлены
+	// нами, а не пришли из текста
+	s = html.EscapeString(s)
+	s = imageRe.ReplaceAllStringFunc(s, func(m string) string {
+		p := imageRe.FindStringSubmatch(m)
+		return `<img src="` + safeURL(p[2]) + `" alt="` + p[1] + `">`
+	})
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		p := linkRe.FindStringSubmatch(m)
+		return `<a href="` + safeURL(p[2]) + `">` + p[1] + `</a>`
+	})
+	s = boldRe.ReplaceAllString(s, "<strong>$1</strong>")
+	return italicRe.ReplaceAllString(s, "<em>$1</em>")
+}
+
+// safeURL пропускает только http(s), mailto и относительные адреса.
+// Экранирование не спасает от href="javascript:alert(1)" - там нет ни
+// одного опасного символа, поэтому нужен список разрешенных схем.
+func safeURL(escaped string) string {
+	u := strings.ToLower(html.UnescapeString(escaped))
+	for _, prefix := range []string{"http://", "https://", "mailto:", "/", "#"} {
+		if strings.HasPrefix(u, prefix) && !strings.HasPrefix(u, "//") {
+			return escaped
+		}
+	}
+	return "#"
+}
\ No newline at end of file
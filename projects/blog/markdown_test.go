@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"heading", "## Каналы", "<h2>Каналы</h2>\n"},
+		{"paragraphs", "first\nline\n\nsecond", "<p>first\nline</p>\n<p>second</p>\n"},
+		{"emphasis", "**bold** and *italic*", "<p><strong>bold</strong> and <em>italic</em></p>\n"},
+		{"code span", "call `fmt.Println(*x*)`", "<p>call <code>fmt.Println(*x*)</code></p>\n"},
+		{"fenced code", "```go\nif a < b {\n```", "<pre><code class=\"language-go\">if a &lt; b {</code></pre>\n"},
+		{"unordered list", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n"},
+		{"ordered list", "1. one\n2. two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"quote", "> quoted\n> text", "<blockquote>\n<p>quoted\ntext</p>\n</blockquote>\n"},
+		{"hr", "a\n\n---\n\nb", "<p>a</p>\n<hr>\n<p>b</p>\n"},
+		{"link", "[Go](https://go.dev)", "<p><a href=\"https://go.dev\">Go</a></p>\n"},
+		{"image", "![gopher](/static/gopher.png)", "<p><img src=\"/static/gopher.png\" alt=\"gopher\"></p>\n"},
+		{"heading after paragraph", "text\n# Title", "<p>text</p>\n<h1>Title</h1>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Markdown(tt.src)); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMarkdownEscapesHTML(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		forbidden string
+	}{
+		{"script tag", "<script>alert(1)</script>", "<script"},
+		{"raw html attribute", `<img src=x onerror="alert(1)">`, "<img"},
+		{"javascript link", "[click](javascript:alert(1))", "javascript:"},
+		{"mixed case scheme", "[click](JaVaScRiPt:alert(1))", "JaVaScRiPt:"},
+		{"data link", "[click](data:text/html,<b>x</b>)", "data:"},
+		{"protocol-relative link", "[click](//evil.example)", "//evil.example"},
+		{"quote breaks attribute", `[x](https://a.b/"onmouseover="alert(1))`, `"onmouseover`},
+		{"alt breaks attribute", `![" onerror="alert(1)](/a.png)`, `" onerror`},
+		{"html in code", "```\n</code><script>\n```", "<script"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(Markdown(tt.src))
+			if strings.Contains(got, tt.forbidden) {
+				t.Errorf("Expected output without %q, got %q", tt.forbidden, got)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Шаблоны и статика встраиваются в бинарник: для деплоя достаточно
+// скопировать один файл, а версия шаблонов всегда совпадает с кодом
+//
+//go:embed templates static
+var assets embed.FS
+
+// Параметры страниц
+const (
+	postsPerPage = 10
+	feedSize     = 20
+	maxFormSize  = 1 << 20
+)
+
+// pageNames страницы; каждая парсится вместе с layout.html в отдельный
+// набор шаблонов, иначе блоки "content" разных страниц перетирали бы
+// друг друга
+var pageNames = []string{"index", "post", "login", "admin", "edit"}
+
+// page данные для любого шаблона; каждая страница берет свои поля
+type page struct {
+	SiteTitle string
+	Title     string
+	Session   *Session
+
+	Posts    []Post
+	PrevPage int
+	NextPage int
+
+	Post   Post
+	Errors map[string]string
+
+	Error    string
+	Username string
+}
+
+// Server HTTP-слой блога
+type Server struct {
+	store     *Store
+	auth      *Auth
+	pages     map[string]*template.Template
+	siteTitle string
+	baseURL   string // Для абсолютных ссылок в RSS
+}
+
+// NewServer разбирает шаблоны; ошибка в шаблоне обнаруживается при
+// старте, а не на первом запросе к странице
+func NewServer(store *Store, auth *Auth, siteTitle, baseURL string) (*Server, error) {
+	s := &Server{store: store, auth: auth, pages: make(map[string]*template.Template),
+		siteTitle: siteTitle, baseURL: baseURL}
+	for _, name := range pageNames {
+		t, err := template.ParseFS(assets, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		s.pages[name] = t
+	}
+	return s, nil
+}
+
+// Routes возвращает маршруты:
+//
+//	GET  /                         опубликованные записи, ?page=N
+//	GET  /posts/{slug}             запись
+//	GET  /feed.xml                 RSS
+//	GET  /static/...               CSS
+//	GET  /admin/login, POST        вход
+//	POST /admin/logout
+//	GET  /admin                    все записи, включая черновики
+//	GET  /admin/posts/new          форма новой записи
+//	POST /admin/posts              создать
+//	GET  /admin/posts/{id}/edit    форма редактирования
+//	POST /admin/posts/{id}         сохранить
+//	POST /admin/posts/{id}/delete  удалить
+//
+// Формы HTML умеют только GET и POST, поэтому изменение и удаление -
+// POST на отдельные адреса, а не PUT и DELETE.
+func (s *Server) Routes() http.Handler {
+	static, _ := fs.Sub(assets, "static")
+	protect := func(h http.HandlerFunc) http.Handler { return s.auth.RequireLogin(h) }
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /posts/{slug}", s.handlePost)
+	mux.HandleFunc("GET /feed.xml", s.handleFeed)
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	
+	mux.HandleFunc("GET /admin/login", s.handleLoginForm)
+	mux.HandleFunc("POST /admin/login", s.handleLogin)
+	mux.Handle("POST /admin/logout", protect(s.handleLogout))
+	mux.Handle("GET /admin", protect(s.handleAdmin))
+	mux.Handle("GET /admin/posts/new", protect(s.handleNew))
+	mux.Handle("POST /admin/posts", protect(s.handleCreate))
+	mux.Handle("GET /admin/posts/{id}/edit", protect(s.handleEdit))
+	mux.Handle("POST /admin/posts/{id}", protect(s.handleUpdate))
+	mux.Handle("POST /admin/posts/{id}/delete", protect(s.handleDelete))
+	return secureHeaders(mux)
+}
+
+// secureHeaders заголовки, которые страхуют от ошибок в разметке: CSP
+// запрещает скрипты вовсе (блогу они не нужны), X-Frame-Options не дает
+// встроить админку в чужой iframe и обманом нажать "Удалить".
+// Заодно ограничивается размер форм.
+func secureHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", "default-src 'self'; img-src 'self' https: data:; script-src 'none'")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "same-origin")
+		r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 {
+			http.Error(w, "Invalid page", http.StatusBadRequest)
+			return
+		}
+	}
+	posts, hasNext, err := s.store.Published(r.Context(), (n-1)*postsPerPage, postsPerPage)
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	data := page{Posts: posts, PrevPage: n - 1}
+	if hasNext {
+		data.NextPage = n + 1
+	}
+	s.render(w, http.StatusOK, "index", data)
+}
+
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request) {
+	post, err := s.store.PublishedBySlug(r.Context(), r.PathValue("slug"))
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "Post not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	s.render(w, http.StatusOK, "post", page{Title: post.Title, Post: post})
+}
+
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	posts, _, err := s.store.Published(r.Context(), 0, feedSize)
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := WriteFeed(&buf, s.siteTitle, s.baseURL, posts); err != nil {
+		s.serverError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) handleLoginForm(w http.ResponseWriter, r *http.Request) {
+	s.render(w, http.StatusOK, "login", page{Title: "Вход"})
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	username := r.PostFormValue("username")
+	token, err := s.auth.Login(r.Context(), username, r.PostFormValue("password"), time.Now())
+	if errors.Is(err, ErrBadCredentials) {
+		// Одно сообщение для неверного логина и пароля: иначе форма
+		// подсказывает, какие логины существуют
+		s.render(w, http.StatusUnauthorized, "login",
+			page{Title: "Вход", Error: "Неверный логин или пароль", Username: username})
+		return
+	}
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	s.auth.SetCookie(w, token)
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(s.auth.sessionCookie()); err == nil {
+		if err := s.auth.Logout(r.Context(), cookie.Value); err != nil {
+			s.serverError(w, err)
+			return
+		}
+	}
+	s.auth.ClearCookie(w)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	posts, err := s.store.All(r.Context())
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	s.render(w, http.StatusOK, "admin", s.adminPage(r, page{Title: "Записи", Posts: posts}))
+}
+
+func (s *Server) handleNew(w http.ResponseWriter, r *http.Request) {
+	s.render(w, http.StatusOK, "edit", s.adminPage(r, page{Title: "Новая запись"}))
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	post := postFromForm(r)
+	if errs := post.Validate(); len(errs) > 0 {
+		s.render(w, http.StatusUnprocessableEntity, "edit", s.adminPage(r, page{Title: "Новая запись", Post: post, Errors: errs}))
+		return
+	}
+	_, err := s.store.Create(r.Context(), post, time.Now())
+	if errors.Is(err, ErrSlugTaken) {
+		s.render(w, http.StatusUnprocessableEntity, "edit", s.adminPage(r,
+			page{Title: "Новая запись", Post: post, Errors: map[string]string{"slug": "Такой адрес уже занят"}}))
+		return
+	}
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+func (s *Server) handleEdit(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	post, err := s.store.ByID(r.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "Post not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	s.render(w, http.StatusOK, "edit", s.adminPage(r, page{Title: post.Title, Post: post}))
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	post := postFromForm(r)
+	post.ID = id
+	if errs := post.Validate(); len(errs) > 0 {
+		s.render(w, http.StatusUnprocessableEntity, "edit", s.adminPage(r, page{Title: post.Title, Post: post, Errors: errs}))
+		return
+	}
+	_, err := s.store.Update(r.Context(), post, time.Now())
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, "Post not found", http.StatusNotFound)
+	case errors.Is(err, ErrSlugTaken):
+		s.render(w, http.StatusUnprocessableEntity, "edit", s.adminPage(r,
+			page{Title: post.Title, Post: post, Errors: map[string]string{"slug": "Такой адрес уже занят"}}))
+	case err != nil:
+		s.serverError(w, err)
+	default:
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	}
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	err := s.store.Delete(r.Context(), id)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "Post not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+// adminPage добавляет к данным сессию: шаблонам нужен CSRF-токен для форм
+func (s *Server) adminPage(r *http.Request, data page) page {
+	if sess, ok := SessionFrom(r.Context()); ok {
+		data.Session = &sess
+	}
+	return data
+}
+
+// render выполняет шаблон в буфер и только потом пишет ответ: ошибка
+// посреди шаблона дает 500, а не обрезанную страницу с кодом 200
+func (s *Server) render(w http.ResponseWriter, status int, name string, data page) {
+	data.SiteTitle = s.siteTitle
+	var buf bytes.Buffer
+	if err := s.pages[name].ExecuteTemplate(&buf, "layout", data); err != nil {
+		s.serverError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) serverError(w http.ResponseWriter, err error) {
+	log.Printf("Ошибка: %v", err)
+	http.Error(w, "Internal error", http.StatusInternalServerError)
+}
+
+func postFromForm(r *http.Request) Post {
+	return Post{
+		Title:     r.PostFormValue("title"),
+		Slug:      r.PostFormValue("slug"),
+		Markdown:  r.PostFormValue("markdown"),
+		Published: r.PostFormValue("published") == "1",
+	}
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
\ No newline at end of file
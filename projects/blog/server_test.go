@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEnv struct {
+	server *httptest.Server
+	store  *Store
+	client *http.Client // С cookie и без следования редиректам
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	store := newTestStore(t)
+	auth := NewAuth(store.db, time.Hour, false)
+	if err := auth.SetPassword(context.Background(), "admin", "secret123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	srv, err := NewServer(store, auth, "Test Blog", "https://blog.example")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ts := httptest.NewServer(srv.Routes())
+	t.Cleanup(ts.Close)
+	
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Jar:           jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return &testEnv{ts, store, client}
+}
+
+func (e *testEnv) get(t *testing.T, path string) (*http.Response, string) {
+	t.Helper()
+	resp, err := e.client.Get(e.server.URL + path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func (e *testEnv) post(t *testing.T, path string, form url.Values) (*http.Response, string) {
+	t.Helper()
+	resp, err := e.client.PostForm(e.server.URL+path, form)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+var csrfRe = regexp.MustCompile(`name="csrf" value="([^"]+)"`)
+
+// login входит и возвращает CSRF-токен со страницы админки
+func (e *testEnv) login(t *testing.T) string {
+	t.Helper()
+	resp, _ := e.post(t, "/admin/login", url.Values{"username": {"admin"}, "password": {"secret123"}})
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/admin" {
+		t.Fatalf("Expected redirect to /admin, got %d", resp.StatusCode)
+	}
+	_, body := e.get(t, "/admin")
+	m := csrfRe.FindStringSubmatch(body)
+	if m == nil {
+		t.Fatal("Expected CSRF token on admin page")
+	}
+	return m[1]
+}
+
+func TestServerAdminRequiresLogin(t *testing.T) {
+	env := newTestEnv(t)
+	for _, path := range []string{"/admin", "/admin/posts/new", "/admin/posts/1/edit"} {
+		resp, _ := env.get(t, path)
+		if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/admin/login" {
+			t.Errorf("%s: expected redirect to login, got %d", path, resp.StatusCode)
+		}
+	}
+	resp, _ := env.post(t, "/admin/posts", url.Values{"title": {"x"}})
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("Expected redirect for anonymous POST, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerLogin(t *testing.T) {
+	env := newTestEnv(t)
+	
+	resp, body := env.post(t, "/admin/login", url.Values{"username": {"admin"}, "password": {"wrong-password"}})
+	if resp.StatusCode != http.StatusUnauthorized || !strings.Contains(body, "Неверный логин или пароль") {
+		t.Errorf("Expected 401 with message, got %d", resp.StatusCode)
+	}
+	
+	env.login(t)
+	var session *http.Cookie
+	for _, c := range env.client.Jar.Cookies(mustParse(t, env.server.URL)) {
+		if c.Name == "session" {
+			session = c
+		}
+	}
+	if session == nil {
+		t.Fatal("Expected session cookie")
+	}
+	
+	resp, _ = env.get(t, "/admin")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 after login, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerLogout(t *testing.T) {
+	env := newTestEnv(t)
+	csrf := env.login(t)
+	
+	resp, _ := env.post(t, "/admin/logout", url.Values{"csrf": {csrf}})
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d", resp.StatusCode)
+	}
+	resp, _ = env.get(t, "/admin")
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Errorf("Expected redirect to login after logout, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerCSRF(t *testing.T) {
+	env := newTestEnv(t)
+	env.login(t)
+	
+	for _, token := range []string{"", "forged"} {
+		resp, _ := env.post(t, "/admin/posts", url.Values{
+			"csrf": {token}, "title": {"Hi"}, "slug": {"hi"}, "markdown": {"x"}, "published": {"1"},
+		})
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected 403 for token %q, got %d", token, resp.StatusCode)
+		}
+	}
+	if posts, _ := env.store.All(context.Background()); len(posts) != 0 {
+		t.Errorf("Expected no posts created, got %d", len(posts))
+	}
+}
+
+func TestServerPostLifecycle(t *testing.T) {
+	env := newTestEnv(t)
+	csrf := env.login(t)
+	
+	// Ошибки валидации возвращают форму с введенными данными
+	resp, body := env.post(t, "/admin/posts", url.Values{"csrf": {csrf}, "title": {"Draft <b>"}, "slug": {"bad slug"}})
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(body, `value="Draft &lt;b&gt;"`) || !strings.Contains(body, "Текст не может быть пустым") {
+		t.Error("Expected form with escaped values and errors")
+	}
+	
+	// Черновик виден в админке, но не читателям
+	resp, _ = env.post(t, "/admin/posts", url.Values{
+		"csrf": {csrf}, "title": {"Hello"}, "slug": {"hello"}, "markdown": {"# Hi\n\n<script>x</script>"},
+	})
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d", resp.StatusCode)
+	}
+	if resp, _ := env.get(t, "/posts/hello"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected draft hidden, got %d", resp.StatusCode)
+	}
+	if _, body := env.get(t, "/"); strings.Contains(body, "Hello") {
+		t.Error("Expected draft missing from index")
+	}
+	
+	resp, _ = env.post(t, "/admin/posts", url.Values{"csrf": {csrf}, "title": {"Dup"}, "slug": {"hello"}, "markdown": {"x"}})
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for duplicate slug, got %d", resp.StatusCode)
+	}
+	
+	posts, _ := env.store.All(context.Background())
+	id := posts[0].ID
+	if resp, body := env.get(t, "/admin/posts/"+itoa(id)+"/edit"); resp.StatusCode != http.StatusOK || !strings.Contains(body, "# Hi") {
+		t.Errorf("Expected edit form with markdown, got %d", resp.StatusCode)
+	}
+	
+	resp, _ = env.post(t, "/admin/posts/"+itoa(id), url.Values{
+		"csrf": {csrf}, "title": {"Hello"}, "slug": {"hello"}, "markdown": {"# Hi\n\n<script>x</script>"}, "published": {"1"},
+	})
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d", resp.StatusCode)
+	}
+	resp, body = env.get(t, "/posts/hello")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected published post, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(body, "<h1>Hi</h1>") || strings.Contains(body, "<script>") {
+		t.Error("Expected rendered markdown without raw script")
+	}
+	if resp.Header.Get("Content-Security-Policy") == "" || resp.Header.Get("X-Frame-Options") != "DENY" {
+		t.Error("Expected security headers")
+	}
+	if _, body := env.get(t, "/"); !strings.Contains(body, `href="/posts/hello"`) {
+		t.Error("Expected published post on index")
+	}
+	
+	resp, _ = env.post(t, "/admin/posts/"+itoa(id)+"/delete", url.Values{"csrf": {csrf}})
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("Expected redirect, got %d", resp.StatusCode)
+	}
+	if resp, _ := env.get(t, "/posts/hello"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 after delete, got %d", resp.StatusCode)
+	}
+	if resp, _ := env.post(t, "/admin/posts/"+itoa(id)+"/delete", url.Values{"csrf": {csrf}}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for second delete, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerIndexPagination(t *testing.T) {
+	env := newTestEnv(t)
+	t0 := time.Now()
+	for i := range postsPerPage + 1 {
+		p := Post{Slug: "p-" + itoa(int64(i)), Title: "Post " + itoa(int64(i)), Markdown: "x", Published: true}
+		if _, err := env.store.Create(context.Background(), p, t0.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	
+	_, body := env.get(t, "/")
+	if !strings.Contains(body, "/?page=2") || strings.Contains(body, `href="/posts/p-0"`) {
+		t.Error("Expected first page with link to second and without oldest post")
+	}
+	_, body = env.get(t, "/?page=2")
+	if !strings.Contains(body, `href="/posts/p-0"`) || !strings.Contains(body, "/?page=1") || strings.Contains(body, "/?page=3") {
+		t.Error("Expected second page with oldest post and link back")
+	}
+	if resp, _ := env.get(t, "/?page=0"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerFeed(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	env.store.Create(ctx, Post{Slug: "old", Title: "Old & gold", Markdown: "*x*", Published: true}, t0)
+	env.store.Create(ctx, Post{Slug: "new", Title: "New", Markdown: "y", Published: true}, t0.Add(time.Hour))
+	env.store.Create(ctx, Post{Slug: "draft", Title: "Draft", Markdown: "z"}, t0.Add(2*time.Hour))
+	
+	resp, body := env.get(t, "/feed.xml")
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/rss+xml") {
+		t.Errorf("Unexpected Content-Type %q", ct)
+	}
+	var feed rss
+	if err := xml.Unmarshal([]byte(body), &feed); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	items := feed.Channel.Items
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items without draft, got %d", len(items))
+	}
+	if items[0].Link != "https://blog.example/posts/new" || items[1].Title != "Old & gold" {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+	if items[1].Description != "<p><em>x</em></p>\n" {
+		t.Errorf("Expected HTML description, got %q", items[1].Description)
+	}
+	if items[1].PubDate != "Fri, 01 Mar 2024 12:00:00 +0000" {
+		t.Errorf("Unexpected pubDate %q", items[1].PubDate)
+	}
+}
+
+func TestServerStatic(t *testing.T) {
+	env := newTestEnv(t)
+	resp, body := env.get(t, "/static/style.css")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "max-width") {
+		t.Errorf("Expected embedded CSS, got %d", resp.StatusCode)
+	}
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return u
+}
\ No newline at end of file
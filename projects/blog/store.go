@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Ошибки хранилища
+var (
+	ErrNotFound  = errors.New("post not found")
+	ErrSlugTaken = errors.New("slug already taken")
+)
+
+// Post запись блога. HTML рендерится из Markdown при сохранении, а не
+// на каждый просмотр: постов читают на порядки больше, чем пишут.
+type Post struct {
+	ID          int64
+	Slug        string
+	Title       string
+	Markdown    string
+	HTML        template.HTML
+	Published   bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	PublishedAt time.Time // Нулевое, пока пост - черновик
+}
+
+// slugPattern адрес поста: латиница, цифры и дефисы
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Validate проверяет поля перед сохранением; возвращает сообщения
+// для формы по именам полей
+func (p *Post) Validate() map[string]string {
+	errs := make(map[string]string)
+	p.Title = strings.TrimSpace(p.Title)
+	p.Slug = strings.ToLower(strings.TrimSpace(p.Slug))
+	if p.Title == "" {
+		errs["title"] = "Укажите заголовок"
+	}
+	if len(p.Slug) > 100 || !slugPattern.MatchString(p.Slug) {
+		errs["slug"] = "Адрес: латинские буквы, цифры и дефисы, до 100 символов"
+	}
+	if strings.TrimSpace(p.Markdown) == "" {
+		errs["markdown"] = "Текст не может быть пустым"
+	}
+	return errs
+}
+
+// Store посты, пользователи и сессии в SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore создает хранилище
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate создает таблицы. Индекс по (published, published_at)
+// обслуживает главную страницу и RSS.
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS posts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		slug TEXT NOT NULL UNIQUE,
+		title TEXT NOT NULL,
+		markdown TEXT NOT NULL,
+		html TEXT NOT NULL,
+		published INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		published_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_posts_published ON posts(published, published_at);
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS sessions (
+		token_hash TEXT PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		csrf TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	);`)
+	return err
+}
+
+// Create сохраняет новый пост
+func (s *Store) Create(ctx context.Context, p Post, now time.Time) (Post, error) {
+	p.HTML = Markdown(p.Markdown)
+	p.CreatedAt, p.UpdatedAt = now, now
+	if p.Published {
+		p.PublishedAt = now
+	}
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO posts (slug, title, markdown, html, published, created_at, updated_at, published_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.Slug, p.Title, p.Markdown, string(p.HTML), p.Published,
+		now.UnixMilli(), now.UnixMilli(), unixMilli(p.PublishedAt))
+	if err != nil {
+		return Post{}, uniqueErr(err)
+	}
+	p.ID, err = res.LastInsertId()
+	return p, err
+}
+
+// Update сохраняет изменения. Дата публикации ставится при первой
+// публикации и не меняется при правках: иначе исправленная опечатка
+// поднимала бы старый пост наверх ленты и RSS.
+func (s *Store) Update(ctx context.Context, p Post, now time.Time) (Post, error) {
+	old, err := s.ByID(ctx, p.ID)
+	if err != nil {
+		return Post{}, err
+	}
+	p.HTML = Markdown(p.Markdown)
+	p.CreatedAt, p.UpdatedAt, p.PublishedAt = old.CreatedAt, now, old.PublishedAt
+	if p.Published && p.PublishedAt.IsZero() {
+		p.PublishedAt = now
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE posts SET slug = ?, title = ?, markdown = ?, html = ?, published = ?, updated_at = ?, published_at = ?
+		WHERE id = ?`,
+		p.Slug, p.Title, p.Markdown, string(p.HTML), p.Published, now.UnixMilli(), unixMilli(p.PublishedAt), p.ID)
+	if err != nil {
+		return Post{}, uniqueErr(err)
+	}
+	return p, nil
+}
+
+// Delete удаляет пост
+func (s *Store) Delete(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM posts WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const postColumns = `id, slug, title, markdown, html, published, created_at, updated_at, published_at`
+
+// ByID возвращает пост, в том числе черновик
+func (s *Store) ByID(ctx context.Context, id int64) (Post, error) {
+	return scanPost(s.db.QueryRowContext(ctx, `SELECT `+postColumns+` FROM posts WHERE id = ?`, id))
+}
+
+// PublishedBySlug возвращает опубликованный пост; черновик для читателя
+// не существует
+func (s *Store) PublishedBySlug(ctx context.Context, slug string) (Post, error) {
+	return scanPost(s.db.QueryRowContext(ctx,
+		`SELECT `+postColumns+` FROM posts WHERE slug = ? AND published = 1`, slug))
+}
+
+// Published возвращает страницу опубликованных постов, новые первыми,
+// и признак, что есть следующая страница
+func (s *Store) Published(ctx context.Context, offset, limit int) ([]Post, bool, error) {
+	// Запрашиваем на одну запись больше, чтобы узнать о следующей
+	// странице без отдельного COUNT
+	posts, err := s.list(ctx, `SELECT `+postColumns+` FROM posts WHERE published = 1
+		ORDER BY published_at DESC, id DESC LIMIT ? OFFSET ?`, limit+1, offset)
+	if err != nil || len(posts) <= limit {
+		return posts, false, err
+	}
+	return posts[:limit], true, nil
+}
+
+// All возвращает все посты для админки, последние измененные первыми
+func (s *Store) All(ctx context.Context) ([]Post, error) {
+	return s.list(ctx, `SELECT `+postColumns+` FROM posts ORDER BY updated_at DESC, id DESC`)
+}
+
+func (s *Store) list(ctx context.Context, query string, args ...any) ([]Post, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var posts []Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPost(row scanner) (Post, error) {
+	var p Post
+	var html string
+	var created, updated, published int64
+	err := row.Scan(&p.ID, &p.Slug, &p.Title, &p.Markdown, &html, &p.Published, &created, &updated, &published)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Post{}, ErrNotFound
+	}
+	if err != nil {
+		return Post{}, err
+	}
+	// В колонку html пишут только Create и Update результатом Markdown,
+	// поэтому ей можно доверять так же, как самому Markdown
+	p.HTML = template.HTML(html)
+	p.CreatedAt, p.UpdatedAt = time.UnixMilli(created).UTC(), time.UnixMilli(updated).UTC()
+	if published != 0 {
+		p.PublishedAt = time.UnixMilli(published).UTC()
+	}
+	return p, nil
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func uniqueErr(err error) error {
+	if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrSlugTaken
+	}
+	return err
+}
\ No newline at end of file
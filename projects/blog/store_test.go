@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestStore создает хранилище в отдельном файле для каждого теста
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	store := NewStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return store
+}
+
+func TestPostValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		post   Post
+		fields []string
+	}{
+		{"valid", Post{Title: "Hello", Slug: "hello-world", Markdown: "text"}, nil},
+		{"slug normalized", Post{Title: "Hello", Slug: "  Hello-World ", Markdown: "text"}, nil},
+		{"empty", Post{}, []string{"markdown", "slug", "title"}},
+		{"bad slug", Post{Title: "Hello", Slug: "hello world", Markdown: "text"}, []string{"slug"}},
+		{"trailing dash", Post{Title: "Hello", Slug: "hello-", Markdown: "text"}, []string{"slug"}},
+		{"long slug", Post{Title: "Hello", Slug: strings.Repeat("a", 101), Markdown: "text"}, []string{"slug"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.post.Validate()
+			if len(errs) != len(tt.fields) {
+				t.Fatalf("Expected errors for %v, got %v", tt.fields, errs)
+			}
+			for _, f := range tt.fields {
+				if errs[f] == "" {
+					t.Errorf("Expected error for %q, got %v", f, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreCreateAndUpdate(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	
+	draft, err := store.Create(ctx, Post{Slug: "draft", Title: "Draft", Markdown: "**hi**"}, t0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if draft.HTML != "<p><strong>hi</strong></p>\n" {
+		t.Errorf("Unexpected HTML: %q", draft.HTML)
+	}
+	if _, err := store.PublishedBySlug(ctx, "draft"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected draft to be hidden, got %v", err)
+	}
+	
+	// Первая публикация ставит дату, следующие правки ее не трогают
+	draft.Published = true
+	if _, err := store.Update(ctx, draft, t0.Add(time.Hour)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	draft.Markdown = "fixed typo"
+	if _, err := store.Update(ctx, draft, t0.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, err := store.PublishedBySlug(ctx, "draft")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !got.PublishedAt.Equal(t0.Add(time.Hour)) {
+		t.Errorf("Expected published at %v, got %v", t0.Add(time.Hour), got.PublishedAt)
+	}
+	if !got.UpdatedAt.Equal(t0.Add(2*time.Hour)) || !got.CreatedAt.Equal(t0) {
+		t.Errorf("Unexpected timestamps: created %v, updated %v", got.CreatedAt, got.UpdatedAt)
+	}
+	if got.HTML != "<p>fixed typo</p>\n" {
+		t.Errorf("Expected re-rendered HTML, got %q", got.HTML)
+	}
+	
+	if _, err := store.Create(ctx, Post{Slug: "draft", Title: "Again", Markdown: "x"}, t0); !errors.Is(err, ErrSlugTaken) {
+		t.Errorf("Expected ErrSlugTaken, got %v", err)
+	}
+	if _, err := store.Update(ctx, Post{ID: 999, Slug: "x", Title: "x", Markdown: "x"}, t0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStorePublishedPagination(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		p := Post{Slug: "post-" + string(rune('a'+i)), Title: "Post", Markdown: "x", Published: i != 2}
+		if _, err := store.Create(ctx, p, t0.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	
+	page1, hasNext, err := store.Published(ctx, 0, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page1) != 3 || !hasNext {
+		t.Fatalf("Expected 3 posts and next page, got %d, %v", len(page1), hasNext)
+	}
+	if page1[0].Slug != "post-e" || page1[2].Slug != "post-b" {
+		t.Errorf("Expected newest first without drafts, got %s..%s", page1[0].Slug, page1[2].Slug)
+	}
+	
+	page2, hasNext, err := store.Published(ctx, 3, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page2) != 1 || hasNext || page2[0].Slug != "post-a" {
+		t.Errorf("Expected last page with post-a, got %d posts, next %v", len(page2), hasNext)
+	}
+	
+	all, err := store.All(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("Expected 5 posts including draft, got %d", len(all))
+	}
+}
+
+func TestStoreDelete(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	p, err := store.Create(ctx, Post{Slug: "bye", Title: "Bye", Markdown: "x"}, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Delete(ctx, p.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
\ No newline at end of file
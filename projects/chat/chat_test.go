@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testChat struct {
+	server *httptest.Server
+	store  *Store
+	hub    *Hub
+	stop   func()
+}
+
+func newTestChat(t *testing.T) *testChat {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "chat.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := NewStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	hub := NewHub(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		hub.Run(ctx)
+	}()
+	ts := httptest.NewServer(NewServer(hub, store).Routes())
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+	// Сначала хаб закрывает WebSocket-соединения, иначе ts.Close их ждет
+	t.Cleanup(func() {
+		stop()
+		ts.Close()
+	})
+	return &testChat{ts, store, hub, stop}
+}
+
+func (tc *testChat) join(t *testing.T, room, user string) *Conn {
+	t.Helper()
+	c := dial(t, tc.server.URL, fmt.Sprintf("/ws?room=%s&user=%s", room, user))
+	if ev := readEvent(t, c); ev.Type != "history" {
+		t.Fatalf("Expected history first, got %+v", ev)
+	}
+	return c
+}
+
+func readEvent(t *testing.T, c *Conn) Event {
+	t.Helper()
+	data, err := c.ReadMessage()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var ev Event
+	if err := json.Unmarshal(data, &ev); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return ev
+}
+
+// expect читает события, пока не встретит нужный тип
+func expect(t *testing.T, c *Conn, typ string) Event {
+	t.Helper()
+	for range 10 {
+		if ev := readEvent(t, c); ev.Type == typ {
+			return ev
+		}
+	}
+	t.Fatalf("Event %q not received", typ)
+	return Event{}
+}
+
+func say(t *testing.T, c *Conn, text string) {
+	t.Helper()
+	data, _ := json.Marshal(map[string]string{"text": text})
+	if err := c.WriteText(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestChatMessagesAndPresence(t *testing.T) {
+	tc := newTestChat(t)
+	
+	alice := tc.join(t, "general", "alice")
+	expect(t, alice, "join") // О себе
+	bob := tc.join(t, "general", "bob")
+	if ev := expect(t, alice, "join"); ev.User != "bob" || !slices.Equal(ev.Online, []string{"alice", "bob"}) {
+		t.Errorf("Unexpected join event %+v", ev)
+	}
+	expect(t, bob, "join")
+	
+	// Сообщение из другой комнаты не должно прийти
+	carol := tc.join(t, "random", "carol")
+	say(t, carol, "не для general")
+	
+	say(t, alice, "привет")
+	for _, c := range []*Conn{alice, bob} {
+		ev := expect(t, c, "message")
+		if ev.Message.Text != "привет" || ev.Message.User != "alice" || ev.Message.Room != "general" {
+			t.Errorf("Unexpected message %+v", ev.Message)
+		}
+	}
+	
+	rooms := tc.hub.Rooms()
+	if len(rooms) != 2 || rooms[0].Name != "general" || !slices.Equal(rooms[0].Online, []string{"alice", "bob"}) {
+		t.Errorf("Unexpected rooms %+v", rooms)
+	}
+	
+	bob.Close(CloseNormal, "")
+	if ev := expect(t, alice, "leave"); ev.User != "bob" || !slices.Equal(ev.Online, []string{"alice"}) {
+		t.Errorf("Unexpected leave event %+v", ev)
+	}
+}
+
+func TestChatHistory(t *testing.T) {
+	tc := newTestChat(t)
+	
+	alice := tc.join(t, "dev", "alice")
+	for i := range 3 {
+		say(t, alice, fmt.Sprintf("msg %d", i))
+		expect(t, alice, "message")
+	}
+	say(t, alice, "   ") // Пустые сообщения игнорируются
+	
+	// Новый участник получает историю при входе
+	c := dial(t, tc.server.URL, "/ws?room=dev&user=bob")
+	ev := readEvent(t, c)
+	if ev.Type != "history" || len(ev.History) != 3 || ev.History[0].Text != "msg 0" {
+		t.Fatalf("Unexpected history %+v", ev)
+	}
+	
+	// Пагинация по курсору через REST
+	resp, err := http.Get(fmt.Sprintf("%s/api/rooms/dev/messages?before=%d&limit=1", tc.server.URL, ev.History[2].ID))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	var page []Message
+	json.NewDecoder(resp.Body).Decode(&page)
+	if len(page) != 1 || page[0].Text != "msg 1" {
+		t.Errorf("Expected [msg 1], got %+v", page)
+	}
+}
+
+func TestChatRejectsBadNames(t *testing.T) {
+	tc := newTestChat(t)
+	for _, path := range []string{"/ws?room=general", "/ws?room=a%20b&user=x", "/ws?room=general&user=" + strings.Repeat("a", 40)} {
+		if _, err := dialErr(tc.server.URL, path); err == nil {
+			t.Errorf("%s: expected rejection", path)
+		}
+	}
+}
+
+func TestSlowClientDropped(t *testing.T) {
+	tc := newTestChat(t)
+	
+	tc.join(t, "load", "slow")
+	fast := tc.join(t, "load", "fast")
+	
+	// slow не читает: его очередь и TCP-буферы заполняются,
+	// хаб отключает его, а fast продолжает получать сообщения
+	big := strings.Repeat("x", maxTextLength)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		say(t, fast, big)
+		expect(t, fast, "message")
+		if online := tc.hub.Rooms(); len(online) == 1 && slices.Equal(online[0].Online, []string{"fast"}) {
+			return
+		}
+	}
+	t.Fatal("Slow client was not dropped")
+}
+
+func TestShutdownClosesClients(t *testing.T) {
+	tc := newTestChat(t)
+	c := tc.join(t, "general", "alice")
+	expect(t, c, "join")
+	
+	tc.stop()
+	_, err := c.ReadMessage()
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected close frame on shutdown, got %v", err)
+	}
+}
\ No newline at end of file
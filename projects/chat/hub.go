@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	historySize   = 50               // Сообщений истории при входе в комнату
+	maxTextLength = 2000             // Символов в сообщении
+	sendBuffer    = 64               // Исходящих сообщений в очереди клиента
+	pingPeriod    = 30 * time.Second // Как часто сервер шлет ping
+	pongWait      = 2 * pingPeriod   // Сколько ждать любого кадра от клиента
+	writeWait     = 10 * time.Second
+	saveTimeout   = 5 * time.Second
+)
+
+// Event событие, которое получает клиент
+type Event struct {
+	Type    string    `json:"type"` // message, history, join, leave, error
+	Message *Message  `json:"message,omitempty"`
+	History []Message `json:"history,omitempty"`
+	User    string    `json:"user,omitempty"`
+	Online  []string  `json:"online,omitempty"` // Кто в комнате - для join/leave и history
+	Error   string    `json:"error,omitempty"`
+}
+
+// RoomInfo комната и кто в ней
+type RoomInfo struct {
+	Name   string   `json:"name"`
+	Online []string `json:"online"`
+}
+
+// Client участник чата: одно WebSocket-соединение в одной комнате
+type Client struct {
+	hub  *Hub
+	conn *Conn
+	user string
+	room string
+
+	// send - очередь исходящих: пишет только хаб, читает только writePump.
+	// Хаб закрывает канал, когда отключает клиента.
+	send chan []byte
+}
+
+type inbound struct {
+	client *Client
+	text   string
+}
+
+// Hub владеет всем состоянием чата - комнатами и участниками - и
+// меняет его только из своей горутины. Остальные горутины общаются
+// с ним через каналы, поэтому мьютексы не нужны, а порядок событий
+// в комнате одинаков для всех ее участников.
+type Hub struct {
+	store *Store
+	now   func() time.Time
+
+	register   chan *Client
+	unregister chan *Client
+	incoming   chan inbound
+	queries    chan func() // Функции, выполняемые в горутине хаба
+	done       chan struct{}
+
+	rooms map[string]map[*Client]bool
+}
+
+// NewHub создает хаб
+func NewHub(store *Store) *Hub {
+	return &Hub{
+		store:      store,
+		now:        time.Now,
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		incoming:   make(chan inbound),
+		queries:    make(chan func()),
+		done:       make(chan struct{}),
+		rooms:      make(map[string]map[*Client]bool),
+	}
+}
+
+// Run обрабатывает события до отмены ctx, затем отключает всех клиентов.
+// Соединения захвачены через Hijack, и http.Server.Shutdown их не
+// закрывает - это делает хаб.
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+	for {
+		select {
+		case c := <-h.register:
+			h.join(c)
+		case c := <-h.unregister:
+			h.leave(c)
+		case in := <-h.incoming:
+			h.post(in)
+		case fn := <-h.queries:
+			fn()
+		case <-ctx.Done():
+			for _, clients := range h.rooms {
+				for c := range clients {
+					close(c.send) // writePump отправит close и закроет соединение
+				}
+			}
+			clear(h.rooms)
+			return
+		}
+	}
+}
+
+// Rooms возвращает комнаты с участниками онлайн
+func (h *Hub) Rooms() []RoomInfo {
+	reply := make(chan []RoomInfo, 1)
+	select {
+	case h.queries <- func() {
+		rooms := []RoomInfo{}
+		for name := range h.rooms {
+			rooms = append(rooms, RoomInfo{name, h.online(name)})
+		}
+		slices.SortFunc(rooms, func(a, b RoomInfo) int { return strings.Compare(a.Name, b.Name) })
+		reply <- rooms
+	}:
+		return <-reply
+	case <-h.done:
+		return []RoomInfo{}
+	}
+}
+
+func (h *Hub) join(c *Client) {
+	clients := h.rooms[c.room]
+	if clients == nil {
+		clients = make(map[*Client]bool)
+		h.rooms[c.room] = clients
+	}
+	clients[c] = true
+	
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	history, err := h.store.History(ctx, c.room, 0, historySize)
+	cancel()
+	if err != nil {
+		log.Printf("Ошибка чтения истории %s: %v", c.room, err)
+	}
+	online := h.online(c.room)
+	h.deliver(c, Event{Type: "history", History: history, Online: online})
+	h.broadcast(c.room, Event{Type: "join", User: c.user, Online: online})
+}
+
+func (h *Hub) leave(c *Client) {
+	clients := h.rooms[c.room]
+	if !clients[c] {
+		return // Уже отключен хабом как медленный
+	}
+	h.drop(c)
+	h.broadcast(c.room, Event{Type: "leave", User: c.user, Online: h.online(c.room)})
+}
+
+// drop убирает клиента из комнаты и закрывает его очередь
+func (h *Hub) drop(c *Client) {
+	clients := h.rooms[c.room]
+	delete(clients, c)
+	close(c.send)
+	if len(clients) == 0 {
+		delete(h.rooms, c.room)
+	}
+}
+
+// post сохраняет сообщение и рассылает его. Запись в БД идет в горутине
+// хаба: это сериализует сообщения, и ID в истории совпадает с порядком
+// доставки. Для SQLite с одним писателем это ничего не стоит; при
+// нагрузке выше хаб шардируют по комнатам.
+func (h *Hub) post(in inbound) {
+	c := in.client
+	if !h.rooms[c.room][c] {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+	msg, err := h.store.Save(ctx, Message{Room: c.room, User: c.user, Text: in.text, Time: h.now().UTC()})
+	if err != nil {
+		log.Printf("Ошибка сохранения сообщения: %v", err)
+		h.deliver(c, Event{Type: "error", Error: "message not saved"})
+		return
+	}
+	h.broadcast(c.room, Event{Type: "message", Message: &msg})
+}
+
+func (h *Hub) online(room string) []string {
+	users := []string{}
+	for c := range h.rooms[room] {
+		users = append(users, c.user)
+	}
+	slices.Sort(users)
+	return slices.Compact(users) // Один пользователь может сидеть с двух вкладок
+}
+
+func (h *Hub) broadcast(room string, ev Event) {
+	data, _ := json.Marshal(ev)
+	for c := range h.rooms[room] {
+		h.send(c, data)
+	}
+}
+
+func (h *Hub) deliver(c *Client, ev Event) {
+	data, _ := json.Marshal(ev)
+	h.send(c, data)
+}
+
+// send кладет сообщение в очередь клиента, не блокируясь. Клиент, чья
+// очередь заполнена, не успевает читать - его отключают, иначе один
+// медленный участник задержал бы всю комнату.
+func (h *Hub) send(c *Client, data []byte) {
+	select {
+	case c.send <- data:
+	default:
+		log.Printf("Клиент %s в %s не успевает читать - отключаем", c.user, c.room)
+		h.drop(c)
+	}
+}
+
+// readPump читает сообщения клиента и передает их хабу. Выполняется
+// в горутине HTTP-обработчика; выход означает отключение клиента.
+func (c *Client) readPump() {
+	defer func() {
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
+	}()
+	
+	c.conn.ReadTimeout = pongWait
+	for {
+		data, err := c.conn.ReadMessage()
+		if err != nil {
+			if err != ErrClosed {
+				code, reason := closeError(err)
+				c.conn.Close(code, reason)
+			}
+			return
+		}
+		
+		var req struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			continue
+		}
+		text := strings.TrimSpace(req.Text)
+		if text == "" || !utf8.ValidString(text) || utf8.RuneCountInString(text) > maxTextLength {
+			continue
+		}
+		
+		select {
+		case c.hub.incoming <- inbound{c, text}:
+		case <-c.hub.done:
+			return
+		}
+	}
+}
+
+// writePump - единственная горутина, которая пишет сообщения клиенту,
+// плюс периодический ping, чтобы заметить пропавшего собеседника
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	
+	c.conn.WriteTimeout = writeWait
+	for {
+		select {
+		case data, ok := <-c.send:
+			if !ok {
+				// Хаб отключил клиента: при остановке сервера или из-за медленного чтения
+				c.conn.Close(CloseGoingAway, "bye")
+				return
+			}
+			if err := c.conn.WriteText(data); err != nil {
+				c.conn.conn.Close() // readPump получит ошибку и снимет регистрацию
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.Ping(); err != nil {
+				c.conn.conn.Close()
+				return
+			}
+		}
+	}
+}
\ No newline at end of file
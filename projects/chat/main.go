@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Чат реального времени - итоговый проект, собранный из уроков:
+//   ws.go     - протокол WebSocket поверх net/http (Hijack, кадры, ping/pong)
+//   hub.go    - хаб: одна горутина владеет состоянием, остальные
+//               общаются с ней через каналы (examples/channels);
+//               по две горутины на клиента - чтение и запись
+//   store.go  - история в SQLite с пагинацией по курсору (examples/database)
+//   server.go - маршруты и встроенный через embed HTML-клиент
+//   main.go   - graceful shutdown с context (examples/context, examples/signals)
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск: go run . -db chat.db
+// Открыть http://localhost:8080 в двух вкладках и войти в одну комнату.
+//   curl localhost:8080/api/rooms
+//   curl 'localhost:8080/api/rooms/general/messages?limit=10'
+//
+// Тесты: go test -race ./...
+
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// run запускает чат и блокируется до отмены ctx
+func run(ctx context.Context, addr, dbPath string) error {
+	db, err := openDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+	
+	store := NewStore(db)
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	
+	hub := NewHub(store)
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		hub.Run(hubCtx)
+	}()
+	
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           NewServer(hub, store).Routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Чат запущен на %s", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		stopHub()
+		wg.Wait()
+		return err
+	case <-ctx.Done():
+	}
+	
+	// Shutdown перестает принимать соединения и ждет обычные запросы,
+	// но WebSocket-соединения захвачены и ему не видны - их закрывает хаб
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки сервера: %v", err)
+	}
+	stopHub()
+	wg.Wait()
+	log.Println("Остановлен")
+	return nil
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "адрес HTTP-сервера")
+	dbPath := flag.String("db", "chat.db", "путь к файлу SQLite")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, *addr, *dbPath); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Имена комнат и пользователей: буквы, цифры, - и _, до 32 символов
+var namePattern = regexp.MustCompile(`^[\p{L}\p{N}_-]{1,32}$`)
+
+// Server HTTP-слой чата
+type Server struct {
+	hub   *Hub
+	store *Store
+}
+
+// NewServer создает сервер
+func NewServer(hub *Hub, store *Store) *Server {
+	return &Server{hub: hub, store: store}
+}
+
+// Routes возвращает маршруты:
+//
+//	GET /                               HTML-клиент
+//	GET /ws?room=&user=                 WebSocket
+//	GET /api/rooms                      комнаты и кто онлайн
+//	GET /api/rooms/{room}/messages      история, ?before=ID&limit=N
+func (s *Server) Routes() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /ws", s.handleWS)
+	mux.HandleFunc("GET /api/rooms", s.handleRooms)
+	mux.HandleFunc("GET /api/rooms/{room}/messages", s.handleHistory)
+	return mux
+}
+
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	room, user := r.URL.Query().Get("room"), r.URL.Query().Get("user")
+	if !namePattern.MatchString(room) || !namePattern.MatchString(user) {
+		http.Error(w, "Invalid room or user", http.StatusBadRequest)
+		return
+	}
+	
+	conn, err := Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	c := &Client{hub: s.hub, conn: conn, user: user, room: room, send: make(chan []byte, sendBuffer)}
+	
+	select {
+	case s.hub.register <- c:
+	case <-s.hub.done:
+		conn.Close(CloseGoingAway, "server is shutting down")
+		return
+	}
+	go c.writePump()
+	c.readPump()
+}
+
+func (s *Server) handleRooms(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.hub.Rooms())
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	room := r.PathValue("room")
+	if !namePattern.MatchString(room) {
+		http.Error(w, "Invalid room", http.StatusBadRequest)
+		return
+	}
+	
+	var before int64
+	if v := r.URL.Query().Get("before"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid before", http.StatusBadRequest)
+			return
+		}
+		before = n
+	}
+	limit := historySize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	
+	messages, err := s.store.History(r.Context(), room, before, limit)
+	if err != nil {
+		log.Printf("Ошибка чтения истории: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, messages)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
\ No newline at end of file
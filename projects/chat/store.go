@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Message сообщение чата
+type Message struct {
+	ID   int64     `json:"id"`
+	Room string    `json:"room"`
+	User string    `json:"user"`
+	Text string    `json:"text"`
+	Time time.Time `json:"time"`
+}
+
+// Store история сообщений в SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore создает хранилище
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate создает таблицу. Индекс (room, id) обслуживает и последние
+// сообщения комнаты, и подгрузку более старых по курсору before.
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		room TEXT NOT NULL,
+		user TEXT NOT NULL,
+		text TEXT NOT NULL,
+		created_at INTEGER NOT NULL -- Unix-миллисекунды
+	);
+	CREATE INDEX IF NOT EXISTS idx_messages_room_id ON messages(room, id);`)
+	return err
+}
+
+// Save сохраняет сообщение и заполняет ID
+func (s *Store) Save(ctx context.Context, m Message) (Message, error) {
+	res, err := s.db.ExecContext(ctx, `INSERT INTO messages (room, user, text, created_at) VALUES (?, ?, ?, ?)`,
+		m.Room, m.User, m.Text, m.Time.UnixMilli())
+	if err != nil {
+		return Message{}, err
+	}
+	m.ID, err = res.LastInsertId()
+	return m, err
+}
+
+// History возвращает до limit сообщений комнаты с ID меньше before
+// (0 - самые последние) в хронологическом порядке. Курсор по ID, а не
+// OFFSET: новые сообщения не сдвигают страницы, и запрос не
+// пропускает строки перебором.
+func (s *Store) History(ctx context.Context, room string, before int64, limit int) ([]Message, error) {
+	if before <= 0 {
+		before = 1<<63 - 1
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, room, user, text, created_at FROM messages
+		WHERE room = ? AND id < ?
+		ORDER BY id DESC LIMIT ?`, room, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	result := []Message{}
+	for rows.Next() {
+		var m Message
+		var ms int64
+		if err := rows.Scan(&m.ID, &m.Room, &m.User, &m.Text, &ms); err != nil {
+			return nil, err
+		}
+		m.Time = time.UnixMilli(ms).UTC()
+		result = append(result, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Выбирали с конца - разворачиваем в хронологический порядок
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
\ No newline at end of file
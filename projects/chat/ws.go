@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Минимальная реализация WebSocket (RFC 6455) поверх net/http: рукопожатие
+// через Hijack и кадры text/close/ping/pong. Ее хватает для чата и она
+// показывает, что под капотом у библиотек. В продакшене берут
+// github.com/gorilla/websocket или github.com/coder/websocket - там
+// сжатие, подпротоколы, проверка UTF-8 и годы исправленных краевых случаев.
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Коды операций кадра
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// maxMessageSize ограничение на сообщение: без него клиент может
+// объявить кадр в 2^63 байт и заставить сервер выделить память
+const maxMessageSize = 64 << 10
+
+var (
+	// ErrClosed соединение закрыто кадром close
+	ErrClosed = errors.New("websocket: connection closed")
+	// ErrTooLarge сообщение больше maxMessageSize
+	ErrTooLarge = errors.New("websocket: message too large")
+	errProtocol = errors.New("websocket: protocol error")
+)
+
+// Conn WebSocket-соединение. Чтение - из одной горутины, запись
+// защищена мьютексом: ping из writePump и pong в ответ на ping из
+// readPump могут писать одновременно.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // Клиент маскирует свои кадры, сервер - нет
+
+	writeMu sync.Mutex
+
+	// ReadTimeout продлевается при каждом полученном кадре (включая pong);
+	// если собеседник молчит дольше, ReadMessage вернет ошибку
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{conn: conn, br: br, client: client}
+}
+
+// acceptKey вычисляет Sec-WebSocket-Accept из ключа клиента
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin защищает от cross-site WebSocket hijacking: браузер шлет
+// куки на WebSocket любого сайта, и без проверки Origin чужая страница
+// подключится от имени пользователя. CORS на WebSocket не действует.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true // Не браузер
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Upgrade переводит HTTP-запрос в WebSocket. При ошибке ответ клиенту
+// уже отправлен.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocket upgrade required", http.StatusUpgradeRequired)
+		return nil, errProtocol
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusBadRequest)
+		return nil, errProtocol
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "Missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errProtocol
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return nil, errProtocol
+	}
+	
+	// Hijack забирает TCP-соединение у net/http: дальше сервер его не
+	// трогает, в том числе не закрывает при Shutdown
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Hijacking not supported", http.StatusInternalServerError)
+		return nil, errProtocol
+	}
+	netConn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := netConn.Write([]byte(resp)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, brw.Reader, false), nil
+}
+
+// ReadMessage возвращает следующее текстовое или бинарное сообщение,
+// собирая его из фрагментов. Ping получает pong автоматически.
+// После кадра close возвращает ErrClosed.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+	for {
+		if c.ReadTimeout > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+		}
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+			// Достаточно продления дедлайна в начале цикла
+		case opClose:
+			// Отвечаем тем же кодом и закрываем - так завершается рукопожатие закрытия
+			c.writeFrame(opClose, payload)
+			c.conn.Close()
+			return nil, ErrClosed
+		case opText, opBinary, opContinuation:
+			if (op == opContinuation) != started {
+				return nil, errProtocol
+			}
+			started = true
+			if len(msg)+len(payload) > maxMessageSize {
+				return nil, ErrTooLarge
+			}
+			msg = append(msg, payload...)
+			if fin {
+				return msg, nil
+			}
+		default:
+			return nil, errProtocol
+		}
+	}
+}
+
+// readFrame читает один кадр:
+//
+//	байт 0: FIN(1) RSV(3) opcode(4)
+//	байт 1: MASK(1) длина(7); 126 - далее 2 байта длины, 127 - 8 байт
+//	[4 байта ключа маски] полезная нагрузка
+func (c *Conn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(c.br, hdr[:]); err != nil {
+		return
+	}
+	fin = hdr[0]&0x80 != 0
+	op = hdr[0] & 0x0F
+	if hdr[0]&0x70 != 0 {
+		return false, 0, nil, errProtocol // Расширения не согласовывались
+	}
+	masked := hdr[1]&0x80 != 0
+	// Клиент обязан маскировать кадры, сервер - не должен
+	if masked == c.client {
+		return false, 0, nil, errProtocol
+	}
+	
+	length := uint64(hdr[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if op >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, errProtocol // Управляющие кадры короткие и не фрагментируются
+	}
+	if length > maxMessageSize {
+		return false, 0, nil, ErrTooLarge
+	}
+	
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame пишет один кадр с FIN
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|op)
+	
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	
+	if c.client {
+		var mask [4]byte
+		rand.Read(mask[:])
+		buf = append(buf, mask[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		for i := range payload {
+			buf[start+i] ^= mask[i%4]
+		}
+	} else {
+		buf = append(buf, payload...)
+	}
+	
+	if c.WriteTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	}
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// WriteText отправляет текстовое сообщение
+func (c *Conn) WriteText(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// Ping отправляет ping; ответный pong продлит ReadTimeout
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// Close отправляет кадр close с кодом и закрывает соединение
+func (c *Conn) Close(code uint16, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	payload = append(payload, reason...)
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+	c.writeFrame(opClose, payload)
+	return c.conn.Close()
+}
+
+// Коды закрытия
+const (
+	CloseNormal      uint16 = 1000
+	CloseGoingAway   uint16 = 1001
+	CloseProtocol    uint16 = 1002
+	ClosePolicy      uint16 = 1008
+	CloseTooBig      uint16 = 1009
+	CloseServerError uint16 = 1011
+)
+
+// closeError возвращает код закрытия для ошибки чтения
+func closeError(err error) (uint16, string) {
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		return CloseTooBig, "message too large"
+	case errors.Is(err, errProtocol):
+		return CloseProtocol, "protocol error"
+	}
+	return CloseServerError, fmt.Sprint(err)
+}
\ No newline at end of file
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dial тестовый клиент: рукопожатие вручную и Conn в режиме клиента
+func dial(t *testing.T, serverURL, path string) *Conn {
+	t.Helper()
+	conn, err := dialErr(serverURL, path)
+	if err != nil {
+		t.Fatalf("Dial %s: %v", path, err)
+	}
+	t.Cleanup(func() { conn.conn.Close() })
+	return conn
+}
+
+func dialErr(serverURL, path string) (*Conn, error) {
+	host := strings.TrimPrefix(serverURL, "http://")
+	netConn, err := net.Dial("tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	var key [16]byte
+	rand.Read(key[:])
+	k := base64.StdEncoding.EncodeToString(key[:])
+	fmt.Fprintf(netConn, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", path, host, k)
+	
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		netConn.Close()
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(k) {
+		netConn.Close()
+		return nil, errors.New("bad accept key")
+	}
+	c := newConn(netConn, br, true)
+	c.ReadTimeout = 5 * time.Second
+	return c, nil
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Пример из RFC 6455, раздел 1.3
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Expected s3pPLMBiTxaQ9kYGzzhZRbK+xOo=, got %s", got)
+	}
+}
+
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		for {
+			msg, err := conn.ReadMessage()
+			if err != nil {
+				if err != ErrClosed {
+					code, reason := closeError(err)
+					conn.Close(code, reason)
+				}
+				return
+			}
+			conn.WriteText(msg)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestEchoSizes(t *testing.T) {
+	ts := echoServer(t)
+	c := dial(t, ts.URL, "/")
+	
+	// Три формата длины: 7 бит, 16 бит и 64 бита
+	for _, n := range []int{0, 5, 125, 126, 1000, 65535, maxMessageSize} {
+		msg := strings.Repeat("я", n/2) + strings.Repeat("x", n%2)
+		if err := c.WriteText([]byte(msg)); err != nil {
+			t.Fatalf("Write %d: %v", n, err)
+		}
+		got, err := c.ReadMessage()
+		if err != nil {
+			t.Fatalf("Read %d: %v", n, err)
+		}
+		if string(got) != msg {
+			t.Errorf("Size %d: echo mismatch (%d bytes)", n, len(got))
+		}
+	}
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	ts := echoServer(t)
+	c := dial(t, ts.URL, "/")
+	
+	// text без FIN, ping между фрагментами, continuation с FIN
+	for _, frame := range []struct {
+		first byte
+		data  string
+	}{{opText, "Hel"}, {0x80 | opPing, ""}, {opContinuation, "lo, "}, {0x80 | opContinuation, "world"}} {
+		writeRawFrame(t, c, frame.first, []byte(frame.data))
+	}
+	
+	// Сначала приходит pong, ReadMessage его пропускает
+	got, err := c.ReadMessage()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(got) != "Hello, world" {
+		t.Errorf("Expected 'Hello, world', got %q", got)
+	}
+}
+
+// writeRawFrame пишет кадр с произвольным первым байтом (FIN и opcode)
+func writeRawFrame(t *testing.T, c *Conn, first byte, payload []byte) {
+	t.Helper()
+	mask := [4]byte{1, 2, 3, 4}
+	buf := []byte{first, 0x80 | byte(len(payload))}
+	buf = append(buf, mask[:]...)
+	for i, b := range payload {
+		buf = append(buf, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestUnmaskedClientFrameRejected(t *testing.T) {
+	ts := echoServer(t)
+	c := dial(t, ts.URL, "/")
+	
+	// Клиент без маски нарушает протокол - сервер закрывает с кодом 1002
+	c.conn.Write([]byte{0x80 | opText, 2, 'h', 'i'})
+	_, err := c.ReadMessage()
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected close from server, got %v", err)
+	}
+}
+
+func TestCloseHandshake(t *testing.T) {
+	ts := echoServer(t)
+	c := dial(t, ts.URL, "/")
+	
+	c.writeFrame(opClose, []byte{0x03, 0xE8}) // 1000
+	_, err := c.ReadMessage()
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected close reply, got %v", err)
+	}
+}
+
+func TestUpgradeRejects(t *testing.T) {
+	ts := echoServer(t)
+	
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"plain GET", map[string]string{}, http.StatusUpgradeRequired},
+		{"old version", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": "x"}, http.StatusBadRequest},
+		{"no key", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13"}, http.StatusBadRequest},
+		{"foreign origin", map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "x", "Origin": "https://evil.example"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", ts.URL, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const modulePath = "clean-arch"
+
+// layerRules правила зависимостей между слоями: пакет (по префиксу
+// каталога) может импортировать из модуля только перечисленное.
+// Сторонние модули разрешены только адаптерам.
+var layerRules = []struct {
+	dir        string
+	allowed    []string
+	thirdParty bool
+}{
+	{"internal/domain", nil, false},
+	{"internal/usecase", []string{"internal/domain"}, false},
+	{"internal/adapter/repotest", []string{"internal/domain", "internal/usecase"}, false},
+	{"internal/adapter", []string{"internal/domain", "internal/usecase"}, true},
+	// Клиент подключают другие модули: ни internal/, ни сторонних зависимостей
+	{"client", nil, false},
+}
+
+// testOnlyImports разрешены только в _test.go. Декоратор cache
+// проверяется поверх настоящих хранилищ, но в коде от них не зависит.
+var testOnlyImports = []string{"internal/adapter/repotest", "internal/adapter/memory", "internal/adapter/sqlite"}
+
+func ruleFor(dir string) (allowed []string, thirdParty bool, ok bool) {
+	for _, rule := range layerRules {
+		if dir == rule.dir || strings.HasPrefix(dir, rule.dir+"/") {
+			return rule.allowed, rule.thirdParty, true
+		}
+	}
+	return nil, false, false
+}
+
+func TestLayerDependencies(t *testing.T) {
+	fset := token.NewFileSet()
+	checked := 0
+	walk := func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		dir := filepath.ToSlash(filepath.Dir(path))
+		allowed, thirdParty, ok := ruleFor(dir)
+		if !ok {
+			t.Errorf("%s: no layer rule for package", path)
+			return nil
+		}
+		
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		isTest := strings.HasSuffix(path, "_test.go")
+		for _, spec := range f.Imports {
+			imp, _ := strconv.Unquote(spec.Path.Value)
+			checked++
+			switch {
+			case strings.HasPrefix(imp, modulePath+"/"):
+				target := strings.TrimPrefix(imp, modulePath+"/")
+				if target == dir || slices.Contains(allowed, target) || (isTest && slices.Contains(testOnlyImports, target)) {
+					continue
+				}
+				t.Errorf("%s: %s must not import %s", path, dir, target)
+			case strings.Contains(strings.SplitN(imp, "/", 2)[0], "."):
+				if !thirdParty {
+					t.Errorf("%s: %s must not depend on third-party %s", path, dir, imp)
+				}
+			}
+		}
+		return nil
+	}
+	for _, root := range []string{"internal", "client"} {
+		if err := filepath.WalkDir(root, walk); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if checked == 0 {
+		t.Fatal("Expected to check some imports")
+	}
+}
\ No newline at end of file
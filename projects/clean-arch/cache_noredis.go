@@ -0,0 +1,20 @@
+//go:build !redis
+
+package main
+
+import (
+	"errors"
+
+	"clean-arch/internal/adapter/cache"
+)
+
+// cacheCapacity число пользователей в кэше в памяти
+const cacheCapacity = 10000
+
+// newCacheStore без тега redis умеет только кэш в памяти
+func newCacheStore(cfg cacheConfig) (cache.Store, func() error, error) {
+	if cfg.RedisAddr != "" {
+		return nil, nil, errors.New("redis cache requires building with -tags redis")
+	}
+	return cache.NewLRU(cacheCapacity), func() error { return nil }, nil
+}
\ No newline at end of file
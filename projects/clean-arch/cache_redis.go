@@ -0,0 +1,21 @@
+//go:build redis
+
+package main
+
+import (
+	"github.com/redis/go-redis/v9"
+
+	"clean-arch/internal/adapter/cache"
+)
+
+// cacheCapacity число пользователей в кэше в памяти
+const cacheCapacity = 10000
+
+// newCacheStore выбирает Redis, если задан адрес
+func newCacheStore(cfg cacheConfig) (cache.Store, func() error, error) {
+	if cfg.RedisAddr == "" {
+		return cache.NewLRU(cacheCapacity), func() error { return nil }, nil
+	}
+	client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+	return cache.NewRedisStore(client, "clean-arch:"), client.Close, nil
+}
\ No newline at end of file
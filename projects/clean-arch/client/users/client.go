@@ -0,0 +1,323 @@
+// Package users - клиент API пользователей для других сервисов:
+//
+//	c, err := users.New("http://users:8080", users.WithRetries(3, 100*time.Millisecond))
+//	u, err := c.Register(ctx, "Иван", "ivan@example.com")
+//	if errors.Is(err, users.ErrConflict) { ... }
+//
+// Владелец сервиса поставляет клиент вместе с API: потребители получают
+// типы вместо map[string]any, ошибки вместо разбора статусов и повторы,
+// настроенные теми, кто знает, какие запросы повторять безопасно.
+//
+// Пакет вне internal/ и не импортирует его: клиент подключают из других
+// модулей, а им internal/ недоступен. Поэтому User здесь свой, а не
+// domain.User - это формат API, а не сущность (arch_test.go следит).
+package users
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User пользователь в формате API
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Ошибки по статусу ответа; проверяются через errors.Is
+var (
+	ErrNotFound   = errors.New("users: not found")
+	ErrConflict   = errors.New("users: conflict")     // email занят или пользователь деактивирован
+	ErrValidation = errors.New("users: validation")   // неверное значение поля
+	ErrServer     = errors.New("users: server error") // 5xx, в том числе после всех повторов
+)
+
+// APIError ответ сервера с неуспешным статусом. Message - поле "error"
+// из тела {"error": "..."}.
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // Из заголовка Retry-After, если он был
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("users: %d %s", e.StatusCode, e.Message)
+}
+
+// Unwrap сопоставляет статус с ошибкой пакета, чтобы вызывающий код
+// проверял errors.Is(err, users.ErrNotFound), а не номера статусов
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case e.StatusCode >= 500:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// Client клиент API. Безопасен для конкурентного использования.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// Option настройка клиента
+type Option func(*Client)
+
+// WithHTTPClient подставляет свой http.Client: таймауты, TLS, транспорт
+// с трассировкой или httptest.Server.Client() в тестах
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetries до n повторов с паузой backoff, удваивающейся с каждой
+// попыткой. n == 0 отключает повторы.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) { c.maxRetries, c.backoff = n, backoff }
+}
+
+// WithUserAgent имя клиента в логах сервера
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New создает клиент для baseURL вида http://host:port. По умолчанию
+// таймаут запроса 10 секунд и 2 повтора с паузой от 100 мс.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("users: base URL должен быть вида http://host:port, получено %q", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		userAgent:  "clean-arch-users-client/1",
+		maxRetries: 2,
+		backoff:    100 * time.Millisecond,
+		maxBackoff: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Register создает пользователя
+func (c *Client) Register(ctx context.Context, name, email string) (*User, error) {
+	var u User
+	body := map[string]string{"name": name, "email": email}
+	if err := c.do(ctx, http.MethodPost, "/api/users", body, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Get возвращает пользователя по ID
+func (c *Client) Get(ctx context.Context, id int64) (*User, error) {
+	var u User
+	if err := c.do(ctx, http.MethodGet, "/api/users/"+strconv.FormatInt(id, 10), nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// List возвращает всех пользователей
+func (c *Client) List(ctx context.Context) ([]User, error) {
+	var list []User
+	if err := c.do(ctx, http.MethodGet, "/api/users", nil, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ChangeEmail меняет email пользователя
+func (c *Client) ChangeEmail(ctx context.Context, id int64, email string) (*User, error) {
+	var u User
+	path := "/api/users/" + strconv.FormatInt(id, 10) + "/email"
+	if err := c.do(ctx, http.MethodPatch, path, map[string]string{"email": email}, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Deactivate деактивирует пользователя
+func (c *Client) Deactivate(ctx context.Context, id int64) (*User, error) {
+	var u User
+	path := "/api/users/" + strconv.FormatInt(id, 10) + "/deactivate"
+	if err := c.do(ctx, http.MethodPost, path, nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// do выполняет запрос с повторами и раскладывает ответ в dst.
+//
+// Повторяются сетевые ошибки, 429, 502-504 и 409 "ключ занят". GET безопасно повторять
+// всегда, а POST и PATCH - только потому, что у всех попыток один
+// Idempotency-Key: если сервер выполнил запрос, но ответ потерялся,
+// повтор получит сохраненный ответ, а не создаст второго пользователя.
+func (c *Client) do(ctx context.Context, method, path string, body, dst any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	var idemKey string
+	if method != http.MethodGet {
+		idemKey = newIdempotencyKey()
+	}
+	
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.delay(attempt, lastErr)); err != nil {
+				return err
+			}
+		}
+		retry, err := c.attempt(ctx, method, path, payload, idemKey, dst)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// attempt одна попытка; retry - стоит ли повторять при ошибке
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, idemKey string, dst any) (retry bool, err error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.JoinPath(path).String(), body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idemKey != "" {
+		req.Header.Set("Idempotency-Key", idemKey)
+	}
+	
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return true, err
+	}
+	
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    errorMessage(data),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		// 409 с Retry-After - запрос с этим ключом еще выполняется
+		// (предыдущая попытка не дождалась ответа): стоит подождать
+		inProgress := resp.StatusCode == http.StatusConflict && resp.Header.Get("Retry-After") != ""
+		return retryable(resp.StatusCode) || inProgress, apiErr
+	}
+	if dst != nil {
+		if err := json.Unmarshal(data, dst); err != nil {
+			return false, fmt.Errorf("users: разбор ответа: %w", err)
+		}
+	}
+	return false, nil
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// errorMessage достает "error" из тела ответа; тело не в формате API
+// (страница прокси, обрезанный ответ) возвращается как есть
+func errorMessage(data []byte) string {
+	var envelope struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &envelope) == nil && envelope.Error != "" {
+		return envelope.Error
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// delay пауза перед попыткой: Retry-After, если сервер его прислал,
+// иначе backoff * 2^(attempt-1), не больше maxBackoff
+func (c *Client) delay(attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, c.maxBackoff)
+	}
+	return min(c.backoff<<(attempt-1), c.maxBackoff)
+}
+
+// parseRetryAfter поддерживает только секунды: дату в Retry-After
+// присылают редко, и ошибка в ее разборе не стоит лишней сложности
+func parseRetryAfter(v string) time.Duration {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return time.Duration(n) * time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
\ No newline at end of file
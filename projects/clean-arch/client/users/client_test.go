@@ -0,0 +1,218 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, h http.Handler, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	opts = append([]Option{WithHTTPClient(srv.Client()), WithRetries(2, time.Millisecond)}, opts...)
+	c, err := New(srv.URL, opts...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestNew_BadURL(t *testing.T) {
+	for _, u := range []string{"", "localhost:8080", "/api", "http://"} {
+		if _, err := New(u); err == nil {
+			t.Errorf("%q: expected error", u)
+		}
+	}
+}
+
+func TestClient_Get(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/users/7" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Idempotency-Key") != "" {
+			t.Error("Expected no Idempotency-Key on GET")
+		}
+		io.WriteString(w, `{"id":7,"name":"Иван","email":"ivan@example.com","active":true,"created_at":"2024-01-02T03:04:05Z"}`)
+	}))
+	u, err := c.Get(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u.ID != 7 || u.Name != "Иван" || !u.Active || u.CreatedAt.Year() != 2024 {
+		t.Errorf("Unexpected user %+v", u)
+	}
+}
+
+func TestClient_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+		message  string
+	}{
+		{"not found", 404, `{"error":"user not found"}`, ErrNotFound, "user not found"},
+		{"conflict", 409, `{"error":"email already taken"}`, ErrConflict, "email already taken"},
+		{"validation", 422, `{"error":"email: is required"}`, ErrValidation, "email: is required"},
+		{"server", 500, `{"error":"internal error"}`, ErrServer, "internal error"},
+		{"не JSON", 404, "404 page not found\n", ErrNotFound, "404 page not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			_, err := c.Get(context.Background(), 1)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("Expected APIError %d %q, got %v", tt.status, tt.message, err)
+			}
+		})
+	}
+}
+
+// flaky отвечает статусами из списка, потом 200; запоминает ключи
+type flaky struct {
+	mu       sync.Mutex
+	statuses []int
+	calls    int
+	keys     []string
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.keys = append(f.keys, r.Header.Get("Idempotency-Key"))
+	if len(f.statuses) > 0 {
+		status := f.statuses[0]
+		f.statuses = f.statuses[1:]
+		if status == http.StatusConflict {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, `{"error":"временно"}`)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	io.WriteString(w, `{"id":1,"name":"Иван","email":"ivan@example.com","active":true}`)
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		calls    int
+		expected error
+	}{
+		{"без сбоев", nil, 1, nil},
+		{"503 и успех", []int{503}, 2, nil},
+		{"429, 502 и успех", []int{429, 502}, 3, nil},
+		{"повторы кончились", []int{503, 503, 503}, 3, ErrServer},
+		{"500 не повторяется", []int{500}, 1, ErrServer},
+		{"422 не повторяется", []int{422}, 1, ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &flaky{statuses: tt.statuses}
+			c := newTestClient(t, f)
+			_, err := c.Register(context.Background(), "Иван", "ivan@example.com")
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if f.calls != tt.calls {
+				t.Errorf("Expected %d calls, got %d", tt.calls, f.calls)
+			}
+			// Все попытки одного вызова - с одним ключом
+			for _, k := range f.keys {
+				if k == "" || k != f.keys[0] {
+					t.Errorf("Expected the same Idempotency-Key, got %v", f.keys)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RetryInProgress(t *testing.T) {
+	// 409 с Retry-After - ключ занят предыдущей попыткой: повторяем;
+	// 409 без него - настоящий конфликт: нет
+	f := &flaky{statuses: []int{http.StatusConflict}}
+	c := newTestClient(t, f)
+	if _, err := c.Register(context.Background(), "Иван", "ivan@example.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", f.calls)
+	}
+}
+
+func TestClient_NewKeyPerCall(t *testing.T) {
+	f := &flaky{}
+	c := newTestClient(t, f)
+	ctx := context.Background()
+	c.Register(ctx, "Иван", "ivan@example.com")
+	c.Register(ctx, "Иван", "ivan@example.com")
+	if len(f.keys) != 2 || f.keys[0] == f.keys[1] {
+		t.Errorf("Expected different keys for separate calls, got %v", f.keys)
+	}
+}
+
+func TestClient_Delay(t *testing.T) {
+	c, _ := New("http://users.invalid", WithRetries(5, 100*time.Millisecond))
+	tests := []struct {
+		attempt  int
+		err      error
+		expected time.Duration
+	}{
+		{1, nil, 100 * time.Millisecond},
+		{2, nil, 200 * time.Millisecond},
+		{3, nil, 400 * time.Millisecond},
+		{10, nil, 5 * time.Second},
+		{1, &APIError{StatusCode: 429, RetryAfter: 2 * time.Second}, 2 * time.Second},
+		{1, &APIError{StatusCode: 429, RetryAfter: time.Hour}, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := c.delay(tt.attempt, tt.err); got != tt.expected {
+			t.Errorf("delay(%d, %v): expected %v, got %v", tt.attempt, tt.err, tt.expected, got)
+		}
+	}
+}
+
+func TestClient_ContextCancel(t *testing.T) {
+	f := &flaky{statuses: []int{503, 503, 503}}
+	c := newTestClient(t, f, WithRetries(2, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	
+	start := time.Now()
+	_, err := c.Register(ctx, "Иван", "ivan@example.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected backoff to stop on context cancel")
+	}
+}
+
+func TestClient_NetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close() // Порт закрыт: соединение не установится
+	
+	c, _ := New(url, WithRetries(1, time.Millisecond))
+	if _, err := c.List(context.Background()); err == nil {
+		t.Error("Expected network error")
+	}
+}
\ No newline at end of file
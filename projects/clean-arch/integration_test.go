@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"clean-arch/client/users"
+	"clean-arch/internal/adapter/idempotency"
+	"clean-arch/internal/adapter/memory"
+)
+
+// Интеграционные тесты: настоящий HTTP-сервер со всеми слоями и клиент
+// из client/users - так, как сервис видят его потребители
+
+func startServer(t *testing.T, wrap func(http.Handler) http.Handler) *users.Client {
+	t.Helper()
+	h := newHandler(memory.NewUserRepository(), idempotency.New(idempotency.NewMemoryStore()))
+	if wrap != nil {
+		h = wrap(h)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c, err := users.New(srv.URL, users.WithHTTPClient(srv.Client()), users.WithRetries(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestIntegration_UserLifecycle(t *testing.T) {
+	c := startServer(t, nil)
+	ctx := context.Background()
+	
+	u, err := c.Register(ctx, "Иван", "Ivan@Example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u.ID == 0 || !u.Active || u.CreatedAt.IsZero() {
+		t.Errorf("Unexpected user %+v", u)
+	}
+	// Email нормализуется доменом
+	if u.Email != "ivan@example.com" {
+		t.Errorf("Expected ivan@example.com, got %s", u.Email)
+	}
+	
+	got, err := c.Get(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Name != "Иван" {
+		t.Errorf("Expected Иван, got %s", got.Name)
+	}
+	
+	if _, err := c.Register(ctx, "Петр", "petr@example.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	list, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(list) != 2 {
+		t.Errorf("Expected 2 users, got %d", len(list))
+	}
+	
+	changed, err := c.ChangeEmail(ctx, u.ID, "new@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if changed.Email != "new@example.com" {
+		t.Errorf("Expected new@example.com, got %s", changed.Email)
+	}
+	
+	deactivated, err := c.Deactivate(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if deactivated.Active {
+		t.Error("Expected user to be deactivated")
+	}
+}
+
+func TestIntegration_Errors(t *testing.T) {
+	c := startServer(t, nil)
+	ctx := context.Background()
+	u, err := c.Register(ctx, "Иван", "ivan@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	tests := []struct {
+		name     string
+		call     func() error
+		expected error
+	}{
+		{"нет пользователя", func() error { _, err := c.Get(ctx, 999); return err }, users.ErrNotFound},
+		{"email занят", func() error { _, err := c.Register(ctx, "Другой", "ivan@example.com"); return err }, users.ErrConflict},
+		{"неверный email", func() error { _, err := c.Register(ctx, "Петр", "не email"); return err }, users.ErrValidation},
+		{"пустое имя", func() error { _, err := c.Register(ctx, "", "petr@example.com"); return err }, users.ErrValidation},
+		{"смена email у чужого", func() error { _, err := c.ChangeEmail(ctx, 999, "x@example.com"); return err }, users.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			var apiErr *users.APIError
+			if !errors.As(err, &apiErr) || apiErr.Message == "" {
+				t.Errorf("Expected APIError with message, got %v", err)
+			}
+		})
+	}
+	
+	// Деактивированному нельзя менять email
+	if _, err := c.Deactivate(ctx, u.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := c.ChangeEmail(ctx, u.ID, "new@example.com"); !errors.Is(err, users.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
+
+// loseFirstResponse выполняет первый POST, но вместо ответа отдает 503 -
+// как прокси, у которого оборвалась связь с сервисом уже после записи
+func loseFirstResponse(calls *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && calls.Add(1) == 1 {
+				next.ServeHTTP(httptest.NewRecorder(), r)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestIntegration_RetryIsIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	c := startServer(t, loseFirstResponse(&calls))
+	ctx := context.Background()
+	
+	// Первая попытка создала пользователя, но ответ потерялся. Повтор
+	// с тем же Idempotency-Key получает сохраненный ответ, а не 409
+	// "email already taken" и не второго пользователя.
+	u, err := c.Register(ctx, "Иван", "ivan@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+	list, err := c.List(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != u.ID {
+		t.Errorf("Expected exactly one user %d, got %+v", u.ID, list)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"clean-arch/internal/adapter/sqlite"
+	"clean-arch/internal/domain"
+	"clean-arch/internal/usecase"
+)
+
+// go test -bench=. -benchmem ./internal/adapter/cache
+// Сравнение: чтение из SQLite напрямую, попадание в кэш и промах
+// (чтение из SQLite плюс запись в кэш). Разница попадания и прямого
+// чтения - выигрыш кэша; разница промаха и прямого чтения - его цена.
+
+func newBenchRepo(b *testing.B) (usecase.UserRepository, []int64) {
+	b.Helper()
+	ctx := context.Background()
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", filepath.Join(b.TempDir(), "bench.db"))
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+	repo := sqlite.NewUserRepository(db)
+	if err := repo.Migrate(ctx); err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+	
+	ids := make([]int64, 1000)
+	for i := range ids {
+		u := &domain.User{Name: "Пользователь", Email: fmt.Sprintf("u%d@example.com", i), Active: true, CreatedAt: time.Now()}
+		if err := repo.Create(ctx, u); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		ids[i] = u.ID
+	}
+	return repo, ids
+}
+
+func benchGet(b *testing.B, repo usecase.UserRepository, ids []int64) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Get(ctx, ids[i%len(ids)]); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkGetDirect(b *testing.B) {
+	repo, ids := newBenchRepo(b)
+	benchGet(b, repo, ids)
+}
+
+func BenchmarkGetHit(b *testing.B) {
+	next, ids := newBenchRepo(b)
+	repo := NewUserRepository(next, NewLRU(len(ids)), Options{TTL: time.Hour})
+	for _, id := range ids {
+		repo.Get(context.Background(), id) // Прогрев
+	}
+	benchGet(b, repo, ids)
+}
+
+func BenchmarkGetMiss(b *testing.B) {
+	next, ids := newBenchRepo(b)
+	// Кэш на одну запись при переборе по кругу - всегда промах
+	repo := NewUserRepository(next, NewLRU(1), Options{TTL: time.Hour})
+	benchGet(b, repo, ids)
+}
+
+func BenchmarkGetHitParallel(b *testing.B) {
+	next, ids := newBenchRepo(b)
+	repo := NewUserRepository(next, NewLRU(len(ids)), Options{TTL: time.Hour})
+	for _, id := range ids {
+		repo.Get(context.Background(), id)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := context.Background()
+		i := 0
+		for pb.Next() {
+			if _, err := repo.Get(ctx, ids[i%len(ids)]); err != nil {
+				b.Fatalf("Unexpected error: %v", err)
+			}
+			i++
+		}
+	})
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+//go:build redis
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Сборка с Redis:
+//   go get github.com/redis/go-redis/v9
+//   go build -tags redis .
+//   ./clean-arch -redis localhost:6379
+// Без тега файл не компилируется, и проекту не нужна зависимость; без
+// флага -redis даже сборка с тегом использует LRU в памяти.
+//
+// Тесты на живом Redis (redis_test.go) тоже под тегом и пропускаются,
+// пока не задан адрес:
+//   CLEAN_ARCH_REDIS_ADDR=localhost:6379 go test -tags redis ./internal/adapter/cache/
+
+// RedisStore кэш в Redis: общий для всех копий сервиса, поэтому
+// инвалидация на одной копии видна остальным сразу
+type RedisStore struct {
+	client *redis.Client
+	prefix string // Пространство ключей, чтобы не пересечься с другими данными
+}
+
+// NewRedisStore создает кэш поверх клиента
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Get реализует Store
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set реализует Store; срок жизни ставит сам Redis (SET ... PX)
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+key, value, ttl).Err()
+}
+
+// Delete реализует Store
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
\ No newline at end of file
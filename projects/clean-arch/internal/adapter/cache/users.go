@@ -0,0 +1,233 @@
+// Package cache кэширующий декоратор порта usecase.UserRepository:
+//
+//	repo = cache.NewUserRepository(sqliteRepo, cache.NewLRU(10000), cache.Options{TTL: time.Minute})
+//
+// Сценарии (usecase) о кэше не знают: для них это еще одна реализация
+// того же интерфейса, которая собирается в main.go.
+//
+// Схема read-through: Get сначала смотрит в кэш, при промахе читает из
+// хранилища и кладет результат в кэш. Одновременные промахи по одному
+// ключу схлопываются в одно чтение (singleflight, как в examples/cache):
+// иначе истечение записи популярного пользователя отправит в БД сотню
+// одинаковых запросов разом (cache stampede). Срок жизни разбрасывается
+// на ±Jitter, чтобы записи, заполненные одновременно (например, после
+// перезапуска), не истекали тоже одновременно.
+//
+// Запись (Create, Update) сначала идет в хранилище, затем удаляет ключ из
+// кэша. Обратный порядок оставляет окно, в котором конкурентный Get
+// вернет в кэш старую версию до записи. В выбранном порядке окно тоже
+// есть (Get прочитал старое до записи, а положил в кэш после удаления),
+// но оно короче и в худшем случае ограничено TTL.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"clean-arch/internal/domain"
+	"clean-arch/internal/usecase"
+)
+
+// Options настройки кэширования
+type Options struct {
+	// TTL срок жизни записи
+	TTL time.Duration
+	// Jitter доля случайного разброса TTL: 0.1 - от 0.9*TTL до 1.1*TTL
+	Jitter float64
+}
+
+// Stats счетчики кэша
+type Stats struct {
+	Hits   int64 // Ответ из кэша
+	Misses int64 // Промах
+	Loads  int64 // Чтения из хранилища; при схлопывании меньше Misses
+	Shared int64 // Промахи, присоединившиеся к уже идущему чтению
+}
+
+// UserRepository кэширует Get; остальные методы идут в хранилище.
+// GetByEmail не кэшируется намеренно: по нему Register проверяет, свободен
+// ли адрес, и устаревший ответ здесь превратился бы в лишнюю ошибку.
+// List тоже: его пришлось бы инвалидировать при любой записи.
+type UserRepository struct {
+	next  usecase.UserRepository
+	store Store
+	opts  Options
+	group group
+
+	hits, misses, loads atomic.Int64
+}
+
+var _ usecase.UserRepository = (*UserRepository)(nil)
+
+// NewUserRepository оборачивает next кэшем в store
+func NewUserRepository(next usecase.UserRepository, store Store, opts Options) *UserRepository {
+	return &UserRepository{next: next, store: store, opts: opts}
+}
+
+// Get реализует usecase.UserRepository
+func (r *UserRepository) Get(ctx context.Context, id int64) (*domain.User, error) {
+	key := userKey(id)
+	if u, ok := r.cached(ctx, key); ok {
+		r.hits.Add(1)
+		return u, nil
+	}
+	r.misses.Add(1)
+	
+	data, err := r.group.Do(ctx, key, func(ctx context.Context) ([]byte, error) {
+		r.loads.Add(1)
+		u, err := r.next.Get(ctx, id)
+		if err != nil {
+			return nil, err // Ошибки и ErrNotFound не кэшируем
+		}
+		data, err := json.Marshal(u)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.store.Set(ctx, key, data, r.ttl()); err != nil {
+			log.Printf("Кэш: не удалось записать %s: %v", key, err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeUser(data)
+}
+
+// cached читает из кэша. Недоступный кэш - не ошибка запроса: хранилище
+// все равно ответит, только медленнее.
+func (r *UserRepository) cached(ctx context.Context, key string) (*domain.User, bool) {
+	data, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		log.Printf("Кэш: не удалось прочитать %s: %v", key, err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	u, err := decodeUser(data)
+	if err != nil {
+		log.Printf("Кэш: испорченная запись %s: %v", key, err)
+		return nil, false
+	}
+	return u, true
+}
+
+// Create реализует usecase.UserRepository. Ключ нового пользователя
+// тоже удаляется: ID мог остаться в кэше от удаленной записи.
+func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
+	if err := r.next.Create(ctx, u); err != nil {
+		return err
+	}
+	r.invalidate(ctx, u.ID)
+	return nil
+}
+
+// Update реализует usecase.UserRepository
+func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
+	err := r.next.Update(ctx, u)
+	// Даже при ошибке: запись могла пройти, а ответ - потеряться
+	if !errors.Is(err, domain.ErrNotFound) {
+		r.invalidate(ctx, u.ID)
+	}
+	return err
+}
+
+// GetByEmail реализует usecase.UserRepository без кэша
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.next.GetByEmail(ctx, email)
+}
+
+// List реализует usecase.UserRepository без кэша
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	return r.next.List(ctx)
+}
+
+// Stats возвращает счетчики
+func (r *UserRepository) Stats() Stats {
+	return Stats{Hits: r.hits.Load(), Misses: r.misses.Load(), Loads: r.loads.Load(), Shared: r.group.shared.Load()}
+}
+
+func (r *UserRepository) invalidate(ctx context.Context, id int64) {
+	// Отмена запроса не должна оставить в кэше старую версию
+	if err := r.store.Delete(context.WithoutCancel(ctx), userKey(id)); err != nil {
+		log.Printf("Кэш: не удалось удалить %s: %v", userKey(id), err)
+	}
+}
+
+// ttl срок жизни со случайным разбросом
+func (r *UserRepository) ttl() time.Duration {
+	if r.opts.Jitter <= 0 {
+		return r.opts.TTL
+	}
+	f := 1 + r.opts.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(r.opts.TTL) * f)
+}
+
+func userKey(id int64) string {
+	return "user:" + strconv.FormatInt(id, 10)
+}
+
+func decodeUser(data []byte) (*domain.User, error) {
+	var u domain.User
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// group схлопывает одновременные загрузки одного ключа. В отличие от
+// Group из examples/cache, ожидающий может уйти по своему контексту, а
+// сама загрузка идет с контекстом без отмены: отмена первого запроса не
+// должна оборачиваться ошибкой для всех остальных.
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+	
+	// shared считает вызовы, которые застали загрузку ключа и ждут ее
+	// результата. Увеличивается под mu, до ожидания: в отличие от misses,
+	// значение n означает, что n вызовов уже привязаны к загрузке.
+	shared atomic.Int64
+}
+
+type call struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+func (g *group) Do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		g.calls[key] = c
+		go func() {
+			c.value, c.err = fn(context.WithoutCancel(ctx))
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(c.done)
+		}()
+	} else {
+		g.shared.Add(1)
+	}
+	g.mu.Unlock()
+	
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
\ No newline at end of file
@@ -0,0 +1,262 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"clean-arch/internal/adapter/memory"
+	"clean-arch/internal/adapter/repotest"
+	"clean-arch/internal/domain"
+	"clean-arch/internal/usecase"
+)
+
+func TestUserRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) usecase.UserRepository {
+		return NewUserRepository(memory.NewUserRepository(), NewLRU(100), Options{TTL: time.Minute})
+	})
+}
+
+// countingRepo считает чтения и может задерживать их до сигнала
+type countingRepo struct {
+	usecase.UserRepository
+	gets    atomic.Int64
+	release chan struct{} // nil - без задержки
+}
+
+func (r *countingRepo) Get(ctx context.Context, id int64) (*domain.User, error) {
+	r.gets.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	return r.UserRepository.Get(ctx, id)
+}
+
+func newTestRepo(t *testing.T) (*UserRepository, *countingRepo, *domain.User) {
+	t.Helper()
+	next := &countingRepo{UserRepository: memory.NewUserRepository()}
+	u := &domain.User{Name: "Иван", Email: "ivan@example.com", Active: true, CreatedAt: time.Now().UTC()}
+	if err := next.Create(context.Background(), u); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return NewUserRepository(next, NewLRU(100), Options{TTL: time.Minute}), next, u
+}
+
+func TestGetReadThrough(t *testing.T) {
+	ctx := context.Background()
+	repo, next, u := newTestRepo(t)
+	for range 3 {
+		got, err := repo.Get(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.Email != u.Email {
+			t.Errorf("Expected email %q, got %q", u.Email, got.Email)
+		}
+	}
+	if n := next.gets.Load(); n != 1 {
+		t.Errorf("Expected 1 repository read, got %d", n)
+	}
+	if s := repo.Stats(); s != (Stats{Hits: 2, Misses: 1, Loads: 1}) {
+		t.Errorf("Expected 2 hits, 1 miss, 1 load, got %+v", s)
+	}
+	
+	// Изменение полученной копии не портит кэш
+	got, _ := repo.Get(ctx, u.ID)
+	got.Name = "Изменено"
+	if again, _ := repo.Get(ctx, u.ID); again.Name != u.Name {
+		t.Errorf("Expected cached name %q, got %q", u.Name, again.Name)
+	}
+}
+
+func TestGetNotFoundNotCached(t *testing.T) {
+	ctx := context.Background()
+	repo, next, _ := newTestRepo(t)
+	for range 2 {
+		if _, err := repo.Get(ctx, 999); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	}
+	if n := next.gets.Load(); n != 2 {
+		t.Errorf("Expected 2 repository reads, got %d", n)
+	}
+}
+
+func TestStampede(t *testing.T) {
+	ctx := context.Background()
+	repo, next, u := newTestRepo(t)
+	next.release = make(chan struct{})
+	
+	const callers = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Get(ctx, u.ID)
+			errs <- err
+		}()
+	}
+	// Ждем, пока все встанут в очередь за одной загрузкой. Misses для
+	// этого не годится: промах считается до group.Do, и вызов, который
+	// еще не дошел до группы, после release начал бы вторую загрузку.
+	for {
+		s := repo.Stats()
+		if s.Loads+s.Shared == callers {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(next.release)
+	wg.Wait()
+	close(errs)
+	
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if n := next.gets.Load(); n != 1 {
+		t.Errorf("Expected 1 repository read for %d callers, got %d", callers, n)
+	}
+	if s := repo.Stats(); s.Shared != callers-1 {
+		t.Errorf("Expected %d shared loads, got %d", callers-1, s.Shared)
+	}
+}
+
+func TestWaiterCancel(t *testing.T) {
+	repo, next, u := newTestRepo(t)
+	next.release = make(chan struct{})
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.Get(ctx, u.ID)
+		done <- err
+	}()
+	for next.gets.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	
+	// Загрузка не прервана и заполнит кэш для следующих запросов
+	close(next.release)
+	for {
+		if _, ok, _ := repo.store.Get(context.Background(), userKey(u.ID)); ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := repo.Get(context.Background(), u.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := next.gets.Load(); n != 1 {
+		t.Errorf("Expected 1 repository read, got %d", n)
+	}
+}
+
+func TestUpdateInvalidates(t *testing.T) {
+	ctx := context.Background()
+	repo, _, u := newTestRepo(t)
+	if _, err := repo.Get(ctx, u.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	changed := *u
+	changed.Email = "new@example.com"
+	if err := repo.Update(ctx, &changed); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, err := repo.Get(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Email != changed.Email {
+		t.Errorf("Expected email %q after update, got %q", changed.Email, got.Email)
+	}
+}
+
+// failingStore недоступный кэш
+type failingStore struct{}
+
+var errStoreDown = errors.New("store down")
+
+func (failingStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errStoreDown
+}
+func (failingStore) Set(context.Context, string, []byte, time.Duration) error {
+	return errStoreDown
+}
+func (failingStore) Delete(context.Context, string) error { return errStoreDown }
+
+func TestStoreDownFallsBack(t *testing.T) {
+	ctx := context.Background()
+	next := memory.NewUserRepository()
+	u := &domain.User{Name: "Иван", Email: "ivan@example.com", Active: true}
+	if err := next.Create(ctx, u); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := NewUserRepository(next, failingStore{}, Options{TTL: time.Minute})
+	if _, err := repo.Get(ctx, u.ID); err != nil {
+		t.Fatalf("Expected fallback to repository, got %v", err)
+	}
+	if err := repo.Update(ctx, u); err != nil {
+		t.Fatalf("Expected write to succeed, got %v", err)
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	repo := NewUserRepository(nil, NewLRU(1), Options{TTL: time.Minute, Jitter: 0.2})
+	lo, hi := time.Minute, time.Duration(0)
+	for range 1000 {
+		ttl := repo.ttl()
+		lo, hi = min(lo, ttl), max(hi, ttl)
+	}
+	if lo < 48*time.Second || hi > 72*time.Second {
+		t.Errorf("Expected TTL within 48s..72s, got %v..%v", lo, hi)
+	}
+	if hi-lo < 10*time.Second {
+		t.Errorf("Expected TTL to be spread, got %v..%v", lo, hi)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+	
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	c.Get(ctx, "a") // "a" свежее "b"
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+	
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"a", true},
+		{"b", false}, // Вытеснен
+		{"c", true},
+	}
+	for _, tt := range tests {
+		if _, ok, _ := c.Get(ctx, tt.key); ok != tt.want {
+			t.Errorf("Get(%q): expected %v, got %v", tt.key, tt.want, ok)
+		}
+	}
+	
+	now = now.Add(time.Minute)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Expected entry to expire")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Expected 1 entry after expiry, got %d", c.Len())
+	}
+}
\ No newline at end of file
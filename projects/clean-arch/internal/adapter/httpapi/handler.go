@@ -0,0 +1,188 @@
+// Package httpapi HTTP-адаптер: разбирает запросы, вызывает сценарии
+// и переводит ошибки предметной области в статусы. Бизнес-правил
+// здесь нет - только транспорт.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"clean-arch/internal/domain"
+)
+
+// UserService то, что адаптеру нужно от сценариев. Объявлен здесь,
+// чтобы HTTP-слой можно было тестировать с подставной реализацией;
+// *usecase.Users ему удовлетворяет.
+type UserService interface {
+	Register(ctx context.Context, name, email string) (*domain.User, error)
+	Get(ctx context.Context, id int64) (*domain.User, error)
+	List(ctx context.Context) ([]domain.User, error)
+	ChangeEmail(ctx context.Context, id int64, email string) (*domain.User, error)
+	Deactivate(ctx context.Context, id int64) (*domain.User, error)
+}
+
+// Handler HTTP-обработчики пользователей
+type Handler struct {
+	users UserService
+}
+
+// NewHandler создает обработчики
+func NewHandler(users UserService) *Handler {
+	return &Handler{users: users}
+}
+
+// Routes возвращает маршруты:
+//
+//	POST  /api/users                 {"name", "email"} -> 201
+//	GET   /api/users
+//	GET   /api/users/{id}
+//	PATCH /api/users/{id}/email      {"email"}
+//	POST  /api/users/{id}/deactivate
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/users", h.register)
+	mux.HandleFunc("GET /api/users", h.list)
+	mux.HandleFunc("GET /api/users/{id}", h.get)
+	mux.HandleFunc("PATCH /api/users/{id}/email", h.changeEmail)
+	mux.HandleFunc("POST /api/users/{id}/deactivate", h.deactivate)
+	return mux
+}
+
+// userResponse формат пользователя в API: отделен от domain.User,
+// чтобы переименование поля в домене не ломало клиентов
+type userResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toResponse(u domain.User) userResponse {
+	return userResponse{u.ID, u.Name, u.Email, u.Active, u.CreatedAt}
+}
+
+func (h *Handler) register(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	u, err := h.users.Register(r.Context(), req.Name, req.Email)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/api/users/"+strconv.FormatInt(u.ID, 10))
+	writeJSON(w, http.StatusCreated, toResponse(*u))
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	users, err := h.users.List(r.Context())
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	resp := make([]userResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, toResponse(u))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	u, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toResponse(*u))
+}
+
+func (h *Handler) changeEmail(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Email string `json:"email"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	u, err := h.users.ChangeEmail(r.Context(), id, req.Email)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toResponse(*u))
+}
+
+func (h *Handler) deactivate(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	u, err := h.users.Deactivate(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toResponse(*u))
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, domain.ErrNotFound.Error())
+		return 0, false
+	}
+	return id, true
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return false
+	}
+	return true
+}
+
+// writeDomainError единственное место, где ошибки домена
+// превращаются в HTTP-статусы
+func writeDomainError(w http.ResponseWriter, err error) {
+	var verr *domain.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		writeError(w, http.StatusUnprocessableEntity, verr.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrEmailTaken), errors.Is(err, domain.ErrDeactivated):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Внутренняя ошибка: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"clean-arch/internal/domain"
+)
+
+// stubService возвращает заданного пользователя или ошибку: адаптер
+// проверяется отдельно от сценариев
+type stubService struct {
+	user *domain.User
+	err  error
+}
+
+func (s stubService) Register(ctx context.Context, name, email string) (*domain.User, error) {
+	return s.user, s.err
+}
+
+func (s stubService) Get(ctx context.Context, id int64) (*domain.User, error) {
+	return s.user, s.err
+}
+
+func (s stubService) List(ctx context.Context) ([]domain.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []domain.User{*s.user}, nil
+}
+
+func (s stubService) ChangeEmail(ctx context.Context, id int64, email string) (*domain.User, error) {
+	return s.user, s.err
+}
+
+func (s stubService) Deactivate(ctx context.Context, id int64) (*domain.User, error) {
+	return s.user, s.err
+}
+
+func TestRegisterResponse(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	h := NewHandler(stubService{user: &domain.User{ID: 7, Name: "Иван", Email: "ivan@example.com", Active: true, CreatedAt: created}})
+	
+	req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{"name":"Иван","email":"ivan@example.com"}`))
+	rec := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rec, req)
+	
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/api/users/7" {
+		t.Errorf("Expected Location /api/users/7, got %q", loc)
+	}
+	var got map[string]any
+	json.NewDecoder(rec.Body).Decode(&got)
+	if got["email"] != "ivan@example.com" || got["active"] != true || got["created_at"] != "2024-03-01T12:00:00Z" {
+		t.Errorf("Unexpected body %v", got)
+	}
+}
+
+func TestErrorStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		err    error
+		want   int
+	}{
+		{"validation", "POST", "/api/users", `{}`, &domain.ValidationError{Field: "email", Reason: "is required"}, http.StatusUnprocessableEntity},
+		{"email taken", "POST", "/api/users", `{}`, domain.ErrEmailTaken, http.StatusConflict},
+		{"bad JSON", "POST", "/api/users", `{`, nil, http.StatusBadRequest},
+		{"not found", "GET", "/api/users/1", "", domain.ErrNotFound, http.StatusNotFound},
+		{"bad ID", "GET", "/api/users/abc", "", nil, http.StatusNotFound},
+		{"deactivated", "PATCH", "/api/users/1/email", `{"email":"x@example.com"}`, domain.ErrDeactivated, http.StatusConflict},
+		{"wrapped", "POST", "/api/users/1/deactivate", "", errors.Join(errors.New("tx"), domain.ErrNotFound), http.StatusNotFound},
+		{"internal", "GET", "/api/users", "", errors.New("db is down"), http.StatusInternalServerError},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(stubService{err: tt.err})
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			h.Routes().ServeHTTP(rec, req)
+			
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body)
+			}
+			if tt.want == http.StatusInternalServerError && strings.Contains(rec.Body.String(), "db is down") {
+				t.Error("Expected internal error details to be hidden")
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,236 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newSQLStore(t *testing.T) Store {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	store := NewSQLStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return store
+}
+
+var stores = []struct {
+	name string
+	new  func(t *testing.T) Store
+}{
+	{"memory", func(t *testing.T) Store { return NewMemoryStore() }},
+	{"sqlite", newSQLStore},
+}
+
+func TestStore(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := s.new(t)
+			now := time.UnixMilli(1_700_000_000_000)
+			
+			if rec, err := store.Begin(ctx, "k", "fp", now, now.Add(time.Minute)); rec != nil || err != nil {
+				t.Fatalf("Expected free key, got %+v, %v", rec, err)
+			}
+			rec, err := store.Begin(ctx, "k", "fp", now, now.Add(time.Minute))
+			if err != nil || rec == nil || rec.Done {
+				t.Fatalf("Expected in-progress record, got %+v, %v", rec, err)
+			}
+			
+			resp := Response{Status: 201, Header: map[string]string{"Location": "/api/users/1"}, Body: []byte(`{"id":1}`)}
+			if err := store.Complete(ctx, "k", resp, now.Add(time.Hour)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// Release не трогает завершенный запрос
+			store.Release(ctx, "k")
+			
+			rec, err = store.Begin(ctx, "k", "fp", now.Add(30*time.Minute), now.Add(31*time.Minute))
+			if err != nil || rec == nil || !rec.Done {
+				t.Fatalf("Expected completed record, got %+v, %v", rec, err)
+			}
+			if rec.Fingerprint != "fp" || rec.Response.Status != 201 || string(rec.Response.Body) != `{"id":1}` || rec.Response.Header["Location"] != "/api/users/1" {
+				t.Errorf("Expected saved response, got %+v", rec)
+			}
+			
+			// После TTL ключ свободен
+			if rec, err := store.Begin(ctx, "k", "other", now.Add(2*time.Hour), now.Add(3*time.Hour)); rec != nil || err != nil {
+				t.Errorf("Expected expired key to be free, got %+v, %v", rec, err)
+			}
+			if n, err := store.DeleteExpired(ctx, now.Add(4*time.Hour)); n != 1 || err != nil {
+				t.Errorf("Expected 1 expired record deleted, got %d, %v", n, err)
+			}
+		})
+	}
+}
+
+func TestStoreConcurrentBegin(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.new(t)
+			now := time.Now()
+			
+			var wg sync.WaitGroup
+			var winners atomic.Int32
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					rec, err := store.Begin(context.Background(), "k", "fp", now, now.Add(time.Minute))
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+					}
+					if rec == nil {
+						winners.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+			if winners.Load() != 1 {
+				t.Errorf("Expected exactly one request to take the key, got %d", winners.Load())
+			}
+		})
+	}
+}
+
+// createHandler имитирует POST /api/users: каждый вызов создает нового
+// пользователя
+func createHandler(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "fail") {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/api/users/%d", n))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":%d}`, n)
+	})
+}
+
+func send(h http.Handler, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests [][3]string // method, key, body
+		status   int         // Статус последнего ответа
+		calls    int32
+		replayed bool
+	}{
+		{"retry replays response", [][3]string{{"POST", "k1", `{"a":1}`}, {"POST", "k1", `{"a":1}`}}, 201, 1, true},
+		{"different payload conflicts", [][3]string{{"POST", "k1", `{"a":1}`}, {"POST", "k1", `{"a":2}`}}, 409, 1, false},
+		{"different keys run twice", [][3]string{{"POST", "k1", `{"a":1}`}, {"POST", "k2", `{"a":1}`}}, 201, 2, false},
+		{"no key runs twice", [][3]string{{"POST", "", `{"a":1}`}, {"POST", "", `{"a":1}`}}, 201, 2, false},
+		{"server error is not saved", [][3]string{{"POST", "k1", `fail`}, {"POST", "k1", `fail`}}, 500, 2, false},
+		{"GET is ignored", [][3]string{{"GET", "k1", ``}, {"GET", "k1", ``}}, 201, 2, false},
+		{"key too long", [][3]string{{"POST", strings.Repeat("k", 300), `{}`}}, 400, 0, false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			h := New(NewMemoryStore()).Wrap(createHandler(&calls))
+			
+			var first, last *httptest.ResponseRecorder
+			for i, req := range tt.requests {
+				last = send(h, req[0], "/api/users", req[1], req[2])
+				if i == 0 {
+					first = last
+				}
+			}
+			if last.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, last.Code, last.Body)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("Expected handler to run %d times, got %d", tt.calls, calls.Load())
+			}
+			if replayed := last.Header().Get(HeaderReplayed) == "true"; replayed != tt.replayed {
+				t.Errorf("Expected replayed=%v, got %v", tt.replayed, replayed)
+			}
+			if tt.replayed {
+				if last.Body.String() != first.Body.String() || last.Header().Get("Location") != first.Header().Get("Location") {
+					t.Errorf("Expected identical replay, got %q %q vs %q %q",
+						last.Body, last.Header().Get("Location"), first.Body, first.Header().Get("Location"))
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareSamePathDifferentEndpoint(t *testing.T) {
+	var calls atomic.Int32
+	h := New(NewMemoryStore()).Wrap(createHandler(&calls))
+	
+	send(h, http.MethodPost, "/api/users", "k1", `{}`)
+	if rec := send(h, http.MethodPost, "/api/users/1/deactivate", "k1", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for key reused on another endpoint, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareInProgress(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	h := New(NewMemoryStore()).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+	
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send(h, http.MethodPost, "/api/users", "k1", `{}`) }()
+	<-started
+	
+	rec := send(h, http.MethodPost, "/api/users", "k1", `{}`)
+	if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 409 with Retry-After while first request runs, got %d", rec.Code)
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("Expected first request to finish with 201, got %d", first.Code)
+	}
+	if rec := send(h, http.MethodPost, "/api/users", "k1", `{}`); rec.Code != http.StatusCreated {
+		t.Errorf("Expected replayed 201, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareTTL(t *testing.T) {
+	var calls atomic.Int32
+	now := time.Now()
+	m := New(NewMemoryStore())
+	m.now = func() time.Time { return now }
+	h := m.Wrap(createHandler(&calls))
+	
+	send(h, http.MethodPost, "/api/users", "k1", `{}`)
+	now = now.Add(m.TTL + time.Second)
+	rec := send(h, http.MethodPost, "/api/users", "k1", `{}`)
+	
+	if calls.Load() != 2 || rec.Header().Get(HeaderReplayed) != "" {
+		t.Errorf("Expected expired key to run the handler again, got %d calls", calls.Load())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,185 @@
+// Package idempotency делает POST и PATCH безопасными для повторов.
+// Клиент посылает заголовок Idempotency-Key; первый запрос с ключом
+// выполняется, а его ответ сохраняется. Повтор с тем же ключом и телом
+// (клиент не дождался ответа из-за таймаута) получает сохраненный ответ,
+// и пользователь не создается второй раз.
+//
+// Это HTTP-адаптер: сценарии о ключах не знают. SQL-хранилище лежит
+// здесь же, а не в adapter/sqlite, потому что адаптеры не импортируют
+// друг друга (arch_test.go).
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Заголовки протокола
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed" // "true" в повторенном ответе
+)
+
+// replayHeaders заголовки, которые сохраняются вместе с ответом
+var replayHeaders = []string{"Content-Type", "Location"}
+
+const (
+	maxKeyLength = 255
+	maxBodyBytes = 1 << 20
+)
+
+// Middleware проверяет ключи идемпотентности
+type Middleware struct {
+	store Store
+	now   func() time.Time
+
+	// TTL сколько хранить ответ: повтор после этого выполнится заново
+	TTL time.Duration
+	// LockTimeout сколько ключ считается занятым выполняющимся запросом.
+	// Если процесс упал посреди запроса, ключ освободится через это время.
+	LockTimeout time.Duration
+}
+
+// New создает middleware с TTL ответа 24 часа
+func New(store Store) *Middleware {
+	return &Middleware{store: store, now: time.Now, TTL: 24 * time.Hour, LockTimeout: time.Minute}
+}
+
+// Wrap применяет проверку к POST и PATCH с заголовком Idempotency-Key.
+// Запросы без ключа проходят как есть.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+		
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := fingerprint(r, body)
+		
+		now := m.now()
+		rec, err := m.store.Begin(r.Context(), key, fingerprint, now, now.Add(m.LockTimeout))
+		if err != nil {
+			log.Printf("Ошибка хранилища ключей идемпотентности: %v", err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		
+		switch {
+		case rec == nil:
+			m.execute(w, r, key, next)
+		case rec.Fingerprint != fingerprint:
+			// Тот же ключ с другим запросом - ошибка клиента: молча вернуть
+			// чужой ответ или выполнить второй запрос одинаково опасно
+			writeError(w, http.StatusConflict, "Idempotency-Key was used with a different request")
+		case !rec.Done:
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusConflict, "request with this Idempotency-Key is in progress")
+		default:
+			for name, value := range rec.Response.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(HeaderReplayed, "true")
+			w.WriteHeader(rec.Response.Status)
+			w.Write(rec.Response.Body)
+		}
+	})
+}
+
+// execute выполняет первый запрос с ключом и сохраняет ответ.
+// Ответы 5xx не сохраняются: сбой временный, и повтор должен
+// выполниться по-настоящему.
+func (m *Middleware) execute(w http.ResponseWriter, r *http.Request, key string, next http.Handler) {
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+	// Ключ нужно освободить или сохранить, даже если клиент ушел
+	ctx := context.WithoutCancel(r.Context())
+	defer func() {
+		if p := recover(); p != nil {
+			m.store.Release(ctx, key)
+			panic(p)
+		}
+	}()
+	
+	next.ServeHTTP(rec, r)
+	
+	if rec.status >= 500 {
+		if err := m.store.Release(ctx, key); err != nil {
+			log.Printf("Ошибка освобождения ключа %q: %v", key, err)
+		}
+		return
+	}
+	resp := Response{Status: rec.status, Header: make(map[string]string), Body: rec.body.Bytes()}
+	for _, name := range replayHeaders {
+		if v := rec.Header().Get(name); v != "" {
+			resp.Header[name] = v
+		}
+	}
+	if err := m.store.Complete(ctx, key, resp, m.now().Add(m.TTL)); err != nil {
+		log.Printf("Ошибка сохранения ответа для ключа %q: %v", key, err)
+	}
+}
+
+// Cleanup раз в interval удаляет устаревшие ключи; блокируется до отмены ctx
+func (m *Middleware) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.store.DeleteExpired(ctx, m.now()); err != nil && ctx.Err() == nil {
+				log.Printf("Ошибка очистки ключей идемпотентности: %v", err)
+			}
+		}
+	}
+}
+
+// fingerprint отличает "тот же запрос" от "другого запроса с тем же
+// ключом": метод, путь и тело
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder пишет ответ клиенту и одновременно запоминает его
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
\ No newline at end of file
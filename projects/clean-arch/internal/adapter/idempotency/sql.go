@@ -0,0 +1,104 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SQLStore ключи в SQLite: переживают перезапуск и общие для всех
+// копий сервиса, работающих с одной БД
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore создает хранилище
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Migrate создает таблицу idempotency_keys
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key TEXT PRIMARY KEY,
+		fingerprint TEXT NOT NULL,
+		done INTEGER NOT NULL DEFAULT 0,
+		status INTEGER NOT NULL DEFAULT 0,
+		header TEXT NOT NULL DEFAULT '{}',
+		body BLOB,
+		expires_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_idempotency_expires ON idempotency_keys(expires_at);`)
+	return err
+}
+
+// Begin реализует Store. Атомарность дает PRIMARY KEY: из двух
+// одновременных INSERT ... ON CONFLICT DO NOTHING строку вставит один.
+func (s *SQLStore) Begin(ctx context.Context, key, fingerprint string, now, expiresAt time.Time) (*Record, error) {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = ? AND expires_at <= ?`,
+		key, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO NOTHING`,
+		key, fingerprint, expiresAt.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil, nil
+	}
+	
+	var rec Record
+	var header string
+	var expires int64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT key, fingerprint, done, status, header, body, expires_at
+		FROM idempotency_keys WHERE key = ?`, key).
+		Scan(&rec.Key, &rec.Fingerprint, &rec.Done, &rec.Response.Status, &header, &rec.Response.Body, &expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Запись успели освободить между INSERT и SELECT - пробуем снова
+		return s.Begin(ctx, key, fingerprint, now, expiresAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(header), &rec.Response.Header); err != nil {
+		return nil, err
+	}
+	rec.ExpiresAt = time.UnixMilli(expires)
+	return &rec, nil
+}
+
+// Complete реализует Store
+func (s *SQLStore) Complete(ctx context.Context, key string, resp Response, expiresAt time.Time) error {
+	header, err := json.Marshal(resp.Header)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET done = 1, status = ?, header = ?, body = ?, expires_at = ?
+		WHERE key = ?`,
+		resp.Status, string(header), resp.Body, expiresAt.UnixMilli(), key)
+	return err
+}
+
+// Release реализует Store
+func (s *SQLStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = ? AND done = 0`, key)
+	return err
+}
+
+// DeleteExpired реализует Store
+func (s *SQLStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Response сохраненный ответ, который повторяется на повторные запросы
+type Response struct {
+	Status int
+	Header map[string]string // Только заголовки из replayHeaders
+	Body   []byte
+}
+
+// Record запись о ключе
+type Record struct {
+	Key         string
+	Fingerprint string    // Хеш метода, пути и тела первого запроса
+	Done        bool      // false - первый запрос еще выполняется
+	Response    Response  // Заполнен, если Done
+	ExpiresAt   time.Time // После этого ключ можно использовать заново
+}
+
+// Store хранилище ключей. Begin должен быть атомарным: из двух
+// одновременных запросов с одним ключом ключ получает только один.
+type Store interface {
+	// Begin занимает свободный (или устаревший) ключ и возвращает nil.
+	// Если ключ занят, возвращает существующую запись.
+	Begin(ctx context.Context, key, fingerprint string, now, expiresAt time.Time) (*Record, error)
+	// Complete сохраняет ответ и продлевает запись до expiresAt
+	Complete(ctx context.Context, key string, resp Response, expiresAt time.Time) error
+	// Release освобождает ключ, чтобы запрос можно было повторить
+	Release(ctx context.Context, key string) error
+	// DeleteExpired удаляет устаревшие записи
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// MemoryStore ключи в памяти процесса: для запуска без БД и тестов.
+// После перезапуска ключи теряются, а с несколькими копиями сервиса
+// не работает - для этого нужен SQLStore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore создает пустое хранилище
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Begin реализует Store
+func (s *MemoryStore) Begin(ctx context.Context, key, fingerprint string, now, expiresAt time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.records[key]; ok && rec.ExpiresAt.After(now) {
+		return &rec, nil
+	}
+	s.records[key] = Record{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}
+	return nil, nil
+}
+
+// Complete реализует Store
+func (s *MemoryStore) Complete(ctx context.Context, key string, resp Response, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := s.records[key]
+	rec.Done, rec.Response, rec.ExpiresAt = true, resp, expiresAt
+	s.records[key] = rec
+	return nil
+}
+
+// Release реализует Store
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.records[key].Done {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// DeleteExpired реализует Store
+func (s *MemoryStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for key, rec := range s.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.records, key)
+			n++
+		}
+	}
+	return n, nil
+}
\ No newline at end of file
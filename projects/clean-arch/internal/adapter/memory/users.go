@@ -0,0 +1,99 @@
+// Package memory хранилище пользователей в памяти: для тестов,
+// демонстрации и запуска без БД
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"clean-arch/internal/domain"
+)
+
+// UserRepository реализует usecase.UserRepository в памяти
+type UserRepository struct {
+	mu     sync.RWMutex
+	users  map[int64]domain.User
+	nextID int64
+}
+
+// NewUserRepository создает пустое хранилище
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: make(map[int64]domain.User)}
+}
+
+func (r *UserRepository) emailTaken(email string, exceptID int64) bool {
+	for id, u := range r.users {
+		if u.Email == email && id != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// Create сохраняет пользователя
+func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.emailTaken(u.Email, 0) {
+		return domain.ErrEmailTaken
+	}
+	r.nextID++
+	u.ID = r.nextID
+	r.users[u.ID] = *u
+	return nil
+}
+
+// Get возвращает пользователя по ID
+func (r *UserRepository) Get(ctx context.Context, id int64) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &u, nil
+}
+
+// GetByEmail возвращает пользователя по email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+// List возвращает пользователей в порядке ID
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	users := make([]domain.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	slices.SortFunc(users, func(a, b domain.User) int { return int(a.ID - b.ID) })
+	return users, nil
+}
+
+// Update сохраняет пользователя
+func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if _, ok := r.users[u.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	if r.emailTaken(u.Email, u.ID) {
+		return domain.ErrEmailTaken
+	}
+	r.users[u.ID] = *u
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"clean-arch/internal/adapter/repotest"
+	"clean-arch/internal/usecase"
+)
+
+func TestUserRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) usecase.UserRepository {
+		return NewUserRepository()
+	})
+}
\ No newline at end of file
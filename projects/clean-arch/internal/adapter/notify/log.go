@@ -0,0 +1,21 @@
+// Package notify уведомления пользователям. Настоящая отправка писем
+// заменена записью в лог: сценарию регистрации все равно, что под портом.
+package notify
+
+import (
+	"context"
+	"log"
+
+	"clean-arch/internal/domain"
+)
+
+// LogNotifier реализует usecase.Notifier записью в лог
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+// Welcome "отправляет" приветственное письмо
+func (n LogNotifier) Welcome(ctx context.Context, u domain.User) error {
+	n.Logger.Printf("Письмо для %s: добро пожаловать, %s!", u.Email, u.Name)
+	return nil
+}
\ No newline at end of file
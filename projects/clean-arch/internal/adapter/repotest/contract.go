@@ -0,0 +1,101 @@
+// Package repotest общий контракт для реализаций usecase.UserRepository
+// (подход из examples/repository-contract). Обычный пакет, а не _test.go:
+// иначе тесты memory и sqlite не смогли бы его импортировать.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"clean-arch/internal/domain"
+	"clean-arch/internal/usecase"
+)
+
+// Run проверяет поведение хранилища; newRepo возвращает пустое хранилище
+func Run(t *testing.T, newRepo func(t *testing.T) usecase.UserRepository) {
+	ctx := context.Background()
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	newUser := func(name, email string) *domain.User {
+		return &domain.User{Name: name, Email: email, Active: true, CreatedAt: created}
+	}
+	
+	t.Run("create and get", func(t *testing.T) {
+		repo := newRepo(t)
+		u := newUser("Иван", "ivan@example.com")
+		mustCreate(t, repo, u)
+		if u.ID == 0 {
+			t.Fatal("Expected ID to be assigned")
+		}
+		
+		got, err := repo.Get(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if *got != *u {
+			t.Errorf("Expected %+v, got %+v", *u, *got)
+		}
+		byEmail, err := repo.GetByEmail(ctx, "ivan@example.com")
+		if err != nil || byEmail.ID != u.ID {
+			t.Errorf("Expected user %d by email, got %v, %v", u.ID, byEmail, err)
+		}
+	})
+	
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.Get(ctx, 42); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound from Get, got %v", err)
+		}
+		if _, err := repo.GetByEmail(ctx, "nobody@example.com"); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound from GetByEmail, got %v", err)
+		}
+		if err := repo.Update(ctx, &domain.User{ID: 42, Email: "x@example.com"}); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound from Update, got %v", err)
+		}
+	})
+	
+	t.Run("email uniqueness", func(t *testing.T) {
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("A", "a@example.com"))
+		b := newUser("B", "b@example.com")
+		mustCreate(t, repo, b)
+		
+		if err := repo.Create(ctx, newUser("C", "a@example.com")); !errors.Is(err, domain.ErrEmailTaken) {
+			t.Errorf("Expected ErrEmailTaken from Create, got %v", err)
+		}
+		b.Email = "a@example.com"
+		if err := repo.Update(ctx, b); !errors.Is(err, domain.ErrEmailTaken) {
+			t.Errorf("Expected ErrEmailTaken from Update, got %v", err)
+		}
+	})
+	
+	t.Run("update and list", func(t *testing.T) {
+		repo := newRepo(t)
+		a := newUser("A", "a@example.com")
+		b := newUser("B", "b@example.com")
+		mustCreate(t, repo, a)
+		mustCreate(t, repo, b)
+		
+		a.Active = false
+		a.Email = "a2@example.com"
+		if err := repo.Update(ctx, a); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		
+		users, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(users) != 2 || users[0] != *a || users[1] != *b {
+			t.Errorf("Expected [%+v %+v], got %+v", *a, *b, users)
+		}
+	})
+}
+
+func mustCreate(t *testing.T, repo usecase.UserRepository, u *domain.User) {
+	t.Helper()
+	if err := repo.Create(context.Background(), u); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,128 @@
+// Package sqlite хранилище пользователей в SQLite. Единственное место
+// в проекте, которое знает, как пользователи лежат в таблицах.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"clean-arch/internal/domain"
+)
+
+// UserRepository реализует usecase.UserRepository поверх database/sql
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository создает хранилище
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Migrate создает таблицу users
+func (r *UserRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL UNIQUE,
+		active INTEGER NOT NULL,
+		created_at INTEGER NOT NULL
+	)`)
+	return err
+}
+
+// mapErr переводит ошибки драйвера в ошибки предметной области
+func mapErr(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrNotFound
+	case err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed"):
+		return domain.ErrEmailTaken
+	}
+	return err
+}
+
+// Create сохраняет пользователя
+func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO users (name, email, active, created_at) VALUES (?, ?, ?, ?)
+		RETURNING id`,
+		u.Name, u.Email, u.Active, u.CreatedAt.UnixMilli()).Scan(&u.ID)
+	return mapErr(err)
+}
+
+// Get возвращает пользователя по ID
+func (r *UserRepository) Get(ctx context.Context, id int64) (*domain.User, error) {
+	return r.scanOne(r.db.QueryRowContext(ctx, `
+		SELECT id, name, email, active, created_at FROM users WHERE id = ?`, id))
+}
+
+// GetByEmail возвращает пользователя по email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.scanOne(r.db.QueryRowContext(ctx, `
+		SELECT id, name, email, active, created_at FROM users WHERE email = ?`, email))
+}
+
+// List возвращает пользователей в порядке ID
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, email, active, created_at FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	users := []domain.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, rows.Err()
+}
+
+// Update сохраняет пользователя
+func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET name = ?, email = ?, active = ? WHERE id = ?`,
+		u.Name, u.Email, u.Active, u.ID)
+	if err != nil {
+		return mapErr(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *UserRepository) scanOne(s scanner) (*domain.User, error) {
+	u, err := scanUser(s)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return u, nil
+}
+
+func scanUser(s scanner) (*domain.User, error) {
+	var u domain.User
+	var createdAt int64
+	if err := s.Scan(&u.ID, &u.Name, &u.Email, &u.Active, &createdAt); err != nil {
+		return nil, err
+	}
+	u.CreatedAt = time.UnixMilli(createdAt).UTC()
+	return &u, nil
+}
\ No newline at end of file
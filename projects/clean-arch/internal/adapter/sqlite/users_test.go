@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"clean-arch/internal/adapter/repotest"
+	"clean-arch/internal/usecase"
+)
+
+func TestUserRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) usecase.UserRepository {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		
+		repo := NewUserRepository(db)
+		if err := repo.Migrate(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return repo
+	})
+}
\ No newline at end of file
@@ -0,0 +1,104 @@
+// Package domain содержит сущности и правила, которые не зависят
+// ни от хранилища, ни от транспорта. Импортирует только стандартную
+// библиотеку - это проверяет arch_test.go в корне проекта.
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Ошибки предметной области. Адаптеры переводят их в свои коды:
+// HTTP - в статусы, SQL-хранилище - из нарушений ограничений.
+var (
+	ErrNotFound    = errors.New("user not found")
+	ErrEmailTaken  = errors.New("email already taken")
+	ErrDeactivated = errors.New("user is deactivated")
+)
+
+// ValidationError неверное значение поля
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// maxNameLength ограничение длины имени в символах
+const maxNameLength = 100
+
+// User пользователь. Теги json здесь намеренно отсутствуют:
+// формат ответа - забота HTTP-адаптера.
+type User struct {
+	ID        int64
+	Name      string
+	Email     string
+	Active    bool
+	CreatedAt time.Time
+}
+
+// NewUser создает активного пользователя с проверенными полями
+func NewUser(name, email string, now time.Time) (*User, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	email, err = NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return &User{Name: name, Email: email, Active: true, CreatedAt: now}, nil
+}
+
+// ChangeEmail меняет email; у деактивированного пользователя - нельзя
+func (u *User) ChangeEmail(email string) error {
+	if !u.Active {
+		return ErrDeactivated
+	}
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	u.Email = email
+	return nil
+}
+
+// Deactivate выключает пользователя; повторный вызов - ошибка,
+// чтобы вызывающий код не считал, что что-то изменил
+func (u *User) Deactivate() error {
+	if !u.Active {
+		return ErrDeactivated
+	}
+	u.Active = false
+	return nil
+}
+
+// NormalizeEmail проверяет адрес и приводит его к нижнему регистру,
+// чтобы Ivan@Example.com и ivan@example.com считались одним адресом
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", &ValidationError{"email", "is required"}
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", &ValidationError{"email", "is malformed"}
+	}
+	return email, nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", &ValidationError{"name", "is required"}
+	}
+	if len([]rune(name)) > maxNameLength {
+		return "", &ValidationError{"name", fmt.Sprintf("must be at most %d characters", maxNameLength)}
+	}
+	return name, nil
+}
\ No newline at end of file
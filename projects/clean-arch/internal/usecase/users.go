@@ -0,0 +1,126 @@
+// Package usecase реализует сценарии работы с пользователями.
+// Зависит только от domain; хранилище и уведомления описаны здесь же
+// как интерфейсы (порты), а реализации живут в adapter и передаются
+// снаружи. Поэтому сценарии тестируются без HTTP и без БД.
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"clean-arch/internal/domain"
+)
+
+// UserRepository порт хранилища. Интерфейс объявлен там, где его
+// используют, а не рядом с реализацией - так зависимость направлена
+// от adapter к usecase, а не наоборот.
+type UserRepository interface {
+	// Create заполняет u.ID; при занятом email - domain.ErrEmailTaken
+	Create(ctx context.Context, u *domain.User) error
+	// Get возвращает domain.ErrNotFound, если пользователя нет
+	Get(ctx context.Context, id int64) (*domain.User, error)
+	// GetByEmail возвращает domain.ErrNotFound, если адрес свободен
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	List(ctx context.Context) ([]domain.User, error)
+	// Update сохраняет все поля; ошибки как у Create и Get
+	Update(ctx context.Context, u *domain.User) error
+}
+
+// Notifier порт уведомлений пользователю
+type Notifier interface {
+	Welcome(ctx context.Context, u domain.User) error
+}
+
+// Users сценарии работы с пользователями
+type Users struct {
+	repo     UserRepository
+	notifier Notifier
+	now      func() time.Time
+}
+
+// NewUsers собирает сценарии из портов
+func NewUsers(repo UserRepository, notifier Notifier) *Users {
+	return &Users{repo: repo, notifier: notifier, now: time.Now}
+}
+
+// Register создает пользователя и отправляет приветствие.
+// Проверка GetByEmail дает понятную ошибку в обычном случае, а гонку
+// двух одновременных регистраций закрывает уникальность в хранилище.
+func (s *Users) Register(ctx context.Context, name, email string) (*domain.User, error) {
+	u, err := domain.NewUser(name, email, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureEmailFree(ctx, u.Email); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	
+	// Пользователь уже создан: сбой почты не должен откатывать регистрацию
+	if err := s.notifier.Welcome(ctx, *u); err != nil {
+		log.Printf("Не удалось отправить приветствие пользователю %d: %v", u.ID, err)
+	}
+	return u, nil
+}
+
+// Get возвращает пользователя по ID
+func (s *Users) Get(ctx context.Context, id int64) (*domain.User, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List возвращает всех пользователей
+func (s *Users) List(ctx context.Context) ([]domain.User, error) {
+	return s.repo.List(ctx)
+}
+
+// ChangeEmail меняет адрес, если он свободен
+func (s *Users) ChangeEmail(ctx context.Context, id int64, email string) (*domain.User, error) {
+	u, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	old := u.Email
+	if err := u.ChangeEmail(email); err != nil {
+		return nil, err
+	}
+	if u.Email == old {
+		return u, nil
+	}
+	if err := s.ensureEmailFree(ctx, u.Email); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Deactivate выключает пользователя
+func (s *Users) Deactivate(ctx context.Context, id int64) (*domain.User, error) {
+	u, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.Deactivate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Users) ensureEmailFree(ctx context.Context, email string) error {
+	_, err := s.repo.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return domain.ErrEmailTaken
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	}
+	return err
+}
\ No newline at end of file
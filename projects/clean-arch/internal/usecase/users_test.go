@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"clean-arch/internal/domain"
+)
+
+// fakeRepo минимальное хранилище для тестов сценариев: ни БД,
+// ни адаптера memory - только то, что нужно порту
+type fakeRepo struct {
+	users  map[int64]domain.User
+	nextID int64
+	err    error // Если задана, возвращается из Create и Update
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{users: make(map[int64]domain.User)}
+}
+
+func (r *fakeRepo) Create(ctx context.Context, u *domain.User) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.nextID++
+	u.ID = r.nextID
+	r.users[u.ID] = *u
+	return nil
+}
+
+func (r *fakeRepo) Get(ctx context.Context, id int64) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r *fakeRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeRepo) List(ctx context.Context) ([]domain.User, error) {
+	var users []domain.User
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *fakeRepo) Update(ctx context.Context, u *domain.User) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.users[u.ID] = *u
+	return nil
+}
+
+type fakeNotifier struct {
+	welcomed []int64
+	err      error
+}
+
+func (n *fakeNotifier) Welcome(ctx context.Context, u domain.User) error {
+	n.welcomed = append(n.welcomed, u.ID)
+	return n.err
+}
+
+var testNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestUsers() (*Users, *fakeRepo, *fakeNotifier) {
+	repo, notifier := newFakeRepo(), &fakeNotifier{}
+	s := NewUsers(repo, notifier)
+	s.now = func() time.Time { return testNow }
+	return s, repo, notifier
+}
+
+func TestRegister(t *testing.T) {
+	s, repo, notifier := newTestUsers()
+	ctx := context.Background()
+	
+	u, err := s.Register(ctx, "  Иван   Иванов ", "Ivan@Example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := domain.User{ID: 1, Name: "Иван Иванов", Email: "ivan@example.com", Active: true, CreatedAt: testNow}
+	if *u != want {
+		t.Errorf("Expected %+v, got %+v", want, *u)
+	}
+	if repo.users[1] != want {
+		t.Errorf("Expected stored %+v, got %+v", want, repo.users[1])
+	}
+	if len(notifier.welcomed) != 1 || notifier.welcomed[0] != 1 {
+		t.Errorf("Expected welcome for user 1, got %v", notifier.welcomed)
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		repoErr error
+		wantErr error
+		field   string
+	}{
+		{"taken email differs only by case", "TAKEN@example.com", nil, domain.ErrEmailTaken, ""},
+		{"malformed email", "not-an-email", nil, nil, "email"},
+		{"repository failure", "new@example.com", errors.New("disk full"), nil, ""},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, notifier := newTestUsers()
+			ctx := context.Background()
+			if _, err := s.Register(ctx, "Занят", "taken@example.com"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			repo.err = tt.repoErr
+			
+			_, err := s.Register(ctx, "Петр", tt.email)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			var verr *domain.ValidationError
+			if tt.field != "" && (!errors.As(err, &verr) || verr.Field != tt.field) {
+				t.Errorf("Expected validation error on %s, got %v", tt.field, err)
+			}
+			if len(repo.users) != 1 || len(notifier.welcomed) != 1 {
+				t.Errorf("Expected no side effects, got %d users and %d welcomes", len(repo.users), len(notifier.welcomed))
+			}
+		})
+	}
+}
+
+func TestRegisterNotifierFailureKeepsUser(t *testing.T) {
+	s, repo, notifier := newTestUsers()
+	notifier.err = errors.New("smtp unavailable")
+	
+	u, err := s.Register(context.Background(), "Иван", "ivan@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := repo.users[u.ID]; !ok {
+		t.Error("Expected user to be stored despite notifier failure")
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	s, _, _ := newTestUsers()
+	ctx := context.Background()
+	ivan, _ := s.Register(ctx, "Иван", "ivan@example.com")
+	s.Register(ctx, "Петр", "petr@example.com")
+	
+	u, err := s.ChangeEmail(ctx, ivan.ID, "IVAN@example.com")
+	if err != nil || u.Email != "ivan@example.com" {
+		t.Errorf("Expected unchanged email to be accepted, got %v, %v", u, err)
+	}
+	if _, err := s.ChangeEmail(ctx, ivan.ID, "petr@example.com"); !errors.Is(err, domain.ErrEmailTaken) {
+		t.Errorf("Expected ErrEmailTaken, got %v", err)
+	}
+	if _, err := s.ChangeEmail(ctx, 42, "x@example.com"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	
+	u, err = s.ChangeEmail(ctx, ivan.ID, "ivan@new.example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, _ := s.Get(ctx, ivan.ID)
+	if got.Email != "ivan@new.example.com" || u.Email != got.Email {
+		t.Errorf("Expected stored email ivan@new.example.com, got %q", got.Email)
+	}
+}
+
+func TestDeactivate(t *testing.T) {
+	s, _, _ := newTestUsers()
+	ctx := context.Background()
+	u, _ := s.Register(ctx, "Иван", "ivan@example.com")
+	
+	if _, err := s.Deactivate(ctx, u.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := s.Get(ctx, u.ID); got.Active {
+		t.Error("Expected user to be inactive")
+	}
+	if _, err := s.Deactivate(ctx, u.ID); !errors.Is(err, domain.ErrDeactivated) {
+		t.Errorf("Expected ErrDeactivated, got %v", err)
+	}
+	if _, err := s.ChangeEmail(ctx, u.ID, "other@example.com"); !errors.Is(err, domain.ErrDeactivated) {
+		t.Errorf("Expected ErrDeactivated, got %v", err)
+	}
+}
\ No newline at end of file
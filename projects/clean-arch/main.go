@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"clean-arch/internal/adapter/cache"
+	"clean-arch/internal/adapter/httpapi"
+	"clean-arch/internal/adapter/idempotency"
+	"clean-arch/internal/adapter/memory"
+	"clean-arch/internal/adapter/notify"
+	"clean-arch/internal/adapter/sqlite"
+	"clean-arch/internal/usecase"
+)
+
+// Сервис пользователей в слоях "чистой архитектуры". Ответ на вопрос
+// "как разложить Go-проект по пакетам":
+//
+//   internal/domain           - сущность User и ее правила; только stdlib
+//   internal/usecase          - сценарии (регистрация, смена email) и порты:
+//                               интерфейсы UserRepository и Notifier
+//   internal/adapter/sqlite   - порт UserRepository поверх SQLite
+//   internal/adapter/memory   - он же в памяти
+//   internal/adapter/notify   - порт Notifier (письма в лог)
+//   internal/adapter/httpapi  - HTTP: JSON и перевод ошибок в статусы
+//   internal/adapter/idempotency - заголовок Idempotency-Key: повтор
+//                               запроса получает сохраненный ответ
+//   internal/adapter/cache    - декоратор UserRepository: кэш чтений
+//                               (LRU в памяти или Redis) с защитой от
+//                               одновременных промахов
+//   client/users              - клиент API для других сервисов (SDK)
+//   main.go                   - единственное место, где все собирается
+//
+// Зависимости направлены внутрь: adapter -> usecase -> domain. Компилятор
+// не даст домену импортировать адаптер, если адаптер уже импортирует домен
+// (цикл), а остальные правила проверяет arch_test.go. internal/ запрещает
+// импорт этих пакетов из-за пределов модуля.
+//
+// Модуль отдельный (см. go.mod): без пути модуля пакеты друг друга
+// не импортируют.
+//
+// Запуск:
+//   go run .                 хранилище в памяти
+//   go run . -db users.db    SQLite
+//   go run . -db users.db -cache-ttl 1m          SQLite + кэш в памяти
+//   go run -tags redis . -db users.db -cache-ttl 1m -redis localhost:6379
+//                            кэш в Redis (go get github.com/redis/go-redis/v9)
+//   curl -d '{"name":"Иван","email":"ivan@example.com"}' localhost:8080/api/users
+//   curl -H 'Idempotency-Key: k1' -d '{"name":"Петр","email":"petr@example.com"}' localhost:8080/api/users
+//   (повтор той же команды вернет тот же ответ, а не 409 email taken)
+//   curl -X PATCH -d '{"email":"new@example.com"}' localhost:8080/api/users/1/email
+//   curl -X POST localhost:8080/api/users/1/deactivate
+//
+// Тесты: go test ./...
+
+// newStores выбирает адаптеры хранилища; остальной код о выборе не знает
+func newStores(ctx context.Context, dbPath string) (usecase.UserRepository, idempotency.Store, func() error, error) {
+	if dbPath == "" {
+		return memory.NewUserRepository(), idempotency.NewMemoryStore(), func() error { return nil }, nil
+	}
+	
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", dbPath))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	repo := sqlite.NewUserRepository(db)
+	keys := idempotency.NewSQLStore(db)
+	for _, m := range []interface{ Migrate(context.Context) error }{repo, keys} {
+		if err := m.Migrate(ctx); err != nil {
+			db.Close()
+			return nil, nil, nil, err
+		}
+	}
+	return repo, keys, db.Close, nil
+}
+
+// cacheConfig настройки кэша; TTL == 0 - без кэша
+type cacheConfig struct {
+	TTL       time.Duration
+	RedisAddr string // Пусто - LRU в памяти процесса
+}
+
+// withCache оборачивает хранилище кэшем
+func withCache(repo usecase.UserRepository, cfg cacheConfig) (usecase.UserRepository, func() error, error) {
+	if cfg.TTL == 0 {
+		return repo, func() error { return nil }, nil
+	}
+	store, closeStore, err := newCacheStore(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cache.NewUserRepository(repo, store, cache.Options{TTL: cfg.TTL, Jitter: 0.1}), closeStore, nil
+}
+
+// newHandler собирает сценарии и HTTP-слой поверх хранилища; его же
+// используют интеграционные тесты (integration_test.go)
+func newHandler(repo usecase.UserRepository, idem *idempotency.Middleware) http.Handler {
+	users := usecase.NewUsers(repo, notify.LogNotifier{Logger: log.Default()})
+	return idem.Wrap(httpapi.NewHandler(users).Routes())
+}
+
+func run(ctx context.Context, addr, dbPath string, cacheCfg cacheConfig) error {
+	repo, keys, closeStores, err := newStores(ctx, dbPath)
+	if err != nil {
+		return fmt.Errorf("open repository: %w", err)
+	}
+	defer closeStores()
+	repo, closeCache, err := withCache(repo, cacheCfg)
+	if err != nil {
+		return fmt.Errorf("open cache: %w", err)
+	}
+	defer closeCache()
+	
+	idem := idempotency.New(keys)
+	go idem.Cleanup(ctx, 10*time.Minute)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           newHandler(repo, idem),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Сервер запущен на %s", addr)
+		errCh <- server.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "адрес HTTP-сервера")
+	dbPath := flag.String("db", "", "файл SQLite; пусто - хранилище в памяти")
+	var cacheCfg cacheConfig
+	flag.DurationVar(&cacheCfg.TTL, "cache-ttl", 0, "срок жизни записей кэша; 0 - без кэша")
+	flag.StringVar(&cacheCfg.RedisAddr, "redis", "", "адрес Redis для кэша (сборка с -tags redis); пусто - кэш в памяти")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, *addr, *dbPath, cacheCfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,80 @@
+// Package cli входящий адаптер: те же сценарии ядра из командной строки
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"hexagonal/core"
+)
+
+const usage = `Команды:
+  register NAME EMAIL   зарегистрировать пользователя
+  get ID                показать пользователя
+  list                  список пользователей
+  rename ID NAME...     переименовать
+`
+
+// Run выполняет команду и возвращает код выхода: 0 - успех,
+// 1 - ошибка ядра, 2 - неверные аргументы
+func Run(ctx context.Context, svc core.UserService, args []string, out, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(errOut, usage)
+		return 2
+	}
+	
+	var users []core.User
+	var err error
+	switch cmd, rest := args[0], args[1:]; {
+	case cmd == "register" && len(rest) == 2:
+		var u core.User
+		u, err = svc.Register(ctx, rest[0], rest[1])
+		users = []core.User{u}
+	case cmd == "get" && len(rest) == 1:
+		var u core.User
+		u, err = withID(rest[0], func(id int64) (core.User, error) { return svc.Get(ctx, id) })
+		users = []core.User{u}
+	case cmd == "list" && len(rest) == 0:
+		users, err = svc.List(ctx)
+	case cmd == "rename" && len(rest) >= 2:
+		var u core.User
+		u, err = withID(rest[0], func(id int64) (core.User, error) {
+			return svc.Rename(ctx, id, strings.Join(rest[1:], " "))
+		})
+		users = []core.User{u}
+	default:
+		fmt.Fprint(errOut, usage)
+		return 2
+	}
+	
+	if err != nil {
+		fmt.Fprintf(errOut, "Ошибка: %v\n", err)
+		if errors.Is(err, errBadID) {
+			return 2
+		}
+		return 1
+	}
+	
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tИМЯ\tEMAIL")
+	for _, u := range users {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", u.ID, u.Name, u.Email)
+	}
+	tw.Flush()
+	return 0
+}
+
+var errBadID = errors.New("ID must be a number")
+
+func withID(arg string, f func(id int64) (core.User, error)) (core.User, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return core.User{}, errBadID
+	}
+	return f(id)
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+// Package httpapi входящий адаптер: JSON API поверх core.UserService
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"hexagonal/core"
+)
+
+// New возвращает маршруты:
+//
+//	POST  /api/users       {"name", "email"} -> 201
+//	GET   /api/users
+//	GET   /api/users/{id}
+//	PATCH /api/users/{id}  {"name"}
+func New(svc core.UserService) http.Handler {
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("POST /api/users", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		u, err := svc.Register(r.Context(), req.Name, req.Email)
+		if err != nil {
+			writeCoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, u)
+	})
+	
+	mux.HandleFunc("GET /api/users", func(w http.ResponseWriter, r *http.Request) {
+		users, err := svc.List(r.Context())
+		if err != nil {
+			writeCoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, users)
+	})
+	
+	mux.HandleFunc("GET /api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusNotFound, core.ErrNotFound.Error())
+			return
+		}
+		u, err := svc.Get(r.Context(), id)
+		if err != nil {
+			writeCoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, u)
+	})
+	
+	mux.HandleFunc("PATCH /api/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusNotFound, core.ErrNotFound.Error())
+			return
+		}
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		u, err := svc.Rename(r.Context(), id, req.Name)
+		if err != nil {
+			writeCoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, u)
+	})
+	
+	return mux
+}
+
+func writeCoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, core.ErrInvalid):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, core.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, core.ErrEmailTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Внутренняя ошибка: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+// Package memory исходящий адаптер: core.UserStore в памяти процесса
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"hexagonal/core"
+)
+
+// Store хранилище в памяти; пользователи хранятся в порядке создания
+type Store struct {
+	mu    sync.RWMutex
+	users []core.User
+}
+
+// NewStore создает пустое хранилище
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Insert сохраняет пользователя
+func (s *Store) Insert(ctx context.Context, u *core.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	for _, existing := range s.users {
+		if existing.Email == u.Email {
+			return core.ErrEmailTaken
+		}
+	}
+	u.ID = int64(len(s.users)) + 1
+	s.users = append(s.users, *u)
+	return nil
+}
+
+// Get возвращает пользователя по ID
+func (s *Store) Get(ctx context.Context, id int64) (core.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	
+	if id < 1 || id > int64(len(s.users)) {
+		return core.User{}, core.ErrNotFound
+	}
+	return s.users[id-1], nil
+}
+
+// List возвращает копию списка пользователей
+func (s *Store) List(ctx context.Context) ([]core.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	
+	return append([]core.User{}, s.users...), nil
+}
+
+// Update сохраняет пользователя
+func (s *Store) Update(ctx context.Context, u core.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	if u.ID < 1 || u.ID > int64(len(s.users)) {
+		return core.ErrNotFound
+	}
+	s.users[u.ID-1] = u
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+// Package sqlite исходящий адаптер: core.UserStore в SQLite
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"hexagonal/core"
+)
+
+// Store хранилище в SQLite
+type Store struct {
+	db *sql.DB
+}
+
+// Open открывает файл БД и создает таблицу
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL UNIQUE
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close закрывает БД
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Insert сохраняет пользователя
+func (s *Store) Insert(ctx context.Context, u *core.User) error {
+	err := s.db.QueryRowContext(ctx, `INSERT INTO users (name, email) VALUES (?, ?) RETURNING id`,
+		u.Name, u.Email).Scan(&u.ID)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return core.ErrEmailTaken
+	}
+	return err
+}
+
+// Get возвращает пользователя по ID
+func (s *Store) Get(ctx context.Context, id int64) (core.User, error) {
+	var u core.User
+	err := s.db.QueryRowContext(ctx, `SELECT id, name, email FROM users WHERE id = ?`, id).
+		Scan(&u.ID, &u.Name, &u.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.User{}, core.ErrNotFound
+	}
+	return u, err
+}
+
+// List возвращает пользователей в порядке ID
+func (s *Store) List(ctx context.Context) ([]core.User, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, email FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	users := []core.User{}
+	for rows.Next() {
+		var u core.User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// Update сохраняет пользователя
+func (s *Store) Update(ctx context.Context, u core.User) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE users SET name = ?, email = ? WHERE id = ?`, u.Name, u.Email, u.ID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return core.ErrNotFound
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config выбирает адаптеры при запуске: ядро одно и то же,
+// меняется только то, что к нему подключено
+type Config struct {
+	Driver string `json:"driver"`  // Входящий адаптер: http или cli
+	Store  string `json:"store"`   // Исходящий адаптер: memory или sqlite
+	DBPath string `json:"db_path"` // Файл SQLite для store=sqlite
+	Addr   string `json:"addr"`    // Адрес для driver=http
+}
+
+func defaultConfig() Config {
+	return Config{Driver: "http", Store: "memory", DBPath: "hexagonal.db", Addr: ":8080"}
+}
+
+// LoadConfig читает конфигурацию: значения по умолчанию, затем
+// JSON-файл (если path не пустой), затем переменные окружения HEX_*
+func LoadConfig(path string, getenv func(string) string) (Config, error) {
+	cfg := defaultConfig()
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return Config{}, err
+		}
+		defer f.Close()
+		dec := json.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	
+	for env, field := range map[string]*string{
+		"HEX_DRIVER": &cfg.Driver,
+		"HEX_STORE":  &cfg.Store,
+		"HEX_DB":     &cfg.DBPath,
+		"HEX_ADDR":   &cfg.Addr,
+	} {
+		if v := getenv(env); v != "" {
+			*field = v
+		}
+	}
+	
+	if cfg.Driver != "http" && cfg.Driver != "cli" {
+		return Config{}, fmt.Errorf("неизвестный driver %q: ожидается http или cli", cfg.Driver)
+	}
+	if cfg.Store != "memory" && cfg.Store != "sqlite" {
+		return Config{}, fmt.Errorf("неизвестный store %q: ожидается memory или sqlite", cfg.Store)
+	}
+	return cfg, nil
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+// Package core ядро приложения: модель, правила и порты.
+//
+// Порты делятся на два вида:
+//   - входящий (driving) - UserService: через него ядро вызывают
+//     HTTP, CLI, тесты;
+//   - исходящий (driven) - UserStore: через него ядро само вызывает
+//     внешний мир (БД, память).
+//
+// Ядро не импортирует ни одного адаптера и не знает, кто с какой
+// стороны к нему подключен.
+package core
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// Ошибки ядра; адаптеры переводят их в свои коды (HTTP-статусы, код выхода)
+var (
+	ErrNotFound   = errors.New("user not found")
+	ErrEmailTaken = errors.New("email already taken")
+	ErrInvalid    = errors.New("invalid user")
+)
+
+// User пользователь
+type User struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// UserService входящий порт: что приложение умеет делать
+type UserService interface {
+	Register(ctx context.Context, name, email string) (User, error)
+	Get(ctx context.Context, id int64) (User, error)
+	List(ctx context.Context) ([]User, error)
+	Rename(ctx context.Context, id int64, name string) (User, error)
+}
+
+// UserStore исходящий порт: что приложению нужно от хранилища
+type UserStore interface {
+	// Insert заполняет ID; при занятом email - ErrEmailTaken
+	Insert(ctx context.Context, u *User) error
+	// Get возвращает ErrNotFound, если пользователя нет
+	Get(ctx context.Context, id int64) (User, error)
+	// List возвращает пользователей в порядке ID
+	List(ctx context.Context) ([]User, error)
+	// Update возвращает ErrNotFound, если пользователя нет
+	Update(ctx context.Context, u User) error
+}
+
+// service реализация входящего порта поверх исходящего
+type service struct {
+	store UserStore
+}
+
+// NewService собирает ядро. Возвращается интерфейс, а не структура:
+// адаптерам доступен только порт.
+func NewService(store UserStore) UserService {
+	return &service{store: store}
+}
+
+func (s *service) Register(ctx context.Context, name, email string) (User, error) {
+	name, err := validName(name)
+	if err != nil {
+		return User{}, err
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return User{}, errors.Join(ErrInvalid, errors.New("email is malformed"))
+	}
+	
+	u := User{Name: name, Email: email}
+	if err := s.store.Insert(ctx, &u); err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *service) Get(ctx context.Context, id int64) (User, error) {
+	return s.store.Get(ctx, id)
+}
+
+func (s *service) List(ctx context.Context) ([]User, error) {
+	return s.store.List(ctx)
+}
+
+func (s *service) Rename(ctx context.Context, id int64, name string) (User, error) {
+	name, err := validName(name)
+	if err != nil {
+		return User{}, err
+	}
+	u, err := s.store.Get(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	u.Name = name
+	if err := s.store.Update(ctx, u); err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func validName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", errors.Join(ErrInvalid, errors.New("name is required"))
+	}
+	return name, nil
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hexagonal/adapters/memory"
+	"hexagonal/core"
+)
+
+// Тест ядра подключает исходящий адаптер memory: сам пакет core
+// об адаптерах не знает, а внешний тестовый пакет core_test может
+func TestServiceErrors(t *testing.T) {
+	ctx := context.Background()
+	svc := core.NewService(memory.NewStore())
+	u, err := svc.Register(ctx, " Иван ", "IVAN@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u.Name != "Иван" || u.Email != "ivan@example.com" {
+		t.Errorf("Expected normalized user, got %+v", u)
+	}
+	
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"empty name", func() error { _, err := svc.Register(ctx, "  ", "a@example.com"); return err }, core.ErrInvalid},
+		{"bad email", func() error { _, err := svc.Register(ctx, "A", "a@"); return err }, core.ErrInvalid},
+		{"taken email", func() error { _, err := svc.Register(ctx, "A", "ivan@example.com"); return err }, core.ErrEmailTaken},
+		{"rename missing", func() error { _, err := svc.Rename(ctx, 42, "A"); return err }, core.ErrNotFound},
+		{"rename to empty", func() error { _, err := svc.Rename(ctx, u.ID, ""); return err }, core.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"hexagonal/adapters/cli"
+	"hexagonal/adapters/httpapi"
+	"hexagonal/adapters/memory"
+	"hexagonal/adapters/sqlite"
+	"hexagonal/core"
+)
+
+// Гексагональная архитектура (порты и адаптеры) - вариант к projects/clean-arch.
+// Здесь слоев меньше, зато видно главное: одно ядро подключается
+// к разным адаптерам без изменений, и выбор делается конфигурацией.
+//
+//   core/               модель, правила и порты: UserService (входящий)
+//                       и UserStore (исходящий)
+//   adapters/httpapi    входящий: JSON API
+//   adapters/cli        входящий: командная строка
+//   adapters/memory     исходящий: память процесса
+//   adapters/sqlite     исходящий: SQLite
+//   config.go           выбор адаптеров: JSON-файл и переменные HEX_*
+//
+//   HTTP ──┐                                 ┌──> memory
+//          ├──> UserService [core] UserStore ┤
+//   CLI  ──┘                                 └──> sqlite
+//
+// Запуск:
+//   go run .                                          HTTP + память
+//   HEX_STORE=sqlite go run .                         HTTP + SQLite
+//   HEX_DRIVER=cli HEX_STORE=sqlite go run . register Иван ivan@example.com
+//   HEX_DRIVER=cli HEX_STORE=sqlite go run . list
+//   go run . -config config.example.json
+//
+// Тесты: go test ./...  (main_test.go гоняет один сценарий по всем
+// сочетаниям адаптеров)
+
+// openStore создает исходящий адаптер по конфигурации
+func openStore(cfg Config) (core.UserStore, func() error, error) {
+	switch cfg.Store {
+	case "sqlite":
+		s, err := sqlite.Open(cfg.DBPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	default:
+		return memory.NewStore(), func() error { return nil }, nil
+	}
+}
+
+// run собирает приложение и запускает выбранный входящий адаптер.
+// Возвращает код выхода.
+func run(ctx context.Context, cfg Config, args []string, out, errOut io.Writer) int {
+	store, closeStore, err := openStore(cfg)
+	if err != nil {
+		fmt.Fprintf(errOut, "Ошибка открытия хранилища: %v\n", err)
+		return 1
+	}
+	defer closeStore()
+	
+	svc := core.NewService(store)
+	if cfg.Driver == "cli" {
+		return cli.Run(ctx, svc, args, out, errOut)
+	}
+	if err := serve(ctx, cfg.Addr, httpapi.New(svc)); err != nil {
+		fmt.Fprintf(errOut, "Ошибка сервера: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func serve(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Сервер запущен на %s", addr)
+		errCh <- server.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func main() {
+	configPath := flag.String("config", "", "JSON-файл конфигурации")
+	flag.Parse()
+	
+	cfg, err := LoadConfig(*configPath, os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, cfg, flag.Args(), os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
\ No newline at end of file
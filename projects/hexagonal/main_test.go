@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"hexagonal/adapters/cli"
+	"hexagonal/adapters/httpapi"
+	"hexagonal/core"
+)
+
+// client сценарий глазами внешнего пользователя: как именно
+// вызывается ядро, решает входящий адаптер
+type client interface {
+	register(t *testing.T, name, email string) int64
+	rename(t *testing.T, id int64, name string)
+	names(t *testing.T) []string
+}
+
+type httpClient struct{ url string }
+
+func newHTTPClient(t *testing.T, svc core.UserService) client {
+	ts := httptest.NewServer(httpapi.New(svc))
+	t.Cleanup(ts.Close)
+	return httpClient{ts.URL}
+}
+
+func (c httpClient) do(t *testing.T, method, path, body string, want int, v any) {
+	t.Helper()
+	req, _ := http.NewRequest(method, c.url+path, strings.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != want {
+		t.Fatalf("%s %s: expected status %d, got %d", method, path, want, resp.StatusCode)
+	}
+	if v != nil {
+		json.NewDecoder(resp.Body).Decode(v)
+	}
+}
+
+func (c httpClient) register(t *testing.T, name, email string) int64 {
+	var u core.User
+	body, _ := json.Marshal(map[string]string{"name": name, "email": email})
+	c.do(t, "POST", "/api/users", string(body), http.StatusCreated, &u)
+	return u.ID
+}
+
+func (c httpClient) rename(t *testing.T, id int64, name string) {
+	body, _ := json.Marshal(map[string]string{"name": name})
+	c.do(t, "PATCH", "/api/users/"+strconv.FormatInt(id, 10), string(body), http.StatusOK, nil)
+}
+
+func (c httpClient) names(t *testing.T) []string {
+	var users []core.User
+	c.do(t, "GET", "/api/users", "", http.StatusOK, &users)
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	return names
+}
+
+type cliClient struct{ svc core.UserService }
+
+func newCLIClient(t *testing.T, svc core.UserService) client {
+	return cliClient{svc}
+}
+
+// run выполняет команду и разбирает таблицу: ID, имя (может содержать
+// пробелы), email
+func (c cliClient) run(t *testing.T, args ...string) [][]string {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	if code := cli.Run(context.Background(), c.svc, args, &out, &errOut); code != 0 {
+		t.Fatalf("%v: expected exit code 0, got %d: %s", args, code, errOut.String())
+	}
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+		f := strings.Fields(line)
+		rows = append(rows, []string{f[0], strings.Join(f[1:len(f)-1], " "), f[len(f)-1]})
+	}
+	return rows
+}
+
+func (c cliClient) register(t *testing.T, name, email string) int64 {
+	rows := c.run(t, "register", name, email)
+	id, _ := strconv.ParseInt(rows[0][0], 10, 64)
+	return id
+}
+
+func (c cliClient) rename(t *testing.T, id int64, name string) {
+	c.run(t, append([]string{"rename", strconv.FormatInt(id, 10)}, strings.Fields(name)...)...)
+}
+
+func (c cliClient) names(t *testing.T) []string {
+	var names []string
+	for _, row := range c.run(t, "list") {
+		names = append(names, row[1])
+	}
+	return names
+}
+
+// TestAdapterMatrix один и тот же сценарий для каждого сочетания
+// входящего и исходящего адаптера: ядро не меняется ни разу
+func TestAdapterMatrix(t *testing.T) {
+	drivers := map[string]func(*testing.T, core.UserService) client{
+		"http": newHTTPClient,
+		"cli":  newCLIClient,
+	}
+	
+	for _, store := range []string{"memory", "sqlite"} {
+		for driverName, newClient := range drivers {
+			t.Run(driverName+"+"+store, func(t *testing.T) {
+				cfg := Config{Store: store, DBPath: filepath.Join(t.TempDir(), "users.db")}
+				s, closeStore, err := openStore(cfg)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				t.Cleanup(func() { closeStore() })
+				c := newClient(t, core.NewService(s))
+				
+				ivan := c.register(t, "Иван", "ivan@example.com")
+				c.register(t, "Петр", "petr@example.com")
+				c.rename(t, ivan, "Иван Петрович")
+				
+				got := strings.Join(c.names(t), ", ")
+				if got != "Иван Петрович, Петр" {
+					t.Errorf("Expected [Иван Петрович, Петр], got [%s]", got)
+				}
+			})
+		}
+	}
+}
+
+func TestRunCLIWithSQLitePersists(t *testing.T) {
+	cfg := Config{Driver: "cli", Store: "sqlite", DBPath: filepath.Join(t.TempDir(), "users.db")}
+	ctx := context.Background()
+	
+	var out, errOut bytes.Buffer
+	if code := run(ctx, cfg, []string{"register", "Иван", "ivan@example.com"}, &out, &errOut); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, errOut.String())
+	}
+	// Повтор в новом "процессе": email уже занят в файле БД
+	errOut.Reset()
+	if code := run(ctx, cfg, []string{"register", "Другой", "ivan@example.com"}, &out, &errOut); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), core.ErrEmailTaken.Error()) {
+		t.Errorf("Expected email taken error, got %q", errOut.String())
+	}
+	
+	out.Reset()
+	run(ctx, cfg, []string{"list"}, &out, &errOut)
+	if !strings.Contains(out.String(), "ivan@example.com") {
+		t.Errorf("Expected persisted user in list, got %q", out.String())
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"driver": "cli", "store": "sqlite", "db_path": "file.db"}`), 0o644)
+	badPath := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(badPath, []byte(`{"storage": "sqlite"}`), 0o644)
+	
+	tests := []struct {
+		name    string
+		path    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{"defaults", "", nil, defaultConfig(), false},
+		{"file", path, nil, Config{Driver: "cli", Store: "sqlite", DBPath: "file.db", Addr: ":8080"}, false},
+		{"env overrides file", path, map[string]string{"HEX_STORE": "memory", "HEX_ADDR": ":9090"},
+			Config{Driver: "cli", Store: "memory", DBPath: "file.db", Addr: ":9090"}, false},
+		{"unknown store", "", map[string]string{"HEX_STORE": "postgres"}, Config{}, true},
+		{"unknown field", badPath, nil, Config{}, true},
+		{"missing file", filepath.Join(t.TempDir(), "none.json"), nil, Config{}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(tt.path, func(k string) string { return tt.env[k] })
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %+v", cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, cfg)
+			}
+		})
+	}
+}
\ No newline at end of file
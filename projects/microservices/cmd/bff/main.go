@@ -0,0 +1,33 @@
+// BFF - точка входа для клиентов. ADDR - адрес (по умолчанию :8080),
+// USERS_URL и ORDERS_URL - адреса сервисов.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"microservices/internal/bff"
+	"microservices/internal/orders"
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	client := platform.NewClient(2 * time.Second)
+	handler := bff.New(
+		users.NewClient(platform.Env("USERS_URL", "http://localhost:8081"), client),
+		orders.NewClient(platform.Env("ORDERS_URL", "http://localhost:8082"), client),
+	).Handler()
+	
+	addr := platform.Env("ADDR", ":8080")
+	if err := platform.Serve(ctx, addr, handler); err != nil {
+		log.Fatal("Ошибка сервера:", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,29 @@
+// Сервис заказов. ADDR - адрес (по умолчанию :8082), USERS_URL - адрес
+// сервиса пользователей.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"microservices/internal/orders"
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	client := platform.NewClient(2 * time.Second)
+	usersClient := users.NewClient(platform.Env("USERS_URL", "http://localhost:8081"), client)
+	
+	addr := platform.Env("ADDR", ":8082")
+	if err := platform.Serve(ctx, addr, orders.NewService(usersClient).Handler()); err != nil {
+		log.Fatal("Ошибка сервера:", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+// Сервис пользователей. ADDR - адрес (по умолчанию :8081).
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	addr := platform.Env("ADDR", ":8081")
+	if err := platform.Serve(ctx, addr, users.NewService().Handler()); err != nil {
+		log.Fatal("Ошибка сервера:", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+// Package bff Backend for Frontend: единая точка входа для клиента.
+// Своих данных у него нет - он собирает ответ из нескольких сервисов,
+// чтобы клиенту не пришлось делать несколько запросов.
+package bff
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"microservices/internal/group"
+	"microservices/internal/orders"
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+// Handler обработчики BFF
+type Handler struct {
+	users  *users.Client
+	orders *orders.Client
+}
+
+// New создает BFF поверх клиентов сервисов
+func New(usersClient *users.Client, ordersClient *orders.Client) *Handler {
+	return &Handler{users: usersClient, orders: ordersClient}
+}
+
+// Profile ответ GET /api/profile/{id}
+type Profile struct {
+	User   users.User     `json:"user"`
+	Orders []orders.Order `json:"orders"`
+	Total  int64          `json:"total"`
+}
+
+// Handler возвращает маршруты:
+//
+//	GET /api/profile/{id}   пользователь и его заказы одним ответом
+//	GET /healthz, /readyz   readyz проверяет users и orders
+func (h *Handler) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/profile/{id}", h.profile)
+	platform.HealthRoutes(mux, map[string]platform.Check{
+		"users":  h.users.Ping,
+		"orders": h.orders.Ping,
+	})
+	return platform.Middleware("bff", mux)
+}
+
+// profile запрашивает users и orders параллельно: время ответа -
+// максимум из двух вызовов, а не сумма. Если один вызов упал, контекст
+// группы отменяет второй - ждать его уже незачем.
+func (h *Handler) profile(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		platform.WriteError(w, http.StatusNotFound, users.ErrNotFound.Error())
+		return
+	}
+	
+	var p Profile
+	g, ctx := group.WithContext(r.Context())
+	g.Go(func() error {
+		u, err := h.users.Get(ctx, id)
+		p.User = u
+		return err
+	})
+	g.Go(func() error {
+		list, err := h.orders.ListByUser(ctx, id)
+		p.Orders = list
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			platform.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[bff] %s: %v", platform.RequestID(r.Context()), err)
+		platform.WriteError(w, http.StatusBadGateway, "upstream service unavailable")
+		return
+	}
+	
+	for _, o := range p.Orders {
+		p.Total += o.Amount
+	}
+	platform.WriteJSON(w, http.StatusOK, p)
+}
\ No newline at end of file
@@ -0,0 +1,51 @@
+// Package group запускает горутины и ждет их, возвращая первую ошибку.
+// API совпадает с golang.org/x/sync/errgroup: в своем проекте
+// подключите errgroup, а этот пакет показывает, что у него внутри, и
+// позволяет собрать проект без внешних зависимостей.
+package group
+
+import (
+	"context"
+	"sync"
+)
+
+// Group набор горутин, выполняющих части одной задачи
+type Group struct {
+	cancel func(error)
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// WithContext создает группу и производный контекст. Контекст
+// отменяется, как только одна из горутин вернет ошибку (остальные
+// могут не тратить время), или когда Wait вернется.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go запускает f в отдельной горутине
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// Wait ждет все горутины и возвращает первую ошибку
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("all succeed", func(t *testing.T) {
+		g, _ := WithContext(context.Background())
+		results := make([]int, 3)
+		for i := range results {
+			g.Go(func() error {
+				results[i] = i + 1
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if results[0] != 1 || results[1] != 2 || results[2] != 3 {
+			t.Errorf("Expected all goroutines to run, got %v", results)
+		}
+	})
+	
+	t.Run("first error cancels the rest", func(t *testing.T) {
+		boom := errors.New("boom")
+		g, ctx := WithContext(context.Background())
+		g.Go(func() error { return boom })
+		g.Go(func() error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("not canceled")
+			}
+		})
+		if err := g.Wait(); !errors.Is(err, boom) {
+			t.Errorf("Expected first error, got %v", err)
+		}
+		if !errors.Is(context.Cause(ctx), boom) {
+			t.Errorf("Expected context cause %v, got %v", boom, context.Cause(ctx))
+		}
+	})
+	
+	t.Run("context done after wait", func(t *testing.T) {
+		g, ctx := WithContext(context.Background())
+		g.Go(func() error { return nil })
+		g.Wait()
+		if ctx.Err() == nil {
+			t.Error("Expected context to be canceled after Wait")
+		}
+	})
+}
\ No newline at end of file
@@ -0,0 +1,51 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"microservices/internal/platform"
+)
+
+// Client клиент сервиса заказов
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient создает клиента; httpClient должен передавать request ID
+// (см. platform.NewClient)
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{baseURL: baseURL, http: httpClient}
+}
+
+// ListByUser возвращает заказы пользователя
+func (c *Client) ListByUser(ctx context.Context, userID int64) ([]Order, error) {
+	url := c.baseURL + "/orders?user_id=" + strconv.FormatInt(userID, 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("orders: %w", err)
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("orders: unexpected status %s", resp.Status)
+	}
+	var list []Order
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("orders: decode: %w", err)
+	}
+	return list, nil
+}
+
+// Ping проверяет, что сервис отвечает
+func (c *Client) Ping(ctx context.Context) error {
+	return platform.PingCheck(c.http, c.baseURL)(ctx)
+}
\ No newline at end of file
@@ -0,0 +1,124 @@
+// Package orders сервис заказов. О пользователях он знает только ID,
+// а существование пользователя проверяет вызовом сервиса users.
+package orders
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+// Order заказ
+type Order struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Item      string    `json:"item"`
+	Amount    int64     `json:"amount"` // В копейках
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserDirectory то, что сервису нужно от users; *users.Client ему
+// удовлетворяет, а в тестах можно подставить заглушку
+type UserDirectory interface {
+	Get(ctx context.Context, id int64) (users.User, error)
+	Ping(ctx context.Context) error
+}
+
+// Service хранит заказы в памяти
+type Service struct {
+	users  UserDirectory
+	mu     sync.RWMutex
+	orders []Order
+}
+
+// NewService создает сервис
+func NewService(users UserDirectory) *Service {
+	return &Service{users: users}
+}
+
+// Handler возвращает маршруты сервиса:
+//
+//	POST /orders                {"user_id", "item", "amount"} -> 201
+//	GET  /orders?user_id=ID
+//	GET  /orders/{id}
+//	GET  /healthz, /readyz      readyz проверяет и сервис users
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /orders", s.create)
+	mux.HandleFunc("GET /orders", s.list)
+	mux.HandleFunc("GET /orders/{id}", s.get)
+	platform.HealthRoutes(mux, map[string]platform.Check{"users": s.users.Ping})
+	return platform.Middleware("orders", mux)
+}
+
+func (s *Service) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID int64  `json:"user_id"`
+		Item   string `json:"item"`
+		Amount int64  `json:"amount"`
+	}
+	if !platform.Decode(w, r, &req) {
+		return
+	}
+	item := strings.TrimSpace(req.Item)
+	if item == "" || req.Amount <= 0 {
+		platform.WriteError(w, http.StatusUnprocessableEntity, "item and a positive amount are required")
+		return
+	}
+	
+	// Вызов другого сервиса: контекст запроса несет request ID и
+	// отмену, если клиент ушел
+	if _, err := s.users.Get(r.Context(), req.UserID); err != nil {
+		if errors.Is(err, users.ErrNotFound) {
+			platform.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		log.Printf("[orders] %s: %v", platform.RequestID(r.Context()), err)
+		platform.WriteError(w, http.StatusBadGateway, "users service unavailable")
+		return
+	}
+	
+	s.mu.Lock()
+	o := Order{ID: int64(len(s.orders) + 1), UserID: req.UserID, Item: item, Amount: req.Amount, CreatedAt: time.Now().UTC()}
+	s.orders = append(s.orders, o)
+	s.mu.Unlock()
+	
+	w.Header().Set("Location", "/orders/"+strconv.FormatInt(o.ID, 10))
+	platform.WriteJSON(w, http.StatusCreated, o)
+}
+
+func (s *Service) list(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+	if err != nil {
+		platform.WriteError(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+	s.mu.RLock()
+	list := []Order{}
+	for _, o := range s.orders {
+		if o.UserID == userID {
+			list = append(list, o)
+		}
+	}
+	s.mu.RUnlock()
+	platform.WriteJSON(w, http.StatusOK, list)
+}
+
+func (s *Service) get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if id < 1 || id > int64(len(s.orders)) {
+		platform.WriteError(w, http.StatusNotFound, "order not found")
+		return
+	}
+	platform.WriteJSON(w, http.StatusOK, s.orders[id-1])
+}
\ No newline at end of file
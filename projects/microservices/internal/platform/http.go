@@ -0,0 +1,113 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Check проверка зависимости для /readyz
+type Check func(ctx context.Context) error
+
+// HealthRoutes добавляет в mux проверки здоровья:
+//
+//	GET /healthz  процесс жив (для перезапуска контейнера)
+//	GET /readyz   зависимости доступны (для балансировщика: пока
+//	              сервис не готов, трафик на него не идет)
+func HealthRoutes(mux *http.ServeMux, checks map[string]Check) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		
+		status, results := http.StatusOK, make(map[string]string, len(checks))
+		for name, check := range checks {
+			if err := check(ctx); err != nil {
+				status, results[name] = http.StatusServiceUnavailable, err.Error()
+				continue
+			}
+			results[name] = "ok"
+		}
+		WriteJSON(w, status, map[string]any{"status": http.StatusText(status), "checks": results})
+	})
+}
+
+// PingCheck проверяет, что сервис по адресу baseURL отвечает на /healthz
+func PingCheck(client *http.Client, baseURL string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/healthz", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return errors.New(resp.Status)
+		}
+		return nil
+	}
+}
+
+// Serve запускает сервер и останавливает его при отмене ctx,
+// дожидаясь текущих запросов
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Сервер запущен на %s", addr)
+		errCh <- server.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Env значение переменной окружения или def
+func Env(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// Decode читает JSON-тело запроса; при ошибке сам отвечает 400
+func Decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		WriteError(w, http.StatusBadRequest, "invalid JSON")
+		return false
+	}
+	return true
+}
+
+// WriteJSON отвечает JSON с указанным статусом
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError отвечает {"error": msg}
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	WriteJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
@@ -0,0 +1,94 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareRequestID(t *testing.T) {
+	var seen string
+	handler := Middleware("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+	
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"incoming id is kept", "abc-123", true},
+		{"missing id is generated", "", false},
+		{"oversized id is replaced", strings.Repeat("x", 100), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			
+			got := rec.Header().Get(RequestIDHeader)
+			if got == "" || got != seen {
+				t.Fatalf("Expected response header to match context id %q, got %q", seen, got)
+			}
+			if (got == tt.header) != tt.keep {
+				t.Errorf("Expected keep=%v, got id %q for header %q", tt.keep, got, tt.header)
+			}
+		})
+	}
+}
+
+func TestTransportPropagatesRequestID(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(RequestIDHeader)
+	}))
+	defer server.Close()
+	
+	client := NewClient(0)
+	req, _ := http.NewRequestWithContext(WithRequestID(context.Background(), "req-42"), http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	
+	if got != "req-42" {
+		t.Errorf("Expected request id req-42 downstream, got %q", got)
+	}
+	if req.Header.Get(RequestIDHeader) != "" {
+		t.Error("Expected original request to stay unmodified")
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks map[string]Check
+		status int
+	}{
+		{"no checks", nil, http.StatusOK},
+		{"all ok", map[string]Check{"db": func(context.Context) error { return nil }}, http.StatusOK},
+		{"dependency down", map[string]Check{
+			"db":    func(context.Context) error { return nil },
+			"users": func(context.Context) error { return errors.New("connection refused") },
+		}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			HealthRoutes(mux, tt.checks)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+// Package platform общий для всех сервисов код: request ID, журнал
+// запросов, проверки здоровья, JSON-ответы и запуск сервера. В больших
+// системах такой пакет выносят в отдельный модуль и подключают в каждый
+// сервис, чтобы они одинаково логировали и отвечали.
+package platform
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader заголовок, в котором ID запроса идет от сервиса
+// к сервису: по нему в журналах всех сервисов находится одна цепочка
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID кладет ID запроса в контекст
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID достает ID запроса из контекста
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Middleware берет ID из заголовка (или создает новый, если запрос пришел
+// снаружи), возвращает его в ответе и пишет строку журнала на каждый запрос
+func Middleware(service string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(WithRequestID(r.Context(), id)))
+		
+		if r.URL.Path != "/healthz" && r.URL.Path != "/readyz" {
+			log.Printf("[%s] %s %s %s -> %d (%v)", service, id, r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Microsecond))
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Transport передает ID запроса из контекста в исходящие вызовы.
+// Без него цепочка рвется на первом же обращении к другому сервису.
+type Transport struct {
+	Base http.RoundTripper // nil - http.DefaultTransport
+}
+
+// RoundTrip реализует http.RoundTripper
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id := RequestID(req.Context()); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		// RoundTripper не должен менять исходный запрос
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, id)
+	}
+	return base.RoundTrip(req)
+}
+
+// NewClient HTTP-клиент для вызовов других сервисов: с таймаутом
+// и передачей ID запроса
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: Transport{}}
+}
\ No newline at end of file
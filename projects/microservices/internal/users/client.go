@@ -0,0 +1,59 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"microservices/internal/platform"
+)
+
+// ErrNotFound пользователя нет
+var ErrNotFound = errors.New("user not found")
+
+// Client клиент сервиса пользователей для других сервисов.
+// Живет рядом с сервисом: кто меняет API, тот меняет и клиента.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient создает клиента; httpClient должен передавать request ID
+// (см. platform.NewClient)
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{baseURL: baseURL, http: httpClient}
+}
+
+// Get возвращает пользователя по ID
+func (c *Client) Get(ctx context.Context, id int64) (User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/users/"+strconv.FormatInt(id, 10), nil)
+	if err != nil {
+		return User{}, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return User{}, fmt.Errorf("users: %w", err)
+	}
+	defer resp.Body.Close()
+	
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var u User
+		if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+			return User{}, fmt.Errorf("users: decode: %w", err)
+		}
+		return u, nil
+	case http.StatusNotFound:
+		return User{}, ErrNotFound
+	default:
+		return User{}, fmt.Errorf("users: unexpected status %s", resp.Status)
+	}
+}
+
+// Ping проверяет, что сервис отвечает: для /readyz зависимых сервисов
+func (c *Client) Ping(ctx context.Context) error {
+	return platform.PingCheck(c.http, c.baseURL)(ctx)
+}
\ No newline at end of file
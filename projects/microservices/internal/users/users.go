@@ -0,0 +1,99 @@
+// Package users сервис пользователей. Он единственный владелец данных
+// о пользователях: другие сервисы не ходят в его хранилище, а спрашивают
+// по HTTP через Client.
+package users
+
+import (
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"microservices/internal/platform"
+)
+
+// User пользователь
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Service хранит пользователей в памяти
+type Service struct {
+	mu     sync.RWMutex
+	users  map[int64]User
+	nextID int64
+}
+
+// NewService создает сервис
+func NewService() *Service {
+	return &Service{users: make(map[int64]User), nextID: 1}
+}
+
+// Handler возвращает маршруты сервиса:
+//
+//	POST /users        {"name", "email"} -> 201
+//	GET  /users
+//	GET  /users/{id}
+//	GET  /healthz, /readyz
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", s.create)
+	mux.HandleFunc("GET /users", s.list)
+	mux.HandleFunc("GET /users/{id}", s.get)
+	platform.HealthRoutes(mux, nil)
+	return platform.Middleware("users", mux)
+}
+
+func (s *Service) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if !platform.Decode(w, r, &req) {
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if addr, err := mail.ParseAddress(email); name == "" || err != nil || addr.Address != email {
+		platform.WriteError(w, http.StatusUnprocessableEntity, "name and a valid email are required")
+		return
+	}
+	
+	s.mu.Lock()
+	u := User{ID: s.nextID, Name: name, Email: email, CreatedAt: time.Now().UTC()}
+	s.users[u.ID] = u
+	s.nextID++
+	s.mu.Unlock()
+	
+	w.Header().Set("Location", "/users/"+strconv.FormatInt(u.ID, 10))
+	platform.WriteJSON(w, http.StatusCreated, u)
+}
+
+func (s *Service) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	list := make([]User, 0, len(s.users))
+	for id := range s.nextID {
+		if u, ok := s.users[id]; ok {
+			list = append(list, u)
+		}
+	}
+	s.mu.RUnlock()
+	platform.WriteJSON(w, http.StatusOK, list)
+}
+
+func (s *Service) get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	s.mu.RLock()
+	u, ok := s.users[id]
+	s.mu.RUnlock()
+	if !ok {
+		platform.WriteError(w, http.StatusNotFound, ErrNotFound.Error())
+		return
+	}
+	platform.WriteJSON(w, http.StatusOK, u)
+}
\ No newline at end of file
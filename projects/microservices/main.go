@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"microservices/internal/bff"
+	"microservices/internal/group"
+	"microservices/internal/orders"
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+// Микросервисы - итоговый проект, собранный из уроков:
+//   internal/users, internal/orders - два сервиса со своими данными
+//                    и HTTP-клиентами друг к другу (examples/http-client)
+//   internal/platform - X-Request-ID через все вызовы, журнал запросов,
+//                    /healthz и /readyz (examples/http-server, examples/context)
+//   internal/bff   - Backend for Frontend: параллельные запросы к двум
+//                    сервисам через internal/group (аналог errgroup)
+//   cmd/*          - точки входа для контейнеров; этот main поднимает
+//                    все три сервиса в одном процессе для разработки
+//
+//   клиент ──> bff :8080 ──┬──> users  :8081
+//                          └──> orders :8082 ──> users (проверка user_id)
+//
+// Сервисы общаются по HTTP/JSON, а не gRPC, чтобы проект собирался без
+// внешних зависимостей; в gRPC request ID передают так же, через metadata.
+// Теория: theory/microservices.md.
+//
+// Запуск:
+//   go run .                  все сервисы в одном процессе
+//   docker compose up --build все сервисы в отдельных контейнерах
+//
+//   curl -d '{"name":"Иван","email":"ivan@example.com"}' localhost:8081/users
+//   curl -d '{"user_id":1,"item":"Книга","amount":50000}' localhost:8082/orders
+//   curl -i -H 'X-Request-ID: demo-1' localhost:8080/api/profile/1
+//   curl localhost:8080/readyz
+//
+// В журнале всех трех сервисов запрос виден под одним ID demo-1.
+
+func main() {
+	usersAddr := flag.String("users", ":8081", "Адрес сервиса пользователей")
+	ordersAddr := flag.String("orders", ":8082", "Адрес сервиса заказов")
+	bffAddr := flag.String("bff", ":8080", "Адрес BFF")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	client := platform.NewClient(2 * time.Second)
+	usersClient := users.NewClient("http://localhost"+*usersAddr, client)
+	ordersClient := orders.NewClient("http://localhost"+*ordersAddr, client)
+	
+	// Если один сервер не запустился (порт занят), группа отменяет
+	// контекст и останавливает остальные
+	g, ctx := group.WithContext(ctx)
+	g.Go(func() error { return platform.Serve(ctx, *usersAddr, users.NewService().Handler()) })
+	g.Go(func() error { return platform.Serve(ctx, *ordersAddr, orders.NewService(usersClient).Handler()) })
+	g.Go(func() error { return platform.Serve(ctx, *bffAddr, bff.New(usersClient, ordersClient).Handler()) })
+	if err := g.Wait(); err != nil {
+		log.Fatal("Ошибка сервера:", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"microservices/internal/bff"
+	"microservices/internal/orders"
+	"microservices/internal/platform"
+	"microservices/internal/users"
+)
+
+// cluster три сервиса на httptest-серверах, связанные по HTTP, как в
+// docker-compose. seen записывает X-Request-ID каждого входящего
+// запроса по имени сервиса.
+type cluster struct {
+	users, orders, bff *httptest.Server
+
+	mu   sync.Mutex
+	seen map[string][]string
+}
+
+func newCluster(t *testing.T) *cluster {
+	t.Helper()
+	c := &cluster{seen: make(map[string][]string)}
+	client := platform.NewClient(time.Second)
+	
+	c.users = httptest.NewServer(c.record("users", users.NewService().Handler()))
+	usersClient := users.NewClient(c.users.URL, client)
+	c.orders = httptest.NewServer(c.record("orders", orders.NewService(usersClient).Handler()))
+	ordersClient := orders.NewClient(c.orders.URL, client)
+	c.bff = httptest.NewServer(c.record("bff", bff.New(usersClient, ordersClient).Handler()))
+	
+	t.Cleanup(func() {
+		c.bff.Close()
+		c.orders.Close()
+		c.users.Close()
+	})
+	return c
+}
+
+func (c *cluster) record(service string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		c.seen[service] = append(c.seen[service], r.Header.Get(platform.RequestIDHeader))
+		c.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func do(t *testing.T, method, url, body, requestID string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if requestID != "" {
+		req.Header.Set(platform.RequestIDHeader, requestID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestProfileFanOut(t *testing.T) {
+	c := newCluster(t)
+	do(t, http.MethodPost, c.users.URL+"/users", `{"name":"Иван","email":"ivan@example.com"}`, "")
+	for _, amount := range []int{100, 250} {
+		body := `{"user_id":1,"item":"Книга","amount":` + strconv.Itoa(amount) + `}`
+		if resp := do(t, http.MethodPost, c.orders.URL+"/orders", body, ""); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+		}
+	}
+	
+	c.mu.Lock()
+	c.seen = make(map[string][]string)
+	c.mu.Unlock()
+	resp := do(t, http.MethodGet, c.bff.URL+"/api/profile/1", "", "trace-1")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var profile bff.Profile
+	json.NewDecoder(resp.Body).Decode(&profile)
+	if profile.User.Name != "Иван" || len(profile.Orders) != 2 || profile.Total != 350 {
+		t.Errorf("Expected Иван with 2 orders totalling 350, got %+v", profile)
+	}
+	if got := resp.Header.Get(platform.RequestIDHeader); got != "trace-1" {
+		t.Errorf("Expected request id trace-1 in response, got %q", got)
+	}
+	
+	// Один запрос клиента прошел через все три сервиса с одним ID
+	for _, service := range []string{"bff", "users", "orders"} {
+		ids := c.seen[service]
+		if len(ids) != 1 || ids[0] != "trace-1" {
+			t.Errorf("Expected %s to see [trace-1], got %v", service, ids)
+		}
+	}
+}
+
+func TestServiceToServiceErrors(t *testing.T) {
+	c := newCluster(t)
+	
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+	}{
+		{"order for unknown user", http.MethodPost, c.orders.URL + "/orders", `{"user_id":42,"item":"Книга","amount":100}`, http.StatusUnprocessableEntity},
+		{"invalid order", http.MethodPost, c.orders.URL + "/orders", `{"user_id":1,"item":"","amount":100}`, http.StatusUnprocessableEntity},
+		{"profile of unknown user", http.MethodGet, c.bff.URL + "/api/profile/42", "", http.StatusNotFound},
+		{"bff ready", http.MethodGet, c.bff.URL + "/readyz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := do(t, tt.method, tt.url, tt.body, ""); resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestUsersDown(t *testing.T) {
+	c := newCluster(t)
+	c.users.Close()
+	
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+	}{
+		{"profile", http.MethodGet, c.bff.URL + "/api/profile/1", "", http.StatusBadGateway},
+		{"create order", http.MethodPost, c.orders.URL + "/orders", `{"user_id":1,"item":"Книга","amount":100}`, http.StatusBadGateway},
+		{"orders not ready", http.MethodGet, c.orders.URL + "/readyz", "", http.StatusServiceUnavailable},
+		{"bff not ready", http.MethodGet, c.bff.URL + "/readyz", "", http.StatusServiceUnavailable},
+		{"orders alive", http.MethodGet, c.orders.URL + "/healthz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := do(t, tt.method, tt.url, tt.body, ""); resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Document документ в индексе
+type Document struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// Analyzed документ после токенизации: частоты термов и длина. Анализ -
+// самая дорогая часть индексации, и он не трогает индекс, поэтому его
+// можно делать параллельно (см. indexer.go).
+type Analyzed struct {
+	Doc    Document
+	Terms  map[string]int // Терм -> сколько раз встретился
+	Length int            // Всего термов
+}
+
+// titleWeight во сколько раз слово в заголовке весомее слова в тексте
+const titleWeight = 3
+
+// Analyze токенизирует документ
+func Analyze(doc Document) Analyzed {
+	a := Analyzed{Doc: doc, Terms: make(map[string]int)}
+	for _, t := range Tokenize(doc.Title) {
+		a.Terms[t] += titleWeight
+		a.Length += titleWeight
+	}
+	for _, t := range Tokenize(doc.Body) {
+		a.Terms[t]++
+		a.Length++
+	}
+	return a
+}
+
+// Index обратный индекс: для каждого терма - документы, в которых он
+// встречается, и сколько раз. Поиск по запросу читает только списки
+// термов запроса, а не все документы.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]Document
+	lengths  map[string]int
+	postings map[string]map[string]int // Терм -> ID документа -> частота
+}
+
+// NewIndex создает пустой индекс
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]Document),
+		lengths:  make(map[string]int),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// Add индексирует документ; документ с тем же ID заменяется
+func (idx *Index) Add(doc Document) {
+	idx.AddAnalyzed(Analyze(doc))
+}
+
+// AddAnalyzed добавляет уже проанализированный документ. Под
+// блокировкой только обновление карт, без токенизации.
+func (idx *Index) AddAnalyzed(a Analyzed) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	
+	idx.remove(a.Doc.ID)
+	idx.docs[a.Doc.ID] = a.Doc
+	idx.lengths[a.Doc.ID] = a.Length
+	for term, tf := range a.Terms {
+		list, ok := idx.postings[term]
+		if !ok {
+			list = make(map[string]int)
+			idx.postings[term] = list
+		}
+		list[a.Doc.ID] = tf
+	}
+}
+
+// Remove удаляет документ; false, если его не было
+func (idx *Index) Remove(id string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.remove(id)
+}
+
+// remove удаляет документ из всех списков; вызывается под mu.
+// Термы документа берутся из его текста заново, чтобы не хранить
+// для каждого документа еще и список его термов.
+func (idx *Index) remove(id string) bool {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return false
+	}
+	for term := range Analyze(doc).Terms {
+		list := idx.postings[term]
+		delete(list, id)
+		if len(list) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, id)
+	delete(idx.lengths, id)
+	return true
+}
+
+// Get возвращает документ по ID
+func (idx *Index) Get(id string) (Document, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	doc, ok := idx.docs[id]
+	return doc, ok
+}
+
+// Result найденный документ
+type Result struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Snippet string   `json:"snippet"`
+	Score   float64  `json:"score"`
+	Matched []string `json:"matched"` // Термы запроса, найденные в документе
+}
+
+// Search ищет документы, содержащие хотя бы один терм запроса, и
+// сортирует их по TF-IDF:
+//
+//	score(d) = Σ tf(t, d) / len(d) · idf(t),   idf(t) = ln(1 + N / df(t))
+//
+// tf - частота терма в документе, нормированная на длину (длинный
+// документ не выигрывает только за счет объема); idf - редкость терма
+// в корпусе: совпадение по редкому слову ценнее, чем по частому.
+func (idx *Index) Search(query string, limit int) []Result {
+	terms := unique(Tokenize(query))
+	
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	
+	n := float64(len(idx.docs))
+	scores := make(map[string]float64)
+	matched := make(map[string][]string)
+	for _, term := range terms {
+		list := idx.postings[term]
+		if len(list) == 0 {
+			continue
+		}
+		idf := math.Log(1 + n/float64(len(list)))
+		for id, tf := range list {
+			scores[id] += float64(tf) / float64(idx.lengths[id]) * idf
+			matched[id] = append(matched[id], term)
+		}
+	}
+	
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		doc := idx.docs[id]
+		results = append(results, Result{
+			ID:      id,
+			Title:   doc.Title,
+			Snippet: snippet(doc.Body, matched[id]),
+			Score:   math.Round(score*1e6) / 1e6,
+			Matched: matched[id],
+		})
+	}
+	// При равных баллах - по ID, чтобы порядок был стабильным
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// Stats размер индекса
+type Stats struct {
+	Documents int `json:"documents"`
+	Terms     int `json:"terms"`
+}
+
+// Stats возвращает размер индекса
+func (idx *Index) Stats() Stats {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return Stats{Documents: len(idx.docs), Terms: len(idx.postings)}
+}
+
+func unique(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// snippetRadius сколько символов показывать вокруг найденного слова
+const snippetRadius = 60
+
+// snippet вырезает кусок текста вокруг первого найденного терма
+func snippet(body string, terms []string) string {
+	runes := []rune(body)
+	// Позиция ищется в приведенном тексте, а вырезается из исходного;
+	// это верно, пока приведение не меняет число символов
+	lower := strings.ReplaceAll(strings.ToLower(body), "ё", "е")
+	pos := 0
+	if utf8.RuneCountInString(lower) == len(runes) {
+		for _, term := range terms {
+			if i := strings.Index(lower, term); i >= 0 {
+				pos = utf8.RuneCountInString(lower[:i])
+				break
+			}
+		}
+	}
+	
+	start, end := max(0, pos-snippetRadius), min(len(runes), pos+snippetRadius)
+	s := strings.Join(strings.Fields(string(runes[start:end])), " ")
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(runes) {
+		s += "…"
+	}
+	return s
+}
\ No newline at end of file
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func corpus() *Index {
+	idx := NewIndex()
+	idx.Add(Document{ID: "ctx", Title: "Контекст", Body: "Контекст передает отмену и дедлайны между горутинами. context.WithCancel"})
+	idx.Add(Document{ID: "chan", Title: "Каналы", Body: "Каналы связывают горутины. Закрытый канал сигнализирует об отмене всем читателям."})
+	idx.Add(Document{ID: "mutex", Title: "Мьютексы", Body: "Мьютекс защищает общие данные от гонок между горутинами."})
+	idx.Add(Document{ID: "gc", Title: "Сборщик мусора", Body: "Сборщик мусора освобождает память, на которую нет ссылок."})
+	return idx
+}
+
+func ids(results []Result) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.ID
+	}
+	return out
+}
+
+func TestSearchRanking(t *testing.T) {
+	idx := corpus()
+	
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Слово в заголовке весит больше, чем в тексте
+		{"контекст", []string{"ctx"}},
+		// Терм в двух документах - выше короткий, где его доля больше
+		{"горутинами", []string{"mutex", "ctx"}},
+		// Редкий терм ("отмену" - только в ctx) решает при равных частых
+		{"отмену горутинами", []string{"ctx", "mutex"}},
+		{"КАНАЛЫ", []string{"chan"}},
+		{"и на от", []string{}},
+		{"квантовый", []string{}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := ids(idx.Search(tt.query, 0))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSearchResult(t *testing.T) {
+	idx := corpus()
+	results := idx.Search("сборщик память", 1)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if r.ID != "gc" || r.Title != "Сборщик мусора" || r.Score <= 0 {
+		t.Errorf("Unexpected result: %+v", r)
+	}
+	if strings.Join(r.Matched, ",") != "сборщик,память" {
+		t.Errorf("Expected matched [сборщик память], got %v", r.Matched)
+	}
+	if !strings.Contains(r.Snippet, "Сборщик") {
+		t.Errorf("Expected snippet around the match, got %q", r.Snippet)
+	}
+	
+	// Длинный текст обрезается вокруг совпадения
+	long := strings.Repeat("слово ", 50) + "иголка " + strings.Repeat("слово ", 50)
+	s := snippet(long, []string{"иголка"})
+	if !strings.HasPrefix(s, "…") || !strings.HasSuffix(s, "…") || !strings.Contains(s, "иголка") {
+		t.Errorf("Expected trimmed snippet with the match, got %q", s)
+	}
+}
+
+func TestIndexReplaceAndRemove(t *testing.T) {
+	idx := corpus()
+	before := idx.Stats()
+	
+	// Замена документа убирает его старые термы
+	idx.Add(Document{ID: "gc", Title: "Сборщик мусора", Body: "Трехцветная маркировка."})
+	if got := idx.Search("память", 0); len(got) != 0 {
+		t.Errorf("Expected old terms to be gone after replace, got %v", ids(got))
+	}
+	if got := idx.Search("маркировка", 0); len(got) != 1 {
+		t.Errorf("Expected new terms to be found, got %v", ids(got))
+	}
+	if idx.Stats().Documents != before.Documents {
+		t.Errorf("Expected replace to keep document count %d, got %d", before.Documents, idx.Stats().Documents)
+	}
+	
+	if !idx.Remove("gc") || idx.Remove("gc") {
+		t.Error("Expected Remove to return true once")
+	}
+	if got := idx.Search("сборщик маркировка", 0); len(got) != 0 {
+		t.Errorf("Expected removed document not to be found, got %v", ids(got))
+	}
+	for term, list := range idx.postings {
+		if len(list) == 0 {
+			t.Errorf("Expected empty posting list for %q to be deleted", term)
+		}
+	}
+}
+
+func TestIndexConcurrent(t *testing.T) {
+	idx := NewIndex()
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				idx.Add(Document{ID: fmt.Sprintf("%d-%d", w, i), Body: fmt.Sprintf("горутина номер n%d", i)})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				idx.Search("горутина", 5)
+			}
+		}()
+	}
+	wg.Wait()
+	
+	if got := len(idx.Search("горутина", 0)); got != 400 {
+		t.Errorf("Expected 400 documents, got %d", got)
+	}
+	if got := len(idx.Search("n7", 0)); got != 4 {
+		t.Errorf("Expected 4 documents for n7, got %d", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Indexer индексирует поток документов несколькими воркерами. Разбор
+// текста (Analyze) идет параллельно, а запись в индекс - короткая
+// операция под блокировкой, поэтому поиск во время индексации
+// продолжает работать и видит уже добавленные документы.
+//
+//	документы ──> analyze ×N ──> AddAnalyzed (по одному под Lock)
+type Indexer struct {
+	index   *Index
+	workers int
+}
+
+// NewIndexer создает индексатор; workers <= 0 - по числу CPU
+func NewIndexer(index *Index, workers int) *Indexer {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Indexer{index: index, workers: workers}
+}
+
+// Run индексирует документы из канала, пока он не закроется или не
+// отменится ctx. Возвращает число проиндексированных документов.
+func (ix *Indexer) Run(ctx context.Context, docs <-chan Document) int {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		count int
+	)
+	for range ix.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case doc, ok := <-docs:
+					if !ok {
+						return
+					}
+					ix.index.AddAnalyzed(Analyze(doc))
+					mu.Lock()
+					count++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return count
+}
+
+// maxFileSize файлы больше пропускаются: это скорее дампы, чем тексты
+const maxFileSize = 1 << 20
+
+// IndexDir индексирует .txt и .md файлы каталога. ID документа - путь
+// относительно dir, заголовок - первая непустая строка. Файлы читает
+// одна горутина (диск - последовательный ресурс), разбирают - воркеры.
+func (ix *Indexer) IndexDir(ctx context.Context, dir string) (int, error) {
+	docs := make(chan Document, ix.workers)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(docs)
+		errCh <- filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if d.IsDir() || (ext != ".txt" && ext != ".md") {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil || info.Size() > maxFileSize {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(dir, path)
+			title, body := splitTitle(string(data))
+			select {
+			case docs <- Document{ID: filepath.ToSlash(rel), Title: title, Body: body, IndexedAt: time.Now().UTC()}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	
+	n := ix.Run(ctx, docs)
+	// Если воркеры остановились по ctx, обход может ждать на отправке:
+	// выбираем остаток, чтобы он завершился
+	for range docs {
+	}
+	return n, <-errCh
+}
+
+// splitTitle отделяет первую непустую строку; "# " заголовка markdown
+// отбрасывается
+func splitTitle(text string) (title, body string) {
+	text = strings.TrimLeft(text, "\r\n\t ")
+	title, body, _ = strings.Cut(text, "\n")
+	return strings.TrimSpace(strings.TrimLeft(title, "# ")), strings.TrimSpace(body)
+}
\ No newline at end of file
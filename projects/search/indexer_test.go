@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.md":            "# Язык Go\n\nGo компилируется в один бинарник.",
+		"sub/channels.txt": "\n\nКаналы\nБуферизованный канал не блокирует отправку.",
+		"image.png":        "not text",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+	}
+	
+	idx := NewIndex()
+	n, err := NewIndexer(idx, 2).IndexDir(context.Background(), dir)
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 files indexed, got %d, %v", n, err)
+	}
+	
+	doc, ok := idx.Get("sub/channels.txt")
+	if !ok || doc.Title != "Каналы" || doc.Body != "Буферизованный канал не блокирует отправку." {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+	if doc, _ := idx.Get("go.md"); doc.Title != "Язык Go" {
+		t.Errorf("Expected markdown heading as title, got %q", doc.Title)
+	}
+	if got := idx.Search("бинарник", 0); len(got) != 1 || got[0].ID != "go.md" {
+		t.Errorf("Expected go.md, got %v", ids(got))
+	}
+	
+	if _, err := NewIndexer(idx, 2).IndexDir(context.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
+
+func TestIndexerRun(t *testing.T) {
+	idx := NewIndex()
+	docs := make(chan Document)
+	go func() {
+		defer close(docs)
+		for i := range 1000 {
+			docs <- Document{ID: fmt.Sprint(i), Body: fmt.Sprintf("документ d%d", i%10)}
+		}
+	}()
+	
+	if n := NewIndexer(idx, 8).Run(context.Background(), docs); n != 1000 {
+		t.Errorf("Expected 1000 documents indexed, got %d", n)
+	}
+	if got := len(idx.Search("d3", 0)); got != 100 {
+		t.Errorf("Expected 100 documents for d3, got %d", got)
+	}
+}
+
+func TestIndexDirCancel(t *testing.T) {
+	dir := t.TempDir()
+	for i := range 50 {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.txt", i)), []byte("текст"), 0o644)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	
+	// Отмененная индексация завершается, не зависая на отправке
+	n, err := NewIndexer(NewIndex(), 2).IndexDir(ctx, dir)
+	if err == nil && n == 50 {
+		t.Errorf("Expected canceled indexing to stop early, got %d files", n)
+	}
+}
\ No newline at end of file
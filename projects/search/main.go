@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Полнотекстовый поиск в памяти - итоговый проект, собранный из уроков:
+//   tokenize.go - разбор текста на термы, стоп-слова (examples/string-algorithms)
+//   index.go    - обратный индекс, ранжирование TF-IDF, RWMutex
+//                 (examples/ds, examples/synchronization)
+//   indexer.go  - пул воркеров для параллельной индексации (examples/goroutines)
+//   server.go   - HTTP API (examples/http-server)
+//   main.go     - конфигурация, graceful shutdown (examples/signals)
+//
+// Запуск: go run . -dir ../../theory
+//   curl 'localhost:8080/search?q=context+cancel&limit=5'
+//   curl -X PUT -d '{"title":"Каналы","body":"Буферизованный канал..."}' localhost:8080/documents/channels
+//   curl localhost:8080/stats
+//
+// Тесты: go test -race ./...
+
+// Config параметры сервиса
+type Config struct {
+	Addr    string
+	Dir     string
+	Workers int
+}
+
+// run индексирует каталог и запускает сервер; блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	index := NewIndex()
+	srv := NewServer(index)
+	httpServer := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           srv.Routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Сервер запущен на %s", cfg.Addr)
+		errCh <- httpServer.ListenAndServe()
+	}()
+	
+	// Индексация идет, пока сервер уже отвечает: поиск видит документы
+	// по мере добавления
+	indexCtx, stopIndexing := context.WithCancel(ctx)
+	defer stopIndexing()
+	indexed := make(chan struct{})
+	go func() {
+		defer close(indexed)
+		if cfg.Dir == "" {
+			return
+		}
+		start := time.Now()
+		n, err := NewIndexer(index, cfg.Workers).IndexDir(indexCtx, cfg.Dir)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("Ошибка индексации %s: %v", cfg.Dir, err)
+		}
+		stats := index.Stats()
+		log.Printf("Проиндексировано файлов: %d за %v, термов: %d", n, time.Since(start).Round(time.Millisecond), stats.Terms)
+	}()
+	
+	select {
+	case err := <-errCh:
+		stopIndexing()
+		<-indexed
+		return err
+	case <-ctx.Done():
+	}
+	
+	log.Println("Остановка...")
+	stopIndexing()
+	<-indexed
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.Addr, "addr", ":8080", "адрес HTTP-сервера")
+	flag.StringVar(&cfg.Dir, "dir", "", "каталог с .txt и .md для индексации при старте")
+	flag.IntVar(&cfg.Workers, "workers", 0, "воркеров индексации (0 - по числу CPU)")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ограничения запросов
+const (
+	maxQueryLength = 256
+	maxLimit       = 100
+	defaultLimit   = 10
+	maxBodySize    = 1 << 20
+)
+
+// Server HTTP-слой поиска
+type Server struct {
+	index *Index
+}
+
+// NewServer создает сервер над индексом
+func NewServer(index *Index) *Server {
+	return &Server{index: index}
+}
+
+// Routes возвращает маршруты:
+//
+//	GET    /search?q=...&limit=10  результаты по убыванию релевантности
+//	PUT    /documents/{id}         {"title", "body"} - добавить или заменить
+//	GET    /documents/{id}
+//	DELETE /documents/{id}
+//	GET    /stats                  число документов и термов
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", s.handleSearch)
+	mux.HandleFunc("PUT /documents/{id}", s.handlePut)
+	mux.HandleFunc("GET /documents/{id}", s.handleGet)
+	mux.HandleFunc("DELETE /documents/{id}", s.handleDelete)
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.index.Stats())
+	})
+	return mux
+}
+
+type searchResponse struct {
+	Query   string   `json:"query"`
+	Total   int      `json:"total"`
+	Results []Result `json:"results"`
+	TookMS  float64  `json:"took_ms"`
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	if len(q) > maxQueryLength {
+		writeError(w, http.StatusBadRequest, "q is too long")
+		return
+	}
+	limit := defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxLimit))
+			return
+		}
+		limit = n
+	}
+	
+	start := time.Now()
+	all := s.index.Search(q, 0)
+	resp := searchResponse{Query: q, Total: len(all), Results: all[:min(limit, len(all))]}
+	resp.TookMS = float64(time.Since(start).Microseconds()) / 1000
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type putRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func (s *Server) handlePut(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req putRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "document is too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" && strings.TrimSpace(req.Body) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "title or body is required")
+		return
+	}
+	
+	_, existed := s.index.Get(id)
+	doc := Document{ID: id, Title: req.Title, Body: req.Body, IndexedAt: time.Now().UTC()}
+	s.index.Add(doc)
+	status := http.StatusCreated
+	if existed {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, doc)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	doc, ok := s.index.Get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !s.index.Remove(r.PathValue("id")) {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
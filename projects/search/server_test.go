@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	srv := httptest.NewServer(NewServer(corpus()).Routes())
+	defer srv.Close()
+	
+	do := func(method, path, body string) *http.Response {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"search", "GET", "/search?q=горутинами", "", 200},
+		{"empty query", "GET", "/search?q=+", "", 400},
+		{"long query", "GET", "/search?q=" + strings.Repeat("a", maxQueryLength+1), "", 400},
+		{"bad limit", "GET", "/search?q=go&limit=0", "", 400},
+		{"limit too big", "GET", "/search?q=go&limit=1000", "", 400},
+		{"create", "PUT", "/documents/new", `{"title":"Новый","body":"текст"}`, 201},
+		{"replace", "PUT", "/documents/new", `{"title":"Новый","body":"другой текст"}`, 200},
+		{"invalid json", "PUT", "/documents/x", `{`, 400},
+		{"empty document", "PUT", "/documents/x", `{"title":" "}`, 422},
+		{"too large", "PUT", "/documents/x", `{"body":"` + strings.Repeat("a", maxBodySize) + `"}`, 413},
+		{"get", "GET", "/documents/new", "", 200},
+		{"get missing", "GET", "/documents/x", "", 404},
+		{"delete", "DELETE", "/documents/new", "", 204},
+		{"delete missing", "DELETE", "/documents/new", "", 404},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := do(tt.method, tt.path, tt.body); resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+	
+	var got searchResponse
+	json.NewDecoder(do("GET", "/search?q=горутинами&limit=1", "").Body).Decode(&got)
+	if got.Total != 2 || len(got.Results) != 1 || got.Results[0].ID != "mutex" {
+		t.Errorf("Expected total 2 with top result mutex, got %+v", got)
+	}
+	
+	var stats Stats
+	json.NewDecoder(do("GET", "/stats", "").Body).Decode(&stats)
+	if stats.Documents != 4 {
+		t.Errorf("Expected 4 documents after create and delete, got %d", stats.Documents)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords частые слова, которые есть почти в каждом документе и
+// ничего не говорят о его теме. Их IDF близок к нулю, но без фильтра
+// они раздувают индекс: у "и" список документов - весь корпус.
+var stopWords = func() map[string]bool {
+	words := strings.Fields(`
+		a an and are as at be but by for from has have in is it its of on or
+		that the this to was were will with not no
+		а без бы в во вот да для до же за и из или к как ко ли на над не нет
+		но о об от по под при про с со так то тоже у уже что чтобы это этот
+		был была были было быть есть он она они оно мы вы я ты его ее их`)
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}()
+
+// maxTokenLength длиннее - скорее всего мусор (base64, хэши), а не слово
+const maxTokenLength = 64
+
+// Tokenize разбивает текст на термы: слова из букв и цифр в нижнем
+// регистре, без стоп-слов. Запрос и документы проходят через одну и ту
+// же функцию - иначе "Go" в запросе не найдет "go" в тексте.
+//
+// Стемминга нет: "индекс" и "индексы" - разные термы. Для русского
+// языка с его окончаниями это заметно, и в настоящем поиске здесь
+// стоит стеммер (например, Snowball).
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		word = strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+		if len([]rune(word)) < 2 && !unicode.IsDigit([]rune(word)[0]) {
+			continue
+		}
+		if len(word) > maxTokenLength || stopWords[word] {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"lowercase and punctuation", "Go: простой, надежный!", []string{"go", "простой", "надежный"}},
+		{"stop words", "the context of a request и его отмена", []string{"context", "request", "отмена"}},
+		{"yo normalized", "Ёлка и елка", []string{"елка", "елка"}},
+		{"digits kept", "HTTP 2 и TLS1.3", []string{"http", "2", "tls1", "3"}},
+		{"single letters dropped", "x y z go", []string{"go"}},
+		{"identifiers split", "context.WithCancel(ctx)", []string{"context", "withcancel", "ctx"}},
+		{"too long", strings.Repeat("a", maxTokenLength+1) + " ok", []string{"ok"}},
+		{"empty", "  ,.;  ", nil},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Сервис миниатюр - итоговый проект об асинхронной обработке, собранный из уроков:
+//   queue.go  - персистентная очередь задач в SQLite (examples/jobs)
+//   worker.go - обработчик задач, атомарная запись файлов (examples/jobs)
+//   resize.go - уменьшение изображений усреднением (examples/matrix)
+//   server.go - загрузка multipart, 202 Accepted и опрос статуса (examples/http-server)
+//   main.go   - конфигурация, graceful shutdown (examples/signals)
+//
+// Загрузка только сохраняет оригинал и ставит задачу; миниатюры делает
+// пул воркеров. Задачи лежат в SQLite, поэтому переживают перезапуск:
+// при остановке воркеры доделывают текущие картинки, а если процесс
+// убили раньше, зависшие задачи возвращаются в очередь при старте.
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск: go run . -data ./data
+//   curl -F image=@photo.jpg localhost:8080/api/images
+//   curl localhost:8080/api/images/<id>
+//   curl -o thumb.jpg localhost:8080/thumbnails/<id>/256.jpg
+//
+// Тесты: go test -race ./...
+
+// Config параметры сервиса
+type Config struct {
+	Addr            string
+	DataDir         string
+	Sizes           []int
+	Workers         int
+	PollInterval    time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// openDB открывает БД очереди; параметры те же, что в examples/jobs
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_txlock=immediate&_busy_timeout=5000&_journal_mode=WAL", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// parseSizes разбирает список размеров вида "64,256,1024"
+func parseSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, field := range strings.Split(s, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("неверный размер %q", field)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// run запускает сервис и блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	if err := os.MkdirAll(cfg.DataDir, 0o755); err != nil {
+		return err
+	}
+	db, err := openDB(filepath.Join(cfg.DataDir, "jobs.db"))
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+	
+	queue, err := NewQueue(db)
+	if err != nil {
+		return fmt.Errorf("init queue: %w", err)
+	}
+	thumbnailer, err := NewThumbnailer(cfg.DataDir, cfg.Sizes, queue)
+	if err != nil {
+		return fmt.Errorf("init storage: %w", err)
+	}
+	queue.Register(KindThumbnail, thumbnailer.Handle)
+	
+	// Экземпляр один, значит все running-задачи на старте остались
+	// от упавшего процесса. При нескольких экземплярах на одной БД
+	// здесь нужен порог больше самой долгой обработки.
+	recovered, err := queue.RecoverStale(0)
+	if err != nil {
+		return fmt.Errorf("recover jobs: %w", err)
+	}
+	if recovered > 0 {
+		log.Printf("Возвращено в очередь незавершенных задач: %d", recovered)
+	}
+	
+	workCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		queue.Work(workCtx, cfg.Workers, cfg.PollInterval)
+	}()
+	
+	httpServer := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           NewServer(queue, thumbnailer).Routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+		// Загрузка большого файла по медленному каналу занимает время
+		ReadTimeout:  time.Minute,
+		WriteTimeout: time.Minute,
+		IdleTimeout:  time.Minute,
+	}
+	
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Сервер запущен на %s, воркеров: %d", cfg.Addr, cfg.Workers)
+		errCh <- httpServer.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		stopWorkers()
+		wg.Wait()
+		return err
+	case <-ctx.Done():
+	}
+	
+	// Сначала перестаем принимать загрузки, затем просим воркеры не брать
+	// новые задачи и ждем текущие. Не успели за ShutdownTimeout - выходим:
+	// задачи останутся в running, и RecoverStale подберет их при старте.
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки сервера: %v", err)
+	}
+	stopWorkers()
+	
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Println("Воркеры остановлены")
+	case <-shutdownCtx.Done():
+		log.Println("Воркеры не успели завершиться, задачи будут повторены при запуске")
+	}
+	return nil
+}
+
+func main() {
+	var cfg Config
+	var sizes string
+	flag.StringVar(&cfg.Addr, "addr", ":8080", "адрес HTTP-сервера")
+	flag.StringVar(&cfg.DataDir, "data", "data", "каталог для БД, оригиналов и миниатюр")
+	flag.StringVar(&sizes, "sizes", "64,256,1024", "размеры миниатюр по большей стороне")
+	flag.IntVar(&cfg.Workers, "workers", 4, "число воркеров обработки")
+	flag.DurationVar(&cfg.PollInterval, "poll", 500*time.Millisecond, "интервал опроса очереди")
+	flag.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", 30*time.Second, "сколько ждать текущие задачи при остановке")
+	flag.Parse()
+	
+	var err error
+	if cfg.Sizes, err = parseSizes(sizes); err != nil {
+		log.Fatal(err)
+	}
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Копия examples/jobs/jobs.go, дополненная для сервиса миниатюр:
+// прогресс выполнения, возврат зависших задач после падения процесса
+// и завершение текущих задач при остановке вместо их прерывания.
+
+// Статусы задач
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// Job задача, сохраненная в таблице jobs
+type Job struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Payload     string    `json:"payload"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	RunAt       time.Time `json:"run_at"`
+	LastError   string    `json:"last_error,omitempty"`
+	LockedBy    string    `json:"locked_by,omitempty"`
+	Progress    int       `json:"progress"` // 0-100, обновляет обработчик
+}
+
+// HandlerFunc обработчик задач определенного типа
+type HandlerFunc func(ctx context.Context, job Job) error
+
+// ErrNoJobs возвращается, когда нет задач, готовых к выполнению
+var ErrNoJobs = errors.New("нет готовых задач")
+
+// Queue персистентная очередь задач поверх SQLite
+type Queue struct {
+	db       *sql.DB
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+
+	// Backoff вычисляет задержку перед повторной попыткой
+	Backoff func(attempt int) time.Duration
+}
+
+// NewQueue создает очередь и таблицу jobs.
+// DSN должен включать _txlock=immediate, чтобы каждая транзакция
+// начиналась с BEGIN IMMEDIATE и сразу брала блокировку на запись.
+func NewQueue(db *sql.DB) (*Queue, error) {
+	query := `
+	CREATE TABLE IF NOT EXISTS jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		max_attempts INTEGER NOT NULL DEFAULT 3,
+		run_at INTEGER NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		locked_by TEXT NOT NULL DEFAULT '',
+		locked_at INTEGER NOT NULL DEFAULT 0,
+		progress INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_jobs_ready ON jobs(status, run_at);`
+	
+	if _, err := db.Exec(query); err != nil {
+		return nil, err
+	}
+	
+	return &Queue{
+		db:       db,
+		handlers: make(map[string]HandlerFunc),
+		Backoff:  exponentialBackoff,
+	}, nil
+}
+
+// exponentialBackoff 1s, 2s, 4s, ... но не больше минуты
+func exponentialBackoff(attempt int) time.Duration {
+	d := time.Second << (attempt - 1)
+	if d > time.Minute || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+// Register регистрирует обработчик для типа задач
+func (q *Queue) Register(kind string, h HandlerFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = h
+}
+
+// Enqueue добавляет задачу для немедленного выполнения
+func (q *Queue) Enqueue(kind, payload string) (int64, error) {
+	return q.EnqueueAt(kind, payload, time.Now())
+}
+
+// EnqueueAt добавляет отложенную задачу
+func (q *Queue) EnqueueAt(kind, payload string, runAt time.Time) (int64, error) {
+	query := `INSERT INTO jobs (kind, payload, run_at) VALUES (?, ?, ?)`
+	result, err := q.db.Exec(query, kind, payload, runAt.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// claim атомарно забирает одну готовую задачу.
+// В Postgres здесь был бы SELECT ... FOR UPDATE SKIP LOCKED;
+// в SQLite ту же гарантию дает BEGIN IMMEDIATE: пока транзакция открыта,
+// другие воркеры не могут начать свою и забрать ту же задачу.
+func (q *Queue) claim(ctx context.Context, workerID string) (Job, error) {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Job{}, err
+	}
+	defer tx.Rollback()
+	
+	var job Job
+	var runAt int64
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, kind, payload, attempts, max_attempts, run_at
+		FROM jobs
+		WHERE status = ? AND run_at <= ?
+		ORDER BY run_at, id
+		LIMIT 1`, StatusPending, time.Now().Unix())
+	err = row.Scan(&job.ID, &job.Kind, &job.Payload, &job.Attempts, &job.MaxAttempts, &runAt)
+	if err == sql.ErrNoRows {
+		return Job{}, ErrNoJobs
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	
+	job.Attempts++
+	job.Status = StatusRunning
+	job.LockedBy = workerID
+	job.RunAt = time.Unix(runAt, 0)
+	
+	_, err = tx.ExecContext(ctx, `
+		UPDATE jobs SET status = ?, attempts = ?, locked_by = ?, locked_at = ?
+		WHERE id = ?`, StatusRunning, job.Attempts, workerID, time.Now().Unix(), job.ID)
+	if err != nil {
+		return Job{}, err
+	}
+	
+	return job, tx.Commit()
+}
+
+// complete фиксирует результат выполнения задачи
+func (q *Queue) complete(job Job, jobErr error) error {
+	if jobErr == nil {
+		_, err := q.db.Exec(`UPDATE jobs SET status = ?, last_error = '', progress = 100 WHERE id = ?`, StatusDone, job.ID)
+		return err
+	}
+	
+	if job.Attempts >= job.MaxAttempts {
+		_, err := q.db.Exec(`UPDATE jobs SET status = ?, last_error = ? WHERE id = ?`,
+			StatusFailed, jobErr.Error(), job.ID)
+		return err
+	}
+	
+	// Возвращаем в очередь с задержкой
+	retryAt := time.Now().Add(q.Backoff(job.Attempts))
+	_, err := q.db.Exec(`UPDATE jobs SET status = ?, last_error = ?, run_at = ?, locked_by = '', progress = 0 WHERE id = ?`,
+		StatusPending, jobErr.Error(), retryAt.Unix(), job.ID)
+	return err
+}
+
+// runJob вызывает обработчик, превращая панику в ошибку
+func (q *Queue) runJob(ctx context.Context, job Job) (err error) {
+	q.mu.RLock()
+	h, ok := q.handlers[job.Kind]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("нет обработчика для %q", job.Kind)
+	}
+	
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника: %v", r)
+		}
+	}()
+	return h(ctx, job)
+}
+
+// Work запускает n воркеров и блокируется до отмены контекста.
+// После отмены воркеры не берут новые задачи, но текущие доводят до
+// конца: обработчик получает контекст без отмены. Если процесс убьют
+// раньше, задача останется в running, и RecoverStale вернет ее в
+// очередь при следующем запуске.
+func (q *Queue) Work(ctx context.Context, n int, pollInterval time.Duration) {
+	jobCtx := context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func(workerID string) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				job, err := q.claim(ctx, workerID)
+				if err != nil {
+					if err != ErrNoJobs && ctx.Err() == nil {
+						log.Printf("%s: ошибка получения задачи: %v", workerID, err)
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(pollInterval):
+						continue
+					}
+				}
+				
+				jobErr := q.runJob(jobCtx, job)
+				if err := q.complete(job, jobErr); err != nil {
+					log.Printf("%s: ошибка сохранения результата задачи %d: %v", workerID, job.ID, err)
+				}
+			}
+		}(fmt.Sprintf("worker-%d", i))
+	}
+	wg.Wait()
+}
+
+// SetProgress сохраняет прогресс задачи в процентах
+func (q *Queue) SetProgress(id int64, percent int) error {
+	_, err := q.db.Exec(`UPDATE jobs SET progress = ? WHERE id = ? AND status = ?`, percent, id, StatusRunning)
+	return err
+}
+
+// RecoverStale возвращает в очередь задачи, которые числятся
+// выполняющимися дольше olderThan: их воркер упал вместе с процессом.
+// Вызывается при старте до запуска воркеров.
+func (q *Queue) RecoverStale(olderThan time.Duration) (int64, error) {
+	res, err := q.db.Exec(`
+		UPDATE jobs SET status = ?, locked_by = '', progress = 0, run_at = ?
+		WHERE status = ? AND locked_at <= ?`,
+		StatusPending, time.Now().Unix(), StatusRunning, time.Now().Add(-olderThan).Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Get возвращает задачу по ID
+func (q *Queue) Get(id int64) (*Job, error) {
+	row := q.db.QueryRow(`
+		SELECT id, kind, payload, status, attempts, max_attempts, run_at, last_error, locked_by, progress
+		FROM jobs WHERE id = ?`, id)
+	return scanJob(row)
+}
+
+// List возвращает последние задачи
+func (q *Queue) List(limit int) ([]Job, error) {
+	rows, err := q.db.Query(`
+		SELECT id, kind, payload, status, attempts, max_attempts, run_at, last_error, locked_by, progress
+		FROM jobs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var jobs []Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+// Stats возвращает количество задач по статусам
+func (q *Queue) Stats() (map[string]int, error) {
+	rows, err := q.db.Query(`SELECT status, COUNT(*) FROM jobs GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	stats := map[string]int{StatusPending: 0, StatusRunning: 0, StatusDone: 0, StatusFailed: 0}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		stats[status] = count
+	}
+	return stats, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(s scanner) (*Job, error) {
+	var job Job
+	var runAt int64
+	err := s.Scan(&job.ID, &job.Kind, &job.Payload, &job.Status, &job.Attempts,
+		&job.MaxAttempts, &runAt, &job.LastError, &job.LockedBy, &job.Progress)
+	if err != nil {
+		return nil, err
+	}
+	job.RunAt = time.Unix(runAt, 0)
+	return &job, nil
+}
\ No newline at end of file
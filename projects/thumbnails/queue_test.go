@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestQueue создает очередь в отдельном файле для каждого теста
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	q, err := NewQueue(db)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return q
+}
+
+func TestQueueProgress(t *testing.T) {
+	q := newTestQueue(t)
+	id, err := q.Enqueue("test", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	// Прогресс пишется только для выполняющейся задачи
+	if err := q.SetProgress(id, 50); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job, _ := q.Get(id); job.Progress != 0 {
+		t.Errorf("Expected progress 0 for pending job, got %d", job.Progress)
+	}
+	
+	if _, err := q.claim(context.Background(), "w1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.SetProgress(id, 50)
+	if job, _ := q.Get(id); job.Progress != 50 {
+		t.Errorf("Expected progress 50, got %d", job.Progress)
+	}
+	
+	job, _ := q.Get(id)
+	if err := q.complete(*job, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job, _ := q.Get(id); job.Status != StatusDone || job.Progress != 100 {
+		t.Errorf("Expected done with progress 100, got %s %d", job.Status, job.Progress)
+	}
+}
+
+func TestQueueRecoverStale(t *testing.T) {
+	q := newTestQueue(t)
+	id, _ := q.Enqueue("test", "")
+	if _, err := q.claim(context.Background(), "w1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.SetProgress(id, 40)
+	
+	// Свежая задача еще может выполняться другим экземпляром
+	n, err := q.RecoverStale(time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Expected 0 recovered, got %d", n)
+	}
+	
+	n, err = q.RecoverStale(0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 recovered, got %d", n)
+	}
+	job, _ := q.Get(id)
+	if job.Status != StatusPending || job.Progress != 0 || job.LockedBy != "" {
+		t.Errorf("Expected reset pending job, got %+v", job)
+	}
+	
+	// Попытка уже засчитана: упавший процесс тоже считается попыткой
+	claimed, err := q.claim(context.Background(), "w2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if claimed.Attempts != 2 {
+		t.Errorf("Expected attempt 2, got %d", claimed.Attempts)
+	}
+}
+
+func TestQueueWorkFinishesRunningJobOnCancel(t *testing.T) {
+	q := newTestQueue(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var handled, canceled atomic.Bool
+	q.Register("slow", func(ctx context.Context, job Job) error {
+		close(started)
+		<-release
+		canceled.Store(ctx.Err() != nil)
+		handled.Store(true)
+		return nil
+	})
+	id, _ := q.Enqueue("slow", "")
+	q.Enqueue("slow", "") // Не должна начаться после отмены
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Work(ctx, 1, 10*time.Millisecond)
+		close(done)
+	}()
+	
+	<-started
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Expected Work to wait for the running job")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-done
+	
+	if !handled.Load() || canceled.Load() {
+		t.Errorf("Expected job to finish with live context, handled=%v canceled=%v", handled.Load(), canceled.Load())
+	}
+	if job, _ := q.Get(id); job.Status != StatusDone {
+		t.Errorf("Expected status %s, got %s", StatusDone, job.Status)
+	}
+	stats, _ := q.Stats()
+	if stats[StatusPending] != 1 {
+		t.Errorf("Expected 1 pending job, got %v", stats)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// fitSize вписывает w x h в квадрат maxSide с сохранением пропорций.
+// Меньшие картинки не увеличиваются: миниатюра крупнее оригинала
+// только размывает его.
+func fitSize(w, h, maxSide int) (int, int) {
+	if w <= maxSide && h <= maxSide {
+		return w, h
+	}
+	if w >= h {
+		return maxSide, max(1, h*maxSide/w)
+	}
+	return max(1, w*maxSide/h), maxSide
+}
+
+// toRGBA приводит изображение к *image.RGBA: JPEG декодируется в YCbCr,
+// PNG - в NRGBA или палитру, а обходить удобнее один формат
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, src, b.Min, draw.Src)
+	return dst
+}
+
+// Resize уменьшает изображение до maxSide по большей стороне усреднением
+// по площади (box filter): каждый пиксель результата - среднее всех
+// исходных пикселей, которые на него попадают. В отличие от ближайшего
+// соседа, мелкие детали не пропадают и не дают "лесенки".
+// Прозрачность накладывается на белый фон, потому что результат
+// сохраняется в JPEG.
+func Resize(src image.Image, maxSide int) *image.RGBA {
+	in := toRGBA(src)
+	sw, sh := in.Rect.Dx(), in.Rect.Dy()
+	dw, dh := fitSize(sw, sh, maxSide)
+	out := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	
+	for y := range dh {
+		y0, y1 := y*sh/dh, (y+1)*sh/dh
+		for x := range dw {
+			x0, x1 := x*sw/dw, (x+1)*sw/dw
+			
+			// Цвета в image.RGBA уже умножены на альфу, поэтому сумма
+			// по каналам корректна и для полупрозрачных пикселей
+			var r, g, b, a uint64
+			for sy := y0; sy < y1; sy++ {
+				row := in.Pix[sy*in.Stride+x0*4 : sy*in.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					b += uint64(row[i+2])
+					a += uint64(row[i+3])
+				}
+			}
+			n := uint64((x1 - x0) * (y1 - y0))
+			// Белый фон под прозрачными участками: c + (255 - a)
+			white := 255*n - a
+			out.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + white + n/2) / n),
+				G: uint8((g + white + n/2) / n),
+				B: uint8((b + white + n/2) / n),
+				A: 255,
+			})
+		}
+	}
+	return out
+}
\ No newline at end of file
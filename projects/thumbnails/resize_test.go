@@ -0,0 +1,109 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h, max    int
+		wantW, wantH int
+	}{
+		{"landscape", 1000, 500, 100, 100, 50},
+		{"portrait", 300, 1200, 200, 50, 200},
+		{"square", 800, 800, 64, 64, 64},
+		{"smaller than max", 40, 30, 64, 40, 30},
+		{"very thin", 10000, 3, 100, 100, 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := fitSize(tt.w, tt.h, tt.max)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.wantW, tt.wantH, w, h)
+			}
+		})
+	}
+}
+
+func TestResizeKeepsSolidColor(t *testing.T) {
+	want := color.RGBA{200, 100, 50, 255}
+	src := image.NewNRGBA(image.Rect(0, 0, 301, 157))
+	for y := range 157 {
+		for x := range 301 {
+			src.SetNRGBA(x, y, color.NRGBA{200, 100, 50, 255})
+		}
+	}
+	
+	out := Resize(src, 64)
+	if out.Rect.Dx() != 64 || out.Rect.Dy() != 33 {
+		t.Fatalf("Expected 64x33, got %dx%d", out.Rect.Dx(), out.Rect.Dy())
+	}
+	for y := range out.Rect.Dy() {
+		for x := range out.Rect.Dx() {
+			if got := out.RGBAAt(x, y); got != want {
+				t.Fatalf("Expected %v at (%d,%d), got %v", want, x, y, got)
+			}
+		}
+	}
+}
+
+func TestResizeAveragesPixels(t *testing.T) {
+	// Шахматная доска 2x2 из черного и белого должна стать серой,
+	// а не черной или белой, как при выборке ближайшего соседа
+	src := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			if (x+y)%2 == 0 {
+				src.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+	
+	out := Resize(src, 8)
+	got := out.RGBAAt(3, 3)
+	if got.R < 126 || got.R > 129 || got.R != got.G || got.G != got.B {
+		t.Errorf("Expected mid gray, got %v", got)
+	}
+}
+
+func TestResizeFlattensTransparency(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	// Левая половина прозрачная, правая - непрозрачный красный
+	for y := range 10 {
+		for x := 5; x < 10; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+	
+	out := Resize(src, 10)
+	if got := out.RGBAAt(0, 0); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("Expected white background, got %v", got)
+	}
+	if got := out.RGBAAt(9, 0); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("Expected red, got %v", got)
+	}
+}
+
+func TestResizeSubImage(t *testing.T) {
+	// SubImage с ненулевым Min не должен сдвигать результат
+	src := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	for y := range 20 {
+		for x := range 20 {
+			c := color.RGBA{0, 0, 0, 255}
+			if x >= 10 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+	sub := src.SubImage(image.Rect(10, 0, 20, 10))
+	
+	out := Resize(sub, 5)
+	if got := out.RGBAAt(0, 0); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("Expected blue, got %v", got)
+	}
+}
\ No newline at end of file
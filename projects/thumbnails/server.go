@@ -0,0 +1,250 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Ограничения загрузки. Размер файла отсекает большие тела запросов,
+// а число пикселей - "декомпрессионные бомбы": PNG в сотню килобайт
+// может заявить 30000x30000 и при декодировании занять гигабайты.
+const (
+	maxUploadBytes = 20 << 20
+	maxPixels      = 40_000_000
+)
+
+// allowedFormats форматы, для которых подключены декодеры
+var allowedFormats = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+}
+
+// Server HTTP-слой сервиса
+type Server struct {
+	queue       *Queue
+	thumbnailer *Thumbnailer
+}
+
+// NewServer собирает сервер из зависимостей
+func NewServer(q *Queue, t *Thumbnailer) *Server {
+	return &Server{queue: q, thumbnailer: t}
+}
+
+// Routes возвращает маршруты:
+//
+//	POST /api/images               multipart, поле image -> 202 {"id", "status_url"}
+//	GET  /api/images/{id}          статус, прогресс и ссылки на миниатюры
+//	GET  /thumbnails/{id}/{size}.jpg
+//	GET  /healthz                  состояние очереди
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/images", s.handleUpload)
+	mux.HandleFunc("GET /api/images/{id}", s.handleStatus)
+	mux.Handle("GET /thumbnails/", http.StripPrefix("/thumbnails/",
+		http.FileServerFS(os.DirFS(filepath.Join(s.thumbnailer.Dir, "thumbnails")))))
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	return mux
+}
+
+type uploadResponse struct {
+	ID        int64  `json:"id"`
+	StatusURL string `json:"status_url"`
+}
+
+type statusResponse struct {
+	ID         int64             `json:"id"`
+	Status     string            `json:"status"`
+	Progress   int               `json:"progress"`
+	Attempts   int               `json:"attempts"`
+	Error      string            `json:"error,omitempty"`
+	Original   Upload            `json:"original"`
+	Thumbnails map[string]string `json:"thumbnails,omitempty"`
+}
+
+// handleUpload сохраняет оригинал и ставит задачу в очередь, не дожидаясь
+// обработки: клиент получает 202 и дальше опрашивает status_url
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes+1<<20)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "multipart/form-data expected")
+		return
+	}
+	part, err := nextPart(mr, "image")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "image field is required")
+		return
+	}
+	defer part.Close()
+	
+	up, status, err := s.saveOriginal(part)
+	if err != nil {
+		if status == http.StatusInternalServerError {
+			log.Printf("Ошибка сохранения загрузки: %v", err)
+			writeError(w, status, "internal error")
+			return
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	up.Name = filepath.Base(part.FileName())
+	
+	payload, _ := json.Marshal(up)
+	id, err := s.queue.Enqueue(KindThumbnail, string(payload))
+	if err != nil {
+		os.Remove(s.thumbnailer.originalPath(up.File))
+		log.Printf("Ошибка постановки задачи: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	
+	statusURL := fmt.Sprintf("/api/images/%d", id)
+	w.Header().Set("Location", statusURL)
+	writeJSON(w, http.StatusAccepted, uploadResponse{id, statusURL})
+}
+
+// nextPart пропускает части формы до поля name
+func nextPart(mr *multipart.Reader, name string) (*multipart.Part, error) {
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == name {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// saveOriginal пишет загрузку во временный файл и проверяет заголовок
+// картинки до того, как задача попадет в очередь: битые и чрезмерно
+// большие файлы отклоняются сразу, а не падают потом в воркере.
+// Возвращает HTTP-статус, соответствующий ошибке.
+func (s *Server) saveOriginal(r io.Reader) (Upload, int, error) {
+	dir := filepath.Join(s.thumbnailer.Dir, "originals")
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return Upload{}, http.StatusInternalServerError, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	
+	n, err := io.Copy(tmp, io.LimitReader(r, maxUploadBytes+1))
+	var maxErr *http.MaxBytesError
+	if n > maxUploadBytes || errors.As(err, &maxErr) {
+		return Upload{}, http.StatusRequestEntityTooLarge, errors.New("file is too large")
+	}
+	if err != nil {
+		return Upload{}, http.StatusBadRequest, errors.New("upload interrupted")
+	}
+	
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return Upload{}, http.StatusInternalServerError, err
+	}
+	cfg, format, err := image.DecodeConfig(tmp)
+	if err != nil {
+		return Upload{}, http.StatusUnsupportedMediaType, errors.New("not a supported image")
+	}
+	ext, ok := allowedFormats[format]
+	if !ok {
+		return Upload{}, http.StatusUnsupportedMediaType, errors.New("not a supported image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return Upload{}, http.StatusUnprocessableEntity, errors.New("image dimensions are too large")
+	}
+	
+	name, err := randomName()
+	if err != nil {
+		return Upload{}, http.StatusInternalServerError, err
+	}
+	up := Upload{File: name + ext, Format: format, Width: cfg.Width, Height: cfg.Height}
+	if err := tmp.Close(); err != nil {
+		return Upload{}, http.StatusInternalServerError, err
+	}
+	if err := os.Rename(tmp.Name(), s.thumbnailer.originalPath(up.File)); err != nil {
+		return Upload{}, http.StatusInternalServerError, err
+	}
+	return up, 0, nil
+}
+
+// randomName случайное имя файла: имя от клиента в путь не попадает
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "image not found")
+		return
+	}
+	job, err := s.queue.Get(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && job.Kind != KindThumbnail) {
+		writeError(w, http.StatusNotFound, "image not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка чтения задачи %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	
+	resp := statusResponse{
+		ID:       job.ID,
+		Status:   job.Status,
+		Progress: job.Progress,
+		Attempts: job.Attempts,
+		Error:    job.LastError,
+	}
+	json.Unmarshal([]byte(job.Payload), &resp.Original)
+	resp.Original.File = ""
+	if job.Status == StatusDone {
+		resp.Thumbnails = make(map[string]string, len(s.thumbnailer.Sizes))
+		for _, size := range s.thumbnailer.Sizes {
+			resp.Thumbnails[strconv.Itoa(size)] = fmt.Sprintf("/thumbnails/%d/%d.jpg", id, size)
+		}
+	}
+	
+	// Пока задача не завершена, подсказываем клиенту интервал опроса
+	if job.Status == StatusPending || job.Status == StatusRunning {
+		w.Header().Set("Retry-After", "1")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.queue.Stats()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "jobs": stats})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEnv struct {
+	server      *httptest.Server
+	queue       *Queue
+	thumbnailer *Thumbnailer
+}
+
+// newTestEnv поднимает сервер с воркерами; sizes - размеры миниатюр
+func newTestEnv(t *testing.T, sizes ...int) *testEnv {
+	t.Helper()
+	q := newTestQueue(t)
+	th, err := NewThumbnailer(t.TempDir(), sizes, q)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q.Register(KindThumbnail, th.Handle)
+	q.Backoff = func(int) time.Duration { return 0 }
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		q.Work(ctx, 2, 10*time.Millisecond)
+	}()
+	
+	ts := httptest.NewServer(NewServer(q, th).Routes())
+	t.Cleanup(func() {
+		ts.Close()
+		cancel()
+		wg.Wait()
+	})
+	return &testEnv{ts, q, th}
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func (e *testEnv) upload(t *testing.T, field string, data []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("comment", "поле до файла пропускается")
+	fw, _ := mw.CreateFormFile(field, "../../photo.png")
+	fw.Write(data)
+	mw.Close()
+	
+	resp, err := http.Post(e.server.URL+"/api/images", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// waitStatus опрашивает статус, пока задача не завершится
+func (e *testEnv) waitStatus(t *testing.T, url string) statusResponse {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get(e.server.URL + url)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var status statusResponse
+		json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+		if status.Status == StatusDone || status.Status == StatusFailed {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job did not finish, last status %+v", status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestUploadAndPoll(t *testing.T) {
+	env := newTestEnv(t, 32, 100)
+	
+	resp := env.upload(t, "image", testPNG(t, 200, 120))
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+	var up uploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&up); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Header.Get("Location") != up.StatusURL {
+		t.Errorf("Expected Location %q, got %q", up.StatusURL, resp.Header.Get("Location"))
+	}
+	
+	status := env.waitStatus(t, up.StatusURL)
+	if status.Status != StatusDone || status.Progress != 100 {
+		t.Fatalf("Expected done with progress 100, got %+v", status)
+	}
+	if status.Original.Name != "photo.png" || status.Original.File != "" {
+		t.Errorf("Expected sanitized original name without file, got %+v", status.Original)
+	}
+	if status.Original.Width != 200 || status.Original.Height != 120 {
+		t.Errorf("Expected 200x120, got %dx%d", status.Original.Width, status.Original.Height)
+	}
+	
+	tests := []struct {
+		size         string
+		wantW, wantH int
+	}{
+		{"32", 32, 19},
+		{"100", 100, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			url, ok := status.Thumbnails[tt.size]
+			if !ok {
+				t.Fatalf("Expected thumbnail %s in %v", tt.size, status.Thumbnails)
+			}
+			resp, err := http.Get(env.server.URL + url)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.Header.Get("Content-Type") != "image/jpeg" {
+				t.Errorf("Expected image/jpeg, got %q", resp.Header.Get("Content-Type"))
+			}
+			img, err := jpeg.Decode(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.wantW, tt.wantH, b.Dx(), b.Dy())
+			}
+		})
+	}
+}
+
+func TestUploadRejected(t *testing.T) {
+	// PNG-заголовок с огромными размерами: сам файл крошечный
+	var bomb bytes.Buffer
+	png.Encode(&bomb, image.NewGray(image.Rect(0, 0, 10000, 5000)))
+	
+	tests := []struct {
+		name  string
+		field string
+		data  []byte
+		want  int
+	}{
+		{"not an image", "image", []byte("hello, world"), http.StatusUnsupportedMediaType},
+		{"wrong field", "file", testPNG(t, 10, 10), http.StatusBadRequest},
+		{"too many pixels", "image", bomb.Bytes(), http.StatusUnprocessableEntity},
+		{"too large", "image", make([]byte, maxUploadBytes+1), http.StatusRequestEntityTooLarge},
+	}
+	
+	env := newTestEnv(t, 32)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := env.upload(t, tt.field, tt.data)
+			if resp.StatusCode != tt.want {
+				body, _ := io.ReadAll(resp.Body)
+				t.Errorf("Expected status %d, got %d: %s", tt.want, resp.StatusCode, body)
+			}
+		})
+	}
+	
+	// Отклоненные загрузки не оставляют ни задач, ни файлов
+	stats, _ := env.queue.Stats()
+	if stats[StatusPending]+stats[StatusRunning]+stats[StatusDone] != 0 {
+		t.Errorf("Expected no jobs, got %v", stats)
+	}
+	entries, _ := os.ReadDir(filepath.Join(env.thumbnailer.Dir, "originals"))
+	if len(entries) != 0 {
+		t.Errorf("Expected no originals, got %d files", len(entries))
+	}
+}
+
+func TestStatusNotFound(t *testing.T) {
+	env := newTestEnv(t, 32)
+	otherID, _ := env.queue.Enqueue("other", "")
+	
+	for _, path := range []string{"/api/images/999", "/api/images/abc", "/api/images/" + strconv.FormatInt(otherID, 10)} {
+		resp, err := http.Get(env.server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusNotFound, resp.StatusCode)
+		}
+	}
+}
+
+func TestCorruptImageFails(t *testing.T) {
+	env := newTestEnv(t, 32)
+	
+	// Заголовок целый, данные обрезаны: DecodeConfig проходит,
+	// а полное декодирование в воркере - нет
+	data := testPNG(t, 50, 50)
+	resp := env.upload(t, "image", data[:len(data)/2])
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+	var up uploadResponse
+	json.NewDecoder(resp.Body).Decode(&up)
+	
+	status := env.waitStatus(t, up.StatusURL)
+	if status.Status != StatusFailed || status.Error == "" || status.Thumbnails != nil {
+		t.Errorf("Expected failed job with error, got %+v", status)
+	}
+	if status.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", status.Attempts)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// KindThumbnail тип задачи в очереди
+const KindThumbnail = "thumbnail"
+
+// Upload описание загруженного оригинала; хранится в payload задачи,
+// поэтому отдельная таблица не нужна: ID задачи и есть ID картинки
+type Upload struct {
+	File   string `json:"file,omitempty"` // Имя файла в каталоге originals
+	Name   string `json:"name"`           // Имя, под которым файл прислали
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Thumbnailer делает миниатюры всех размеров для одной картинки
+type Thumbnailer struct {
+	Dir     string // Корень хранилища: originals/ и thumbnails/
+	Sizes   []int
+	Quality int
+	queue   *Queue
+}
+
+// NewThumbnailer создает каталоги хранилища
+func NewThumbnailer(dir string, sizes []int, q *Queue) (*Thumbnailer, error) {
+	for _, sub := range []string{"originals", "thumbnails"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Thumbnailer{Dir: dir, Sizes: sizes, Quality: 85, queue: q}, nil
+}
+
+func (t *Thumbnailer) originalPath(file string) string {
+	return filepath.Join(t.Dir, "originals", file)
+}
+
+func (t *Thumbnailer) thumbnailDir(id int64) string {
+	return filepath.Join(t.Dir, "thumbnails", strconv.FormatInt(id, 10))
+}
+
+// Handle обработчик задачи thumbnail. Повторный запуск безопасен:
+// миниатюры перезаписываются целиком через временный файл и rename,
+// так что после падения посреди записи не остается битых файлов.
+func (t *Thumbnailer) Handle(ctx context.Context, job Job) error {
+	var up Upload
+	if err := json.Unmarshal([]byte(job.Payload), &up); err != nil {
+		return fmt.Errorf("разбор задачи: %w", err)
+	}
+	
+	f, err := os.Open(t.originalPath(up.File))
+	if err != nil {
+		return err
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("декодирование: %w", err)
+	}
+	
+	// Декодирование - заметная часть работы, учитываем его как 10%
+	t.progress(job.ID, 10)
+	
+	dir := t.thumbnailDir(job.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, size := range t.Sizes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		thumb := Resize(src, size)
+		if err := t.writeJPEG(filepath.Join(dir, strconv.Itoa(size)+".jpg"), thumb); err != nil {
+			return err
+		}
+		t.progress(job.ID, 10+90*(i+1)/len(t.Sizes))
+	}
+	return nil
+}
+
+// progress ошибку записи прогресса не считает ошибкой задачи:
+// миниатюры от этого хуже не станут
+func (t *Thumbnailer) progress(id int64, percent int) {
+	if t.queue != nil {
+		t.queue.SetProgress(id, percent)
+	}
+}
+
+func (t *Thumbnailer) writeJPEG(path string, img image.Image) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".thumb-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	
+	if err := jpeg.Encode(tmp, img, &jpeg.Options{Quality: t.Quality}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
\ No newline at end of file
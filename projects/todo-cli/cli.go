@@ -0,0 +1,259 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// usageError ошибка в аргументах: печатается вместе со справкой команды.
+// Пустое сообщение - flag уже напечатал, что не так.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string { return e.msg }
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// App состояние CLI: все зависимости передаются явно, поэтому в тестах
+// команды запускаются с in-memory выводом, временным хранилищем и
+// фиктивными часами
+type App struct {
+	Store Storage
+	Clock Clock
+	Out   io.Writer
+	Err   io.Writer
+}
+
+// Command подкоманда в духе cobra: имя, строка использования, описание
+// и обработчик со своим набором флагов
+type Command struct {
+	Name  string
+	Usage string
+	Short string
+	Run   func(app *App, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []*Command{
+	{
+		Name:  "add",
+		Usage: "add [-due DATE] TITLE...",
+		Short: "добавить задачу",
+		Run:   runAdd,
+	},
+	{
+		Name:  "list",
+		Usage: "list [-all] [-overdue] [-format table|json]",
+		Short: "показать задачи",
+		Run:   runList,
+	},
+	{
+		Name:  "done",
+		Usage: "done ID...",
+		Short: "отметить выполненными",
+		Run:   runDone,
+	},
+	{
+		Name:  "rm",
+		Usage: "rm ID...",
+		Short: "удалить задачи",
+		Run:   runRemove,
+	},
+}
+
+// Run выполняет подкоманду и возвращает код выхода: 0 - успех,
+// 1 - ошибка выполнения, 2 - неверные аргументы (как у flag)
+func (app *App) Run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		app.usage(app.Out)
+		return 0
+	}
+	
+	var cmd *Command
+	for _, c := range commands {
+		if c.Name == args[0] {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(app.Err, "todo: неизвестная команда %q\n\n", args[0])
+		app.usage(app.Err)
+		return 2
+	}
+	
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(app.Err)
+	fs.Usage = func() {
+		fmt.Fprintf(app.Err, "Использование: todo %s\n", cmd.Usage)
+		fs.PrintDefaults()
+	}
+	
+	err := cmd.Run(app, fs, args[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, new(usageError)):
+		if msg := err.Error(); msg != "" {
+			fmt.Fprintf(app.Err, "todo %s: %s\n", cmd.Name, msg)
+		}
+		fs.Usage()
+		return 2
+	default:
+		fmt.Fprintf(app.Err, "todo %s: %v\n", cmd.Name, err)
+		return 1
+	}
+}
+
+func (app *App) usage(w io.Writer) {
+	fmt.Fprintln(w, "Использование: todo [-store json|sqlite] [-file PATH] КОМАНДА [аргументы]")
+	fmt.Fprintln(w, "\nКоманды:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-6s %s\n", c.Name, c.Short)
+	}
+	fmt.Fprintln(w, "\nСправка по команде: todo КОМАНДА -h")
+}
+
+// parseFlags разбирает флаги; ошибку flag переводит в usageError
+// (сообщение flag уже напечатал сам)
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{}
+	}
+	return nil
+}
+
+func runAdd(app *App, fs *flag.FlagSet, args []string) error {
+	due := fs.String("due", "", "срок: today, tomorrow, +3d, +2w, mon..sun, 2026-03-01")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	title := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if title == "" {
+		return usageErrorf("нужен текст задачи")
+	}
+	
+	now := app.Clock.Now()
+	task := Task{Title: title, CreatedAt: now}
+	if *due != "" {
+		d, err := ParseDue(*due, now)
+		if err != nil {
+			return usageError{err.Error()}
+		}
+		task.Due = d
+	}
+	
+	task, err := app.Store.Add(task)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.Out, "Добавлена задача %d: %s", task.ID, task.Title)
+	if !task.Due.IsZero() {
+		fmt.Fprintf(app.Out, " (срок %s)", task.Due.Format(time.DateOnly))
+	}
+	fmt.Fprintln(app.Out)
+	return nil
+}
+
+func runList(app *App, fs *flag.FlagSet, args []string) error {
+	all := fs.Bool("all", false, "включая выполненные")
+	overdue := fs.Bool("overdue", false, "только просроченные")
+	format := fs.String("format", "table", "формат вывода: table или json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	
+	render, ok := formats[*format]
+	if !ok {
+		return usageErrorf("неизвестный формат %q", *format)
+	}
+	
+	now := app.Clock.Now()
+	filter := Filter{IncludeDone: *all}
+	if *overdue {
+		filter.OverdueAt = now
+	}
+	tasks, err := app.Store.List(filter)
+	if err != nil {
+		return err
+	}
+	return render(app.Out, tasks, now)
+}
+
+// parseIDs разбирает список ID из аргументов
+func parseIDs(args []string) ([]int64, error) {
+	if len(args) == 0 {
+		return nil, usageErrorf("нужен хотя бы один ID")
+	}
+	ids := make([]int64, len(args))
+	for i, a := range args {
+		id, err := strconv.ParseInt(a, 10, 64)
+		if err != nil || id < 1 {
+			return nil, usageErrorf("неверный ID %q", a)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// forEachID применяет fn к каждому ID и не останавливается на первой
+// ошибке: `todo done 1 99 2` отметит 1 и 2 и сообщит про 99
+func forEachID(app *App, args []string, fn func(id int64) error) error {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			errs = append(errs, fmt.Errorf("задача %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runDone(app *App, fs *flag.FlagSet, args []string) error {
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	return forEachID(app, fs.Args(), func(id int64) error {
+		task, err := app.Store.Get(id)
+		if err != nil {
+			return err
+		}
+		if task.Done() {
+			fmt.Fprintf(app.Out, "Задача %d уже выполнена\n", id)
+			return nil
+		}
+		task.DoneAt = app.Clock.Now()
+		if err := app.Store.Update(task); err != nil {
+			return err
+		}
+		fmt.Fprintf(app.Out, "Выполнена задача %d: %s\n", id, task.Title)
+		return nil
+	})
+}
+
+func runRemove(app *App, fs *flag.FlagSet, args []string) error {
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	return forEachID(app, fs.Args(), func(id int64) error {
+		if err := app.Store.Delete(id); err != nil {
+			return err
+		}
+		fmt.Fprintf(app.Out, "Удалена задача %d\n", id)
+		return nil
+	})
+}
\ No newline at end of file
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCLI struct {
+	app      *App
+	clock    *fakeClock
+	out, err *bytes.Buffer
+}
+
+func newTestCLI(t *testing.T) *testCLI {
+	t.Helper()
+	clock := &fakeClock{now: wednesday}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	store := NewJSONStore(filepath.Join(t.TempDir(), "todo.json"))
+	return &testCLI{&App{Store: store, Clock: clock, Out: out, Err: errOut}, clock, out, errOut}
+}
+
+// run выполняет команду и проверяет код выхода
+func (c *testCLI) run(t *testing.T, wantCode int, args ...string) string {
+	t.Helper()
+	c.out.Reset()
+	c.err.Reset()
+	if code := c.app.Run(args); code != wantCode {
+		t.Fatalf("todo %s: expected exit code %d, got %d\nstdout: %s\nstderr: %s",
+			strings.Join(args, " "), wantCode, code, c.out, c.err)
+	}
+	return c.out.String()
+}
+
+func TestAddAndList(t *testing.T) {
+	c := newTestCLI(t)
+	
+	out := c.run(t, 0, "add", "-due", "tomorrow", "Купить", "молоко")
+	if !strings.Contains(out, "Добавлена задача 1: Купить молоко (срок 2026-03-05)") {
+		t.Errorf("Unexpected output: %s", out)
+	}
+	c.run(t, 0, "add", "Без срока")
+	
+	out = c.run(t, 0, "list")
+	for _, want := range []string{"ID", "Купить молоко", "завтра", "Без срока", "открыта"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Table must contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDoneAndRemove(t *testing.T) {
+	c := newTestCLI(t)
+	c.run(t, 0, "add", "a")
+	c.run(t, 0, "add", "b")
+	c.run(t, 0, "add", "c")
+	
+	c.run(t, 0, "done", "1", "3")
+	out := c.run(t, 0, "done", "1")
+	if !strings.Contains(out, "уже выполнена") {
+		t.Errorf("Unexpected output: %s", out)
+	}
+	
+	out = c.run(t, 0, "list")
+	if strings.Contains(out, " a\n") || !strings.Contains(out, " b\n") {
+		t.Errorf("Done tasks must be hidden by default:\n%s", out)
+	}
+	out = c.run(t, 0, "list", "-all")
+	if strings.Count(out, "готово") != 2 {
+		t.Errorf("Expected two done tasks with -all:\n%s", out)
+	}
+	
+	// Несуществующий ID не мешает удалить остальные
+	c.run(t, 1, "rm", "2", "99")
+	if !strings.Contains(c.err.String(), "задача 99") {
+		t.Errorf("Expected error about task 99, got: %s", c.err)
+	}
+	if out := c.run(t, 0, "list"); !strings.Contains(out, "Задач нет") {
+		t.Errorf("Expected empty list, got:\n%s", out)
+	}
+}
+
+func TestOverdueWithFakeClock(t *testing.T) {
+	c := newTestCLI(t)
+	c.run(t, 0, "add", "-due", "today", "Сдать отчет")
+	c.run(t, 0, "add", "-due", "+2d", "Позвонить")
+	
+	if out := c.run(t, 0, "list", "-overdue"); !strings.Contains(out, "Задач нет") {
+		t.Errorf("Nothing is overdue yet:\n%s", out)
+	}
+	
+	c.clock.Advance(24 * time.Hour)
+	out := c.run(t, 0, "list")
+	if !strings.Contains(out, "просрочено") || !strings.Contains(out, "вчера") || !strings.Contains(out, "завтра") {
+		t.Errorf("Expected overdue and relative dates:\n%s", out)
+	}
+	out = c.run(t, 0, "list", "-overdue")
+	if !strings.Contains(out, "Сдать отчет") || strings.Contains(out, "Позвонить") {
+		t.Errorf("Expected only the overdue task:\n%s", out)
+	}
+}
+
+func TestListJSON(t *testing.T) {
+	c := newTestCLI(t)
+	c.run(t, 0, "add", "-due", "today", "a")
+	c.run(t, 0, "add", "b")
+	c.run(t, 0, "done", "2")
+	c.clock.Advance(48 * time.Hour)
+	
+	var tasks []struct {
+		ID      int64  `json:"id"`
+		Title   string `json:"title"`
+		Due     string `json:"due"`
+		Done    bool   `json:"done"`
+		Overdue bool   `json:"overdue"`
+	}
+	out := c.run(t, 0, "list", "-all", "-format", "json")
+	if err := json.Unmarshal([]byte(out), &tasks); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	if len(tasks) != 2 || !tasks[0].Overdue || tasks[0].Done || !tasks[1].Done || tasks[1].Due != "" {
+		t.Errorf("Unexpected JSON: %+v", tasks)
+	}
+	
+	// Пустой список - это [], а не null: скриптам так проще
+	c.run(t, 0, "rm", "1", "2")
+	if out := strings.TrimSpace(c.run(t, 0, "list", "-format", "json")); out != "[]" {
+		t.Errorf("Expected [], got %s", out)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	c := newTestCLI(t)
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"add"}, "нужен текст задачи"},
+		{[]string{"add", "-due", "someday", "x"}, "invalid due date"},
+		{[]string{"add", "-unknown", "x"}, "flag provided but not defined"},
+		{[]string{"list", "-format", "xml"}, "неизвестный формат"},
+		{[]string{"done"}, "нужен хотя бы один ID"},
+		{[]string{"rm", "abc"}, "неверный ID"},
+		{[]string{"frobnicate"}, "неизвестная команда"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			c.run(t, 2, tt.args...)
+			if !strings.Contains(c.err.String(), tt.want) {
+				t.Errorf("Expected stderr to contain %q, got:\n%s", tt.want, c.err)
+			}
+		})
+	}
+}
+
+func TestHelp(t *testing.T) {
+	c := newTestCLI(t)
+	if out := c.run(t, 0); !strings.Contains(out, "Команды:") {
+		t.Errorf("Expected command list, got:\n%s", out)
+	}
+	c.run(t, 0, "add", "-h")
+	if !strings.Contains(c.err.String(), "todo add [-due DATE]") {
+		t.Errorf("Expected add usage, got:\n%s", c.err)
+	}
+}
\ No newline at end of file
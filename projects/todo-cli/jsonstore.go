@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// JSONStore хранит задачи в одном JSON-файле. Каждая операция читает
+// файл целиком и переписывает его - для сотен задач в CLI это
+// мгновенно, а файл можно открыть и поправить руками.
+type JSONStore struct {
+	path string
+}
+
+type jsonFile struct {
+	NextID int64  `json:"next_id"`
+	Tasks  []Task `json:"tasks"`
+}
+
+// NewJSONStore создает хранилище; файл появится при первой записи
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+func (s *JSONStore) load() (*jsonFile, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &jsonFile{NextID: 1}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f jsonFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// save пишет во временный файл и переименовывает: rename атомарен,
+// и при сбое посреди записи старый файл остается целым
+func (s *JSONStore) save(f *jsonFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".todo-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // После успешного rename файла уже нет
+	
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *JSONStore) Add(t Task) (Task, error) {
+	f, err := s.load()
+	if err != nil {
+		return Task{}, err
+	}
+	t.ID = f.NextID
+	f.NextID++
+	f.Tasks = append(f.Tasks, t)
+	return t, s.save(f)
+}
+
+func (s *JSONStore) Get(id int64) (Task, error) {
+	f, err := s.load()
+	if err != nil {
+		return Task{}, err
+	}
+	for _, t := range f.Tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return Task{}, ErrNotFound
+}
+
+func (s *JSONStore) List(filter Filter) ([]Task, error) {
+	f, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	result := []Task{}
+	for _, t := range f.Tasks {
+		if filter.Match(t) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (s *JSONStore) Update(t Task) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(f.Tasks, func(x Task) bool { return x.ID == t.ID })
+	if i < 0 {
+		return ErrNotFound
+	}
+	f.Tasks[i] = t
+	return s.save(f)
+}
+
+func (s *JSONStore) Delete(id int64) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(f.Tasks, func(x Task) bool { return x.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	f.Tasks = slices.Delete(f.Tasks, i, i+1)
+	return s.save(f)
+}
+
+func (s *JSONStore) Close() error {
+	return nil
+}
\ No newline at end of file
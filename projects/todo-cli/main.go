@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Менеджер задач в командной строке - итоговый проект:
+//   cli.go         - подкоманды на flag.FlagSet (как в cobra, но без зависимостей)
+//   task.go        - модель, интерфейс Storage и Clock, разбор сроков
+//   jsonstore.go   - бэкенд на JSON-файле с атомарной записью
+//   sqlitestore.go - бэкенд на SQLite (examples/database)
+//   output.go      - таблица через text/tabwriter и JSON
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Примеры:
+//   go run . add -due tomorrow Купить молоко
+//   go run . add -due +3d Подготовить доклад
+//   go run . list
+//   go run . done 1
+//   go run . list -all -format json
+//   go run . -store sqlite -file todo.db list
+//
+// Тесты: go test ./...
+
+func defaultFile(store string) string {
+	name := ".todo.json"
+	if store == "sqlite" {
+		name = ".todo.db"
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, name)
+	}
+	return name
+}
+
+func openStore(kind, path string) (Storage, error) {
+	switch kind {
+	case "json":
+		return NewJSONStore(path), nil
+	case "sqlite":
+		return NewSQLiteStore(path)
+	}
+	return nil, fmt.Errorf("unknown store %q: use json or sqlite", kind)
+}
+
+func main() {
+	// Глобальные флаги идут до подкоманды: todo -store sqlite list -all
+	global := flag.NewFlagSet("todo", flag.ExitOnError)
+	kind := global.String("store", envOr("TODO_STORE", "json"), "хранилище: json или sqlite (TODO_STORE)")
+	file := global.String("file", os.Getenv("TODO_FILE"), "файл хранилища (TODO_FILE), по умолчанию ~/.todo.json или ~/.todo.db")
+	global.Parse(os.Args[1:])
+	
+	if *file == "" {
+		*file = defaultFile(*kind)
+	}
+	store, err := openStore(*kind, *file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "todo:", err)
+		os.Exit(1)
+	}
+	
+	app := &App{Store: store, Clock: realClock{}, Out: os.Stdout, Err: os.Stderr}
+	code := app.Run(global.Args())
+	store.Close()
+	os.Exit(code)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
\ No newline at end of file
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// formats способы вывода списка задач; новый формат - одна запись здесь
+var formats = map[string]func(w io.Writer, tasks []Task, now time.Time) error{
+	"table": renderTable,
+	"json":  renderJSON,
+}
+
+// renderTable выводит таблицу. tabwriter выравнивает колонки по
+// самой длинной ячейке; ширина считается в рунах, поэтому кириллица
+// не ломает выравнивание.
+func renderTable(w io.Writer, tasks []Task, now time.Time) error {
+	if len(tasks) == 0 {
+		_, err := fmt.Fprintln(w, "Задач нет")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tСТАТУС\tСРОК\tЗАДАЧА")
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", t.ID, status(t, now), dueLabel(t, now), t.Title)
+	}
+	return tw.Flush()
+}
+
+func status(t Task, now time.Time) string {
+	switch {
+	case t.Done():
+		return "готово"
+	case t.Overdue(now):
+		return "просрочено"
+	}
+	return "открыта"
+}
+
+// dueLabel показывает близкие сроки словами, дальние - датой
+func dueLabel(t Task, now time.Time) string {
+	if t.Due.IsZero() {
+		return "-"
+	}
+	days := int(startOfDay(t.Due).Sub(startOfDay(now)).Hours() / 24)
+	switch days {
+	case -1:
+		return "вчера"
+	case 0:
+		return "сегодня"
+	case 1:
+		return "завтра"
+	}
+	return t.Due.Format(time.DateOnly)
+}
+
+// taskJSON представление для -format json: стабильные поля для скриптов
+// и вычисленные статусы, чтобы jq не пересчитывал просрочку сам
+type taskJSON struct {
+	Task
+	Done    bool `json:"done"`
+	Overdue bool `json:"overdue"`
+}
+
+func renderJSON(w io.Writer, tasks []Task, now time.Time) error {
+	out := make([]taskJSON, len(tasks))
+	for i, t := range tasks {
+		out[i] = taskJSON{t, t.Done(), t.Overdue(now)}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
\ No newline at end of file
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SQLiteStore хранит задачи в SQLite
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore открывает БД и создает таблицу
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`
+	CREATE TABLE IF NOT EXISTS tasks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL,
+		due INTEGER,        -- Unix-секунды, NULL - без срока
+		created_at INTEGER NOT NULL,
+		done_at INTEGER     -- NULL - не выполнена
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Время хранится числом: так не зависим от того, как драйвер
+// форматирует TIMESTAMP, а сравнения в SQL - обычные числовые
+
+func toUnix(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
+
+func fromUnix(n sql.NullInt64) time.Time {
+	if !n.Valid {
+		return time.Time{}
+	}
+	return time.Unix(n.Int64, 0)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(s scanner) (Task, error) {
+	var t Task
+	var due, created, done sql.NullInt64
+	if err := s.Scan(&t.ID, &t.Title, &due, &created, &done); err != nil {
+		return Task{}, err
+	}
+	t.Due, t.CreatedAt, t.DoneAt = fromUnix(due), fromUnix(created), fromUnix(done)
+	return t, nil
+}
+
+func (s *SQLiteStore) Add(t Task) (Task, error) {
+	res, err := s.db.Exec(`INSERT INTO tasks (title, due, created_at, done_at) VALUES (?, ?, ?, ?)`,
+		t.Title, toUnix(t.Due), toUnix(t.CreatedAt), toUnix(t.DoneAt))
+	if err != nil {
+		return Task{}, err
+	}
+	t.ID, err = res.LastInsertId()
+	return t, err
+}
+
+func (s *SQLiteStore) Get(id int64) (Task, error) {
+	t, err := scanTask(s.db.QueryRow(`SELECT id, title, due, created_at, done_at FROM tasks WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Task{}, ErrNotFound
+	}
+	return t, err
+}
+
+func (s *SQLiteStore) List(filter Filter) ([]Task, error) {
+	rows, err := s.db.Query(`SELECT id, title, due, created_at, done_at FROM tasks ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	// Фильтр общий с JSONStore, поэтому применяется в Go: правило
+	// просрочки живет в одном месте (Task.Overdue), а не дублируется в SQL
+	result := []Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Match(t) {
+			result = append(result, t)
+		}
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteStore) Update(t Task) error {
+	res, err := s.db.Exec(`UPDATE tasks SET title = ?, due = ?, done_at = ? WHERE id = ?`,
+		t.Title, toUnix(t.Due), toUnix(t.DoneAt), t.ID)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res)
+}
+
+func (s *SQLiteStore) Delete(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return expectOneRow(res)
+}
+
+func expectOneRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// backends фабрики хранилищ: контрактные тесты прогоняются на каждом
+var backends = map[string]func(t *testing.T) Storage{
+	"json": func(t *testing.T) Storage {
+		return NewJSONStore(filepath.Join(t.TempDir(), "todo.json"))
+	},
+	"sqlite": func(t *testing.T) Storage {
+		s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "todo.db"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return s
+	},
+}
+
+func forEachBackend(t *testing.T, test func(t *testing.T, s Storage)) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			t.Cleanup(func() { s.Close() })
+			test(t, s)
+		})
+	}
+}
+
+func TestStorageContract(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s Storage) {
+		a, err := s.Add(Task{Title: "первая", CreatedAt: wednesday, Due: date(2026, 3, 5)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		b, _ := s.Add(Task{Title: "вторая", CreatedAt: wednesday})
+		if a.ID == b.ID || a.ID == 0 {
+			t.Fatalf("Expected distinct non-zero IDs, got %d and %d", a.ID, b.ID)
+		}
+		
+		got, err := s.Get(a.ID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.Title != "первая" || !got.Due.Equal(a.Due) || !got.CreatedAt.Equal(wednesday) || got.Done() {
+			t.Errorf("Expected %+v, got %+v", a, got)
+		}
+		
+		got.DoneAt = wednesday.Add(time.Hour)
+		if err := s.Update(got); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		open, _ := s.List(Filter{})
+		all, _ := s.List(Filter{IncludeDone: true})
+		if len(open) != 1 || open[0].ID != b.ID || len(all) != 2 || all[0].ID != a.ID {
+			t.Errorf("Unexpected lists: open %+v, all %+v", open, all)
+		}
+		
+		if err := s.Delete(b.ID); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := s.Get(b.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound after delete, got %v", err)
+		}
+		
+		// ID не переиспользуются после удаления
+		c, _ := s.Add(Task{Title: "третья", CreatedAt: wednesday})
+		if c.ID <= b.ID {
+			t.Errorf("Expected ID greater than %d, got %d", b.ID, c.ID)
+		}
+	})
+}
+
+func TestStorageNotFound(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s Storage) {
+		if _, err := s.Get(42); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get: expected ErrNotFound, got %v", err)
+		}
+		if err := s.Update(Task{ID: 42}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Update: expected ErrNotFound, got %v", err)
+		}
+		if err := s.Delete(42); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Delete: expected ErrNotFound, got %v", err)
+		}
+		if tasks, err := s.List(Filter{}); err != nil || len(tasks) != 0 {
+			t.Errorf("Expected empty list, got %v, %v", tasks, err)
+		}
+	})
+}
+
+func TestStorageOverdueFilter(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s Storage) {
+		s.Add(Task{Title: "вчера", CreatedAt: wednesday, Due: date(2026, 3, 3)})
+		s.Add(Task{Title: "сегодня", CreatedAt: wednesday, Due: date(2026, 3, 4)})
+		s.Add(Task{Title: "без срока", CreatedAt: wednesday})
+		
+		tasks, err := s.List(Filter{OverdueAt: wednesday})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].Title != "вчера" {
+			t.Errorf("Expected only overdue task, got %+v", tasks)
+		}
+	})
+}
\ No newline at end of file
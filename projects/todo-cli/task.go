@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound задача не найдена
+var ErrNotFound = errors.New("task not found")
+
+// Task задача
+type Task struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Due       time.Time `json:"due,omitzero"` // Нулевое значение - без срока
+	CreatedAt time.Time `json:"created_at"`
+	DoneAt    time.Time `json:"done_at,omitzero"`
+}
+
+// Done проверяет, выполнена ли задача
+func (t Task) Done() bool {
+	return !t.DoneAt.IsZero()
+}
+
+// Overdue проверяет, просрочена ли невыполненная задача. Срок - дата
+// без времени: задача на сегодня просрочена только с завтрашнего дня.
+func (t Task) Overdue(now time.Time) bool {
+	return !t.Done() && !t.Due.IsZero() && t.Due.Before(startOfDay(now))
+}
+
+// Filter условия выборки
+type Filter struct {
+	IncludeDone bool
+	OverdueAt   time.Time // Не нулевое - только просроченные на этот момент
+}
+
+// Match проверяет задачу по фильтру
+func (f Filter) Match(t Task) bool {
+	if t.Done() && !f.IncludeDone {
+		return false
+	}
+	if !f.OverdueAt.IsZero() && !t.Overdue(f.OverdueAt) {
+		return false
+	}
+	return true
+}
+
+// Storage хранилище задач. Бэкенды (JSON-файл и SQLite) взаимозаменяемы:
+// команды CLI знают только об интерфейсе, а общий контрактный тест
+// проверяет, что бэкенды ведут себя одинаково.
+type Storage interface {
+	Add(t Task) (Task, error)
+	Get(id int64) (Task, error)
+	List(f Filter) ([]Task, error)
+	Update(t Task) error
+	Delete(id int64) error
+	Close() error
+}
+
+// Clock источник времени; в тестах подменяется, чтобы проверять сроки
+// и просрочку без ожидания и без зависимости от текущей даты
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// ParseDue разбирает срок относительно now:
+//
+//	today, tomorrow      сегодня, завтра
+//	+3d, +2w             через 3 дня, через 2 недели
+//	mon ... sun          ближайший такой день недели (не сегодня)
+//	2026-03-01           конкретная дата
+func ParseDue(s string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	s = strings.ToLower(strings.TrimSpace(s))
+	
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	if wd, ok := weekdays[s]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+	if rest, ok := strings.CutPrefix(s, "+"); ok && len(rest) >= 2 {
+		n, err := strconv.Atoi(rest[:len(rest)-1])
+		if err == nil && n >= 0 {
+			switch rest[len(rest)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, now.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid due date %q: use today, tomorrow, +Nd, +Nw, mon..sun or YYYY-MM-DD", s)
+}
\ No newline at end of file
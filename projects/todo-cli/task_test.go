@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock часы, которые стоят на месте, пока их не передвинут
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// Среда, 4 марта 2026, 15:30
+var wednesday = time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseDue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"today", date(2026, 3, 4)},
+		{"Tomorrow", date(2026, 3, 5)},
+		{"+0d", date(2026, 3, 4)},
+		{"+3d", date(2026, 3, 7)},
+		{"+30d", date(2026, 4, 3)},
+		{"+2w", date(2026, 3, 18)},
+		{"thu", date(2026, 3, 5)},
+		{"mon", date(2026, 3, 9)},
+		{"wed", date(2026, 3, 11)}, // Сегодня среда - значит, следующая
+		{"2026-12-31", date(2026, 12, 31)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDue(tt.in, wednesday)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseDueInvalid(t *testing.T) {
+	for _, in := range []string{"", "soon", "+d", "+3m", "+-1d", "2026-13-01", "31.12.2026"} {
+		if _, err := ParseDue(in, wednesday); err == nil {
+			t.Errorf("ParseDue(%q): expected error", in)
+		}
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	clock := &fakeClock{now: wednesday}
+	task := Task{Title: "x", Due: date(2026, 3, 4)}
+	
+	if task.Overdue(clock.Now()) {
+		t.Error("Task due today must not be overdue today")
+	}
+	clock.Advance(8*time.Hour + 29*time.Minute) // 23:59
+	if task.Overdue(clock.Now()) {
+		t.Error("Task due today must not be overdue at 23:59")
+	}
+	clock.Advance(time.Minute) // Полночь
+	if !task.Overdue(clock.Now()) {
+		t.Error("Task must be overdue the next day")
+	}
+	
+	task.DoneAt = clock.Now()
+	if task.Overdue(clock.Now()) {
+		t.Error("Done task is never overdue")
+	}
+	if (Task{}).Overdue(clock.Now()) {
+		t.Error("Task without due date is never overdue")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// HitKey переходы агрегируются по ссылке и дню (UTC)
+type HitKey struct {
+	LinkID uint64
+	Day    string
+}
+
+// HitBatch накопленные переходы
+type HitBatch struct {
+	Count int64
+	Last  time.Time
+}
+
+type hit struct {
+	linkID uint64
+	at     time.Time
+}
+
+// hitStore часть Store, нужная аналитике; в тестах подменяется
+type hitStore interface {
+	AddHits(ctx context.Context, hits map[HitKey]HitBatch) error
+}
+
+// Analytics считает переходы в фоне. Редирект не ждет записи в БД:
+// он кладет событие в буферизованный канал и сразу отвечает, а воркер
+// агрегирует события в памяти и сбрасывает их раз в interval одной
+// транзакцией. Цена - при падении процесса теряются переходы за
+// последний interval; для статистики это приемлемо, для денег - нет.
+type Analytics struct {
+	store    hitStore
+	events   chan hit
+	interval time.Duration
+	now      func() time.Time
+
+	dropped atomic.Int64 // События, не влезшие в буфер
+	flushed atomic.Int64 // События, записанные в БД
+}
+
+// NewAnalytics создает аналитику с буфером на buffer событий
+func NewAnalytics(store hitStore, buffer int, interval time.Duration) *Analytics {
+	return &Analytics{
+		store:    store,
+		events:   make(chan hit, buffer),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Record регистрирует переход и никогда не блокирует: если воркер не
+// успевает и буфер полон, событие отбрасывается и считается в dropped.
+// Медленная БД не должна замедлять редиректы.
+func (a *Analytics) Record(linkID uint64) {
+	select {
+	case a.events <- hit{linkID, a.now()}:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Stats возвращает число записанных и отброшенных событий
+func (a *Analytics) Stats() (flushed, dropped int64) {
+	return a.flushed.Load(), a.dropped.Load()
+}
+
+// Run агрегирует события до отмены ctx. Перед выходом дочитывает буфер
+// и сбрасывает остаток, поэтому при graceful shutdown переходы не
+// теряются: сначала останавливают HTTP-сервер, затем отменяют ctx
+// и ждут возврата Run.
+func (a *Analytics) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	
+	pending := make(map[HitKey]HitBatch)
+	add := func(h hit) {
+		key := HitKey{h.linkID, h.at.UTC().Format(time.DateOnly)}
+		b := pending[key]
+		b.Count++
+		if h.at.After(b.Last) {
+			b.Last = h.at
+		}
+		pending[key] = b
+	}
+	
+	for {
+		select {
+		case h := <-a.events:
+			add(h)
+		case <-ticker.C:
+			a.flush(context.Background(), pending)
+		case <-ctx.Done():
+		drain:
+			for {
+				select {
+				case h := <-a.events:
+					add(h)
+				default:
+					break drain
+				}
+			}
+			// ctx уже отменен - для финальной записи нужен свой таймаут
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			a.flush(flushCtx, pending)
+			cancel()
+			return
+		}
+	}
+}
+
+// flush записывает pending и очищает его. При ошибке данные остаются
+// в pending и уйдут со следующим сбросом.
+func (a *Analytics) flush(ctx context.Context, pending map[HitKey]HitBatch) {
+	if len(pending) == 0 {
+		return
+	}
+	if err := a.store.AddHits(ctx, pending); err != nil {
+		log.Printf("Ошибка записи статистики (%d ключей отложено): %v", len(pending), err)
+		return
+	}
+	var n int64
+	for _, b := range pending {
+		n += b.Count
+	}
+	a.flushed.Add(n)
+	clear(pending)
+}
\ No newline at end of file
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHitStore запоминает сброшенные переходы и умеет падать
+type fakeHitStore struct {
+	mu    sync.Mutex
+	total map[uint64]int64
+	calls int
+	fail  bool
+}
+
+func (f *fakeHitStore) AddHits(_ context.Context, hits map[HitKey]HitBatch) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.fail {
+		return errors.New("database is locked")
+	}
+	if f.total == nil {
+		f.total = make(map[uint64]int64)
+	}
+	for k, b := range hits {
+		f.total[k.LinkID] += b.Count
+	}
+	return nil
+}
+
+func (f *fakeHitStore) get(id uint64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.total[id]
+}
+
+func TestAnalyticsFlushesOnShutdown(t *testing.T) {
+	store := &fakeHitStore{}
+	// Интервал больше времени теста: все данные уходят финальным сбросом
+	a := NewAnalytics(store, 1000, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+	
+	for range 300 {
+		a.Record(1)
+	}
+	for range 200 {
+		a.Record(2)
+	}
+	cancel()
+	<-done
+	
+	if store.get(1) != 300 || store.get(2) != 200 {
+		t.Errorf("Expected 300 and 200 hits, got %d and %d", store.get(1), store.get(2))
+	}
+	if flushed, dropped := a.Stats(); flushed != 500 || dropped != 0 {
+		t.Errorf("Expected 500 flushed and 0 dropped, got %d and %d", flushed, dropped)
+	}
+	// Агрегация: один вызов на все 500 событий
+	if store.calls != 1 {
+		t.Errorf("Expected 1 AddHits call, got %d", store.calls)
+	}
+}
+
+func TestAnalyticsDropsWhenFull(t *testing.T) {
+	a := NewAnalytics(&fakeHitStore{}, 10, time.Hour)
+	// Run не запущен - буфер никто не читает, Record не должен блокироваться
+	for range 25 {
+		a.Record(1)
+	}
+	if _, dropped := a.Stats(); dropped != 15 {
+		t.Errorf("Expected 15 dropped, got %d", dropped)
+	}
+}
+
+func TestAnalyticsRetriesAfterError(t *testing.T) {
+	store := &fakeHitStore{fail: true}
+	a := NewAnalytics(store, 100, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+	
+	for range 7 {
+		a.Record(5)
+	}
+	time.Sleep(50 * time.Millisecond) // Несколько неудачных сбросов
+	store.mu.Lock()
+	store.fail = false
+	store.mu.Unlock()
+	
+	cancel()
+	<-done
+	if store.get(5) != 7 {
+		t.Errorf("Expected 7 hits after retry, got %d", store.get(5))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// Короткий код - ID записи в base62: 62^6 ~ 56 миллиардов ссылок
+// укладываются в 6 символов. Алфавит без спецсимволов, поэтому код
+// не нужно экранировать в URL.
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// ErrInvalidCode код содержит символ вне алфавита или слишком длинный
+var ErrInvalidCode = errors.New("invalid short code")
+
+// EncodeBase62 кодирует число в base62
+func EncodeBase62(n uint64) string {
+	if n == 0 {
+		return "0"
+	}
+	var buf [11]byte // 62^11 > 2^64
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(buf[i:])
+}
+
+// DecodeBase62 декодирует base62 в число
+func DecodeBase62(s string) (uint64, error) {
+	if s == "" || len(s) > 11 {
+		return 0, ErrInvalidCode
+	}
+	var n uint64
+	for _, c := range s {
+		d := strings.IndexRune(base62Alphabet, c)
+		if d < 0 {
+			return 0, ErrInvalidCode
+		}
+		next := n*62 + uint64(d)
+		if next/62 != n { // Переполнение uint64
+			return 0, ErrInvalidCode
+		}
+		n = next
+	}
+	return n, nil
+}
+
+// Последовательные ID дают последовательные коды: по /abc легко
+// перебрать соседние ссылки. Перемешиваем ID обратимым умножением
+// по модулю 2^k - биекция, поэтому коды остаются уникальными и
+// короткими, но соседние ID дают непохожие коды.
+const (
+	idBits    = 36 // 2^36 ~ 68 миллиардов ссылок, код - до 7 символов
+	idMask    = 1<<idBits - 1
+	idMul     = 0x9E3779B1  // Нечетное - значит, обратимо по модулю 2^k
+	idMulInv  = 0xB0E8B2F51 // idMul * idMulInv = 1 (mod 2^36)
+	idXorSalt = 0x5DEECE66D & idMask
+)
+
+// obfuscateID перемешивает ID в пределах idBits
+func obfuscateID(id uint64) uint64 {
+	return ((id ^ idXorSalt) * idMul) & idMask
+}
+
+// revealID обратное к obfuscateID
+func revealID(x uint64) uint64 {
+	return ((x * idMulInv) & idMask) ^ idXorSalt
+}
\ No newline at end of file
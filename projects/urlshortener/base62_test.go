@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestBase62RoundTrip(t *testing.T) {
+	values := []uint64{0, 1, 61, 62, 3843, 3844, math.MaxUint32, math.MaxUint64}
+	for range 1000 {
+		values = append(values, rand.Uint64())
+	}
+	for _, n := range values {
+		s := EncodeBase62(n)
+		got, err := DecodeBase62(s)
+		if err != nil {
+			t.Fatalf("DecodeBase62(%q): unexpected error: %v", s, err)
+		}
+		if got != n {
+			t.Errorf("Round trip %d -> %q -> %d", n, s, got)
+		}
+	}
+}
+
+func TestBase62Known(t *testing.T) {
+	tests := map[uint64]string{0: "0", 61: "Z", 62: "10", 3843: "ZZ"}
+	for n, want := range tests {
+		if got := EncodeBase62(n); got != want {
+			t.Errorf("EncodeBase62(%d): expected %q, got %q", n, want, got)
+		}
+	}
+}
+
+func TestBase62Invalid(t *testing.T) {
+	for _, s := range []string{"", "abc-d", "привет", "ZZZZZZZZZZZ", "zzzzzzzzzzzz"} {
+		if _, err := DecodeBase62(s); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("DecodeBase62(%q): expected ErrInvalidCode, got %v", s, err)
+		}
+	}
+}
+
+func TestObfuscateIDIsBijection(t *testing.T) {
+	seen := make(map[uint64]bool)
+	for id := uint64(1); id <= 100000; id++ {
+		x := obfuscateID(id)
+		if x > idMask {
+			t.Fatalf("obfuscateID(%d) = %d exceeds %d bits", id, x, idBits)
+		}
+		if seen[x] {
+			t.Fatalf("obfuscateID(%d) collides", id)
+		}
+		seen[x] = true
+		if back := revealID(x); back != id {
+			t.Fatalf("revealID(obfuscateID(%d)) = %d", id, back)
+		}
+	}
+	
+	// Соседние ID не должны давать соседние коды
+	if codeFor(1)[:2] == codeFor(2)[:2] && codeFor(2)[:2] == codeFor(3)[:2] {
+		t.Errorf("Codes look sequential: %s %s %s", codeFor(1), codeFor(2), codeFor(3))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// urlCache LRU-кэш code -> URL для редиректов. Ссылки не меняются после
+// создания, поэтому инвалидация не нужна, а популярные ссылки почти
+// всегда отдаются из памяти без запроса к БД.
+// Урезанная копия examples/http-server/usercache.go.
+type urlCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List // Голова - самый свежий, хвост - на вытеснение
+
+	hits, misses int64
+}
+
+type cacheEntry struct {
+	code, url string
+}
+
+func newURLCache(capacity int) *urlCache {
+	return &urlCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get возвращает URL из кэша
+func (c *urlCache) Get(code string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[code]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		return el.Value.(cacheEntry).url, true
+	}
+	c.misses++
+	return "", false
+}
+
+// Add кладет URL в кэш, вытесняя самый давний
+func (c *urlCache) Add(code, url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[code]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(cacheEntry).code)
+	}
+	c.items[code] = c.order.PushFront(cacheEntry{code, url})
+}
+
+// Stats возвращает число попаданий и промахов
+func (c *urlCache) Stats() (hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
\ No newline at end of file
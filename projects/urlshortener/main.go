@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Сокращатель ссылок - итоговый проект, собранный из уроков:
+//   base62.go    - кодирование ID и обратимое перемешивание (examples/bits)
+//   store.go     - SQLite, транзакции, UPSERT (examples/database, examples/jobs)
+//   cache.go     - LRU-кэш редиректов (examples/cache)
+//   analytics.go - фоновый воркер с буферизованным каналом (examples/channels)
+//   ratelimit.go - token bucket и middleware (examples/http-server)
+//   main.go      - конфигурация, graceful shutdown (examples/signals)
+//
+// go get github.com/mattn/go-sqlite3
+//
+// Запуск: go run . -db links.db
+//   curl -d '{"url":"https://go.dev/doc/effective_go"}' localhost:8080/api/links
+//   curl -i localhost:8080/<code>
+//   curl localhost:8080/api/links/<code>
+//
+// Тесты: go test -race ./...
+
+// Config параметры сервиса
+type Config struct {
+	Addr          string
+	DBPath        string
+	BaseURL       string
+	CacheSize     int
+	FlushInterval time.Duration
+	Rate          float64
+	Burst         int
+}
+
+// openDB открывает SQLite: WAL, чтобы чтения редиректов не ждали
+// записи статистики, и busy_timeout вместо мгновенной SQLITE_BUSY
+func openDB(path string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// run запускает сервис и блокируется до отмены ctx
+func run(ctx context.Context, cfg Config) error {
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+	
+	store := NewStore(db)
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	
+	analytics := NewAnalytics(store, 4096, cfg.FlushInterval)
+	analyticsCtx, stopAnalytics := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		analytics.Run(analyticsCtx)
+	}()
+	
+	srv := NewServer(store, newURLCache(cfg.CacheSize), analytics, NewRateLimiter(cfg.Rate, cfg.Burst), cfg.BaseURL)
+	httpServer := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           srv.Routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Сервер запущен на %s", cfg.Addr)
+		errCh <- httpServer.ListenAndServe()
+	}()
+	
+	select {
+	case err := <-errCh:
+		stopAnalytics()
+		wg.Wait()
+		return err
+	case <-ctx.Done():
+	}
+	
+	// Порядок важен: сначала перестаем принимать запросы и дожидаемся
+	// текущих, затем останавливаем аналитику - она сбросит последние
+	// переходы, пока БД еще открыта
+	log.Println("Остановка...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки сервера: %v", err)
+	}
+	stopAnalytics()
+	wg.Wait()
+	
+	flushed, dropped := analytics.Stats()
+	log.Printf("Остановлен; переходов записано: %d, отброшено: %d", flushed, dropped)
+	return nil
+}
+
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.Addr, "addr", ":8080", "адрес HTTP-сервера")
+	flag.StringVar(&cfg.DBPath, "db", "urlshortener.db", "путь к файлу SQLite")
+	flag.StringVar(&cfg.BaseURL, "base-url", "http://localhost:8080", "внешний адрес для коротких ссылок")
+	flag.IntVar(&cfg.CacheSize, "cache", 10000, "размер LRU-кэша редиректов")
+	flag.DurationVar(&cfg.FlushInterval, "flush", 2*time.Second, "интервал записи статистики")
+	flag.Float64Var(&cfg.Rate, "rate", 1, "создание ссылок: запросов в секунду с одного IP")
+	flag.IntVar(&cfg.Burst, "burst", 10, "создание ссылок: допустимый всплеск")
+	flag.Parse()
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
\ No newline at end of file
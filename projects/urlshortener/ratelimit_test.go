@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(2, 3) // 2 в секунду, всплеск 3
+	l.now = func() time.Time { return now }
+	
+	for i := range 3 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("Request %d within burst was rejected", i+1)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok {
+		t.Fatal("Expected rejection after burst")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("Expected wait 500ms, got %v", wait)
+	}
+	
+	// Другой клиент не затронут
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("Expected independent bucket for another key")
+	}
+	
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("Expected a token after 500ms")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("Expected rejection: only one token refilled")
+	}
+	
+	// Долгий простой не копит больше burst
+	now = now.Add(time.Hour)
+	allowed := 0
+	for range 10 {
+		if ok, _ := l.Allow("a"); ok {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Expected 3 requests after idle, got %d", allowed)
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10, 10)
+	l.now = func() time.Time { return now }
+	
+	for i := range 999 {
+		l.Allow(string(rune('a' + i%26)))
+	}
+	now = now.Add(time.Minute)
+	l.Allow("fresh") // 1000-й вызов запускает очистку
+	if len(l.buckets) != 1 {
+		t.Errorf("Expected idle buckets to be swept, %d left", len(l.buckets))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxURLLength ограничение длины исходного адреса; браузеры и прокси
+// все равно плохо переносят URL длиннее нескольких килобайт
+const maxURLLength = 2048
+
+// Server HTTP-слой сервиса
+type Server struct {
+	store     *Store
+	cache     *urlCache
+	analytics *Analytics
+	limiter   *RateLimiter
+	baseURL   string
+}
+
+// NewServer собирает сервер из зависимостей
+func NewServer(store *Store, cache *urlCache, analytics *Analytics, limiter *RateLimiter, baseURL string) *Server {
+	return &Server{
+		store:     store,
+		cache:     cache,
+		analytics: analytics,
+		limiter:   limiter,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Routes возвращает маршруты:
+//
+//	POST /api/links          {"url": "..."} -> 201 {"code", "short_url", ...}
+//	GET  /api/links/{code}   ссылка со статистикой по дням
+//	GET  /{code}             302 на исходный адрес
+//	GET  /healthz            проверка живости
+//
+// Лимит запросов - только на создание: редиректы дешевые и кэшируются,
+// а создание пишет в БД и привлекает спамеров.
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /api/links", s.limiter.Middleware(http.HandlerFunc(s.handleCreate)))
+	mux.HandleFunc("GET /api/links/{code}", s.handleStats)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /{code}", s.handleRedirect)
+	return mux
+}
+
+type createRequest struct {
+	URL string `json:"url"`
+}
+
+type createResponse struct {
+	Link
+	ShortURL string `json:"short_url"`
+}
+
+type statsResponse struct {
+	Link
+	ShortURL string      `json:"short_url"`
+	Daily    []DailyHits `json:"daily"`
+}
+
+// validateURL принимает только абсолютные http(s) адреса: иначе
+// сокращатель превращается в редирект на javascript: или file:
+func validateURL(raw string) error {
+	if raw == "" {
+		return errors.New("url is required")
+	}
+	if len(raw) > maxURLLength {
+		return errors.New("url is too long")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return errors.New("url is malformed")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("url scheme must be http or https")
+	}
+	if u.Host == "" {
+		return errors.New("url host is required")
+	}
+	return nil
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxURLLength+1024)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validateURL(req.URL); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	link, err := s.store.Create(r.Context(), req.URL)
+	if err != nil {
+		log.Printf("Ошибка создания ссылки: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	s.cache.Add(link.Code, link.URL)
+	
+	w.Header().Set("Location", "/api/links/"+link.Code)
+	writeJSON(w, http.StatusCreated, createResponse{link, s.baseURL + "/" + link.Code})
+}
+
+func (s *Server) handleRedirect(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	id, err := idFor(code)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	
+	target, ok := s.cache.Get(code)
+	if !ok {
+		target, err = s.store.URL(r.Context(), code)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка чтения ссылки %s: %v", code, err)
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		s.cache.Add(code, target)
+	}
+	
+	s.analytics.Record(id)
+	// 302, а не 301: постоянный редирект браузер кэширует и больше
+	// не приходит к нам - переходы перестали бы считаться
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	link, err := s.store.Get(r.Context(), code)
+	if errors.Is(err, ErrNotFound) {
+		writeError(w, http.StatusNotFound, "link not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка чтения статистики %s: %v", code, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	daily, err := s.store.Daily(r.Context(), code)
+	if err != nil {
+		log.Printf("Ошибка чтения статистики %s: %v", code, err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusOK, statsResponse{link, s.baseURL + "/" + code, daily})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.db.PingContext(r.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, "database unavailable")
+		return
+	}
+	hits, misses := s.cache.Stats()
+	flushed, dropped := s.analytics.Stats()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":       "ok",
+		"cache_hits":   hits,
+		"cache_misses": misses,
+		"hits_flushed": flushed,
+		"hits_dropped": dropped,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
\ No newline at end of file
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEnv struct {
+	server    *httptest.Server
+	store     *Store
+	analytics *Analytics
+	stop      func() // Останавливает аналитику с финальным сбросом
+}
+
+func newTestEnv(t *testing.T, rate float64, burst int) *testEnv {
+	t.Helper()
+	store := newTestStore(t)
+	analytics := NewAnalytics(store, 1000, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		analytics.Run(ctx)
+	}()
+	
+	srv := NewServer(store, newURLCache(100), analytics, NewRateLimiter(rate, burst), "https://sho.rt/")
+	ts := httptest.NewServer(srv.Routes())
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+	t.Cleanup(func() {
+		ts.Close()
+		stop()
+	})
+	return &testEnv{ts, store, analytics, stop}
+}
+
+// noRedirect клиент, который не следует редиректам
+var noRedirect = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+func (e *testEnv) create(t *testing.T, body string) (*http.Response, createResponse) {
+	t.Helper()
+	resp, err := http.Post(e.server.URL+"/api/links", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	var created createResponse
+	json.NewDecoder(resp.Body).Decode(&created)
+	return resp, created
+}
+
+func TestCreateAndRedirect(t *testing.T) {
+	env := newTestEnv(t, 100, 100)
+	
+	resp, link := env.create(t, `{"url":"https://go.dev/doc?x=1"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	if link.ShortURL != "https://sho.rt/"+link.Code {
+		t.Errorf("Unexpected short URL %q", link.ShortURL)
+	}
+	
+	for range 3 {
+		resp, err := noRedirect.Get(env.server.URL + "/" + link.Code)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusFound {
+			t.Fatalf("Expected 302, got %d", resp.StatusCode)
+		}
+		if loc := resp.Header.Get("Location"); loc != "https://go.dev/doc?x=1" {
+			t.Errorf("Unexpected Location %q", loc)
+		}
+	}
+	
+	// Статистика видна после сброса аналитики
+	env.stop()
+	resp, err := http.Get(env.server.URL + "/api/links/" + link.Code)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	var stats statsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Hits != 3 || len(stats.Daily) != 1 || stats.Daily[0].Hits != 3 {
+		t.Errorf("Expected 3 hits in one day, got %+v", stats)
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	env := newTestEnv(t, 100, 100)
+	
+	tests := []struct {
+		name, body string
+	}{
+		{"invalid json", `{"url":`},
+		{"empty", `{"url":""}`},
+		{"relative", `{"url":"/path"}`},
+		{"javascript", `{"url":"javascript:alert(1)"}`},
+		{"ftp", `{"url":"ftp://example.com/file"}`},
+		{"no host", `{"url":"https://"}`},
+		{"too long", `{"url":"https://example.com/` + strings.Repeat("a", maxURLLength) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := env.create(t, tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRedirectNotFound(t *testing.T) {
+	env := newTestEnv(t, 100, 100)
+	
+	for _, path := range []string{"/" + codeFor(999), "/not-a-code", "/api/links/" + codeFor(999)} {
+		resp, err := noRedirect.Get(env.server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestCreateRateLimited(t *testing.T) {
+	env := newTestEnv(t, 0.001, 2)
+	
+	var statuses []int
+	for range 3 {
+		resp, _ := env.create(t, `{"url":"https://go.dev"}`)
+		statuses = append(statuses, resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Error("Expected Retry-After header")
+		}
+	}
+	if statuses[0] != 201 || statuses[1] != 201 || statuses[2] != 429 {
+		t.Errorf("Expected [201 201 429], got %v", statuses)
+	}
+	
+	// Редиректы не ограничены
+	resp, err := http.Get(env.server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected healthz 200, got %d", resp.StatusCode)
+	}
+}
\ No newline at end of file
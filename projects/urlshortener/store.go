@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotFound ссылка не найдена
+var ErrNotFound = errors.New("link not found")
+
+// Link сокращенная ссылка
+type Link struct {
+	ID        uint64    `json:"-"`
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	Hits      int64     `json:"hits"`
+	LastHitAt time.Time `json:"last_hit_at,omitzero"`
+}
+
+// Store хранилище ссылок в SQLite. Код в БД не хранится: он однозначно
+// вычисляется из ID (см. codeFor), а поиск идет по первичному ключу.
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewStore создает хранилище
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db, now: time.Now}
+}
+
+// Migrate создает таблицы. Счетчик переходов - в отдельной таблице
+// по дням: так видна динамика, а UPDATE по дню не конфликтует
+// с чтением самой ссылки.
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS links (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS link_hits (
+		link_id INTEGER NOT NULL REFERENCES links(id),
+		day TEXT NOT NULL,
+		hits INTEGER NOT NULL,
+		last_hit_at INTEGER NOT NULL, -- Unix-миллисекунды: MAX() по TIMESTAMP драйвер вернул бы строкой
+		PRIMARY KEY (link_id, day)
+	);`)
+	return err
+}
+
+func codeFor(id uint64) string {
+	return EncodeBase62(obfuscateID(id))
+}
+
+func idFor(code string) (uint64, error) {
+	x, err := DecodeBase62(code)
+	if err != nil || x > idMask {
+		return 0, ErrInvalidCode
+	}
+	return revealID(x), nil
+}
+
+// Create сохраняет ссылку и возвращает ее с кодом
+func (s *Store) Create(ctx context.Context, url string) (Link, error) {
+	link := Link{URL: url, CreatedAt: s.now().UTC().Truncate(time.Second)}
+	res, err := s.db.ExecContext(ctx, `INSERT INTO links (url, created_at) VALUES (?, ?)`, link.URL, link.CreatedAt)
+	if err != nil {
+		return Link{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Link{}, err
+	}
+	link.ID = uint64(id)
+	link.Code = codeFor(link.ID)
+	return link, nil
+}
+
+// URL возвращает адрес для редиректа - горячий путь, только первичный ключ
+func (s *Store) URL(ctx context.Context, code string) (string, error) {
+	id, err := idFor(code)
+	if err != nil {
+		return "", ErrNotFound
+	}
+	var url string
+	err = s.db.QueryRowContext(ctx, `SELECT url FROM links WHERE id = ?`, id).Scan(&url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return url, err
+}
+
+// Get возвращает ссылку со статистикой
+func (s *Store) Get(ctx context.Context, code string) (Link, error) {
+	id, err := idFor(code)
+	if err != nil {
+		return Link{}, ErrNotFound
+	}
+	link := Link{ID: id, Code: code}
+	var lastHit sql.NullInt64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT l.url, l.created_at, COALESCE(SUM(h.hits), 0), MAX(h.last_hit_at)
+		FROM links l LEFT JOIN link_hits h ON h.link_id = l.id
+		WHERE l.id = ?
+		GROUP BY l.id`, id).Scan(&link.URL, &link.CreatedAt, &link.Hits, &lastHit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Link{}, ErrNotFound
+	}
+	if err != nil {
+		return Link{}, err
+	}
+	if lastHit.Valid {
+		link.LastHitAt = time.UnixMilli(lastHit.Int64).UTC()
+	}
+	return link, nil
+}
+
+// DailyHits переходы по дням, от старых к новым
+type DailyHits struct {
+	Day  string `json:"day"`
+	Hits int64  `json:"hits"`
+}
+
+// Daily возвращает статистику ссылки по дням
+func (s *Store) Daily(ctx context.Context, code string) ([]DailyHits, error) {
+	id, err := idFor(code)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT day, hits FROM link_hits WHERE link_id = ? ORDER BY day`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	result := []DailyHits{}
+	for rows.Next() {
+		var d DailyHits
+		if err := rows.Scan(&d.Day, &d.Hits); err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+// AddHits записывает накопленные переходы одной транзакцией: сотни
+// переходов превращаются в несколько UPSERT вместо сотен UPDATE
+func (s *Store) AddHits(ctx context.Context, hits map[HitKey]HitBatch) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // После Commit ничего не делает
+	
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO link_hits (link_id, day, hits, last_hit_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (link_id, day) DO UPDATE SET
+			hits = hits + excluded.hits,
+			last_hit_at = MAX(last_hit_at, excluded.last_hit_at)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	
+	for key, b := range hits {
+		if _, err := stmt.ExecContext(ctx, key.LinkID, key.Day, b.Count, b.Last.UnixMilli()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
\ No newline at end of file
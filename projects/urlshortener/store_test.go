@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestStore создает хранилище в отдельном файле для каждого теста
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := openDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	
+	store := NewStore(db)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return store
+}
+
+func TestStoreCreateAndGet(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	
+	link, err := store.Create(ctx, "https://go.dev")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if link.Code == "" || link.Code != codeFor(link.ID) {
+		t.Errorf("Unexpected code %q for ID %d", link.Code, link.ID)
+	}
+	
+	url, err := store.URL(ctx, link.Code)
+	if err != nil || url != "https://go.dev" {
+		t.Errorf("Expected https://go.dev, got %q, %v", url, err)
+	}
+	
+	got, err := store.Get(ctx, link.Code)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.URL != link.URL || !got.CreatedAt.Equal(link.CreatedAt) || got.Hits != 0 || !got.LastHitAt.IsZero() {
+		t.Errorf("Expected %+v, got %+v", link, got)
+	}
+}
+
+func TestStoreNotFound(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	
+	for _, code := range []string{codeFor(42), "!!!", "ZZZZZZZZZZ"} {
+		if _, err := store.URL(ctx, code); !errors.Is(err, ErrNotFound) {
+			t.Errorf("URL(%q): expected ErrNotFound, got %v", code, err)
+		}
+		if _, err := store.Get(ctx, code); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q): expected ErrNotFound, got %v", code, err)
+		}
+	}
+}
+
+func TestStoreAddHitsAccumulates(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	link, _ := store.Create(ctx, "https://go.dev")
+	
+	day1 := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+	batches := []map[HitKey]HitBatch{
+		{{link.ID, "2026-03-01"}: {3, day1.Add(-time.Hour)}},
+		{{link.ID, "2026-03-01"}: {2, day1}, {link.ID, "2026-03-02"}: {5, day2}},
+	}
+	for _, b := range batches {
+		if err := store.AddHits(ctx, b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	
+	got, err := store.Get(ctx, link.Code)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Hits != 10 || !got.LastHitAt.Equal(day2) {
+		t.Errorf("Expected 10 hits, last at %v; got %d at %v", day2, got.Hits, got.LastHitAt)
+	}
+	
+	daily, err := store.Daily(ctx, link.Code)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []DailyHits{{"2026-03-01", 5}, {"2026-03-02", 5}}
+	if len(daily) != len(want) || daily[0] != want[0] || daily[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, daily)
+	}
+}
+
+func TestStoreAddHitsRollsBack(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	link, _ := store.Create(ctx, "https://go.dev")
+	
+	// Вторая запись нарушает внешний ключ - транзакция откатывается целиком
+	err := store.AddHits(ctx, map[HitKey]HitBatch{
+		{link.ID, "2026-03-01"}:       {1, time.Now()},
+		{link.ID + 100, "2026-03-01"}: {1, time.Now()},
+	})
+	if err == nil {
+		t.Fatal("Expected foreign key error")
+	}
+	var hits sql.NullInt64
+	store.db.QueryRow(`SELECT SUM(hits) FROM link_hits`).Scan(&hits)
+	if hits.Valid {
+		t.Errorf("Expected no hits after rollback, got %d", hits.Int64)
+	}
+}
\ No newline at end of file